@@ -8,6 +8,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/stashapp/stash/internal/api/rest"
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
@@ -29,6 +30,10 @@ func allowUnauthenticated(r *http.Request) bool {
 	return strings.HasPrefix(r.URL.Path, loginEndpoint) || r.URL.Path == logoutEndpoint || r.URL.Path == "/css" || strings.HasPrefix(r.URL.Path, "/assets")
 }
 
+func isRESTRequest(r *http.Request) bool {
+	return r.URL.Path == rest.Endpoint || strings.HasPrefix(r.URL.Path, rest.Endpoint+"/")
+}
+
 func authenticateHandler() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -76,9 +81,9 @@ func authenticateHandler() func(http.Handler) http.Handler {
 			if c.HasCredentials() {
 				// authentication is required
 				if userID == "" && !allowUnauthenticated(r) {
-					// if graphql or a non-webpage was requested, we just return a forbidden error
+					// if graphql, the REST API or a non-webpage was requested, we just return a forbidden error
 					ext := path.Ext(r.URL.Path)
-					if r.URL.Path == gqlEndpoint || (ext != "" && ext != ".html") {
+					if r.URL.Path == gqlEndpoint || isRESTRequest(r) || (ext != "" && ext != ".html") {
 						w.Header().Add("WWW-Authenticate", "FormBased")
 						w.WriteHeader(http.StatusUnauthorized)
 						return
@@ -103,6 +108,8 @@ func authenticateHandler() func(http.Handler) http.Handler {
 			}
 
 			ctx = session.SetCurrentUserID(ctx, userID)
+			ctx = session.SetRequestIsLocal(ctx, session.IsLocalRequest(r))
+			ctx = session.SetRequestUsesAPIKey(ctx, userID != "" && session.UsesAPIKey(r))
 
 			r = r.WithContext(ctx)
 
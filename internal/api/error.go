@@ -6,7 +6,9 @@ import (
 	"errors"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/txn"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
@@ -38,5 +40,12 @@ func gqlErrorHandler(ctx context.Context, e error) *gqlerror.Error {
 
 	// we may also want to transform the error message for the response
 	// for now just return the original error
-	return graphql.DefaultErrorPresenter(ctx, e)
+	ret := graphql.DefaultErrorPresenter(ctx, e)
+
+	// allow clients to distinguish timeouts from other errors
+	if errors.Is(e, txn.ErrTimeout) {
+		errcode.Set(ret, errQueryTimeout)
+	}
+
+	return ret
 }
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	errDepthLimit   = "DEPTH_LIMIT_EXCEEDED"
+	errQueryTimeout = "QUERY_TIMEOUT"
+
+	// unboundedListSize is the number of results assumed for queries that
+	// return all results. This is deliberately modest so that scripts using
+	// per_page -1 are not rejected outright; the query timeout still limits
+	// the cost of these queries.
+	unboundedListSize = 1000
+)
+
+// listComplexity returns the complexity of a query returning a list of
+// results, where childComplexity is the complexity of a single result.
+// The number of results is estimated from ids if it is not empty, otherwise
+// from the page size of filter.
+func listComplexity(childComplexity int, filter *models.FindFilterType, ids int) int {
+	n := ids
+	if n == 0 {
+		var ff models.FindFilterType
+		if filter != nil {
+			ff = *filter
+		}
+
+		n = ff.GetPageSize()
+		if ff.IsGetAll() {
+			n = unboundedListSize
+		}
+	}
+
+	// the count and other aggregate fields are still returned for empty
+	// pages
+	return childComplexity * max(n, 1)
+}
+
+func allComplexity(childComplexity int) int {
+	return childComplexity * unboundedListSize
+}
+
+// setComplexity sets the functions used to estimate the complexity of list
+// queries, so that the complexity scales with the requested page size.
+// Other fields use the default complexity of one per field.
+func setComplexity(c *ComplexityRoot) {
+	q := &c.Query
+
+	q.FindScenes = func(childComplexity int, _ *models.SceneFilterType, sceneIds []int, ids []string, filter *models.FindFilterType) int {
+		return listComplexity(childComplexity, filter, len(sceneIds)+len(ids))
+	}
+	q.FindScenesByPathRegex = func(childComplexity int, filter *models.FindFilterType) int {
+		return listComplexity(childComplexity, filter, 0)
+	}
+	q.FindSceneMarkers = func(childComplexity int, _ *models.SceneMarkerFilterType, filter *models.FindFilterType) int {
+		return listComplexity(childComplexity, filter, 0)
+	}
+	q.FindImages = func(childComplexity int, _ *models.ImageFilterType, imageIds []int, ids []string, filter *models.FindFilterType) int {
+		return listComplexity(childComplexity, filter, len(imageIds)+len(ids))
+	}
+	q.FindPerformers = func(childComplexity int, _ *models.PerformerFilterType, filter *models.FindFilterType, performerIds []int, ids []string) int {
+		return listComplexity(childComplexity, filter, len(performerIds)+len(ids))
+	}
+	q.FindStudios = func(childComplexity int, _ *models.StudioFilterType, filter *models.FindFilterType, ids []string) int {
+		return listComplexity(childComplexity, filter, len(ids))
+	}
+	q.FindTags = func(childComplexity int, _ *models.TagFilterType, filter *models.FindFilterType, ids []string) int {
+		return listComplexity(childComplexity, filter, len(ids))
+	}
+	q.FindGalleries = func(childComplexity int, _ *models.GalleryFilterType, filter *models.FindFilterType, ids []string) int {
+		return listComplexity(childComplexity, filter, len(ids))
+	}
+	q.FindGroups = func(childComplexity int, _ *models.GroupFilterType, filter *models.FindFilterType, ids []string) int {
+		return listComplexity(childComplexity, filter, len(ids))
+	}
+	q.FindMovies = func(childComplexity int, _ *models.GroupFilterType, filter *models.FindFilterType, ids []string) int {
+		return listComplexity(childComplexity, filter, len(ids))
+	}
+	q.FindFiles = func(childComplexity int, _ *models.FileFilterType, filter *models.FindFilterType) int {
+		return listComplexity(childComplexity, filter, 0)
+	}
+	q.FindTrash = func(childComplexity int, _ *models.TrashObjectType, filter *models.FindFilterType) int {
+		return listComplexity(childComplexity, filter, 0)
+	}
+
+	q.AllScenes = allComplexity
+	q.AllSceneMarkers = allComplexity
+	q.AllImages = allComplexity
+	q.AllPerformers = allComplexity
+	q.AllStudios = allComplexity
+	q.AllTags = allComplexity
+	q.AllGalleries = allComplexity
+	q.AllMovies = allComplexity
+}
+
+// complexityLimit returns the configured complexity limit.
+func complexityLimit(ctx context.Context, rc *graphql.OperationContext) int {
+	limit := config.GetInstance().GetGraphQLMaxComplexity()
+	if limit <= 0 {
+		return math.MaxInt
+	}
+	return limit
+}
+
+// depthLimit returns the configured depth limit.
+func depthLimit(ctx context.Context) int {
+	return config.GetInstance().GetGraphQLMaxDepth()
+}
+
+// DepthLimit is a handler extension that rejects operations with selections
+// or argument values nested deeper than the limit returned by Func.
+// A limit of zero or less disables the check.
+type DepthLimit struct {
+	Func func(ctx context.Context) int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = DepthLimit{}
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	limit := d.Func(ctx)
+	if limit <= 0 {
+		return nil
+	}
+
+	c := depthCalculator{variables: rc.Variables}
+	c.selectionSet(rc.Operation.SelectionSet, 0)
+
+	var err *gqlerror.Error
+	switch {
+	case c.selectionDepth > limit:
+		err = gqlerror.Errorf("operation has a selection depth of %d, which exceeds the limit of %d", c.selectionDepth, limit)
+	case c.argumentDepth > limit:
+		err = gqlerror.Errorf("argument %s has a depth of %d, which exceeds the limit of %d", c.argument, c.argumentDepth, limit)
+	default:
+		return nil
+	}
+
+	errcode.Set(err, errDepthLimit)
+	return err
+}
+
+// depthCalculator finds the maximum depth of the selections and argument
+// values of an operation. Fragments do not add to the depth. Variables are
+// resolved so that variable inputs are measured in the same way as
+// literals.
+type depthCalculator struct {
+	variables map[string]interface{}
+
+	selectionDepth int
+	argumentDepth  int
+	// argument is the name of the deepest argument
+	argument string
+}
+
+func (c *depthCalculator) selectionSet(set ast.SelectionSet, depth int) {
+	for _, s := range set {
+		switch s := s.(type) {
+		case *ast.Field:
+			// introspection queries are deeply nested by design
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+
+			c.selectionDepth = max(c.selectionDepth, depth+1)
+
+			for _, arg := range s.Arguments {
+				if d := c.valueDepth(arg.Value); d > c.argumentDepth {
+					c.argumentDepth = d
+					c.argument = s.Name + "." + arg.Name
+				}
+			}
+
+			c.selectionSet(s.SelectionSet, depth+1)
+		case *ast.InlineFragment:
+			c.selectionSet(s.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				c.selectionSet(s.Definition.SelectionSet, depth)
+			}
+		}
+	}
+}
+
+func (c *depthCalculator) valueDepth(v *ast.Value) int {
+	if v == nil {
+		return 0
+	}
+
+	switch v.Kind {
+	case ast.Variable:
+		return variableDepth(c.variables[v.Raw])
+	case ast.ListValue, ast.ObjectValue:
+		ret := 0
+		for _, child := range v.Children {
+			ret = max(ret, c.valueDepth(child.Value))
+		}
+		return ret + 1
+	default:
+		return 0
+	}
+}
+
+func variableDepth(v interface{}) int {
+	ret := 0
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			ret = max(ret, variableDepth(child))
+		}
+	case []interface{}:
+		for _, child := range v {
+			ret = max(ret, variableDepth(child))
+		}
+	default:
+		return 0
+	}
+
+	return ret + 1
+}
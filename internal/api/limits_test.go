@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const limitsTestSchema = `
+input Filter {
+  name: String
+  AND: Filter
+  ids: [ID!]
+}
+
+type Item {
+  name: String
+  children: [Item!]!
+}
+
+type Query {
+  find(filter: Filter): [Item!]!
+}
+`
+
+func TestDepthLimit(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: limitsTestSchema})
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		wantErr   bool
+	}{
+		{
+			"shallow",
+			`{ find { name children { name } } }`,
+			nil,
+			false,
+		},
+		{
+			"deep selection",
+			`{ find { children { children { children { name } } } } }`,
+			nil,
+			true,
+		},
+		{
+			"fragments are not counted",
+			`{ find { ...F } } fragment F on Item { children { name } }`,
+			nil,
+			false,
+		},
+		{
+			"introspection is ignored",
+			`{ __schema { types { fields { type { ofType { name } } } } } }`,
+			nil,
+			false,
+		},
+		{
+			"deep argument",
+			`{ find(filter: { AND: { AND: { AND: { name: "x" } } } }) { name } }`,
+			nil,
+			true,
+		},
+		{
+			"deep variable",
+			`query ($f: Filter) { find(filter: $f) { name } }`,
+			map[string]interface{}{
+				"f": map[string]interface{}{
+					"AND": map[string]interface{}{
+						"AND": map[string]interface{}{
+							"ids": []interface{}{"1"},
+						},
+					},
+				},
+			},
+			true,
+		},
+		{
+			"shallow variable",
+			`query ($f: Filter) { find(filter: $f) { name } }`,
+			map[string]interface{}{
+				"f": map[string]interface{}{
+					"ids": []interface{}{"1"},
+				},
+			},
+			false,
+		},
+	}
+
+	limit := DepthLimit{Func: func(ctx context.Context) int { return 3 }}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := gqlparser.MustLoadQuery(schema, tt.query)
+			rc := &graphql.OperationContext{
+				Doc:       doc,
+				Operation: doc.Operations[0],
+				Variables: tt.variables,
+			}
+
+			err := limit.MutateOperationContext(context.Background(), rc)
+			if !tt.wantErr {
+				assert.Nil(t, err)
+				return
+			}
+
+			if assert.NotNil(t, err) {
+				assert.Equal(t, errDepthLimit, err.Extensions["code"])
+			}
+		})
+	}
+}
+
+func TestListComplexity(t *testing.T) {
+	perPage := 100
+	all := -1
+
+	tests := []struct {
+		name   string
+		filter *models.FindFilterType
+		ids    int
+		want   int
+	}{
+		{"default page size", nil, 0, 2 * 25},
+		{"page size", &models.FindFilterType{PerPage: &perPage}, 0, 2 * 100},
+		{"all results", &models.FindFilterType{PerPage: &all}, 0, 2 * unboundedListSize},
+		{"ids", &models.FindFilterType{PerPage: &perPage}, 3, 2 * 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, listComplexity(2, tt.filter, tt.ids))
+		})
+	}
+}
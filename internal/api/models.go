@@ -27,6 +27,19 @@ func convertVisualFile(f models.File) (VisualFile, error) {
 	}
 }
 
+func convertBaseFile(f models.File) BaseFile {
+	switch f := f.(type) {
+	case BaseFile:
+		return f
+	case *models.VideoFile:
+		return &VideoFile{VideoFile: f}
+	case *models.ImageFile:
+		return &ImageFile{ImageFile: f}
+	default:
+		return &GalleryFile{BaseFile: f.Base()}
+	}
+}
+
 type GalleryFile struct {
 	*models.BaseFile
 }
@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/stashapp/stash/internal/build"
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin/hook"
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/txn"
 )
 
 var (
@@ -347,16 +350,27 @@ func (r *mutationResolver) ExecSQL(ctx context.Context, sql string, args []inter
 	}, nil
 }
 
-func (r *mutationResolver) QuerySQL(ctx context.Context, sql string, args []interface{}) (*SQLQueryResult, error) {
+func (r *mutationResolver) QuerySQL(ctx context.Context, sql string, args []interface{}, readOnly *bool) (*SQLQueryResult, error) {
 	var cols []string
 	var rows [][]interface{}
 
 	db := manager.GetInstance().Database
-	if err := r.withTxn(ctx, func(ctx context.Context) error {
+	fn := func(ctx context.Context) error {
 		var err error
 		cols, rows, err = db.QuerySQL(ctx, sql, args)
 		return err
-	}); err != nil {
+	}
+
+	// the statement is always time-limited, even if the request is not
+	timeout := time.Duration(config.GetInstance().GetQueryTimeout()) * time.Second
+
+	var err error
+	if readOnly == nil || *readOnly {
+		err = r.withReadTxn(txn.WithReadTimeout(ctx, timeout), fn)
+	} else {
+		err = txn.WithTimeout(ctx, r.repository.TxnManager, timeout, fn)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -1,6 +1,10 @@
 package api
 
-import "context"
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
 
 func (r *galleryFileResolver) Fingerprint(ctx context.Context, obj *GalleryFile, type_ string) (*string, error) {
 	fp := obj.BaseFile.Fingerprints.For(type_)
@@ -28,3 +32,26 @@ func (r *videoFileResolver) Fingerprint(ctx context.Context, obj *VideoFile, typ
 	}
 	return nil, nil
 }
+
+func (r *galleryFileResolver) IntegrityIssue(ctx context.Context, obj *GalleryFile) (*models.FileIntegrityIssue, error) {
+	return r.getIntegrityIssue(ctx, obj.BaseFile.ID)
+}
+
+func (r *imageFileResolver) IntegrityIssue(ctx context.Context, obj *ImageFile) (*models.FileIntegrityIssue, error) {
+	return r.getIntegrityIssue(ctx, obj.ImageFile.ID)
+}
+
+func (r *videoFileResolver) IntegrityIssue(ctx context.Context, obj *VideoFile) (*models.FileIntegrityIssue, error) {
+	return r.getIntegrityIssue(ctx, obj.VideoFile.ID)
+}
+
+func (r *Resolver) getIntegrityIssue(ctx context.Context, id models.FileID) (ret *models.FileIntegrityIssue, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.File.GetIntegrityIssue(ctx, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -230,3 +230,14 @@ func (r *galleryResolver) Image(ctx context.Context, obj *models.Gallery, index
 
 	return
 }
+
+func (r *galleryResolver) CustomFields(ctx context.Context, obj *models.Gallery) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Gallery.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
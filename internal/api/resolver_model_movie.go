@@ -206,3 +206,14 @@ func (r *groupResolver) Scenes(ctx context.Context, obj *models.Group) (ret []*m
 
 	return ret, nil
 }
+
+func (r *groupResolver) CustomFields(ctx context.Context, obj *models.Group) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Group.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -142,6 +142,28 @@ func (r *sceneResolver) SceneMarkers(ctx context.Context, obj *models.Scene) (re
 	return ret, nil
 }
 
+func (r *sceneResolver) SuggestedMarkers(ctx context.Context, obj *models.Scene) ([]*models.SuggestedMarker, error) {
+	if _, err := r.getPrimaryFile(ctx, obj); err != nil {
+		return nil, err
+	}
+
+	markers, err := r.SceneMarkers(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := manager.GetSceneSuggestedMarkers(obj, markers, manager.GetInstance().Config.GetVideoFileNamingAlgorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		ret = []*models.SuggestedMarker{}
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) Captions(ctx context.Context, obj *models.Scene) (ret []*models.VideoCaption, err error) {
 	primaryFile, err := r.getPrimaryFile(ctx, obj)
 	if err != nil {
@@ -310,7 +332,7 @@ func (r *sceneResolver) SceneStreams(ctx context.Context, obj *models.Scene) ([]
 	builder := urlbuilders.NewSceneURLBuilder(baseURL, obj)
 	apiKey := config.GetAPIKey()
 
-	return manager.GetSceneStreamPaths(obj, builder.GetStreamURL(apiKey), config.GetMaxStreamingTranscodeSize())
+	return manager.GetSceneStreamPaths(obj, builder.GetStreamURL(apiKey), config.GetMaxStreamingTranscodeSize(), manager.GetStreamingMaxBitrate(ctx))
 }
 
 func (r *sceneResolver) Interactive(ctx context.Context, obj *models.Scene) (bool, error) {
@@ -424,3 +446,14 @@ func (r *sceneResolver) OHistory(ctx context.Context, obj *models.Scene) ([]*tim
 
 	return ptrRet, nil
 }
+
+func (r *sceneResolver) CustomFields(ctx context.Context, obj *models.Scene) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -178,3 +178,14 @@ func (r *studioResolver) Groups(ctx context.Context, obj *models.Studio) (ret []
 func (r *studioResolver) Movies(ctx context.Context, obj *models.Studio) (ret []*models.Group, err error) {
 	return r.Groups(ctx, obj)
 }
+
+func (r *studioResolver) CustomFields(ctx context.Context, obj *models.Studio) (ret map[string]interface{}, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Studio.GetCustomFields(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -15,7 +15,9 @@ import (
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
 	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/webhook"
 )
 
 var ErrOverriddenConfig = errors.New("cannot set overridden value")
@@ -84,9 +86,13 @@ func (r *mutationResolver) setConfigFloat(key string, value *float64) {
 func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGeneralInput) (*ConfigGeneralResult, error) {
 	c := config.GetInstance()
 
+	refreshStashFS := false
+	refreshWatcher := false
+	refreshWebhooks := false
 	existingPaths := c.GetStashPaths()
 	if input.Stashes != nil {
-		for _, s := range input.Stashes {
+		stashes := config.NewStashConfigs(input.Stashes, existingPaths)
+		for _, s := range stashes {
 			// Only validate existence of new paths
 			isNew := true
 			for _, path := range existingPaths {
@@ -95,14 +101,20 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 					break
 				}
 			}
-			if isNew {
+			if isNew && s.Remote != nil {
+				if err := manager.ValidateRemoteStash(s.Path, s.Remote); err != nil {
+					return makeConfigGeneralResult(), err
+				}
+			} else if isNew {
 				exists, err := fsutil.DirExists(s.Path)
 				if !exists {
 					return makeConfigGeneralResult(), err
 				}
 			}
 		}
-		c.SetInterface(config.Stash, input.Stashes)
+		c.SetInterface(config.Stash, stashes)
+		refreshStashFS = true
+		refreshWatcher = true
 	}
 
 	checkConfigOverride := func(key string) error {
@@ -134,10 +146,10 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		}
 
 		ext := filepath.Ext(*input.DatabasePath)
-		if ext != ".db" && ext != ".sqlite" && ext != ".sqlite3" {
-			return makeConfigGeneralResult(), fmt.Errorf("invalid database path, use extension db, sqlite, or sqlite3")
+		if !sqlite.IsPostgresURL(*input.DatabasePath) && ext != ".db" && ext != ".sqlite" && ext != ".sqlite3" {
+			return makeConfigGeneralResult(), fmt.Errorf("invalid database path, use extension db, sqlite, or sqlite3, or a postgres:// URL")
 		}
-		c.SetString(config.Database, *input.DatabasePath)
+		c.SetDatabasePath(*input.DatabasePath)
 	}
 
 	existingBackupDirectoryPath := c.GetBackupDirectoryPath()
@@ -149,6 +161,13 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.SetString(config.BackupDirectoryPath, *input.BackupDirectoryPath)
 	}
 
+	r.setConfigInt(config.BackupInterval, input.BackupInterval)
+	r.setConfigInt(config.BackupKeepCount, input.BackupKeepCount)
+	r.setConfigInt(config.BackupKeepDaily, input.BackupKeepDaily)
+	r.setConfigInt(config.BackupKeepWeekly, input.BackupKeepWeekly)
+	r.setConfigBool(config.BackupCompress, input.BackupCompress)
+	r.setConfigBool(config.BackupIncludeBlobs, input.BackupIncludeBlobs)
+
 	existingGeneratedPath := c.GetGeneratedPath()
 	if input.GeneratedPath != nil && existingGeneratedPath != *input.GeneratedPath {
 		if err := validateDir(config.Generated, *input.GeneratedPath, false); err != nil {
@@ -225,6 +244,17 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		refreshBlobStorage = true
 	}
 
+	if input.TrashPath != nil && c.GetTrashPath() != *input.TrashPath {
+		if err := validateDir(config.TrashPath, *input.TrashPath, true); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+
+		c.SetString(config.TrashPath, *input.TrashPath)
+	}
+
+	r.setConfigInt(config.TrashRetentionDays, input.TrashRetentionDays)
+	r.setConfigInt(config.ChangeLogRetentionDays, input.ChangeLogRetentionDays)
+
 	refreshFfmpeg := false
 	if input.FfmpegPath != nil && *input.FfmpegPath != c.GetFFMpegPath() {
 		if *input.FfmpegPath != "" {
@@ -286,6 +316,20 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if input.MaxStreamingTranscodeSize != nil {
 		c.SetString(config.MaxStreamingTranscodeSize, input.MaxStreamingTranscodeSize.String())
 	}
+
+	if input.StreamingBandwidthPolicies != nil {
+		if err := c.ValidateStreamingBandwidthPolicies(input.StreamingBandwidthPolicies); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.SetInterface(config.StreamingBandwidthPolicies, input.StreamingBandwidthPolicies)
+	}
+
+	if input.OrganiserRules != nil {
+		if err := manager.ValidateOrganiserRules(input.OrganiserRules); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.SetInterface(config.OrganiserRules, input.OrganiserRules)
+	}
 	r.setConfigBool(config.WriteImageThumbnails, input.WriteImageThumbnails)
 	r.setConfigBool(config.CreateImageClipsFromVideos, input.CreateImageClipsFromVideos)
 
@@ -323,6 +367,9 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	}
 
 	r.setConfigInt(config.MaxSessionAge, input.MaxSessionAge)
+	r.setConfigInt(config.GraphQLMaxComplexity, input.GraphqlMaxComplexity)
+	r.setConfigInt(config.GraphQLMaxDepth, input.GraphqlMaxDepth)
+	r.setConfigInt(config.QueryTimeout, input.QueryTimeout)
 	r.setConfigString(config.LogFile, input.LogFile)
 	r.setConfigBool(config.LogOut, input.LogOut)
 	r.setConfigBool(config.LogAccess, input.LogAccess)
@@ -367,6 +414,18 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 
 	r.setConfigBool(config.CreateGalleriesFromFolders, input.CreateGalleriesFromFolders)
 
+	r.setConfigBool(config.ReadNfo, input.ReadNfo)
+	if input.NfoPrecedence != nil {
+		c.SetString(config.NfoPrecedence, input.NfoPrecedence.String())
+	}
+	r.setConfigBool(config.WriteNfo, input.WriteNfo)
+
+	if input.WatchLibrary != nil || input.WatchRescanInterval != nil {
+		r.setConfigBool(config.WatchLibrary, input.WatchLibrary)
+		r.setConfigInt(config.WatchRescanInterval, input.WatchRescanInterval)
+		refreshWatcher = true
+	}
+
 	if input.CustomPerformerImageLocation != nil {
 		c.SetString(config.CustomPerformerImageLocation, *input.CustomPerformerImageLocation)
 		initCustomPerformerImages(*input.CustomPerformerImageLocation)
@@ -379,6 +438,15 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.SetInterface(config.StashBoxes, input.StashBoxes)
 	}
 
+	if input.Webhooks != nil {
+		webhooks := webhook.NewWebhooks(input.Webhooks, c.GetWebhooks())
+		if err := webhook.ValidateWebhooks(webhooks); err != nil {
+			return nil, err
+		}
+		c.SetInterface(config.Webhooks, webhooks)
+		refreshWebhooks = true
+	}
+
 	if input.PythonPath != nil {
 		r.setConfigString(config.PythonPath, input.PythonPath)
 	}
@@ -428,6 +496,13 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if refreshStreamManager {
 		manager.GetInstance().RefreshStreamManager()
 	}
+	if refreshStashFS {
+		manager.GetInstance().RefreshStashFS()
+
+		if _, err := manager.GetInstance().RefreshOfflinePaths(ctx); err != nil {
+			logger.Errorf("error checking stash paths: %v", err)
+		}
+	}
 	if refreshBlobStorage {
 		manager.GetInstance().SetBlobStoreOptions()
 	}
@@ -437,6 +512,12 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 	if refreshPluginSource {
 		manager.GetInstance().RefreshPluginSourceManager()
 	}
+	if refreshWatcher {
+		manager.GetInstance().RefreshWatcher()
+	}
+	if refreshWebhooks {
+		manager.GetInstance().RefreshWebhooks()
+	}
 
 	return makeConfigGeneralResult(), nil
 }
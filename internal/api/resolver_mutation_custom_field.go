@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/customfield"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *mutationResolver) CustomFieldDefinitionCreate(ctx context.Context, input CustomFieldDefinitionCreateInput) (*models.CustomFieldDefinition, error) {
+	newDefinition := models.NewCustomFieldDefinition()
+	newDefinition.EntityType = input.EntityType
+	newDefinition.Name = strings.TrimSpace(input.Name)
+	newDefinition.Type = input.Type
+	newDefinition.Options = input.Options
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.CustomField
+
+		if err := customfield.ValidateCreate(ctx, newDefinition, qb); err != nil {
+			return err
+		}
+
+		return qb.Create(ctx, &newDefinition)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &newDefinition, nil
+}
+
+func (r *mutationResolver) CustomFieldDefinitionUpdate(ctx context.Context, input CustomFieldDefinitionUpdateInput) (ret *models.CustomFieldDefinition, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.CustomField
+
+		ret, err = qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return fmt.Errorf("custom field with id %d not found", id)
+		}
+
+		if input.Name != nil {
+			ret.Name = strings.TrimSpace(*input.Name)
+		}
+		if input.Options != nil {
+			ret.Options = input.Options
+		}
+		ret.UpdatedAt = time.Now()
+
+		if err := customfield.ValidateUpdate(ctx, *ret, qb); err != nil {
+			return err
+		}
+
+		return qb.Update(ctx, ret)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) CustomFieldDefinitionDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.CustomField.Destroy(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -149,7 +149,8 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 		return false, fmt.Errorf("converting ids: %w", err)
 	}
 
-	fileDeleter := file.NewDeleter()
+	trash := manager.GetInstance().NewTrashRecorder()
+	fileDeleter := trash.Deleter
 	destroyer := &file.ZipDestroyer{
 		FileDestroyer:   r.repository.File,
 		FolderDestroyer: r.repository.Folder,
@@ -191,7 +192,9 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 			}
 
 			const deleteFile = true
-			if err := destroyer.DestroyZip(ctx, f[0], fileDeleter, deleteFile); err != nil {
+			if err := trash.File(ctx, f[0], func() error {
+				return destroyer.DestroyZip(ctx, f[0], fileDeleter, deleteFile)
+			}); err != nil {
 				return fmt.Errorf("deleting file %s: %w", path, err)
 			}
 		}
@@ -8,7 +8,6 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
@@ -86,6 +85,14 @@ func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreat
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, newGallery.ID, models.CustomFieldsInput{
+				Full: input.CustomFields,
+			}); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -246,6 +253,12 @@ func (r *mutationResolver) galleryUpdate(ctx context.Context, input models.Galle
 		return nil, err
 	}
 
+	if input.CustomFields != nil {
+		if err := qb.SetCustomFields(ctx, galleryID, *input.CustomFields); err != nil {
+			return nil, err
+		}
+	}
+
 	return gallery, nil
 }
 
@@ -303,6 +316,12 @@ func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGall
 				return err
 			}
 
+			if input.CustomFields != nil {
+				if err := qb.SetCustomFields(ctx, galleryID, *input.CustomFields); err != nil {
+					return err
+				}
+			}
+
 			ret = append(ret, gallery)
 		}
 
@@ -335,8 +354,9 @@ func (r *mutationResolver) GalleryDestroy(ctx context.Context, input models.Gall
 
 	var galleries []*models.Gallery
 	var imgsDestroyed []*models.Image
+	trash := manager.GetInstance().NewTrashRecorder()
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: trash.Deleter,
 		Paths:   manager.GetInstance().Paths,
 	}
 
@@ -362,8 +382,11 @@ func (r *mutationResolver) GalleryDestroy(ctx context.Context, input models.Gall
 
 			galleries = append(galleries, gallery)
 
-			imgsDestroyed, err = r.galleryService.Destroy(ctx, gallery, fileDeleter, deleteGenerated, deleteFile)
-			if err != nil {
+			if err := trash.Gallery(ctx, gallery, deleteFile, func() error {
+				destroyed, err := r.galleryService.Destroy(ctx, gallery, fileDeleter, deleteGenerated, deleteFile)
+				imgsDestroyed = append(imgsDestroyed, destroyed...)
+				return err
+			}); err != nil {
 				return err
 			}
 		}
@@ -97,6 +97,14 @@ func (r *mutationResolver) GroupCreate(ctx context.Context, input GroupCreateInp
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := r.repository.Group.SetCustomFields(ctx, newGroup.ID, models.CustomFieldsInput{
+				Full: input.CustomFields,
+			}); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -202,6 +210,12 @@ func (r *mutationResolver) GroupUpdate(ctx context.Context, input GroupUpdateInp
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := r.repository.Group.SetCustomFields(ctx, groupID, *input.CustomFields); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -273,6 +287,12 @@ func (r *mutationResolver) BulkGroupUpdate(ctx context.Context, input BulkGroupU
 				return err
 			}
 
+			if input.CustomFields != nil {
+				if err := r.repository.Group.SetCustomFields(ctx, groupID, *input.CustomFields); err != nil {
+					return err
+				}
+			}
+
 			ret = append(ret, group)
 		}
 
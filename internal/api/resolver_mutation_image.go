@@ -6,7 +6,6 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
@@ -309,8 +308,9 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 	}
 
 	var i *models.Image
+	trash := manager.GetInstance().NewTrashRecorder()
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: trash.Deleter,
 		Paths:   manager.GetInstance().Paths,
 	}
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
@@ -323,7 +323,9 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 			return fmt.Errorf("image with id %d not found", imageID)
 		}
 
-		return r.imageService.Destroy(ctx, i, fileDeleter, utils.IsTrue(input.DeleteGenerated), utils.IsTrue(input.DeleteFile))
+		return trash.Image(ctx, i, func() error {
+			return r.imageService.Destroy(ctx, i, fileDeleter, utils.IsTrue(input.DeleteGenerated), utils.IsTrue(input.DeleteFile))
+		})
 	}); err != nil {
 		fileDeleter.Rollback()
 		return false, err
@@ -349,8 +351,9 @@ func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.Image
 	}
 
 	var images []*models.Image
+	trash := manager.GetInstance().NewTrashRecorder()
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: trash.Deleter,
 		Paths:   manager.GetInstance().Paths,
 	}
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
@@ -368,7 +371,9 @@ func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.Image
 
 			images = append(images, i)
 
-			if err := r.imageService.Destroy(ctx, i, fileDeleter, utils.IsTrue(input.DeleteGenerated), utils.IsTrue(input.DeleteFile)); err != nil {
+			if err := trash.Image(ctx, i, func() error {
+				return r.imageService.Destroy(ctx, i, fileDeleter, utils.IsTrue(input.DeleteGenerated), utils.IsTrue(input.DeleteFile))
+			}); err != nil {
 				return err
 			}
 		}
@@ -99,6 +99,24 @@ func (r *mutationResolver) MetadataClean(ctx context.Context, input manager.Clea
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataVerify(ctx context.Context, input manager.VerifyMetadataInput) (string, error) {
+	jobID, err := manager.GetInstance().Verify(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataWriteNfo(ctx context.Context, input manager.WriteNfoMetadataInput) (string, error) {
+	jobID, err := manager.GetInstance().WriteNfo(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) MetadataCleanGenerated(ctx context.Context, input task.CleanGeneratedOptions) (string, error) {
 	mgr := manager.GetInstance()
 	t := &task.CleanGeneratedJob{
@@ -148,6 +166,15 @@ func (r *mutationResolver) BackupDatabase(ctx context.Context, input BackupDatab
 	return nil, nil
 }
 
+func (r *mutationResolver) RestoreBackup(ctx context.Context, name string) (bool, error) {
+	if err := manager.GetInstance().RestoreBackup(name); err != nil {
+		logger.Errorf("Error restoring backup %s: %v", name, err)
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (r *mutationResolver) AnonymiseDatabase(ctx context.Context, input AnonymiseDatabaseInput) (*string, error) {
 	// if download is true, then save to temporary file and return a link
 	download := input.Download != nil && *input.Download
@@ -181,3 +208,9 @@ func (r *mutationResolver) OptimiseDatabase(ctx context.Context) (string, error)
 	jobID := manager.GetInstance().OptimiseDatabase(ctx)
 	return strconv.Itoa(jobID), nil
 }
+
+func (r *mutationResolver) DatabaseHealthCheck(ctx context.Context, input DatabaseHealthCheckInput) (string, error) {
+	repair := input.Repair != nil && *input.Repair
+	jobID := manager.GetInstance().DatabaseHealthCheck(ctx, repair)
+	return strconv.Itoa(jobID), nil
+}
@@ -113,6 +113,14 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input models.Per
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, newPerformer.ID, models.CustomFieldsInput{
+				Full: input.CustomFields,
+			}); err != nil {
+				return err
+			}
+		}
+
 		// update image table
 		if len(imageData) > 0 {
 			if err := qb.UpdateImage(ctx, newPerformer.ID, imageData); err != nil {
@@ -318,6 +326,12 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input models.Per
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, performerID, *input.CustomFields); err != nil {
+				return err
+			}
+		}
+
 		// update image table
 		if imageIncluded {
 			if err := qb.UpdateImage(ctx, performerID, imageData); err != nil {
@@ -427,6 +441,12 @@ func (r *mutationResolver) BulkPerformerUpdate(ctx context.Context, input BulkPe
 				return err
 			}
 
+			if input.CustomFields != nil {
+				if err := qb.SetCustomFields(ctx, performerID, *input.CustomFields); err != nil {
+					return err
+				}
+			}
+
 			ret = append(ret, performer)
 		}
 
@@ -104,7 +104,19 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.Resolver.sceneService.Create(ctx, &newScene, fileIDs, coverImageData)
-		return err
+		if err != nil {
+			return err
+		}
+
+		if input.CustomFields != nil {
+			if err := r.repository.Scene.SetCustomFields(ctx, ret.ID, models.CustomFieldsInput{
+				Full: input.CustomFields,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
 	}); err != nil {
 		return nil, err
 	}
@@ -117,20 +129,28 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	var organised bool
+
 	// Start the transaction and save the scene
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
-		ret, err = r.sceneUpdate(ctx, input, translator)
+		ret, organised, err = r.sceneUpdate(ctx, input, translator)
 		return err
 	}); err != nil {
 		return nil, err
 	}
 
+	if organised {
+		manager.GetInstance().AutoOrganise(ctx, []int{ret.ID})
+	}
+	manager.GetInstance().AutoWriteNfo(ctx, []int{ret.ID})
+
 	r.hookExecutor.ExecutePostHooks(ctx, ret.ID, hook.SceneUpdatePost, input, translator.getFields())
 	return r.getScene(ctx, ret.ID)
 }
 
 func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.SceneUpdateInput) (ret []*models.Scene, err error) {
 	inputMaps := getUpdateInputMaps(ctx)
+	var organisedIDs []int
 
 	// Start the transaction and save the scenes
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
@@ -139,12 +159,15 @@ func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.Sce
 				inputMap: inputMaps[i],
 			}
 
-			thisScene, err := r.sceneUpdate(ctx, *scene, translator)
+			thisScene, organised, err := r.sceneUpdate(ctx, *scene, translator)
 			if err != nil {
 				return err
 			}
 
 			ret = append(ret, thisScene)
+			if organised {
+				organisedIDs = append(organisedIDs, thisScene.ID)
+			}
 		}
 
 		return nil
@@ -152,6 +175,11 @@ func (r *mutationResolver) ScenesUpdate(ctx context.Context, input []*models.Sce
 		return nil, err
 	}
 
+	if len(organisedIDs) > 0 {
+		manager.GetInstance().AutoOrganise(ctx, organisedIDs)
+	}
+	manager.GetInstance().AutoWriteNfo(ctx, sceneIDsOf(ret))
+
 	// execute post hooks outside of txn
 	var newRet []*models.Scene
 	for i, scene := range ret {
@@ -239,37 +267,39 @@ func scenePartialFromInput(input models.SceneUpdateInput, translator changesetTr
 	return &updatedScene, nil
 }
 
-func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUpdateInput, translator changesetTranslator) (*models.Scene, error) {
+// sceneUpdate updates the scene from the input. It also returns true if the
+// scene was marked as organized.
+func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUpdateInput, translator changesetTranslator) (*models.Scene, bool, error) {
 	sceneID, err := strconv.Atoi(input.ID)
 	if err != nil {
-		return nil, fmt.Errorf("converting id: %w", err)
+		return nil, false, fmt.Errorf("converting id: %w", err)
 	}
 
 	qb := r.repository.Scene
 
 	originalScene, err := qb.Find(ctx, sceneID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if originalScene == nil {
-		return nil, fmt.Errorf("scene with id %d not found", sceneID)
+		return nil, false, fmt.Errorf("scene with id %d not found", sceneID)
 	}
 
 	// Populate scene from the input
 	updatedScene, err := scenePartialFromInput(input, translator)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// ensure that title is set where scene has no file
 	if updatedScene.Title.Set && updatedScene.Title.Value == "" {
 		if err := originalScene.LoadFiles(ctx, r.repository.Scene); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		if len(originalScene.Files.List()) == 0 {
-			return nil, errors.New("title must be set if scene has no files")
+			return nil, false, errors.New("title must be set if scene has no files")
 		}
 	}
 
@@ -279,7 +309,7 @@ func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUp
 		// if file hash has changed, we should migrate generated files
 		// after commit
 		if err := originalScene.LoadFiles(ctx, r.repository.Scene); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		// ensure that new primary file is associated with scene
@@ -291,7 +321,7 @@ func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUp
 		}
 
 		if f == nil {
-			return nil, fmt.Errorf("file with id %d not associated with scene", newPrimaryFileID)
+			return nil, false, fmt.Errorf("file with id %d not associated with scene", newPrimaryFileID)
 		}
 	}
 
@@ -300,20 +330,27 @@ func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUp
 		var err error
 		coverImageData, err = utils.ProcessImageInput(ctx, *input.CoverImage)
 		if err != nil {
-			return nil, fmt.Errorf("processing cover image: %w", err)
+			return nil, false, fmt.Errorf("processing cover image: %w", err)
 		}
 	}
 
 	scene, err := qb.UpdatePartial(ctx, sceneID, *updatedScene)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if err := r.sceneUpdateCoverImage(ctx, scene, coverImageData); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return scene, nil
+	if input.CustomFields != nil {
+		if err := qb.SetCustomFields(ctx, sceneID, *input.CustomFields); err != nil {
+			return nil, false, err
+		}
+	}
+
+	organised := scene.Organized && !originalScene.Organized
+	return scene, organised, nil
 }
 
 func (r *mutationResolver) sceneUpdateCoverImage(ctx context.Context, s *models.Scene, coverImageData []byte) error {
@@ -386,17 +423,36 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 	}
 
 	ret := []*models.Scene{}
+	markOrganized := updatedScene.Organized.Set && updatedScene.Organized.Value
+	var organisedIDs []int
 
 	// Start the transaction and save the scenes
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Scene
 
 		for _, sceneID := range sceneIDs {
+			if markOrganized {
+				originalScene, err := qb.Find(ctx, sceneID)
+				if err != nil {
+					return err
+				}
+
+				if originalScene != nil && !originalScene.Organized {
+					organisedIDs = append(organisedIDs, sceneID)
+				}
+			}
+
 			scene, err := qb.UpdatePartial(ctx, sceneID, updatedScene)
 			if err != nil {
 				return err
 			}
 
+			if input.CustomFields != nil {
+				if err := qb.SetCustomFields(ctx, sceneID, *input.CustomFields); err != nil {
+					return err
+				}
+			}
+
 			ret = append(ret, scene)
 		}
 
@@ -405,6 +461,11 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 		return nil, err
 	}
 
+	if len(organisedIDs) > 0 {
+		manager.GetInstance().AutoOrganise(ctx, organisedIDs)
+	}
+	manager.GetInstance().AutoWriteNfo(ctx, sceneIDsOf(ret))
+
 	// execute post hooks outside of txn
 	var newRet []*models.Scene
 	for _, scene := range ret {
@@ -421,6 +482,15 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 	return newRet, nil
 }
 
+// sceneIDsOf returns the IDs of the scenes.
+func sceneIDsOf(scenes []*models.Scene) []int {
+	ret := make([]int, len(scenes))
+	for i, s := range scenes {
+		ret[i] = s.ID
+	}
+	return ret
+}
+
 func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneDestroyInput) (bool, error) {
 	sceneID, err := strconv.Atoi(input.ID)
 	if err != nil {
@@ -430,8 +500,9 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 	fileNamingAlgo := manager.GetInstance().Config.GetVideoFileNamingAlgorithm()
 
 	var s *models.Scene
+	trash := manager.GetInstance().NewTrashRecorder()
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        trash.Deleter,
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
@@ -454,7 +525,9 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 		// kill any running encoders
 		manager.KillRunningStreams(s, fileNamingAlgo)
 
-		return r.sceneService.Destroy(ctx, s, fileDeleter, deleteGenerated, deleteFile)
+		return trash.Scene(ctx, s, func() error {
+			return r.sceneService.Destroy(ctx, s, fileDeleter, deleteGenerated, deleteFile)
+		})
 	}); err != nil {
 		fileDeleter.Rollback()
 		return false, err
@@ -483,8 +556,9 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 	var scenes []*models.Scene
 	fileNamingAlgo := manager.GetInstance().Config.GetVideoFileNamingAlgorithm()
 
+	trash := manager.GetInstance().NewTrashRecorder()
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        trash.Deleter,
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
@@ -509,7 +583,9 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 			// kill any running encoders
 			manager.KillRunningStreams(scene, fileNamingAlgo)
 
-			if err := r.sceneService.Destroy(ctx, scene, fileDeleter, deleteGenerated, deleteFile); err != nil {
+			if err := trash.Scene(ctx, scene, func() error {
+				return r.sceneService.Destroy(ctx, scene, fileDeleter, deleteGenerated, deleteFile)
+			}); err != nil {
 				return err
 			}
 		}
@@ -625,6 +701,33 @@ func (r *mutationResolver) SceneMerge(ctx context.Context, input SceneMergeInput
 	return ret, nil
 }
 
+func (r *mutationResolver) SceneExportClip(ctx context.Context, input manager.ExportClipInput) (string, error) {
+	jobID, err := manager.GetInstance().ExportClip(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) SceneMarkersExportClips(ctx context.Context, input manager.ExportMarkerClipsInput) (string, error) {
+	jobID, err := manager.GetInstance().ExportMarkerClips(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) OrganiseScenes(ctx context.Context, input manager.OrganiseScenesInput) (string, error) {
+	jobID, err := manager.GetInstance().Organise(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) getSceneMarker(ctx context.Context, id int) (ret *models.SceneMarker, err error) {
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.repository.SceneMarker.Find(ctx, id)
@@ -687,6 +790,67 @@ func (r *mutationResolver) SceneMarkerCreate(ctx context.Context, input SceneMar
 	return r.getSceneMarker(ctx, newMarker.ID)
 }
 
+func (r *mutationResolver) SceneAcceptSuggestedMarkers(ctx context.Context, input SceneAcceptSuggestedMarkersInput) ([]*models.SceneMarker, error) {
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene id: %w", err)
+	}
+
+	var suggestions []*models.SuggestedMarker
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		s, err := r.repository.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return err
+		}
+
+		markers, err := r.repository.SceneMarker.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		suggestions, err = manager.GetSceneSuggestedMarkers(s, markers, manager.GetInstance().Config.GetVideoFileNamingAlgorithm())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var title string
+	if input.Title != nil {
+		title = *input.Title
+	}
+
+	var ret []*models.SceneMarker
+	for _, seconds := range input.Seconds {
+		suggestion := models.FindSuggestedMarker(suggestions, seconds)
+		if suggestion == nil {
+			return ret, fmt.Errorf("no suggested marker at %v seconds", seconds)
+		}
+
+		marker, err := r.SceneMarkerCreate(ctx, SceneMarkerCreateInput{
+			Title:        title,
+			Seconds:      suggestion.Seconds,
+			EndSeconds:   suggestion.EndSeconds,
+			SceneID:      input.SceneID,
+			PrimaryTagID: input.PrimaryTagID,
+			TagIds:       input.TagIds,
+		})
+		if err != nil {
+			return ret, err
+		}
+
+		ret = append(ret, marker)
+	}
+
+	return ret, nil
+}
+
 func validateSceneMarkerEndSeconds(seconds, endSeconds float64) error {
 	if endSeconds < seconds {
 		return fmt.Errorf("end_seconds (%f) must be greater than or equal to seconds (%f)", endSeconds, seconds)
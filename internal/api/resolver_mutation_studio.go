@@ -76,6 +76,14 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input models.Studio
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, newStudio.ID, models.CustomFieldsInput{
+				Full: input.CustomFields,
+			}); err != nil {
+				return err
+			}
+		}
+
 		if len(imageData) > 0 {
 			if err := qb.UpdateImage(ctx, newStudio.ID, imageData); err != nil {
 				return err
@@ -148,6 +156,12 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input models.Studio
 			return err
 		}
 
+		if input.CustomFields != nil {
+			if err := qb.SetCustomFields(ctx, studioID, *input.CustomFields); err != nil {
+				return err
+			}
+		}
+
 		if imageIncluded {
 			if err := qb.UpdateImage(ctx, studioID, imageData); err != nil {
 				return err
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) RestoreFromTrash(ctx context.Context, ids []string) (bool, error) {
+	idInts, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := manager.GetInstance().RestoreFromTrash(ctx, idInts); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) EmptyTrash(ctx context.Context, ids []string) (int, error) {
+	var idInts []int
+	if ids != nil {
+		var err error
+		idInts, err = stringslice.StringSliceToIntSlice(ids)
+		if err != nil {
+			return 0, fmt.Errorf("converting ids: %w", err)
+		}
+
+		if len(idInts) == 0 {
+			return 0, nil
+		}
+	}
+
+	return manager.GetInstance().EmptyTrash(ctx, idInts)
+}
@@ -0,0 +1,12 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+)
+
+func (r *mutationResolver) ClearWebhookDeadLetters(ctx context.Context) (bool, error) {
+	manager.GetInstance().ClearWebhookDeadLetters()
+	return true, nil
+}
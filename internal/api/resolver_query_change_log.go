@@ -0,0 +1,16 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+)
+
+func (r *queryResolver) ChangesSince(ctx context.Context, cursor *string, limit *int) (*manager.ChangesSinceResult, error) {
+	l := manager.DefaultChangesLimit
+	if limit != nil {
+		l = *limit
+	}
+
+	return manager.GetInstance().ChangesSince(ctx, cursor, l)
+}
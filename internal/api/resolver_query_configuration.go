@@ -83,6 +83,12 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		Stashes:                       config.GetStashPaths(),
 		DatabasePath:                  config.GetDatabasePath(),
 		BackupDirectoryPath:           config.GetBackupDirectoryPath(),
+		BackupInterval:                config.GetBackupInterval(),
+		BackupKeepCount:               config.GetBackupKeepCount(),
+		BackupKeepDaily:               config.GetBackupKeepDaily(),
+		BackupKeepWeekly:              config.GetBackupKeepWeekly(),
+		BackupCompress:                config.IsBackupCompress(),
+		BackupIncludeBlobs:            config.IsBackupIncludeBlobs(),
 		GeneratedPath:                 config.GetGeneratedPath(),
 		MetadataPath:                  config.GetMetadataPath(),
 		ConfigFilePath:                config.GetConfigFile(),
@@ -91,6 +97,9 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		CachePath:                     config.GetCachePath(),
 		BlobsPath:                     config.GetBlobsPath(),
 		BlobsStorage:                  config.GetBlobsStorage(),
+		TrashPath:                     config.GetTrashPath(),
+		TrashRetentionDays:            config.GetTrashRetentionDays(),
+		ChangeLogRetentionDays:        config.GetChangeLogRetentionDays(),
 		FfmpegPath:                    config.GetFFMpegPath(),
 		FfprobePath:                   config.GetFFProbePath(),
 		CalculateMd5:                  config.IsCalculateMD5(),
@@ -105,6 +114,8 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		TranscodeHardwareAcceleration: config.GetTranscodeHardwareAcceleration(),
 		MaxTranscodeSize:              &maxTranscodeSize,
 		MaxStreamingTranscodeSize:     &maxStreamingTranscodeSize,
+		StreamingBandwidthPolicies:    config.GetStreamingBandwidthPolicies(),
+		OrganiserRules:                config.GetOrganiserRules(),
 		WriteImageThumbnails:          config.IsWriteImageThumbnails(),
 		CreateImageClipsFromVideos:    config.IsCreateImageClipsFromVideos(),
 		GalleryCoverRegex:             config.GetGalleryCoverRegex(),
@@ -112,6 +123,9 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		Username:                      config.GetUsername(),
 		Password:                      config.GetPasswordHash(),
 		MaxSessionAge:                 config.GetMaxSessionAge(),
+		GraphqlMaxComplexity:          config.GetGraphQLMaxComplexity(),
+		GraphqlMaxDepth:               config.GetGraphQLMaxDepth(),
+		QueryTimeout:                  config.GetQueryTimeout(),
 		LogFile:                       &logFile,
 		LogOut:                        config.GetLogOut(),
 		LogLevel:                      config.GetLogLevel(),
@@ -120,10 +134,16 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 		ImageExtensions:               config.GetImageExtensions(),
 		GalleryExtensions:             config.GetGalleryExtensions(),
 		CreateGalleriesFromFolders:    config.GetCreateGalleriesFromFolders(),
+		WatchLibrary:                  config.GetWatchLibrary(),
+		ReadNfo:                       config.GetReadNfo(),
+		NfoPrecedence:                 config.GetNfoPrecedence(),
+		WriteNfo:                      config.GetWriteNfo(),
+		WatchRescanInterval:           config.GetWatchRescanInterval(),
 		Excludes:                      config.GetExcludes(),
 		ImageExcludes:                 config.GetImageExcludes(),
 		CustomPerformerImageLocation:  &customPerformerImageLocation,
 		StashBoxes:                    config.GetStashBoxes(),
+		Webhooks:                      config.GetWebhooks(),
 		PythonPath:                    config.GetPythonPath(),
 		TranscodeInputArgs:            config.GetTranscodeInputArgs(),
 		TranscodeOutputArgs:           config.GetTranscodeOutputArgs(),
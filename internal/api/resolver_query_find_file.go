@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindFiles(ctx context.Context, fileFilter *models.FileFilterType, filter *models.FindFilterType) (ret *FindFilesResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		result, err := r.repository.File.Query(ctx, models.FileQueryOptions{
+			QueryOptions: models.QueryOptions{
+				FindFilter: filter,
+				Count:      true,
+			},
+			FileFilter: fileFilter,
+		})
+		if err != nil {
+			return err
+		}
+
+		files, err := result.Resolve(ctx)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindFilesResultType{
+			Count: result.Count,
+			Files: make([]BaseFile, len(files)),
+		}
+		for i, f := range files {
+			ret.Files[i] = convertBaseFile(f)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
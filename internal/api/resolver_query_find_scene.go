@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"strconv"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 func (r *queryResolver) FindScene(ctx context.Context, id *string, checksum *string) (*models.Scene, error) {
@@ -228,7 +230,7 @@ func (r *queryResolver) ParseSceneFilenames(ctx context.Context, filter *models.
 	return ret, nil
 }
 
-func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, durationDiff *float64) (ret [][]*models.Scene, err error) {
+func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int, durationDiff *float64, audio *bool) (ret [][]*models.Scene, err error) {
 	dist := 0
 	durDiff := -1.
 	if distance != nil {
@@ -238,7 +240,29 @@ func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int,
 		durDiff = *durationDiff
 	}
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
-		ret, err = r.repository.Scene.FindDuplicates(ctx, dist, durDiff)
+		ret, err = r.repository.Scene.FindDuplicates(ctx, dist, durDiff, audio != nil && *audio)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindOverlappingScenes(ctx context.Context, distance *int, minDuration *float64) (ret []*models.SceneOverlap, err error) {
+	dist := 0
+	minDur := 60.
+	if distance != nil {
+		dist = *distance
+	}
+	if minDuration != nil {
+		minDur = *minDuration
+	}
+	if dist < 0 || dist > utils.MaxSegmentDistance {
+		return nil, fmt.Errorf("distance must be between 0 and %d", utils.MaxSegmentDistance)
+	}
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.FindOverlaps(ctx, dist, minDur)
 		return err
 	}); err != nil {
 		return nil, err
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindTrash(ctx context.Context, objectType *models.TrashObjectType, filter *models.FindFilterType) (ret *FindTrashResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		items, count, err := r.repository.Trash.Query(ctx, objectType, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindTrashResultType{
+			Count: count,
+			Items: items,
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
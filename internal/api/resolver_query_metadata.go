@@ -4,8 +4,17 @@ import (
 	"context"
 
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/task"
 )
 
 func (r *queryResolver) SystemStatus(ctx context.Context) (*manager.SystemStatus, error) {
 	return manager.GetInstance().GetSystemStatus(), nil
 }
+
+func (r *queryResolver) ListBackups(ctx context.Context) ([]*manager.DatabaseBackup, error) {
+	return manager.GetInstance().ListBackups()
+}
+
+func (r *queryResolver) DatabaseHealthReport(ctx context.Context) (*task.DatabaseHealthReport, error) {
+	return manager.GetInstance().DatabaseHealthReport(), nil
+}
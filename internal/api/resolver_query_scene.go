@@ -8,6 +8,7 @@ import (
 	"github.com/stashapp/stash/internal/api/urlbuilders"
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/organise"
 )
 
 func (r *queryResolver) SceneStreams(ctx context.Context, id *string) ([]*manager.SceneStreamEndpoint, error) {
@@ -41,5 +42,9 @@ func (r *queryResolver) SceneStreams(ctx context.Context, id *string) ([]*manage
 	builder := urlbuilders.NewSceneURLBuilder(baseURL, scene)
 	apiKey := config.GetAPIKey()
 
-	return manager.GetSceneStreamPaths(scene, builder.GetStreamURL(apiKey), config.GetMaxStreamingTranscodeSize())
+	return manager.GetSceneStreamPaths(scene, builder.GetStreamURL(apiKey), config.GetMaxStreamingTranscodeSize(), manager.GetStreamingMaxBitrate(ctx))
+}
+
+func (r *queryResolver) OrganisePlan(ctx context.Context, input manager.OrganiseScenesInput) ([]*organise.Move, error) {
+	return manager.GetInstance().PlanOrganise(ctx, input)
 }
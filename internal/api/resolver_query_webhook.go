@@ -0,0 +1,12 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/webhook"
+)
+
+func (r *queryResolver) WebhookDeadLetters(ctx context.Context) ([]*webhook.DeadLetter, error) {
+	return manager.GetInstance().WebhookDeadLetters(), nil
+}
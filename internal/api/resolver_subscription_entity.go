@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"slices"
+
+	"github.com/stashapp/stash/internal/manager"
+)
+
+func (r *subscriptionResolver) EntityChanged(ctx context.Context, types []manager.EntityType) (<-chan *manager.EntityChange, error) {
+	msg := make(chan *manager.EntityChange, 100)
+
+	subscription := manager.GetInstance().EntityChangeSubscribe(ctx)
+
+	go func() {
+		defer close(msg)
+
+		for change := range subscription {
+			if len(types) > 0 && !slices.Contains(types, change.Type) {
+				continue
+			}
+
+			select {
+			case msg <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return msg, nil
+}
@@ -0,0 +1,3 @@
+// Package rest provides a read-only REST API for the main object types, along
+// with its OpenAPI document.
+package rest
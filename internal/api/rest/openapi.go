@@ -0,0 +1,309 @@
+package rest
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/build"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+const openAPIVersion = "3.0.3"
+
+// The following types are the subset of the OpenAPI 3.0 specification used
+// by the API document.
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+	Security   []map[string]any     `json:"security"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type PathItem struct {
+	Get *Operation `json:"get,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Style       string  `json:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *int               `json:"minimum,omitempty"`
+	Maximum     *int               `json:"maximum,omitempty"`
+	Default     any                `json:"default,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+func schemaRef(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func responseRef(name string) *Response {
+	return &Response{Ref: "#/components/responses/" + name}
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		"application/json": {Schema: s},
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaGenerator generates schemas from the response types. Struct types
+// are added to the schema components and referenced by name.
+type schemaGenerator struct {
+	schemas map[string]*Schema
+}
+
+func (g *schemaGenerator) generate(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Pointer:
+		s := g.generate(t.Elem())
+		if s.Ref != "" {
+			// sibling properties of $ref are ignored
+			return s
+		}
+		s.Nullable = true
+		return s
+	case t.Kind() == reflect.Slice:
+		return &Schema{Type: "array", Items: g.generate(t.Elem())}
+	case t.Kind() == reflect.Struct:
+		name := t.Name()
+		if _, found := g.schemas[name]; !found {
+			// add before generating the fields in case of recursion
+			s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			g.schemas[name] = s
+			g.generateProperties(s, t)
+		}
+		return schemaRef(name)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+func (g *schemaGenerator) generateProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		p := g.generate(f.Type)
+		if format := f.Tag.Get("format"); format != "" {
+			p.Format = format
+		}
+		p.Description = f.Tag.Get("description")
+		if p.Ref != "" && p.Description != "" {
+			// description cannot be set alongside $ref
+			p.Description = ""
+		}
+
+		s.Properties[name] = p
+		s.Required = append(s.Required, name)
+	}
+}
+
+func (g *schemaGenerator) listSchema(r resourceSpec) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"count":    {Type: "integer", Description: "Total number of results"},
+			"page":     {Type: "integer"},
+			"per_page": {Type: "integer"},
+			"items":    {Type: "array", Items: g.generate(r.itemType())},
+		},
+		Required: []string{"count", "page", "per_page", "items"},
+	}
+}
+
+func findFilterParameters(r resourceSpec) []*Parameter {
+	return []*Parameter{
+		{Name: "q", In: "query", Description: "Search query", Schema: &Schema{Type: "string"}},
+		{Name: "page", In: "query", Schema: &Schema{Type: "integer", Minimum: intPtr(1), Default: 1}},
+		{Name: "per_page", In: "query", Schema: &Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(MaxPerPage), Default: DefaultPerPage}},
+		{Name: "sort", In: "query", Description: "Field to sort by, as in the GraphQL API", Schema: &Schema{Type: "string", Enum: r.sortOptions(), Default: r.defaultSort()}},
+		{Name: "direction", In: "query", Schema: &Schema{Type: "string", Enum: []string{"asc", "desc"}, Default: "asc"}},
+	}
+}
+
+// OpenAPI returns the OpenAPI document describing the API. serverURL is the
+// URL that the API is served from.
+func OpenAPI(serverURL string) *Document {
+	g := &schemaGenerator{schemas: make(map[string]*Schema)}
+	g.schemas["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"error": {Type: "string"},
+			"code":  {Type: "string", Description: "Set for errors that cannot be identified by status alone", Enum: []string{codeQueryTimeout}},
+		},
+		Required: []string{"error"},
+	}
+
+	errorResponse := func(description string) *Response {
+		return &Response{
+			Description: description,
+			Content:     jsonContent(schemaRef("Error")),
+		}
+	}
+
+	doc := &Document{
+		OpenAPI: openAPIVersion,
+		Info: Info{
+			Title:       "Stash REST API",
+			Description: "Read-only access to the main object types. Use the GraphQL API for everything else.",
+			Version:     build.VersionString(),
+		},
+		Servers: []Server{{URL: serverURL}},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: g.schemas,
+			Responses: map[string]*Response{
+				"BadRequest":   errorResponse("Invalid parameters"),
+				"NotFound":     errorResponse("Object not found"),
+				"Unauthorized": {Description: "Authentication is required"},
+				"Timeout":      errorResponse("The query exceeded the server's query timeout"),
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"ApiKey": {Type: "apiKey", Name: session.ApiKeyHeader, In: "header"},
+			},
+		},
+		Security: []map[string]any{{"ApiKey": []string{}}},
+	}
+
+	for _, r := range resources {
+		name := r.name()
+		title := r.title()
+
+		listParams := findFilterParameters(r)
+		for _, p := range r.filterParams() {
+			param := &Parameter{
+				Name:        p.name,
+				In:          "query",
+				Description: p.description,
+				Schema:      p.schema(),
+			}
+			if p.kind == paramIDs {
+				// comma-separated
+				explode := false
+				param.Style = "form"
+				param.Explode = &explode
+			}
+			listParams = append(listParams, param)
+		}
+
+		listName := title + "List"
+		g.schemas[listName] = g.listSchema(r)
+
+		doc.Paths["/"+name] = &PathItem{
+			Get: &Operation{
+				OperationID: "list" + title + "s",
+				Summary:     "Find " + name,
+				Tags:        []string{name},
+				Parameters:  listParams,
+				Responses: map[string]*Response{
+					"200": {Description: "Page of " + name, Content: jsonContent(schemaRef(listName))},
+					"400": responseRef("BadRequest"),
+					"401": responseRef("Unauthorized"),
+					"503": responseRef("Timeout"),
+				},
+			},
+		}
+
+		doc.Paths["/"+name+"/{id}"] = &PathItem{
+			Get: &Operation{
+				OperationID: "get" + title,
+				Summary:     "Find " + strings.ToLower(title) + " by ID",
+				Tags:        []string{name},
+				Parameters: []*Parameter{
+					{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}},
+				},
+				Responses: map[string]*Response{
+					"200": {Description: title, Content: jsonContent(g.generate(r.itemType()))},
+					"400": responseRef("BadRequest"),
+					"401": responseRef("Unauthorized"),
+					"404": responseRef("NotFound"),
+					"503": responseRef("Timeout"),
+				},
+			},
+		}
+	}
+
+	return doc
+}
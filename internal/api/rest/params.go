@@ -0,0 +1,256 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	DefaultPerPage = 25
+	MaxPerPage     = 1000
+)
+
+var errInvalidParameter = errors.New("invalid parameter")
+
+type paramKind int
+
+const (
+	paramString paramKind = iota
+	paramBool
+	paramInt
+	paramIDs
+)
+
+// filterParam is a query parameter that sets a criterion of the filter type F.
+type filterParam[F any] struct {
+	paramSpec
+	set func(f *F, v string) error
+}
+
+type paramSpec struct {
+	name        string
+	description string
+	kind        paramKind
+}
+
+func (p paramSpec) schema() *Schema {
+	switch p.kind {
+	case paramBool:
+		return &Schema{Type: "boolean"}
+	case paramInt:
+		return &Schema{Type: "integer"}
+	case paramIDs:
+		return &Schema{Type: "array", Items: &Schema{Type: "integer"}}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+func parameterError(name string, err error) error {
+	return fmt.Errorf("%w %s: %v", errInvalidParameter, name, err)
+}
+
+func parseBool(v string) (bool, error) {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("must be true or false")
+	}
+	return b, nil
+}
+
+// parseIDs parses a comma-separated list of IDs. The IDs are returned as
+// strings to match the criterion types.
+func parseIDs(v string) ([]string, error) {
+	var ret []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if _, err := strconv.Atoi(s); err != nil {
+			return nil, fmt.Errorf("%q is not an ID", s)
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+func textParam[F any](name string, field func(f *F) **models.StringCriterionInput) filterParam[F] {
+	return filterParam[F]{
+		paramSpec: paramSpec{
+			name:        name,
+			description: "Includes all of the words in " + name,
+			kind:        paramString,
+		},
+		set: func(f *F, v string) error {
+			*field(f) = &models.StringCriterionInput{
+				Value:    v,
+				Modifier: models.CriterionModifierIncludes,
+			}
+			return nil
+		},
+	}
+}
+
+func boolParam[F any](name string, description string, field func(f *F) **bool) filterParam[F] {
+	return filterParam[F]{
+		paramSpec: paramSpec{
+			name:        name,
+			description: description,
+			kind:        paramBool,
+		},
+		set: func(f *F, v string) error {
+			b, err := parseBool(v)
+			if err != nil {
+				return err
+			}
+			*field(f) = &b
+			return nil
+		},
+	}
+}
+
+func minRatingParam[F any](field func(f *F) **models.IntCriterionInput) filterParam[F] {
+	return filterParam[F]{
+		paramSpec: paramSpec{
+			name:        "min_rating100",
+			description: "Minimum rating, from 1 to 100",
+			kind:        paramInt,
+		},
+		set: func(f *F, v string) error {
+			i, err := strconv.Atoi(v)
+			if err != nil || i < 1 || i > 100 {
+				return errors.New("must be a number from 1 to 100")
+			}
+			*field(f) = &models.IntCriterionInput{
+				Value:    i - 1,
+				Modifier: models.CriterionModifierGreaterThan,
+			}
+			return nil
+		},
+	}
+}
+
+func idsParam[F any](name string, description string, field func(f *F) **models.MultiCriterionInput) filterParam[F] {
+	return filterParam[F]{
+		paramSpec: paramSpec{
+			name:        name,
+			description: description,
+			kind:        paramIDs,
+		},
+		set: func(f *F, v string) error {
+			ids, err := parseIDs(v)
+			if err != nil {
+				return err
+			}
+			*field(f) = &models.MultiCriterionInput{
+				Value:    ids,
+				Modifier: models.CriterionModifierIncludes,
+			}
+			return nil
+		},
+	}
+}
+
+func hierarchicalIDsParam[F any](name string, description string, field func(f *F) **models.HierarchicalMultiCriterionInput) filterParam[F] {
+	return filterParam[F]{
+		paramSpec: paramSpec{
+			name:        name,
+			description: description,
+			kind:        paramIDs,
+		},
+		set: func(f *F, v string) error {
+			ids, err := parseIDs(v)
+			if err != nil {
+				return err
+			}
+			*field(f) = &models.HierarchicalMultiCriterionInput{
+				Value:    ids,
+				Modifier: models.CriterionModifierIncludes,
+			}
+			return nil
+		},
+	}
+}
+
+func isMissingParam[F any](example string, field func(f *F) **string) filterParam[F] {
+	return filterParam[F]{
+		paramSpec: paramSpec{
+			name:        "is_missing",
+			description: "Only include objects without a value for the field, such as " + example + ". Accepts the same fields as the GraphQL is_missing criterion.",
+			kind:        paramString,
+		},
+		set: func(f *F, v string) error {
+			*field(f) = &v
+			return nil
+		},
+	}
+}
+
+// parseFilter returns the filter set by the parameters in q. It returns nil
+// if none of the parameters are set.
+func parseFilter[F any](params []filterParam[F], q url.Values) (*F, error) {
+	var ret *F
+	for _, p := range params {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+
+		if ret == nil {
+			ret = new(F)
+		}
+
+		if err := p.set(ret, v); err != nil {
+			return nil, parameterError(p.name, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// parseFindFilter returns the find filter set by the paging, sorting and
+// search parameters in q.
+func parseFindFilter(q url.Values) (*models.FindFilterType, error) {
+	ret := &models.FindFilterType{}
+
+	page := 1
+	if v := q.Get("page"); v != "" {
+		var err error
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return nil, parameterError("page", errors.New("must be a positive number"))
+		}
+	}
+	ret.Page = &page
+
+	perPage := DefaultPerPage
+	if v := q.Get("per_page"); v != "" {
+		var err error
+		perPage, err = strconv.Atoi(v)
+		if err != nil || perPage < 1 || perPage > MaxPerPage {
+			return nil, parameterError("per_page", fmt.Errorf("must be a number from 1 to %d", MaxPerPage))
+		}
+	}
+	ret.PerPage = &perPage
+
+	if v := q.Get("q"); v != "" {
+		ret.Q = &v
+	}
+
+	if v := q.Get("sort"); v != "" {
+		ret.Sort = &v
+	}
+
+	if v := q.Get("direction"); v != "" {
+		direction := models.SortDirectionEnum(strings.ToUpper(v))
+		if !direction.IsValid() {
+			return nil, parameterError("direction", errors.New("must be asc or desc"))
+		}
+		ret.Direction = &direction
+	}
+
+	return ret, nil
+}
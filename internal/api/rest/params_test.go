@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func intP(i int) *int {
+	return &i
+}
+
+func TestParseFindFilter(t *testing.T) {
+	desc := models.SortDirectionEnumDesc
+	q := "search"
+	sort := "date"
+
+	tests := []struct {
+		query   string
+		want    *models.FindFilterType
+		wantErr bool
+	}{
+		{"", &models.FindFilterType{Page: intP(1), PerPage: intP(DefaultPerPage)}, false},
+		{
+			"page=2&per_page=50&q=search&sort=date&direction=desc",
+			&models.FindFilterType{Page: intP(2), PerPage: intP(50), Q: &q, Sort: &sort, Direction: &desc},
+			false,
+		},
+		{"page=0", nil, true},
+		{"page=x", nil, true},
+		{"per_page=-1", nil, true},
+		{"per_page=1001", nil, true},
+		{"direction=up", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			got, err := parseFindFilter(values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFindFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	organized := true
+
+	tests := []struct {
+		query   string
+		want    *models.SceneFilterType
+		wantErr bool
+	}{
+		{"page=2", nil, false},
+		{
+			"title=foo&organized=true&min_rating100=60&tags=1,2&performers=3&is_missing=studio",
+			&models.SceneFilterType{
+				Title:     &models.StringCriterionInput{Value: "foo", Modifier: models.CriterionModifierIncludes},
+				Organized: &organized,
+				Rating100: &models.IntCriterionInput{Value: 59, Modifier: models.CriterionModifierGreaterThan},
+				Tags: &models.HierarchicalMultiCriterionInput{
+					Value:    []string{"1", "2"},
+					Modifier: models.CriterionModifierIncludes,
+				},
+				Performers: &models.MultiCriterionInput{
+					Value:    []string{"3"},
+					Modifier: models.CriterionModifierIncludes,
+				},
+				IsMissing: func() *string { s := "studio"; return &s }(),
+			},
+			false,
+		},
+		{"organized=maybe", nil, true},
+		{"min_rating100=0", nil, true},
+		{"tags=1,a", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			got, err := parseFilter(sceneResource.params, values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseFilter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// resourceSpec is the non-generic interface of a resource, used to build the
+// routes and OpenAPI document.
+type resourceSpec interface {
+	name() string
+	title() string
+	defaultSort() string
+	sortOptions() []string
+	itemType() reflect.Type
+	filterParams() []paramSpec
+	routes(h Handler) chi.Router
+}
+
+// resource is an object type served by the API. M is the model type, O the
+// JSON representation of the model and F the filter type.
+type resource[M any, O any, F any] struct {
+	path   string
+	schema string
+	sort   string
+	sorts  []string
+	params []filterParam[F]
+
+	find     func(ctx context.Context, r models.Repository, id int) (*M, error)
+	query    func(ctx context.Context, r models.Repository, filter *F, findFilter *models.FindFilterType) ([]*M, int, error)
+	toOutput func(ctx context.Context, r models.Repository, baseURL string, m *M) (*O, error)
+}
+
+func (rs resource[M, O, F]) name() string {
+	return rs.path
+}
+
+func (rs resource[M, O, F]) title() string {
+	return rs.schema
+}
+
+func (rs resource[M, O, F]) defaultSort() string {
+	return rs.sort
+}
+
+func (rs resource[M, O, F]) sortOptions() []string {
+	return rs.sorts
+}
+
+func (rs resource[M, O, F]) itemType() reflect.Type {
+	return reflect.TypeOf((*O)(nil)).Elem()
+}
+
+func (rs resource[M, O, F]) filterParams() []paramSpec {
+	ret := make([]paramSpec, len(rs.params))
+	for i, p := range rs.params {
+		ret[i] = p.paramSpec
+	}
+	return ret
+}
+
+func (rs resource[M, O, F]) routes(h Handler) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		rs.list(h, w, r)
+	})
+	r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		rs.get(h, w, r)
+	})
+	return r
+}
+
+// listResult is the response to a list request.
+type listResult[O any] struct {
+	Count   int  `json:"count"`
+	Page    int  `json:"page"`
+	PerPage int  `json:"per_page"`
+	Items   []*O `json:"items"`
+}
+
+func (rs resource[M, O, F]) list(h Handler, w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	findFilter, err := parseFindFilter(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := parseFilter(rs.params, q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if findFilter.Sort == nil {
+		findFilter.Sort = &rs.sort
+	}
+
+	ret := listResult[O]{
+		Page:    *findFilter.Page,
+		PerPage: *findFilter.PerPage,
+		Items:   []*O{},
+	}
+
+	ctx := r.Context()
+	baseURL := h.baseURL(ctx)
+	if err := txn.WithReadTxn(ctx, h.Repository.TxnManager, func(ctx context.Context) error {
+		items, count, err := rs.query(ctx, h.Repository, filter, findFilter)
+		if err != nil {
+			return err
+		}
+
+		ret.Count = count
+		for _, m := range items {
+			o, err := rs.toOutput(ctx, h.Repository, baseURL, m)
+			if err != nil {
+				return err
+			}
+			ret.Items = append(ret.Items, o)
+		}
+
+		return nil
+	}); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (rs resource[M, O, F]) get(h Handler, w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, parameterError("id", errors.New("must be a number")))
+		return
+	}
+
+	ctx := r.Context()
+	baseURL := h.baseURL(ctx)
+
+	var ret *O
+	if err := txn.WithReadTxn(ctx, h.Repository.TxnManager, func(ctx context.Context) error {
+		m, err := rs.find(ctx, h.Repository, id)
+		if err != nil || m == nil {
+			return err
+		}
+
+		ret, err = rs.toOutput(ctx, h.Repository, baseURL, m)
+		return err
+	}); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	if ret == nil {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ret)
+}
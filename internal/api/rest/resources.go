@@ -0,0 +1,205 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+var sceneResource = resource[models.Scene, Scene, models.SceneFilterType]{
+	path:   "scenes",
+	schema: "Scene",
+	sort:   "title",
+	sorts:  sqlite.SortOptions("scenes"),
+	params: []filterParam[models.SceneFilterType]{
+		textParam("title", func(f *models.SceneFilterType) **models.StringCriterionInput { return &f.Title }),
+		textParam("path", func(f *models.SceneFilterType) **models.StringCriterionInput { return &f.Path }),
+		boolParam("organized", "Only include organized or unorganized scenes", func(f *models.SceneFilterType) **bool { return &f.Organized }),
+		minRatingParam(func(f *models.SceneFilterType) **models.IntCriterionInput { return &f.Rating100 }),
+		hierarchicalIDsParam("studios", "Only include scenes from any of the studios", func(f *models.SceneFilterType) **models.HierarchicalMultiCriterionInput { return &f.Studios }),
+		hierarchicalIDsParam("tags", "Only include scenes with any of the tags", func(f *models.SceneFilterType) **models.HierarchicalMultiCriterionInput { return &f.Tags }),
+		idsParam("performers", "Only include scenes with any of the performers", func(f *models.SceneFilterType) **models.MultiCriterionInput { return &f.Performers }),
+		idsParam("galleries", "Only include scenes with any of the galleries", func(f *models.SceneFilterType) **models.MultiCriterionInput { return &f.Galleries }),
+		hierarchicalIDsParam("groups", "Only include scenes in any of the groups", func(f *models.SceneFilterType) **models.HierarchicalMultiCriterionInput { return &f.Groups }),
+		isMissingParam("studio", func(f *models.SceneFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Scene, error) {
+		return r.Scene.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.SceneFilterType, findFilter *models.FindFilterType) ([]*models.Scene, int, error) {
+		result, err := r.Scene.Query(ctx, models.SceneQueryOptions{
+			QueryOptions: models.QueryOptions{
+				FindFilter: findFilter,
+				Count:      true,
+			},
+			SceneFilter: filter,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		scenes, err := result.Resolve(ctx)
+		return scenes, result.Count, err
+	},
+	toOutput: sceneFromModel,
+}
+
+var performerResource = resource[models.Performer, Performer, models.PerformerFilterType]{
+	path:   "performers",
+	schema: "Performer",
+	sort:   "name",
+	sorts:  sqlite.SortOptions("performers"),
+	params: []filterParam[models.PerformerFilterType]{
+		textParam("name", func(f *models.PerformerFilterType) **models.StringCriterionInput { return &f.Name }),
+		boolParam("favorite", "Only include favorite or non-favorite performers", func(f *models.PerformerFilterType) **bool { return &f.FilterFavorites }),
+		minRatingParam(func(f *models.PerformerFilterType) **models.IntCriterionInput { return &f.Rating100 }),
+		hierarchicalIDsParam("tags", "Only include performers with any of the tags", func(f *models.PerformerFilterType) **models.HierarchicalMultiCriterionInput { return &f.Tags }),
+		hierarchicalIDsParam("studios", "Only include performers in scenes, images or galleries from any of the studios", func(f *models.PerformerFilterType) **models.HierarchicalMultiCriterionInput { return &f.Studios }),
+		isMissingParam("image", func(f *models.PerformerFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Performer, error) {
+		return r.Performer.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.PerformerFilterType, findFilter *models.FindFilterType) ([]*models.Performer, int, error) {
+		return r.Performer.Query(ctx, filter, findFilter)
+	},
+	toOutput: performerFromModel,
+}
+
+var studioResource = resource[models.Studio, Studio, models.StudioFilterType]{
+	path:   "studios",
+	schema: "Studio",
+	sort:   "name",
+	sorts:  sqlite.SortOptions("studios"),
+	params: []filterParam[models.StudioFilterType]{
+		textParam("name", func(f *models.StudioFilterType) **models.StringCriterionInput { return &f.Name }),
+		boolParam("favorite", "Only include favorite or non-favorite studios", func(f *models.StudioFilterType) **bool { return &f.Favorite }),
+		minRatingParam(func(f *models.StudioFilterType) **models.IntCriterionInput { return &f.Rating100 }),
+		hierarchicalIDsParam("tags", "Only include studios with any of the tags", func(f *models.StudioFilterType) **models.HierarchicalMultiCriterionInput { return &f.Tags }),
+		idsParam("parents", "Only include studios with any of the parent studios", func(f *models.StudioFilterType) **models.MultiCriterionInput { return &f.Parents }),
+		isMissingParam("image", func(f *models.StudioFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Studio, error) {
+		return r.Studio.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.StudioFilterType, findFilter *models.FindFilterType) ([]*models.Studio, int, error) {
+		return r.Studio.Query(ctx, filter, findFilter)
+	},
+	toOutput: studioFromModel,
+}
+
+var tagResource = resource[models.Tag, Tag, models.TagFilterType]{
+	path:   "tags",
+	schema: "Tag",
+	sort:   "name",
+	sorts:  sqlite.SortOptions("tags"),
+	params: []filterParam[models.TagFilterType]{
+		textParam("name", func(f *models.TagFilterType) **models.StringCriterionInput { return &f.Name }),
+		boolParam("favorite", "Only include favorite or non-favorite tags", func(f *models.TagFilterType) **bool { return &f.Favorite }),
+		hierarchicalIDsParam("parents", "Only include tags with any of the parent tags", func(f *models.TagFilterType) **models.HierarchicalMultiCriterionInput { return &f.Parents }),
+		isMissingParam("image", func(f *models.TagFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Tag, error) {
+		return r.Tag.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.TagFilterType, findFilter *models.FindFilterType) ([]*models.Tag, int, error) {
+		return r.Tag.Query(ctx, filter, findFilter)
+	},
+	toOutput: tagFromModel,
+}
+
+var galleryResource = resource[models.Gallery, Gallery, models.GalleryFilterType]{
+	path:   "galleries",
+	schema: "Gallery",
+	sort:   "path",
+	sorts:  sqlite.SortOptions("galleries"),
+	params: []filterParam[models.GalleryFilterType]{
+		textParam("title", func(f *models.GalleryFilterType) **models.StringCriterionInput { return &f.Title }),
+		textParam("path", func(f *models.GalleryFilterType) **models.StringCriterionInput { return &f.Path }),
+		boolParam("organized", "Only include organized or unorganized galleries", func(f *models.GalleryFilterType) **bool { return &f.Organized }),
+		minRatingParam(func(f *models.GalleryFilterType) **models.IntCriterionInput { return &f.Rating100 }),
+		hierarchicalIDsParam("studios", "Only include galleries from any of the studios", func(f *models.GalleryFilterType) **models.HierarchicalMultiCriterionInput { return &f.Studios }),
+		hierarchicalIDsParam("tags", "Only include galleries with any of the tags", func(f *models.GalleryFilterType) **models.HierarchicalMultiCriterionInput { return &f.Tags }),
+		idsParam("performers", "Only include galleries with any of the performers", func(f *models.GalleryFilterType) **models.MultiCriterionInput { return &f.Performers }),
+		isMissingParam("studio", func(f *models.GalleryFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Gallery, error) {
+		return r.Gallery.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.GalleryFilterType, findFilter *models.FindFilterType) ([]*models.Gallery, int, error) {
+		return r.Gallery.Query(ctx, filter, findFilter)
+	},
+	toOutput: galleryFromModel,
+}
+
+var imageResource = resource[models.Image, Image, models.ImageFilterType]{
+	path:   "images",
+	schema: "Image",
+	sort:   "title",
+	sorts:  sqlite.SortOptions("images"),
+	params: []filterParam[models.ImageFilterType]{
+		textParam("title", func(f *models.ImageFilterType) **models.StringCriterionInput { return &f.Title }),
+		textParam("path", func(f *models.ImageFilterType) **models.StringCriterionInput { return &f.Path }),
+		boolParam("organized", "Only include organized or unorganized images", func(f *models.ImageFilterType) **bool { return &f.Organized }),
+		minRatingParam(func(f *models.ImageFilterType) **models.IntCriterionInput { return &f.Rating100 }),
+		hierarchicalIDsParam("studios", "Only include images from any of the studios", func(f *models.ImageFilterType) **models.HierarchicalMultiCriterionInput { return &f.Studios }),
+		hierarchicalIDsParam("tags", "Only include images with any of the tags", func(f *models.ImageFilterType) **models.HierarchicalMultiCriterionInput { return &f.Tags }),
+		idsParam("performers", "Only include images with any of the performers", func(f *models.ImageFilterType) **models.MultiCriterionInput { return &f.Performers }),
+		idsParam("galleries", "Only include images in any of the galleries", func(f *models.ImageFilterType) **models.MultiCriterionInput { return &f.Galleries }),
+		isMissingParam("studio", func(f *models.ImageFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Image, error) {
+		return r.Image.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.ImageFilterType, findFilter *models.FindFilterType) ([]*models.Image, int, error) {
+		result, err := r.Image.Query(ctx, models.ImageQueryOptions{
+			QueryOptions: models.QueryOptions{
+				FindFilter: findFilter,
+				Count:      true,
+			},
+			ImageFilter: filter,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		images, err := result.Resolve(ctx)
+		return images, result.Count, err
+	},
+	toOutput: imageFromModel,
+}
+
+var groupResource = resource[models.Group, Group, models.GroupFilterType]{
+	path:   "groups",
+	schema: "Group",
+	sort:   "name",
+	sorts:  sqlite.SortOptions("groups"),
+	params: []filterParam[models.GroupFilterType]{
+		textParam("name", func(f *models.GroupFilterType) **models.StringCriterionInput { return &f.Name }),
+		minRatingParam(func(f *models.GroupFilterType) **models.IntCriterionInput { return &f.Rating100 }),
+		hierarchicalIDsParam("studios", "Only include groups from any of the studios", func(f *models.GroupFilterType) **models.HierarchicalMultiCriterionInput { return &f.Studios }),
+		hierarchicalIDsParam("tags", "Only include groups with any of the tags", func(f *models.GroupFilterType) **models.HierarchicalMultiCriterionInput { return &f.Tags }),
+		idsParam("performers", "Only include groups with scenes with any of the performers", func(f *models.GroupFilterType) **models.MultiCriterionInput { return &f.Performers }),
+		isMissingParam("front_image", func(f *models.GroupFilterType) **string { return &f.IsMissing }),
+	},
+	find: func(ctx context.Context, r models.Repository, id int) (*models.Group, error) {
+		return r.Group.Find(ctx, id)
+	},
+	query: func(ctx context.Context, r models.Repository, filter *models.GroupFilterType, findFilter *models.FindFilterType) ([]*models.Group, int, error) {
+		return r.Group.Query(ctx, filter, findFilter)
+	},
+	toOutput: groupFromModel,
+}
+
+// resources are the object types served by the API, in the order they are
+// documented.
+var resources = []resourceSpec{
+	sceneResource,
+	performerResource,
+	studioResource,
+	tagResource,
+	galleryResource,
+	imageResource,
+	groupResource,
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+const (
+	// Endpoint is the path that the API is served from.
+	Endpoint = "/api/v1"
+
+	openAPIPath = "/openapi.json"
+
+	codeQueryTimeout = "QUERY_TIMEOUT"
+)
+
+var (
+	errNotFound = errors.New("not found")
+)
+
+// errorResponse is the response body of an unsuccessful request.
+type errorResponse struct {
+	Error string `json:"error"`
+	// Code identifies errors that cannot be distinguished by status alone.
+	Code string `json:"code,omitempty"`
+}
+
+// Handler serves the API. Authentication is handled by the server's
+// middleware.
+type Handler struct {
+	Repository models.Repository
+	// BaseURL returns the base URL of the server for the request context.
+	// It is used to build image and stream URLs.
+	BaseURL func(ctx context.Context) string
+}
+
+func (h Handler) baseURL(ctx context.Context) string {
+	if h.BaseURL == nil {
+		return ""
+	}
+	return h.BaseURL(ctx)
+}
+
+func (h Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get(openAPIPath, h.openAPI)
+	for _, rs := range resources {
+		r.Mount("/"+rs.name(), rs.routes(h))
+	}
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, errNotFound)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("the API is read-only"))
+	})
+
+	return r
+}
+
+func (h Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, OpenAPI(h.baseURL(r.Context())+Endpoint))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warnf("error writing REST API response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	if status == http.StatusInternalServerError {
+		logger.Errorf("REST API error: %v", err)
+	}
+
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeQueryError writes the response for an error returned from a read
+// transaction.
+func writeQueryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		// the client has gone away
+	case errors.Is(err, models.ErrInvalidSort):
+		writeError(w, http.StatusBadRequest, parameterError("sort", err))
+	case errors.Is(err, txn.ErrTimeout):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  codeQueryTimeout,
+		})
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
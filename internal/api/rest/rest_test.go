@@ -0,0 +1,193 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testBaseURL = "http://localhost:9999"
+
+func newTestHandler(db *mocks.Database) http.Handler {
+	return Handler{
+		Repository: db.Repository(),
+		BaseURL:    func(ctx context.Context) string { return testBaseURL },
+	}.Routes()
+}
+
+func serve(h http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func mockPerformerRelationships(db *mocks.Database, id int) {
+	db.Performer.On("GetAliases", mock.Anything, id).Return([]string{"alias"}, nil)
+	db.Performer.On("GetURLs", mock.Anything, id).Return(nil, nil)
+	db.Performer.On("GetTagIDs", mock.Anything, id).Return([]int{2, 3}, nil)
+}
+
+func TestHandler_list(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	favorite := true
+	perPage := 10
+	page := 2
+	sort := "name"
+	db.Performer.On("Query", mock.Anything, &models.PerformerFilterType{FilterFavorites: &favorite}, &models.FindFilterType{
+		Page:    &page,
+		PerPage: &perPage,
+		Sort:    &sort,
+	}).Return([]*models.Performer{{ID: 1, Name: "performer"}}, 11, nil)
+	mockPerformerRelationships(db, 1)
+
+	w := serve(newTestHandler(db), "/performers?favorite=true&page=2&per_page=10")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var got listResult[Performer]
+	if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got)) {
+		assert.Equal(t, 11, got.Count)
+		assert.Equal(t, 2, got.Page)
+		assert.Equal(t, 10, got.PerPage)
+		if assert.Len(t, got.Items, 1) {
+			p := got.Items[0]
+			assert.Equal(t, "performer", p.Name)
+			assert.Equal(t, []string{"alias"}, p.Aliases)
+			assert.Equal(t, []string{}, p.URLs)
+			assert.Equal(t, []int{2, 3}, p.TagIDs)
+			assert.Contains(t, p.ImagePath, testBaseURL+"/performer/1/image")
+		}
+	}
+
+	db.AssertExpectations(t)
+}
+
+func TestHandler_get(t *testing.T) {
+	db := mocks.NewDatabase()
+	db.Performer.On("Find", mock.Anything, 1).Return(&models.Performer{ID: 1, Name: "performer"}, nil)
+	db.Performer.On("Find", mock.Anything, 2).Return(nil, nil)
+	mockPerformerRelationships(db, 1)
+
+	h := newTestHandler(db)
+
+	w := serve(h, "/performers/1")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var got Performer
+	if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got)) {
+		assert.Equal(t, 1, got.ID)
+		assert.Equal(t, "performer", got.Name)
+	}
+
+	w = serve(h, "/performers/2")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_errors(t *testing.T) {
+	h := newTestHandler(mocks.NewDatabase())
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/scenes?per_page=0", http.StatusBadRequest},
+		{"/scenes?tags=x", http.StatusBadRequest},
+		{"/scenes/x", http.StatusBadRequest},
+		{"/markers", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := serve(h, tt.target)
+			assert.Equal(t, tt.status, w.Code)
+
+			var got errorResponse
+			if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got)) {
+				assert.NotEmpty(t, got.Error)
+			}
+		})
+	}
+}
+
+func TestHandler_timeout(t *testing.T) {
+	db := mocks.NewDatabase()
+	db.Performer.On("Find", mock.Anything, 1).Return(nil, fmt.Errorf("%w after 1s: interrupted", txn.ErrTimeout))
+
+	w := serve(newTestHandler(db), "/performers/1")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var got errorResponse
+	if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got)) {
+		assert.Equal(t, codeQueryTimeout, got.Code)
+	}
+}
+
+func TestHandler_invalidSort(t *testing.T) {
+	db := mocks.NewDatabase()
+	db.Performer.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil, 0, fmt.Errorf("%w: x", models.ErrInvalidSort))
+
+	w := serve(newTestHandler(db), "/performers?sort=x")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var got errorResponse
+	if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got)) {
+		assert.Contains(t, got.Error, "sort")
+		assert.Empty(t, got.Code)
+	}
+}
+
+func TestHandler_readOnly(t *testing.T) {
+	h := newTestHandler(mocks.NewDatabase())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/scenes/1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestOpenAPI(t *testing.T) {
+	w := serve(newTestHandler(mocks.NewDatabase()), "/openapi.json")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var doc Document
+	if !assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc)) {
+		return
+	}
+
+	assert.Equal(t, testBaseURL+Endpoint, doc.Servers[0].URL)
+
+	for _, rs := range resources {
+		list := doc.Paths["/"+rs.name()]
+		if assert.NotNil(t, list, rs.name()) {
+			// find filter parameters followed by the filter parameters
+			assert.Len(t, list.Get.Parameters, 5+len(rs.filterParams()))
+		}
+		assert.NotNil(t, doc.Paths["/"+rs.name()+"/{id}"], rs.name())
+
+		if list != nil {
+			sort := list.Get.Parameters[3]
+			assert.Equal(t, "sort", sort.Name)
+			assert.Contains(t, sort.Schema.Enum, rs.defaultSort(), rs.name())
+		}
+
+		schema := doc.Components.Schemas[rs.title()]
+		if assert.NotNil(t, schema, rs.title()) {
+			assert.Equal(t, "integer", schema.Properties["id"].Type)
+		}
+	}
+
+	scene := doc.Components.Schemas["Scene"]
+	assert.Equal(t, &Schema{Type: "string", Format: "date", Nullable: true}, scene.Properties["date"])
+	assert.Equal(t, "#/components/schemas/VideoFile", scene.Properties["files"].Items.Ref)
+	assert.Equal(t, "#/components/schemas/ScenePaths", scene.Properties["paths"].Ref)
+	assert.Equal(t, "date-time", scene.Properties["created_at"].Format)
+}
@@ -0,0 +1,457 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// The following types are the JSON representations of the objects returned
+// by the API. The OpenAPI schemas are generated from them.
+
+type VideoFile struct {
+	ID         int     `json:"id"`
+	Path       string  `json:"path"`
+	Size       int64   `json:"size"`
+	Duration   float64 `json:"duration" description:"Duration in seconds"`
+	VideoCodec string  `json:"video_codec"`
+	AudioCodec string  `json:"audio_codec"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	FrameRate  float64 `json:"frame_rate"`
+	BitRate    int64   `json:"bit_rate"`
+}
+
+type File struct {
+	ID   int    `json:"id"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+type ScenePaths struct {
+	Screenshot string `json:"screenshot"`
+	Stream     string `json:"stream"`
+}
+
+type Scene struct {
+	ID           int         `json:"id"`
+	Title        string      `json:"title"`
+	Code         string      `json:"code"`
+	Details      string      `json:"details"`
+	Director     string      `json:"director"`
+	Date         *string     `json:"date" format:"date"`
+	Rating100    *int        `json:"rating100"`
+	Organized    bool        `json:"organized"`
+	StudioID     *int        `json:"studio_id"`
+	URLs         []string    `json:"urls"`
+	TagIDs       []int       `json:"tag_ids"`
+	PerformerIDs []int       `json:"performer_ids"`
+	GalleryIDs   []int       `json:"gallery_ids"`
+	GroupIDs     []int       `json:"group_ids"`
+	Files        []VideoFile `json:"files"`
+	Paths        ScenePaths  `json:"paths"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+type Performer struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Disambiguation string    `json:"disambiguation"`
+	Aliases        []string  `json:"aliases"`
+	Gender         *string   `json:"gender"`
+	Birthdate      *string   `json:"birthdate" format:"date"`
+	DeathDate      *string   `json:"death_date" format:"date"`
+	Country        string    `json:"country"`
+	Ethnicity      string    `json:"ethnicity"`
+	HairColor      string    `json:"hair_color"`
+	EyeColor       string    `json:"eye_color"`
+	HeightCm       *int      `json:"height_cm"`
+	Weight         *int      `json:"weight" description:"Weight in kilograms"`
+	Details        string    `json:"details"`
+	Favorite       bool      `json:"favorite"`
+	Rating100      *int      `json:"rating100"`
+	URLs           []string  `json:"urls"`
+	TagIDs         []int     `json:"tag_ids"`
+	ImagePath      string    `json:"image_path"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type Studio struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	ParentID  *int      `json:"parent_id"`
+	Aliases   []string  `json:"aliases"`
+	Details   string    `json:"details"`
+	Favorite  bool      `json:"favorite"`
+	Rating100 *int      `json:"rating100"`
+	TagIDs    []int     `json:"tag_ids"`
+	ImagePath string    `json:"image_path"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Tag struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Aliases     []string  `json:"aliases"`
+	Favorite    bool      `json:"favorite"`
+	ParentIDs   []int     `json:"parent_ids"`
+	ChildIDs    []int     `json:"child_ids"`
+	ImagePath   string    `json:"image_path"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GalleryPaths struct {
+	Cover   string `json:"cover"`
+	Preview string `json:"preview"`
+}
+
+type Gallery struct {
+	ID           int          `json:"id"`
+	Title        string       `json:"title"`
+	Code         string       `json:"code"`
+	Date         *string      `json:"date" format:"date"`
+	Details      string       `json:"details"`
+	Photographer string       `json:"photographer"`
+	Rating100    *int         `json:"rating100"`
+	Organized    bool         `json:"organized"`
+	StudioID     *int         `json:"studio_id"`
+	Path         string       `json:"path" description:"Path of the zip file or folder. Empty for galleries created in stash."`
+	URLs         []string     `json:"urls"`
+	TagIDs       []int        `json:"tag_ids"`
+	PerformerIDs []int        `json:"performer_ids"`
+	SceneIDs     []int        `json:"scene_ids"`
+	Paths        GalleryPaths `json:"paths"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type ImagePaths struct {
+	Image     string `json:"image"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+type Image struct {
+	ID           int        `json:"id"`
+	Title        string     `json:"title"`
+	Code         string     `json:"code"`
+	Date         *string    `json:"date" format:"date"`
+	Details      string     `json:"details"`
+	Photographer string     `json:"photographer"`
+	Rating100    *int       `json:"rating100"`
+	Organized    bool       `json:"organized"`
+	StudioID     *int       `json:"studio_id"`
+	URLs         []string   `json:"urls"`
+	TagIDs       []int      `json:"tag_ids"`
+	PerformerIDs []int      `json:"performer_ids"`
+	GalleryIDs   []int      `json:"gallery_ids"`
+	Files        []File     `json:"files"`
+	Paths        ImagePaths `json:"paths"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type Group struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Aliases        string    `json:"aliases"`
+	Duration       *int      `json:"duration" description:"Duration in seconds"`
+	Date           *string   `json:"date" format:"date"`
+	Rating100      *int      `json:"rating100"`
+	StudioID       *int      `json:"studio_id"`
+	Director       string    `json:"director"`
+	Synopsis       string    `json:"synopsis"`
+	URLs           []string  `json:"urls"`
+	TagIDs         []int     `json:"tag_ids"`
+	FrontImagePath string    `json:"front_image_path"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func dateString(d *models.Date) *string {
+	if d == nil {
+		return nil
+	}
+	s := d.String()
+	return &s
+}
+
+// nonNil returns an empty slice in place of nil, so that lists are encoded
+// as empty arrays.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func sceneFromModel(ctx context.Context, r models.Repository, baseURL string, s *models.Scene) (*Scene, error) {
+	if err := s.LoadRelationships(ctx, r.Scene); err != nil {
+		return nil, err
+	}
+
+	files := []VideoFile{}
+	for _, f := range s.Files.List() {
+		files = append(files, VideoFile{
+			ID:         int(f.ID),
+			Path:       f.Path,
+			Size:       f.Size,
+			Duration:   f.Duration,
+			VideoCodec: f.VideoCodec,
+			AudioCodec: f.AudioCodec,
+			Width:      f.Width,
+			Height:     f.Height,
+			FrameRate:  f.FrameRate,
+			BitRate:    f.BitRate,
+		})
+	}
+
+	groupIDs := []int{}
+	for _, g := range s.Groups.List() {
+		groupIDs = append(groupIDs, g.GroupID)
+	}
+
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, s)
+
+	return &Scene{
+		ID:           s.ID,
+		Title:        s.Title,
+		Code:         s.Code,
+		Details:      s.Details,
+		Director:     s.Director,
+		Date:         dateString(s.Date),
+		Rating100:    s.Rating,
+		Organized:    s.Organized,
+		StudioID:     s.StudioID,
+		URLs:         nonNil(s.URLs.List()),
+		TagIDs:       nonNil(s.TagIDs.List()),
+		PerformerIDs: nonNil(s.PerformerIDs.List()),
+		GalleryIDs:   nonNil(s.GalleryIDs.List()),
+		GroupIDs:     groupIDs,
+		Files:        files,
+		Paths: ScenePaths{
+			Screenshot: builder.GetScreenshotURL(),
+			Stream:     builder.GetStreamURL("").String(),
+		},
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}, nil
+}
+
+func performerFromModel(ctx context.Context, r models.Repository, baseURL string, p *models.Performer) (*Performer, error) {
+	if err := p.LoadAliases(ctx, r.Performer); err != nil {
+		return nil, err
+	}
+	if err := p.LoadURLs(ctx, r.Performer); err != nil {
+		return nil, err
+	}
+	if err := p.LoadTagIDs(ctx, r.Performer); err != nil {
+		return nil, err
+	}
+
+	var gender *string
+	if p.Gender != nil {
+		g := p.Gender.String()
+		gender = &g
+	}
+
+	return &Performer{
+		ID:             p.ID,
+		Name:           p.Name,
+		Disambiguation: p.Disambiguation,
+		Aliases:        nonNil(p.Aliases.List()),
+		Gender:         gender,
+		Birthdate:      dateString(p.Birthdate),
+		DeathDate:      dateString(p.DeathDate),
+		Country:        p.Country,
+		Ethnicity:      p.Ethnicity,
+		HairColor:      p.HairColor,
+		EyeColor:       p.EyeColor,
+		HeightCm:       p.Height,
+		Weight:         p.Weight,
+		Details:        p.Details,
+		Favorite:       p.Favorite,
+		Rating100:      p.Rating,
+		URLs:           nonNil(p.URLs.List()),
+		TagIDs:         nonNil(p.TagIDs.List()),
+		// the image route serves a default image if the performer has none
+		ImagePath: urlbuilders.NewPerformerURLBuilder(baseURL, p).GetPerformerImageURL(true),
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}, nil
+}
+
+func studioFromModel(ctx context.Context, r models.Repository, baseURL string, s *models.Studio) (*Studio, error) {
+	if err := s.LoadAliases(ctx, r.Studio); err != nil {
+		return nil, err
+	}
+	if err := s.LoadTagIDs(ctx, r.Studio); err != nil {
+		return nil, err
+	}
+
+	return &Studio{
+		ID:        s.ID,
+		Name:      s.Name,
+		URL:       s.URL,
+		ParentID:  s.ParentID,
+		Aliases:   nonNil(s.Aliases.List()),
+		Details:   s.Details,
+		Favorite:  s.Favorite,
+		Rating100: s.Rating,
+		TagIDs:    nonNil(s.TagIDs.List()),
+		ImagePath: urlbuilders.NewStudioURLBuilder(baseURL, s).GetStudioImageURL(true),
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}, nil
+}
+
+func tagFromModel(ctx context.Context, r models.Repository, baseURL string, t *models.Tag) (*Tag, error) {
+	if err := t.LoadAliases(ctx, r.Tag); err != nil {
+		return nil, err
+	}
+	if err := t.LoadParentIDs(ctx, r.Tag); err != nil {
+		return nil, err
+	}
+	if err := t.LoadChildIDs(ctx, r.Tag); err != nil {
+		return nil, err
+	}
+
+	return &Tag{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Aliases:     nonNil(t.Aliases.List()),
+		Favorite:    t.Favorite,
+		ParentIDs:   nonNil(t.ParentIDs.List()),
+		ChildIDs:    nonNil(t.ChildIDs.List()),
+		ImagePath:   urlbuilders.NewTagURLBuilder(baseURL, t).GetTagImageURL(true),
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}, nil
+}
+
+func galleryFromModel(ctx context.Context, r models.Repository, baseURL string, g *models.Gallery) (*Gallery, error) {
+	if err := g.LoadURLs(ctx, r.Gallery); err != nil {
+		return nil, err
+	}
+	if err := g.LoadTagIDs(ctx, r.Gallery); err != nil {
+		return nil, err
+	}
+	if err := g.LoadPerformerIDs(ctx, r.Gallery); err != nil {
+		return nil, err
+	}
+	if err := g.LoadSceneIDs(ctx, r.Gallery); err != nil {
+		return nil, err
+	}
+
+	builder := urlbuilders.NewGalleryURLBuilder(baseURL, g)
+
+	return &Gallery{
+		ID:           g.ID,
+		Title:        g.Title,
+		Code:         g.Code,
+		Date:         dateString(g.Date),
+		Details:      g.Details,
+		Photographer: g.Photographer,
+		Rating100:    g.Rating,
+		Organized:    g.Organized,
+		StudioID:     g.StudioID,
+		Path:         g.Path,
+		URLs:         nonNil(g.URLs.List()),
+		TagIDs:       nonNil(g.TagIDs.List()),
+		PerformerIDs: nonNil(g.PerformerIDs.List()),
+		SceneIDs:     nonNil(g.SceneIDs.List()),
+		Paths: GalleryPaths{
+			Cover:   builder.GetCoverURL(),
+			Preview: builder.GetPreviewURL(),
+		},
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}, nil
+}
+
+func imageFromModel(ctx context.Context, r models.Repository, baseURL string, i *models.Image) (*Image, error) {
+	if err := i.LoadURLs(ctx, r.Image); err != nil {
+		return nil, err
+	}
+	if err := i.LoadTagIDs(ctx, r.Image); err != nil {
+		return nil, err
+	}
+	if err := i.LoadPerformerIDs(ctx, r.Image); err != nil {
+		return nil, err
+	}
+	if err := i.LoadGalleryIDs(ctx, r.Image); err != nil {
+		return nil, err
+	}
+	if err := i.LoadFiles(ctx, r.Image); err != nil {
+		return nil, err
+	}
+
+	files := []File{}
+	for _, f := range i.Files.List() {
+		base := f.Base()
+		files = append(files, File{
+			ID:   int(base.ID),
+			Path: base.Path,
+			Size: base.Size,
+		})
+	}
+
+	builder := urlbuilders.NewImageURLBuilder(baseURL, i)
+
+	return &Image{
+		ID:           i.ID,
+		Title:        i.Title,
+		Code:         i.Code,
+		Date:         dateString(i.Date),
+		Details:      i.Details,
+		Photographer: i.Photographer,
+		Rating100:    i.Rating,
+		Organized:    i.Organized,
+		StudioID:     i.StudioID,
+		URLs:         nonNil(i.URLs.List()),
+		TagIDs:       nonNil(i.TagIDs.List()),
+		PerformerIDs: nonNil(i.PerformerIDs.List()),
+		GalleryIDs:   nonNil(i.GalleryIDs.List()),
+		Files:        files,
+		Paths: ImagePaths{
+			Image:     builder.GetImageURL(),
+			Thumbnail: builder.GetThumbnailURL(),
+		},
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}, nil
+}
+
+func groupFromModel(ctx context.Context, r models.Repository, baseURL string, g *models.Group) (*Group, error) {
+	if err := g.LoadURLs(ctx, r.Group); err != nil {
+		return nil, err
+	}
+	if err := g.LoadTagIDs(ctx, r.Group); err != nil {
+		return nil, err
+	}
+
+	return &Group{
+		ID:             g.ID,
+		Name:           g.Name,
+		Aliases:        g.Aliases,
+		Duration:       g.Duration,
+		Date:           dateString(g.Date),
+		Rating100:      g.Rating,
+		StudioID:       g.StudioID,
+		Director:       g.Director,
+		Synopsis:       g.Synopsis,
+		URLs:           nonNil(g.URLs.List()),
+		TagIDs:         nonNil(g.TagIDs.List()),
+		FrontImagePath: urlbuilders.NewGroupURLBuilder(baseURL, g).GetGroupFrontImageURL(true),
+		CreatedAt:      g.CreatedAt,
+		UpdatedAt:      g.UpdatedAt,
+	}, nil
+}
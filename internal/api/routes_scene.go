@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -164,6 +165,7 @@ func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, st
 		VideoFile:  f,
 		Resolution: resolution,
 		StartTime:  ss,
+		MaxBitrate: manager.GetStreamingMaxBitrate(r.Context()),
 	}
 
 	logger.Debugf("[transcode] streaming scene %d as %s", scene.ID, streamType.MimeType)
@@ -243,6 +245,7 @@ func (rs sceneRoutes) streamSegment(w http.ResponseWriter, r *http.Request, stre
 		Resolution: resolution,
 		Hash:       sceneHash,
 		Segment:    segment,
+		MaxBitrate: manager.GetStreamingMaxBitrate(r.Context()),
 	}
 
 	streamManager.ServeSegment(w, r, options)
@@ -324,6 +327,23 @@ func (rs sceneRoutes) VttChapter(w http.ResponseWriter, r *http.Request) {
 	}
 
 	vttLines := []string{"WEBVTT", ""}
+
+	// fall back to the suggested markers if there are no manual markers
+	if len(sceneMarkers) == 0 {
+		suggestions, err := manager.GetSceneSuggestedMarkers(scene, nil, config.GetInstance().GetVideoFileNamingAlgorithm())
+		if err != nil {
+			logger.Warnf("error getting suggested markers for scene %d: %v", scene.ID, err)
+		}
+
+		for i, suggestion := range suggestions {
+			vttLines = append(vttLines, strconv.Itoa(i+1))
+			time := utils.GetVTTTime(suggestion.Seconds)
+			vttLines = append(vttLines, time+" --> "+time)
+			vttLines = append(vttLines, fmt.Sprintf("Chapter %d", i+1))
+			vttLines = append(vttLines, "")
+		}
+	}
+
 	for i, marker := range sceneMarkers {
 		vttLines = append(vttLines, strconv.Itoa(i+1))
 		time := utils.GetVTTTime(marker.Seconds)
@@ -30,12 +30,14 @@ import (
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/api/rest"
 	"github.com/stashapp/stash/internal/build"
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/txn"
 	"github.com/stashapp/stash/pkg/utils"
 	"github.com/stashapp/stash/ui"
 )
@@ -169,7 +171,10 @@ func Initialize() (*Server, error) {
 		hookExecutor:   pluginCache,
 	}
 
-	gqlSrv := gqlHandler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	gqlCfg := Config{Resolvers: resolver}
+	setComplexity(&gqlCfg.Complexity)
+
+	gqlSrv := gqlHandler.New(NewExecutableSchema(gqlCfg))
 	gqlSrv.SetRecoverFunc(recoverFunc)
 	gqlSrv.AddTransport(gqlTransport.Websocket{
 		Upgrader: websocket.Upgrader{
@@ -188,6 +193,8 @@ func Initialize() (*Server, error) {
 
 	gqlSrv.SetQueryCache(gqlLru.New[*ast.QueryDocument](1000))
 	gqlSrv.Use(gqlExtension.Introspection{})
+	gqlSrv.Use(&gqlExtension.ComplexityLimit{Func: complexityLimit})
+	gqlSrv.Use(DepthLimit{Func: depthLimit})
 
 	gqlSrv.SetErrorPresenter(gqlErrorHandler)
 
@@ -202,7 +209,7 @@ func Initialize() (*Server, error) {
 	gqlHandler := visitedPluginHandler(dataloaders.Middleware(http.HandlerFunc(gqlHandlerFunc)))
 	pluginCache.RegisterGQLHandler(gqlHandler)
 
-	r.HandleFunc(gqlEndpoint, gqlHandlerFunc)
+	r.With(queryTimeoutMiddleware(cfg)).HandleFunc(gqlEndpoint, gqlHandlerFunc)
 	r.HandleFunc(playgroundEndpoint, func(w http.ResponseWriter, r *http.Request) {
 		setPageSecurityHeaders(w, r, pluginCache.ListPlugins())
 		endpoint := getProxyPrefix(r) + gqlEndpoint
@@ -219,6 +226,13 @@ func Initialize() (*Server, error) {
 	r.Mount("/character", server.getCharacterRoutes())
 	r.Mount("/downloads", server.getDownloadsRoutes())
 	r.Mount("/plugin", server.getPluginRoutes())
+	r.With(queryTimeoutMiddleware(cfg)).Mount(rest.Endpoint, rest.Handler{
+		Repository: repo,
+		BaseURL: func(ctx context.Context) string {
+			baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+			return baseURL
+		},
+	}.Routes())
 
 	r.HandleFunc("/css", cssHandler(cfg))
 	r.HandleFunc("/javascript", javascriptHandler(cfg))
@@ -642,6 +656,20 @@ func BaseURLMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
+// queryTimeoutMiddleware limits the duration of the database reads of each
+// request to the configured query timeout.
+func queryTimeoutMiddleware(c *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			timeout := time.Duration(c.GetQueryTimeout()) * time.Second
+			r = r.WithContext(txn.WithReadTimeout(r.Context(), timeout))
+
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
 func getProxyPrefix(r *http.Request) string {
 	return strings.TrimRight(r.Header.Get("X-Forwarded-Prefix"), "/")
 }
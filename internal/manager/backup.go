@@ -0,0 +1,649 @@
+package manager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/sqlite"
+)
+
+// backupCheckInterval is the time between checks for whether an automatic
+// database backup is due.
+const backupCheckInterval = 10 * time.Minute
+
+const (
+	automaticBackupSuffix  = ".auto"
+	compressedBackupSuffix = ".gz"
+	blobsBackupSuffix      = ".blobs.tar"
+
+	// restoreDirSuffix is appended to the blobs path to give the directory
+	// that replaces the blobs directory when stash is next started.
+	restoreDirSuffix = ".restore"
+
+	// backupTimeFormat is the format of the time in backup file names. It
+	// matches the format used by sqlite.Database.DatabaseBackupPath.
+	backupTimeFormat = "20060102_150405"
+)
+
+// backupMutex prevents concurrent automatic backups and restores.
+var backupMutex sync.Mutex
+
+// DatabaseBackup is a backup of the database in the backup directory.
+type DatabaseBackup struct {
+	Name          string    `json:"name"`
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Size          int64     `json:"size"`
+	Compressed    bool      `json:"compressed"`
+	Automatic     bool      `json:"automatic"`
+	// Blobs is true if the blobs directory was backed up with the database.
+	Blobs bool `json:"blobs"`
+}
+
+// blobsArchiveName returns the file name of the blobs archive of the backup.
+func (b DatabaseBackup) blobsArchiveName() string {
+	ret := strings.TrimSuffix(b.Name, compressedBackupSuffix) + blobsBackupSuffix
+	if b.Compressed {
+		ret += compressedBackupSuffix
+	}
+	return ret
+}
+
+func backupNameRE(databaseName string) *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(databaseName) + `\.(\d+)\.(\d{8}_\d{6})(` +
+		regexp.QuoteMeta(automaticBackupSuffix) + `)?(` + regexp.QuoteMeta(compressedBackupSuffix) + `)?$`)
+}
+
+// listBackups returns the backups of the named database in the directory,
+// most recent first.
+func listBackups(dir string, databaseName string) ([]*DatabaseBackup, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	re := backupNameRE(databaseName)
+	var ret []*DatabaseBackup
+	for _, e := range entries {
+		m := re.FindStringSubmatch(e.Name())
+		if m == nil || e.IsDir() {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		schemaVersion, _ := strconv.Atoi(m[1])
+		createdAt, err := time.ParseInLocation(backupTimeFormat, m[2], time.Local)
+		if err != nil {
+			continue
+		}
+
+		b := &DatabaseBackup{
+			Name:          e.Name(),
+			SchemaVersion: schemaVersion,
+			CreatedAt:     createdAt,
+			Size:          info.Size(),
+			Automatic:     m[3] != "",
+			Compressed:    m[4] != "",
+		}
+
+		if exists, _ := fsutil.FileExists(filepath.Join(dir, b.blobsArchiveName())); exists {
+			b.Blobs = true
+		}
+
+		ret = append(ret, b)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].CreatedAt.After(ret[j].CreatedAt)
+	})
+
+	return ret, nil
+}
+
+// backupsToPrune returns the backups that are not retained by the retention
+// policy. The most recent keepCount backups are retained, along with the
+// latest backup of each of the most recent keepDaily days and keepWeekly
+// weeks. Backups must be sorted most recent first. No backups are pruned if
+// all of the values are zero.
+func backupsToPrune(backups []*DatabaseBackup, keepCount, keepDaily, keepWeekly int) []*DatabaseBackup {
+	if keepCount <= 0 && keepDaily <= 0 && keepWeekly <= 0 {
+		return nil
+	}
+
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+
+	var ret []*DatabaseBackup
+	for i, b := range backups {
+		keep := i < keepCount
+
+		day := b.CreatedAt.Format("2006-01-02")
+		if !days[day] && len(days) < keepDaily {
+			days[day] = true
+			keep = true
+		}
+
+		year, w := b.CreatedAt.ISOWeek()
+		week := fmt.Sprintf("%d-%d", year, w)
+		if !weeks[week] && len(weeks) < keepWeekly {
+			weeks[week] = true
+			keep = true
+		}
+
+		if !keep {
+			ret = append(ret, b)
+		}
+	}
+
+	return ret
+}
+
+func (s *Manager) backupDatabaseName() string {
+	return filepath.Base(s.Database.DatabasePath())
+}
+
+// ListBackups returns the database backups in the backup directory, most
+// recent first. PostgreSQL databases have no backups.
+func (s *Manager) ListBackups() ([]*DatabaseBackup, error) {
+	if s.Database.Type() == sqlite.PostgresBackend {
+		return nil, nil
+	}
+
+	return listBackups(s.Config.GetBackupDirectoryPathOrDefault(), s.backupDatabaseName())
+}
+
+// RunAutomaticBackup backs up the database into the backup directory, and
+// prunes the automatic backups that are not retained. Each backup is checked
+// for integrity, and is compressed and includes the blobs according to the
+// backup configuration.
+func (s *Manager) RunAutomaticBackup(ctx context.Context) error {
+	backupMutex.Lock()
+	defer backupMutex.Unlock()
+
+	cfg := s.Config
+	backupDir := cfg.GetBackupDirectoryPathOrDefault()
+	if err := fsutil.EnsureDir(backupDir); err != nil {
+		return fmt.Errorf("could not create backup directory %v: %w", backupDir, err)
+	}
+
+	backupPath := s.Database.DatabaseBackupPath(backupDir) + automaticBackupSuffix
+	if err := s.Database.Backup(backupPath); err != nil {
+		return err
+	}
+
+	if err := s.finishBackup(backupPath); err != nil {
+		// don't keep backups that are incomplete or corrupt
+		for _, p := range []string{backupPath, backupPath + compressedBackupSuffix} {
+			if removeErr := os.Remove(p); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+				logger.Warnf("error removing backup %s: %v", p, removeErr)
+			}
+		}
+		return err
+	}
+
+	return s.pruneBackups(backupDir)
+}
+
+func (s *Manager) finishBackup(backupPath string) error {
+	cfg := s.Config
+	includeBlobs := cfg.IsBackupIncludeBlobs()
+	useFilesystem := cfg.GetBlobsStorage() == config.BlobStorageTypeFilesystem
+
+	if !includeBlobs && !useFilesystem {
+		if err := sqlite.StripBlobs(backupPath); err != nil {
+			return err
+		}
+	}
+
+	if err := sqlite.VerifyBackup(backupPath); err != nil {
+		return err
+	}
+
+	compress := cfg.IsBackupCompress()
+	if compress {
+		if err := gzipFile(backupPath, backupPath+compressedBackupSuffix); err != nil {
+			return err
+		}
+
+		if err := os.Remove(backupPath); err != nil {
+			return err
+		}
+	}
+
+	if includeBlobs && useFilesystem && cfg.GetBlobsPath() != "" {
+		archivePath := backupPath + blobsBackupSuffix
+		if compress {
+			archivePath += compressedBackupSuffix
+		}
+
+		if err := tarDirectory(cfg.GetBlobsPath(), archivePath, compress); err != nil {
+			return fmt.Errorf("backing up blobs: %w", err)
+		}
+	}
+
+	logger.Infof("Database backup %s complete", filepath.Base(backupPath))
+	return nil
+}
+
+// pruneBackups deletes the automatic backups in the directory that are not
+// retained by the retention policy.
+func (s *Manager) pruneBackups(dir string) error {
+	backups, err := listBackups(dir, s.backupDatabaseName())
+	if err != nil {
+		return err
+	}
+
+	var automatic []*DatabaseBackup
+	for _, b := range backups {
+		if b.Automatic {
+			automatic = append(automatic, b)
+		}
+	}
+
+	cfg := s.Config
+	for _, b := range backupsToPrune(automatic, cfg.GetBackupKeepCount(), cfg.GetBackupKeepDaily(), cfg.GetBackupKeepWeekly()) {
+		logger.Infof("Deleting expired database backup %s", b.Name)
+		if err := os.Remove(filepath.Join(dir, b.Name)); err != nil {
+			return err
+		}
+
+		if b.Blobs {
+			if err := os.Remove(filepath.Join(dir, b.blobsArchiveName())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// backupDue returns true if an automatic backup should be run.
+func (s *Manager) backupDue() (bool, error) {
+	interval := time.Duration(s.Config.GetBackupInterval()) * time.Hour
+	if interval <= 0 || s.Database.Type() == sqlite.PostgresBackend {
+		return false, nil
+	}
+
+	// don't back up the database until it has been set up and migrated
+	if s.Config.IsNewSystem() || s.Database.Version() != s.Database.AppSchemaVersion() {
+		return false, nil
+	}
+
+	backups, err := s.ListBackups()
+	if err != nil {
+		return false, err
+	}
+
+	for _, b := range backups {
+		if b.Automatic {
+			return time.Since(b.CreatedAt) >= interval, nil
+		}
+	}
+
+	return true, nil
+}
+
+// backupDatabasePeriodically runs an automatic backup whenever one is due,
+// until the context is cancelled.
+func (s *Manager) backupDatabasePeriodically(ctx context.Context) {
+	ticker := time.NewTicker(backupCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		due, err := s.backupDue()
+		if err != nil {
+			logger.Errorf("error checking database backups: %v", err)
+		} else if due {
+			if err := s.RunAutomaticBackup(ctx); err != nil {
+				logger.Errorf("error backing up database: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RestoreBackup verifies the named backup in the backup directory and stages
+// it to replace the database when stash is next started. If the backup
+// includes the blobs directory, the blobs are also replaced. Backups without
+// the blob data are refused if the blobs are stored in the database.
+func (s *Manager) RestoreBackup(name string) error {
+	if s.Database.Type() == sqlite.PostgresBackend {
+		return sqlite.ErrNotSupportedByPostgres
+	}
+
+	backupMutex.Lock()
+	defer backupMutex.Unlock()
+
+	backupDir := s.Config.GetBackupDirectoryPathOrDefault()
+	backups, err := listBackups(backupDir, s.backupDatabaseName())
+	if err != nil {
+		return err
+	}
+
+	var backup *DatabaseBackup
+	for _, b := range backups {
+		if b.Name == name {
+			backup = b
+		}
+	}
+
+	if backup == nil {
+		return fmt.Errorf("backup %q not found", name)
+	}
+
+	if backup.SchemaVersion > int(s.Database.AppSchemaVersion()) {
+		return fmt.Errorf("backup %q has schema version %d, which is newer than the supported version %d", name, backup.SchemaVersion, s.Database.AppSchemaVersion())
+	}
+
+	restorePath := s.Database.RestorePath()
+	tmpPath := restorePath + ".tmp"
+	if err := copyBackup(filepath.Join(backupDir, name), tmpPath, backup.Compressed); err != nil {
+		return err
+	}
+
+	if err := sqlite.VerifyBackup(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// blobs are removed from backups that don't include them. These are
+	// lost if the blobs are stored in the database.
+	if s.Config.GetBlobsStorage() == config.BlobStorageTypeDatabase {
+		missing, err := sqlite.CountMissingBlobs(tmpPath)
+		if err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+
+		if missing > 0 {
+			os.Remove(tmpPath)
+			return fmt.Errorf("backup %q does not include the data of %d blobs, which would be lost since blobs are stored in the database", name, missing)
+		}
+	}
+
+	blobsPath := s.Config.GetBlobsPath()
+	if backup.Blobs && blobsPath != "" {
+		blobsRestorePath := blobsPath + restoreDirSuffix
+		if err := os.RemoveAll(blobsRestorePath); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+
+		if err := untarDirectory(filepath.Join(backupDir, backup.blobsArchiveName()), blobsRestorePath, backup.Compressed); err != nil {
+			os.Remove(tmpPath)
+			os.RemoveAll(blobsRestorePath)
+			return fmt.Errorf("restoring blobs: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, restorePath); err != nil {
+		return err
+	}
+
+	logger.Infof("Backup %s will be restored when stash is restarted", name)
+	return nil
+}
+
+// applyPendingBlobsRestore replaces the blobs directory with the restored
+// blobs directory, if one exists. The replaced directory is kept alongside.
+// The database is replaced when it is opened.
+func (s *Manager) applyPendingBlobsRestore() error {
+	blobsPath := s.Config.GetBlobsPath()
+	if blobsPath == "" {
+		return nil
+	}
+
+	restorePath := blobsPath + restoreDirSuffix
+	if exists, _ := fsutil.DirExists(restorePath); !exists {
+		return nil
+	}
+
+	if exists, _ := fsutil.DirExists(blobsPath); exists {
+		replacedPath := sqlite.PreRestorePath(blobsPath)
+		if err := os.Rename(blobsPath, replacedPath); err != nil {
+			return fmt.Errorf("moving blobs directory %s: %w", blobsPath, err)
+		}
+		logger.Infof("Moved blobs directory %s to %s", blobsPath, replacedPath)
+	}
+
+	if err := os.Rename(restorePath, blobsPath); err != nil {
+		return fmt.Errorf("restoring blobs directory %s: %w", blobsPath, err)
+	}
+
+	logger.Infof("Restored blobs directory %s from backup", blobsPath)
+
+	if err := sqlite.PrunePreRestore(blobsPath); err != nil {
+		logger.Warnf("error deleting replaced blobs directories: %v", err)
+	}
+
+	return nil
+}
+
+// copyBackup copies the backup file to dest, decompressing it if needed.
+func copyBackup(src string, dest string, compressed bool) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var r io.Reader = in
+	if compressed {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", src, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return fmt.Errorf("copying %s: %w", src, err)
+	}
+
+	return out.Close()
+}
+
+func gzipFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(out)
+	_, err = io.Copy(gz, in)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(dest)
+		return fmt.Errorf("compressing %s: %w", src, err)
+	}
+
+	return nil
+}
+
+// tarDirectory writes the files in dir to a tar archive at dest, optionally
+// compressed with gzip.
+func tarDirectory(dir string, dest string, compress bool) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = out
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(out)
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	return nil
+}
+
+// untarDirectory extracts the tar archive at src into dir.
+func untarDirectory(src string, dir string, compressed bool) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var r io.Reader = in
+	if compressed {
+		gz, err := gzip.NewReader(in)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	if err := fsutil.EnsureDir(dir); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dest := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(dest, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := fsutil.EnsureDir(dest); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := fsutil.EnsureDir(filepath.Dir(dest)); err != nil {
+				return err
+			}
+
+			f, err := os.Create(dest)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func makeTestBackups(times ...time.Time) []*DatabaseBackup {
+	var ret []*DatabaseBackup
+	for _, t := range times {
+		ret = append(ret, &DatabaseBackup{
+			Name:      "stash-go.sqlite.80." + t.Format(backupTimeFormat) + automaticBackupSuffix,
+			CreatedAt: t,
+			Automatic: true,
+		})
+	}
+	return ret
+}
+
+func Test_backupsToPrune(t *testing.T) {
+	day := func(d, h int) time.Time {
+		// 2024-01-01 is a Monday
+		return time.Date(2024, 1, d, h, 0, 0, 0, time.Local)
+	}
+
+	// most recent first
+	backups := makeTestBackups(
+		day(15, 12),
+		day(15, 6),
+		day(14, 12),
+		day(14, 6),
+		day(10, 12),
+		day(8, 12),
+		day(3, 12),
+		day(1, 12),
+	)
+
+	tests := []struct {
+		name       string
+		keepCount  int
+		keepDaily  int
+		keepWeekly int
+		want       []int
+	}{
+		{"keep all", 0, 0, 0, nil},
+		{"count", 3, 0, 0, []int{3, 4, 5, 6, 7}},
+		{"count exceeds", 10, 0, 0, nil},
+		{"daily", 0, 2, 0, []int{1, 3, 4, 5, 6, 7}},
+		{"weekly", 0, 0, 2, []int{1, 3, 4, 5, 6, 7}},
+		{"weekly all weeks", 0, 0, 5, []int{1, 3, 4, 5, 7}},
+		{"combined", 1, 3, 3, []int{1, 3, 5, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := backupsToPrune(backups, tt.keepCount, tt.keepDaily, tt.keepWeekly)
+
+			var gotIndexes []int
+			for _, g := range got {
+				for i, b := range backups {
+					if b == g {
+						gotIndexes = append(gotIndexes, i)
+					}
+				}
+			}
+
+			if len(gotIndexes) != len(tt.want) {
+				t.Fatalf("backupsToPrune() = %v, want %v", gotIndexes, tt.want)
+			}
+			for i := range gotIndexes {
+				if gotIndexes[i] != tt.want[i] {
+					t.Fatalf("backupsToPrune() = %v, want %v", gotIndexes, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_listBackups(t *testing.T) {
+	dir := t.TempDir()
+
+	files := []string{
+		"stash-go.sqlite.79.20240101_120000",
+		"stash-go.sqlite.80.20240102_120000.auto",
+		"stash-go.sqlite.80.20240103_120000.auto.gz",
+		"stash-go.sqlite.80.20240103_120000.auto.blobs.tar.gz",
+		"other.sqlite.80.20240104_120000.auto",
+		"stash-go.sqlite.80.20240105_120000.auto.restore",
+		"stash-go.sqlite",
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := listBackups(dir, "stash-go.sqlite")
+	if err != nil {
+		t.Fatalf("listBackups() error = %v", err)
+	}
+
+	want := []DatabaseBackup{
+		{
+			Name:          "stash-go.sqlite.80.20240103_120000.auto.gz",
+			SchemaVersion: 80,
+			CreatedAt:     time.Date(2024, 1, 3, 12, 0, 0, 0, time.Local),
+			Size:          4,
+			Compressed:    true,
+			Automatic:     true,
+			Blobs:         true,
+		},
+		{
+			Name:          "stash-go.sqlite.80.20240102_120000.auto",
+			SchemaVersion: 80,
+			CreatedAt:     time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local),
+			Size:          4,
+			Automatic:     true,
+		},
+		{
+			Name:          "stash-go.sqlite.79.20240101_120000",
+			SchemaVersion: 79,
+			CreatedAt:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local),
+			Size:          4,
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("listBackups() returned %d backups, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		g := *got[i]
+		if !g.CreatedAt.Equal(want[i].CreatedAt) {
+			t.Errorf("listBackups()[%d].CreatedAt = %v, want %v", i, g.CreatedAt, want[i].CreatedAt)
+		}
+		g.CreatedAt = want[i].CreatedAt
+		if g != want[i] {
+			t.Errorf("listBackups()[%d] = %+v, want %+v", i, g, want[i])
+		}
+	}
+}
+
+func Test_listBackups_missingDir(t *testing.T) {
+	got, err := listBackups(filepath.Join(t.TempDir(), "missing"), "stash-go.sqlite")
+	if err != nil || got != nil {
+		t.Errorf("listBackups() = %v, %v; want nil, nil", got, err)
+	}
+}
@@ -0,0 +1,237 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	// changeLogPruneInterval is the time between removals of expired change
+	// log entries.
+	changeLogPruneInterval = 24 * time.Hour
+
+	DefaultChangesLimit = 1000
+	MaxChangesLimit     = 10000
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ChangedEntity is an object that was changed since the cursor.
+type ChangedEntity struct {
+	Type EntityType `json:"type"`
+	ID   int        `json:"id"`
+	// Sequence is the sequence number of the most recent change to the
+	// object.
+	Sequence int `json:"sequence"`
+}
+
+// ChangesSinceResult is a page of the objects changed since a cursor.
+type ChangesSinceResult struct {
+	// Cursor is passed to the next call to get the subsequent changes.
+	Cursor string `json:"cursor"`
+	// CursorExpired is true if the changes since the cursor are no longer
+	// available. Clients must fetch all objects again, then continue from
+	// Cursor.
+	CursorExpired bool `json:"cursor_expired"`
+	// HasMore is true if there are more changes after Cursor.
+	HasMore bool `json:"has_more"`
+	// Upserts are the objects that were created or updated.
+	Upserts []*ChangedEntity `json:"upserts"`
+	// Tombstones are the objects that were deleted.
+	Tombstones []*ChangedEntity `json:"tombstones"`
+}
+
+// changeLogCursor is a position in a change log. Its string form is the
+// log ID and the sequence number, separated by a period.
+type changeLogCursor struct {
+	logID    string
+	sequence int
+}
+
+func (c changeLogCursor) String() string {
+	return c.logID + "." + strconv.Itoa(c.sequence)
+}
+
+func parseChangeLogCursor(s string) (*changeLogCursor, error) {
+	i := strings.LastIndex(s, ".")
+	if i == -1 {
+		return nil, ErrInvalidCursor
+	}
+
+	sequence, err := strconv.Atoi(s[i+1:])
+	if err != nil || sequence < 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &changeLogCursor{logID: s[:i], sequence: sequence}, nil
+}
+
+// expired returns true if the changes following the cursor are not all in
+// the change log, or if the cursor is from a different change log.
+func (c changeLogCursor) expired(state *models.ChangeLogState) bool {
+	return c.logID != state.LogID || c.sequence < state.PrunedSequence || c.sequence > state.LatestSequence
+}
+
+// collapseChanges returns the objects changed by the entries, according to
+// the last entry of each object.
+func collapseChanges(entries []*models.ChangeLogEntry) (upserts []*ChangedEntity, tombstones []*ChangedEntity) {
+	type key struct {
+		entityType EntityType
+		id         int
+	}
+
+	var order []key
+	last := make(map[key]*models.ChangeLogEntry)
+	for _, e := range entries {
+		k := key{EntityType(e.EntityType), e.EntityID}
+		if !k.entityType.IsValid() {
+			continue
+		}
+
+		if _, found := last[k]; !found {
+			order = append(order, k)
+		}
+		last[k] = e
+	}
+
+	upserts = []*ChangedEntity{}
+	tombstones = []*ChangedEntity{}
+	for _, k := range order {
+		e := last[k]
+		c := &ChangedEntity{
+			Type:     k.entityType,
+			ID:       k.id,
+			Sequence: e.Sequence,
+		}
+
+		if e.Operation == models.ChangeLogOperationDelete {
+			tombstones = append(tombstones, c)
+		} else {
+			upserts = append(upserts, c)
+		}
+	}
+
+	return upserts, tombstones
+}
+
+// changesSince returns up to limit change log entries following cursor. If
+// cursor is nil, no changes are returned, along with a cursor at the end of
+// the change log.
+func changesSince(ctx context.Context, r models.ChangeLogReader, cursor *string, limit int) (*ChangesSinceResult, error) {
+	var from *changeLogCursor
+	if cursor != nil {
+		var err error
+		from, err = parseChangeLogCursor(*cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	state, err := r.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := changeLogCursor{logID: state.LogID, sequence: state.LatestSequence}
+	ret := &ChangesSinceResult{
+		Cursor:     latest.String(),
+		Upserts:    []*ChangedEntity{},
+		Tombstones: []*ChangedEntity{},
+	}
+
+	if from == nil {
+		return ret, nil
+	}
+
+	if from.expired(state) {
+		ret.CursorExpired = true
+		return ret, nil
+	}
+
+	// get an extra entry to determine if there are more
+	entries, err := r.FindSince(ctx, from.sequence, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+		ret.HasMore = true
+	}
+
+	next := *from
+	if len(entries) > 0 {
+		next.sequence = entries[len(entries)-1].Sequence
+	}
+	ret.Cursor = next.String()
+	ret.Upserts, ret.Tombstones = collapseChanges(entries)
+
+	return ret, nil
+}
+
+// ChangesSince returns the objects changed since cursor. If cursor is nil,
+// it returns a cursor for the current end of the change log.
+func (s *Manager) ChangesSince(ctx context.Context, cursor *string, limit int) (*ChangesSinceResult, error) {
+	if limit < 1 || limit > MaxChangesLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxChangesLimit)
+	}
+
+	var ret *ChangesSinceResult
+	if err := s.Repository.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = changesSince(ctx, s.Repository.ChangeLog, cursor, limit)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// PruneChangeLog removes the change log entries older than the retention
+// period.
+func (s *Manager) PruneChangeLog(ctx context.Context) error {
+	days := s.Config.GetChangeLogRetentionDays()
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	var n int
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		var err error
+		n, err = s.Repository.ChangeLog.DestroyBefore(ctx, cutoff)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if n > 0 {
+		logger.Debugf("Pruned %d change log entries", n)
+	}
+
+	return nil
+}
+
+// pruneChangeLogPeriodically prunes the change log now and then daily,
+// until the context is cancelled.
+func (s *Manager) pruneChangeLogPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(changeLogPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PruneChangeLog(ctx); err != nil {
+			logger.Errorf("error pruning change log: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testChangeLog struct {
+	state   models.ChangeLogState
+	entries []*models.ChangeLogEntry
+}
+
+func (l *testChangeLog) FindSince(ctx context.Context, sequence int, limit int) ([]*models.ChangeLogEntry, error) {
+	var ret []*models.ChangeLogEntry
+	for _, e := range l.entries {
+		if e.Sequence > sequence && len(ret) < limit {
+			ret = append(ret, e)
+		}
+	}
+	return ret, nil
+}
+
+func (l *testChangeLog) State(ctx context.Context) (*models.ChangeLogState, error) {
+	ret := l.state
+	return &ret, nil
+}
+
+func changeLogEntry(sequence int, entityType EntityType, id int, op models.ChangeLogOperation) *models.ChangeLogEntry {
+	return &models.ChangeLogEntry{
+		Sequence:   sequence,
+		EntityType: entityType.String(),
+		EntityID:   id,
+		Operation:  op,
+	}
+}
+
+func TestParseChangeLogCursor(t *testing.T) {
+	tests := []struct {
+		cursor  string
+		want    *changeLogCursor
+		wantErr bool
+	}{
+		{"abc.10", &changeLogCursor{logID: "abc", sequence: 10}, false},
+		{"abc.0", &changeLogCursor{logID: "abc", sequence: 0}, false},
+		{"abc", nil, true},
+		{"abc.", nil, true},
+		{"abc.x", nil, true},
+		{"abc.-1", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cursor, func(t *testing.T) {
+			got, err := parseChangeLogCursor(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseChangeLogCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+			if got != nil {
+				assert.Equal(t, tt.cursor, got.String())
+			}
+		})
+	}
+}
+
+func TestChangeLogCursor_expired(t *testing.T) {
+	state := &models.ChangeLogState{LogID: "abc", PrunedSequence: 5, LatestSequence: 10}
+
+	tests := []struct {
+		name   string
+		cursor changeLogCursor
+		want   bool
+	}{
+		{"pruned", changeLogCursor{"abc", 5}, false},
+		{"latest", changeLogCursor{"abc", 10}, false},
+		{"before pruned", changeLogCursor{"abc", 4}, true},
+		{"after latest", changeLogCursor{"abc", 11}, true},
+		{"other log", changeLogCursor{"def", 7}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cursor.expired(state))
+		})
+	}
+}
+
+func TestCollapseChanges(t *testing.T) {
+	upserts, tombstones := collapseChanges([]*models.ChangeLogEntry{
+		changeLogEntry(1, EntityTypeScene, 1, models.ChangeLogOperationUpsert),
+		changeLogEntry(2, EntityTypeTag, 1, models.ChangeLogOperationUpsert),
+		changeLogEntry(3, EntityTypeScene, 1, models.ChangeLogOperationUpsert),
+		changeLogEntry(4, EntityTypeTag, 1, models.ChangeLogOperationDelete),
+		changeLogEntry(5, EntityTypeScene, 2, models.ChangeLogOperationDelete),
+		changeLogEntry(6, EntityTypeScene, 2, models.ChangeLogOperationUpsert),
+		{Sequence: 7, EntityType: "INVALID", EntityID: 1, Operation: models.ChangeLogOperationUpsert},
+	})
+
+	assert.Equal(t, []*ChangedEntity{
+		{Type: EntityTypeScene, ID: 1, Sequence: 3},
+		{Type: EntityTypeScene, ID: 2, Sequence: 6},
+	}, upserts)
+	assert.Equal(t, []*ChangedEntity{
+		{Type: EntityTypeTag, ID: 1, Sequence: 4},
+	}, tombstones)
+}
+
+func TestChangesSince(t *testing.T) {
+	ctx := context.Background()
+	l := &testChangeLog{
+		state: models.ChangeLogState{LogID: "abc", PrunedSequence: 2, LatestSequence: 6},
+		entries: []*models.ChangeLogEntry{
+			changeLogEntry(3, EntityTypeScene, 1, models.ChangeLogOperationUpsert),
+			changeLogEntry(4, EntityTypePerformer, 2, models.ChangeLogOperationUpsert),
+			changeLogEntry(5, EntityTypeScene, 1, models.ChangeLogOperationUpsert),
+			changeLogEntry(6, EntityTypeStudio, 3, models.ChangeLogOperationDelete),
+		},
+	}
+
+	strPtr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		cursor  *string
+		limit   int
+		want    *ChangesSinceResult
+		wantErr bool
+	}{
+		{
+			"no cursor",
+			nil,
+			10,
+			&ChangesSinceResult{Cursor: "abc.6", Upserts: []*ChangedEntity{}, Tombstones: []*ChangedEntity{}},
+			false,
+		},
+		{
+			"all",
+			strPtr("abc.2"),
+			10,
+			&ChangesSinceResult{
+				Cursor: "abc.6",
+				Upserts: []*ChangedEntity{
+					{Type: EntityTypeScene, ID: 1, Sequence: 5},
+					{Type: EntityTypePerformer, ID: 2, Sequence: 4},
+				},
+				Tombstones: []*ChangedEntity{
+					{Type: EntityTypeStudio, ID: 3, Sequence: 6},
+				},
+			},
+			false,
+		},
+		{
+			"limited",
+			strPtr("abc.2"),
+			2,
+			&ChangesSinceResult{
+				Cursor:  "abc.4",
+				HasMore: true,
+				Upserts: []*ChangedEntity{
+					{Type: EntityTypeScene, ID: 1, Sequence: 3},
+					{Type: EntityTypePerformer, ID: 2, Sequence: 4},
+				},
+				Tombstones: []*ChangedEntity{},
+			},
+			false,
+		},
+		{
+			"up to date",
+			strPtr("abc.6"),
+			10,
+			&ChangesSinceResult{Cursor: "abc.6", Upserts: []*ChangedEntity{}, Tombstones: []*ChangedEntity{}},
+			false,
+		},
+		{
+			"expired",
+			strPtr("abc.1"),
+			10,
+			&ChangesSinceResult{Cursor: "abc.6", CursorExpired: true, Upserts: []*ChangedEntity{}, Tombstones: []*ChangedEntity{}},
+			false,
+		},
+		{
+			"invalid",
+			strPtr("abc"),
+			10,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changesSince(ctx, l, tt.cursor, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("changesSince() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -28,6 +29,7 @@ import (
 	"github.com/stashapp/stash/pkg/models/paths"
 	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/webhook"
 )
 
 const (
@@ -51,6 +53,10 @@ const (
 	DefaultMaxSessionAge = 60 * 60 * 1 // 1 hours
 
 	Database = "database"
+	// DatabasePassword is the password of the PostgreSQL database. It is
+	// stored separately from the database URL so that it is not returned
+	// with the database path.
+	DatabasePassword = "database_password"
 
 	Exclude      = "exclude"
 	ImageExclude = "image_exclude"
@@ -60,6 +66,27 @@ const (
 	GalleryExtensions          = "gallery_extensions"
 	CreateGalleriesFromFolders = "create_galleries_from_folders"
 
+	// WatchLibrary is the config key used to determine if the stash paths
+	// should be watched for changes, which are then scanned.
+	WatchLibrary = "watch_library"
+
+	// WatchRescanInterval is the config key for the number of minutes between
+	// rescans of the stash paths while watching. This is for filesystems that
+	// don't report changes, such as network shares. Zero disables the rescan.
+	WatchRescanInterval = "watch_rescan_interval"
+
+	// ReadNfo is the config key used to determine if NFO files next to video
+	// files are read when scanning.
+	ReadNfo = "read_nfo"
+
+	// NfoPrecedence is the config key used to determine if the values in NFO
+	// files replace the existing values of scenes.
+	NfoPrecedence = "nfo_precedence"
+
+	// WriteNfo is the config key used to determine if NFO files are written
+	// next to video files when scenes are updated.
+	WriteNfo = "write_nfo"
+
 	// CalculateMD5 is the config key used to determine if MD5 should be calculated
 	// for video files.
 	CalculateMD5 = "calculate_md5"
@@ -71,6 +98,70 @@ const (
 	MaxTranscodeSize          = "max_transcode_size"
 	MaxStreamingTranscodeSize = "max_streaming_transcode_size"
 
+	// StreamingBandwidthPolicies is the config key for the list of per-client
+	// streaming bitrate limits.
+	StreamingBandwidthPolicies = "streaming_bandwidth_policies"
+
+	// OrganiserRules is the config key for the list of rules used to
+	// organise scene files.
+	OrganiserRules = "organiser_rules"
+
+	// TrashPath is the config key for the directory that deleted files are
+	// moved to. Files are deleted permanently if unset.
+	TrashPath = "trash_path"
+
+	// TrashRetentionDays is the config key for the number of days that items
+	// are kept in the trash before being purged. Zero keeps items until the
+	// trash is emptied.
+	TrashRetentionDays        = "trash_retention_days"
+	trashRetentionDaysDefault = 30
+
+	// ChangeLogRetentionDays is the config key for the number of days that
+	// change log entries are kept for incremental sync clients.
+	ChangeLogRetentionDays        = "change_log_retention_days"
+	changeLogRetentionDaysDefault = 30
+
+	// GraphQLMaxComplexity is the config key for the maximum estimated cost
+	// of a GraphQL operation. Zero disables the limit.
+	GraphQLMaxComplexity        = "graphql_max_complexity"
+	graphQLMaxComplexityDefault = 500000
+
+	// GraphQLMaxDepth is the config key for the maximum nesting depth of the
+	// selections and arguments of a GraphQL operation. Zero disables the
+	// limit.
+	GraphQLMaxDepth        = "graphql_max_depth"
+	graphQLMaxDepthDefault = 15
+
+	// QueryTimeout is the config key for the number of seconds that a
+	// database read may take before it is interrupted. Zero disables the
+	// timeout.
+	QueryTimeout        = "query_timeout"
+	queryTimeoutDefault = 60
+
+	// BackupInterval is the config key for the number of hours between
+	// automatic database backups. Zero disables automatic backups.
+	BackupInterval = "backup_interval"
+
+	// BackupKeepCount, BackupKeepDaily and BackupKeepWeekly are the config
+	// keys for the retention of automatic backups. The most recent
+	// BackupKeepCount backups are kept, along with the latest backup of each
+	// of the last BackupKeepDaily days and BackupKeepWeekly weeks.
+	BackupKeepCount         = "backup_keep_count"
+	backupKeepCountDefault  = 3
+	BackupKeepDaily         = "backup_keep_daily"
+	backupKeepDailyDefault  = 7
+	BackupKeepWeekly        = "backup_keep_weekly"
+	backupKeepWeeklyDefault = 4
+
+	// BackupCompress is the config key for whether automatic backups are
+	// compressed with gzip.
+	BackupCompress = "backup_compress"
+
+	// BackupIncludeBlobs is the config key for whether automatic backups
+	// include the blob data, such as cover images.
+	BackupIncludeBlobs        = "backup_include_blobs"
+	backupIncludeBlobsDefault = true
+
 	// ffmpeg extra args options
 	TranscodeInputArgs      = "ffmpeg.transcode.input_args"
 	TranscodeOutputArgs     = "ffmpeg.transcode.output_args"
@@ -101,6 +192,11 @@ const (
 	PreviewExcludeEnd        = "preview_exclude_end"
 	previewExcludeEndDefault = "0"
 
+	SceneCutThreshold        = "scene_cut_threshold"
+	sceneCutThresholdDefault = 0.4
+
+	ChapterMarkerDefaultTag = "chapter_marker_default_tag"
+
 	WriteImageThumbnails        = "write_image_thumbnails"
 	writeImageThumbnailsDefault = true
 
@@ -138,6 +234,10 @@ const (
 	// stash-box options
 	StashBoxes = "stash_boxes"
 
+	// Webhooks is the config key for the list of webhooks sent hook trigger
+	// events.
+	Webhooks = "webhooks"
+
 	PythonPath = "python_path"
 
 	// plugin options
@@ -286,7 +386,7 @@ const (
 var (
 	defaultVideoExtensions   = []string{"m4v", "mp4", "mov", "wmv", "avi", "mpg", "mpeg", "rmvb", "rm", "flv", "asf", "mkv", "webm"}
 	defaultImageExtensions   = []string{"png", "jpg", "jpeg", "gif", "webp"}
-	defaultGalleryExtensions = []string{"zip", "cbz"}
+	defaultGalleryExtensions = []string{"zip", "cbz", "7z", "cb7", "rar", "cbr", "tar", "cbt", "tar.gz", "tgz"}
 	defaultMenuItems         = []string{"scenes", "images", "movies", "markers", "galleries", "performers", "studios", "tags"}
 )
 
@@ -665,6 +765,33 @@ func (i *Config) GetGeneratedPath() string {
 	return i.getString(Generated)
 }
 
+// GetTrashPath returns the directory that deleted files are moved to.
+// Returns an empty string if the trash is disabled.
+func (i *Config) GetTrashPath() string {
+	return i.getString(TrashPath)
+}
+
+// GetTrashRetentionDays returns the number of days that items are kept in
+// the trash. Returns zero if items are never purged automatically.
+func (i *Config) GetTrashRetentionDays() int {
+	ret := i.getInt(TrashRetentionDays)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
+
+// GetChangeLogRetentionDays returns the number of days that change log
+// entries are kept. The change log is always pruned, so the default is
+// returned if the value is not positive.
+func (i *Config) GetChangeLogRetentionDays() int {
+	ret := i.getInt(ChangeLogRetentionDays)
+	if ret <= 0 {
+		return changeLogRetentionDaysDefault
+	}
+	return ret
+}
+
 func (i *Config) GetBlobsPath() string {
 	return i.getString(BlobsPath)
 }
@@ -691,8 +818,33 @@ func (i *Config) GetMetadataPath() string {
 	return i.getString(Metadata)
 }
 
+// GetDatabasePath returns the path of the SQLite database file, or the URL of
+// the PostgreSQL database without its password.
 func (i *Config) GetDatabasePath() string {
-	return i.getString(Database)
+	ret, _ := utils.SplitURLPassword(i.getString(Database))
+	return ret
+}
+
+// GetDatabaseURL returns the path or URL used to open the database, including
+// the password of a PostgreSQL database.
+func (i *Config) GetDatabaseURL() string {
+	return utils.URLWithPassword(i.getString(Database), i.getString(DatabasePassword))
+}
+
+// SetDatabasePath sets the path or URL of the database. The password of a
+// PostgreSQL URL is stored in DatabasePassword. The existing password is kept
+// if the URL has no password.
+func (i *Config) SetDatabasePath(value string) {
+	value, password := utils.SplitURLPassword(value)
+	i.SetString(Database, value)
+
+	switch {
+	case password != "":
+		i.SetString(DatabasePassword, password)
+	case !strings.Contains(value, "://"):
+		// SQLite databases have no password
+		i.SetInterface(DatabasePassword, nil)
+	}
 }
 
 func (i *Config) GetBackupDirectoryPath() string {
@@ -709,6 +861,57 @@ func (i *Config) GetBackupDirectoryPathOrDefault() string {
 	return ret
 }
 
+// GetBackupInterval returns the number of hours between automatic database
+// backups. Returns zero if automatic backups are disabled.
+func (i *Config) GetBackupInterval() int {
+	ret := i.getInt(BackupInterval)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
+
+// GetBackupKeepCount returns the number of most recent automatic backups
+// that are kept.
+func (i *Config) GetBackupKeepCount() int {
+	ret := i.getInt(BackupKeepCount)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
+
+// GetBackupKeepDaily returns the number of days for which the latest
+// automatic backup of the day is kept.
+func (i *Config) GetBackupKeepDaily() int {
+	ret := i.getInt(BackupKeepDaily)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
+
+// GetBackupKeepWeekly returns the number of weeks for which the latest
+// automatic backup of the week is kept.
+func (i *Config) GetBackupKeepWeekly() int {
+	ret := i.getInt(BackupKeepWeekly)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
+
+// IsBackupCompress returns true if automatic backups should be compressed.
+func (i *Config) IsBackupCompress() bool {
+	return i.getBool(BackupCompress)
+}
+
+// IsBackupIncludeBlobs returns true if automatic backups should include the
+// blob data.
+func (i *Config) IsBackupIncludeBlobs() bool {
+	return i.getBoolDefault(BackupIncludeBlobs, backupIncludeBlobsDefault)
+}
+
 // GetFFMpegPath returns the path to the FFMpeg executable.
 // If empty, stash will attempt to resolve it from the path.
 func (i *Config) GetFFMpegPath() string {
@@ -772,6 +975,39 @@ func (i *Config) GetCreateGalleriesFromFolders() bool {
 	return i.getBool(CreateGalleriesFromFolders)
 }
 
+func (i *Config) GetWatchLibrary() bool {
+	return i.getBool(WatchLibrary)
+}
+
+func (i *Config) GetReadNfo() bool {
+	return i.getBool(ReadNfo)
+}
+
+// GetNfoPrecedence returns whether the values in NFO files replace the
+// existing values of scenes. Defaults to keeping the existing values.
+func (i *Config) GetNfoPrecedence() models.NfoPrecedence {
+	ret := models.NfoPrecedence(i.getString(NfoPrecedence))
+	if !ret.IsValid() {
+		return models.NfoPrecedenceExisting
+	}
+
+	return ret
+}
+
+func (i *Config) GetWriteNfo() bool {
+	return i.getBool(WriteNfo)
+}
+
+// GetWatchRescanInterval returns the number of minutes between rescans of
+// the stash paths while watching. Returns zero if rescanning is disabled.
+func (i *Config) GetWatchRescanInterval() int {
+	ret := i.getInt(WatchRescanInterval)
+	if ret < 0 {
+		return 0
+	}
+	return ret
+}
+
 func (i *Config) GetLanguage() string {
 	ret := i.getString(Language)
 
@@ -851,6 +1087,15 @@ func (i *Config) GetStashBoxes() []*models.StashBox {
 	return boxes
 }
 
+func (i *Config) GetWebhooks() []*webhook.Webhook {
+	var ret []*webhook.Webhook
+	if err := i.unmarshalKey(Webhooks, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
 func (i *Config) GetDefaultPluginsPath() string {
 	// default to the same directory as the config file
 	fn := filepath.Join(i.GetConfigPath(), "plugins")
@@ -942,6 +1187,22 @@ func (i *Config) GetPreviewSegmentDuration() float64 {
 	return i.getFloat64(PreviewSegmentDuration)
 }
 
+// GetSceneCutThreshold returns the minimum scene change score, between 0
+// and 1, for a frame to be detected as a scene cut.
+func (i *Config) GetSceneCutThreshold() float64 {
+	ret := i.getFloat64(SceneCutThreshold)
+	if ret <= 0 || ret >= 1 {
+		return sceneCutThresholdDefault
+	}
+	return ret
+}
+
+// GetChapterMarkerDefaultTag returns the name of the tag used for markers
+// imported from chapters whose title does not match a tag.
+func (i *Config) GetChapterMarkerDefaultTag() string {
+	return i.getString(ChapterMarkerDefaultTag)
+}
+
 // GetParallelTasks returns the number of parallel tasks that should be started
 // by scan or generate task.
 func (i *Config) GetParallelTasks() int {
@@ -1023,6 +1284,42 @@ func (i *Config) GetMaxStreamingTranscodeSize() models.StreamingResolutionEnum {
 	return models.StreamingResolutionEnum(ret)
 }
 
+func (i *Config) GetStreamingBandwidthPolicies() models.StreamingBandwidthPolicies {
+	var ret models.StreamingBandwidthPolicies
+	if err := i.unmarshalKey(StreamingBandwidthPolicies, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+func (i *Config) ValidateStreamingBandwidthPolicies(policies []*models.StreamingBandwidthPolicy) error {
+	for _, p := range policies {
+		if p.MaxBitrate <= 0 {
+			return errors.New("streaming bandwidth policy max bitrate must be greater than zero")
+		}
+
+		if p.Network != nil && !p.Network.IsValid() {
+			return fmt.Errorf("invalid streaming bandwidth policy network: %s", p.Network)
+		}
+
+		if p.Auth != nil && !p.Auth.IsValid() {
+			return fmt.Errorf("invalid streaming bandwidth policy auth: %s", p.Auth)
+		}
+	}
+
+	return nil
+}
+
+func (i *Config) GetOrganiserRules() []*OrganiserRule {
+	var ret []*OrganiserRule
+	if err := i.unmarshalKey(OrganiserRules, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
 func (i *Config) GetTranscodeInputArgs() []string {
 	return i.getStringSlice(TranscodeInputArgs)
 }
@@ -1104,6 +1401,22 @@ func stashBoxValidate(str string) bool {
 	return err == nil && u.Scheme != "" && u.Host != "" && strings.HasSuffix(u.Path, "/graphql")
 }
 
+// OrganiserRule generates the paths of scene files matching a filter.
+type OrganiserRule struct {
+	// SavedFilterID is the saved filter that the rule was created from.
+	SavedFilterID string `json:"savedFilterId"`
+	// SceneFilter is the JSON-encoded SceneFilterType of the scenes that the
+	// rule applies to. It is copied from the saved filter when the rule is
+	// saved.
+	SceneFilter string `json:"sceneFilter"`
+	Template    string `json:"template"`
+	// Destination is the folder that generated paths are relative to. If
+	// empty, paths are relative to the stash path containing the file.
+	Destination string `json:"destination"`
+	// AutoOrganise applies the rule when a scene is marked as organized.
+	AutoOrganise bool `json:"autoOrganise"`
+}
+
 type StashBoxInput struct {
 	Endpoint string `json:"endpoint"`
 	APIKey   string `json:"api_key"`
@@ -1150,6 +1463,43 @@ func (i *Config) GetMaxSessionAge() int {
 	return ret
 }
 
+// GetGraphQLMaxComplexity returns the maximum estimated cost of a GraphQL
+// operation. Zero means no limit.
+func (i *Config) GetGraphQLMaxComplexity() int {
+	return i.getNonNegativeIntOrDefault(GraphQLMaxComplexity, graphQLMaxComplexityDefault)
+}
+
+// GetGraphQLMaxDepth returns the maximum nesting depth of a GraphQL
+// operation. Zero means no limit.
+func (i *Config) GetGraphQLMaxDepth() int {
+	return i.getNonNegativeIntOrDefault(GraphQLMaxDepth, graphQLMaxDepthDefault)
+}
+
+// GetQueryTimeout returns the number of seconds that a database read may
+// take before it is interrupted. Zero means no timeout.
+func (i *Config) GetQueryTimeout() int {
+	return i.getNonNegativeIntOrDefault(QueryTimeout, queryTimeoutDefault)
+}
+
+// getNonNegativeIntOrDefault returns the value of key, or def if it is unset
+// or negative. Zero is a valid value.
+func (i *Config) getNonNegativeIntOrDefault(key string, def int) int {
+	i.RLock()
+	defer i.RUnlock()
+
+	v := i.forKey(key)
+	if !v.Exists(key) {
+		return def
+	}
+
+	ret := v.Int(key)
+	if ret < 0 {
+		return def
+	}
+
+	return ret
+}
+
 // GetCustomServedFolders gets the map of custom paths to their applicable
 // filesystem locations
 func (i *Config) GetCustomServedFolders() utils.URLMap {
@@ -1767,6 +2117,11 @@ func (i *Config) setDefaultValues() {
 	i.setDefault(Port, portDefault)
 
 	i.setDefault(ParallelTasks, parallelTasksDefault)
+	i.setDefault(TrashRetentionDays, trashRetentionDaysDefault)
+	i.setDefault(ChangeLogRetentionDays, changeLogRetentionDaysDefault)
+	i.setDefault(BackupKeepCount, backupKeepCountDefault)
+	i.setDefault(BackupKeepDaily, backupKeepDailyDefault)
+	i.setDefault(BackupKeepWeekly, backupKeepWeeklyDefault)
 	i.setDefault(SequentialScanning, SequentialScanningDefault)
 	i.setDefault(PreviewSegmentDuration, previewSegmentDurationDefault)
 	i.setDefault(PreviewSegments, previewSegmentsDefault)
@@ -48,3 +48,46 @@ func (e *BlobsStorageType) UnmarshalGQL(v interface{}) error {
 func (e BlobsStorageType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type RemoteStorageType string
+
+const (
+	// WebDAV server
+	RemoteStorageTypeWebDAV RemoteStorageType = "WEBDAV"
+	// SFTP server
+	RemoteStorageTypeSFTP RemoteStorageType = "SFTP"
+)
+
+var AllRemoteStorageType = []RemoteStorageType{
+	RemoteStorageTypeWebDAV,
+	RemoteStorageTypeSFTP,
+}
+
+func (e RemoteStorageType) IsValid() bool {
+	switch e {
+	case RemoteStorageTypeWebDAV, RemoteStorageTypeSFTP:
+		return true
+	}
+	return false
+}
+
+func (e RemoteStorageType) String() string {
+	return string(e)
+}
+
+func (e *RemoteStorageType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RemoteStorageType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RemoteStorageType", str)
+	}
+	return nil
+}
+
+func (e RemoteStorageType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
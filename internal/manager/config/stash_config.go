@@ -8,15 +8,105 @@ import (
 
 // Stash configuration details
 type StashConfigInput struct {
-	Path         string `json:"path"`
-	ExcludeVideo bool   `json:"excludeVideo"`
-	ExcludeImage bool   `json:"excludeImage"`
+	Path         string                  `json:"path"`
+	ExcludeVideo bool                    `json:"excludeVideo"`
+	ExcludeImage bool                    `json:"excludeImage"`
+	Remote       *RemoteStashConfigInput `json:"remote"`
+	// MountMarker is the name of a file in the path that must exist for the
+	// path to be considered available.
+	MountMarker string `json:"mountMarker"`
 }
 
 type StashConfig struct {
-	Path         string `json:"path"`
-	ExcludeVideo bool   `json:"excludeVideo"`
-	ExcludeImage bool   `json:"excludeImage"`
+	Path         string             `json:"path"`
+	ExcludeVideo bool               `json:"excludeVideo"`
+	ExcludeImage bool               `json:"excludeImage"`
+	Remote       *RemoteStashConfig `json:"remote"`
+	// MountMarker is the name of a file in the path that must exist for the
+	// path to be considered available.
+	MountMarker string `json:"mountMarker"`
+}
+
+// RemoteStashConfig is the configuration of a stash path that is stored on a
+// remote server rather than the local file system. Files are accessed using
+// the stash path as if they were stored locally.
+type RemoteStashConfig struct {
+	Type           RemoteStorageType `json:"type"`
+	URL            string            `json:"url"`
+	Username       string            `json:"username"`
+	Password       string            `json:"password"`
+	KeyFile        string            `json:"keyFile"`
+	HostKey        string            `json:"hostKey"`
+	MaxConnections int               `json:"maxConnections"`
+}
+
+// HasPassword returns true if a password is set. The password itself is
+// not returned by the API.
+func (c RemoteStashConfig) HasPassword() bool {
+	return c.Password != ""
+}
+
+type RemoteStashConfigInput struct {
+	Type     RemoteStorageType `json:"type"`
+	URL      string            `json:"url"`
+	Username string            `json:"username"`
+	// Password is nil to keep the existing password
+	Password       *string `json:"password"`
+	KeyFile        string  `json:"keyFile"`
+	HostKey        string  `json:"hostKey"`
+	MaxConnections int     `json:"maxConnections"`
+}
+
+// Config returns the remote configuration for the input. If Password is
+// nil, the password of existing is used if it is for the same server and
+// user.
+func (i RemoteStashConfigInput) Config(existing *RemoteStashConfig) *RemoteStashConfig {
+	ret := &RemoteStashConfig{
+		Type:           i.Type,
+		URL:            i.URL,
+		Username:       i.Username,
+		KeyFile:        i.KeyFile,
+		HostKey:        i.HostKey,
+		MaxConnections: i.MaxConnections,
+	}
+
+	switch {
+	case i.Password != nil:
+		ret.Password = *i.Password
+	case existing != nil && existing.Type == i.Type && existing.URL == i.URL && existing.Username == i.Username:
+		ret.Password = existing.Password
+	}
+
+	return ret
+}
+
+// NewStashConfigs returns the stash configurations for the inputs. Remote
+// passwords that are not set in the input are kept from the existing
+// stash with the same path.
+func NewStashConfigs(inputs []*StashConfigInput, existing StashConfigs) StashConfigs {
+	ret := make(StashConfigs, len(inputs))
+	for i, input := range inputs {
+		ret[i] = &StashConfig{
+			Path:         input.Path,
+			ExcludeVideo: input.ExcludeVideo,
+			ExcludeImage: input.ExcludeImage,
+			MountMarker:  input.MountMarker,
+		}
+
+		if input.Remote != nil {
+			var existingRemote *RemoteStashConfig
+			for _, e := range existing {
+				if e.Path == input.Path {
+					existingRemote = e.Remote
+					break
+				}
+			}
+
+			ret[i].Remote = input.Remote.Config(existingRemote)
+		}
+	}
+
+	return ret
 }
 
 type StashConfigs []*StashConfig
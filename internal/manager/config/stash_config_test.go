@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStashConfigs(t *testing.T) {
+	const (
+		path     = "/library"
+		url      = "https://nas.local/media"
+		password = "secret"
+	)
+
+	existing := StashConfigs{
+		{
+			Path: path,
+			Remote: &RemoteStashConfig{
+				Type:     RemoteStorageTypeWebDAV,
+				URL:      url,
+				Username: "user",
+				Password: password,
+			},
+		},
+	}
+
+	newPassword := "new"
+	emptyPassword := ""
+
+	tests := []struct {
+		name     string
+		input    RemoteStashConfigInput
+		inputDir string
+		want     string
+	}{
+		{"unset", RemoteStashConfigInput{Type: RemoteStorageTypeWebDAV, URL: url, Username: "user"}, path, password},
+		{"changed", RemoteStashConfigInput{Type: RemoteStorageTypeWebDAV, URL: url, Username: "user", Password: &newPassword}, path, newPassword},
+		{"cleared", RemoteStashConfigInput{Type: RemoteStorageTypeWebDAV, URL: url, Username: "user", Password: &emptyPassword}, path, ""},
+		{"different url", RemoteStashConfigInput{Type: RemoteStorageTypeWebDAV, URL: "https://other.local", Username: "user"}, path, ""},
+		{"different user", RemoteStashConfigInput{Type: RemoteStorageTypeWebDAV, URL: url, Username: "other"}, path, ""},
+		{"different path", RemoteStashConfigInput{Type: RemoteStorageTypeWebDAV, URL: url, Username: "user"}, "/other", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			got := NewStashConfigs([]*StashConfigInput{{Path: tt.inputDir, Remote: &input}}, existing)
+			if assert.Len(t, got, 1) && assert.NotNil(t, got[0].Remote) {
+				assert.Equal(t, tt.want, got[0].Remote.Password)
+				assert.Equal(t, tt.want != "", got[0].Remote.HasPassword())
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+)
+
+// entityChangeBufferSize is the number of changes buffered for each
+// subscriber. Changes are dropped for subscribers that fall further behind.
+const entityChangeBufferSize = 100
+
+// EntityChange is a change to an object, sent to entity change subscribers
+// after the change is committed.
+type EntityChange struct {
+	Type      EntityType            `json:"type"`
+	ID        int                   `json:"id"`
+	Operation EntityChangeOperation `json:"operation"`
+	// Fields are the names of the input fields that were changed. It is
+	// empty if the changed fields are not known.
+	Fields []string `json:"fields"`
+}
+
+type hookEntityChange struct {
+	entityType EntityType
+	operation  EntityChangeOperation
+}
+
+// hookEntityChanges maps the post hooks to entity changes. The deprecated
+// movie hooks are not mapped, since the group hooks are triggered as well.
+var hookEntityChanges = map[hook.TriggerEnum]hookEntityChange{
+	hook.SceneMarkerCreatePost:  {EntityTypeSceneMarker, EntityChangeOperationCreate},
+	hook.SceneMarkerUpdatePost:  {EntityTypeSceneMarker, EntityChangeOperationUpdate},
+	hook.SceneMarkerDestroyPost: {EntityTypeSceneMarker, EntityChangeOperationDestroy},
+
+	hook.SceneCreatePost:  {EntityTypeScene, EntityChangeOperationCreate},
+	hook.SceneUpdatePost:  {EntityTypeScene, EntityChangeOperationUpdate},
+	hook.SceneDestroyPost: {EntityTypeScene, EntityChangeOperationDestroy},
+
+	hook.ImageCreatePost:  {EntityTypeImage, EntityChangeOperationCreate},
+	hook.ImageUpdatePost:  {EntityTypeImage, EntityChangeOperationUpdate},
+	hook.ImageDestroyPost: {EntityTypeImage, EntityChangeOperationDestroy},
+
+	hook.GalleryCreatePost:  {EntityTypeGallery, EntityChangeOperationCreate},
+	hook.GalleryUpdatePost:  {EntityTypeGallery, EntityChangeOperationUpdate},
+	hook.GalleryDestroyPost: {EntityTypeGallery, EntityChangeOperationDestroy},
+
+	hook.GalleryChapterCreatePost:  {EntityTypeGalleryChapter, EntityChangeOperationCreate},
+	hook.GalleryChapterUpdatePost:  {EntityTypeGalleryChapter, EntityChangeOperationUpdate},
+	hook.GalleryChapterDestroyPost: {EntityTypeGalleryChapter, EntityChangeOperationDestroy},
+
+	hook.GroupCreatePost:  {EntityTypeGroup, EntityChangeOperationCreate},
+	hook.GroupUpdatePost:  {EntityTypeGroup, EntityChangeOperationUpdate},
+	hook.GroupDestroyPost: {EntityTypeGroup, EntityChangeOperationDestroy},
+
+	hook.PerformerCreatePost:  {EntityTypePerformer, EntityChangeOperationCreate},
+	hook.PerformerUpdatePost:  {EntityTypePerformer, EntityChangeOperationUpdate},
+	hook.PerformerDestroyPost: {EntityTypePerformer, EntityChangeOperationDestroy},
+
+	hook.StudioCreatePost:  {EntityTypeStudio, EntityChangeOperationCreate},
+	hook.StudioUpdatePost:  {EntityTypeStudio, EntityChangeOperationUpdate},
+	hook.StudioDestroyPost: {EntityTypeStudio, EntityChangeOperationDestroy},
+
+	hook.TagCreatePost:  {EntityTypeTag, EntityChangeOperationCreate},
+	hook.TagUpdatePost:  {EntityTypeTag, EntityChangeOperationUpdate},
+	hook.TagMergePost:   {EntityTypeTag, EntityChangeOperationMerge},
+	hook.TagDestroyPost: {EntityTypeTag, EntityChangeOperationDestroy},
+
+	hook.CharacterCreatePost:  {EntityTypeCharacter, EntityChangeOperationCreate},
+	hook.CharacterUpdatePost:  {EntityTypeCharacter, EntityChangeOperationUpdate},
+	hook.CharacterDestroyPost: {EntityTypeCharacter, EntityChangeOperationDestroy},
+}
+
+type entityChangeManager struct {
+	subscriptions []chan *EntityChange
+	mutex         sync.Mutex
+}
+
+func (m *entityChangeManager) subscribe(ctx context.Context) <-chan *EntityChange {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	c := make(chan *EntityChange, entityChangeBufferSize)
+	m.subscriptions = append(m.subscriptions, c)
+
+	go func() {
+		<-ctx.Done()
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		close(c)
+
+		for i, s := range m.subscriptions {
+			if s == c {
+				m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+				break
+			}
+		}
+	}()
+
+	return c
+}
+
+func (m *entityChangeManager) notify(change *EntityChange) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, s := range m.subscriptions {
+		// don't block the change on slow subscribers
+		select {
+		case s <- change:
+		default:
+			logger.Warnf("entity change subscriber is not keeping up - dropping %s %s change for ID %d", change.Type, change.Operation, change.ID)
+		}
+	}
+}
+
+// notifyPostHook is a plugin.PostHookListener that notifies the subscribers
+// of the change that triggered the hook.
+func (m *entityChangeManager) notifyPostHook(ctx context.Context, id int, hookType hook.TriggerEnum, inputFields []string) {
+	c, ok := hookEntityChanges[hookType]
+	if !ok {
+		return
+	}
+
+	fields := make([]string, len(inputFields))
+	copy(fields, inputFields)
+
+	m.notify(&EntityChange{
+		Type:      c.entityType,
+		ID:        id,
+		Operation: c.operation,
+		Fields:    fields,
+	})
+}
+
+// EntityChangeSubscribe returns a channel of the changes to objects. The
+// channel is closed when ctx is done.
+func (s *Manager) EntityChangeSubscribe(ctx context.Context) <-chan *EntityChange {
+	return s.entitySubs.subscribe(ctx)
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityChangeManager_notifyPostHook(t *testing.T) {
+	m := &entityChangeManager{}
+	ctx, cancel := context.WithCancel(context.Background())
+	c := m.subscribe(ctx)
+
+	fields := []string{"title", "rating100"}
+	m.notifyPostHook(ctx, 1, hook.SceneUpdatePost, fields)
+	m.notifyPostHook(ctx, 2, hook.TagMergePost, nil)
+	// movie hooks are duplicated by group hooks
+	m.notifyPostHook(ctx, 3, hook.MovieUpdatePost, nil)
+	m.notifyPostHook(ctx, 3, hook.GroupUpdatePost, nil)
+
+	fields[0] = "modified"
+
+	assert.Equal(t, &EntityChange{
+		Type:      EntityTypeScene,
+		ID:        1,
+		Operation: EntityChangeOperationUpdate,
+		Fields:    []string{"title", "rating100"},
+	}, <-c)
+	assert.Equal(t, &EntityChange{
+		Type:      EntityTypeTag,
+		ID:        2,
+		Operation: EntityChangeOperationMerge,
+		Fields:    []string{},
+	}, <-c)
+	assert.Equal(t, &EntityChange{
+		Type:      EntityTypeGroup,
+		ID:        3,
+		Operation: EntityChangeOperationUpdate,
+		Fields:    []string{},
+	}, <-c)
+
+	cancel()
+
+	// channel is closed once the context is done
+	for range c {
+		t.Error("unexpected change after cancel")
+	}
+}
+
+func TestEntityChangeManager_notifyFull(t *testing.T) {
+	m := &entityChangeManager{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := m.subscribe(ctx)
+
+	// changes beyond the buffer are dropped rather than blocking
+	for i := 0; i < entityChangeBufferSize+10; i++ {
+		m.notifyPostHook(ctx, i, hook.ImageCreatePost, nil)
+	}
+
+	assert.Len(t, c, entityChangeBufferSize)
+}
+
+func TestHookEntityChanges(t *testing.T) {
+	for _, h := range hook.AllHookTriggerEnum {
+		switch h {
+		case hook.MovieCreatePost, hook.MovieUpdatePost, hook.MovieDestroyPost:
+			continue
+		}
+
+		c, ok := hookEntityChanges[h]
+		if !ok {
+			t.Errorf("hook %s is not mapped to an entity change", h)
+			continue
+		}
+
+		assert.True(t, c.entityType.IsValid(), "hook %s entity type", h)
+		assert.True(t, c.operation.IsValid(), "hook %s operation", h)
+	}
+}
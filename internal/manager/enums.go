@@ -48,3 +48,106 @@ func (e *SystemStatusEnum) UnmarshalGQL(v interface{}) error {
 func (e SystemStatusEnum) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
+
+type EntityType string
+
+const (
+	EntityTypeScene          EntityType = "SCENE"
+	EntityTypeSceneMarker    EntityType = "SCENE_MARKER"
+	EntityTypeImage          EntityType = "IMAGE"
+	EntityTypeGallery        EntityType = "GALLERY"
+	EntityTypeGalleryChapter EntityType = "GALLERY_CHAPTER"
+	EntityTypeGroup          EntityType = "GROUP"
+	EntityTypePerformer      EntityType = "PERFORMER"
+	EntityTypeStudio         EntityType = "STUDIO"
+	EntityTypeTag            EntityType = "TAG"
+	EntityTypeCharacter      EntityType = "CHARACTER"
+)
+
+var AllEntityType = []EntityType{
+	EntityTypeScene,
+	EntityTypeSceneMarker,
+	EntityTypeImage,
+	EntityTypeGallery,
+	EntityTypeGalleryChapter,
+	EntityTypeGroup,
+	EntityTypePerformer,
+	EntityTypeStudio,
+	EntityTypeTag,
+	EntityTypeCharacter,
+}
+
+func (e EntityType) IsValid() bool {
+	switch e {
+	case EntityTypeScene, EntityTypeSceneMarker, EntityTypeImage, EntityTypeGallery, EntityTypeGalleryChapter,
+		EntityTypeGroup, EntityTypePerformer, EntityTypeStudio, EntityTypeTag, EntityTypeCharacter:
+		return true
+	}
+	return false
+}
+
+func (e EntityType) String() string {
+	return string(e)
+}
+
+func (e *EntityType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EntityType", str)
+	}
+	return nil
+}
+
+func (e EntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EntityChangeOperation string
+
+const (
+	EntityChangeOperationCreate  EntityChangeOperation = "CREATE"
+	EntityChangeOperationUpdate  EntityChangeOperation = "UPDATE"
+	EntityChangeOperationDestroy EntityChangeOperation = "DESTROY"
+	EntityChangeOperationMerge   EntityChangeOperation = "MERGE"
+)
+
+var AllEntityChangeOperation = []EntityChangeOperation{
+	EntityChangeOperationCreate,
+	EntityChangeOperationUpdate,
+	EntityChangeOperationDestroy,
+	EntityChangeOperationMerge,
+}
+
+func (e EntityChangeOperation) IsValid() bool {
+	switch e {
+	case EntityChangeOperationCreate, EntityChangeOperationUpdate, EntityChangeOperationDestroy, EntityChangeOperationMerge:
+		return true
+	}
+	return false
+}
+
+func (e EntityChangeOperation) String() string {
+	return string(e)
+}
+
+func (e *EntityChangeOperation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EntityChangeOperation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EntityChangeOperation", str)
+	}
+	return nil
+}
+
+func (e EntityChangeOperation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/stashapp/stash/internal/log"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/group"
@@ -28,6 +29,7 @@ import (
 	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/sqlite"
 	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/webhook"
 	"github.com/stashapp/stash/ui"
 )
 
@@ -46,6 +48,8 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 
 	pluginCache := plugin.NewCache(cfg)
 
+	stashFS := &file.MountFS{}
+
 	sceneService := &scene.Service{
 		File:             db.File,
 		Repository:       db.Scene,
@@ -86,6 +90,9 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 
 		Paths: mgrPaths,
 
+		FS:          stashFS,
+		remoteProxy: &file.RemoteProxy{FS: stashFS},
+
 		ImageThumbnailGenerateWaitGroup: sizedwaitgroup.New(1),
 
 		JobManager:      initJobManager(cfg),
@@ -106,9 +113,14 @@ func Initialize(cfg *config.Config, l *log.Logger) (*Manager, error) {
 		GalleryService: galleryService,
 		GroupService:   groupService,
 
-		scanSubs: &subscriptionManager{},
+		scanSubs:   &subscriptionManager{},
+		entitySubs: &entityChangeManager{},
+		webhooks:   webhook.NewDispatcher(cfg.GetWebhooks),
 	}
 
+	pluginCache.AddPostHookListener(mgr.entitySubs.notifyPostHook)
+	pluginCache.AddPostHookListener(mgr.webhooks.PostHook)
+
 	if !cfg.IsNewSystem() {
 		logger.Infof("using config file: %s", cfg.GetConfigFile())
 
@@ -227,7 +239,11 @@ func (s *Manager) postInit(ctx context.Context) error {
 		})
 	}
 
-	if err := s.Database.Open(s.Config.GetDatabasePath()); err != nil {
+	if err := s.applyPendingBlobsRestore(); err != nil {
+		logger.Errorf("error restoring blobs from backup: %v", err)
+	}
+
+	if err := s.Database.Open(s.Config.GetDatabaseURL()); err != nil {
 		var migrationNeededErr *sqlite.MigrationNeededError
 		if errors.As(err, &migrationNeededErr) {
 			logger.Warn(err)
@@ -244,9 +260,18 @@ func (s *Manager) postInit(ctx context.Context) error {
 		logger.Info("Using HTTP proxy")
 	}
 
+	s.RefreshStashFS()
+
 	s.RefreshFFMpeg(ctx)
 	s.RefreshStreamManager()
 
+	s.RefreshWatcher()
+
+	go s.purgeTrashPeriodically(context.Background())
+	go s.refreshOfflinePathsPeriodically(context.Background())
+	go s.backupDatabasePeriodically(context.Background())
+	go s.pruneChangeLogPeriodically(context.Background())
+
 	return nil
 }
 
@@ -313,6 +338,10 @@ func (s *Manager) RefreshFFMpeg(ctx context.Context) {
 		s.FFMpeg = ffmpeg.NewEncoder(ffmpegPath)
 		s.FFProbe = ffmpeg.NewFFProbe(ffprobePath)
 
+		// files in remote stash paths are read through the remote proxy
+		s.FFMpeg.InputResolver = s.resolveFFMpegInput
+		s.FFProbe.InputResolver = s.resolveFFMpegInput
+
 		s.FFMpeg.InitHWSupport(ctx)
 	}
 }
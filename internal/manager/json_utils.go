@@ -46,3 +46,7 @@ func (jp *jsonUtils) saveFile(fn string, file jsonschema.DirEntry) error {
 func (jp *jsonUtils) saveSavedFilter(fn string, savedFilter *jsonschema.SavedFilter) error {
 	return jsonschema.SaveSavedFilterFile(filepath.Join(jp.json.SavedFilters, fn), savedFilter)
 }
+
+func (jp *jsonUtils) saveCustomField(fn string, definition *jsonschema.CustomFieldDefinition) error {
+	return jsonschema.SaveCustomFieldDefinitionFile(filepath.Join(jp.json.CustomFields, fn), definition)
+}
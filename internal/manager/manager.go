@@ -10,13 +10,16 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
 	"github.com/stashapp/stash/internal/dlna"
 	"github.com/stashapp/stash/internal/log"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/internal/manager/task"
 	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
@@ -27,6 +30,7 @@ import (
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/webhook"
 
 	// register custom migrations
 	_ "github.com/stashapp/stash/pkg/sqlite/migrations"
@@ -42,6 +46,11 @@ type Manager struct {
 
 	Paths *paths.Paths
 
+	// FS is the file system used to access the stash paths. Remote stash
+	// paths are mounted in it.
+	FS          *file.MountFS
+	remoteProxy *file.RemoteProxy
+
 	FFMpeg        *ffmpeg.FFMpeg
 	FFProbe       *ffmpeg.FFProbe
 	StreamManager *ffmpeg.StreamManager
@@ -68,7 +77,13 @@ type Manager struct {
 	GalleryService GalleryService
 	GroupService   GroupService
 
-	scanSubs *subscriptionManager
+	scanSubs   *subscriptionManager
+	entitySubs *entityChangeManager
+	webhooks   *webhook.Dispatcher
+	watcher    libraryWatcher
+
+	healthReportMutex sync.Mutex
+	healthReport      *task.DatabaseHealthReport
 }
 
 var instance *Manager
@@ -280,10 +295,10 @@ func (s *Manager) Setup(ctx context.Context, input SetupInput) error {
 
 	// set the configuration
 	if !cfg.HasOverride(config.Database) {
-		cfg.SetString(config.Database, input.DatabaseFile)
+		cfg.SetDatabasePath(input.DatabaseFile)
 	}
 
-	cfg.SetInterface(config.Stash, input.Stashes)
+	cfg.SetInterface(config.Stash, config.NewStashConfigs(input.Stashes, nil))
 
 	if err := cfg.Write(); err != nil {
 		return fmt.Errorf("error writing configuration file: %v", err)
@@ -422,11 +437,20 @@ func (s *Manager) GetSystemStatus() *SystemStatus {
 func (s *Manager) Shutdown() {
 	// TODO: Each part of the manager needs to gracefully stop at some point
 
+	s.stopWatcher()
+
+	if err := s.remoteProxy.Close(); err != nil {
+		logger.Warnf("error closing remote file proxy: %v", err)
+	}
+	s.closeStashFS(s.FS.SetMounts(nil))
+
 	if s.StreamManager != nil {
 		s.StreamManager.Shutdown()
 		s.StreamManager = nil
 	}
 
+	s.webhooks.Close()
+
 	err := s.Database.Close()
 	if err != nil {
 		logger.Errorf("Error closing database: %s", err)
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/internal/manager/task"
 	"github.com/stashapp/stash/pkg/file"
 	file_image "github.com/stashapp/stash/pkg/file/image"
 	"github.com/stashapp/stash/pkg/file/video"
@@ -16,6 +17,7 @@ import (
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 func useAsVideo(pathname string) bool {
@@ -100,7 +102,17 @@ func (s *Manager) Scan(ctx context.Context, input ScanMetadataInput) (int, error
 		return 0, err
 	}
 
-	scanner := &file.Scanner{
+	scanJob := ScanJob{
+		scanner:       s.newScanner(),
+		input:         input,
+		subscriptions: s.scanSubs,
+	}
+
+	return s.JobManager.Add(ctx, "Scanning...", &scanJob), nil
+}
+
+func (s *Manager) newScanner() *file.Scanner {
+	return &file.Scanner{
 		Repository: file.NewRepository(s.Repository),
 		FileDecorators: []file.Decorator{
 			&file.FilteredDecorator{
@@ -117,16 +129,8 @@ func (s *Manager) Scan(ctx context.Context, input ScanMetadataInput) (int, error
 			},
 		},
 		FingerprintCalculator: &fingerprintCalculator{s.Config},
-		FS:                    &file.OsFS{},
+		FS:                    s.FS,
 	}
-
-	scanJob := ScanJob{
-		scanner:       scanner,
-		input:         input,
-		subscriptions: s.scanSubs,
-	}
-
-	return s.JobManager.Add(ctx, "Scanning...", &scanJob), nil
 }
 
 func (s *Manager) Import(ctx context.Context) (int, error) {
@@ -208,6 +212,68 @@ func (s *Manager) Generate(ctx context.Context, input GenerateMetadataInput) (in
 	return s.JobManager.Add(ctx, "Generating...", j), nil
 }
 
+func (s *Manager) ExportClip(ctx context.Context, input ExportClipInput) (int, error) {
+	if err := s.validateFFmpeg(); err != nil {
+		return 0, err
+	}
+	if err := validateClipOutputPath(input.OutputPath); err != nil {
+		return 0, err
+	}
+
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return 0, fmt.Errorf("converting scene id: %w", err)
+	}
+
+	if input.End <= input.Start {
+		return 0, errors.New("end time must be after start time")
+	}
+
+	clip := exportClip{
+		sceneID: sceneID,
+		start:   input.Start,
+		end:     input.End,
+	}
+	if input.Title != nil {
+		clip.title = *input.Title
+	}
+
+	j := &ExportClipsJob{
+		repository: s.Repository,
+		scanner:    s.newScanner(),
+		mode:       input.Mode,
+		outputPath: input.OutputPath,
+		clips:      []exportClip{clip},
+	}
+
+	return s.JobManager.Add(ctx, fmt.Sprintf("Exporting clip from scene id %s", input.SceneID), j), nil
+}
+
+func (s *Manager) ExportMarkerClips(ctx context.Context, input ExportMarkerClipsInput) (int, error) {
+	if err := s.validateFFmpeg(); err != nil {
+		return 0, err
+	}
+	if err := validateClipOutputPath(input.OutputPath); err != nil {
+		return 0, err
+	}
+
+	markerIDs, err := stringslice.StringSliceToIntSlice(input.MarkerIds)
+	if err != nil {
+		return 0, fmt.Errorf("converting marker ids: %w", err)
+	}
+
+	j := &ExportClipsJob{
+		repository: s.Repository,
+		scanner:    s.newScanner(),
+		mode:       input.Mode,
+		outputPath: input.OutputPath,
+		markerIDs:  markerIDs,
+		allMarkers: len(markerIDs) == 0,
+	}
+
+	return s.JobManager.Add(ctx, "Exporting marker clips...", j), nil
+}
+
 func (s *Manager) GenerateDefaultScreenshot(ctx context.Context, sceneId string) int {
 	return s.generateScreenshot(ctx, sceneId, nil)
 }
@@ -289,7 +355,7 @@ type CleanMetadataInput struct {
 
 func (s *Manager) Clean(ctx context.Context, input CleanMetadataInput) int {
 	cleaner := &file.Cleaner{
-		FS:         &file.OsFS{},
+		FS:         s.FS,
 		Repository: file.NewRepository(s.Repository),
 		Handlers: []file.CleanHandler{
 			&cleanHandler{},
@@ -308,6 +374,27 @@ func (s *Manager) Clean(ctx context.Context, input CleanMetadataInput) int {
 	return s.JobManager.Add(ctx, "Cleaning...", &j)
 }
 
+type VerifyMetadataInput struct {
+	Paths []string `json:"paths"`
+	// Percentage of files to verify, least recently verified first.
+	// All files are verified if nil.
+	SamplePercentage *float64 `json:"sample_percentage"`
+}
+
+func (s *Manager) Verify(ctx context.Context, input VerifyMetadataInput) (int, error) {
+	if p := input.SamplePercentage; p != nil && (*p <= 0 || *p > 100) {
+		return 0, fmt.Errorf("sample percentage must be greater than 0 and at most 100")
+	}
+
+	j := verifyJob{
+		repository: s.Repository,
+		fs:         s.FS,
+		input:      input,
+	}
+
+	return s.JobManager.Add(ctx, "Verifying files...", &j), nil
+}
+
 func (s *Manager) OptimiseDatabase(ctx context.Context) int {
 	j := OptimiseDatabaseJob{
 		Optimiser: s.Database,
@@ -316,6 +403,31 @@ func (s *Manager) OptimiseDatabase(ctx context.Context) int {
 	return s.JobManager.Add(ctx, "Optimising database...", &j)
 }
 
+// DatabaseHealthCheck starts a job that runs the database consistency checks,
+// repairing the issues that can be fixed safely if repair is true.
+func (s *Manager) DatabaseHealthCheck(ctx context.Context, repair bool) int {
+	j := task.DatabaseHealthCheckJob{
+		TxnManager: s.Database,
+		Checks:     s.Database.HealthChecks(),
+		Repair:     repair,
+		OnComplete: func(report *task.DatabaseHealthReport) {
+			s.healthReportMutex.Lock()
+			defer s.healthReportMutex.Unlock()
+			s.healthReport = report
+		},
+	}
+
+	return s.JobManager.Add(ctx, "Checking database health...", &j)
+}
+
+// DatabaseHealthReport returns the report of the most recent database health
+// check, or nil if no check has completed since startup.
+func (s *Manager) DatabaseHealthReport() *task.DatabaseHealthReport {
+	s.healthReportMutex.Lock()
+	defer s.healthReportMutex.Unlock()
+	return s.healthReport
+}
+
 func (s *Manager) MigrateHash(ctx context.Context) int {
 	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) error {
 		fileNamingAlgo := config.GetInstance().GetVideoFileNamingAlgorithm()
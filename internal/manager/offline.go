@@ -0,0 +1,211 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// offlineCheckInterval is the time between checks for stash paths that have
+// become unavailable or available again.
+const offlineCheckInterval = 5 * time.Minute
+
+// offlineMutex prevents concurrent refreshes from writing the same offline
+// paths.
+var offlineMutex sync.Mutex
+
+// checkStashAvailable returns an error if the stash path is unavailable. A
+// stash path is unavailable if its directory does not exist, or if it has a
+// mount marker and the marker file does not exist in the directory. Without
+// a mount marker, the stash path is also unavailable if its directory is
+// empty but tracked is true, since this is usually an unmounted mount point.
+func checkStashAvailable(fsys models.FS, s *config.StashConfig, tracked bool) error {
+	info, err := fsys.Stat(s.Path)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s.Path)
+	}
+
+	if s.MountMarker != "" {
+		marker := filepath.Join(s.Path, s.MountMarker)
+		if _, err := fsys.Lstat(marker); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("mount marker %s not found", marker)
+			}
+			return err
+		}
+
+		return nil
+	}
+
+	if tracked {
+		empty, err := isDirEmpty(fsys, s.Path)
+		if err != nil {
+			return err
+		}
+
+		if empty {
+			return fmt.Errorf("%s is empty, but has folders in the database", s.Path)
+		}
+	}
+
+	return nil
+}
+
+// isDirEmpty returns true if the directory has no entries.
+func isDirEmpty(fsys models.FS, path string) (bool, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if _, err := f.ReadDir(1); err != nil {
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return false, nil
+}
+
+// trackedStashPaths returns the stash paths that have folders other than
+// the stash path itself in the database.
+func (s *Manager) trackedStashPaths(ctx context.Context, stashes config.StashConfigs) (map[string]bool, error) {
+	ret := make(map[string]bool)
+	r := s.Repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		for _, sp := range stashes {
+			n, err := r.Folder.CountAllInPaths(ctx, []string{sp.Path})
+			if err != nil {
+				return err
+			}
+
+			// the count includes the stash path folder
+			ret[sp.Path] = n > 1
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("counting folders in stash paths: %w", err)
+	}
+
+	return ret, nil
+}
+
+// stashAvailableFunc returns a function that returns an error if the stash
+// path containing path is unavailable. Stash paths are assumed to have
+// folders in the database, since the function is used when cleaning files
+// that are in the database.
+func (s *Manager) stashAvailableFunc() func(path string) error {
+	stashes := s.Config.GetStashPaths()
+
+	return func(path string) error {
+		sp := stashes.GetStashFromDirPath(path)
+		if sp == nil {
+			return nil
+		}
+
+		return checkStashAvailable(s.FS, sp, true)
+	}
+}
+
+// RefreshOfflinePaths checks whether each stash path is available, and
+// updates the stored offline paths accordingly. Files in offline paths are
+// not cleaned. It returns the stash paths that are offline.
+func (s *Manager) RefreshOfflinePaths(ctx context.Context) ([]string, error) {
+	offlineMutex.Lock()
+	defer offlineMutex.Unlock()
+
+	stashes := s.Config.GetStashPaths()
+	tracked, err := s.trackedStashPaths(ctx, stashes)
+	if err != nil {
+		return nil, err
+	}
+
+	offline := make(map[string]error)
+	for _, sp := range stashes {
+		if err := checkStashAvailable(s.FS, sp, tracked[sp.Path]); err != nil {
+			offline[sp.Path] = err
+		}
+	}
+
+	var ret []string
+	r := s.Repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		qb := r.OfflinePath
+		existing, err := qb.All(ctx)
+		if err != nil {
+			return err
+		}
+
+		wasOffline := make(map[string]bool)
+		for _, p := range existing {
+			wasOffline[p.Path] = true
+
+			if _, ok := offline[p.Path]; ok {
+				continue
+			}
+
+			if err := qb.Destroy(ctx, p.Path); err != nil {
+				return err
+			}
+			logger.Infof("Stash path %s is available again", p.Path)
+		}
+
+		now := time.Now()
+		for p, reason := range offline {
+			ret = append(ret, p)
+
+			if wasOffline[p] {
+				continue
+			}
+
+			if err := qb.Create(ctx, &models.OfflinePath{
+				Path:         p,
+				OfflineSince: now,
+			}); err != nil {
+				return err
+			}
+			logger.Warnf("Stash path %s is unavailable, marking its files as offline: %v", p, reason)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("refreshing offline paths: %w", err)
+	}
+
+	return ret, nil
+}
+
+// refreshOfflinePathsPeriodically refreshes the offline paths now and then
+// at regular intervals, until the context is cancelled.
+func (s *Manager) refreshOfflinePathsPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(offlineCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RefreshOfflinePaths(ctx); err != nil {
+			logger.Errorf("error checking stash paths: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+)
+
+func Test_checkStashAvailable(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := filepath.Join(dir, "empty")
+	populated := filepath.Join(dir, "populated")
+	for _, d := range []string{empty, populated} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(populated, "scene.mp4"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		mountMarker string
+		tracked     bool
+		wantErr     bool
+	}{
+		{"missing", filepath.Join(dir, "missing"), "", false, true},
+		{"empty", empty, "", false, false},
+		{"empty with folders in database", empty, "", true, true},
+		{"populated with folders in database", populated, "", true, false},
+		{"missing mount marker", populated, ".mounted", true, true},
+		{"mount marker", populated, "scene.mp4", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &config.StashConfig{
+				Path:        tt.path,
+				MountMarker: tt.mountMarker,
+			}
+
+			err := checkStashAvailable(&file.OsFS{}, s, tt.tracked)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkStashAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
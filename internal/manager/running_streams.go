@@ -8,9 +8,11 @@ import (
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/internal/static"
 	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/txn"
 	"github.com/stashapp/stash/pkg/utils"
 )
@@ -28,6 +30,38 @@ func KillRunningStreams(scene *models.Scene, fileNamingAlgo models.HashAlgorithm
 	instance.ReadLockManager.Cancel(transcodePath)
 }
 
+func streamingClient(ctx context.Context, local bool, apiKey bool) models.StreamingClient {
+	ret := models.StreamingClient{
+		Local: local,
+	}
+
+	if userID := session.GetCurrentUserID(ctx); userID != nil && *userID != "" {
+		ret.UserID = *userID
+		ret.Auth = models.StreamingAuthEnumSession
+		if apiKey {
+			ret.Auth = models.StreamingAuthEnumAPIKey
+		}
+	}
+
+	return ret
+}
+
+// GetStreamingMaxBitrate returns the maximum bitrate in kilobits per second
+// that may be streamed to the client of the current request, according to
+// the configured streaming bandwidth policies. Returns 0 if unrestricted.
+func GetStreamingMaxBitrate(ctx context.Context) int {
+	client := streamingClient(ctx, session.RequestIsLocal(ctx), session.RequestUsesAPIKey(ctx))
+	return config.GetInstance().GetStreamingBandwidthPolicies().MaxBitrate(client)
+}
+
+// getRequestMaxBitrate is equivalent to GetStreamingMaxBitrate, but
+// determines the client network from the request itself, for requests
+// that do not pass through the authentication middleware.
+func getRequestMaxBitrate(r *http.Request) int {
+	client := streamingClient(r.Context(), session.IsLocalRequest(r), session.UsesAPIKey(r))
+	return config.GetInstance().GetStreamingBandwidthPolicies().MaxBitrate(client)
+}
+
 type SceneCoverGetter interface {
 	GetCover(ctx context.Context, sceneID int) ([]byte, error)
 }
@@ -53,6 +87,21 @@ func (s *SceneServer) StreamSceneDirect(scene *models.Scene, w http.ResponseWrit
 	// We trust that the request context will be closed, so we don't need to call Cancel on the
 	// returned context here.
 	_ = GetInstance().ReadLockManager.ReadLock(streamRequestCtx, filepath)
+
+	// throttle the direct stream to the client's bitrate limit
+	if maxBitrate := getRequestMaxBitrate(r); maxBitrate > 0 {
+		w = utils.ThrottleResponseWriter(w, int64(maxBitrate)*1000/8)
+	}
+
+	// files in remote stash paths are read from the remote server
+	if fsys := GetInstance().FS; fsys.IsMounted(filepath) {
+		if err := file.ServeFile(fsys, w, r, filepath); err != nil {
+			logger.Warnf("error streaming %s: %v", filepath, err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+
 	http.ServeFile(w, r, filepath)
 }
 
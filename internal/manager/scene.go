@@ -1,8 +1,12 @@
 package manager
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/url"
+	"os"
 
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/ffmpeg"
@@ -75,7 +79,10 @@ func GetVideoFileContainer(file *models.VideoFile) (ffmpeg.Container, error) {
 	return container, nil
 }
 
-func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStreamingTranscodeSize models.StreamingResolutionEnum) ([]*SceneStreamEndpoint, error) {
+// GetSceneStreamPaths returns the stream endpoints available for the scene.
+// If maxBitrate is non-zero and the primary file exceeds it, direct stream
+// endpoints are omitted so that the client uses a transcoded stream.
+func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStreamingTranscodeSize models.StreamingResolutionEnum, maxBitrate int) ([]*SceneStreamEndpoint, error) {
 	if scene == nil {
 		return nil, fmt.Errorf("nil scene")
 	}
@@ -155,12 +162,15 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 	// don't care if we can't get the container
 	container, _ := GetVideoFileContainer(pf)
 
-	if HasTranscode(scene, config.GetInstance().GetVideoFileNamingAlgorithm()) || ffmpeg.IsValidAudioForContainer(audioCodec, container) {
+	// direct and mkv streams are served at the source bitrate
+	directAllowed := !pf.ExceedsBitrate(maxBitrate)
+
+	if directAllowed && (HasTranscode(scene, config.GetInstance().GetVideoFileNamingAlgorithm()) || ffmpeg.IsValidAudioForContainer(audioCodec, container)) {
 		endpoints = append(endpoints, makeStreamEndpoint(directEndpointType, ""))
 	}
 
 	// only add mkv stream endpoint if the scene container is an mkv already
-	if container == ffmpeg.Matroska {
+	if directAllowed && container == ffmpeg.Matroska {
 		endpoints = append(endpoints, makeStreamEndpoint(mkvEndpointType, ""))
 	}
 
@@ -236,3 +246,44 @@ func HasTranscode(scene *models.Scene, fileNamingAlgo models.HashAlgorithm) bool
 	ret, _ := fsutil.FileExists(transcodePath)
 	return ret
 }
+
+// GetSceneCuts returns the scene cuts generated for the provided scene.
+// Returns nil if scene cuts have not been generated.
+func GetSceneCuts(scene *models.Scene, fileNamingAlgo models.HashAlgorithm) (models.SceneCuts, error) {
+	sceneHash := scene.GetHash(fileNamingAlgo)
+	if sceneHash == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(instance.Paths.Scene.GetSceneCutsPath(sceneHash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading scene cuts: %w", err)
+	}
+
+	var ret models.SceneCuts
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("parsing scene cuts: %w", err)
+	}
+
+	return ret, nil
+}
+
+// GetSceneSuggestedMarkers returns the markers suggested from the scene cuts
+// of the provided scene, excluding those that coincide with existing markers.
+// The primary file of the scene must be loaded.
+func GetSceneSuggestedMarkers(scene *models.Scene, existing []*models.SceneMarker, fileNamingAlgo models.HashAlgorithm) ([]*models.SuggestedMarker, error) {
+	cuts, err := GetSceneCuts(scene, fileNamingAlgo)
+	if err != nil || cuts == nil {
+		return nil, err
+	}
+
+	var duration float64
+	if f := scene.Files.Primary(); f != nil {
+		duration = f.Duration
+	}
+
+	return cuts.SuggestedMarkers(duration, existing), nil
+}
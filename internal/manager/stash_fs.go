@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+func newRemoteStashFS(path string, c *config.RemoteStashConfig) (*file.RemoteFS, error) {
+	opts := file.RemoteOptions{
+		URL:            c.URL,
+		Username:       c.Username,
+		Password:       c.Password,
+		KeyFile:        c.KeyFile,
+		HostKey:        c.HostKey,
+		MaxConnections: c.MaxConnections,
+	}
+
+	switch c.Type {
+	case config.RemoteStorageTypeWebDAV:
+		return file.NewWebDAVFS(path, opts)
+	case config.RemoteStorageTypeSFTP:
+		return file.NewSFTPFS(path, opts)
+	default:
+		return nil, fmt.Errorf("invalid remote storage type %q", c.Type)
+	}
+}
+
+// ValidateRemoteStash returns an error if the remote server of a stash path
+// cannot be connected to, or the configured directory does not exist.
+func ValidateRemoteStash(path string, c *config.RemoteStashConfig) error {
+	fsys, err := newRemoteStashFS(path, c)
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	info, err := fsys.Stat(path)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", c.URL, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", c.URL)
+	}
+
+	return nil
+}
+
+// RefreshStashFS mounts the remote stash paths in the manager file system,
+// closing the previously mounted file systems. Call this when the stash paths
+// change.
+func (s *Manager) RefreshStashFS() {
+	mounts := make(map[string]file.MountedFS)
+	for _, sp := range s.Config.GetStashPaths() {
+		if sp.Remote == nil {
+			continue
+		}
+
+		fsys, err := newRemoteStashFS(sp.Path, sp.Remote)
+		if err != nil {
+			logger.Errorf("error configuring remote stash path %s: %v", sp.Path, err)
+			continue
+		}
+
+		mounts[sp.Path] = fsys
+	}
+
+	// the proxy is only needed if there are remote files
+	if len(mounts) > 0 {
+		if err := s.remoteProxy.Start(); err != nil {
+			logger.Errorf("error starting remote file proxy: %v", err)
+		}
+	}
+
+	s.closeStashFS(s.FS.SetMounts(mounts))
+}
+
+func (s *Manager) closeStashFS(mounts map[string]file.MountedFS) {
+	for p, m := range mounts {
+		if err := m.Close(); err != nil {
+			logger.Warnf("error closing remote stash path %s: %v", p, err)
+		}
+	}
+}
+
+// resolveFFMpegInput returns the remote proxy URL of files in remote stash
+// paths, so that they can be read by ffmpeg and ffprobe.
+func (s *Manager) resolveFFMpegInput(path string) string {
+	if s.FS.IsMounted(path) {
+		return s.remoteProxy.URL(path)
+	}
+	return path
+}
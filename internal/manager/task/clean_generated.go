@@ -344,23 +344,26 @@ func (j *CleanGeneratedJob) hashPatternPrefix() string {
 	return fmt.Sprintf("%%%dx", hashLen)
 }
 
+// spriteFileSuffixes are the suffixes of the files generated in the vtt
+// directory, following the scene hash.
+var spriteFileSuffixes = []string{
+	"_sprite.jpg",
+	"_thumbs.vtt",
+	"_scenecuts.json",
+}
+
 func (j *CleanGeneratedJob) getSpriteFileHash(basename string) (string, error) {
 	patternPrefix := j.hashPatternPrefix()
-	spritePattern := patternPrefix + "_sprite.jpg"
 
 	var hash string
-	_, err := fmt.Sscanf(basename, spritePattern, &hash)
-	if err != nil {
-		// also try thumbs
-		thumbPattern := patternPrefix + "_thumbs.vtt"
-		_, err = fmt.Sscanf(basename, thumbPattern, &hash)
-
-		if err != nil {
-			return "", err
+	var err error
+	for _, suffix := range spriteFileSuffixes {
+		if _, err = fmt.Sscanf(basename, patternPrefix+suffix, &hash); err == nil {
+			return fmt.Sprintf("%x", hash), nil
 		}
 	}
 
-	return fmt.Sprintf("%x", hash), nil
+	return "", err
 }
 
 func (j *CleanGeneratedJob) cleanSpriteFiles(ctx context.Context, progress *job.Progress) error {
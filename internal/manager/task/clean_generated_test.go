@@ -0,0 +1,40 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestCleanGeneratedJob_getSpriteFileHash(t *testing.T) {
+	const hash = "0123456789abcdef"
+
+	tests := []struct {
+		basename string
+		want     string
+		wantErr  bool
+	}{
+		{hash + "_sprite.jpg", hash, false},
+		{hash + "_thumbs.vtt", hash, false},
+		{hash + "_scenecuts.json", hash, false},
+		{hash + "_other.json", "", true},
+		{"sprite.jpg", "", true},
+	}
+
+	j := &CleanGeneratedJob{
+		VideoFileNamingAlgorithm: models.HashAlgorithmOshash,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.basename, func(t *testing.T) {
+			got, err := j.getSpriteFileHash(tt.basename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getSpriteFileHash() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getSpriteFileHash() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
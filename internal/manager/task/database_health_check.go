@@ -0,0 +1,164 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// healthSampleSize is the maximum number of IDs reported for each issue.
+const healthSampleSize = 10
+
+// DatabaseHealthIssue is an inconsistency found by a database health check.
+type DatabaseHealthIssue struct {
+	Category    string   `json:"category"`
+	Check       string   `json:"check"`
+	Description string   `json:"description"`
+	Count       int      `json:"count"`
+	SampleIDs   []string `json:"sample_ids"`
+	Repairable  bool     `json:"repairable"`
+	Repaired    bool     `json:"repaired"`
+}
+
+// DatabaseHealthReport is the result of a database health check.
+type DatabaseHealthReport struct {
+	CheckedAt time.Time              `json:"checked_at"`
+	Repair    bool                   `json:"repair"`
+	Issues    []*DatabaseHealthIssue `json:"issues"`
+}
+
+type DatabaseHealthCheckJob struct {
+	TxnManager txn.Manager
+	Checks     []sqlite.HealthCheck
+	// Repair fixes the issues of the checks that can be repaired safely.
+	Repair bool
+
+	// OnComplete is called with the report once all checks have run.
+	OnComplete func(report *DatabaseHealthReport)
+}
+
+func (j *DatabaseHealthCheckJob) Execute(ctx context.Context, progress *job.Progress) error {
+	logger.Infof("Checking database health")
+	start := time.Now()
+
+	progress.SetTotal(len(j.Checks))
+
+	report := &DatabaseHealthReport{
+		CheckedAt: start,
+		Repair:    j.Repair,
+	}
+
+	for _, c := range j.Checks {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		var (
+			issue *DatabaseHealthIssue
+			err   error
+		)
+		progress.ExecuteTask("Checking "+c.Description, func() {
+			issue, err = j.runCheck(ctx, c)
+		})
+		progress.Increment()
+
+		if err != nil {
+			return err
+		}
+
+		if issue != nil {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	j.logReport(report)
+	logger.Infof("Finished checking database health after %s", time.Since(start))
+
+	if j.OnComplete != nil {
+		j.OnComplete(report)
+	}
+
+	return nil
+}
+
+// runCheck runs the check, repairing the issue if applicable. It returns nil
+// if the check found no issues.
+func (j *DatabaseHealthCheckJob) runCheck(ctx context.Context, c sqlite.HealthCheck) (*DatabaseHealthIssue, error) {
+	var ids []string
+	if err := txn.WithReadTxn(ctx, j.TxnManager, func(ctx context.Context) error {
+		var err error
+		ids, err = c.Find(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	ret := &DatabaseHealthIssue{
+		Category:    c.Category,
+		Check:       c.Name,
+		Description: c.Description,
+		Count:       len(ids),
+		SampleIDs:   sampleIDs(ids, healthSampleSize),
+		Repairable:  c.Repairable(),
+	}
+
+	if j.Repair && ret.Repairable {
+		if err := txn.WithTxn(ctx, j.TxnManager, func(ctx context.Context) error {
+			return c.Repair(ctx, ids)
+		}); err != nil {
+			return nil, err
+		}
+
+		ret.Repaired = true
+	}
+
+	return ret, nil
+}
+
+// sampleIDs returns up to n distinct IDs, in the order they first appear.
+func sampleIDs(ids []string, n int) []string {
+	seen := make(map[string]bool)
+	ret := []string{}
+	for _, id := range ids {
+		if len(ret) >= n {
+			break
+		}
+
+		if !seen[id] {
+			seen[id] = true
+			ret = append(ret, id)
+		}
+	}
+
+	return ret
+}
+
+func (j *DatabaseHealthCheckJob) logReport(report *DatabaseHealthReport) {
+	if len(report.Issues) == 0 {
+		logger.Infof("No database issues found")
+		return
+	}
+
+	for _, issue := range report.Issues {
+		msg := fmt.Sprintf("[%s] %d %s (e.g. %s)", issue.Category, issue.Count, issue.Description, strings.Join(issue.SampleIDs, ", "))
+		switch {
+		case issue.Repaired:
+			logger.Infof("%s - repaired", msg)
+		case issue.Repairable:
+			logger.Warnf("%s - can be repaired", msg)
+		default:
+			logger.Warn(msg)
+		}
+	}
+}
@@ -47,6 +47,18 @@ func (s *MigrateJob) Execute(ctx context.Context, progress *job.Progress) error
 
 	database := s.Database
 
+	if database.Type() == sqlite.PostgresBackend {
+		// PostgreSQL databases are not backed up by stash. PostgreSQL runs
+		// each migration file in a single transaction, so a failed migration
+		// is rolled back.
+		if err := s.runMigrations(ctx, progress); err != nil {
+			return fmt.Errorf("error performing migration: %w", err)
+		}
+
+		logger.Infof("Database migration complete")
+		return nil
+	}
+
 	// always backup so that we can roll back to the previous version if
 	// migration fails
 	backupPath := s.BackupPath
@@ -39,10 +39,22 @@ func (j *cleanJob) Execute(ctx context.Context, progress *job.Progress) error {
 		logger.Infof("Running in Dry Mode")
 	}
 
+	// files in unavailable stash paths must not be cleaned
+	offlinePaths, err := instance.RefreshOfflinePaths(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range offlinePaths {
+		logger.Warnf("Not cleaning files in offline stash path %s", p)
+	}
+
 	j.cleaner.Clean(ctx, file.CleanOptions{
-		Paths:      j.input.Paths,
-		DryRun:     j.input.DryRun,
-		PathFilter: newCleanFilter(instance.Config),
+		Paths:          j.input.Paths,
+		DryRun:         j.input.DryRun,
+		PathFilter:     newCleanFilter(instance.Config),
+		OfflinePaths:   offlinePaths,
+		CheckAvailable: instance.stashAvailableFunc(),
 	}, progress)
 
 	if job.IsCancelled(ctx) {
@@ -152,6 +164,7 @@ func newCleanFilter(c *config.Config) *cleanFilter {
 			extensionConfig:   newExtensionConfig(c),
 			stashPaths:        c.GetStashPaths(),
 			generatedPath:     c.GetGeneratedPath(),
+			trashPath:         c.GetTrashPath(),
 			videoExcludeRegex: generateRegexps(c.GetExcludes()),
 			imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
 		},
@@ -182,6 +195,11 @@ func (f *cleanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
+	if f.trashPath != "" && fsutil.IsPathInDir(f.trashPath, path) {
+		logger.Infof("%s is in trash path. Marking to clean: \"%s\"", fileOrFolder, path)
+		return false
+	}
+
 	if info.IsDir() {
 		return !f.shouldCleanFolder(path, stash)
 	}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/customfield"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/group"
@@ -178,6 +179,7 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 		t.ExportStudios(ctx, workerCount)
 		t.ExportTags(ctx, workerCount)
 		t.ExportSavedFilters(ctx, workerCount)
+		t.ExportCustomFields(ctx)
 
 		return nil
 	})
@@ -509,6 +511,12 @@ func (t *ExportTask) exportScene(ctx context.Context, wg *sync.WaitGroup, jobCha
 			continue
 		}
 
+		newSceneJSON.CustomFields, err = sceneReader.GetCustomFields(ctx, s.ID)
+		if err != nil {
+			logger.Errorf("[scenes] <%s> error getting scene custom fields: %v", sceneHash, err)
+			continue
+		}
+
 		// export files
 		for _, f := range s.Files.List() {
 			t.exportFile(f)
@@ -798,6 +806,12 @@ func (t *ExportTask) exportGallery(ctx context.Context, wg *sync.WaitGroup, jobC
 			continue
 		}
 
+		newGalleryJSON.CustomFields, err = r.Gallery.GetCustomFields(ctx, g.ID)
+		if err != nil {
+			logger.Errorf("[galleries] <%s> error getting gallery custom fields: %v", g.DisplayName(), err)
+			continue
+		}
+
 		// export files
 		for _, f := range g.Files.List() {
 			t.exportFile(f)
@@ -928,6 +942,12 @@ func (t *ExportTask) exportPerformer(ctx context.Context, wg *sync.WaitGroup, jo
 			continue
 		}
 
+		newPerformerJSON.CustomFields, err = performerReader.GetCustomFields(ctx, p.ID)
+		if err != nil {
+			logger.Errorf("[performers] <%s> error getting performer custom fields: %v", p.Name, err)
+			continue
+		}
+
 		tags, err := r.Tag.FindByPerformerID(ctx, p.ID)
 		if err != nil {
 			logger.Errorf("[performers] <%s> error getting performer tags: %v", p.Name, err)
@@ -1002,6 +1022,12 @@ func (t *ExportTask) exportStudio(ctx context.Context, wg *sync.WaitGroup, jobCh
 			continue
 		}
 
+		newStudioJSON.CustomFields, err = studioReader.GetCustomFields(ctx, s.ID)
+		if err != nil {
+			logger.Errorf("[studios] <%s> error getting studio custom fields: %v", s.Name, err)
+			continue
+		}
+
 		tags, err := r.Tag.FindByStudioID(ctx, s.ID)
 		if err != nil {
 			logger.Errorf("[studios] <%s> error getting studio tags: %s", s.Name, err.Error())
@@ -1148,6 +1174,12 @@ func (t *ExportTask) exportGroup(ctx context.Context, wg *sync.WaitGroup, jobCha
 			continue
 		}
 
+		newGroupJSON.CustomFields, err = groupReader.GetCustomFields(ctx, m.ID)
+		if err != nil {
+			logger.Errorf("[groups] <%s> error getting group custom fields: %v", m.Name, err)
+			continue
+		}
+
 		tags, err := tagReader.FindByGroupID(ctx, m.ID)
 		if err != nil {
 			logger.Errorf("[groups] <%s> error getting image tag names: %v", m.Name, err)
@@ -1247,3 +1279,29 @@ func (t *ExportTask) exportSavedFilter(ctx context.Context, wg *sync.WaitGroup,
 		}
 	}
 }
+
+// ExportCustomFields exports all custom field definitions, so that the
+// custom field values of the exported objects can be imported.
+func (t *ExportTask) ExportCustomFields(ctx context.Context) {
+	definitions, err := t.repository.CustomField.All(ctx)
+	if err != nil {
+		logger.Errorf("[custom fields] failed to fetch custom fields: %v", err)
+		return
+	}
+
+	logger.Info("[custom fields] exporting")
+
+	for i, d := range definitions {
+		index := i + 1
+		logger.Progressf("[custom fields] %d of %d", index, len(definitions))
+
+		newJSON := customfield.ToJSON(d)
+		fn := newJSON.Filename()
+
+		if err := t.json.saveCustomField(fn, newJSON); err != nil {
+			logger.Errorf("[custom fields] <%s> failed to save json: %v", fn, err)
+		}
+	}
+
+	logger.Info("[custom fields] export complete")
+}
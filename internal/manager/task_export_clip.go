@@ -0,0 +1,429 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+type ClipExportMode string
+
+const (
+	// Copy the streams without re-encoding. Fast and lossless, but the
+	// start point is aligned to the preceding keyframe.
+	ClipExportModeCopy ClipExportMode = "COPY"
+	// Re-encode the clip. Frame accurate, but slower.
+	ClipExportModeReencode ClipExportMode = "REENCODE"
+)
+
+var AllClipExportMode = []ClipExportMode{
+	ClipExportModeCopy,
+	ClipExportModeReencode,
+}
+
+func (e ClipExportMode) IsValid() bool {
+	switch e {
+	case ClipExportModeCopy, ClipExportModeReencode:
+		return true
+	}
+	return false
+}
+
+func (e ClipExportMode) String() string {
+	return string(e)
+}
+
+func (e *ClipExportMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ClipExportMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ClipExportMode", str)
+	}
+	return nil
+}
+
+func (e ClipExportMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ExportClipInput struct {
+	SceneID string         `json:"scene_id"`
+	Start   float64        `json:"start"`
+	End     float64        `json:"end"`
+	Mode    ClipExportMode `json:"mode"`
+	// Library folder to write the clip to. Must be within a stash path.
+	OutputPath string  `json:"output_path"`
+	Title      *string `json:"title"`
+}
+
+type ExportMarkerClipsInput struct {
+	// Markers to export. If empty, all markers with an end time are exported.
+	MarkerIds  []string       `json:"marker_ids"`
+	Mode       ClipExportMode `json:"mode"`
+	OutputPath string         `json:"output_path"`
+}
+
+func validateClipOutputPath(outputPath string) error {
+	if outputPath == "" {
+		return errors.New("output path must be set")
+	}
+
+	if config.StashConfigs.GetStashFromDirPath(config.GetInstance().GetStashPaths(), outputPath) == nil {
+		return fmt.Errorf("%s is not in the configured stash paths", outputPath)
+	}
+
+	if exists, _ := fsutil.DirExists(outputPath); !exists {
+		return fmt.Errorf("%s is not a directory", outputPath)
+	}
+
+	return nil
+}
+
+type exportClip struct {
+	sceneID int
+	start   float64
+	end     float64
+	title   string
+}
+
+type ExportClipsJob struct {
+	repository models.Repository
+	scanner    scanner
+	mode       ClipExportMode
+	outputPath string
+
+	clips []exportClip
+	// if markerIDs is set, clips are populated from the markers
+	markerIDs []int
+	// if allMarkers is set, clips are populated from all markers with an end time
+	allMarkers bool
+}
+
+func (j *ExportClipsJob) Execute(ctx context.Context, progress *job.Progress) error {
+	if j.markerIDs != nil || j.allMarkers {
+		clips, err := j.markerClips(ctx)
+		if err != nil {
+			return err
+		}
+		j.clips = append(j.clips, clips...)
+	}
+
+	progress.SetTotal(len(j.clips))
+
+	var failed int
+	for _, c := range j.clips {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Exporting clip from scene %d", c.sceneID), func() {
+			if err := j.exportClip(ctx, progress, c); err != nil {
+				logger.Errorf("error exporting clip from scene %d: %v", c.sceneID, err)
+				failed++
+			}
+		})
+
+		progress.Increment()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to export %d of %d clips", failed, len(j.clips))
+	}
+
+	logger.Infof("Exported %d clips", len(j.clips))
+	return nil
+}
+
+func (j *ExportClipsJob) markerClips(ctx context.Context) ([]exportClip, error) {
+	var ret []exportClip
+
+	r := j.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var markers []*models.SceneMarker
+		var err error
+		if j.allMarkers {
+			markers, err = r.SceneMarker.All(ctx)
+		} else {
+			markers, err = r.SceneMarker.FindMany(ctx, j.markerIDs)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, m := range markers {
+			if m.EndSeconds == nil {
+				if !j.allMarkers {
+					logger.Warnf("Skipping marker %d: marker has no end time", m.ID)
+				}
+				continue
+			}
+
+			title := m.Title
+			if title == "" {
+				tag, err := r.Tag.Find(ctx, m.PrimaryTagID)
+				if err != nil {
+					return err
+				}
+				if tag != nil {
+					title = tag.Name
+				}
+			}
+
+			ret = append(ret, exportClip{
+				sceneID: m.SceneID,
+				start:   m.Seconds,
+				end:     *m.EndSeconds,
+				title:   title,
+			})
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("finding markers: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (j *ExportClipsJob) exportClip(ctx context.Context, progress *job.Progress, c exportClip) error {
+	if c.end <= c.start {
+		return fmt.Errorf("end time %v must be after start time %v", c.end, c.start)
+	}
+
+	r := j.repository
+
+	var s *models.Scene
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		s, err = r.Scene.Find(ctx, c.sceneID)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene with id %d not found", c.sceneID)
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+			return err
+		}
+
+		if err := s.LoadPerformerIDs(ctx, r.Scene); err != nil {
+			return err
+		}
+
+		if err := s.LoadTagIDs(ctx, r.Scene); err != nil {
+			return err
+		}
+
+		return s.LoadCharacterIDs(ctx, r.Scene)
+	}); err != nil {
+		return err
+	}
+
+	f := s.Files.Primary()
+	if f == nil {
+		return errors.New("scene has no files")
+	}
+
+	output := filepath.Join(j.outputPath, clipFilename(f.Path, c.start, c.end, j.mode))
+
+	g := &generate.Generator{
+		Encoder:      instance.FFMpeg,
+		FFMpegConfig: instance.Config,
+		LockManager:  instance.ReadLockManager,
+		ScenePaths:   instance.Paths.Scene,
+	}
+
+	if err := g.Clip(ctx, f.Path, output, clipOptions(c, j.mode)); err != nil {
+		return err
+	}
+
+	if err := j.scanClip(ctx, progress, output); err != nil {
+		return err
+	}
+
+	return r.WithTxn(ctx, func(ctx context.Context) error {
+		clipScenes, err := r.Scene.FindByPath(ctx, output)
+		if err != nil {
+			return err
+		}
+		if len(clipScenes) == 0 {
+			return fmt.Errorf("no scene was created for %s", output)
+		}
+
+		title := c.title
+		if title == "" {
+			title = fmt.Sprintf("%s (%s - %s)", s.GetTitle(), formatClipTime(c.start), formatClipTime(c.end))
+		}
+
+		partial := models.NewScenePartial()
+		partial.Title = models.NewOptionalString(title)
+		partial.StudioID = models.NewOptionalIntPtr(s.StudioID)
+		partial.PerformerIDs = &models.UpdateIDs{
+			IDs:  s.PerformerIDs.List(),
+			Mode: models.RelationshipUpdateModeSet,
+		}
+		partial.TagIDs = &models.UpdateIDs{
+			IDs:  s.TagIDs.List(),
+			Mode: models.RelationshipUpdateModeSet,
+		}
+		partial.CharacterIDs = &models.UpdateIDs{
+			IDs:  s.CharacterIDs.List(),
+			Mode: models.RelationshipUpdateModeSet,
+		}
+
+		for _, clipScene := range clipScenes {
+			if _, err := r.Scene.UpdatePartial(ctx, clipScene.ID, partial); err != nil {
+				return fmt.Errorf("updating clip scene: %w", err)
+			}
+
+			logger.Infof("Created scene %d from clip %s", clipScene.ID, output)
+		}
+
+		return nil
+	})
+}
+
+// scanClip scans the new clip file so that a scene is created for it.
+func (j *ExportClipsJob) scanClip(ctx context.Context, progress *job.Progress, output string) error {
+	mgr := GetInstance()
+	cfg := mgr.Config
+	repo := mgr.Repository
+
+	// the scanner only adds files to existing folders, so create the output
+	// folder if it hasn't been scanned yet
+	if err := repo.WithTxn(ctx, func(ctx context.Context) error {
+		stash := cfg.GetStashPaths().GetStashFromDirPath(j.outputPath)
+		if stash == nil {
+			return fmt.Errorf("%s is not in the configured stash paths", j.outputPath)
+		}
+
+		stashFolder, err := repo.Folder.FindByPath(ctx, stash.Path)
+		if err != nil {
+			return err
+		}
+		if stashFolder == nil {
+			return fmt.Errorf("stash path %s has not been scanned", stash.Path)
+		}
+
+		_, err = file.GetOrCreateFolderHierarchy(ctx, repo.Folder, j.outputPath)
+		return err
+	}); err != nil {
+		return fmt.Errorf("creating output folder: %w", err)
+	}
+
+	taskQueue := job.NewTaskQueue(ctx, progress, 1, 1)
+
+	input := ScanMetadataInput{}
+	j.scanner.Scan(ctx, getScanHandlers(input, taskQueue, progress), file.ScanOptions{
+		Paths:                  []string{output},
+		ScanFilters:            []file.PathFilter{newScanFilter(cfg, repo, time.Time{})},
+		ZipFileExtensions:      cfg.GetGalleryExtensions(),
+		ParallelTasks:          1,
+		HandlerRequiredFilters: []file.Filter{newHandlerRequiredFilter(cfg, repo)},
+	}, clipScanProgress{progress})
+
+	taskQueue.Close()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// the scanner logs errors rather than returning them, so check that the
+	// file was added
+	return repo.WithReadTxn(ctx, func(ctx context.Context) error {
+		f, err := repo.File.FindByPath(ctx, output)
+		if err != nil {
+			return fmt.Errorf("finding clip file: %w", err)
+		}
+		if f == nil {
+			return fmt.Errorf("clip file %s was not added by the scan", output)
+		}
+		return nil
+	})
+}
+
+// clipScanProgress reports scan tasks without affecting the job's progress total.
+type clipScanProgress struct {
+	progress *job.Progress
+}
+
+func (p clipScanProgress) AddTotal(total int) {}
+func (p clipScanProgress) Increment()         {}
+func (p clipScanProgress) Definite()          {}
+
+func (p clipScanProgress) ExecuteTask(description string, fn func()) {
+	p.progress.ExecuteTask(description, fn)
+}
+
+func clipOptions(c exportClip, mode ClipExportMode) generate.ClipOptions {
+	return generate.ClipOptions{
+		Start:    c.start,
+		Duration: c.end - c.start,
+		Copy:     mode == ClipExportModeCopy,
+	}
+}
+
+func clipFilename(input string, start, end float64, mode ClipExportMode) string {
+	ext := filepath.Ext(input)
+	base := strings.TrimSuffix(filepath.Base(input), ext)
+
+	if mode == ClipExportModeReencode {
+		ext = ".mp4"
+	}
+
+	return fmt.Sprintf("%s.clip_%s-%s%s", base, formatClipFileTime(start), formatClipFileTime(end), ext)
+}
+
+// formatClipFileTime formats seconds as a filename-safe timestamp, such as 1h02m03.5s.
+func formatClipFileTime(seconds float64) string {
+	h, m, s := splitClipTime(seconds)
+	if h > 0 {
+		return fmt.Sprintf("%dh%02dm%ss", h, m, s)
+	}
+	return fmt.Sprintf("%dm%ss", m, s)
+}
+
+// formatClipTime formats seconds as [h:]mm:ss[.fff].
+func formatClipTime(seconds float64) string {
+	h, m, s := splitClipTime(seconds)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%s", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%s", m, s)
+}
+
+// splitClipTime splits seconds into hours, minutes and zero-padded seconds,
+// rounded to the nearest millisecond.
+func splitClipTime(seconds float64) (int, int, string) {
+	ms := int64(math.Round(seconds * 1000))
+	h := int(ms / 3600000)
+	m := int(ms % 3600000 / 60000)
+	s := float64(ms%60000) / 1000
+
+	secs := strconv.FormatFloat(s, 'f', -1, 64)
+	if s < 10 {
+		secs = "0" + secs
+	}
+
+	return h, m, secs
+}
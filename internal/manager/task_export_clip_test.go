@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+func Test_clipFilename(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		start float64
+		end   float64
+		mode  ClipExportMode
+		want  string
+	}{
+		{"copy", "/stash/video.mkv", 12, 30.5, ClipExportModeCopy, "video.clip_0m12s-0m30.5s.mkv"},
+		{"reencode", "/stash/video.mkv", 12, 30.5, ClipExportModeReencode, "video.clip_0m12s-0m30.5s.mp4"},
+		{"hours", "/stash/video.mp4", 3723.25, 3725, ClipExportModeCopy, "video.clip_1h02m03.25s-1h02m05s.mp4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipFilename(tt.input, tt.start, tt.end, tt.mode); got != tt.want {
+				t.Errorf("clipFilename() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatClipTime(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "00:00"},
+		{5.5, "00:05.5"},
+		{75, "01:15"},
+		{3723.1234, "1:02:03.123"},
+	}
+	for _, tt := range tests {
+		if got := formatClipTime(tt.seconds); got != tt.want {
+			t.Errorf("formatClipTime(%v) = %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+// argIndex returns the index of flag followed by value in args, or -1.
+func argIndex(args []string, flag string, value string) int {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func Test_clipArgs(t *testing.T) {
+	const (
+		input  = "/stash/video.mkv"
+		output = "/clips/video.clip.mkv"
+	)
+
+	tests := []struct {
+		name  string
+		start float64
+		end   float64
+		mode  ClipExportMode
+		// seek before and after the input, empty for none
+		inputSeek  string
+		outputSeek string
+		duration   string
+		videoCodec string
+		audioCodec string
+	}{
+		{"copy", 12, 30.5, ClipExportModeCopy, "12", "", "18.5", "copy", "copy"},
+		{"copy from start", 0, 10, ClipExportModeCopy, "", "", "10", "copy", "copy"},
+		{"reencode", 5, 10, ClipExportModeReencode, "", "5", "5", "libx264", "aac"},
+		{"reencode long seek", 45, 50.25, ClipExportModeReencode, "25", "20", "5.25", "libx264", "aac"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := generate.ClipArgs(input, output, clipOptions(exportClip{start: tt.start, end: tt.end}, tt.mode))
+
+			inputIdx := argIndex(args, "-i", input)
+			if inputIdx == -1 {
+				t.Fatalf("input missing from %v", args)
+			}
+
+			seeks := map[bool]string{}
+			for i := 0; i+1 < len(args); i++ {
+				if args[i] == "-ss" {
+					seeks[i < inputIdx] = args[i+1]
+				}
+			}
+			if seeks[true] != tt.inputSeek {
+				t.Errorf("input seek = %q, want %q in %v", seeks[true], tt.inputSeek, args)
+			}
+			if seeks[false] != tt.outputSeek {
+				t.Errorf("output seek = %q, want %q in %v", seeks[false], tt.outputSeek, args)
+			}
+
+			if argIndex(args, "-t", tt.duration) < inputIdx {
+				t.Errorf("duration %s missing from output args %v", tt.duration, args)
+			}
+			if argIndex(args, "-c:v", tt.videoCodec) == -1 {
+				t.Errorf("video codec %s missing from %v", tt.videoCodec, args)
+			}
+			if argIndex(args, "-c:a", tt.audioCodec) == -1 {
+				t.Errorf("audio codec %s missing from %v", tt.audioCodec, args)
+			}
+
+			// streams are mapped explicitly when copying
+			if got := slices.Contains(args, "0:v:0"); got != (tt.mode == ClipExportModeCopy) {
+				t.Errorf("stream mapping = %v, want %v in %v", got, tt.mode == ClipExportModeCopy, args)
+			}
+
+			if args[len(args)-1] != output {
+				t.Errorf("last arg = %s, want %s", args[len(args)-1], output)
+			}
+		})
+	}
+}
@@ -29,7 +29,11 @@ type GenerateMetadataInput struct {
 	// Generate transcodes even if not required
 	ForceTranscodes           bool `json:"forceTranscodes"`
 	Phashes                   bool `json:"phashes"`
+	PhashSegments             bool `json:"phashSegments"`
+	AudioFingerprints         bool `json:"audioFingerprints"`
 	InteractiveHeatmapsSpeeds bool `json:"interactiveHeatmapsSpeeds"`
+	SceneCuts                 bool `json:"sceneCuts"`
+	ImportChapters            bool `json:"importChapters"`
 	ClipPreviews              bool `json:"clipPreviews"`
 	ImageThumbnails           bool `json:"imageThumbnails"`
 	// scene ids to generate for
@@ -73,7 +77,11 @@ type totalsGenerate struct {
 	markers                  int64
 	transcodes               int64
 	phashes                  int64
+	phashSegments            int64
+	audioFingerprints        int64
 	interactiveHeatmapSpeeds int64
+	sceneCuts                int64
+	chapters                 int64
 	clipPreviews             int64
 	imageThumbnails          int64
 
@@ -172,9 +180,21 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) error
 		if j.input.Phashes {
 			logMsg += fmt.Sprintf(" %d phashes", totals.phashes)
 		}
+		if j.input.PhashSegments {
+			logMsg += fmt.Sprintf(" %d phash segments", totals.phashSegments)
+		}
+		if j.input.AudioFingerprints {
+			logMsg += fmt.Sprintf(" %d audio fingerprints", totals.audioFingerprints)
+		}
 		if j.input.InteractiveHeatmapsSpeeds {
 			logMsg += fmt.Sprintf(" %d heatmaps & speeds", totals.interactiveHeatmapSpeeds)
 		}
+		if j.input.SceneCuts {
+			logMsg += fmt.Sprintf(" %d scene cuts", totals.sceneCuts)
+		}
+		if j.input.ImportChapters {
+			logMsg += fmt.Sprintf(" %d chapter imports", totals.chapters)
+		}
 		if j.input.ClipPreviews {
 			logMsg += fmt.Sprintf(" %d Image Clip Previews", totals.clipPreviews)
 		}
@@ -284,7 +304,7 @@ func (j *GenerateJob) queueImagesTasks(ctx context.Context, g *generate.Generato
 
 	r := j.repository
 
-	for more := j.input.ClipPreviews || j.input.ImageThumbnails; more; {
+	for more := j.input.ClipPreviews || j.input.ImageThumbnails || j.input.Phashes; more; {
 		if job.IsCancelled(ctx) {
 			return
 		}
@@ -466,6 +486,40 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 		}
 	}
 
+	if j.input.PhashSegments {
+		// generate for all files in scene
+		for _, f := range scene.Files.List() {
+			task := &GeneratePhashSegmentsTask{
+				repository: r,
+				File:       f,
+				Overwrite:  j.overwrite,
+			}
+
+			if task.required() {
+				j.totals.phashSegments++
+				j.totals.tasks++
+				queue <- task
+			}
+		}
+	}
+
+	if j.input.AudioFingerprints {
+		// generate for all files in scene
+		for _, f := range scene.Files.List() {
+			task := &GenerateAudioFingerprintTask{
+				repository: r,
+				File:       f,
+				Overwrite:  j.overwrite,
+			}
+
+			if task.required() {
+				j.totals.audioFingerprints++
+				j.totals.tasks++
+				queue <- task
+			}
+		}
+	}
+
 	if j.input.InteractiveHeatmapsSpeeds {
 		task := &GenerateInteractiveHeatmapSpeedTask{
 			repository:          r,
@@ -480,6 +534,35 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			queue <- task
 		}
 	}
+
+	if j.input.SceneCuts {
+		task := &GenerateSceneCutsTask{
+			Scene:               *scene,
+			Overwrite:           j.overwrite,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			generator:           g,
+		}
+
+		if task.required() {
+			j.totals.sceneCuts++
+			j.totals.tasks++
+			queue <- task
+		}
+	}
+
+	if j.input.ImportChapters {
+		task := &ImportChaptersTask{
+			repository: r,
+			Scene:      *scene,
+			DefaultTag: instance.Config.GetChapterMarkerDefaultTag(),
+		}
+
+		if task.required() {
+			j.totals.chapters++
+			j.totals.tasks++
+			queue <- task
+		}
+	}
 }
 
 func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.SceneMarker, queue chan<- Task) {
@@ -521,4 +604,26 @@ func (j *GenerateJob) queueImageJob(g *generate.Generator, image *models.Image,
 			queue <- task
 		}
 	}
+
+	if j.input.Phashes {
+		// generate for all image files, including those in zip files
+		for _, f := range image.Files.List() {
+			imageFile, ok := f.(*models.ImageFile)
+			if !ok {
+				continue
+			}
+
+			task := &GenerateImagePhashTask{
+				repository: j.repository,
+				File:       imageFile,
+				Overwrite:  j.overwrite,
+			}
+
+			if task.required() {
+				j.totals.phashes++
+				j.totals.tasks++
+				queue <- task
+			}
+		}
+	}
 }
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/hash/audiofingerprint"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type GenerateAudioFingerprintTask struct {
+	repository models.Repository
+	File       *models.VideoFile
+	Overwrite  bool
+}
+
+func (t *GenerateAudioFingerprintTask) GetDescription() string {
+	return fmt.Sprintf("Generating audio fingerprint for %s", t.File.Path)
+}
+
+func (t *GenerateAudioFingerprintTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	generated, err := audiofingerprint.Generate(ctx, instance.FFMpeg, t.File, audiofingerprint.DefaultLength)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Errorf("Error generating audio fingerprint: %v", err)
+			logErrorOutput(err)
+		}
+		return
+	}
+
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		t.File.Fingerprints = t.File.Fingerprints.AppendUnique(models.Fingerprint{
+			Type:        models.FingerprintTypeAudio,
+			Fingerprint: utils.AudioFingerprintToString(generated),
+		})
+
+		return r.File.Update(ctx, t.File)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("Error setting audio fingerprint: %v", err)
+	}
+}
+
+func (t *GenerateAudioFingerprintTask) required() bool {
+	// files without audio cannot be fingerprinted
+	if t.File.AudioCodec == "" {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	return t.File.Fingerprints.Get(models.FingerprintTypeAudio) == nil
+}
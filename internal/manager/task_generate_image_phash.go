@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/stashapp/stash/pkg/hash/imagephash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type GenerateImagePhashTask struct {
+	repository models.Repository
+	File       *models.ImageFile
+	Overwrite  bool
+}
+
+func (t *GenerateImagePhashTask) GetDescription() string {
+	return fmt.Sprintf("Generating phash for %s", t.File.Path)
+}
+
+func (t *GenerateImagePhashTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	generated, err := imagephash.Generate(GetInstance().FS, t.File)
+	if err != nil {
+		// formats that can only be read by ffmpeg are not supported
+		if errors.Is(err, image.ErrFormat) {
+			logger.Debugf("Skipping phash for %s: %v", t.File.Path, err)
+		} else {
+			logger.Errorf("Error generating phash for %s: %v", t.File.Path, err)
+		}
+		return
+	}
+
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		t.File.Fingerprints = t.File.Fingerprints.AppendUnique(models.Fingerprint{
+			Type:        models.FingerprintTypePhash,
+			Fingerprint: int64(*generated),
+		})
+
+		return r.File.Update(ctx, t.File)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("Error setting phash: %v", err)
+	}
+}
+
+func (t *GenerateImagePhashTask) required() bool {
+	if t.Overwrite {
+		return true
+	}
+
+	return t.File.Fingerprints.Get(models.FingerprintTypePhash) == nil
+}
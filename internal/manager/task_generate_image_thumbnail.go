@@ -40,7 +40,7 @@ func (t *GenerateImageThumbnailTask) Start(ctx context.Context) {
 		Preset:     c.GetPreviewPreset().String(),
 	}
 
-	encoder := image.NewThumbnailEncoder(mgr.FFMpeg, mgr.FFProbe, clipPreviewOptions)
+	encoder := image.NewThumbnailEncoder(mgr.FS, mgr.FFMpeg, mgr.FFProbe, clipPreviewOptions)
 	data, err := encoder.GetThumbnail(f, models.DefaultGthumbWidth)
 
 	if err != nil {
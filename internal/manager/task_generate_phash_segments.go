@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/hash/videophash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type GeneratePhashSegmentsTask struct {
+	repository models.Repository
+	File       *models.VideoFile
+	Overwrite  bool
+}
+
+func (t *GeneratePhashSegmentsTask) GetDescription() string {
+	return fmt.Sprintf("Generating phash segments for %s", t.File.Path)
+}
+
+func (t *GeneratePhashSegmentsTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	interval := videophash.DefaultSegmentInterval
+	generated, err := videophash.GenerateSegments(ctx, instance.FFMpeg, t.File, interval)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Errorf("Error generating phash segments: %v", err)
+			logErrorOutput(err)
+		}
+		return
+	}
+
+	segments := utils.PhashSegments{
+		Interval: interval,
+		Hashes:   make([]int64, len(generated)),
+	}
+	for i, h := range generated {
+		segments.Hashes[i] = int64(h)
+	}
+
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		t.File.Fingerprints = t.File.Fingerprints.AppendUnique(models.Fingerprint{
+			Type:        models.FingerprintTypePhashSegments,
+			Fingerprint: segments.String(),
+		})
+
+		return r.File.Update(ctx, t.File)
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("Error setting phash segments: %v", err)
+	}
+}
+
+func (t *GeneratePhashSegmentsTask) required() bool {
+	if t.Overwrite {
+		return true
+	}
+
+	// files shorter than a single segment cannot be hashed
+	if t.File.Duration < videophash.DefaultSegmentInterval {
+		return false
+	}
+
+	return t.File.Fingerprints.Get(models.FingerprintTypePhashSegments) == nil
+}
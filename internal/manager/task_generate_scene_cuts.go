@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+type GenerateSceneCutsTask struct {
+	Scene               models.Scene
+	Overwrite           bool
+	fileNamingAlgorithm models.HashAlgorithm
+	generator           *generate.Generator
+}
+
+func (t *GenerateSceneCutsTask) GetDescription() string {
+	return fmt.Sprintf("Detecting scene cuts for %s", t.Scene.Path)
+}
+
+func (t *GenerateSceneCutsTask) Start(ctx context.Context) {
+	if !t.required() {
+		return
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	threshold := instance.Config.GetSceneCutThreshold()
+
+	if err := t.generator.SceneCuts(ctx, t.Scene.Path, sceneHash, threshold); err != nil && ctx.Err() == nil {
+		logger.Errorf("error detecting scene cuts for %s: %v", t.Scene.Path, err)
+	}
+}
+
+func (t *GenerateSceneCutsTask) required() bool {
+	if t.Scene.Path == "" {
+		return false
+	}
+
+	if t.Overwrite {
+		return true
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if sceneHash == "" {
+		return false
+	}
+
+	exists, _ := fsutil.FileExists(instance.Paths.Scene.GetSceneCutsPath(sceneHash))
+	return !exists
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/customfield"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/gallery"
@@ -126,6 +127,7 @@ func (t *ImportTask) Start(ctx context.Context) {
 	}
 
 	t.ImportSavedFilters(ctx)
+	t.ImportCustomFields(ctx)
 	t.ImportTags(ctx)
 	t.ImportPerformers(ctx)
 	t.ImportStudios(ctx)
@@ -831,3 +833,46 @@ func (t *ImportTask) importSavedFilter(ctx context.Context, savedFilterJSON *jso
 
 	return nil
 }
+
+// ImportCustomFields imports the custom field definitions. These must be
+// imported before the objects that have custom field values.
+func (t *ImportTask) ImportCustomFields(ctx context.Context) {
+	logger.Info("[custom fields] importing")
+
+	path := t.json.json.CustomFields
+	files, err := os.ReadDir(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Errorf("[custom fields] failed to read custom fields directory: %v", err)
+		}
+
+		return
+	}
+
+	r := t.repository
+
+	for i, fi := range files {
+		index := i + 1
+		customFieldJSON, err := jsonschema.LoadCustomFieldDefinitionFile(filepath.Join(path, fi.Name()))
+		if err != nil {
+			logger.Errorf("[custom fields] failed to read json: %v", err)
+			continue
+		}
+
+		logger.Progressf("[custom fields] %d of %d", index, len(files))
+
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			importer := &customfield.Importer{
+				ReaderWriter: r.CustomField,
+				Input:        *customFieldJSON,
+			}
+
+			return performImport(ctx, importer, t.DuplicateBehaviour)
+		}); err != nil {
+			logger.Errorf("[custom fields] <%s> failed to import: %v", fi.Name(), err)
+			continue
+		}
+	}
+
+	logger.Info("[custom fields] import complete")
+}
@@ -0,0 +1,135 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/tag"
+)
+
+// chapterMarkerTolerance is the distance in seconds within which an existing
+// marker is considered to have been created from a chapter.
+const chapterMarkerTolerance = 0.01
+
+// ImportChaptersTask creates scene markers from the chapters embedded in the
+// primary file of a scene. Chapters that already have a marker at the same
+// start time are skipped.
+type ImportChaptersTask struct {
+	repository models.Repository
+	Scene      models.Scene
+	// DefaultTag is the name of the primary tag used when a chapter title
+	// does not match a tag. Chapters are skipped if empty.
+	DefaultTag string
+}
+
+func (t *ImportChaptersTask) GetDescription() string {
+	return fmt.Sprintf("Importing chapters for %s", t.Scene.Path)
+}
+
+func (t *ImportChaptersTask) Start(ctx context.Context) {
+	r := t.repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		existing, err := r.SceneMarker.FindBySceneID(ctx, t.Scene.ID)
+		if err != nil {
+			return err
+		}
+
+		created := 0
+		for _, c := range t.chapters() {
+			if hasMarkerAt(existing, c.Start) {
+				continue
+			}
+
+			tagID, err := t.chapterTagID(ctx, c)
+			if err != nil {
+				return err
+			}
+			if tagID == nil {
+				logger.Debugf("[chapters] no tag found for chapter %q in %s, skipping", c.Title, t.Scene.Path)
+				continue
+			}
+
+			marker := models.NewSceneMarker()
+			marker.Title = c.Title
+			marker.Seconds = c.Start
+			marker.PrimaryTagID = *tagID
+			marker.SceneID = t.Scene.ID
+			if c.End > c.Start {
+				end := c.End
+				marker.EndSeconds = &end
+			}
+
+			if err := r.SceneMarker.Create(ctx, &marker); err != nil {
+				return fmt.Errorf("creating marker: %w", err)
+			}
+
+			existing = append(existing, &marker)
+			created++
+		}
+
+		if created > 0 {
+			logger.Infof("[chapters] created %d markers for %s", created, t.Scene.Path)
+		}
+
+		return nil
+	}); err != nil && ctx.Err() == nil {
+		logger.Errorf("error importing chapters for %s: %v", t.Scene.Path, err)
+	}
+}
+
+func (t *ImportChaptersTask) chapters() []models.VideoChapter {
+	f := t.Scene.Files.Primary()
+	if f == nil {
+		return nil
+	}
+
+	return f.Chapters
+}
+
+func (t *ImportChaptersTask) required() bool {
+	return len(t.chapters()) > 0
+}
+
+// chapterTagID returns the ID of the tag whose name or alias matches the
+// chapter title, falling back to the default tag. Returns nil if no tag is
+// found.
+func (t *ImportChaptersTask) chapterTagID(ctx context.Context, c models.VideoChapter) (*int, error) {
+	qb := t.repository.Tag
+
+	for _, name := range []string{c.Title, t.DefaultTag} {
+		if name == "" {
+			continue
+		}
+
+		found, err := qb.FindByName(ctx, name, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if found == nil {
+			found, err = tag.ByAlias(ctx, qb, name)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if found != nil {
+			return &found.ID, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func hasMarkerAt(markers []*models.SceneMarker, seconds float64) bool {
+	for _, m := range markers {
+		if math.Abs(m.Seconds-seconds) < chapterMarkerTolerance {
+			return true
+		}
+	}
+
+	return false
+}
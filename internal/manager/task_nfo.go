@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/nfo"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+type WriteNfoMetadataInput struct {
+	// Scenes to write NFO files for. NFO files are written for all scenes if
+	// empty.
+	Ids []string `json:"ids"`
+}
+
+// WriteNfo starts a job writing the NFO files of the scenes.
+func (s *Manager) WriteNfo(ctx context.Context, input WriteNfoMetadataInput) (int, error) {
+	ids, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return 0, fmt.Errorf("converting ids: %w", err)
+	}
+
+	j := &writeNfoJob{
+		repository: s.Repository,
+		sceneIDs:   ids,
+	}
+
+	return s.JobManager.Add(ctx, "Writing NFO files...", j), nil
+}
+
+// AutoWriteNfo starts a job writing the NFO files of the updated scenes, if
+// writing NFO files is enabled.
+func (s *Manager) AutoWriteNfo(ctx context.Context, sceneIDs []int) {
+	if !s.Config.GetWriteNfo() || len(sceneIDs) == 0 {
+		return
+	}
+
+	j := &writeNfoJob{
+		repository: s.Repository,
+		sceneIDs:   sceneIDs,
+	}
+
+	s.JobManager.Add(ctx, "Writing NFO files...", j)
+}
+
+type writeNfoJob struct {
+	repository models.Repository
+	// all scenes are written if empty
+	sceneIDs []int
+}
+
+func (j *writeNfoJob) Execute(ctx context.Context, progress *job.Progress) error {
+	start := time.Now()
+
+	ids := j.sceneIDs
+	if len(ids) == 0 {
+		r := j.repository
+		if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+			allPages := -1
+			result, err := r.Scene.Query(ctx, models.SceneQueryOptions{
+				QueryOptions: models.QueryOptions{
+					FindFilter: &models.FindFilterType{
+						PerPage: &allPages,
+					},
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			ids = result.IDs
+			return nil
+		}); err != nil {
+			return fmt.Errorf("finding scenes: %w", err)
+		}
+	}
+
+	progress.SetTotal(len(ids))
+
+	written := 0
+	for _, id := range ids {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		n, err := j.writeScene(ctx, id)
+		if err != nil {
+			logger.Errorf("Error writing NFO file for scene %d: %v", id, err)
+		}
+
+		written += n
+		progress.Increment()
+	}
+
+	logger.Infof("Wrote %d NFO and image files for %d scenes (%s)", written, len(ids), time.Since(start))
+	return nil
+}
+
+func (j *writeNfoJob) writeScene(ctx context.Context, id int) (int, error) {
+	var files []nfo.File
+	r := j.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		s, err := r.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if s == nil {
+			return nil
+		}
+
+		w := &nfo.Writer{
+			FileGetter:      r.File,
+			SceneReader:     r.Scene,
+			StudioReader:    r.Studio,
+			PerformerReader: r.Performer,
+			TagReader:       r.Tag,
+		}
+
+		files, err = w.Files(ctx, s)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	// files are written outside of the transaction
+	var writable []nfo.File
+	for _, f := range files {
+		if instance.FS.IsMounted(f.Path) {
+			logger.Debugf("Not writing %s: remote files are read-only", f.Path)
+			continue
+		}
+
+		writable = append(writable, f)
+	}
+
+	return nfo.WriteFiles(writable)
+}
@@ -0,0 +1,339 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scene/organise"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+type OrganiseScenesInput struct {
+	// Scenes to organise. If scene_filter is also set, only the listed
+	// scenes matching the filter are organised.
+	Ids         []string                `json:"ids"`
+	SceneFilter *models.SceneFilterType `json:"scene_filter"`
+	Template    string                  `json:"template"`
+	// Folder that generated paths are relative to. Must be within a stash
+	// path. If unset, paths are relative to the stash path containing the
+	// file.
+	Destination *string `json:"destination"`
+}
+
+// organiseSpec is a set of scenes to organise using a template.
+type organiseSpec struct {
+	template    *organise.Template
+	destination string
+	sceneFilter *models.SceneFilterType
+	sceneIDs    []int
+}
+
+func newOrganiseSpec(input OrganiseScenesInput) (*organiseSpec, error) {
+	t, err := organise.ParseTemplate(input.Template)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	ret := &organiseSpec{
+		template:    t,
+		sceneFilter: input.SceneFilter,
+	}
+
+	if input.Destination != nil && *input.Destination != "" {
+		if err := validateOrganiseDestination(*input.Destination); err != nil {
+			return nil, err
+		}
+		ret.destination = *input.Destination
+	}
+
+	if len(input.Ids) > 0 {
+		ret.sceneIDs, err = stringslice.StringSliceToIntSlice(input.Ids)
+		if err != nil {
+			return nil, fmt.Errorf("converting ids: %w", err)
+		}
+	}
+
+	if ret.sceneIDs == nil && ret.sceneFilter == nil {
+		return nil, errors.New("must specify ids or scene filter")
+	}
+
+	return ret, nil
+}
+
+func validateOrganiseDestination(destination string) error {
+	if config.StashConfigs.GetStashFromDirPath(config.GetInstance().GetStashPaths(), destination) == nil {
+		return fmt.Errorf("%s is not in the configured stash paths", destination)
+	}
+
+	return nil
+}
+
+func parseOrganiserRule(rule *config.OrganiserRule) (*organiseSpec, error) {
+	input := OrganiseScenesInput{
+		SceneFilter: &models.SceneFilterType{},
+		Template:    rule.Template,
+	}
+
+	if rule.SceneFilter != "" {
+		if err := json.Unmarshal([]byte(rule.SceneFilter), input.SceneFilter); err != nil {
+			return nil, fmt.Errorf("parsing scene filter: %w", err)
+		}
+	}
+
+	if rule.Destination != "" {
+		input.Destination = &rule.Destination
+	}
+
+	return newOrganiseSpec(input)
+}
+
+// ValidateOrganiserRules returns an error if any of the rules has an invalid
+// template, scene filter or destination.
+func ValidateOrganiserRules(rules []*config.OrganiserRule) error {
+	for i, rule := range rules {
+		if _, err := parseOrganiserRule(rule); err != nil {
+			return fmt.Errorf("organiser rule %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Manager) newOrganisePlanner() *organise.Planner {
+	r := s.Repository
+
+	var stashPaths []string
+	for _, sp := range s.Config.GetStashPaths() {
+		stashPaths = append(stashPaths, sp.Path)
+	}
+
+	return &organise.Planner{
+		Repository: organise.FieldsRepository{
+			Scene:     r.Scene,
+			File:      r.File,
+			Studio:    r.Studio,
+			Performer: r.Performer,
+			Tag:       r.Tag,
+			Group:     r.Group,
+		},
+		FS:         s.FS,
+		StashPaths: stashPaths,
+		Windows:    runtime.GOOS == "windows",
+	}
+}
+
+// PlanOrganise returns the moves that organising the scenes would make,
+// without moving any files.
+func (s *Manager) PlanOrganise(ctx context.Context, input OrganiseScenesInput) ([]*organise.Move, error) {
+	spec, err := newOrganiseSpec(input)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &OrganiseJob{
+		repository: s.Repository,
+		planner:    s.newOrganisePlanner(),
+		specs:      []*organiseSpec{spec},
+	}
+
+	return j.plan(ctx)
+}
+
+// Organise starts a job moving the scene files to the paths generated from
+// the template.
+func (s *Manager) Organise(ctx context.Context, input OrganiseScenesInput) (int, error) {
+	spec, err := newOrganiseSpec(input)
+	if err != nil {
+		return 0, err
+	}
+
+	j := &OrganiseJob{
+		repository: s.Repository,
+		planner:    s.newOrganisePlanner(),
+		specs:      []*organiseSpec{spec},
+	}
+
+	return s.JobManager.Add(ctx, "Organising scenes...", j), nil
+}
+
+// AutoOrganise starts a job organising the scenes using the organiser rules
+// that are applied automatically. Each scene is organised using the first
+// rule that matches it. No job is started if there are no such rules.
+func (s *Manager) AutoOrganise(ctx context.Context, sceneIDs []int) {
+	var specs []*organiseSpec
+	for _, rule := range s.Config.GetOrganiserRules() {
+		if !rule.AutoOrganise {
+			continue
+		}
+
+		spec, err := parseOrganiserRule(rule)
+		if err != nil {
+			logger.Errorf("invalid organiser rule: %v", err)
+			continue
+		}
+
+		spec.sceneIDs = sceneIDs
+		specs = append(specs, spec)
+	}
+
+	if len(specs) == 0 {
+		return
+	}
+
+	j := &OrganiseJob{
+		repository: s.Repository,
+		planner:    s.newOrganisePlanner(),
+		specs:      specs,
+	}
+
+	s.JobManager.Add(ctx, "Organising scenes...", j)
+}
+
+type OrganiseJob struct {
+	repository models.Repository
+	planner    *organise.Planner
+	specs      []*organiseSpec
+}
+
+func (j *OrganiseJob) Execute(ctx context.Context, progress *job.Progress) error {
+	var moves []*organise.Move
+	var err error
+	progress.ExecuteTask("Planning moves", func() {
+		moves, err = j.plan(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	progress.SetTotal(len(moves))
+
+	r := j.repository
+	executor := &organise.Executor{
+		TxnManager: r.TxnManager,
+		File:       r.File,
+		Folder:     r.Folder,
+	}
+
+	var failed int
+	for _, m := range moves {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		progress.ExecuteTask("Moving "+m.OldPath, func() {
+			if err := executor.Execute(ctx, *m); err != nil {
+				logger.Errorf("error moving %s to %s: %v", m.OldPath, m.NewPath, err)
+				failed++
+				return
+			}
+
+			logger.Infof("Moved %s to %s", m.OldPath, m.NewPath)
+		})
+
+		progress.Increment()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to move %d of %d files", failed, len(moves))
+	}
+
+	logger.Infof("Organised %d files", len(moves))
+	return nil
+}
+
+// plan returns the moves of the scenes matched by the specs. Scenes matched
+// by more than one spec are moved using the first.
+func (j *OrganiseJob) plan(ctx context.Context) ([]*organise.Move, error) {
+	var ret []*organise.Move
+	planned := make(map[int]bool)
+
+	r := j.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		for _, spec := range j.specs {
+			j.planner.Template = spec.template
+			j.planner.Destination = spec.destination
+
+			if err := j.findScenes(ctx, spec, func(s *models.Scene) error {
+				if planned[s.ID] {
+					return nil
+				}
+				planned[s.ID] = true
+
+				m, err := j.planner.Plan(ctx, s)
+				if err != nil {
+					// don't fail the whole plan because of one scene
+					logger.Warnf("cannot organise scene %d: %v", s.ID, err)
+					return nil
+				}
+
+				if m != nil {
+					ret = append(ret, m)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (j *OrganiseJob) findScenes(ctx context.Context, spec *organiseSpec, fn func(s *models.Scene) error) error {
+	qb := j.repository.Scene
+
+	if spec.sceneIDs == nil {
+		return scene.BatchProcess(ctx, qb, spec.sceneFilter, nil, fn)
+	}
+
+	if spec.sceneFilter == nil {
+		scenes, err := qb.FindMany(ctx, spec.sceneIDs)
+		if err != nil {
+			return fmt.Errorf("finding scenes: %w", err)
+		}
+
+		for _, s := range scenes {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// check each scene against the filter
+	for _, id := range spec.sceneIDs {
+		filter := &models.SceneFilterType{
+			ID: &models.IntCriterionInput{
+				Value:    id,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+		filter.And = spec.sceneFilter
+
+		scenes, err := scene.Query(ctx, qb, filter, nil)
+		if err != nil {
+			return fmt.Errorf("querying scene %d: %w", id, err)
+		}
+
+		for _, s := range scenes {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
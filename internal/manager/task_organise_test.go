@@ -0,0 +1,104 @@
+//go:build integration
+// +build integration
+
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/organise"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganiseJob_plan(t *testing.T) {
+	ctx := context.Background()
+	stash := t.TempDir()
+
+	m := newTrashTestManager(t, "")
+	r := m.Repository
+
+	// scenes are organised using the first rule whose filter matches them
+	rules := []*config.OrganiserRule{
+		{
+			SceneFilter: `{"title": {"value": "match", "modifier": "EQUALS"}}`,
+			Template:    "matched/{title}",
+		},
+		{
+			Template: "other/{title}",
+		},
+	}
+
+	var ids []int
+	for _, title := range []string{"match", "other"} {
+		if err := r.WithTxn(ctx, func(ctx context.Context) error {
+			s, err := createTrashTestScene(ctx, r, filepath.Join(stash, title, "video.mp4"))
+			if err != nil {
+				return err
+			}
+
+			partial := models.NewScenePartial()
+			partial.Title = models.NewOptionalString(title)
+			if _, err := r.Scene.UpdatePartial(ctx, s.ID, partial); err != nil {
+				return err
+			}
+
+			ids = append(ids, s.ID)
+			return nil
+		}); err != nil {
+			t.Fatalf("creating scene: %v", err)
+		}
+	}
+
+	var specs []*organiseSpec
+	for _, rule := range rules {
+		spec, err := parseOrganiserRule(rule)
+		if err != nil {
+			t.Fatalf("parsing rule: %v", err)
+		}
+
+		spec.sceneIDs = ids
+		specs = append(specs, spec)
+	}
+
+	j := &OrganiseJob{
+		repository: r,
+		planner: &organise.Planner{
+			Repository: organise.FieldsRepository{
+				Scene:     r.Scene,
+				File:      r.File,
+				Studio:    r.Studio,
+				Performer: r.Performer,
+				Tag:       r.Tag,
+				Group:     r.Group,
+			},
+			FS:         &file.OsFS{},
+			StashPaths: []string{stash},
+		},
+		specs: specs,
+	}
+
+	got, err := j.plan(ctx)
+	if err != nil {
+		t.Fatalf("plan() error = %v", err)
+	}
+
+	assert.Equal(t, []*organise.Move{
+		{
+			SceneID: ids[0],
+			FileID:  1,
+			OldPath: filepath.Join(stash, "match", "video.mp4"),
+			NewPath: filepath.Join(stash, "matched", "match.mp4"),
+		},
+		{
+			SceneID: ids[1],
+			FileID:  2,
+			OldPath: filepath.Join(stash, "other", "video.mp4"),
+			NewPath: filepath.Join(stash, "other", "other.mp4"),
+		},
+	}, got)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/stashapp/stash/pkg/models/paths"
 	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/scene/generate"
+	"github.com/stashapp/stash/pkg/scene/nfo"
 	"github.com/stashapp/stash/pkg/txn"
 )
 
@@ -251,6 +252,7 @@ type scanFilter struct {
 
 	stashPaths        config.StashConfigs
 	generatedPath     string
+	trashPath         string
 	videoExcludeRegex []*regexp.Regexp
 	imageExcludeRegex []*regexp.Regexp
 	minModTime        time.Time
@@ -264,6 +266,7 @@ func newScanFilter(c *config.Config, repo models.Repository, minModTime time.Tim
 		CaptionUpdater:    repo.File,
 		stashPaths:        c.GetStashPaths(),
 		generatedPath:     c.GetGeneratedPath(),
+		trashPath:         c.GetTrashPath(),
 		videoExcludeRegex: generateRegexps(c.GetExcludes()),
 		imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
 		minModTime:        minModTime,
@@ -276,6 +279,11 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
+	if f.trashPath != "" && fsutil.IsPathInDir(f.trashPath, path) {
+		logger.Debugf("Skipping %q as it is in the trash folder", path)
+		return false
+	}
+
 	// exit early on cutoff
 	if info.Mode().IsRegular() && info.ModTime().Before(f.minModTime) {
 		return false
@@ -359,6 +367,19 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 	r := mgr.Repository
 	pluginCache := mgr.PluginCache
 
+	var nfoReader scene.NfoReader
+	if c.GetReadNfo() {
+		nfoReader = &nfo.Reader{
+			FS:              mgr.FS,
+			VideoExtensions: c.GetVideoExtensions(),
+			SceneUpdater:    r.Scene,
+			StudioWriter:    r.Studio,
+			PerformerWriter: r.Performer,
+			TagWriter:       r.Tag,
+			Precedence:      c.GetNfoPrecedence(),
+		}
+	}
+
 	return []file.Handler{
 		&file.FilteredHandler{
 			Filter: file.FilterFunc(imageFileFilter),
@@ -396,6 +417,7 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				CreatorUpdater: r.Scene,
 				CaptionUpdater: r.File,
 				PluginCache:    pluginCache,
+				NfoReader:      nfoReader,
 				ScanGenerator: &sceneGenerators{
 					input:               options,
 					taskQueue:           taskQueue,
@@ -442,6 +464,25 @@ func (g *imageGenerators) Generate(ctx context.Context, i *models.Image, f model
 		taskThumbnail.Start(ctx)
 	}
 
+	if imageFile, ok := f.(*models.ImageFile); ok && t.ScanGeneratePhashes {
+		progress.AddTotal(1)
+		phashFn := func(ctx context.Context) {
+			taskPhash := GenerateImagePhashTask{
+				repository: GetInstance().Repository,
+				File:       imageFile,
+				Overwrite:  overwrite,
+			}
+			taskPhash.Start(ctx)
+			progress.Increment()
+		}
+
+		if g.sequentialScanning {
+			phashFn(ctx)
+		} else {
+			g.taskQueue.Add(fmt.Sprintf("Generating phash for %s", path), phashFn)
+		}
+	}
+
 	// avoid adding a task if the file isn't a video file
 	_, isVideo := f.(*models.VideoFile)
 	if isVideo && t.ScanGenerateClipPreviews {
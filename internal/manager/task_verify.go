@@ -0,0 +1,292 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"time"
+
+	"github.com/stashapp/stash/pkg/hash/md5"
+	"github.com/stashapp/stash/pkg/hash/oshash"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// verifyFingerprintTypes are the fingerprint types that are re-calculated
+// when verifying a file, in order of cost.
+var verifyFingerprintTypes = []string{
+	models.FingerprintTypeOshash,
+	models.FingerprintTypeMD5,
+}
+
+type verifyResult int
+
+const (
+	verifyResultOK verifyResult = iota
+	verifyResultMismatch
+	verifyResultUnreadable
+	// the file was modified since it was last scanned
+	verifyResultChanged
+	verifyResultMissing
+	// the file has no fingerprints to compare against
+	verifyResultSkipped
+)
+
+type verifyJob struct {
+	repository models.Repository
+	fs         models.FS
+	input      VerifyMetadataInput
+}
+
+func (j *verifyJob) Execute(ctx context.Context, progress *job.Progress) error {
+	logger.Infof("Starting verification of files")
+	start := time.Now()
+
+	progress.Indefinite()
+
+	ids, err := j.getFileIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("finding files to verify: %w", err)
+	}
+
+	progress.SetTotal(len(ids))
+
+	counts := make(map[verifyResult]int)
+	for _, id := range ids {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return nil
+		}
+
+		result, err := j.verifyFile(ctx, id, progress)
+		if err != nil {
+			logger.Errorf("Error verifying file %d: %v", id, err)
+		} else {
+			counts[result]++
+		}
+
+		progress.Increment()
+	}
+
+	elapsed := time.Since(start)
+	logger.Infof("Finished verifying %d files (%s): %d ok, %d hash mismatches, %d unreadable, %d modified since last scan, %d missing, %d without fingerprints",
+		len(ids), elapsed,
+		counts[verifyResultOK],
+		counts[verifyResultMismatch],
+		counts[verifyResultUnreadable],
+		counts[verifyResultChanged],
+		counts[verifyResultMissing],
+		counts[verifyResultSkipped],
+	)
+
+	if n := counts[verifyResultMismatch] + counts[verifyResultUnreadable]; n > 0 {
+		logger.Warnf("Found %d files with integrity issues", n)
+	}
+
+	return nil
+}
+
+// getFileIDs returns the IDs of the files to verify. If a sample percentage
+// is set, the least recently verified files are returned first.
+func (j *verifyJob) getFileIDs(ctx context.Context) ([]models.FileID, error) {
+	var ret []models.FileID
+	r := j.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		fileFilter := models.PathsFileFilter(j.input.Paths)
+
+		perPage := -1
+		if p := j.input.SamplePercentage; p != nil {
+			countOnly := 0
+			result, err := r.File.Query(ctx, models.FileQueryOptions{
+				QueryOptions: models.QueryOptions{
+					FindFilter: &models.FindFilterType{
+						PerPage: &countOnly,
+					},
+					Count: true,
+				},
+				FileFilter: fileFilter,
+			})
+			if err != nil {
+				return err
+			}
+
+			perPage = int(math.Ceil(float64(result.Count) * *p / 100))
+			if perPage == 0 {
+				return nil
+			}
+		}
+
+		sort := "verified_at"
+		result, err := r.File.Query(ctx, models.FileQueryOptions{
+			QueryOptions: models.QueryOptions{
+				FindFilter: &models.FindFilterType{
+					PerPage: &perPage,
+					Sort:    &sort,
+				},
+			},
+			FileFilter: fileFilter,
+		})
+		if err != nil {
+			return err
+		}
+
+		ret = result.IDs
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (j *verifyJob) verifyFile(ctx context.Context, id models.FileID, progress *job.Progress) (verifyResult, error) {
+	var f *models.BaseFile
+	r := j.repository
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		files, err := r.File.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if len(files) > 0 {
+			f = files[0].Base()
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	// deleted since the job started
+	if f == nil {
+		return verifyResultMissing, nil
+	}
+
+	var (
+		result verifyResult
+		issue  *models.FileIntegrityIssue
+	)
+	progress.ExecuteTask("Verifying "+f.Path, func() {
+		result, issue = j.checkFile(f)
+	})
+
+	switch result {
+	case verifyResultMismatch:
+		logger.Warnf("Fingerprint mismatch for %s: %s", f.Path, issue.Message)
+	case verifyResultUnreadable:
+		logger.Warnf("Could not read %s: %s", f.Path, issue.Message)
+	case verifyResultChanged:
+		logger.Debugf("Skipping %s: modified since last scan", f.Path)
+	case verifyResultMissing:
+		logger.Debugf("Skipping %s: file not found", f.Path)
+	case verifyResultSkipped:
+		logger.Debugf("Skipping %s: no fingerprints to verify", f.Path)
+	}
+
+	now := time.Now()
+	if issue != nil {
+		issue.DetectedAt = now
+	}
+
+	// the verification time is recorded for every result, so that sampled
+	// runs move on to other files. Any issue is cleared if the file has
+	// changed, since it will be fingerprinted again when it is scanned, but
+	// kept if the file could not be checked.
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		if result == verifyResultMissing || result == verifyResultSkipped {
+			var err error
+			issue, err = r.File.GetIntegrityIssue(ctx, f.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return r.File.SetIntegrity(ctx, models.FileIntegrity{
+			FileID:     f.ID,
+			VerifiedAt: now,
+			Issue:      issue,
+		})
+	}); err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// checkFile re-calculates the fingerprints of the file and compares them to
+// the stored fingerprints. Files that have been modified since they were last
+// scanned are not checked, since their fingerprints are expected to differ.
+func (j *verifyJob) checkFile(f *models.BaseFile) (verifyResult, *models.FileIntegrityIssue) {
+	unreadable := func(err error) (verifyResult, *models.FileIntegrityIssue) {
+		return verifyResultUnreadable, &models.FileIntegrityIssue{
+			Type:    models.FileIntegrityIssueTypeUnreadable,
+			Message: err.Error(),
+		}
+	}
+
+	info, err := f.Info(j.fs)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return verifyResultMissing, nil
+		}
+		return unreadable(err)
+	}
+
+	if !info.ModTime().Truncate(time.Second).Equal(f.ModTime) || info.Size() != f.Size {
+		return verifyResultChanged, nil
+	}
+
+	result := verifyResultSkipped
+	for _, t := range verifyFingerprintTypes {
+		fp := f.Fingerprints.For(t)
+		if fp == nil {
+			continue
+		}
+
+		hash, err := j.calculateFingerprint(f, t)
+		if err != nil {
+			return unreadable(err)
+		}
+
+		if hash != fp.Value() {
+			return verifyResultMismatch, &models.FileIntegrityIssue{
+				Type:    models.FileIntegrityIssueTypeHashMismatch,
+				Message: fmt.Sprintf("%s is %s, expected %s", t, hash, fp.Value()),
+			}
+		}
+
+		result = verifyResultOK
+	}
+
+	return result, nil
+}
+
+func (j *verifyJob) calculateFingerprint(f *models.BaseFile, fpType string) (string, error) {
+	r, err := f.Open(j.fs)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer r.Close()
+
+	switch fpType {
+	case models.FingerprintTypeMD5:
+		return md5.FromReader(r)
+	case models.FingerprintTypeOshash:
+		rs, ok := r.(io.ReadSeeker)
+		if !ok {
+			// files in zips are not seekable
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return "", err
+			}
+			rs = bytes.NewReader(data)
+		}
+		return oshash.FromReader(rs, f.Size)
+	}
+
+	return "", fmt.Errorf("unsupported fingerprint type %s", fpType)
+}
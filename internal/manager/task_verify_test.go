@@ -0,0 +1,111 @@
+//go:build integration
+// +build integration
+
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// executeJob runs e using a job manager, returning when it has finished.
+func executeJob(ctx context.Context, e job.JobExec) error {
+	m := job.NewManager()
+	defer m.Stop()
+
+	done := make(chan error, 1)
+	m.Add(ctx, "test", job.MakeJobExec(func(ctx context.Context, progress *job.Progress) error {
+		err := e.Execute(ctx, progress)
+		done <- err
+		return err
+	}))
+
+	return <-done
+}
+
+func TestVerifyJob_sampled(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	m := newTrashTestManager(t, "")
+	r := m.Repository
+
+	// files without fingerprints are skipped, and missing files are not
+	// found, but both are recorded as verified
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		folder := &models.Folder{
+			Path: dir,
+		}
+		if err := r.Folder.Create(ctx, folder); err != nil {
+			return err
+		}
+
+		for i, name := range []string{"a.mp4", "b.mp4", "c.mp4", "d.mp4"} {
+			f := &models.BaseFile{
+				Basename:       name,
+				ParentFolderID: folder.ID,
+				Size:           4,
+			}
+
+			if i%2 == 0 {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+					return err
+				}
+
+				info, err := os.Stat(path)
+				if err != nil {
+					return err
+				}
+				f.ModTime = info.ModTime().Truncate(time.Second)
+			}
+
+			if err := r.File.Create(ctx, f); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("creating files: %v", err)
+	}
+
+	sample := 50.0
+	j := &verifyJob{
+		repository: r,
+		fs:         &file.OsFS{},
+		input: VerifyMetadataInput{
+			SamplePercentage: &sample,
+		},
+	}
+
+	seen := make(map[models.FileID]bool)
+	for run := 0; run < 2; run++ {
+		ids, err := j.getFileIDs(ctx)
+		if err != nil {
+			t.Fatalf("getFileIDs() error = %v", err)
+		}
+
+		if len(ids) != 2 {
+			t.Fatalf("run %d verified %d files, want 2", run, len(ids))
+		}
+
+		for _, id := range ids {
+			if seen[id] {
+				t.Errorf("run %d verified file %d again", run, id)
+			}
+			seen[id] = true
+		}
+
+		if err := executeJob(ctx, j); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+}
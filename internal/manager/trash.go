@@ -0,0 +1,849 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/gallery"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// trashPurgeInterval is the time between checks for expired trash items.
+const trashPurgeInterval = 24 * time.Hour
+
+// trashSnapshot is the content of a trash item snapshot. Objects and file
+// entries are stored in the export JSON format, so that they can be restored
+// using the importers.
+type trashSnapshot struct {
+	Scene   *jsonschema.Scene   `json:"scene,omitempty"`
+	Image   *jsonschema.Image   `json:"image,omitempty"`
+	Gallery *jsonschema.Gallery `json:"gallery,omitempty"`
+	// Images are the images in a folder-based gallery, which are deleted
+	// along with the gallery.
+	Images []*jsonschema.Image `json:"images,omitempty"`
+	Files  []json.RawMessage   `json:"files,omitempty"`
+
+	// objects that a deleted file belonged to
+	SceneIDs   []int `json:"scene_ids,omitempty"`
+	ImageIDs   []int `json:"image_ids,omitempty"`
+	GalleryIDs []int `json:"gallery_ids,omitempty"`
+}
+
+func (s *trashSnapshot) addFile(entry jsonschema.DirEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding file %s: %w", entry.DirEntry().Path, err)
+	}
+
+	s.Files = append(s.Files, data)
+	return nil
+}
+
+// TrashRecorder adds objects to the trash as they are destroyed. Files
+// deleted using its Deleter are moved to the trash directory. If the trash
+// is disabled, objects are destroyed without being recorded and files are
+// deleted permanently.
+type TrashRecorder struct {
+	Deleter *file.Deleter
+
+	repository models.Repository
+	enabled    bool
+}
+
+func (s *Manager) NewTrashRecorder() *TrashRecorder {
+	trashPath := s.Config.GetTrashPath()
+
+	deleter := file.NewDeleter()
+	deleter.TrashPath = trashPath
+
+	return &TrashRecorder{
+		Deleter:    deleter,
+		repository: s.Repository,
+		enabled:    trashPath != "",
+	}
+}
+
+// Scene snapshots the scene, calls destroy and adds the scene to the trash.
+// Must be called within a write transaction.
+func (t *TrashRecorder) Scene(ctx context.Context, s *models.Scene, destroy func() error) error {
+	if !t.enabled {
+		return destroy()
+	}
+
+	snapshot, err := t.snapshotScene(ctx, s)
+	if err != nil {
+		return fmt.Errorf("snapshotting scene %d: %w", s.ID, err)
+	}
+
+	if err := destroy(); err != nil {
+		return err
+	}
+
+	return t.add(ctx, models.TrashObjectTypeScene, s.DisplayName(), snapshot)
+}
+
+// Image snapshots the image, calls destroy and adds the image to the trash.
+// Must be called within a write transaction.
+func (t *TrashRecorder) Image(ctx context.Context, i *models.Image, destroy func() error) error {
+	if !t.enabled {
+		return destroy()
+	}
+
+	imageJSON, files, err := t.imageJSON(ctx, i)
+	if err != nil {
+		return fmt.Errorf("snapshotting image %d: %w", i.ID, err)
+	}
+
+	snapshot := &trashSnapshot{Image: imageJSON}
+	for _, f := range files {
+		if err := snapshot.addFile(fileToJSON(f)); err != nil {
+			return err
+		}
+	}
+
+	if err := destroy(); err != nil {
+		return err
+	}
+
+	return t.add(ctx, models.TrashObjectTypeImage, i.DisplayName(), snapshot)
+}
+
+// Gallery snapshots the gallery, calls destroy and adds the gallery to the
+// trash. If deleteFile is true, the images in a folder-based gallery are
+// included in the snapshot. Must be called within a write transaction.
+func (t *TrashRecorder) Gallery(ctx context.Context, g *models.Gallery, deleteFile bool, destroy func() error) error {
+	if !t.enabled {
+		return destroy()
+	}
+
+	snapshot, err := t.snapshotGallery(ctx, g, deleteFile)
+	if err != nil {
+		return fmt.Errorf("snapshotting gallery %d: %w", g.ID, err)
+	}
+
+	if err := destroy(); err != nil {
+		return err
+	}
+
+	return t.add(ctx, models.TrashObjectTypeGallery, g.DisplayName(), snapshot)
+}
+
+// File snapshots the file and the objects it belongs to, calls destroy and
+// adds the file to the trash. Must be called within a write transaction.
+func (t *TrashRecorder) File(ctx context.Context, f models.File, destroy func() error) error {
+	if !t.enabled {
+		return destroy()
+	}
+
+	snapshot, err := t.snapshotFile(ctx, f)
+	if err != nil {
+		return fmt.Errorf("snapshotting file %s: %w", f.Base().Path, err)
+	}
+
+	if err := destroy(); err != nil {
+		return err
+	}
+
+	return t.add(ctx, models.TrashObjectTypeFile, f.Base().Path, snapshot)
+}
+
+func (t *TrashRecorder) add(ctx context.Context, objectType models.TrashObjectType, name string, snapshot *trashSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+
+	item := &models.TrashItem{
+		ObjectType: objectType,
+		Name:       name,
+		Files:      t.Deleter.TakeTrashed(),
+		Snapshot:   data,
+		DeletedAt:  time.Now(),
+	}
+
+	if err := t.repository.Trash.Create(ctx, item); err != nil {
+		return fmt.Errorf("adding %s to trash: %w", name, err)
+	}
+
+	return nil
+}
+
+func (t *TrashRecorder) snapshotScene(ctx context.Context, s *models.Scene) (*trashSnapshot, error) {
+	r := t.repository
+
+	if err := s.LoadRelationships(ctx, r.Scene); err != nil {
+		return nil, fmt.Errorf("loading scene relationships: %w", err)
+	}
+
+	ret, err := scene.ToBasicJSON(ctx, r.Scene, s)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Studio, err = scene.GetStudioName(ctx, r.Studio, s)
+	if err != nil {
+		return nil, fmt.Errorf("getting studio name: %w", err)
+	}
+
+	galleries, err := r.Gallery.FindBySceneID(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting galleries: %w", err)
+	}
+
+	for _, g := range galleries {
+		if err := g.LoadFiles(ctx, r.Gallery); err != nil {
+			return nil, fmt.Errorf("getting gallery files: %w", err)
+		}
+	}
+
+	ret.Galleries = gallery.GetRefs(galleries)
+
+	ret.ResumeTime = s.ResumeTime
+	ret.PlayDuration = s.PlayDuration
+
+	performers, err := r.Performer.FindBySceneID(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting performers: %w", err)
+	}
+
+	ret.Performers = performer.GetNames(performers)
+
+	ret.Tags, err = scene.GetTagNames(ctx, r.Tag, s)
+	if err != nil {
+		return nil, fmt.Errorf("getting tag names: %w", err)
+	}
+
+	ret.Markers, err = scene.GetSceneMarkersJSON(ctx, r.SceneMarker, r.Tag, s)
+	if err != nil {
+		return nil, fmt.Errorf("getting markers: %w", err)
+	}
+
+	ret.Groups, err = scene.GetSceneGroupsJSON(ctx, r.Group, s)
+	if err != nil {
+		return nil, fmt.Errorf("getting groups: %w", err)
+	}
+
+	ret.CustomFields, err = r.Scene.GetCustomFields(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+
+	snapshot := &trashSnapshot{Scene: ret}
+	for _, f := range s.Files.List() {
+		if err := snapshot.addFile(fileToJSON(f)); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (t *TrashRecorder) imageJSON(ctx context.Context, i *models.Image) (*jsonschema.Image, []models.File, error) {
+	r := t.repository
+
+	if err := i.LoadFiles(ctx, r.Image); err != nil {
+		return nil, nil, fmt.Errorf("getting image files: %w", err)
+	}
+
+	if err := i.LoadURLs(ctx, r.Image); err != nil {
+		return nil, nil, fmt.Errorf("getting image urls: %w", err)
+	}
+
+	ret := image.ToBasicJSON(i)
+
+	var err error
+	ret.Studio, err = image.GetStudioName(ctx, r.Studio, i)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting studio name: %w", err)
+	}
+
+	galleries, err := r.Gallery.FindByImageID(ctx, i.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting galleries: %w", err)
+	}
+
+	for _, g := range galleries {
+		if err := g.LoadFiles(ctx, r.Gallery); err != nil {
+			return nil, nil, fmt.Errorf("getting gallery files: %w", err)
+		}
+	}
+
+	ret.Galleries = gallery.GetRefs(galleries)
+
+	performers, err := r.Performer.FindByImageID(ctx, i.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting performers: %w", err)
+	}
+
+	ret.Performers = performer.GetNames(performers)
+
+	tags, err := r.Tag.FindByImageID(ctx, i.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting tags: %w", err)
+	}
+
+	ret.Tags = tag.GetNames(tags)
+
+	return ret, i.Files.List(), nil
+}
+
+func (t *TrashRecorder) snapshotGallery(ctx context.Context, g *models.Gallery, deleteFile bool) (*trashSnapshot, error) {
+	r := t.repository
+
+	if err := g.LoadFiles(ctx, r.Gallery); err != nil {
+		return nil, fmt.Errorf("getting gallery files: %w", err)
+	}
+
+	if err := g.LoadURLs(ctx, r.Gallery); err != nil {
+		return nil, fmt.Errorf("getting gallery urls: %w", err)
+	}
+
+	ret, err := gallery.ToBasicJSON(g)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Studio, err = gallery.GetStudioName(ctx, r.Studio, g)
+	if err != nil {
+		return nil, fmt.Errorf("getting studio name: %w", err)
+	}
+
+	performers, err := r.Performer.FindByGalleryID(ctx, g.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting performers: %w", err)
+	}
+
+	ret.Performers = performer.GetNames(performers)
+
+	tags, err := r.Tag.FindByGalleryID(ctx, g.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting tags: %w", err)
+	}
+
+	ret.Tags = tag.GetNames(tags)
+
+	ret.Chapters, err = gallery.GetGalleryChaptersJSON(ctx, r.GalleryChapter, g)
+	if err != nil {
+		return nil, fmt.Errorf("getting chapters: %w", err)
+	}
+
+	ret.CustomFields, err = r.Gallery.GetCustomFields(ctx, g.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom fields: %w", err)
+	}
+
+	snapshot := &trashSnapshot{Gallery: ret}
+	for _, f := range g.Files.List() {
+		if err := snapshot.addFile(fileToJSON(f)); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.FolderID == nil {
+		return snapshot, nil
+	}
+
+	folder, err := r.Folder.Find(ctx, *g.FolderID)
+	if err != nil {
+		return nil, fmt.Errorf("getting gallery folder: %w", err)
+	}
+
+	if folder != nil {
+		if err := snapshot.addFile(folderToJSON(*folder)); err != nil {
+			return nil, err
+		}
+	}
+
+	if !deleteFile {
+		return snapshot, nil
+	}
+
+	// the images in the folder are deleted along with the gallery. Images
+	// with files elsewhere are kept, so are not included.
+	images, err := r.Image.FindByFolderID(ctx, *g.FolderID)
+	if err != nil {
+		return nil, fmt.Errorf("getting gallery images: %w", err)
+	}
+
+	for _, i := range images {
+		imageJSON, files, err := t.imageJSON(ctx, i)
+		if err != nil {
+			return nil, fmt.Errorf("snapshotting image %d: %w", i.ID, err)
+		}
+
+		if len(files) > 1 {
+			continue
+		}
+
+		snapshot.Images = append(snapshot.Images, imageJSON)
+		for _, f := range files {
+			if err := snapshot.addFile(fileToJSON(f)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (t *TrashRecorder) snapshotFile(ctx context.Context, f models.File) (*trashSnapshot, error) {
+	r := t.repository
+	fileID := f.Base().ID
+
+	snapshot := &trashSnapshot{}
+	if err := snapshot.addFile(fileToJSON(f)); err != nil {
+		return nil, err
+	}
+
+	scenes, err := r.Scene.FindByFileID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scenes: %w", err)
+	}
+	for _, s := range scenes {
+		snapshot.SceneIDs = append(snapshot.SceneIDs, s.ID)
+	}
+
+	images, err := r.Image.FindByFileID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("getting images: %w", err)
+	}
+	for _, i := range images {
+		snapshot.ImageIDs = append(snapshot.ImageIDs, i.ID)
+	}
+
+	galleries, err := r.Gallery.FindByFileID(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("getting galleries: %w", err)
+	}
+	for _, g := range galleries {
+		snapshot.GalleryIDs = append(snapshot.GalleryIDs, g.ID)
+	}
+
+	return snapshot, nil
+}
+
+// RestoreFromTrash moves the files of the trash items back to their original
+// paths and recreates the deleted objects and files. Each item is restored in
+// its own transaction. Files in restored zip files are scanned afterwards,
+// since they are not included in snapshots.
+func (s *Manager) RestoreFromTrash(ctx context.Context, ids []int) error {
+	var rescanPaths []string
+	zipExt := s.Config.GetGalleryExtensions()
+
+	for _, id := range ids {
+		var item *models.TrashItem
+		if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+			var err error
+			item, err = s.Repository.Trash.Find(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if item == nil {
+				return fmt.Errorf("trash item with id %d not found", id)
+			}
+
+			return s.restoreTrashItem(ctx, item)
+		}); err != nil {
+			return fmt.Errorf("restoring trash item %d: %w", id, err)
+		}
+
+		logger.Infof("Restored %s from trash", item.Name)
+
+		for _, f := range item.Files {
+			if fsutil.MatchExtension(f.OriginalPath, zipExt) {
+				rescanPaths = append(rescanPaths, f.OriginalPath)
+			}
+		}
+	}
+
+	if len(rescanPaths) > 0 {
+		input := ScanMetadataInput{
+			Paths: rescanPaths,
+		}
+		input.Rescan = true
+
+		if _, err := s.Scan(ctx, input); err != nil {
+			logger.Errorf("error starting scan of restored files: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Manager) restoreTrashItem(ctx context.Context, item *models.TrashItem) error {
+	r := s.Repository
+
+	var snapshot trashSnapshot
+	if err := json.Unmarshal(item.Snapshot, &snapshot); err != nil {
+		return fmt.Errorf("decoding snapshot: %w", err)
+	}
+
+	mover := file.NewMover(r.File, r.Folder)
+	mover.RegisterHooks(ctx)
+
+	for _, f := range item.Files {
+		if _, err := os.Stat(f.TrashPath); err != nil {
+			return fmt.Errorf("getting info for %s: %w", f.TrashPath, err)
+		}
+
+		folderPath := filepath.Dir(f.OriginalPath)
+		if err := mover.CreateFolderHierarchy(folderPath); err != nil {
+			return fmt.Errorf("creating folder hierarchy %s in filesystem: %w", folderPath, err)
+		}
+
+		if err := mover.MoveSidecar(f.TrashPath, f.OriginalPath); err != nil {
+			return fmt.Errorf("restoring %s: %w", f.OriginalPath, err)
+		}
+	}
+
+	if err := s.restoreTrashFiles(ctx, snapshot.Files); err != nil {
+		return err
+	}
+
+	if err := s.restoreTrashObjects(ctx, &snapshot); err != nil {
+		return err
+	}
+
+	if err := r.Trash.Destroy(ctx, item.ID); err != nil {
+		return err
+	}
+
+	txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+		removeTrashDirs(item)
+	})
+
+	return nil
+}
+
+// restoreTrashFiles recreates the file entries that no longer exist.
+// Folders are created first, then files, then the files within zip files.
+func (s *Manager) restoreTrashFiles(ctx context.Context, files []json.RawMessage) error {
+	r := s.Repository
+
+	var entries []jsonschema.DirEntry
+	for _, data := range files {
+		entry, err := jsonschema.ParseDirEntry(data)
+		if err != nil {
+			return fmt.Errorf("decoding file: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	order := func(e jsonschema.DirEntry) int {
+		switch {
+		case !e.IsFile():
+			return 0
+		case e.DirEntry().ZipFile == "":
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	slices.SortStableFunc(entries, func(a, b jsonschema.DirEntry) int {
+		return order(a) - order(b)
+	})
+
+	for _, entry := range entries {
+		fileImporter := &file.Importer{
+			ReaderWriter: r.File,
+			FolderStore:  r.Folder,
+			Input:        entry,
+		}
+
+		// existing entries are kept
+		if err := performImport(ctx, fileImporter, ImportDuplicateEnumIgnore); err != nil {
+			if errors.Is(err, file.ErrZipFileNotExist) {
+				logger.Warnf("Not restoring %s: %v", entry.DirEntry().Path, err)
+				continue
+			}
+
+			return fmt.Errorf("restoring file %s: %w", entry.DirEntry().Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Manager) restoreTrashObjects(ctx context.Context, snapshot *trashSnapshot) error {
+	r := s.Repository
+
+	// deleted objects are recreated, so refs to missing objects are ignored
+	const missingRefBehaviour = models.ImportMissingRefEnumIgnore
+
+	if snapshot.Scene != nil {
+		sceneImporter := &scene.Importer{
+			ReaderWriter: r.Scene,
+			Input:        *snapshot.Scene,
+			FileFinder:   r.File,
+
+			FileNamingAlgorithm: s.Config.GetVideoFileNamingAlgorithm(),
+			MissingRefBehaviour: missingRefBehaviour,
+
+			GalleryFinder:   r.Gallery,
+			GroupWriter:     r.Group,
+			PerformerWriter: r.Performer,
+			StudioWriter:    r.Studio,
+			TagWriter:       r.Tag,
+		}
+
+		if err := performImport(ctx, sceneImporter, ImportDuplicateEnumFail); err != nil {
+			return err
+		}
+
+		for _, m := range snapshot.Scene.Markers {
+			markerImporter := &scene.MarkerImporter{
+				SceneID:             sceneImporter.ID,
+				Input:               m,
+				MissingRefBehaviour: missingRefBehaviour,
+				ReaderWriter:        r.SceneMarker,
+				TagWriter:           r.Tag,
+			}
+
+			if err := performImport(ctx, markerImporter, ImportDuplicateEnumFail); err != nil {
+				return err
+			}
+		}
+	}
+
+	if snapshot.Gallery != nil {
+		galleryImporter := &gallery.Importer{
+			ReaderWriter:        r.Gallery,
+			FolderFinder:        r.Folder,
+			FileFinder:          r.File,
+			PerformerWriter:     r.Performer,
+			StudioWriter:        r.Studio,
+			TagWriter:           r.Tag,
+			Input:               *snapshot.Gallery,
+			MissingRefBehaviour: missingRefBehaviour,
+		}
+
+		if err := performImport(ctx, galleryImporter, ImportDuplicateEnumFail); err != nil {
+			return err
+		}
+
+		for _, m := range snapshot.Gallery.Chapters {
+			chapterImporter := &gallery.ChapterImporter{
+				GalleryID:           galleryImporter.ID,
+				Input:               m,
+				MissingRefBehaviour: missingRefBehaviour,
+				ReaderWriter:        r.GalleryChapter,
+			}
+
+			if err := performImport(ctx, chapterImporter, ImportDuplicateEnumFail); err != nil {
+				return err
+			}
+		}
+	}
+
+	images := snapshot.Images
+	if snapshot.Image != nil {
+		images = append(images, snapshot.Image)
+	}
+
+	for _, imageJSON := range images {
+		imageImporter := &image.Importer{
+			ReaderWriter: r.Image,
+			FileFinder:   r.File,
+			Input:        *imageJSON,
+
+			MissingRefBehaviour: missingRefBehaviour,
+
+			GalleryFinder:   r.Gallery,
+			PerformerWriter: r.Performer,
+			StudioWriter:    r.Studio,
+			TagWriter:       r.Tag,
+		}
+
+		if err := performImport(ctx, imageImporter, ImportDuplicateEnumFail); err != nil {
+			return err
+		}
+	}
+
+	return s.restoreTrashFileOwners(ctx, snapshot)
+}
+
+// restoreTrashFileOwners adds a restored file back to the objects it belonged
+// to, if they still exist.
+func (s *Manager) restoreTrashFileOwners(ctx context.Context, snapshot *trashSnapshot) error {
+	if len(snapshot.SceneIDs)+len(snapshot.ImageIDs)+len(snapshot.GalleryIDs) == 0 || len(snapshot.Files) == 0 {
+		return nil
+	}
+
+	r := s.Repository
+
+	entry, err := jsonschema.ParseDirEntry(snapshot.Files[0])
+	if err != nil {
+		return fmt.Errorf("decoding file: %w", err)
+	}
+
+	f, err := r.File.FindByPath(ctx, entry.DirEntry().Path)
+	if err != nil {
+		return fmt.Errorf("finding file %s: %w", entry.DirEntry().Path, err)
+	}
+
+	if f == nil {
+		return nil
+	}
+
+	fileID := f.Base().ID
+
+	for _, id := range snapshot.SceneIDs {
+		if existing, err := r.Scene.Find(ctx, id); err != nil {
+			return err
+		} else if existing != nil {
+			if err := r.Scene.AddFileID(ctx, id, fileID); err != nil {
+				return fmt.Errorf("adding file to scene %d: %w", id, err)
+			}
+		}
+	}
+
+	for _, id := range snapshot.ImageIDs {
+		if existing, err := r.Image.Find(ctx, id); err != nil {
+			return err
+		} else if existing != nil {
+			if err := r.Image.AddFileID(ctx, id, fileID); err != nil {
+				return fmt.Errorf("adding file to image %d: %w", id, err)
+			}
+		}
+	}
+
+	for _, id := range snapshot.GalleryIDs {
+		if existing, err := r.Gallery.Find(ctx, id); err != nil {
+			return err
+		} else if existing != nil {
+			if err := r.Gallery.AddFileID(ctx, id, fileID); err != nil {
+				return fmt.Errorf("adding file to gallery %d: %w", id, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// EmptyTrash permanently deletes the trash items and their files. All items
+// are deleted if ids is nil. Returns the number of items deleted.
+func (s *Manager) EmptyTrash(ctx context.Context, ids []int) (int, error) {
+	var items []*models.TrashItem
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		qb := s.Repository.Trash
+
+		var err error
+		if ids == nil {
+			items, err = qb.All(ctx)
+		} else {
+			items, err = qb.FindMany(ctx, ids)
+		}
+		if err != nil {
+			return err
+		}
+
+		return destroyTrashItems(ctx, qb, items)
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(items), nil
+}
+
+// PurgeTrash permanently deletes the trash items that are older than the
+// configured retention period.
+func (s *Manager) PurgeTrash(ctx context.Context) error {
+	days := s.Config.GetTrashRetentionDays()
+	if days == 0 {
+		return nil
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	var items []*models.TrashItem
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		qb := s.Repository.Trash
+
+		var err error
+		items, err = qb.FindDeletedBefore(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+
+		return destroyTrashItems(ctx, qb, items)
+	}); err != nil {
+		return err
+	}
+
+	if len(items) > 0 {
+		logger.Infof("Purged %d items from trash", len(items))
+	}
+
+	return nil
+}
+
+// purgeTrashPeriodically purges expired trash items now and then daily,
+// until the context is cancelled.
+func (s *Manager) purgeTrashPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PurgeTrash(ctx); err != nil {
+			logger.Errorf("error purging trash: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// destroyTrashItems deletes the trash items from the database, and deletes
+// their files once the transaction is committed.
+func destroyTrashItems(ctx context.Context, qb models.TrashWriter, items []*models.TrashItem) error {
+	for _, item := range items {
+		if err := qb.Destroy(ctx, item.ID); err != nil {
+			return err
+		}
+	}
+
+	txn.AddPostCommitHook(ctx, func(ctx context.Context) {
+		for _, item := range items {
+			for _, f := range item.Files {
+				dir := filepath.Dir(f.TrashPath)
+				if err := os.RemoveAll(dir); err != nil {
+					logger.Warnf("Error deleting %q: %v", dir, err)
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+// removeTrashDirs removes the directories that held the files of a restored
+// trash item.
+func removeTrashDirs(item *models.TrashItem) {
+	for _, f := range item.Files {
+		dir := filepath.Dir(f.TrashPath)
+		if err := os.Remove(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Warnf("Error removing trash directory %q: %v", dir, err)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+//go:build integration
+// +build integration
+
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+
+	// necessary to register custom migrations
+	_ "github.com/stashapp/stash/pkg/sqlite/migrations"
+)
+
+func newTrashTestManager(t *testing.T, trashPath string) *Manager {
+	t.Helper()
+
+	cfg := config.InitializeEmpty()
+	cfg.SetString(config.TrashPath, trashPath)
+
+	db := sqlite.NewDatabase()
+	db.SetBlobStoreOptions(sqlite.BlobStoreOptions{
+		UseDatabase: true,
+	})
+	if err := db.Open(filepath.Join(t.TempDir(), "stash.sqlite")); err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return &Manager{
+		Config:     cfg,
+		Repository: db.Repository(),
+	}
+}
+
+// createTrashTestScene creates a scene with a video file at path.
+func createTrashTestScene(ctx context.Context, r models.Repository, path string) (*models.Scene, error) {
+	folder := &models.Folder{
+		Path: filepath.Dir(path),
+	}
+	if err := r.Folder.Create(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	f := &models.VideoFile{
+		BaseFile: &models.BaseFile{
+			Basename:       filepath.Base(path),
+			ParentFolderID: folder.ID,
+			Fingerprints: []models.Fingerprint{
+				{Type: models.FingerprintTypeOshash, Fingerprint: "0123456789abcdef"},
+			},
+			Size: 4,
+		},
+		Format:   "mp4",
+		Duration: 10,
+	}
+	if err := r.File.Create(ctx, f); err != nil {
+		return nil, err
+	}
+
+	s := &models.Scene{
+		Title: "scene",
+	}
+	if err := r.Scene.Create(ctx, s, []models.FileID{f.ID}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func TestManager_RestoreFromTrash(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	trashPath := filepath.Join(dir, "trash")
+	path := filepath.Join(dir, "library", "scene.mp4")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTrashTestManager(t, trashPath)
+	r := m.Repository
+
+	var sceneID int
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		s, err := createTrashTestScene(ctx, r, path)
+		if err != nil {
+			return err
+		}
+		sceneID = s.ID
+		return nil
+	}); err != nil {
+		t.Fatalf("creating scene: %v", err)
+	}
+
+	// delete the scene and its file
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		recorder := m.NewTrashRecorder()
+		recorder.Deleter.RegisterHooks(ctx)
+
+		s, err := r.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if err := s.LoadFiles(ctx, r.Scene); err != nil {
+			return err
+		}
+
+		return recorder.Scene(ctx, s, func() error {
+			if err := r.Scene.Destroy(ctx, s.ID); err != nil {
+				return err
+			}
+			return file.Destroy(ctx, r.File, s.Files.Primary(), recorder.Deleter, true)
+		})
+	}); err != nil {
+		t.Fatalf("deleting scene: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("%s was not moved to the trash", path)
+	}
+
+	var items []*models.TrashItem
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		items, err = r.Trash.All(ctx)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 || len(items[0].Files) != 1 {
+		t.Fatalf("trash items = %v, want one item with one file", items)
+	}
+
+	trashFile := items[0].Files[0]
+	if trashFile.OriginalPath != path {
+		t.Errorf("trash file original path = %q, want %q", trashFile.OriginalPath, path)
+	}
+
+	if err := m.RestoreFromTrash(ctx, []int{items[0].ID}); err != nil {
+		t.Fatalf("RestoreFromTrash() error = %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("%s was not restored: %v", path, err)
+	}
+	if _, err := os.Stat(filepath.Dir(trashFile.TrashPath)); !os.IsNotExist(err) {
+		t.Errorf("trash directory %s was not removed", filepath.Dir(trashFile.TrashPath))
+	}
+
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		remaining, err := r.Trash.All(ctx)
+		if err != nil {
+			return err
+		}
+		if len(remaining) != 0 {
+			t.Errorf("trash has %d items after restoring, want 0", len(remaining))
+		}
+
+		scenes, err := r.Scene.FindByPath(ctx, path)
+		if err != nil {
+			return err
+		}
+		if len(scenes) != 1 {
+			t.Errorf("found %d scenes with path %s, want 1", len(scenes), path)
+			return nil
+		}
+		if scenes[0].Title != "scene" {
+			t.Errorf("restored scene title = %q, want %q", scenes[0].Title, "scene")
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManager_RestoreFromTrash_NotFound(t *testing.T) {
+	m := newTrashTestManager(t, t.TempDir())
+
+	if err := m.RestoreFromTrash(context.Background(), []int{1}); err == nil {
+		t.Error("RestoreFromTrash() expected error for missing item")
+	}
+}
+
+func TestManager_RestoreFromTrash_customFields(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "library", "scene.mp4")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTrashTestManager(t, filepath.Join(dir, "trash"))
+	r := m.Repository
+
+	customFields := map[string]interface{}{
+		"source": "trash",
+	}
+
+	var (
+		sceneID   int
+		galleryID int
+	)
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		for _, entityType := range []models.CustomFieldEntityType{models.CustomFieldEntityTypeScene, models.CustomFieldEntityTypeGallery} {
+			d := models.NewCustomFieldDefinition()
+			d.EntityType = entityType
+			d.Name = "source"
+			d.Type = models.CustomFieldTypeString
+			if err := r.CustomField.Create(ctx, &d); err != nil {
+				return err
+			}
+		}
+
+		s, err := createTrashTestScene(ctx, r, path)
+		if err != nil {
+			return err
+		}
+		sceneID = s.ID
+
+		if err := r.Scene.SetCustomFields(ctx, s.ID, models.CustomFieldsInput{Full: customFields}); err != nil {
+			return err
+		}
+
+		g := &models.Gallery{
+			Title: "gallery",
+		}
+		if err := r.Gallery.Create(ctx, g, nil); err != nil {
+			return err
+		}
+		galleryID = g.ID
+
+		return r.Gallery.SetCustomFields(ctx, g.ID, models.CustomFieldsInput{Full: customFields})
+	}); err != nil {
+		t.Fatalf("creating objects: %v", err)
+	}
+
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		recorder := m.NewTrashRecorder()
+		recorder.Deleter.RegisterHooks(ctx)
+
+		s, err := r.Scene.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if err := s.LoadFiles(ctx, r.Scene); err != nil {
+			return err
+		}
+
+		if err := recorder.Scene(ctx, s, func() error {
+			if err := r.Scene.Destroy(ctx, s.ID); err != nil {
+				return err
+			}
+			return file.Destroy(ctx, r.File, s.Files.Primary(), recorder.Deleter, true)
+		}); err != nil {
+			return err
+		}
+
+		g, err := r.Gallery.Find(ctx, galleryID)
+		if err != nil {
+			return err
+		}
+
+		return recorder.Gallery(ctx, g, false, func() error {
+			return r.Gallery.Destroy(ctx, g.ID)
+		})
+	}); err != nil {
+		t.Fatalf("deleting objects: %v", err)
+	}
+
+	var ids []int
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		items, err := r.Trash.All(ctx)
+		for _, i := range items {
+			ids = append(ids, i.ID)
+		}
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.RestoreFromTrash(ctx, ids); err != nil {
+		t.Fatalf("RestoreFromTrash() error = %v", err)
+	}
+
+	if err := r.WithReadTxn(ctx, func(ctx context.Context) error {
+		scenes, err := r.Scene.FindByPath(ctx, path)
+		if err != nil {
+			return err
+		}
+		if len(scenes) != 1 {
+			t.Errorf("found %d scenes with path %s, want 1", len(scenes), path)
+		} else {
+			got, err := r.Scene.GetCustomFields(ctx, scenes[0].ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, customFields, got)
+		}
+
+		galleries, err := r.Gallery.FindUserGalleryByTitle(ctx, "gallery")
+		if err != nil {
+			return err
+		}
+		if len(galleries) != 1 {
+			t.Errorf("found %d galleries titled gallery, want 1", len(galleries))
+		} else {
+			got, err := r.Gallery.GetCustomFields(ctx, galleries[0].ID)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, customFields, got)
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
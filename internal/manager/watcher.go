@@ -0,0 +1,184 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// watchDebounce is how long a path must be unchanged before it is scanned.
+const watchDebounce = 5 * time.Second
+
+type libraryWatcher struct {
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (w *libraryWatcher) stop() {
+	if w.cancel == nil {
+		return
+	}
+
+	w.cancel()
+	<-w.done
+	w.cancel = nil
+	w.done = nil
+}
+
+// RefreshWatcher starts or stops the library watcher as needed, restarting it
+// if it is already running. Call this when the stash paths or the watcher
+// configuration changes.
+func (s *Manager) RefreshWatcher() {
+	w := &s.watcher
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.stop()
+
+	cfg := s.Config
+	if !cfg.GetWatchLibrary() {
+		return
+	}
+
+	var roots, paths []string
+	for _, sp := range cfg.GetStashPaths() {
+		roots = append(roots, sp.Path)
+
+		// remote stash paths cannot be watched, so rely on the periodic rescan
+		if sp.Remote != nil {
+			continue
+		}
+		paths = append(paths, sp.Path)
+	}
+
+	rescanInterval := time.Duration(cfg.GetWatchRescanInterval()) * time.Minute
+
+	if len(paths) == 0 && rescanInterval <= 0 {
+		return
+	}
+
+	// the watcher may stop early on error, so the rescan has its own context
+	// to keep running until the watcher is stopped
+	watchCtx, watchCancel := context.WithCancel(context.Background())
+	rescanCtx, rescanCancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	w.cancel = func() {
+		watchCancel()
+		rescanCancel()
+	}
+	w.done = done
+
+	watcher := &file.Watcher{
+		Debounce: watchDebounce,
+		Handler:  s.handleWatchBatch,
+	}
+
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		if rescanInterval > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.periodicRescan(rescanCtx, roots, rescanInterval)
+			}()
+		}
+
+		if len(paths) > 0 {
+			if err := watcher.Watch(watchCtx, paths); err != nil {
+				logger.Errorf("[watcher] error watching stash paths: %v", err)
+			}
+
+			watchCancel()
+		}
+
+		wg.Wait()
+	}()
+
+	logger.Infof("[watcher] watching %d stash paths for changes", len(paths))
+}
+
+func (s *Manager) stopWatcher() {
+	w := &s.watcher
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.stop()
+}
+
+// handleWatchBatch scans the changed paths, then cleans the removed paths.
+// Renames are reported as a removal and a change, so the scan must run first
+// to detect the rename before the old path is cleaned.
+func (s *Manager) handleWatchBatch(ctx context.Context, batch file.WatchBatch) {
+	if len(batch.Changed) > 0 {
+		logger.Infof("[watcher] scanning %d changed paths", len(batch.Changed))
+		s.watchScan(ctx, batch.Changed, nil)
+	}
+
+	// only clean removed paths if their parent folder still exists.
+	// Otherwise the stash path may have been unmounted.
+	var removed []string
+	for _, p := range batch.Removed {
+		if exists, _ := fsutil.DirExists(filepath.Dir(p)); exists {
+			removed = append(removed, p)
+		}
+	}
+
+	if len(removed) > 0 {
+		logger.Infof("[watcher] cleaning %d removed paths", len(removed))
+		s.Clean(ctx, CleanMetadataInput{
+			Paths: removed,
+		})
+	}
+}
+
+// periodicRescan scans the stash path roots every interval until the context
+// is cancelled. Only files modified since the previous rescan are processed.
+func (s *Manager) periodicRescan(ctx context.Context, roots []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			minModTime := since
+			since = time.Now()
+
+			logger.Info("[watcher] rescanning stash paths")
+			s.watchScan(ctx, roots, &ScanMetaDataFilterInput{
+				MinModTime: &minModTime,
+			})
+		}
+	}
+}
+
+// watchScan queues a scan of the provided paths, using the default scan
+// settings and the provided filter.
+func (s *Manager) watchScan(ctx context.Context, paths []string, filter *ScanMetaDataFilterInput) {
+	input := ScanMetadataInput{
+		Paths:  paths,
+		Filter: filter,
+	}
+
+	if defaults := s.Config.GetDefaultScanSettings(); defaults != nil {
+		input.ScanMetadataOptions = *defaults
+	}
+
+	// unchanged files never need to be rescanned
+	input.Rescan = false
+
+	if _, err := s.Scan(ctx, input); err != nil {
+		logger.Errorf("[watcher] error starting scan: %v", err)
+	}
+}
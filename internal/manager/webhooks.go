@@ -0,0 +1,20 @@
+package manager
+
+import "github.com/stashapp/stash/pkg/webhook"
+
+// WebhookDeadLetters returns the webhook deliveries that failed after all
+// attempts, most recent first.
+func (s *Manager) WebhookDeadLetters() []*webhook.DeadLetter {
+	return s.webhooks.DeadLetters()
+}
+
+// ClearWebhookDeadLetters removes the failed webhook deliveries.
+func (s *Manager) ClearWebhookDeadLetters() {
+	s.webhooks.ClearDeadLetters()
+}
+
+// RefreshWebhooks stops the deliveries to webhooks that have been removed or
+// changed. Call this when the webhook configuration changes.
+func (s *Manager) RefreshWebhooks() {
+	s.webhooks.Refresh()
+}
@@ -0,0 +1,19 @@
+package customfield
+
+import (
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/json"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+// ToJSON converts a CustomFieldDefinition object into its JSON equivalent.
+func ToJSON(d *models.CustomFieldDefinition) *jsonschema.CustomFieldDefinition {
+	return &jsonschema.CustomFieldDefinition{
+		EntityType: d.EntityType,
+		Name:       d.Name,
+		Type:       d.Type,
+		Options:    d.Options,
+		CreatedAt:  json.JSONTime{Time: d.CreatedAt},
+		UpdatedAt:  json.JSONTime{Time: d.UpdatedAt},
+	}
+}
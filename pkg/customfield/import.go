@@ -0,0 +1,97 @@
+package customfield
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+)
+
+type ImporterReaderWriter interface {
+	models.CustomFieldDefinitionReaderWriter
+}
+
+type Importer struct {
+	ReaderWriter ImporterReaderWriter
+	Input        jsonschema.CustomFieldDefinition
+
+	definition models.CustomFieldDefinition
+}
+
+func (i *Importer) PreImport(ctx context.Context) error {
+	i.definition = models.CustomFieldDefinition{
+		EntityType: i.Input.EntityType,
+		Name:       i.Input.Name,
+		Type:       i.Input.Type,
+		Options:    i.Input.Options,
+		CreatedAt:  i.Input.CreatedAt.GetTime(),
+		UpdatedAt:  i.Input.UpdatedAt.GetTime(),
+	}
+
+	if !i.definition.EntityType.IsValid() {
+		return fmt.Errorf("invalid entity type %q", i.definition.EntityType)
+	}
+
+	if !i.definition.Type.IsValid() {
+		return fmt.Errorf("invalid custom field type %q", i.definition.Type)
+	}
+
+	return ValidateOptions(i.definition.Type, i.definition.Options)
+}
+
+func (i *Importer) PostImport(ctx context.Context, id int) error {
+	return nil
+}
+
+func (i *Importer) Name() string {
+	return strings.ToLower(i.Input.EntityType.String()) + "." + i.Input.Name
+}
+
+func (i *Importer) FindExistingID(ctx context.Context) (*int, error) {
+	existing, err := i.ReaderWriter.FindByName(ctx, i.Input.EntityType, i.Input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		id := existing.ID
+		return &id, nil
+	}
+
+	return nil, nil
+}
+
+func (i *Importer) Create(ctx context.Context) (*int, error) {
+	err := i.ReaderWriter.Create(ctx, &i.definition)
+	if err != nil {
+		return nil, fmt.Errorf("error creating custom field: %v", err)
+	}
+
+	id := i.definition.ID
+	return &id, nil
+}
+
+func (i *Importer) Update(ctx context.Context, id int) error {
+	existing, err := i.ReaderWriter.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return &NotFoundError{id}
+	}
+
+	if existing.Type != i.definition.Type {
+		return fmt.Errorf("cannot change type of custom field from %s to %s", existing.Type, i.definition.Type)
+	}
+
+	d := i.definition
+	d.ID = id
+	if err := i.ReaderWriter.Update(ctx, &d); err != nil {
+		return fmt.Errorf("error updating existing custom field: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package customfield
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/jsonschema"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	fieldName       = "rating source"
+	fieldNameErr    = "fieldNameErr"
+	fieldNameNew    = "new field"
+	existingFieldID = 100
+)
+
+func TestImporterName(t *testing.T) {
+	i := Importer{
+		Input: jsonschema.CustomFieldDefinition{
+			EntityType: models.CustomFieldEntityTypeScene,
+			Name:       fieldName,
+		},
+	}
+
+	assert.Equal(t, "scene."+fieldName, i.Name())
+}
+
+func TestImporterPreImport(t *testing.T) {
+	i := Importer{
+		Input: jsonschema.CustomFieldDefinition{
+			EntityType: models.CustomFieldEntityTypeScene,
+			Name:       fieldName,
+			Type:       models.CustomFieldTypeEnum,
+			Options:    []string{"a", "b"},
+		},
+	}
+
+	err := i.PreImport(testCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, i.definition.Options)
+
+	i.Input.Type = "TIME"
+	err = i.PreImport(testCtx)
+	assert.NotNil(t, err)
+
+	i.Input.Type = models.CustomFieldTypeEnum
+	i.Input.Options = nil
+	err = i.PreImport(testCtx)
+	assert.NotNil(t, err)
+}
+
+func TestImporterFindExistingID(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	i := Importer{
+		ReaderWriter: db.CustomField,
+		Input: jsonschema.CustomFieldDefinition{
+			EntityType: models.CustomFieldEntityTypeScene,
+			Name:       fieldNameNew,
+		},
+	}
+
+	errFindByName := errors.New("FindByName error")
+	db.CustomField.On("FindByName", testCtx, models.CustomFieldEntityTypeScene, fieldNameNew).Return(nil, nil).Once()
+	db.CustomField.On("FindByName", testCtx, models.CustomFieldEntityTypeScene, fieldName).Return(&models.CustomFieldDefinition{
+		ID: existingFieldID,
+	}, nil).Once()
+	db.CustomField.On("FindByName", testCtx, models.CustomFieldEntityTypeScene, fieldNameErr).Return(nil, errFindByName).Once()
+
+	id, err := i.FindExistingID(testCtx)
+	assert.Nil(t, id)
+	assert.Nil(t, err)
+
+	i.Input.Name = fieldName
+	id, err = i.FindExistingID(testCtx)
+	assert.Equal(t, existingFieldID, *id)
+	assert.Nil(t, err)
+
+	i.Input.Name = fieldNameErr
+	id, err = i.FindExistingID(testCtx)
+	assert.Nil(t, id)
+	assert.NotNil(t, err)
+
+	db.AssertExpectations(t)
+}
+
+func TestImporterCreate(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	d := models.CustomFieldDefinition{
+		EntityType: models.CustomFieldEntityTypeScene,
+		Name:       fieldName,
+		Type:       models.CustomFieldTypeString,
+	}
+
+	dErr := models.CustomFieldDefinition{
+		EntityType: models.CustomFieldEntityTypeScene,
+		Name:       fieldNameErr,
+		Type:       models.CustomFieldTypeString,
+	}
+
+	i := Importer{
+		ReaderWriter: db.CustomField,
+		definition:   d,
+	}
+
+	errCreate := errors.New("Create error")
+	db.CustomField.On("Create", testCtx, &d).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.CustomFieldDefinition).ID = existingFieldID
+	}).Return(nil).Once()
+	db.CustomField.On("Create", testCtx, &dErr).Return(errCreate).Once()
+
+	id, err := i.Create(testCtx)
+	assert.Equal(t, existingFieldID, *id)
+	assert.Nil(t, err)
+
+	i.definition = dErr
+	id, err = i.Create(testCtx)
+	assert.Nil(t, id)
+	assert.NotNil(t, err)
+
+	db.AssertExpectations(t)
+}
+
+func TestImporterUpdate(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	i := Importer{
+		ReaderWriter: db.CustomField,
+		definition: models.CustomFieldDefinition{
+			EntityType: models.CustomFieldEntityTypeScene,
+			Name:       fieldName,
+			Type:       models.CustomFieldTypeEnum,
+			Options:    []string{"a", "b"},
+		},
+	}
+
+	db.CustomField.On("Find", testCtx, existingFieldID).Return(&models.CustomFieldDefinition{
+		ID:         existingFieldID,
+		EntityType: models.CustomFieldEntityTypeScene,
+		Name:       fieldName,
+		Type:       models.CustomFieldTypeEnum,
+		Options:    []string{"a"},
+	}, nil).Once()
+	db.CustomField.On("Update", testCtx, mock.MatchedBy(func(d *models.CustomFieldDefinition) bool {
+		return d.ID == existingFieldID && len(d.Options) == 2
+	})).Return(nil).Once()
+
+	err := i.Update(testCtx, existingFieldID)
+	assert.Nil(t, err)
+
+	db.CustomField.On("Find", testCtx, existingFieldID).Return(&models.CustomFieldDefinition{
+		ID:   existingFieldID,
+		Type: models.CustomFieldTypeString,
+	}, nil).Once()
+
+	err = i.Update(testCtx, existingFieldID)
+	assert.NotNil(t, err)
+
+	db.AssertExpectations(t)
+}
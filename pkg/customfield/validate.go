@@ -0,0 +1,135 @@
+// Package customfield provides validation and import/export of custom field
+// definitions.
+package customfield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var (
+	ErrNameMissing       = errors.New("custom field name must not be blank")
+	ErrOptionsMissing    = errors.New("enum custom fields must have at least one option")
+	ErrOptionsNotAllowed = errors.New("only enum custom fields may have options")
+)
+
+type NotFoundError struct {
+	id int
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("custom field with id %d not found", e.id)
+}
+
+type NameExistsError struct {
+	EntityType models.CustomFieldEntityType
+	Name       string
+}
+
+func (e *NameExistsError) Error() string {
+	return fmt.Sprintf("%s custom field with name '%s' already exists", strings.ToLower(e.EntityType.String()), e.Name)
+}
+
+type InvalidOptionError struct {
+	Option string
+}
+
+func (e *InvalidOptionError) Error() string {
+	if e.Option == "" {
+		return "custom field options must not be blank"
+	}
+	return fmt.Sprintf("custom field contains duplicate option '%s'", e.Option)
+}
+
+// ValidateCreate validates a new custom field definition.
+func ValidateCreate(ctx context.Context, d models.CustomFieldDefinition, qb models.CustomFieldDefinitionReader) error {
+	if !d.EntityType.IsValid() {
+		return fmt.Errorf("invalid entity type %q", d.EntityType)
+	}
+
+	if !d.Type.IsValid() {
+		return fmt.Errorf("invalid custom field type %q", d.Type)
+	}
+
+	if err := ValidateName(ctx, d.EntityType, d.Name, nil, qb); err != nil {
+		return err
+	}
+
+	return ValidateOptions(d.Type, d.Options)
+}
+
+// ValidateUpdate validates the changes to an existing custom field definition.
+// The entity type and field type of a definition cannot be changed.
+func ValidateUpdate(ctx context.Context, d models.CustomFieldDefinition, qb models.CustomFieldDefinitionReader) error {
+	existing, err := qb.Find(ctx, d.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return &NotFoundError{d.ID}
+	}
+
+	if d.EntityType != existing.EntityType || d.Type != existing.Type {
+		return errors.New("the entity type and type of a custom field cannot be changed")
+	}
+
+	if d.Name != existing.Name {
+		if err := ValidateName(ctx, d.EntityType, d.Name, &d.ID, qb); err != nil {
+			return err
+		}
+	}
+
+	return ValidateOptions(d.Type, d.Options)
+}
+
+// ValidateName returns an error if the name is blank or is already used by
+// another custom field of the same entity type.
+func ValidateName(ctx context.Context, entityType models.CustomFieldEntityType, name string, id *int, qb models.CustomFieldDefinitionReader) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrNameMissing
+	}
+
+	existing, err := qb.FindByName(ctx, entityType, name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && (id == nil || existing.ID != *id) {
+		return &NameExistsError{
+			EntityType: entityType,
+			Name:       name,
+		}
+	}
+
+	return nil
+}
+
+// ValidateOptions checks that enum fields have a non-empty set of unique,
+// non-blank options, and that other field types have no options.
+func ValidateOptions(t models.CustomFieldType, options []string) error {
+	if t != models.CustomFieldTypeEnum {
+		if len(options) > 0 {
+			return ErrOptionsNotAllowed
+		}
+		return nil
+	}
+
+	if len(options) == 0 {
+		return ErrOptionsMissing
+	}
+
+	seen := make(map[string]bool)
+	for _, o := range options {
+		if strings.TrimSpace(o) == "" || seen[o] {
+			return &InvalidOptionError{o}
+		}
+		seen[o] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package customfield
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCtx = context.Background()
+
+const (
+	existingID   = 1
+	existingName = "existing"
+	newName      = "new name"
+)
+
+func TestValidateCreate(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	db.CustomField.On("FindByName", testCtx, models.CustomFieldEntityTypeScene, existingName).Return(&models.CustomFieldDefinition{
+		ID:         existingID,
+		EntityType: models.CustomFieldEntityTypeScene,
+		Name:       existingName,
+		Type:       models.CustomFieldTypeString,
+	}, nil)
+	db.CustomField.On("FindByName", testCtx, mock.Anything, mock.Anything).Return(nil, nil)
+
+	tests := []struct {
+		name    string
+		d       models.CustomFieldDefinition
+		wantErr bool
+	}{
+		{"valid", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeInt}, false},
+		{"blank name", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: " ", Type: models.CustomFieldTypeInt}, true},
+		{"existing name", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: existingName, Type: models.CustomFieldTypeInt}, true},
+		{"existing name other entity", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypePerformer, Name: existingName, Type: models.CustomFieldTypeInt}, false},
+		{"invalid entity type", models.CustomFieldDefinition{EntityType: "IMAGE", Name: newName, Type: models.CustomFieldTypeInt}, true},
+		{"invalid type", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: "TIME"}, true},
+		{"enum", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeEnum, Options: []string{"a", "b"}}, false},
+		{"enum without options", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeEnum}, true},
+		{"enum duplicate option", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeEnum, Options: []string{"a", "a"}}, true},
+		{"enum blank option", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeEnum, Options: []string{"a", ""}}, true},
+		{"options on string", models.CustomFieldDefinition{EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeString, Options: []string{"a"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreate(testCtx, tt.d, db.CustomField)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	const (
+		otherID   = 2
+		missingID = 3
+		otherName = "other"
+	)
+
+	existing := &models.CustomFieldDefinition{
+		ID:         existingID,
+		EntityType: models.CustomFieldEntityTypeScene,
+		Name:       existingName,
+		Type:       models.CustomFieldTypeString,
+	}
+
+	db := mocks.NewDatabase()
+
+	db.CustomField.On("Find", testCtx, existingID).Return(existing, nil)
+	db.CustomField.On("Find", testCtx, missingID).Return(nil, nil)
+	db.CustomField.On("FindByName", testCtx, models.CustomFieldEntityTypeScene, otherName).Return(&models.CustomFieldDefinition{
+		ID:         otherID,
+		EntityType: models.CustomFieldEntityTypeScene,
+		Name:       otherName,
+		Type:       models.CustomFieldTypeString,
+	}, nil)
+	db.CustomField.On("FindByName", testCtx, mock.Anything, mock.Anything).Return(nil, nil)
+
+	tests := []struct {
+		name    string
+		d       models.CustomFieldDefinition
+		wantErr bool
+	}{
+		{"unchanged", *existing, false},
+		{"rename", models.CustomFieldDefinition{ID: existingID, EntityType: models.CustomFieldEntityTypeScene, Name: newName, Type: models.CustomFieldTypeString}, false},
+		{"rename to existing", models.CustomFieldDefinition{ID: existingID, EntityType: models.CustomFieldEntityTypeScene, Name: otherName, Type: models.CustomFieldTypeString}, true},
+		{"change type", models.CustomFieldDefinition{ID: existingID, EntityType: models.CustomFieldEntityTypeScene, Name: existingName, Type: models.CustomFieldTypeInt}, true},
+		{"change entity type", models.CustomFieldDefinition{ID: existingID, EntityType: models.CustomFieldEntityTypeGroup, Name: existingName, Type: models.CustomFieldTypeString}, true},
+		{"missing", models.CustomFieldDefinition{ID: missingID, EntityType: models.CustomFieldEntityTypeScene, Name: existingName, Type: models.CustomFieldTypeString}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdate(testCtx, tt.d, db.CustomField)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
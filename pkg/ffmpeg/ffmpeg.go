@@ -214,11 +214,18 @@ func (v Version) String() string {
 	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
 }
 
+// InputResolver returns the input that ffmpeg or ffprobe should read for
+// the file at path, such as a URL for a file that is not stored locally.
+type InputResolver func(path string) string
+
 // FFMpeg provides an interface to ffmpeg.
 type FFMpeg struct {
 	ffmpeg         string
 	version        Version
 	hwCodecSupport []VideoCodec
+
+	// InputResolver is used to resolve input file arguments if set.
+	InputResolver InputResolver
 }
 
 // Creates a new FFMpeg encoder
@@ -235,9 +242,27 @@ func NewEncoder(ffmpegPath string) *FFMpeg {
 
 // Returns an exec.Cmd that can be used to run ffmpeg using args.
 func (f *FFMpeg) Command(ctx context.Context, args []string) *exec.Cmd {
+	if f.InputResolver != nil {
+		args = resolveInputs(args, f.InputResolver)
+	}
 	return stashExec.CommandContext(ctx, string(f.ffmpeg), args...)
 }
 
+// resolveInputs returns a copy of args with the input file arguments
+// resolved using resolver.
+func resolveInputs(args []string, resolver InputResolver) []string {
+	ret := make([]string, len(args))
+	copy(ret, args)
+
+	for i := 1; i < len(ret); i++ {
+		if ret[i-1] == "-i" {
+			ret[i] = resolver(ret[i])
+		}
+	}
+
+	return ret
+}
+
 func (f *FFMpeg) Path() string {
 	return f.ffmpeg
 }
@@ -73,3 +73,27 @@ func TestFFMpegVersion_GreaterThan(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveInputs(t *testing.T) {
+	resolver := func(path string) string {
+		return "resolved:" + path
+	}
+
+	args := []string{"-ss", "5", "-i", "a.mp4", "-i", "b.vtt", "-f", "null", "-"}
+	got := resolveInputs(args, resolver)
+	want := []string{"-ss", "5", "-i", "resolved:a.mp4", "-i", "resolved:b.vtt", "-f", "null", "-"}
+
+	if len(got) != len(want) {
+		t.Fatalf("resolveInputs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("resolveInputs() = %v, want %v", got, want)
+			break
+		}
+	}
+
+	if args[3] != "a.mp4" {
+		t.Errorf("resolveInputs() modified the original args")
+	}
+}
@@ -113,6 +113,15 @@ type VideoFile struct {
 	FrameCount   int64
 
 	AudioCodec string
+
+	Chapters []Chapter
+}
+
+// Chapter is a chapter embedded in the container of a video file.
+type Chapter struct {
+	Start float64
+	End   float64
+	Title string
 }
 
 // TranscodeScale calculates the dimension scaling for a transcode, where maxSize is the maximum size of the longest dimension of the input video.
@@ -146,6 +155,9 @@ func (v *VideoFile) TranscodeScale(maxSize int) (int, int) {
 type FFProbe struct {
 	path    string
 	version Version
+
+	// InputResolver is used to resolve the path of probed files if set.
+	InputResolver InputResolver
 }
 
 func (f *FFProbe) Path() string {
@@ -196,6 +208,13 @@ func (f *FFProbe) getVersion() error {
 	return nil
 }
 
+func (f *FFProbe) input(path string) string {
+	if f.InputResolver != nil {
+		return f.InputResolver(path)
+	}
+	return path
+}
+
 // Creates a new FFProbe instance.
 func NewFFProbe(path string) *FFProbe {
 	ret := &FFProbe{
@@ -220,6 +239,7 @@ func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
 		"-print_format", "json",
 		"-show_format",
 		"-show_streams",
+		"-show_chapters",
 		"-show_error",
 	}
 
@@ -228,7 +248,7 @@ func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
 		args = append(args, "-show_entries", "stream_side_data=rotation")
 	}
 
-	args = append(args, videoPath)
+	args = append(args, f.input(videoPath))
 
 	cmd := stashExec.Command(f.path, args...)
 	out, err := cmd.Output()
@@ -248,7 +268,7 @@ func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
 // GetReadFrameCount counts the actual frames of the video file.
 // Used when the frame count is missing or incorrect.
 func (f *FFProbe) GetReadFrameCount(path string) (int64, error) {
-	args := []string{"-v", "quiet", "-print_format", "json", "-count_frames", "-show_format", "-show_streams", "-show_error", path}
+	args := []string{"-v", "quiet", "-print_format", "json", "-count_frames", "-show_format", "-show_streams", "-show_error", f.input(path)}
 	out, err := stashExec.Command(f.path, args...).Output()
 
 	if err != nil {
@@ -343,9 +363,30 @@ func parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
 		}
 	}
 
+	result.Chapters = parseChapters(probeJSON.Chapters)
+
 	return result, nil
 }
 
+func parseChapters(chapters []FFProbeChapter) []Chapter {
+	var ret []Chapter
+	for _, c := range chapters {
+		start, err := strconv.ParseFloat(c.StartTime, 64)
+		if err != nil {
+			continue
+		}
+		end, _ := strconv.ParseFloat(c.EndTime, 64)
+
+		ret = append(ret, Chapter{
+			Start: start,
+			End:   end,
+			Title: strings.TrimSpace(c.Tags.Title),
+		})
+	}
+
+	return ret
+}
+
 func isRotated(s *FFProbeStream) bool {
 	rotate, _ := strconv.ParseInt(s.Tags.Rotate, 10, 64)
 	if rotate != 180 && rotate != 0 {
@@ -0,0 +1,31 @@
+package ffmpeg
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func Test_parseChapters(t *testing.T) {
+	const probeOutput = `{
+		"chapters": [
+			{"id": 0, "time_base": "1/1000", "start": 0, "start_time": "0.000000", "end": 90500, "end_time": "90.500000", "tags": {"title": " Intro "}},
+			{"id": 1, "time_base": "1/1000", "start": 90500, "start_time": "90.500000", "end": 300000, "end_time": "300.000000"},
+			{"id": 2, "time_base": "1/1000", "start_time": "invalid"}
+		]
+	}`
+
+	var probeJSON FFProbeJSON
+	if err := json.Unmarshal([]byte(probeOutput), &probeJSON); err != nil {
+		t.Fatalf("unmarshalling probe output: %v", err)
+	}
+
+	want := []Chapter{
+		{Start: 0, End: 90.5, Title: "Intro"},
+		{Start: 90.5, End: 300},
+	}
+
+	if got := parseChapters(probeJSON.Chapters); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseChapters() = %v, want %v", got, want)
+	}
+}
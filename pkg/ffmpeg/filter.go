@@ -93,6 +93,17 @@ func (f VideoFilter) Select(frame int) VideoFilter {
 	return f.Append(fmt.Sprintf("select=eq(n\\,%d)", frame))
 }
 
+// SelectSceneChange returns a VideoFilter to select frames where the scene
+// change score exceeds threshold. The threshold is between 0 and 1.
+func (f VideoFilter) SelectSceneChange(threshold float64) VideoFilter {
+	return f.Append(fmt.Sprintf("select=gt(scene\\,%v)", threshold))
+}
+
+// MetadataPrint returns a VideoFilter that logs the metadata of each frame.
+func (f VideoFilter) MetadataPrint() VideoFilter {
+	return f.Append("metadata=print")
+}
+
 // Append returns a VideoFilter appending the given string.
 func (f VideoFilter) Append(s string) VideoFilter {
 	// if filter is empty, then just set
@@ -20,6 +20,8 @@ var (
 	FormatMP4      Format = "mp4"
 	FormatWebm     Format = "webm"
 	FormatMatroska Format = "matroska"
+	FormatNull     Format = "null"
+	FormatS16LE    Format = "s16le"
 )
 
 // ImageFormat represents the input format for an image for ffmpeg.
@@ -90,6 +90,15 @@ func (a Args) AudioBitrate(b string) Args {
 	return append(a, "-b:a", b)
 }
 
+// MaxVideoBitrate constrains the video bitrate to kbps kilobits per second
+// using -maxrate and -bufsize, and returns the result.
+func (a Args) MaxVideoBitrate(kbps int) Args {
+	return append(a,
+		"-maxrate", fmt.Sprintf("%dk", kbps),
+		"-bufsize", fmt.Sprintf("%dk", kbps*2),
+	)
+}
+
 // MaxMuxingQueueSize adds the -max_muxing_queue_size argument with s and returns the result.
 func (a Args) MaxMuxingQueueSize(s int) Args {
 	// https://trac.ffmpeg.org/ticket/6375
@@ -101,6 +110,21 @@ func (a Args) SkipAudio() Args {
 	return append(a, "-an")
 }
 
+// SkipVideo adds the skip video flag (-vn) and returns the result.
+func (a Args) SkipVideo() Args {
+	return append(a, "-vn")
+}
+
+// AudioChannels adds the -ac argument with c and returns the result.
+func (a Args) AudioChannels(c int) Args {
+	return append(a, "-ac", fmt.Sprint(c))
+}
+
+// AudioSampleRate adds the -ar argument with r and returns the result.
+func (a Args) AudioSampleRate(r int) Args {
+	return append(a, "-ar", fmt.Sprint(r))
+}
+
 // VideoCodec adds the given video codec and returns the result.
 func (a Args) VideoCodec(c VideoCodec) Args {
 	return append(a, c.Args()...)
@@ -260,6 +260,9 @@ type StreamOptions struct {
 	Resolution string
 	Hash       string
 	Segment    string
+	// MaxBitrate is the maximum video bitrate in kilobits per second.
+	// Zero means unlimited.
+	MaxBitrate int
 }
 
 type transcodeProcess struct {
@@ -287,6 +290,7 @@ type runningStream struct {
 	streamType       *StreamType
 	vf               *models.VideoFile
 	maxTranscodeSize int
+	maxBitrate       int
 	outputDir        string
 
 	waitingSegments []*waitingSegment
@@ -299,12 +303,15 @@ func (t StreamType) String() string {
 	return t.Name
 }
 
-func (t StreamType) FileDir(hash string, maxTranscodeSize int) string {
-	if maxTranscodeSize == 0 {
-		return fmt.Sprintf("%s_%s", hash, t)
-	} else {
-		return fmt.Sprintf("%s_%s_%d", hash, t, maxTranscodeSize)
+func (t StreamType) FileDir(hash string, maxTranscodeSize int, maxBitrate int) string {
+	ret := fmt.Sprintf("%s_%s", hash, t)
+	if maxTranscodeSize != 0 {
+		ret = fmt.Sprintf("%s_%d", ret, maxTranscodeSize)
 	}
+	if maxBitrate != 0 {
+		ret = fmt.Sprintf("%s_%dk", ret, maxBitrate)
+	}
+	return ret
 }
 
 func HLSGetCodec(sm *StreamManager, name string) (codec VideoCodec) {
@@ -351,6 +358,10 @@ func (s *runningStream) makeStreamArgs(sm *StreamManager, segment int) Args {
 
 	args = append(args, s.streamType.Args(codec, segment, videoFilter, videoOnly, s.outputDir)...)
 
+	if s.maxBitrate > 0 && codec != VideoCodecCopy {
+		args = args.MaxVideoBitrate(s.maxBitrate)
+	}
+
 	args = append(args, extraOutputArgs...)
 
 	return args
@@ -627,7 +638,14 @@ func (sm *StreamManager) ServeSegment(w http.ResponseWriter, r *http.Request, op
 		maxTranscodeSize = models.StreamingResolutionEnum(options.Resolution).GetMaxResolution()
 	}
 
-	dir := options.StreamType.FileDir(options.Hash, maxTranscodeSize)
+	// only limit the bitrate if the source exceeds it, so that
+	// unrestricted clients can share the same segments
+	maxBitrate := 0
+	if options.VideoFile.ExceedsBitrate(options.MaxBitrate) {
+		maxBitrate = options.MaxBitrate
+	}
+
+	dir := options.StreamType.FileDir(options.Hash, maxTranscodeSize, maxBitrate)
 	outputDir := filepath.Join(sm.cacheDir, dir)
 
 	name := streamType.SegmentType.MakeFilename(segment)
@@ -642,6 +660,7 @@ func (sm *StreamManager) ServeSegment(w http.ResponseWriter, r *http.Request, op
 			streamType:       options.StreamType,
 			vf:               options.VideoFile,
 			maxTranscodeSize: maxTranscodeSize,
+			maxBitrate:       maxBitrate,
 			outputDir:        outputDir,
 
 			// initialize to cap 10 to avoid reallocations
@@ -147,12 +147,16 @@ type TranscodeOptions struct {
 	VideoFile  *models.VideoFile
 	Resolution string
 	StartTime  float64
+	// MaxBitrate is the maximum video bitrate in kilobits per second.
+	// Zero means unlimited.
+	MaxBitrate int
 }
 
 func (o TranscodeOptions) FileGetCodec(sm *StreamManager, maxTranscodeSize int) (codec VideoCodec) {
-	needsResize := false
+	// re-encode if the source exceeds the bitrate limit
+	needsResize := o.VideoFile.ExceedsBitrate(o.MaxBitrate)
 
-	if maxTranscodeSize != 0 {
+	if !needsResize && maxTranscodeSize != 0 {
 		if o.VideoFile.Width > o.VideoFile.Height {
 			needsResize = o.VideoFile.Width > maxTranscodeSize
 		} else {
@@ -213,6 +217,10 @@ func (o TranscodeOptions) makeStreamArgs(sm *StreamManager) Args {
 
 	args = append(args, o.StreamType.Args(codec, videoFilter, videoOnly)...)
 
+	if o.MaxBitrate > 0 && codec != VideoCodecCopy {
+		args = args.MaxVideoBitrate(o.MaxBitrate)
+	}
+
 	args = append(args, extraOutputArgs...)
 
 	args = args.Output("pipe:")
@@ -27,8 +27,9 @@ type FFProbeJSON struct {
 			Comment          string        `json:"comment"`
 		} `json:"tags"`
 	} `json:"format"`
-	Streams []FFProbeStream `json:"streams"`
-	Error   struct {
+	Streams  []FFProbeStream  `json:"streams"`
+	Chapters []FFProbeChapter `json:"chapters"`
+	Error    struct {
 		Code   int    `json:"code"`
 		String string `json:"string"`
 	} `json:"error"`
@@ -98,3 +99,16 @@ type FFProbeStream struct {
 		Rotation int `json:"rotation"`
 	} `json:"side_data_list"`
 }
+
+// FFProbeChapter is a JSON representation of a chapter embedded in the container.
+type FFProbeChapter struct {
+	ID        int64  `json:"id"`
+	TimeBase  string `json:"time_base"`
+	Start     int64  `json:"start"`
+	StartTime string `json:"start_time"`
+	End       int64  `json:"end"`
+	EndTime   string `json:"end_time"`
+	Tags      struct {
+		Title string `json:"title"`
+	} `json:"tags"`
+}
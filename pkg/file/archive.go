@@ -0,0 +1,184 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var (
+	errNotReaderAt  = errors.New("not a ReaderAt")
+	errZipFSOpenZip = errors.New("cannot open archive file inside archive file")
+)
+
+// archiveReader returns a file system for the contents of the archive
+// read from r. path is the path of the archive file, used for logging.
+type archiveReader func(r io.ReaderAt, size int64, path string) (fs.FS, error)
+
+// archiveFormat is an archive format identified by the magic bytes at offset.
+type archiveFormat struct {
+	offset int
+	magic  []byte
+	reader archiveReader
+}
+
+// archiveFormats is the list of supported archive formats. Zip files are
+// assumed if the format is not recognised.
+var archiveFormats = []archiveFormat{
+	{magic: []byte("7z\xbc\xaf\x27\x1c"), reader: newSevenZipReader},
+	{magic: []byte("Rar!\x1a\x07"), reader: newRarReader},
+	{magic: []byte("\x1f\x8b"), reader: newTarGzipReader},
+	{offset: 257, magic: []byte("ustar"), reader: newTarReader},
+}
+
+// archiveHeaderSize is the number of bytes read to identify the archive format.
+const archiveHeaderSize = 512
+
+func detectArchiveReader(r io.ReaderAt) archiveReader {
+	header := make([]byte, archiveHeaderSize)
+	n, _ := r.ReadAt(header, 0)
+	header = header[:n]
+
+	for _, f := range archiveFormats {
+		end := f.offset + len(f.magic)
+		if end <= len(header) && bytes.Equal(header[f.offset:end], f.magic) {
+			return f.reader
+		}
+	}
+
+	return newZipReader
+}
+
+// archiveFS is a read-only file system backed by an archive file, such as a
+// zip, 7z, RAR or tar file. The format is detected from the contents of the
+// file rather than its extension.
+type archiveFS struct {
+	fs.FS
+	archiveFileCloser io.Closer
+	archivePath       string
+}
+
+func newArchiveFS(fsys models.FS, path string, size int64) (*archiveFS, error) {
+	reader, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	asReaderAt, _ := reader.(io.ReaderAt)
+	if asReaderAt == nil {
+		reader.Close()
+		return nil, errNotReaderAt
+	}
+
+	newReader := detectArchiveReader(asReaderAt)
+	archiveReader, err := newReader(asReaderAt, size, path)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	return &archiveFS{
+		FS:                archiveReader,
+		archiveFileCloser: reader,
+		archivePath:       path,
+	}, nil
+}
+
+func (f *archiveFS) rel(name string) (string, error) {
+	if f.archivePath == name {
+		return ".", nil
+	}
+
+	relName, err := filepath.Rel(f.archivePath, name)
+	if err != nil {
+		return "", fmt.Errorf("internal error getting relative path: %w", err)
+	}
+
+	// convert relName to use slash, since archive files do so regardless
+	// of os
+	relName = filepath.ToSlash(relName)
+
+	return relName, nil
+}
+
+func (f *archiveFS) Stat(name string) (fs.FileInfo, error) {
+	relName, err := f.rel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Stat(f.FS, relName)
+}
+
+func (f *archiveFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *archiveFS) OpenZip(name string, size int64) (models.ZipFS, error) {
+	return nil, errZipFSOpenZip
+}
+
+func (f *archiveFS) IsPathCaseSensitive(path string) (bool, error) {
+	return true, nil
+}
+
+type archiveReadDirFile struct {
+	fs.File
+}
+
+func (f *archiveReadDirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	asReadDirFile, _ := f.File.(fs.ReadDirFile)
+	if asReadDirFile == nil {
+		return nil, fmt.Errorf("internal error: not a ReadDirFile")
+	}
+
+	return asReadDirFile.ReadDir(n)
+}
+
+func (f *archiveFS) Open(name string) (fs.ReadDirFile, error) {
+	relName, err := f.rel(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := f.FS.Open(relName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveReadDirFile{
+		File: r,
+	}, nil
+}
+
+func (f *archiveFS) Close() error {
+	return f.archiveFileCloser.Close()
+}
+
+// OpenOnly returns a ReadCloser where calling Close will close the archive fs as well.
+func (f *archiveFS) OpenOnly(name string) (io.ReadCloser, error) {
+	r, err := f.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrappedReadCloser{
+		ReadCloser: r,
+		outer:      f,
+	}, nil
+}
+
+type wrappedReadCloser struct {
+	io.ReadCloser
+	outer io.Closer
+}
+
+func (f *wrappedReadCloser) Close() error {
+	_ = f.ReadCloser.Close()
+	return f.outer.Close()
+}
@@ -0,0 +1,403 @@
+package file
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxStreamCacheSize is the maximum total size of the entries cached by a
+// streamFS.
+const maxStreamCacheSize = 64 << 20
+
+// archiveEntry is a file or directory in an archive read by streamFS.
+type archiveEntry struct {
+	// name is the slash separated path of the entry in the archive
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+
+	// index is the position of the entry in the archive. It is -1 for
+	// directories that are not in the archive.
+	index int
+
+	// section is set if the file contents can be read directly, without
+	// reading the archive from the start
+	section *io.SectionReader
+}
+
+// archiveStream reads the entries of an archive in order.
+type archiveStream interface {
+	// Next advances to the next entry in the archive. It returns io.EOF
+	// when there are no more entries.
+	Next() (*archiveEntry, error)
+	// Read reads the contents of the current entry.
+	io.Reader
+}
+
+// streamFS is a read-only file system for archive formats that can only be
+// read sequentially, such as RAR and compressed tar files. The entries are
+// indexed when the file system is created.
+//
+// Files whose contents cannot be read directly are read from the stream.
+// When a file is closed, the stream is kept so that opening a later file
+// continues from the same position, rather than reading the archive from
+// the start. Files skipped to reach the opened file are cached, up to
+// maxStreamCacheSize, so that opening files out of order does not read the
+// archive again.
+type streamFS struct {
+	open     func() (archiveStream, error)
+	entries  map[string]*archiveEntry
+	children map[string][]*archiveEntry
+
+	mu sync.Mutex
+	// stream is positioned before the entry at index next. It is nil if
+	// there is no stream that is not in use.
+	stream archiveStream
+	next   int
+	// cache holds the contents of skipped entries until they are opened
+	cache     map[string][]byte
+	cacheSize int
+	// opened is the set of entries that have been read from the stream
+	opened map[string]bool
+}
+
+func newStreamFS(open func() (archiveStream, error)) (*streamFS, error) {
+	ret := &streamFS{
+		open: open,
+		entries: map[string]*archiveEntry{
+			".": {name: ".", isDir: true, index: -1},
+		},
+		children: make(map[string][]*archiveEntry),
+		cache:    make(map[string][]byte),
+		opened:   make(map[string]bool),
+	}
+
+	stream, err := open()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; ; i++ {
+		e, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		e.index = i
+		ret.add(e)
+	}
+
+	for _, c := range ret.children {
+		sort.Slice(c, func(i, j int) bool {
+			return c[i].name < c[j].name
+		})
+	}
+
+	return ret, nil
+}
+
+// validArchiveName returns the name of an archive entry as a valid fs path,
+// or an empty string if it is not valid.
+func validArchiveName(name string) string {
+	name = strings.ReplaceAll(name, `\`, "/")
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if !fs.ValidPath(name) || name == "." {
+		return ""
+	}
+
+	return name
+}
+
+func (f *streamFS) add(e *archiveEntry) {
+	name := validArchiveName(e.name)
+	if name == "" {
+		return
+	}
+
+	// use the first entry if there are duplicates
+	if _, found := f.entries[name]; found {
+		return
+	}
+
+	e.name = name
+	f.entries[name] = e
+
+	dir := path.Dir(name)
+	f.children[dir] = append(f.children[dir], e)
+
+	// add any parent directories not in the archive
+	if _, found := f.entries[dir]; !found {
+		f.add(&archiveEntry{
+			name:  dir,
+			isDir: true,
+			index: -1,
+		})
+	}
+}
+
+func (f *streamFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	e := f.entries[name]
+	if e == nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &archiveEntryInfo{e}, nil
+}
+
+func (f *streamFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	e := f.entries[name]
+	if e == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	if e.isDir {
+		return &streamDir{
+			archiveEntryInfo: archiveEntryInfo{e},
+			children:         f.children[name],
+		}, nil
+	}
+
+	if e.section != nil {
+		return &streamFile{
+			archiveEntryInfo: archiveEntryInfo{e},
+			r:                io.NewSectionReader(e.section, 0, e.size),
+		}, nil
+	}
+
+	return f.openStream(e)
+}
+
+// openStream returns a file that reads the contents of e from the archive
+// stream.
+func (f *streamFS) openStream(e *archiveEntry) (fs.File, error) {
+	f.mu.Lock()
+	f.opened[e.name] = true
+	if data, found := f.cache[e.name]; found {
+		delete(f.cache, e.name)
+		f.cacheSize -= len(data)
+		f.mu.Unlock()
+
+		return &streamFile{
+			archiveEntryInfo: archiveEntryInfo{e},
+			r:                bytes.NewReader(data),
+		}, nil
+	}
+
+	stream, next := f.stream, f.next
+	f.stream = nil
+	f.mu.Unlock()
+
+	// continue from the kept stream if it has not passed the entry,
+	// otherwise read the archive from the start
+	if stream != nil && next <= e.index {
+		var err error
+		next, err = f.seek(stream, next, e)
+		if err != nil {
+			// the stream may have been left in a bad state by the last
+			// file, so try again from the start
+			stream = nil
+		}
+	}
+
+	if stream == nil || next != e.index+1 {
+		var err error
+		stream, err = f.open()
+		if err == nil {
+			next, err = f.seek(stream, 0, e)
+		}
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: e.name, Err: err}
+		}
+	}
+
+	return &streamFile{
+		archiveEntryInfo: archiveEntryInfo{e},
+		r:                stream,
+		close: func() {
+			f.release(stream, next)
+		},
+	}, nil
+}
+
+// seek advances stream, positioned before the entry at index next, until
+// the current entry is e. It returns the index of the following entry.
+func (f *streamFS) seek(stream archiveStream, next int, e *archiveEntry) (int, error) {
+	for ; next <= e.index; next++ {
+		entry, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return next, fs.ErrNotExist
+		}
+		if err != nil {
+			return next, err
+		}
+
+		if next < e.index {
+			f.cacheEntry(next, entry, stream)
+		}
+	}
+
+	return next, nil
+}
+
+// cacheEntry caches the contents of the entry at index, which is the
+// current entry of stream, if it has not been opened and fits in the cache.
+func (f *streamFS) cacheEntry(index int, entry *archiveEntry, stream archiveStream) {
+	e := f.entries[validArchiveName(entry.name)]
+	if e == nil || e.index != index || e.isDir || e.size <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	_, cached := f.cache[e.name]
+	ok := !cached && !f.opened[e.name] && f.cacheSize+int(e.size) <= maxStreamCacheSize
+	if ok {
+		// reserve the space while the entry is read
+		f.cacheSize += int(e.size)
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	data := make([]byte, e.size)
+	_, err := io.ReadFull(stream, data)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err != nil || f.opened[e.name] {
+		f.cacheSize -= int(e.size)
+		return
+	}
+
+	f.cache[e.name] = data
+}
+
+// release keeps stream, positioned before the entry at index next, to be
+// used by the next file opened.
+func (f *streamFS) release(stream archiveStream, next int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stream == nil || f.next < next {
+		f.stream = stream
+		f.next = next
+	}
+}
+
+// archiveEntryInfo implements fs.FileInfo and fs.DirEntry for an archive entry.
+type archiveEntryInfo struct {
+	e *archiveEntry
+}
+
+func (i *archiveEntryInfo) Name() string {
+	return path.Base(i.e.name)
+}
+
+func (i *archiveEntryInfo) Size() int64 {
+	return i.e.size
+}
+
+func (i *archiveEntryInfo) Mode() fs.FileMode {
+	if i.e.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (i *archiveEntryInfo) Type() fs.FileMode {
+	return i.Mode().Type()
+}
+
+func (i *archiveEntryInfo) ModTime() time.Time {
+	return i.e.modTime
+}
+
+func (i *archiveEntryInfo) IsDir() bool {
+	return i.e.isDir
+}
+
+func (i *archiveEntryInfo) Sys() interface{} {
+	return nil
+}
+
+func (i *archiveEntryInfo) Info() (fs.FileInfo, error) {
+	return i, nil
+}
+
+func (i *archiveEntryInfo) Stat() (fs.FileInfo, error) {
+	return i, nil
+}
+
+type streamFile struct {
+	archiveEntryInfo
+	r io.Reader
+
+	// close is called when the file is closed, if set
+	close func()
+}
+
+func (f *streamFile) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+func (f *streamFile) Close() error {
+	if f.close != nil {
+		f.close()
+		f.close = nil
+	}
+	return nil
+}
+
+type streamDir struct {
+	archiveEntryInfo
+	children []*archiveEntry
+	offset   int
+}
+
+func (d *streamDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.e.name, Err: errors.New("is a directory")}
+}
+
+func (d *streamDir) Close() error {
+	return nil
+}
+
+func (d *streamDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.children[d.offset:]
+	if n > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > 0 && n < len(remaining) {
+		remaining = remaining[:n]
+	}
+
+	ret := make([]fs.DirEntry, len(remaining))
+	for i, e := range remaining {
+		ret[i] = &archiveEntryInfo{e}
+	}
+	d.offset += len(remaining)
+
+	return ret, nil
+}
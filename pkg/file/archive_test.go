@@ -0,0 +1,258 @@
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type archiveTestFile struct {
+	name    string
+	content string
+}
+
+var archiveTestFiles = []archiveTestFile{
+	{"dir/a.jpg", "image a"},
+	{"b.jpg", "image b"},
+}
+
+// 7z archive of archiveTestFiles, created with bsdtar
+const archiveTest7z = "N3q8ryccAAOb2UnIjgAAAAAAAAAhAAAAAAAAAN/ZYxMANJtIR1cJHyuLeqhB////++AAAAAAgTMHrg/PObAMB8hDgIOBW/+sds94Pw5xriiEkQIiBRCZqiI2AvXHO3OExyWOIZp2pkf76A2INids7eI48VWdc1tk6VHsI+GHSgeRy3qcT/PMmc6tnKqVh+ylEUSHlK33rtXKnohABT6P9iruawu872QNIf/8iwAAFwYUAQl6AAcLAQABIwMBAQVdAACAAAyAwQoB1RtPYAAA"
+
+func makeTestZip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range archiveTestFiles {
+		fw, err := w.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeTestTar(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, f := range archiveTestFiles {
+		if err := w.WriteHeader(&tar.Header{
+			Name:     f.name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(f.content)),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, f.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeTestTarGzip(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(makeTestTar(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeTest7z(t *testing.T) []byte {
+	ret, err := base64.StdEncoding.DecodeString(archiveTest7z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestArchiveFS(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     func(t *testing.T) []byte
+	}{
+		{"zip", "test.zip", makeTestZip},
+		{"7z", "test.7z", makeTest7z},
+		{"tar", "test.tar", makeTestTar},
+		{"tar.gz", "test.tar.gz", makeTestTarGzip},
+		// format is detected from the contents, not the extension
+		{"tar as cbz", "test.cbz", makeTestTar},
+	}
+
+	dir := t.TempDir()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := filepath.Join(dir, tt.filename)
+			data := tt.data(t)
+			if err := os.WriteFile(archivePath, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			afs, err := (&OsFS{}).OpenZip(archivePath, int64(len(data)))
+			if err != nil {
+				t.Fatalf("OpenZip() error = %v", err)
+			}
+			defer afs.Close()
+
+			root, err := afs.Open(archivePath)
+			if err != nil {
+				t.Fatalf("Open(root) error = %v", err)
+			}
+			entries, err := root.ReadDir(-1)
+			root.Close()
+			if err != nil {
+				t.Fatalf("ReadDir(root) error = %v", err)
+			}
+
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			if want := []string{"b.jpg", "dir"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("ReadDir(root) = %v, want %v", names, want)
+			}
+
+			info, err := afs.Stat(filepath.Join(archivePath, "dir"))
+			if err != nil {
+				t.Fatalf("Stat(dir) error = %v", err)
+			}
+			if !info.IsDir() {
+				t.Errorf("Stat(dir).IsDir() = false")
+			}
+
+			for _, f := range archiveTestFiles {
+				p := filepath.Join(archivePath, filepath.FromSlash(f.name))
+
+				info, err := afs.Stat(p)
+				if err != nil {
+					t.Fatalf("Stat(%s) error = %v", f.name, err)
+				}
+				if info.IsDir() || info.Size() != int64(len(f.content)) {
+					t.Errorf("Stat(%s) = dir %v, size %d", f.name, info.IsDir(), info.Size())
+				}
+
+				r, err := afs.Open(p)
+				if err != nil {
+					t.Fatalf("Open(%s) error = %v", f.name, err)
+				}
+				got, err := io.ReadAll(r)
+				r.Close()
+				if err != nil {
+					t.Fatalf("reading %s: %v", f.name, err)
+				}
+				if string(got) != f.content {
+					t.Errorf("contents of %s = %q, want %q", f.name, got, f.content)
+				}
+			}
+
+			if _, err := afs.Stat(filepath.Join(archivePath, "missing.jpg")); err == nil {
+				t.Errorf("Stat(missing.jpg) expected error")
+			}
+		})
+	}
+}
+
+func TestStreamFS_ReadsArchiveOnce(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	var names []string
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("%02d.jpg", i)
+		content := "image " + name
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(content)),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, content); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+
+	reversed := make([]string, len(names))
+	for i, name := range names {
+		reversed[len(names)-1-i] = name
+	}
+
+	tests := []struct {
+		name  string
+		order []string
+	}{
+		{"in order", names},
+		{"reverse order", reversed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opens := 0
+			fsys, err := newStreamFS(func() (archiveStream, error) {
+				opens++
+				gr, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					return nil, err
+				}
+				return &tarStream{Reader: tar.NewReader(gr)}, nil
+			})
+			if err != nil {
+				t.Fatalf("newStreamFS() error = %v", err)
+			}
+
+			for _, name := range tt.order {
+				r, err := fsys.Open(name)
+				if err != nil {
+					t.Fatalf("Open(%s) error = %v", name, err)
+				}
+				got, err := io.ReadAll(r)
+				r.Close()
+				if err != nil {
+					t.Fatalf("reading %s: %v", name, err)
+				}
+				if want := "image " + name; string(got) != want {
+					t.Errorf("contents of %s = %q, want %q", name, got, want)
+				}
+			}
+
+			// once to index the entries, and once to read them
+			if opens != 2 {
+				t.Errorf("archive was read %d times, want 2", opens)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -38,6 +39,15 @@ type CleanOptions struct {
 	// PathFilter are used to determine if a file should be included.
 	// Excluded files are marked for cleaning.
 	PathFilter PathFilter
+
+	// OfflinePaths are paths that are currently unavailable. Files and
+	// folders in these paths are not cleaned.
+	OfflinePaths []string
+
+	// CheckAvailable returns an error if the path containing a file or
+	// folder that no longer exists has become unavailable since the clean
+	// started. Missing files are not cleaned if it returns an error.
+	CheckAvailable func(path string) error
 }
 
 // Clean starts the clean process.
@@ -329,9 +339,28 @@ func isNotFound(err error) bool {
 			errors.As(err, &pathErr))
 }
 
+// isAvailable returns false if the path containing the missing file or
+// folder at path has become unavailable.
+func (j *cleanJob) isAvailable(path string) bool {
+	if j.options.CheckAvailable == nil {
+		return true
+	}
+
+	if err := j.options.CheckAvailable(path); err != nil {
+		logger.Warnf("Not cleaning %q, as its path is unavailable: %v", path, err)
+		return false
+	}
+
+	return true
+}
+
 func (j *cleanJob) shouldClean(ctx context.Context, f models.File) bool {
 	path := f.Base().Path
 
+	if fsutil.IsPathInDirs(j.options.OfflinePaths, path) {
+		return false
+	}
+
 	info, err := f.Base().Info(j.FS)
 	if err != nil && !isNotFound(err) {
 		logger.Errorf("error getting file info for %q, not cleaning: %v", path, err)
@@ -340,6 +369,10 @@ func (j *cleanJob) shouldClean(ctx context.Context, f models.File) bool {
 
 	if info == nil {
 		// info is nil - file not exist
+		if !j.isAvailable(path) {
+			return false
+		}
+
 		logger.Infof("File not found. Marking to clean: \"%s\"", path)
 		return true
 	}
@@ -354,6 +387,10 @@ func (j *cleanJob) shouldClean(ctx context.Context, f models.File) bool {
 func (j *cleanJob) shouldCleanFolder(ctx context.Context, f *models.Folder) bool {
 	path := f.Path
 
+	if fsutil.IsPathInDirs(j.options.OfflinePaths, path) {
+		return false
+	}
+
 	info, err := f.Info(j.FS)
 
 	if err != nil && !isNotFound(err) {
@@ -363,6 +400,10 @@ func (j *cleanJob) shouldCleanFolder(ctx context.Context, f *models.Folder) bool
 
 	if info == nil {
 		// info is nil - file not exist
+		if !j.isAvailable(path) {
+			return false
+		}
+
 		logger.Infof("Folder not found. Marking to clean: \"%s\"", path)
 		return true
 	}
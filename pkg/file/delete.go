@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
 
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
@@ -15,6 +19,10 @@ import (
 
 const deleteFileSuffix = ".delete"
 
+// ErrTrashVolume is returned when a file cannot be moved to the trash
+// directory, usually because it is on a different volume.
+var ErrTrashVolume = errors.New("file could not be renamed into the trash directory, which must be on the same volume as the file")
+
 // RenamerRemover provides access to the Rename and Remove functions.
 type RenamerRemover interface {
 	Renamer
@@ -63,15 +71,35 @@ func newRenamerRemoverImpl() renamerRemoverImpl {
 // be restored to their original state with the Abort method. If the
 // transaction is committed, the marked files are then deleted from the
 // filesystem using the Complete method.
+//
+// If TrashPath is set, files designated using the Trash method are moved to
+// the trash directory instead, and are kept when the transaction is
+// committed. Files are moved to the trash with TrashRenamer, which must not
+// copy files between volumes.
 type Deleter struct {
 	RenamerRemover RenamerRemover
+	TrashRenamer   DirMakerStatRenamer
+	TrashPath      string
 	files          []string
 	dirs           []string
+	trashed        []models.TrashFile
+	taken          int
 }
 
 func NewDeleter() *Deleter {
 	return &Deleter{
 		RenamerRemover: newRenamerRemoverImpl(),
+		TrashRenamer: &folderCreatorStatRenamerImpl{
+			// files are renamed rather than moved with fsutil.SafeMove, which
+			// would copy files on another volume while the transaction is open
+			renamerRemoverImpl: renamerRemoverImpl{
+				RenameFn:    os.Rename,
+				RemoveFn:    os.Remove,
+				RemoveAllFn: os.RemoveAll,
+				StatFn:      os.Stat,
+			},
+			mkDirFn: os.Mkdir,
+		},
 	}
 }
 
@@ -112,6 +140,120 @@ func (d *Deleter) Files(paths []string) error {
 	return nil
 }
 
+// Trash designates files to be moved to the trash directory. Each file is
+// moved to a new directory within TrashPath, so that files with the same
+// basename do not collide. Files on a different volume to TrashPath are not
+// copied; ErrTrashVolume is returned instead. If TrashPath is empty, the files are marked for
+// deletion using Files. As with Files, Rollback should be called to restore
+// moved files if this function returns an error.
+func (d *Deleter) Trash(paths []string) error {
+	if d.TrashPath == "" {
+		return d.Files(paths)
+	}
+
+	for _, p := range paths {
+		// fail silently if the file does not exist
+		info, err := d.RenamerRemover.Stat(p)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				logger.Warnf("File %q does not exist and therefore cannot be deleted. Ignoring.", p)
+				continue
+			}
+
+			return fmt.Errorf("check file %q exists: %w", p, err)
+		}
+
+		trashPath, err := d.moveToTrash(p)
+		if err != nil {
+			return fmt.Errorf("moving file %q to trash: %w", p, err)
+		}
+
+		d.trashed = append(d.trashed, models.TrashFile{
+			OriginalPath: p,
+			TrashPath:    trashPath,
+			Size:         info.Size(),
+		})
+	}
+
+	return nil
+}
+
+func (d *Deleter) moveToTrash(path string) (string, error) {
+	if err := d.createTrashDir(d.TrashPath); err != nil {
+		return "", err
+	}
+
+	dir, err := d.makeTrashSubDir()
+	if err != nil {
+		return "", err
+	}
+
+	// files must be on the same volume as the trash directory
+	trashPath := filepath.Join(dir, filepath.Base(path))
+	if err := d.TrashRenamer.Rename(path, trashPath); err != nil {
+		_ = d.TrashRenamer.Remove(dir)
+
+		if errors.Is(err, syscall.EXDEV) {
+			return "", fmt.Errorf("%w: %v", ErrTrashVolume, err)
+		}
+		return "", err
+	}
+
+	return trashPath, nil
+}
+
+// createTrashDir creates the directory and any missing parents.
+func (d *Deleter) createTrashDir(path string) error {
+	info, err := d.TrashRenamer.Stat(path)
+	if err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+		return nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("getting info for %s: %w", path, err)
+	}
+
+	if err := d.createTrashDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	if err := d.TrashRenamer.Mkdir(path, 0755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("creating folder %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// makeTrashSubDir creates a new, randomly named directory within TrashPath.
+func (d *Deleter) makeTrashSubDir() (string, error) {
+	const maxAttempts = 10000
+
+	for i := 0; i < maxAttempts; i++ {
+		dir := filepath.Join(d.TrashPath, strconv.FormatUint(uint64(rand.Uint32()), 10))
+		err := d.TrashRenamer.Mkdir(dir, 0755)
+		if err == nil {
+			return dir, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("creating folder %s: %w", dir, err)
+		}
+	}
+
+	return "", fmt.Errorf("could not create a directory in %s", d.TrashPath)
+}
+
+// TakeTrashed returns the files moved to the trash since the last call. This
+// is used to record the files of each deleted object separately.
+func (d *Deleter) TakeTrashed() []models.TrashFile {
+	ret := d.trashed[d.taken:]
+	d.taken = len(d.trashed)
+	return ret
+}
+
 // Dirs designates directories to be deleted. Each directory marked will be renamed to add
 // a `.delete` suffix. An error is returned if a directory could not be renamed.
 // Note that if an error is returned, then some directories may be left renamed.
@@ -148,8 +290,21 @@ func (d *Deleter) Rollback() {
 		}
 	}
 
+	for _, f := range d.trashed {
+		if err := d.TrashRenamer.Rename(f.TrashPath, f.OriginalPath); err != nil {
+			logger.Warnf("Error restoring %q: %v", f.OriginalPath, err)
+			continue
+		}
+
+		if err := d.TrashRenamer.Remove(filepath.Dir(f.TrashPath)); err != nil {
+			logger.Warnf("Error removing trash directory %q: %v", filepath.Dir(f.TrashPath), err)
+		}
+	}
+
 	d.files = nil
 	d.dirs = nil
+	d.trashed = nil
+	d.taken = 0
 }
 
 // Commit deletes all files marked for deletion and clears the marked list.
@@ -168,8 +323,11 @@ func (d *Deleter) Commit() {
 		}
 	}
 
+	// trashed files are kept until the trash is emptied
 	d.files = nil
 	d.dirs = nil
+	d.trashed = nil
+	d.taken = 0
 }
 
 func (d *Deleter) renameForDelete(path string) error {
@@ -187,7 +345,7 @@ func Destroy(ctx context.Context, destroyer models.FileDestroyer, f models.File,
 
 	// don't delete files in zip files
 	if deleteFile && f.Base().ZipFileID == nil {
-		if err := fileDeleter.Files([]string{f.Base().Path}); err != nil {
+		if err := fileDeleter.Trash([]string{f.Base().Path}); err != nil {
 			return err
 		}
 	}
@@ -230,7 +388,7 @@ func (d *ZipDestroyer) DestroyZip(ctx context.Context, f models.File, fileDelete
 	}
 
 	if deleteFile {
-		if err := fileDeleter.Files([]string{f.Base().Path}); err != nil {
+		if err := fileDeleter.Trash([]string{f.Base().Path}); err != nil {
 			return err
 		}
 	}
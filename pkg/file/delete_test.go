@@ -0,0 +1,182 @@
+package file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestDeleter_Trash(t *testing.T) {
+	dir := t.TempDir()
+	trashPath := filepath.Join(dir, "trash")
+
+	p1 := filepath.Join(dir, "a.mp4")
+	p2 := filepath.Join(dir, "sub", "a.mp4")
+	writeTestFile(t, p1)
+	if err := os.Mkdir(filepath.Dir(p2), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, p2)
+
+	d := NewDeleter()
+	d.TrashPath = trashPath
+
+	if err := d.Trash([]string{p1, p2, filepath.Join(dir, "missing.mp4")}); err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+
+	trashed := d.TakeTrashed()
+	if len(trashed) != 2 {
+		t.Fatalf("TakeTrashed() returned %d files, want 2", len(trashed))
+	}
+
+	for i, p := range []string{p1, p2} {
+		f := trashed[i]
+		if f.OriginalPath != p {
+			t.Errorf("OriginalPath = %q, want %q", f.OriginalPath, p)
+		}
+		if exists(p) {
+			t.Errorf("%s still exists", p)
+		}
+		if !exists(f.TrashPath) {
+			t.Errorf("%s does not exist", f.TrashPath)
+		}
+		if f.Size != 4 {
+			t.Errorf("Size = %d, want 4", f.Size)
+		}
+	}
+
+	if trashed[0].TrashPath == trashed[1].TrashPath {
+		t.Errorf("files with the same basename have the same trash path %q", trashed[0].TrashPath)
+	}
+
+	if got := d.TakeTrashed(); len(got) != 0 {
+		t.Errorf("second TakeTrashed() returned %d files, want 0", len(got))
+	}
+
+	d.Commit()
+
+	for _, f := range trashed {
+		if !exists(f.TrashPath) {
+			t.Errorf("%s deleted on commit", f.TrashPath)
+		}
+	}
+}
+
+func TestDeleter_TrashRollback(t *testing.T) {
+	dir := t.TempDir()
+
+	p := filepath.Join(dir, "a.jpg")
+	writeTestFile(t, p)
+
+	d := NewDeleter()
+	d.TrashPath = filepath.Join(dir, "trash")
+
+	if err := d.Trash([]string{p}); err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+
+	trashPath := d.trashed[0].TrashPath
+
+	d.Rollback()
+
+	if !exists(p) {
+		t.Errorf("%s not restored", p)
+	}
+
+	if exists(filepath.Dir(trashPath)) {
+		t.Errorf("trash directory %s not removed", filepath.Dir(trashPath))
+	}
+}
+
+func TestDeleter_TrashRenameError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantVolume bool
+	}{
+		{"cross device", syscall.EXDEV, true},
+		{"permission denied", os.ErrPermission, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			trashPath := filepath.Join(dir, "trash")
+
+			p := filepath.Join(dir, "a.mp4")
+			writeTestFile(t, p)
+
+			d := NewDeleter()
+			d.TrashPath = trashPath
+			d.TrashRenamer = &folderCreatorStatRenamerImpl{
+				renamerRemoverImpl: renamerRemoverImpl{
+					RenameFn: func(oldpath, newpath string) error {
+						return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: tt.err}
+					},
+					RemoveFn: os.Remove,
+					StatFn:   os.Stat,
+				},
+				mkDirFn: os.Mkdir,
+			}
+
+			err := d.Trash([]string{p})
+			if err == nil {
+				t.Fatal("Trash() error = nil")
+			}
+
+			if got := errors.Is(err, ErrTrashVolume); got != tt.wantVolume {
+				t.Errorf("errors.Is(%v, ErrTrashVolume) = %v, want %v", err, got, tt.wantVolume)
+			}
+
+			if !exists(p) {
+				t.Errorf("%s was moved", p)
+			}
+
+			entries, err := os.ReadDir(trashPath)
+			if err != nil {
+				t.Fatalf("reading trash directory: %v", err)
+			}
+			if len(entries) != 0 {
+				t.Errorf("trash directory contains %d entries, want 0", len(entries))
+			}
+		})
+	}
+}
+
+func TestDeleter_TrashDisabled(t *testing.T) {
+	dir := t.TempDir()
+
+	p := filepath.Join(dir, "a.mp4")
+	writeTestFile(t, p)
+
+	d := NewDeleter()
+
+	if err := d.Trash([]string{p}); err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+
+	if got := d.TakeTrashed(); len(got) != 0 {
+		t.Errorf("TakeTrashed() returned %d files, want 0", len(got))
+	}
+
+	d.Commit()
+
+	if exists(p) || exists(p+deleteFileSuffix) {
+		t.Errorf("%s not deleted", p)
+	}
+}
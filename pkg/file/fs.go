@@ -59,7 +59,7 @@ func (f *OsFS) Open(name string) (fs.ReadDirFile, error) {
 }
 
 func (f *OsFS) OpenZip(name string, size int64) (models.ZipFS, error) {
-	return newZipFS(f, name, size)
+	return newArchiveFS(f, name, size)
 }
 
 func (f *OsFS) IsPathCaseSensitive(path string) (bool, error) {
@@ -0,0 +1,94 @@
+package file
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// MountedFS is a file system that can be mounted in a MountFS.
+type MountedFS interface {
+	models.FS
+	Close() error
+}
+
+// MountFS is a file system backed by the OS, with other file systems
+// mounted at local paths. Operations on paths within a mount point are
+// passed to the mounted file system.
+type MountFS struct {
+	OsFS
+
+	mutex  sync.RWMutex
+	mounts map[string]MountedFS
+}
+
+// SetMounts replaces the mounted file systems with mounts, keyed by the
+// mount point. The previously mounted file systems are returned so that
+// they can be closed.
+func (f *MountFS) SetMounts(mounts map[string]MountedFS) map[string]MountedFS {
+	newMounts := make(map[string]MountedFS, len(mounts))
+	for p, m := range mounts {
+		newMounts[filepath.Clean(p)] = m
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	old := f.mounts
+	f.mounts = newMounts
+	return old
+}
+
+// Lookup returns the file system mounted at or above path, or nil if path
+// is not within a mount point.
+func (f *MountFS) Lookup(path string) MountedFS {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	var ret MountedFS
+	retPoint := ""
+	for p, m := range f.mounts {
+		// use the deepest mount point
+		if len(p) > len(retPoint) && fsutil.IsPathInDir(p, path) {
+			ret = m
+			retPoint = p
+		}
+	}
+
+	return ret
+}
+
+// IsMounted returns true if path is within a mount point.
+func (f *MountFS) IsMounted(path string) bool {
+	return f.Lookup(path) != nil
+}
+
+func (f *MountFS) fs(path string) models.FS {
+	if m := f.Lookup(path); m != nil {
+		return m
+	}
+	return &f.OsFS
+}
+
+func (f *MountFS) Stat(name string) (fs.FileInfo, error) {
+	return f.fs(name).Stat(name)
+}
+
+func (f *MountFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.fs(name).Lstat(name)
+}
+
+func (f *MountFS) Open(name string) (fs.ReadDirFile, error) {
+	return f.fs(name).Open(name)
+}
+
+func (f *MountFS) OpenZip(name string, size int64) (models.ZipFS, error) {
+	return f.fs(name).OpenZip(name, size)
+}
+
+func (f *MountFS) IsPathCaseSensitive(path string) (bool, error) {
+	return f.fs(path).IsPathCaseSensitive(path)
+}
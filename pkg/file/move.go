@@ -132,6 +132,29 @@ func (m *Mover) CreateFolderHierarchy(path string) error {
 	return nil
 }
 
+// MoveSidecar moves a file that is associated with a moved file, such as a
+// caption or funscript, which is not tracked in the database. It does
+// nothing if oldPath does not exist. The file is moved back if the
+// transaction is rolled back.
+func (m *Mover) MoveSidecar(oldPath, newPath string) error {
+	if oldPath == newPath {
+		return nil
+	}
+
+	if _, err := m.Renamer.Stat(oldPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("getting info for %s: %w", oldPath, err)
+	}
+
+	if _, err := m.Renamer.Stat(newPath); !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("file %s already exists", newPath)
+	}
+
+	return m.moveFile(oldPath, newPath)
+}
+
 func (m *Mover) moveFile(oldPath, newPath string) error {
 	if err := m.Renamer.Rename(oldPath, newPath); err != nil {
 		return fmt.Errorf("renaming file %s to %s: %w", oldPath, newPath, err)
@@ -0,0 +1,39 @@
+package file
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/nwaples/rardecode/v2"
+)
+
+// newRarReader returns a file system for a RAR file. Multi-volume archives
+// are not supported.
+func newRarReader(r io.ReaderAt, size int64, path string) (fs.FS, error) {
+	return newStreamFS(func() (archiveStream, error) {
+		rr, err := rardecode.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+
+		return &rarStream{Reader: rr}, nil
+	})
+}
+
+type rarStream struct {
+	*rardecode.Reader
+}
+
+func (s *rarStream) Next() (*archiveEntry, error) {
+	h, err := s.Reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveEntry{
+		name:    h.Name,
+		size:    h.UnPackedSize,
+		modTime: h.ModificationTime,
+		isDir:   h.IsDir,
+	}, nil
+}
@@ -0,0 +1,432 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	// DefaultRemoteMaxConnections is the default maximum number of concurrent
+	// connections to a remote server.
+	DefaultRemoteMaxConnections = 4
+
+	// remoteReadAheadSize is the number of bytes read from the server at a
+	// time when a remote file is read sequentially.
+	remoteReadAheadSize = 1024 * 1024
+)
+
+var errRemoteFSClosed = errors.New("remote file system is closed")
+
+// RemoteOptions are the options for connecting to a remote file server.
+type RemoteOptions struct {
+	// URL is the URL of the directory on the server that is mapped to the
+	// local path.
+	URL      string
+	Username string
+	Password string
+	// KeyFile is the path to a private key file used for SFTP authentication.
+	KeyFile string
+	// HostKey is the expected public key of an SFTP server, in authorized_keys
+	// format. The user's known_hosts file is used if empty.
+	HostKey string
+	// MaxConnections is the maximum number of concurrent connections to the
+	// server. DefaultRemoteMaxConnections is used if zero.
+	MaxConnections int
+}
+
+func (o RemoteOptions) maxConnections() int {
+	if o.MaxConnections <= 0 {
+		return DefaultRemoteMaxConnections
+	}
+	return o.MaxConnections
+}
+
+// remoteClient is a connection to a remote file server. Paths are slash
+// separated and absolute.
+type remoteClient interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadDir(name string) ([]fs.FileInfo, error)
+	// ReadAt reads from the file using the semantics of io.ReaderAt.
+	ReadAt(name string, p []byte, off int64) (int, error)
+	Close() error
+}
+
+// remotePool limits the number of concurrent connections to a server, and
+// reuses idle connections.
+type remotePool struct {
+	dial func() (remoteClient, error)
+
+	// sem limits the number of connections in use
+	sem chan struct{}
+
+	mutex  sync.Mutex
+	idle   []remoteClient
+	closed bool
+}
+
+func newRemotePool(maxConnections int, dial func() (remoteClient, error)) *remotePool {
+	return &remotePool{
+		dial: dial,
+		sem:  make(chan struct{}, maxConnections),
+	}
+}
+
+func (p *remotePool) get() (remoteClient, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return nil, errRemoteFSClosed
+	}
+
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		return c, nil
+	}
+
+	return p.dial()
+}
+
+func (p *remotePool) put(c remoteClient) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		_ = c.Close()
+		return
+	}
+
+	p.idle = append(p.idle, c)
+}
+
+// do calls fn with a connection from the pool. The connection is discarded
+// if fn returns an error other than the file not existing or reaching the
+// end of the file, since the connection may be broken.
+func (p *remotePool) do(fn func(c remoteClient) error) error {
+	p.sem <- struct{}{}
+	defer func() { <-p.sem }()
+
+	c, err := p.get()
+	if err != nil {
+		return err
+	}
+
+	err = fn(c)
+	if err == nil || errors.Is(err, fs.ErrNotExist) || errors.Is(err, io.EOF) {
+		p.put(c)
+	} else {
+		_ = c.Close()
+	}
+
+	return err
+}
+
+func (p *remotePool) close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.closed = true
+
+	var errs []error
+	for _, c := range p.idle {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	p.idle = nil
+
+	return errors.Join(errs...)
+}
+
+// RemoteFS is a read-only file system backed by a remote file server. Paths
+// within the local root directory are mapped to paths within the base
+// directory on the server.
+type RemoteFS struct {
+	root string
+	base string
+	pool *remotePool
+
+	// onClose is called when the file system is closed
+	onClose func()
+}
+
+func newRemoteFS(root string, base string, maxConnections int, dial func() (remoteClient, error)) *RemoteFS {
+	if base == "" {
+		base = "/"
+	}
+
+	return &RemoteFS{
+		root: filepath.Clean(root),
+		base: path.Clean(base),
+		pool: newRemotePool(maxConnections, dial),
+	}
+}
+
+// remoteError returns err as a *fs.PathError if the file does not exist.
+// Other errors, such as connection failures, are not returned as path errors,
+// since the cleaner treats path errors as the file not existing.
+func remoteError(op string, name string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	return fmt.Errorf("%s %s: %w", op, name, err)
+}
+
+// remotePath returns the path on the server of the local path name.
+func (f *RemoteFS) remotePath(name string) (string, error) {
+	if !fsutil.IsPathInDir(f.root, name) {
+		return "", fmt.Errorf("%s is not within %s", name, f.root)
+	}
+
+	rel, err := filepath.Rel(f.root, name)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(f.base, filepath.ToSlash(rel)), nil
+}
+
+func (f *RemoteFS) Stat(name string) (fs.FileInfo, error) {
+	p, err := f.remotePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret fs.FileInfo
+	if err := f.pool.do(func(c remoteClient) error {
+		ret, err = c.Stat(p)
+		return err
+	}); err != nil {
+		return nil, remoteError("stat", name, err)
+	}
+
+	return ret, nil
+}
+
+// Lstat returns the same as Stat. Symbolic links are resolved by the server.
+func (f *RemoteFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.Stat(name)
+}
+
+func (f *RemoteFS) Open(name string) (fs.ReadDirFile, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := f.remotePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remoteFile{
+		fs:   f,
+		name: name,
+		path: p,
+		info: info,
+	}, nil
+}
+
+func (f *RemoteFS) OpenZip(name string, size int64) (models.ZipFS, error) {
+	return newArchiveFS(f, name, size)
+}
+
+func (f *RemoteFS) IsPathCaseSensitive(path string) (bool, error) {
+	return true, nil
+}
+
+// Close closes all connections to the server. The file system cannot be
+// used once closed.
+func (f *RemoteFS) Close() error {
+	err := f.pool.close()
+	if f.onClose != nil {
+		f.onClose()
+	}
+	return err
+}
+
+// remoteFile is a file or directory on a remote server. Files support
+// random access, so can be served with range requests or read as archives.
+type remoteFile struct {
+	fs   *RemoteFS
+	name string
+	path string
+	info fs.FileInfo
+
+	offset int64
+
+	// read-ahead buffer for sequential reads
+	buf       []byte
+	bufOffset int64
+
+	entries     []fs.DirEntry
+	entriesRead bool
+}
+
+func (f *remoteFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *remoteFile) ReadAt(p []byte, off int64) (int, error) {
+	if f.info.IsDir() {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: errors.New("is a directory")}
+	}
+
+	if off >= f.info.Size() {
+		return 0, io.EOF
+	}
+
+	read := 0
+	for read < len(p) {
+		var n int
+		err := f.fs.pool.do(func(c remoteClient) error {
+			var err error
+			n, err = c.ReadAt(f.path, p[read:], off+int64(read))
+			return err
+		})
+		read += n
+
+		if err != nil {
+			return read, err
+		}
+		if n == 0 {
+			return read, io.ErrNoProgress
+		}
+	}
+
+	return read, nil
+}
+
+func (f *remoteFile) Read(p []byte) (int, error) {
+	if f.offset >= f.info.Size() {
+		return 0, io.EOF
+	}
+
+	bufEnd := f.bufOffset + int64(len(f.buf))
+	if f.offset < f.bufOffset || f.offset >= bufEnd {
+		size := f.info.Size() - f.offset
+		if size > remoteReadAheadSize {
+			size = remoteReadAheadSize
+		}
+
+		if cap(f.buf) < int(size) {
+			f.buf = make([]byte, size)
+		}
+		f.buf = f.buf[:size]
+
+		n, err := f.ReadAt(f.buf, f.offset)
+		f.buf = f.buf[:n]
+		f.bufOffset = f.offset
+		if n == 0 && err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, f.buf[f.offset-f.bufOffset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *remoteFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.Size()
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid offset %d", offset)
+	}
+
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *remoteFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !f.info.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: f.name, Err: errors.New("not a directory")}
+	}
+
+	if !f.entriesRead {
+		var infos []fs.FileInfo
+		if err := f.fs.pool.do(func(c remoteClient) error {
+			var err error
+			infos, err = c.ReadDir(f.path)
+			return err
+		}); err != nil {
+			return nil, remoteError("readdir", f.name, err)
+		}
+
+		for _, info := range infos {
+			f.entries = append(f.entries, fs.FileInfoToDirEntry(info))
+		}
+		f.entriesRead = true
+	}
+
+	if n > 0 && len(f.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	ret := f.entries
+	if n > 0 && n < len(ret) {
+		ret = ret[:n]
+	}
+	f.entries = f.entries[len(ret):]
+
+	return ret, nil
+}
+
+func (f *remoteFile) Close() error {
+	f.buf = nil
+	return nil
+}
+
+// remoteFileInfo is the information of a file returned by a remote server.
+type remoteFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (i *remoteFileInfo) Name() string {
+	return i.name
+}
+
+func (i *remoteFileInfo) Size() int64 {
+	return i.size
+}
+
+func (i *remoteFileInfo) Mode() fs.FileMode {
+	if i.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (i *remoteFileInfo) ModTime() time.Time {
+	return i.modTime
+}
+
+func (i *remoteFileInfo) IsDir() bool {
+	return i.isDir
+}
+
+func (i *remoteFileInfo) Sys() interface{} {
+	return nil
+}
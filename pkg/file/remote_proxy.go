@@ -0,0 +1,146 @@
+package file
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ServeFile serves the file at path in fsys, supporting range requests if
+// the file can be seeked.
+func ServeFile(fsys models.FS, w http.ResponseWriter, r *http.Request, path string) error {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return &fs.PathError{Op: "serve", Path: path, Err: errors.New("is a directory")}
+	}
+
+	if rs, ok := f.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, info.Name(), info.ModTime(), rs)
+		return nil
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// RemoteProxy is a HTTP server listening on the loopback interface that
+// serves files from a file system. It allows remote files to be read by
+// external programs, such as ffmpeg, that accept a URL as input. Requests
+// must include a random token, so that other local users cannot read the
+// files.
+type RemoteProxy struct {
+	FS models.FS
+
+	// mu guards the fields below, which are set when the proxy is started
+	mu       sync.RWMutex
+	listener net.Listener
+	server   *http.Server
+	token    string
+}
+
+// Start starts the proxy server on a random port, if it is not already
+// running.
+func (p *RemoteProxy) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.listener != nil {
+		return nil
+	}
+
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p.serveHTTP(token, w, r)
+		}),
+	}
+
+	p.token = token
+	p.listener = l
+	p.server = server
+
+	go func() {
+		if err := server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("remote file proxy: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// URL returns the proxy URL of the file at path. The path is returned
+// unchanged if the proxy is not running.
+func (p *RemoteProxy) URL(path string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.listener == nil {
+		return path
+	}
+
+	u := url.URL{
+		Scheme:   "http",
+		Host:     p.listener.Addr().String(),
+		Path:     "/" + p.token,
+		RawQuery: url.Values{"path": []string{path}}.Encode(),
+	}
+	return u.String()
+}
+
+func (p *RemoteProxy) serveHTTP(token string, w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/"+token {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+	if err := ServeFile(p.FS, w, r, path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+
+		logger.Warnf("remote file proxy: error serving %s: %v", path, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// Close stops the proxy server.
+func (p *RemoteProxy) Close() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.server == nil {
+		return nil
+	}
+	return p.server.Close()
+}
@@ -0,0 +1,462 @@
+package file
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/webdav"
+)
+
+const (
+	remoteTestUser     = "user"
+	remoteTestPassword = "password"
+)
+
+// remoteTestRoot is the local path that remote test directories are mapped to
+var remoteTestRoot = filepath.FromSlash("/library")
+
+// makeRemoteTestDir creates the files served by the test servers, returning
+// the contents of each file keyed by the slash separated path.
+func makeRemoteTestDir(t *testing.T, dir string) map[string][]byte {
+	// large file is bigger than the read-ahead buffer
+	large := make([]byte, remoteReadAheadSize*2+1234)
+	if _, err := rand.Read(large); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{
+		"dir/a.txt": []byte("file a"),
+		"large.bin": large,
+		"c.zip":     makeTestZip(t),
+	}
+
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return files
+}
+
+func newTestWebDAVServer(t *testing.T, dir string) *httptest.Server {
+	h := &webdav.Handler{
+		FileSystem: webdav.Dir(dir),
+		LockSystem: webdav.NewMemLS(),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != remoteTestUser || p != remoteTestPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// newTestSFTPServer starts a SFTP server serving the local file system,
+// returning its address and host key.
+func newTestSFTPServer(t *testing.T) (string, string) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == remoteTestUser && string(pass) == remoteTestPassword {
+				return nil, nil
+			}
+			return nil, errors.New("invalid credentials")
+		},
+	}
+	config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSFTPConn(conn, config)
+		}
+	}()
+
+	return l.Addr().String(), string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
+
+func serveTestSFTPConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			for req := range requests {
+				// payload is the length prefixed subsystem name
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+
+				if ok {
+					server, err := sftp.NewServer(channel, sftp.ReadOnly())
+					if err != nil {
+						return
+					}
+					_ = server.Serve()
+					server.Close()
+					return
+				}
+			}
+		}()
+	}
+}
+
+func testRemoteFS(t *testing.T, fsys *RemoteFS, files map[string][]byte) {
+	localPath := func(name string) string {
+		return filepath.Join(remoteTestRoot, filepath.FromSlash(name))
+	}
+
+	t.Run("read dir", func(t *testing.T) {
+		d, err := fsys.Open(remoteTestRoot)
+		if err != nil {
+			t.Fatalf("Open(root) error = %v", err)
+		}
+		defer d.Close()
+
+		entries, err := d.ReadDir(-1)
+		if err != nil {
+			t.Fatalf("ReadDir(root) error = %v", err)
+		}
+
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		sort.Strings(names)
+
+		if want := []string{"c.zip", "dir", "large.bin"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("ReadDir(root) = %v, want %v", names, want)
+		}
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		info, err := fsys.Stat(localPath("dir"))
+		if err != nil {
+			t.Fatalf("Stat(dir) error = %v", err)
+		}
+		if !info.IsDir() {
+			t.Errorf("Stat(dir).IsDir() = false")
+		}
+
+		for name, data := range files {
+			info, err := fsys.Stat(localPath(name))
+			if err != nil {
+				t.Fatalf("Stat(%s) error = %v", name, err)
+			}
+			if info.IsDir() || info.Size() != int64(len(data)) {
+				t.Errorf("Stat(%s) = dir %v, size %d", name, info.IsDir(), info.Size())
+			}
+		}
+	})
+
+	t.Run("not exist", func(t *testing.T) {
+		_, err := fsys.Stat(localPath("missing.txt"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Stat(missing.txt) error = %v, want not exist", err)
+		}
+		if !isNotFound(err) {
+			t.Errorf("isNotFound(Stat(missing.txt)) = false")
+		}
+	})
+
+	t.Run("read", func(t *testing.T) {
+		for name, data := range files {
+			f, err := fsys.Open(localPath(name))
+			if err != nil {
+				t.Fatalf("Open(%s) error = %v", name, err)
+			}
+
+			got, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				t.Fatalf("reading %s: %v", name, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("contents of %s differ", name)
+			}
+		}
+	})
+
+	t.Run("seek and read at", func(t *testing.T) {
+		data := files["large.bin"]
+		f, err := fsys.Open(localPath("large.bin"))
+		if err != nil {
+			t.Fatalf("Open(large.bin) error = %v", err)
+		}
+		defer f.Close()
+
+		rs := f.(io.ReadSeeker)
+		const offset = remoteReadAheadSize + 10
+		if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+			t.Fatalf("Seek() error = %v", err)
+		}
+
+		got := make([]byte, 100)
+		if _, err := io.ReadFull(rs, got); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if !bytes.Equal(got, data[offset:offset+100]) {
+			t.Errorf("Read() after Seek() returned wrong data")
+		}
+
+		ra := f.(io.ReaderAt)
+		tail := make([]byte, 100)
+		n, err := ra.ReadAt(tail, int64(len(data)-50))
+		if n != 50 || !errors.Is(err, io.EOF) {
+			t.Errorf("ReadAt(end) = %d, %v, want 50, EOF", n, err)
+		}
+		if !bytes.Equal(tail[:n], data[len(data)-50:]) {
+			t.Errorf("ReadAt(end) returned wrong data")
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		zipPath := localPath("c.zip")
+		zfs, err := fsys.OpenZip(zipPath, int64(len(files["c.zip"])))
+		if err != nil {
+			t.Fatalf("OpenZip() error = %v", err)
+		}
+		defer zfs.Close()
+
+		f := archiveTestFiles[0]
+		r, err := zfs.Open(filepath.Join(zipPath, filepath.FromSlash(f.name)))
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", f.name, err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", f.name, err)
+		}
+		if string(got) != f.content {
+			t.Errorf("contents of %s = %q, want %q", f.name, got, f.content)
+		}
+	})
+}
+
+func TestWebDAVFS(t *testing.T) {
+	dir := t.TempDir()
+	files := makeRemoteTestDir(t, filepath.Join(dir, "media"))
+	srv := newTestWebDAVServer(t, dir)
+
+	fsys, err := NewWebDAVFS(remoteTestRoot, RemoteOptions{
+		URL:      srv.URL + "/media",
+		Username: remoteTestUser,
+		Password: remoteTestPassword,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	testRemoteFS(t, fsys, files)
+
+	t.Run("unauthorized", func(t *testing.T) {
+		fsys, err := NewWebDAVFS(remoteTestRoot, RemoteOptions{
+			URL: srv.URL + "/media",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fsys.Close()
+
+		if _, err := fsys.Stat(remoteTestRoot); !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("Stat() error = %v, want permission error", err)
+		}
+	})
+}
+
+func TestSFTPFS(t *testing.T) {
+	dir := t.TempDir()
+	files := makeRemoteTestDir(t, dir)
+	addr, hostKey := newTestSFTPServer(t)
+
+	fsys, err := NewSFTPFS(remoteTestRoot, RemoteOptions{
+		URL:            "sftp://" + addr + filepath.ToSlash(dir),
+		Username:       remoteTestUser,
+		Password:       remoteTestPassword,
+		HostKey:        hostKey,
+		MaxConnections: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	testRemoteFS(t, fsys, files)
+
+	t.Run("wrong host key", func(t *testing.T) {
+		_, otherKey := newTestSFTPServer(t)
+		fsys, err := NewSFTPFS(remoteTestRoot, RemoteOptions{
+			URL:      "sftp://" + addr + filepath.ToSlash(dir),
+			Username: remoteTestUser,
+			Password: remoteTestPassword,
+			HostKey:  otherKey,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer fsys.Close()
+
+		_, err = fsys.Stat(remoteTestRoot)
+		if err == nil {
+			t.Fatalf("Stat() expected error")
+		}
+		// connection errors must not be mistaken for missing files
+		if isNotFound(err) {
+			t.Errorf("isNotFound(%v) = true", err)
+		}
+	})
+}
+
+func TestRemoteProxy(t *testing.T) {
+	dir := t.TempDir()
+	files := makeRemoteTestDir(t, dir)
+	srv := newTestWebDAVServer(t, dir)
+
+	remote, err := NewWebDAVFS(remoteTestRoot, RemoteOptions{
+		URL:      srv.URL,
+		Username: remoteTestUser,
+		Password: remoteTestPassword,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mfs := &MountFS{}
+	mfs.SetMounts(map[string]MountedFS{remoteTestRoot: remote})
+	defer func() {
+		for _, m := range mfs.SetMounts(nil) {
+			m.Close()
+		}
+	}()
+
+	name := filepath.Join(remoteTestRoot, "large.bin")
+	if !mfs.IsMounted(name) {
+		t.Fatalf("IsMounted(%s) = false", name)
+	}
+	if mfs.IsMounted(filepath.Join(dir, "large.bin")) {
+		t.Errorf("IsMounted(local path) = true")
+	}
+
+	proxy := &RemoteProxy{FS: mfs}
+	if err := proxy.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+
+	req, err := http.NewRequest(http.MethodGet, proxy.URL(name), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Range", "bytes=100-199")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusPartialContent {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusPartialContent)
+	}
+	if !bytes.Equal(got, files["large.bin"][100:200]) {
+		t.Errorf("proxy returned wrong data")
+	}
+
+	// requests without the token are rejected
+	resp, err = http.Get("http://" + proxy.listener.Addr().String() + "/?path=" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status without token = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestRemoteProxy_ConcurrentStart(t *testing.T) {
+	proxy := &RemoteProxy{FS: &OsFS{}}
+	defer proxy.Close()
+
+	const n = 4
+	urls := make(chan string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := proxy.Start(); err != nil {
+				t.Error(err)
+			}
+			urls <- proxy.URL("file.mp4")
+		}()
+	}
+	wg.Wait()
+	close(urls)
+
+	want := proxy.URL("file.mp4")
+	for u := range urls {
+		if u != want {
+			t.Errorf("URL() = %q, want %q", u, want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/txn"
@@ -123,7 +124,7 @@ type scanJob struct {
 type ScanOptions struct {
 	Paths []string
 
-	// ZipFileExtensions is a list of file extensions that are considered zip files.
+	// ZipFileExtensions is a list of file extensions that are considered archive files.
 	// Extension does not include the . character.
 	ZipFileExtensions []string
 
@@ -684,14 +685,7 @@ func (s *scanJob) handleFile(ctx context.Context, f scanFile) error {
 }
 
 func (s *scanJob) isZipFile(path string) bool {
-	fExt := filepath.Ext(path)
-	for _, ext := range s.options.ZipFileExtensions {
-		if strings.EqualFold(fExt, "."+ext) {
-			return true
-		}
-	}
-
-	return false
+	return fsutil.MatchExtension(path, s.options.ZipFileExtensions)
 }
 
 func (s *scanJob) onNewFile(ctx context.Context, f scanFile) (models.File, error) {
@@ -0,0 +1,209 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	sftpDefaultPort = "22"
+	sftpDialTimeout = 30 * time.Second
+)
+
+var errSFTPNoHostKey = errors.New("host key must be set, or the server must be in the known_hosts file")
+
+// NewSFTPFS returns a read-only file system that maps paths within the local
+// root directory to the SFTP server directory at opts.URL. The server's host
+// key is verified against opts.HostKey if set, otherwise against the user's
+// known_hosts file.
+func NewSFTPFS(root string, opts RemoteOptions) (*RemoteFS, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SFTP URL: %w", err)
+	}
+
+	if u.Scheme != "sftp" {
+		return nil, fmt.Errorf("unsupported SFTP URL scheme %q", u.Scheme)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), sftpDefaultPort)
+	}
+
+	username := opts.Username
+	password := opts.Password
+	if u.User != nil {
+		if username == "" {
+			username = u.User.Username()
+		}
+		if p, set := u.User.Password(); set && password == "" {
+			password = p
+		}
+	}
+
+	auth, err := sftpAuthMethods(opts.KeyFile, password)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := sftpHostKeyCallback(opts.HostKey)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sftpDialTimeout,
+	}
+
+	return newRemoteFS(root, u.Path, opts.maxConnections(), func() (remoteClient, error) {
+		c, err := dialSFTP(addr, config)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}), nil
+}
+
+func sftpAuthMethods(keyFile string, password string) ([]ssh.AuthMethod, error) {
+	var ret []ssh.AuthMethod
+
+	if keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+		var missingErr *ssh.PassphraseMissingError
+		if errors.As(err, &missingErr) && password != "" {
+			// the password is the passphrase of the key
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(key, []byte(password))
+			password = ""
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing key file: %w", err)
+		}
+
+		ret = append(ret, ssh.PublicKeys(signer))
+	}
+
+	if password != "" {
+		ret = append(ret, ssh.Password(password))
+	}
+
+	return ret, nil
+}
+
+func sftpHostKeyCallback(hostKey string) (ssh.HostKeyCallback, error) {
+	if hostKey != "" {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey))
+		if err != nil {
+			return nil, fmt.Errorf("parsing host key: %w", err)
+		}
+		return ssh.FixedHostKey(key), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, errSFTPNoHostKey
+	}
+
+	knownHostsFile := filepath.Join(home, ".ssh", "known_hosts")
+	if _, err := os.Stat(knownHostsFile); err != nil {
+		return nil, errSFTPNoHostKey
+	}
+
+	return knownhosts.New(knownHostsFile)
+}
+
+type sftpClient struct {
+	conn   *ssh.Client
+	client *sftp.Client
+}
+
+func dialSFTP(addr string, config *ssh.ClientConfig) (*sftpClient, error) {
+	conn, err := ssh.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("starting sftp session on %s: %w", addr, err)
+	}
+
+	return &sftpClient{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+func (c *sftpClient) Stat(name string) (fs.FileInfo, error) {
+	return c.client.Stat(name)
+}
+
+func (c *sftpClient) ReadDir(name string) ([]fs.FileInfo, error) {
+	entries, err := c.client.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]fs.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if e.Mode()&fs.ModeSymlink != 0 {
+			// resolve symbolic links, ignoring any that are broken
+			target, err := c.client.Stat(path.Join(name, e.Name()))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			e = &remoteFileInfo{
+				name:    e.Name(),
+				size:    target.Size(),
+				modTime: target.ModTime(),
+				isDir:   target.IsDir(),
+			}
+		}
+
+		ret = append(ret, e)
+	}
+
+	return ret, nil
+}
+
+func (c *sftpClient) ReadAt(name string, p []byte, off int64) (int, error) {
+	f, err := c.client.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return f.ReadAt(p, off)
+}
+
+func (c *sftpClient) Close() error {
+	err := c.client.Close()
+	if connErr := c.conn.Close(); err == nil {
+		err = connErr
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// newTarReader returns a file system for an uncompressed tar file. The
+// contents of regular files are read directly from the tar file.
+func newTarReader(r io.ReaderAt, size int64, path string) (fs.FS, error) {
+	return newStreamFS(func() (archiveStream, error) {
+		sr := io.NewSectionReader(r, 0, size)
+		return &tarStream{
+			Reader: tar.NewReader(sr),
+			data:   sr,
+			r:      r,
+		}, nil
+	})
+}
+
+// newTarGzipReader returns a file system for a gzip compressed tar file.
+func newTarGzipReader(r io.ReaderAt, size int64, path string) (fs.FS, error) {
+	return newStreamFS(func() (archiveStream, error) {
+		gr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+
+		return &tarStream{
+			Reader: tar.NewReader(gr),
+		}, nil
+	})
+}
+
+type tarStream struct {
+	*tar.Reader
+
+	// data and r are set if the tar file is uncompressed, so that the
+	// location of each file can be determined.
+	data io.Seeker
+	r    io.ReaderAt
+}
+
+func (s *tarStream) Next() (*archiveEntry, error) {
+	for {
+		h, err := s.Reader.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		ret := &archiveEntry{
+			name:    h.Name,
+			size:    h.Size,
+			modTime: h.ModTime,
+		}
+
+		switch h.Typeflag {
+		case tar.TypeDir:
+			ret.isDir = true
+			ret.size = 0
+		case tar.TypeReg:
+			if s.data != nil && !isSparseTarFile(h) {
+				// the reader is positioned at the start of the file data
+				offset, err := s.data.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return nil, err
+				}
+				ret.section = io.NewSectionReader(s.r, offset, h.Size)
+			}
+		default:
+			// links and special files are ignored
+			continue
+		}
+
+		return ret, nil
+	}
+}
+
+// isSparseTarFile returns true if the file data is stored in sparse format,
+// and so cannot be read directly from the tar file.
+func isSparseTarFile(h *tar.Header) bool {
+	for k := range h.PAXRecords {
+		if strings.HasPrefix(k, "GNU.sparse.") {
+			return true
+		}
+	}
+
+	return false
+}
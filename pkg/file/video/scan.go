@@ -43,6 +43,16 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 		interactive = true
 	}
 
+	// non-nil so that the file is not treated as missing chapters
+	chapters := []models.VideoChapter{}
+	for _, c := range videoFile.Chapters {
+		chapters = append(chapters, models.VideoChapter{
+			Start: c.Start,
+			End:   c.End,
+			Title: c.Title,
+		})
+	}
+
 	return &models.VideoFile{
 		BaseFile:    base,
 		Format:      string(container),
@@ -54,6 +64,7 @@ func (d *Decorator) Decorate(ctx context.Context, fs models.FS, f models.File) (
 		FrameRate:   videoFile.FrameRate,
 		BitRate:     videoFile.Bitrate,
 		Interactive: interactive,
+		Chapters:    chapters,
 	}, nil
 }
 
@@ -77,5 +88,6 @@ func (d *Decorator) IsMissingMetadata(ctx context.Context, fs models.FS, f model
 		vf.Format == unsetString || vf.Width == unsetNumber ||
 		vf.Height == unsetNumber || vf.FrameRate == unsetNumber ||
 		vf.Duration == unsetNumber ||
-		vf.BitRate == unsetNumber || interactive != vf.Interactive
+		vf.BitRate == unsetNumber || interactive != vf.Interactive ||
+		vf.Chapters == nil
 }
@@ -0,0 +1,53 @@
+package video
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func TestDecorator_IsMissingMetadata(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newFile := func(chapters []models.VideoChapter) *models.VideoFile {
+		return &models.VideoFile{
+			BaseFile:   &models.BaseFile{Path: path},
+			Format:     "mp4",
+			VideoCodec: "h264",
+			AudioCodec: "aac",
+			Width:      1920,
+			Height:     1080,
+			Duration:   60,
+			FrameRate:  30,
+			BitRate:    4000000,
+			Chapters:   chapters,
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    *models.VideoFile
+		want bool
+	}{
+		{"chapters not probed", newFile(nil), true},
+		{"no chapters", newFile([]models.VideoChapter{}), false},
+		{"chapters", newFile([]models.VideoChapter{{Start: 0, Title: "Intro"}}), false},
+	}
+
+	d := &Decorator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.IsMissingMetadata(context.Background(), &file.OsFS{}, tt.f); got != tt.want {
+				t.Errorf("Decorator.IsMissingMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// WatchBatch is a set of paths that have changed since the last batch.
+type WatchBatch struct {
+	// Changed contains paths that were created, modified or renamed to.
+	Changed []string
+	// Removed contains paths that were deleted or renamed from.
+	Removed []string
+}
+
+// WatchHandler is called with each batch of changes reported by a Watcher.
+type WatchHandler func(ctx context.Context, batch WatchBatch)
+
+// Watcher watches directory trees for changes to files and folders.
+//
+// Events are debounced per path. A path is only reported once no events
+// have been received for it for the Debounce duration, so that files that
+// are still being written are not reported until they are complete.
+//
+// Paths are collated when reported. Paths that exist are reported as changed,
+// and paths that no longer exist are reported as removed. A rename is reported
+// as the old path being removed and the new path being changed. Paths that are
+// within another reported path of the same kind are omitted.
+type Watcher struct {
+	Debounce time.Duration
+	Handler  WatchHandler
+}
+
+// Watch watches the provided directories and their subdirectories until
+// the context is cancelled. Directories created while watching are watched
+// automatically.
+func (w *Watcher) Watch(ctx context.Context, paths []string) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	for _, p := range paths {
+		w.addTree(fsw, p)
+	}
+
+	// time of the last event received for each path
+	pending := make(map[string]time.Time)
+
+	timer := time.NewTimer(w.Debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warnf("[watcher] %v", err)
+		case e, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+
+			if e.Has(fsnotify.Create) {
+				if info, err := os.Stat(e.Name); err == nil && info.IsDir() {
+					w.addTree(fsw, e.Name)
+				}
+			}
+
+			if e.Has(fsnotify.Rename) {
+				// watches on renamed directories are not updated with the new
+				// path. The new path is added when its create event is received.
+				_ = fsw.Remove(e.Name)
+			}
+
+			// permission changes alone don't affect the scan
+			if e.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if len(pending) == 0 {
+				timer.Reset(w.Debounce)
+			}
+			pending[e.Name] = time.Now()
+		case <-timer.C:
+			now := time.Now()
+			var ready []string
+			next := w.Debounce
+			for p, t := range pending {
+				if elapsed := now.Sub(t); elapsed >= w.Debounce {
+					ready = append(ready, p)
+					delete(pending, p)
+				} else if w.Debounce-elapsed < next {
+					next = w.Debounce - elapsed
+				}
+			}
+
+			// changes within a ready directory are covered by it, even if
+			// they are more recent
+			if len(ready) > 0 {
+				readySet := make(map[string]struct{}, len(ready))
+				for _, p := range ready {
+					readySet[p] = struct{}{}
+				}
+				for p := range pending {
+					if hasAncestor(readySet, p) {
+						ready = append(ready, p)
+						delete(pending, p)
+					}
+				}
+			}
+
+			if len(pending) > 0 {
+				timer.Reset(next)
+			}
+
+			if len(ready) > 0 {
+				batch := collateWatchPaths(ready, pathExists)
+				w.Handler(ctx, batch)
+			}
+		}
+	}
+}
+
+// addTree adds a watch for the directory and all of its subdirectories.
+func (w *Watcher) addTree(fsw *fsnotify.Watcher, root string) {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logger.Warnf("[watcher] error walking %s: %v", path, err)
+			return nil
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if err := fsw.Add(path); err != nil {
+			// inotify returns ENOSPC when the watch limit has been reached.
+			// There is no point trying to add any more.
+			if errors.Is(err, syscall.ENOSPC) {
+				return err
+			}
+			logger.Warnf("[watcher] error watching %s: %v", path, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Warnf("[watcher] could not watch all directories in %s: %v", root, err)
+	}
+}
+
+func pathExists(p string) bool {
+	_, err := os.Lstat(p)
+	return err == nil
+}
+
+// collateWatchPaths sorts the paths into changed and removed paths, omitting
+// paths within another path of the same kind.
+func collateWatchPaths(paths []string, exists func(string) bool) WatchBatch {
+	var ret WatchBatch
+	for _, p := range paths {
+		if exists(p) {
+			ret.Changed = append(ret.Changed, p)
+		} else {
+			ret.Removed = append(ret.Removed, p)
+		}
+	}
+
+	ret.Changed = outermostPaths(ret.Changed)
+	ret.Removed = outermostPaths(ret.Removed)
+
+	return ret
+}
+
+// outermostPaths returns the sorted paths that are not within another of
+// the paths.
+func outermostPaths(paths []string) []string {
+	set := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		set[filepath.Clean(p)] = struct{}{}
+	}
+
+	var ret []string
+	for p := range set {
+		if !hasAncestor(set, p) {
+			ret = append(ret, p)
+		}
+	}
+
+	sort.Strings(ret)
+	return ret
+}
+
+// hasAncestor returns true if any of the parent directories of p are in set.
+func hasAncestor(set map[string]struct{}, p string) bool {
+	for child, dir := p, filepath.Dir(p); dir != child; child, dir = dir, filepath.Dir(dir) {
+		if _, found := set[dir]; found {
+			return true
+		}
+	}
+
+	return false
+}
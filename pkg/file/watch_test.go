@@ -0,0 +1,119 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollateWatchPaths(t *testing.T) {
+	existing := map[string]bool{
+		filepath.FromSlash("/stash/new"):           true,
+		filepath.FromSlash("/stash/new/a.mp4"):     true,
+		filepath.FromSlash("/stash/new/sub/b.mp4"): true,
+		filepath.FromSlash("/stash/new folder"):    true,
+		filepath.FromSlash("/stash/c.mp4"):         true,
+	}
+	exists := func(p string) bool {
+		return existing[p]
+	}
+
+	paths := []string{
+		"/stash/new/sub/b.mp4",
+		"/stash/c.mp4",
+		"/stash/new",
+		"/stash/new/a.mp4",
+		"/stash/new folder",
+		"/stash/old",
+		"/stash/old/d.mp4",
+		"/stash/e.mp4",
+	}
+	for i := range paths {
+		paths[i] = filepath.FromSlash(paths[i])
+	}
+
+	got := collateWatchPaths(paths, exists)
+
+	want := WatchBatch{
+		Changed: []string{
+			filepath.FromSlash("/stash/c.mp4"),
+			filepath.FromSlash("/stash/new"),
+			filepath.FromSlash("/stash/new folder"),
+		},
+		Removed: []string{
+			filepath.FromSlash("/stash/e.mp4"),
+			filepath.FromSlash("/stash/old"),
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collateWatchPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	root := t.TempDir()
+	existingDir := filepath.Join(root, "existing")
+	if err := os.Mkdir(existingDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	removedFile := filepath.Join(existingDir, "removed.mp4")
+	if err := os.WriteFile(removedFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	batches := make(chan WatchBatch, 10)
+	w := &Watcher{
+		Debounce: 100 * time.Millisecond,
+		Handler: func(ctx context.Context, batch WatchBatch) {
+			batches <- batch
+		},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- w.Watch(ctx, []string{root})
+	}()
+
+	// allow the watches to be added
+	time.Sleep(100 * time.Millisecond)
+
+	newDir := filepath.Join(root, "new")
+	if err := os.Mkdir(newDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	renamedFile := filepath.Join(newDir, "renamed.mp4")
+	if err := os.Rename(removedFile, renamedFile); err != nil {
+		t.Fatal(err)
+	}
+
+	var got WatchBatch
+	for len(got.Changed) == 0 || len(got.Removed) == 0 {
+		select {
+		case b := <-batches:
+			got.Changed = append(got.Changed, b.Changed...)
+			got.Removed = append(got.Removed, b.Removed...)
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for changes, got %v", got)
+		}
+	}
+
+	want := WatchBatch{
+		Changed: []string{newDir},
+		Removed: []string{removedFile},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Watch() reported %v, want %v", got, want)
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Errorf("Watch() error = %v", err)
+	}
+}
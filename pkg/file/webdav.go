@@ -0,0 +1,294 @@
+package file
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const webdavTimeout = 30 * time.Second
+
+// propfindBody requests the properties needed to stat a file.
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:"><D:prop><D:resourcetype/><D:getcontentlength/><D:getlastmodified/></D:prop></D:propfind>`
+
+// NewWebDAVFS returns a read-only file system that maps paths within the
+// local root directory to the WebDAV server directory at opts.URL.
+func NewWebDAVFS(root string, opts RemoteOptions) (*RemoteFS, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebDAV URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+	case "webdav":
+		u.Scheme = "http"
+	case "webdavs":
+		u.Scheme = "https"
+	default:
+		return nil, fmt.Errorf("unsupported WebDAV URL scheme %q", u.Scheme)
+	}
+
+	username := opts.Username
+	password := opts.Password
+	if u.User != nil {
+		if username == "" {
+			username = u.User.Username()
+		}
+		if p, set := u.User.Password(); set && password == "" {
+			password = p
+		}
+		u.User = nil
+	}
+
+	maxConnections := opts.maxConnections()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = maxConnections
+	transport.MaxIdleConnsPerHost = maxConnections
+
+	c := &webdavClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   webdavTimeout,
+		},
+		base:     u,
+		username: username,
+		password: password,
+	}
+
+	ret := newRemoteFS(root, u.Path, maxConnections, func() (remoteClient, error) {
+		// http connections are pooled by the transport
+		return c, nil
+	})
+	ret.onClose = transport.CloseIdleConnections
+
+	return ret, nil
+}
+
+type webdavClient struct {
+	client   *http.Client
+	base     *url.URL
+	username string
+	password string
+}
+
+func (c *webdavClient) newRequest(method string, name string, body io.Reader) (*http.Request, error) {
+	u := *c.base
+	u.Path = name
+	u.RawPath = ""
+
+	req, err := http.NewRequestWithContext(context.Background(), method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return req, nil
+}
+
+// statusError returns the error for an unexpected response status.
+func (c *webdavClient) statusError(req *http.Request, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return fs.ErrNotExist
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fs.ErrPermission
+	}
+
+	return fmt.Errorf("webdav %s %s: %s", req.Method, req.URL.Path, resp.Status)
+}
+
+func (c *webdavClient) propfind(name string, depth string) ([]webdavResponse, error) {
+	req, err := c.newRequest("PROPFIND", name, strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", depth)
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, c.statusError(req, resp)
+	}
+
+	var ms webdavMultiStatus
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("parsing webdav response for %s: %w", name, err)
+	}
+
+	return ms.Responses, nil
+}
+
+func (c *webdavClient) Stat(name string) (fs.FileInfo, error) {
+	responses, err := c.propfind(name, "0")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(responses) == 0 {
+		return nil, fs.ErrNotExist
+	}
+
+	return responses[0].fileInfo(name)
+}
+
+func (c *webdavClient) ReadDir(name string) ([]fs.FileInfo, error) {
+	// directories are requested with a trailing slash to avoid redirects
+	dir := strings.TrimSuffix(name, "/") + "/"
+	responses, err := c.propfind(dir, "1")
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []fs.FileInfo
+	for _, r := range responses {
+		p, err := r.path()
+		if err != nil {
+			return nil, err
+		}
+
+		// the directory itself is included in the response
+		if p == path.Clean(name) {
+			continue
+		}
+
+		info, err := r.fileInfo(p)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, info)
+	}
+
+	return ret, nil
+}
+
+func (c *webdavClient) ReadAt(name string, p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	req, err := c.newRequest(http.MethodGet, name, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// the server does not support range requests
+		if _, err := io.CopyN(io.Discard, resp.Body, off); err != nil {
+			return 0, err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		return 0, io.EOF
+	default:
+		return 0, c.statusError(req, resp)
+	}
+
+	n, err := io.ReadFull(resp.Body, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+
+	return n, err
+}
+
+func (c *webdavClient) Close() error {
+	return nil
+}
+
+type webdavMultiStatus struct {
+	Responses []webdavResponse `xml:"DAV: response"`
+}
+
+type webdavResponse struct {
+	Href     string           `xml:"DAV: href"`
+	PropStat []webdavPropStat `xml:"DAV: propstat"`
+}
+
+type webdavPropStat struct {
+	Prop   webdavProp `xml:"DAV: prop"`
+	Status string     `xml:"DAV: status"`
+}
+
+type webdavProp struct {
+	ResourceType  webdavResourceType `xml:"DAV: resourcetype"`
+	ContentLength string             `xml:"DAV: getcontentlength"`
+	LastModified  string             `xml:"DAV: getlastmodified"`
+}
+
+type webdavResourceType struct {
+	Collection *struct{} `xml:"DAV: collection"`
+}
+
+func (r *webdavResponse) path() (string, error) {
+	u, err := url.Parse(r.Href)
+	if err != nil {
+		return "", fmt.Errorf("invalid href %q: %w", r.Href, err)
+	}
+
+	return path.Clean("/" + u.Path), nil
+}
+
+func (r *webdavResponse) fileInfo(name string) (fs.FileInfo, error) {
+	ret := &remoteFileInfo{
+		name: path.Base(name),
+	}
+
+	for _, ps := range r.PropStat {
+		// properties that are not found are returned with a 404 status
+		if !strings.Contains(ps.Status, " 200") {
+			continue
+		}
+
+		if ps.Prop.ResourceType.Collection != nil {
+			ret.isDir = true
+		}
+
+		if ps.Prop.ContentLength != "" {
+			size, err := strconv.ParseInt(strings.TrimSpace(ps.Prop.ContentLength), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid content length for %s: %w", name, err)
+			}
+			ret.size = size
+		}
+
+		if ps.Prop.LastModified != "" {
+			t, err := http.ParseTime(strings.TrimSpace(ps.Prop.LastModified))
+			if err != nil {
+				return nil, fmt.Errorf("invalid last modified time for %s: %w", name, err)
+			}
+			ret.modTime = t
+		}
+	}
+
+	if ret.isDir {
+		ret.size = 0
+	}
+
+	return ret, nil
+}
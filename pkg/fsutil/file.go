@@ -76,11 +76,13 @@ func SafeMove(src, dst string) error {
 }
 
 // MatchExtension returns true if the extension of the provided path
-// matches any of the provided extensions.
+// matches any of the provided extensions. Extensions may contain more than
+// one part, such as "tar.gz".
 func MatchExtension(path string, extensions []string) bool {
-	ext := filepath.Ext(path)
+	base := filepath.Base(path)
 	for _, e := range extensions {
-		if strings.EqualFold(ext, "."+e) {
+		ext := "." + e
+		if len(base) >= len(ext) && strings.EqualFold(base[len(base)-len(ext):], ext) {
 			return true
 		}
 	}
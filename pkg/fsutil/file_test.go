@@ -24,3 +24,29 @@ func TestSanitiseBasename(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchExtension(t *testing.T) {
+	extensions := []string{"zip", "tar.gz"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"file.zip", true},
+		{"file.ZIP", true},
+		{"dir/file.zip", true},
+		{"file.tar.gz", true},
+		{"file.TAR.GZ", true},
+		{"file.gz", false},
+		{"file.tar", false},
+		{"filezip", false},
+		{"file.zip/file", false},
+		{"file", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := MatchExtension(tt.path, extensions); got != tt.want {
+				t.Errorf("MatchExtension() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
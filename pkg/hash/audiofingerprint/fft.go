@@ -0,0 +1,37 @@
+package audiofingerprint
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fft performs an in-place radix-2 fast fourier transform. The length of x
+// must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+
+	// bit reversal permutation
+	shift := 64 - bits.Len(uint(n-1))
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		half := size / 2
+		step := -2 * math.Pi / float64(size)
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				sin, cos := math.Sincos(step * float64(k))
+				t := complex(cos, sin) * x[start+k+half]
+				x[start+k+half] = x[start+k] - t
+				x[start+k] += t
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package audiofingerprint generates fingerprints of the audio track of
+// video files.
+//
+// The fingerprint is a sequence of 32 bit sub-fingerprints, similar to
+// those produced by chromaprint. Each sub-fingerprint describes how the
+// energy of 33 frequency bands changes between two overlapping frames of
+// audio. Identical audio produces identical sequences, and re-encoded audio
+// produces sequences with few differing bits, so fingerprints can be
+// compared at an offset to find the same audio in files with different
+// intros.
+package audiofingerprint
+
+import (
+	"math"
+)
+
+const (
+	// SampleRate is the rate in Hz of the mono audio that is fingerprinted.
+	SampleRate = 11025
+
+	// DefaultLength is the default number of seconds of audio fingerprinted
+	// from the start of the file.
+	DefaultLength = 120
+
+	frameSize = 4096
+	frameStep = frameSize / 3
+
+	// bands are logarithmically spaced between minFreq and maxFreq, where
+	// most of the energy audible to people is found
+	bands   = 33
+	minFreq = 300.0
+	maxFreq = 2000.0
+)
+
+// FramesPerSecond is the number of sub-fingerprints generated per second
+// of audio.
+const FramesPerSecond = float64(SampleRate) / frameStep
+
+// Calculate returns the fingerprint of mono 16 bit PCM samples at SampleRate.
+func Calculate(samples []int16) []uint32 {
+	window := hammingWindow(frameSize)
+	edges := bandEdges()
+
+	var ret []uint32
+	var prev []float64
+	buf := make([]complex128, frameSize)
+
+	for start := 0; start+frameSize <= len(samples); start += frameStep {
+		for i := range buf {
+			buf[i] = complex(float64(samples[start+i])*window[i], 0)
+		}
+
+		fft(buf)
+
+		energies := make([]float64, bands)
+		for b := range energies {
+			for k := edges[b]; k < edges[b+1]; k++ {
+				re, im := real(buf[k]), imag(buf[k])
+				energies[b] += re*re + im*im
+			}
+		}
+
+		if prev != nil {
+			ret = append(ret, subFingerprint(prev, energies))
+		}
+		prev = energies
+	}
+
+	return ret
+}
+
+// subFingerprint sets bit m if the energy difference between bands m and
+// m+1 increased from prev to cur.
+func subFingerprint(prev, cur []float64) uint32 {
+	var ret uint32
+	for m := 0; m < bands-1; m++ {
+		if (cur[m]-cur[m+1])-(prev[m]-prev[m+1]) > 0 {
+			ret |= 1 << m
+		}
+	}
+
+	return ret
+}
+
+func hammingWindow(n int) []float64 {
+	ret := make([]float64, n)
+	for i := range ret {
+		ret[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return ret
+}
+
+// bandEdges returns the first FFT bin of each band, followed by the bin
+// after the last band.
+func bandEdges() []int {
+	ret := make([]int, bands+1)
+	for b := range ret {
+		freq := minFreq * math.Pow(maxFreq/minFreq, float64(b)/bands)
+		ret[b] = int(math.Round(freq * frameSize / SampleRate))
+	}
+	return ret
+}
@@ -0,0 +1,95 @@
+package audiofingerprint
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// testSignal returns seconds of audio made of random tones, which change
+// every quarter second.
+func testSignal(r *rand.Rand, seconds int) []int16 {
+	ret := make([]int16, seconds*SampleRate)
+	var freqs [3]float64
+	for i := range ret {
+		if i%(SampleRate/4) == 0 {
+			for f := range freqs {
+				freqs[f] = minFreq + r.Float64()*(maxFreq-minFreq)
+			}
+		}
+
+		t := float64(i) / SampleRate
+		var v float64
+		for _, f := range freqs {
+			v += math.Sin(2 * math.Pi * f * t)
+		}
+		ret[i] = int16(v * 8000)
+	}
+	return ret
+}
+
+func bitErrorRate(a, b []uint32) float64 {
+	n := min(len(a), len(b))
+	var errors int
+	for i := 0; i < n; i++ {
+		errors += bits.OnesCount32(a[i] ^ b[i])
+	}
+	return float64(errors) / float64(n*32)
+}
+
+func TestCalculate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	signal := testSignal(r, 30)
+
+	fp := Calculate(signal)
+	wantLen := (len(signal)-frameSize)/frameStep + 1 - 1
+	if len(fp) != wantLen {
+		t.Fatalf("len(Calculate()) = %d, want %d", len(fp), wantLen)
+	}
+
+	// audio starting later should produce the same sub-fingerprints offset
+	// by the number of frames skipped
+	const skipFrames = 40
+	shifted := Calculate(signal[skipFrames*frameStep:])
+	for i, v := range shifted {
+		if v != fp[i+skipFrames] {
+			t.Fatalf("shifted sub-fingerprint %d = %x, want %x", i, v, fp[i+skipFrames])
+		}
+	}
+
+	// quieter audio with added noise should be similar
+	noisy := make([]int16, len(signal))
+	for i, v := range signal {
+		noisy[i] = int16(float64(v)*0.7 + r.NormFloat64()*200)
+	}
+	if ber := bitErrorRate(fp, Calculate(noisy)); ber > 0.15 {
+		t.Errorf("bit error rate of noisy audio = %v, want <= 0.15", ber)
+	}
+
+	// different audio should not be similar
+	if ber := bitErrorRate(fp, Calculate(testSignal(r, 30))); ber < 0.35 {
+		t.Errorf("bit error rate of different audio = %v, want >= 0.35", ber)
+	}
+}
+
+func TestFFT(t *testing.T) {
+	const n = 64
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(math.Cos(2*math.Pi*5*float64(i)/n), 0)
+	}
+
+	fft(x)
+
+	for k, v := range x {
+		mag := math.Hypot(real(v), imag(v))
+		want := 0.0
+		if k == 5 || k == n-5 {
+			want = n / 2
+		}
+		if math.Abs(mag-want) > 1e-9 {
+			t.Errorf("|X[%d]| = %v, want %v", k, mag, want)
+		}
+	}
+}
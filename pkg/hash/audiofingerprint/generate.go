@@ -0,0 +1,44 @@
+package audiofingerprint
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Generate decodes up to length seconds of the audio track of the video
+// file and returns its fingerprint.
+func Generate(ctx context.Context, encoder *ffmpeg.FFMpeg, videoFile *models.VideoFile, length float64) ([]uint32, error) {
+	logger.Infof("[generator] generating audio fingerprint for %s", videoFile.Path)
+
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError)
+	args = args.Input(videoFile.Path)
+	args = args.SkipVideo()
+	args = args.Duration(length)
+	args = args.AudioChannels(1)
+	args = args.AudioSampleRate(SampleRate)
+	args = args.Format(ffmpeg.FormatS16LE)
+	args = args.Output("-")
+
+	data, err := encoder.GenerateOutput(ctx, args, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+
+	ret := Calculate(samples)
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("not enough audio to fingerprint %s", videoFile.Path)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,40 @@
+// Package imagephash generates perceptual hashes of image files.
+package imagephash
+
+import (
+	"fmt"
+	"image"
+
+	// register decoders for the supported image formats
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/corona10/goimagehash"
+	_ "golang.org/x/image/webp"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Generate returns the perceptual hash of the image file, read from fsys.
+// Files within zip files are read from the zip file.
+func Generate(fsys models.FS, f *models.ImageFile) (*uint64, error) {
+	reader, err := f.Open(fsys)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+
+	hash, err := goimagehash.PerceptionHash(img)
+	if err != nil {
+		return nil, fmt.Errorf("computing phash from image: %w", err)
+	}
+
+	hashValue := hash.GetHash()
+	return &hashValue, nil
+}
@@ -0,0 +1,110 @@
+package imagephash
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// writeTestImage writes a png gradient to dir, inverted if invert is set.
+func writeTestImage(t *testing.T, dir string, name string, invert bool) *models.ImageFile {
+	const size = 64
+
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			v := uint8((x + y) * 2)
+			if invert {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("creating test image: %v", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("encoding test image: %v", err)
+	}
+
+	return &models.ImageFile{
+		BaseFile: &models.BaseFile{
+			Path: path,
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	fsys := &file.OsFS{}
+
+	gradient := writeTestImage(t, dir, "gradient.png", false)
+	inverted := writeTestImage(t, dir, "inverted.png", true)
+
+	got, err := Generate(fsys, gradient)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("Generate() returned nil hash")
+	}
+
+	again, err := Generate(fsys, gradient)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if *again != *got {
+		t.Errorf("Generate() = %x, want %x for the same image", *again, *got)
+	}
+
+	other, err := Generate(fsys, inverted)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if *other == *got {
+		t.Errorf("Generate() = %x for both a gradient and its inverse", *got)
+	}
+}
+
+func TestGenerate_invalid(t *testing.T) {
+	dir := t.TempDir()
+	fsys := &file.OsFS{}
+
+	path := filepath.Join(dir, "invalid.png")
+	if err := os.WriteFile(path, []byte("not an image"), 0644); err != nil {
+		t.Fatalf("writing test file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"not an image", path},
+		{"missing", filepath.Join(dir, "missing.png")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &models.ImageFile{
+				BaseFile: &models.BaseFile{
+					Path: tt.path,
+				},
+			}
+
+			if got, err := Generate(fsys, f); err == nil {
+				t.Errorf("Generate() = %x, want error", *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package videophash
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/corona10/goimagehash"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// DefaultSegmentInterval is the default length in seconds of each segment
+// hashed by GenerateSegments.
+const DefaultSegmentInterval = 10.0
+
+// GenerateSegments generates a perceptual hash for every interval seconds of
+// the video file. Each hash is taken from the frame in the middle of its
+// segment, so files sharing the same footage produce runs of matching
+// hashes, offset by the difference in where the footage starts.
+func GenerateSegments(ctx context.Context, encoder *ffmpeg.FFMpeg, videoFile *models.VideoFile, interval float64) ([]uint64, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid segment interval %v", interval)
+	}
+
+	count := int(videoFile.Duration / interval)
+	if count == 0 {
+		return nil, fmt.Errorf("%s is shorter than the segment interval", videoFile.Path)
+	}
+
+	logger.Infof("[generator] generating %d segment phashes for %s", count, videoFile.Path)
+
+	ret := make([]uint64, count)
+	for i := range ret {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		t := (float64(i) + 0.5) * interval
+		img, err := generateSpriteScreenshot(encoder, videoFile.Path, t)
+		if err != nil {
+			return nil, fmt.Errorf("generating segment screenshot: %w", err)
+		}
+
+		hash, err := goimagehash.PerceptionHash(img)
+		if err != nil {
+			return nil, fmt.Errorf("computing segment phash: %w", err)
+		}
+
+		ret[i] = hash.GetHash()
+	}
+
+	return ret, nil
+}
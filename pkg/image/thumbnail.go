@@ -12,7 +12,6 @@ import (
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
-	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/models"
 )
@@ -30,6 +29,7 @@ var (
 )
 
 type ThumbnailEncoder struct {
+	FS                 models.FS
 	FFMpeg             *ffmpeg.FFMpeg
 	FFProbe            *ffmpeg.FFProbe
 	ClipPreviewOptions ClipPreviewOptions
@@ -49,8 +49,9 @@ func GetVipsPath() string {
 	return vipsPath
 }
 
-func NewThumbnailEncoder(ffmpegEncoder *ffmpeg.FFMpeg, ffProbe *ffmpeg.FFProbe, clipPreviewOptions ClipPreviewOptions) ThumbnailEncoder {
+func NewThumbnailEncoder(fsys models.FS, ffmpegEncoder *ffmpeg.FFMpeg, ffProbe *ffmpeg.FFProbe, clipPreviewOptions ClipPreviewOptions) ThumbnailEncoder {
 	ret := ThumbnailEncoder{
+		FS:                 fsys,
 		FFMpeg:             ffmpegEncoder,
 		FFProbe:            ffProbe,
 		ClipPreviewOptions: clipPreviewOptions,
@@ -70,7 +71,7 @@ func NewThumbnailEncoder(ffmpegEncoder *ffmpeg.FFMpeg, ffProbe *ffmpeg.FFProbe,
 // It returns nil and an error if an error occurs reading, decoding or encoding
 // the image, or if the image is not suitable for thumbnails.
 func (e *ThumbnailEncoder) GetThumbnail(f models.File, maxSize int) ([]byte, error) {
-	reader, err := f.Open(&file.OsFS{})
+	reader, err := f.Open(e.FS)
 	if err != nil {
 		return nil, err
 	}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/txn"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -179,7 +180,10 @@ func (m *Manager) dispatch(ctx context.Context, j *Job) (done chan struct{}) {
 	j.StartTime = &t
 	j.Status = StatusRunning
 
-	ctx, cancelFunc := context.WithCancel(utils.ValueOnlyContext{Context: ctx})
+	// jobs outlive the request that started them, so are not subject to its
+	// read transaction timeout
+	ctx = txn.WithReadTimeout(utils.ValueOnlyContext{Context: ctx}, 0)
+	ctx, cancelFunc := context.WithCancel(ctx)
 	j.cancelFunc = cancelFunc
 
 	done = make(chan struct{})
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stashapp/stash/pkg/txn"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -343,3 +344,41 @@ func TestSubscribe(t *testing.T) {
 
 	cancel()
 }
+
+type contextExec struct {
+	ctx chan context.Context
+}
+
+func (e *contextExec) Execute(ctx context.Context, p *Progress) error {
+	e.ctx <- ctx
+	return nil
+}
+
+func TestReadTimeout(t *testing.T) {
+	m := NewManager()
+
+	// the context of a request with a read transaction timeout
+	ctx := txn.WithReadTimeout(context.Background(), time.Minute)
+
+	tests := []struct {
+		name string
+		add  func(ctx context.Context, description string, e JobExec) int
+	}{
+		{"Add", m.Add},
+		{"Start", m.Start},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &contextExec{ctx: make(chan context.Context, 1)}
+			tt.add(ctx, "test job", exec)
+
+			select {
+			case jobCtx := <-exec.ctx:
+				assert.Zero(t, txn.ReadTimeout(jobCtx))
+			case <-time.After(time.Second):
+				t.Error("exec was not started")
+			}
+		})
+	}
+}
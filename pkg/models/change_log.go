@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type ChangeLogReader interface {
+	// FindSince returns up to limit entries with a sequence number greater
+	// than sequence, in sequence order.
+	FindSince(ctx context.Context, sequence int, limit int) ([]*ChangeLogEntry, error)
+	State(ctx context.Context) (*ChangeLogState, error)
+}
+
+type ChangeLogWriter interface {
+	// DestroyBefore removes the entries changed before t, returning the
+	// number of entries removed.
+	DestroyBefore(ctx context.Context, t time.Time) (int, error)
+}
+
+type ChangeLogReaderWriter interface {
+	ChangeLogReader
+	ChangeLogWriter
+}
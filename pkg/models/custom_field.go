@@ -0,0 +1,47 @@
+package models
+
+import "context"
+
+// CustomFieldsInput sets the custom fields of an object. Full replaces all
+// custom fields. Otherwise, the fields in Partial are set and the fields in
+// Remove are removed, leaving the other fields unchanged.
+type CustomFieldsInput struct {
+	Full    map[string]interface{} `json:"full"`
+	Partial map[string]interface{} `json:"partial"`
+	Remove  []string               `json:"remove"`
+}
+
+type CustomFieldCriterionInput struct {
+	Field    string            `json:"field"`
+	Value    []interface{}     `json:"value"`
+	Modifier CriterionModifier `json:"modifier"`
+}
+
+// CustomFieldsReader provides methods to get the custom fields of objects.
+// Custom fields are returned as a map of field name to value.
+type CustomFieldsReader interface {
+	GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error)
+}
+
+// CustomFieldsWriter provides methods to set the custom fields of objects.
+type CustomFieldsWriter interface {
+	SetCustomFields(ctx context.Context, id int, input CustomFieldsInput) error
+}
+
+type CustomFieldDefinitionReader interface {
+	Find(ctx context.Context, id int) (*CustomFieldDefinition, error)
+	FindByName(ctx context.Context, entityType CustomFieldEntityType, name string) (*CustomFieldDefinition, error)
+	FindByEntityType(ctx context.Context, entityType CustomFieldEntityType) ([]*CustomFieldDefinition, error)
+	All(ctx context.Context) ([]*CustomFieldDefinition, error)
+}
+
+type CustomFieldDefinitionWriter interface {
+	Create(ctx context.Context, newObject *CustomFieldDefinition) error
+	Update(ctx context.Context, updatedObject *CustomFieldDefinition) error
+	Destroy(ctx context.Context, id int) error
+}
+
+type CustomFieldDefinitionReaderWriter interface {
+	CustomFieldDefinitionReader
+	CustomFieldDefinitionWriter
+}
@@ -10,4 +10,7 @@ var (
 	ErrConversion = errors.New("conversion error")
 
 	ErrScraperSource = errors.New("invalid ScraperSource")
+
+	// ErrInvalidSort signifies a sort that is not supported by the query
+	ErrInvalidSort = errors.New("invalid sort")
 )
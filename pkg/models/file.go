@@ -18,6 +18,15 @@ type FileFilterType struct {
 
 	// Filter by path
 	Path *StringCriterionInput `json:"path"`
+	// Filter by the issue found by the last verification
+	IntegrityIssue *FileIntegrityIssueCriterionInput `json:"integrity_issue"`
+	// Filter by whether the file is in an offline stash path
+	IsOffline *bool `json:"is_offline"`
+}
+
+type FileIntegrityIssueCriterionInput struct {
+	Value    []FileIntegrityIssueType `json:"value"`
+	Modifier CriterionModifier        `json:"modifier"`
 }
 
 func PathsFileFilter(paths []string) *FileFilterType {
@@ -9,6 +9,12 @@ var (
 	FingerprintTypeOshash = "oshash"
 	FingerprintTypeMD5    = "md5"
 	FingerprintTypePhash  = "phash"
+	// FingerprintTypePhashSegments is a sequence of perceptual hashes, one
+	// for each fixed length segment of a video file.
+	FingerprintTypePhashSegments = "phash_segments"
+	// FingerprintTypeAudio is a fingerprint of the start of the audio track
+	// of a video file.
+	FingerprintTypeAudio = "audio"
 )
 
 // Fingerprint represents a fingerprint of a file.
@@ -21,6 +21,8 @@ type GalleryFilterType struct {
 	Rating100 *IntCriterionInput `json:"rating100"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by whether the primary file is in an offline stash path
+	IsOffline *bool `json:"is_offline"`
 	// Filter by average image resolution
 	AverageResolution *ResolutionCriterionInput `json:"average_resolution"`
 	// Filter to only include scenes which have chapters. `true` or `false`
@@ -65,24 +67,27 @@ type GalleryFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type GalleryUpdateInput struct {
-	ClientMutationID *string  `json:"clientMutationId"`
-	ID               string   `json:"id"`
-	Title            *string  `json:"title"`
-	Code             *string  `json:"code"`
-	Urls             []string `json:"urls"`
-	Date             *string  `json:"date"`
-	Details          *string  `json:"details"`
-	Photographer     *string  `json:"photographer"`
-	Rating100        *int     `json:"rating100"`
-	Organized        *bool    `json:"organized"`
-	SceneIds         []string `json:"scene_ids"`
-	StudioID         *string  `json:"studio_id"`
-	TagIds           []string `json:"tag_ids"`
-	PerformerIds     []string `json:"performer_ids"`
-	PrimaryFileID    *string  `json:"primary_file_id"`
+	ClientMutationID *string            `json:"clientMutationId"`
+	ID               string             `json:"id"`
+	Title            *string            `json:"title"`
+	Code             *string            `json:"code"`
+	Urls             []string           `json:"urls"`
+	Date             *string            `json:"date"`
+	Details          *string            `json:"details"`
+	Photographer     *string            `json:"photographer"`
+	Rating100        *int               `json:"rating100"`
+	Organized        *bool              `json:"organized"`
+	SceneIds         []string           `json:"scene_ids"`
+	StudioID         *string            `json:"studio_id"`
+	TagIds           []string           `json:"tag_ids"`
+	PerformerIds     []string           `json:"performer_ids"`
+	PrimaryFileID    *string            `json:"primary_file_id"`
+	CustomFields     *CustomFieldsInput `json:"custom_fields"`
 
 	// deprecated
 	URL *string `json:"url"`
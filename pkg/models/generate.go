@@ -17,7 +17,11 @@ type GenerateMetadataOptions struct {
 	MarkerScreenshots         bool                    `json:"markerScreenshots"`
 	Transcodes                bool                    `json:"transcodes"`
 	Phashes                   bool                    `json:"phashes"`
+	PhashSegments             bool                    `json:"phashSegments"`
+	AudioFingerprints         bool                    `json:"audioFingerprints"`
 	InteractiveHeatmapsSpeeds bool                    `json:"interactiveHeatmapsSpeeds"`
+	SceneCuts                 bool                    `json:"sceneCuts"`
+	ImportChapters            bool                    `json:"importChapters"`
 	ImageThumbnails           bool                    `json:"imageThumbnails"`
 	ClipPreviews              bool                    `json:"clipPreviews"`
 }
@@ -11,6 +11,8 @@ type ImageFilterType struct {
 	Photographer *StringCriterionInput `json:"photographer"`
 	// Filter by file checksum
 	Checksum *StringCriterionInput `json:"checksum"`
+	// Filter by file phash distance
+	PhashDistance *PhashDistanceCriterionInput `json:"phash_distance"`
 	// Filter by path
 	Path *StringCriterionInput `json:"path"`
 	// Filter by file count
@@ -23,6 +25,8 @@ type ImageFilterType struct {
 	URL *StringCriterionInput `json:"url"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by whether the primary file is in an offline stash path
+	IsOffline *bool `json:"is_offline"`
 	// Filter by o-counter
 	OCounter *IntCriterionInput `json:"o_counter"`
 	// Filter by resolution
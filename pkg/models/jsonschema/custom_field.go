@@ -0,0 +1,31 @@
+package jsonschema
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/json"
+)
+
+type CustomFieldDefinition struct {
+	EntityType models.CustomFieldEntityType `json:"entity_type"`
+	Name       string                       `json:"name"`
+	Type       models.CustomFieldType       `json:"type"`
+	Options    []string                     `json:"options,omitempty"`
+	CreatedAt  json.JSONTime                `json:"created_at,omitempty"`
+	UpdatedAt  json.JSONTime                `json:"updated_at,omitempty"`
+}
+
+func (s CustomFieldDefinition) Filename() string {
+	ret := fsutil.SanitiseBasename(strings.ToLower(s.EntityType.String()) + "_" + s.Name)
+	return ret + ".json"
+}
+
+func LoadCustomFieldDefinitionFile(filePath string) (*CustomFieldDefinition, error) {
+	return loadFile[CustomFieldDefinition](filePath)
+}
+
+func SaveCustomFieldDefinitionFile(filePath string, definition *CustomFieldDefinition) error {
+	return saveFile[CustomFieldDefinition](filePath, definition)
+}
@@ -109,6 +109,12 @@ func LoadFileFile(filePath string) (DirEntry, error) {
 		return nil, err
 	}
 
+	return ParseDirEntry(data)
+}
+
+// ParseDirEntry decodes a file or folder from JSON, using the type field to
+// determine the type of entry.
+func ParseDirEntry(data []byte) (DirEntry, error) {
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
 	jsonParser := json.NewDecoder(bytes.NewReader(data))
 
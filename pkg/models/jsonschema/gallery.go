@@ -37,6 +37,8 @@ type Gallery struct {
 
 	// deprecated - for import only
 	URL string `json:"url,omitempty"`
+
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 func (s Gallery) Filename(basename string, hash string) string {
@@ -35,6 +35,8 @@ type Group struct {
 
 	// deprecated - for import only
 	URL string `json:"url,omitempty"`
+
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 func (s Group) Filename() string {
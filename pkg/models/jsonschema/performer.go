@@ -69,6 +69,8 @@ type Performer struct {
 	URL       string `json:"url,omitempty"`
 	Twitter   string `json:"twitter,omitempty"`
 	Instagram string `json:"instagram,omitempty"`
+
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 func (s Performer) Filename() string {
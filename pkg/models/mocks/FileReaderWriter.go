@@ -291,6 +291,29 @@ func (_m *FileReaderWriter) GetCaptions(ctx context.Context, fileID models.FileI
 	return r0, r1
 }
 
+// GetIntegrityIssue provides a mock function with given fields: ctx, fileID
+func (_m *FileReaderWriter) GetIntegrityIssue(ctx context.Context, fileID models.FileID) (*models.FileIntegrityIssue, error) {
+	ret := _m.Called(ctx, fileID)
+
+	var r0 *models.FileIntegrityIssue
+	if rf, ok := ret.Get(0).(func(context.Context, models.FileID) *models.FileIntegrityIssue); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FileIntegrityIssue)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.FileID) error); ok {
+		r1 = rf(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsPrimary provides a mock function with given fields: ctx, fileID
 func (_m *FileReaderWriter) IsPrimary(ctx context.Context, fileID models.FileID) (bool, error) {
 	ret := _m.Called(ctx, fileID)
@@ -349,6 +372,20 @@ func (_m *FileReaderWriter) Query(ctx context.Context, options models.FileQueryO
 	return r0, r1
 }
 
+// SetIntegrity provides a mock function with given fields: ctx, integrity
+func (_m *FileReaderWriter) SetIntegrity(ctx context.Context, integrity models.FileIntegrity) error {
+	ret := _m.Called(ctx, integrity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.FileIntegrity) error); ok {
+		r0 = rf(ctx, integrity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, f
 func (_m *FileReaderWriter) Update(ctx context.Context, f models.File) error {
 	ret := _m.Called(ctx, f)
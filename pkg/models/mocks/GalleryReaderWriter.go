@@ -395,6 +395,29 @@ func (_m *GalleryReaderWriter) FindUserGalleryByTitle(ctx context.Context, title
 	return r0, r1
 }
 
+// GetCustomFields provides a mock function with given fields: ctx, id
+func (_m *GalleryReaderWriter) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFiles provides a mock function with given fields: ctx, relatedID
 func (_m *GalleryReaderWriter) GetFiles(ctx context.Context, relatedID int) ([]models.File, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -656,6 +679,20 @@ func (_m *GalleryReaderWriter) SetCover(ctx context.Context, galleryID int, cove
 	return r0
 }
 
+// SetCustomFields provides a mock function with given fields: ctx, id, input
+func (_m *GalleryReaderWriter) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CustomFieldsInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, updatedGallery
 func (_m *GalleryReaderWriter) Update(ctx context.Context, updatedGallery *models.Gallery) error {
 	ret := _m.Called(ctx, updatedGallery)
@@ -641,13 +641,13 @@ func (_m *SceneReaderWriter) FindByPrimaryFileID(ctx context.Context, fileID mod
 	return r0, r1
 }
 
-// FindDuplicates provides a mock function with given fields: ctx, distance, durationDiff
-func (_m *SceneReaderWriter) FindDuplicates(ctx context.Context, distance int, durationDiff float64) ([][]*models.Scene, error) {
-	ret := _m.Called(ctx, distance, durationDiff)
+// FindDuplicates provides a mock function with given fields: ctx, distance, durationDiff, audio
+func (_m *SceneReaderWriter) FindDuplicates(ctx context.Context, distance int, durationDiff float64, audio bool) ([][]*models.Scene, error) {
+	ret := _m.Called(ctx, distance, durationDiff, audio)
 
 	var r0 [][]*models.Scene
-	if rf, ok := ret.Get(0).(func(context.Context, int, float64) [][]*models.Scene); ok {
-		r0 = rf(ctx, distance, durationDiff)
+	if rf, ok := ret.Get(0).(func(context.Context, int, float64, bool) [][]*models.Scene); ok {
+		r0 = rf(ctx, distance, durationDiff, audio)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([][]*models.Scene)
@@ -655,8 +655,8 @@ func (_m *SceneReaderWriter) FindDuplicates(ctx context.Context, distance int, d
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int, float64) error); ok {
-		r1 = rf(ctx, distance, durationDiff)
+	if rf, ok := ret.Get(1).(func(context.Context, int, float64, bool) error); ok {
+		r1 = rf(ctx, distance, durationDiff, audio)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -687,6 +687,29 @@ func (_m *SceneReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models
 	return r0, r1
 }
 
+// FindOverlaps provides a mock function with given fields: ctx, distance, minDuration
+func (_m *SceneReaderWriter) FindOverlaps(ctx context.Context, distance int, minDuration float64) ([]*models.SceneOverlap, error) {
+	ret := _m.Called(ctx, distance, minDuration)
+
+	var r0 []*models.SceneOverlap
+	if rf, ok := ret.Get(0).(func(context.Context, int, float64) []*models.SceneOverlap); ok {
+		r0 = rf(ctx, distance, minDuration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.SceneOverlap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, float64) error); ok {
+		r1 = rf(ctx, distance, minDuration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllOCount provides a mock function with given fields: ctx
 func (_m *SceneReaderWriter) GetAllOCount(ctx context.Context) (int, error) {
 	ret := _m.Called(ctx)
@@ -731,6 +754,29 @@ func (_m *SceneReaderWriter) GetCover(ctx context.Context, sceneID int) ([]byte,
 	return r0, r1
 }
 
+// GetCustomFields provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFiles provides a mock function with given fields: ctx, relatedID
 func (_m *SceneReaderWriter) GetFiles(ctx context.Context, relatedID int) ([]*models.VideoFile, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -1267,6 +1313,20 @@ func (_m *SceneReaderWriter) SaveActivity(ctx context.Context, sceneID int, resu
 	return r0, r1
 }
 
+// SetCustomFields provides a mock function with given fields: ctx, id, input
+func (_m *SceneReaderWriter) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CustomFieldsInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Size provides a mock function with given fields: ctx
 func (_m *SceneReaderWriter) Size(ctx context.Context) (float64, error) {
 	ret := _m.Called(ctx)
@@ -291,6 +291,29 @@ func (_m *StudioReaderWriter) GetAliases(ctx context.Context, relatedID int) ([]
 	return r0, r1
 }
 
+// GetCustomFields provides a mock function with given fields: ctx, id
+func (_m *StudioReaderWriter) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, int) map[string]interface{}); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetImage provides a mock function with given fields: ctx, studioID
 func (_m *StudioReaderWriter) GetImage(ctx context.Context, studioID int) ([]byte, error) {
 	ret := _m.Called(ctx, studioID)
@@ -455,6 +478,20 @@ func (_m *StudioReaderWriter) QueryForAutoTag(ctx context.Context, words []strin
 	return r0, r1
 }
 
+// SetCustomFields provides a mock function with given fields: ctx, id, input
+func (_m *StudioReaderWriter) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.CustomFieldsInput) error); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, updatedStudio
 func (_m *StudioReaderWriter) Update(ctx context.Context, updatedStudio *models.Studio) error {
 	ret := _m.Called(ctx, updatedStudio)
@@ -22,6 +22,7 @@ type Database struct {
 	Studio         *StudioReaderWriter
 	Tag            *TagReaderWriter
 	SavedFilter    *SavedFilterReaderWriter
+	CustomField    *CustomFieldDefinitionReaderWriter
 }
 
 func (*Database) Begin(ctx context.Context, exclusive bool) (context.Context, error) {
@@ -71,6 +72,7 @@ func NewDatabase() *Database {
 		Studio:         &StudioReaderWriter{},
 		Tag:            &TagReaderWriter{},
 		SavedFilter:    &SavedFilterReaderWriter{},
+		CustomField:    &CustomFieldDefinitionReaderWriter{},
 	}
 }
 
@@ -87,6 +89,7 @@ func (db *Database) AssertExpectations(t mock.TestingT) {
 	db.Studio.AssertExpectations(t)
 	db.Tag.AssertExpectations(t)
 	db.SavedFilter.AssertExpectations(t)
+	db.CustomField.AssertExpectations(t)
 }
 
 func (db *Database) Repository() models.Repository {
@@ -104,5 +107,6 @@ func (db *Database) Repository() models.Repository {
 		Studio:         db.Studio,
 		Tag:            db.Tag,
 		SavedFilter:    db.SavedFilter,
+		CustomField:    db.CustomField,
 	}
 }
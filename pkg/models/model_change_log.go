@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type ChangeLogOperation string
+
+const (
+	// ChangeLogOperationUpsert is recorded when an object is created or
+	// updated, including changes to its relationships.
+	ChangeLogOperationUpsert ChangeLogOperation = "UPSERT"
+	// ChangeLogOperationDelete is recorded when an object is deleted.
+	ChangeLogOperationDelete ChangeLogOperation = "DELETE"
+)
+
+// ChangeLogEntry is a change to an object, recorded in the same transaction
+// as the change.
+type ChangeLogEntry struct {
+	// Sequence increases monotonically with each entry.
+	Sequence int `json:"sequence"`
+	// EntityType is the type of the changed object, such as SCENE or
+	// SCENE_MARKER.
+	EntityType string             `json:"entity_type"`
+	EntityID   int                `json:"entity_id"`
+	Operation  ChangeLogOperation `json:"operation"`
+	ChangedAt  time.Time          `json:"changed_at"`
+}
+
+// ChangeLogState describes the entries available in the change log.
+type ChangeLogState struct {
+	// LogID identifies the change log. It changes if the database is
+	// recreated or restored from a backup.
+	LogID string `json:"log_id"`
+	// PrunedSequence is the highest sequence number that has been removed
+	// from the change log.
+	PrunedSequence int `json:"pruned_sequence"`
+	// LatestSequence is the highest sequence number recorded.
+	LatestSequence int `json:"latest_sequence"`
+}
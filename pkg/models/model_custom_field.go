@@ -0,0 +1,233 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldTypeString CustomFieldType = "STRING"
+	CustomFieldTypeInt    CustomFieldType = "INT"
+	CustomFieldTypeFloat  CustomFieldType = "FLOAT"
+	CustomFieldTypeDate   CustomFieldType = "DATE"
+	CustomFieldTypeBool   CustomFieldType = "BOOL"
+	CustomFieldTypeEnum   CustomFieldType = "ENUM"
+)
+
+var AllCustomFieldType = []CustomFieldType{
+	CustomFieldTypeString,
+	CustomFieldTypeInt,
+	CustomFieldTypeFloat,
+	CustomFieldTypeDate,
+	CustomFieldTypeBool,
+	CustomFieldTypeEnum,
+}
+
+func (e CustomFieldType) IsValid() bool {
+	switch e {
+	case CustomFieldTypeString, CustomFieldTypeInt, CustomFieldTypeFloat, CustomFieldTypeDate, CustomFieldTypeBool, CustomFieldTypeEnum:
+		return true
+	}
+	return false
+}
+
+func (e CustomFieldType) String() string {
+	return string(e)
+}
+
+func (e *CustomFieldType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CustomFieldType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CustomFieldType", str)
+	}
+	return nil
+}
+
+func (e CustomFieldType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CustomFieldEntityType string
+
+const (
+	CustomFieldEntityTypeScene     CustomFieldEntityType = "SCENE"
+	CustomFieldEntityTypePerformer CustomFieldEntityType = "PERFORMER"
+	CustomFieldEntityTypeStudio    CustomFieldEntityType = "STUDIO"
+	CustomFieldEntityTypeGallery   CustomFieldEntityType = "GALLERY"
+	CustomFieldEntityTypeGroup     CustomFieldEntityType = "GROUP"
+)
+
+var AllCustomFieldEntityType = []CustomFieldEntityType{
+	CustomFieldEntityTypeScene,
+	CustomFieldEntityTypePerformer,
+	CustomFieldEntityTypeStudio,
+	CustomFieldEntityTypeGallery,
+	CustomFieldEntityTypeGroup,
+}
+
+func (e CustomFieldEntityType) IsValid() bool {
+	switch e {
+	case CustomFieldEntityTypeScene, CustomFieldEntityTypePerformer, CustomFieldEntityTypeStudio, CustomFieldEntityTypeGallery, CustomFieldEntityTypeGroup:
+		return true
+	}
+	return false
+}
+
+func (e CustomFieldEntityType) String() string {
+	return string(e)
+}
+
+func (e *CustomFieldEntityType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CustomFieldEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CustomFieldEntityType", str)
+	}
+	return nil
+}
+
+func (e CustomFieldEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// CustomFieldDefinition defines a user-defined field of an entity type.
+// Options holds the allowed values of ENUM fields.
+type CustomFieldDefinition struct {
+	ID         int                   `json:"id"`
+	EntityType CustomFieldEntityType `json:"entity_type"`
+	Name       string                `json:"name"`
+	Type       CustomFieldType       `json:"type"`
+	Options    []string              `json:"options"`
+	CreatedAt  time.Time             `json:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at"`
+}
+
+func NewCustomFieldDefinition() CustomFieldDefinition {
+	currentTime := time.Now()
+	return CustomFieldDefinition{
+		CreatedAt: currentTime,
+		UpdatedAt: currentTime,
+	}
+}
+
+// ConvertValue converts a value from GraphQL or JSON input to the type of the
+// field. Integers are returned as int64, floats as float64, dates as
+// YYYY-MM-DD strings and booleans as bool. Strings are accepted for all types.
+func (d CustomFieldDefinition) ConvertValue(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, fmt.Errorf("custom field %q: value must not be null", d.Name)
+	}
+
+	var ret interface{}
+	var err error
+	switch d.Type {
+	case CustomFieldTypeInt:
+		ret, err = toInt64(v)
+	case CustomFieldTypeFloat:
+		ret, err = toFloat64(v)
+	case CustomFieldTypeBool:
+		ret, err = toBool(v)
+	case CustomFieldTypeDate:
+		var s string
+		if s, err = toString(v); err == nil {
+			var date Date
+			if date, err = ParseDate(s); err == nil {
+				ret = date.String()
+			}
+		}
+	case CustomFieldTypeEnum:
+		var s string
+		if s, err = toString(v); err == nil {
+			if !slices.Contains(d.Options, s) {
+				err = fmt.Errorf("%q is not one of %s", s, strings.Join(d.Options, ", "))
+			}
+			ret = s
+		}
+	default:
+		ret, err = toString(v)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("custom field %q: %w", d.Name, err)
+	}
+
+	return ret, nil
+}
+
+func toString(v interface{}) (string, error) {
+	switch vv := v.(type) {
+	case string:
+		return vv, nil
+	case json.Number:
+		return vv.String(), nil
+	case int, int64, float64, bool:
+		return fmt.Sprint(vv), nil
+	}
+
+	return "", fmt.Errorf("invalid string value %v", v)
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch vv := v.(type) {
+	case int:
+		return int64(vv), nil
+	case int64:
+		return vv, nil
+	case float64:
+		if vv != math.Trunc(vv) {
+			return 0, fmt.Errorf("%v is not an integer", vv)
+		}
+		return int64(vv), nil
+	case json.Number:
+		return vv.Int64()
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(vv), 10, 64)
+	}
+
+	return 0, fmt.Errorf("invalid integer value %v", v)
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch vv := v.(type) {
+	case int:
+		return float64(vv), nil
+	case int64:
+		return float64(vv), nil
+	case float64:
+		return vv, nil
+	case json.Number:
+		return vv.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(vv), 64)
+	}
+
+	return 0, fmt.Errorf("invalid float value %v", v)
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch vv := v.(type) {
+	case bool:
+		return vv, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(vv))
+	}
+
+	return false, fmt.Errorf("invalid boolean value %v", v)
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFieldDefinition_ConvertValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       CustomFieldType
+		options []string
+		v       interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"string", CustomFieldTypeString, nil, "foo", "foo", false},
+		{"string from number", CustomFieldTypeString, nil, 12, "12", false},
+		{"int", CustomFieldTypeInt, nil, 12, int64(12), false},
+		{"int from float", CustomFieldTypeInt, nil, float64(12), int64(12), false},
+		{"int from json number", CustomFieldTypeInt, nil, json.Number("12"), int64(12), false},
+		{"int from string", CustomFieldTypeInt, nil, " 12 ", int64(12), false},
+		{"int from fraction", CustomFieldTypeInt, nil, 1.5, nil, true},
+		{"int from invalid string", CustomFieldTypeInt, nil, "abc", nil, true},
+		{"float", CustomFieldTypeFloat, nil, 1.5, 1.5, false},
+		{"float from int", CustomFieldTypeFloat, nil, 2, float64(2), false},
+		{"float from string", CustomFieldTypeFloat, nil, "1.5", 1.5, false},
+		{"bool", CustomFieldTypeBool, nil, true, true, false},
+		{"bool from string", CustomFieldTypeBool, nil, "false", false, false},
+		{"bool from int", CustomFieldTypeBool, nil, 1, nil, true},
+		{"date", CustomFieldTypeDate, nil, "2024-02-03", "2024-02-03", false},
+		{"date with time", CustomFieldTypeDate, nil, "2024-02-03T10:00:00Z", "2024-02-03", false},
+		{"invalid date", CustomFieldTypeDate, nil, "tomorrow", nil, true},
+		{"enum", CustomFieldTypeEnum, []string{"a", "b"}, "b", "b", false},
+		{"invalid enum", CustomFieldTypeEnum, []string{"a", "b"}, "c", nil, true},
+		{"null", CustomFieldTypeString, nil, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := CustomFieldDefinition{
+				Name:    "field",
+				Type:    tt.t,
+				Options: tt.options,
+			}
+
+			got, err := d.ConvertValue(tt.v)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
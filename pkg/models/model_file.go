@@ -277,6 +277,18 @@ type VideoFile struct {
 
 	Interactive      bool `json:"interactive"`
 	InteractiveSpeed *int `json:"interactive_speed"`
+
+	// Chapters is nil if the chapters have not been probed.
+	Chapters []VideoChapter `json:"chapters"`
+}
+
+// VideoChapter is a chapter embedded in the container of a video file.
+type VideoChapter struct {
+	// Start time in seconds
+	Start float64 `json:"start"`
+	// End time in seconds. May be zero if the container does not specify it.
+	End   float64 `json:"end"`
+	Title string  `json:"title"`
 }
 
 func (f VideoFile) GetWidth() int {
@@ -316,3 +328,10 @@ func (f VideoFile) FrameRateFinite() float64 {
 	}
 	return ret
 }
+
+// ExceedsBitrate returns true if the video file bitrate is greater than the
+// provided maximum bitrate in kilobits per second. A maxBitrate of 0 is
+// treated as unlimited.
+func (f VideoFile) ExceedsBitrate(maxBitrate int) bool {
+	return maxBitrate > 0 && f.BitRate > int64(maxBitrate)*1000
+}
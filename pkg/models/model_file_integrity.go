@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type FileIntegrityIssueType string
+
+const (
+	// FileIntegrityIssueTypeHashMismatch indicates that the contents of the
+	// file no longer match its stored fingerprints, while its modification
+	// time and size are unchanged.
+	FileIntegrityIssueTypeHashMismatch FileIntegrityIssueType = "HASH_MISMATCH"
+	// FileIntegrityIssueTypeUnreadable indicates that the file could not be
+	// read.
+	FileIntegrityIssueTypeUnreadable FileIntegrityIssueType = "UNREADABLE"
+)
+
+var AllFileIntegrityIssueType = []FileIntegrityIssueType{
+	FileIntegrityIssueTypeHashMismatch,
+	FileIntegrityIssueTypeUnreadable,
+}
+
+func (e FileIntegrityIssueType) IsValid() bool {
+	switch e {
+	case FileIntegrityIssueTypeHashMismatch, FileIntegrityIssueTypeUnreadable:
+		return true
+	}
+	return false
+}
+
+func (e FileIntegrityIssueType) String() string {
+	return string(e)
+}
+
+func (e *FileIntegrityIssueType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FileIntegrityIssueType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FileIntegrityIssueType", str)
+	}
+	return nil
+}
+
+func (e FileIntegrityIssueType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// FileIntegrityIssue is a problem found when verifying the contents of a
+// file against its stored fingerprints.
+type FileIntegrityIssue struct {
+	Type    FileIntegrityIssueType `json:"type"`
+	Message string                 `json:"message"`
+	// DetectedAt is the time of the verification that found the issue.
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// FileIntegrity is the result of the last verification of a file. Issue is
+// nil if the file was verified successfully.
+type FileIntegrity struct {
+	FileID     FileID              `json:"file_id"`
+	VerifiedAt time.Time           `json:"verified_at"`
+	Issue      *FileIntegrityIssue `json:"issue"`
+}
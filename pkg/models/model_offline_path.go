@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// OfflinePath is a stash path that is currently unavailable, such as a
+// disconnected drive or network share. Files in offline paths are kept in the
+// database until the path is available again.
+type OfflinePath struct {
+	Path         string    `json:"path"`
+	OfflineSince time.Time `json:"offline_since"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type TrashObjectType string
+
+const (
+	TrashObjectTypeScene   TrashObjectType = "SCENE"
+	TrashObjectTypeImage   TrashObjectType = "IMAGE"
+	TrashObjectTypeGallery TrashObjectType = "GALLERY"
+	TrashObjectTypeFile    TrashObjectType = "FILE"
+)
+
+var AllTrashObjectType = []TrashObjectType{
+	TrashObjectTypeScene,
+	TrashObjectTypeImage,
+	TrashObjectTypeGallery,
+	TrashObjectTypeFile,
+}
+
+func (e TrashObjectType) IsValid() bool {
+	switch e {
+	case TrashObjectTypeScene, TrashObjectTypeImage, TrashObjectTypeGallery, TrashObjectTypeFile:
+		return true
+	}
+	return false
+}
+
+func (e TrashObjectType) String() string {
+	return string(e)
+}
+
+func (e *TrashObjectType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TrashObjectType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TrashObjectType", str)
+	}
+	return nil
+}
+
+func (e TrashObjectType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// TrashFile is a file that was moved to the trash directory.
+type TrashFile struct {
+	OriginalPath string `json:"original_path"`
+	TrashPath    string `json:"trash_path"`
+	Size         int64  `json:"size"`
+}
+
+// TrashItem is a deleted object that can be restored. Snapshot holds the
+// object and its file entries in the export JSON format.
+type TrashItem struct {
+	ID         int             `json:"id"`
+	ObjectType TrashObjectType `json:"object_type"`
+	Name       string          `json:"name"`
+	Files      []TrashFile     `json:"files"`
+	Snapshot   []byte          `json:"snapshot"`
+	DeletedAt  time.Time       `json:"deleted_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// NfoPrecedence determines whether the values in NFO files replace the
+// existing values of a scene when the NFO file is read.
+type NfoPrecedence string
+
+const (
+	// NfoPrecedenceExisting only sets fields of the scene that are empty.
+	// Tags and performers in the NFO file are added to the existing ones.
+	NfoPrecedenceExisting NfoPrecedence = "EXISTING"
+	// NfoPrecedenceNfo replaces the values of the scene with the values in
+	// the NFO file, where they are set.
+	NfoPrecedenceNfo NfoPrecedence = "NFO"
+)
+
+var AllNfoPrecedence = []NfoPrecedence{
+	NfoPrecedenceExisting,
+	NfoPrecedenceNfo,
+}
+
+func (e NfoPrecedence) IsValid() bool {
+	switch e {
+	case NfoPrecedenceExisting, NfoPrecedenceNfo:
+		return true
+	}
+	return false
+}
+
+func (e NfoPrecedence) String() string {
+	return string(e)
+}
+
+func (e *NfoPrecedence) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NfoPrecedence(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NfoPrecedence", str)
+	}
+	return nil
+}
+
+func (e NfoPrecedence) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,17 @@
+package models
+
+import "context"
+
+type OfflinePathReader interface {
+	All(ctx context.Context) ([]*OfflinePath, error)
+}
+
+type OfflinePathWriter interface {
+	Create(ctx context.Context, newObject *OfflinePath) error
+	Destroy(ctx context.Context, path string) error
+}
+
+type OfflinePathReaderWriter interface {
+	OfflinePathReader
+	OfflinePathWriter
+}
@@ -21,6 +21,7 @@ type JSONPaths struct {
 	Groups       string
 	Files        string
 	SavedFilters string
+	CustomFields string
 }
 
 func newJSONPaths(baseDir string) *JSONPaths {
@@ -36,6 +37,7 @@ func newJSONPaths(baseDir string) *JSONPaths {
 	jp.Tags = filepath.Join(baseDir, "tags")
 	jp.Files = filepath.Join(baseDir, "files")
 	jp.SavedFilters = filepath.Join(baseDir, "saved_filters")
+	jp.CustomFields = filepath.Join(baseDir, "custom_fields")
 	return &jp
 }
 
@@ -55,6 +57,7 @@ func EmptyJSONDirs(baseDir string) {
 	_ = fsutil.EmptyDir(jsonPaths.Tags)
 	_ = fsutil.EmptyDir(jsonPaths.Files)
 	_ = fsutil.EmptyDir(jsonPaths.SavedFilters)
+	_ = fsutil.EmptyDir(jsonPaths.CustomFields)
 }
 
 func EnsureJSONDirs(baseDir string) {
@@ -89,4 +92,7 @@ func EnsureJSONDirs(baseDir string) {
 	if err := fsutil.EnsureDir(jsonPaths.SavedFilters); err != nil {
 		logger.Warnf("couldn't create directories for Saved Filters: %v", err)
 	}
+	if err := fsutil.EnsureDir(jsonPaths.CustomFields); err != nil {
+		logger.Warnf("couldn't create directories for Custom Fields: %v", err)
+	}
 }
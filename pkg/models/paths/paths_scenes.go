@@ -50,6 +50,10 @@ func (sp *scenePaths) GetSpriteVttFilePath(checksum string) string {
 	return filepath.Join(sp.Vtt, checksum+"_thumbs.vtt")
 }
 
+func (sp *scenePaths) GetSceneCutsPath(checksum string) string {
+	return filepath.Join(sp.Vtt, checksum+"_scenecuts.json")
+}
+
 func (sp *scenePaths) GetInteractiveHeatmapPath(checksum string) string {
 	return filepath.Join(sp.InteractiveHeatmap, checksum+".png")
 }
@@ -28,6 +28,10 @@ type Repository struct {
 	Tag            TagReaderWriter
 	Character      CharacterReaderWriter
 	SavedFilter    SavedFilterReaderWriter
+	Trash          TrashReaderWriter
+	OfflinePath    OfflinePathReaderWriter
+	CustomField    CustomFieldDefinitionReaderWriter
+	ChangeLog      ChangeLogReaderWriter
 }
 
 func (r *Repository) WithTxn(ctx context.Context, fn txn.TxnFunc) error {
@@ -69,6 +69,9 @@ type FileReader interface {
 	FileCounter
 
 	GetCaptions(ctx context.Context, fileID FileID) ([]*VideoCaption, error)
+	// GetIntegrityIssue returns the issue found by the last verification of
+	// the file, or nil if there is none.
+	GetIntegrityIssue(ctx context.Context, fileID FileID) (*FileIntegrityIssue, error)
 	IsPrimary(ctx context.Context, fileID FileID) (bool, error)
 }
 
@@ -85,6 +88,9 @@ type FileWriter interface {
 	FileFingerprintWriter
 
 	UpdateCaptions(ctx context.Context, fileID FileID, captions []*VideoCaption) error
+	// SetIntegrity records the result of verifying the file, replacing the
+	// previous result.
+	SetIntegrity(ctx context.Context, integrity FileIntegrity) error
 }
 
 // FileReaderWriter provides all file methods.
@@ -70,6 +70,7 @@ type GalleryReader interface {
 	PerformerIDLoader
 	TagIDLoader
 	FileLoader
+	CustomFieldsReader
 
 	All(ctx context.Context) ([]*Gallery, error)
 }
@@ -79,6 +80,7 @@ type GalleryWriter interface {
 	GalleryCreator
 	GalleryUpdater
 	GalleryDestroyer
+	CustomFieldsWriter
 
 	AddFileID(ctx context.Context, id int, fileID FileID) error
 	AddImages(ctx context.Context, galleryID int, imageIDs ...int) error
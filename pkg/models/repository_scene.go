@@ -24,7 +24,8 @@ type SceneFinder interface {
 	FindByPerformerID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindByGalleryID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindByGroupID(ctx context.Context, groupID int) ([]*Scene, error)
-	FindDuplicates(ctx context.Context, distance int, durationDiff float64) ([][]*Scene, error)
+	FindDuplicates(ctx context.Context, distance int, durationDiff float64, audio bool) ([][]*Scene, error)
+	FindOverlaps(ctx context.Context, distance int, minDuration float64) ([]*SceneOverlap, error)
 }
 
 // SceneQueryer provides methods to query scenes.
@@ -100,6 +101,7 @@ type SceneReader interface {
 	SceneGroupLoader
 	StashIDLoader
 	VideoFileLoader
+	CustomFieldsReader
 
 	All(ctx context.Context) ([]*Scene, error)
 	Wall(ctx context.Context, q *string) ([]*Scene, error)
@@ -127,6 +129,7 @@ type SceneWriter interface {
 	SceneCreator
 	SceneUpdater
 	SceneDestroyer
+	CustomFieldsWriter
 
 	AddFileID(ctx context.Context, id int, fileID FileID) error
 	AddGalleryIDs(ctx context.Context, sceneID int, galleryIDs []int) error
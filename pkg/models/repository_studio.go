@@ -77,6 +77,7 @@ type StudioReader interface {
 	AliasLoader
 	StashIDLoader
 	TagIDLoader
+	CustomFieldsReader
 
 	All(ctx context.Context) ([]*Studio, error)
 	GetImage(ctx context.Context, studioID int) ([]byte, error)
@@ -88,6 +89,7 @@ type StudioWriter interface {
 	StudioCreator
 	StudioUpdater
 	StudioDestroyer
+	CustomFieldsWriter
 }
 
 // StudioReaderWriter provides all studio methods.
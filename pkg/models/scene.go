@@ -31,6 +31,8 @@ type SceneFilterType struct {
 	Rating100 *IntCriterionInput `json:"rating100"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by whether the primary file is in an offline stash path
+	IsOffline *bool `json:"is_offline"`
 	// Filter by o-counter
 	OCounter *IntCriterionInput `json:"o_counter"`
 	// Filter Scenes that have an exact phash match available
@@ -115,6 +117,8 @@ type SceneFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom fields
+	CustomFields []CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type SceneQueryOptions struct {
@@ -168,7 +172,8 @@ type SceneCreateInput struct {
 	// The first id will be assigned as primary.
 	// Files will be reassigned from existing scenes if applicable.
 	// Files must not already be primary for another scene.
-	FileIds []string `json:"file_ids"`
+	FileIds      []string               `json:"file_ids"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 type SceneUpdateInput struct {
@@ -191,12 +196,13 @@ type SceneUpdateInput struct {
 	Groups           []SceneGroupInput `json:"groups"`
 	TagIds           []string          `json:"tag_ids"`
 	// This should be a URL or a base64 encoded data URL
-	CoverImage    *string        `json:"cover_image"`
-	StashIds      []StashIDInput `json:"stash_ids"`
-	ResumeTime    *float64       `json:"resume_time"`
-	PlayDuration  *float64       `json:"play_duration"`
-	PlayCount     *int           `json:"play_count"`
-	PrimaryFileID *string        `json:"primary_file_id"`
+	CoverImage    *string            `json:"cover_image"`
+	StashIds      []StashIDInput     `json:"stash_ids"`
+	ResumeTime    *float64           `json:"resume_time"`
+	PlayDuration  *float64           `json:"play_duration"`
+	PlayCount     *int               `json:"play_count"`
+	PrimaryFileID *string            `json:"primary_file_id"`
+	CustomFields  *CustomFieldsInput `json:"custom_fields"`
 }
 
 type SceneDestroyInput struct {
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// SuggestedMarkerMinDuration is the minimum length in seconds of a
+	// suggested marker. Cuts closer together than this are merged.
+	SuggestedMarkerMinDuration = 10.0
+
+	// suggestedMarkerTolerance is the distance in seconds within which a
+	// cut is considered to coincide with an existing marker.
+	suggestedMarkerTolerance = 2.0
+
+	// suggestedMarkerMatchTolerance is the distance in seconds within which
+	// a start time matches a suggested marker. It allows for rounding when the
+	// start time is passed through a client.
+	suggestedMarkerMatchTolerance = 0.01
+)
+
+// SceneCut is a scene change detected in a video file.
+type SceneCut struct {
+	Seconds float64 `json:"seconds"`
+	// Score is the scene change score between 0 and 1.
+	Score float64 `json:"score"`
+}
+
+// SuggestedMarker is a candidate marker derived from detected scene cuts.
+type SuggestedMarker struct {
+	Seconds    float64  `json:"seconds"`
+	EndSeconds *float64 `json:"end_seconds"`
+	Score      float64  `json:"score"`
+}
+
+type SceneCuts []SceneCut
+
+// SuggestedMarkers returns the suggested markers for a video of the given
+// duration. Cuts within SuggestedMarkerMinDuration of the previous suggestion
+// are skipped, as are cuts that coincide with an existing marker. Each
+// suggestion ends at the following suggestion, or at the end of the video.
+func (c SceneCuts) SuggestedMarkers(duration float64, existing []*SceneMarker) []*SuggestedMarker {
+	cuts := make(SceneCuts, len(c))
+	copy(cuts, c)
+	sort.Slice(cuts, func(i, j int) bool {
+		return cuts[i].Seconds < cuts[j].Seconds
+	})
+
+	var points SceneCuts
+	last := 0.0
+	for _, cut := range cuts {
+		if cut.Seconds-last < SuggestedMarkerMinDuration {
+			continue
+		}
+
+		if duration > 0 && duration-cut.Seconds < SuggestedMarkerMinDuration {
+			break
+		}
+
+		points = append(points, cut)
+		last = cut.Seconds
+	}
+
+	var ret []*SuggestedMarker
+	for i, p := range points {
+		if nearMarker(p.Seconds, existing) {
+			continue
+		}
+
+		m := &SuggestedMarker{
+			Seconds: p.Seconds,
+			Score:   p.Score,
+		}
+
+		if i+1 < len(points) {
+			end := points[i+1].Seconds
+			m.EndSeconds = &end
+		} else if duration > 0 {
+			end := duration
+			m.EndSeconds = &end
+		}
+
+		ret = append(ret, m)
+	}
+
+	return ret
+}
+
+// FindSuggestedMarker returns the suggested marker that starts at seconds, or
+// nil if there is none.
+func FindSuggestedMarker(suggestions []*SuggestedMarker, seconds float64) *SuggestedMarker {
+	for _, s := range suggestions {
+		if math.Abs(s.Seconds-seconds) <= suggestedMarkerMatchTolerance {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func nearMarker(seconds float64, markers []*SceneMarker) bool {
+	for _, m := range markers {
+		if math.Abs(m.Seconds-seconds) <= suggestedMarkerTolerance {
+			return true
+		}
+	}
+
+	return false
+}
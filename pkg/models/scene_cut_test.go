@@ -0,0 +1,97 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSceneCuts_SuggestedMarkers(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	cuts := SceneCuts{
+		{Seconds: 60, Score: 0.5},
+		{Seconds: 5, Score: 0.9},
+		{Seconds: 30, Score: 0.6},
+		{Seconds: 35, Score: 0.7},
+		{Seconds: 95, Score: 0.4},
+	}
+
+	tests := []struct {
+		name     string
+		duration float64
+		existing []*SceneMarker
+		want     []*SuggestedMarker
+	}{
+		{
+			"no markers",
+			120,
+			nil,
+			[]*SuggestedMarker{
+				{Seconds: 30, EndSeconds: f(60), Score: 0.6},
+				{Seconds: 60, EndSeconds: f(95), Score: 0.5},
+				{Seconds: 95, EndSeconds: f(120), Score: 0.4},
+			},
+		},
+		{
+			"near end of video",
+			100,
+			nil,
+			[]*SuggestedMarker{
+				{Seconds: 30, EndSeconds: f(60), Score: 0.6},
+				{Seconds: 60, EndSeconds: f(100), Score: 0.5},
+			},
+		},
+		{
+			"existing marker",
+			120,
+			[]*SceneMarker{{Seconds: 61}},
+			[]*SuggestedMarker{
+				{Seconds: 30, EndSeconds: f(60), Score: 0.6},
+				{Seconds: 95, EndSeconds: f(120), Score: 0.4},
+			},
+		},
+		{
+			"unknown duration",
+			0,
+			nil,
+			[]*SuggestedMarker{
+				{Seconds: 30, EndSeconds: f(60), Score: 0.6},
+				{Seconds: 60, EndSeconds: f(95), Score: 0.5},
+				{Seconds: 95, Score: 0.4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cuts.SuggestedMarkers(tt.duration, tt.existing)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SceneCuts.SuggestedMarkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSuggestedMarker(t *testing.T) {
+	suggestions := []*SuggestedMarker{
+		{Seconds: 30.1},
+		{Seconds: 60.3},
+	}
+
+	tests := []struct {
+		name    string
+		seconds float64
+		want    *SuggestedMarker
+	}{
+		{"exact", 30.1, suggestions[0]},
+		{"rounded", 60.3000001, suggestions[1]},
+		{"float sum", 60.1 + 0.2, suggestions[1]},
+		{"no match", 45, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindSuggestedMarker(suggestions, tt.seconds); got != tt.want {
+				t.Errorf("FindSuggestedMarker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+// SceneOverlap is footage shared by two scenes. SceneA is the scene with the
+// larger file.
+type SceneOverlap struct {
+	SceneA *Scene `json:"scene_a"`
+	SceneB *Scene `json:"scene_b"`
+	// Ranges are ordered longest first.
+	Ranges []*SceneOverlapRange `json:"ranges"`
+}
+
+// SceneOverlapRange is a range of footage present in both scenes of a
+// SceneOverlap.
+type SceneOverlapRange struct {
+	StartA   float64 `json:"start_a"`
+	StartB   float64 `json:"start_b"`
+	Duration float64 `json:"duration"`
+}
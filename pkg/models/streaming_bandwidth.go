@@ -0,0 +1,157 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type StreamingNetworkEnum string
+
+const (
+	// Clients on a private, loopback or link-local address
+	StreamingNetworkEnumLocal StreamingNetworkEnum = "LOCAL"
+	// Clients on any other address
+	StreamingNetworkEnumRemote StreamingNetworkEnum = "REMOTE"
+)
+
+var AllStreamingNetworkEnum = []StreamingNetworkEnum{
+	StreamingNetworkEnumLocal,
+	StreamingNetworkEnumRemote,
+}
+
+func (e StreamingNetworkEnum) IsValid() bool {
+	switch e {
+	case StreamingNetworkEnumLocal, StreamingNetworkEnumRemote:
+		return true
+	}
+	return false
+}
+
+func (e StreamingNetworkEnum) String() string {
+	return string(e)
+}
+
+func (e *StreamingNetworkEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StreamingNetworkEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StreamingNetworkEnum", str)
+	}
+	return nil
+}
+
+func (e StreamingNetworkEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type StreamingAuthEnum string
+
+const (
+	// Clients authenticated with the API key
+	StreamingAuthEnumAPIKey StreamingAuthEnum = "API_KEY"
+	// Clients authenticated with a login session
+	StreamingAuthEnumSession StreamingAuthEnum = "SESSION"
+)
+
+var AllStreamingAuthEnum = []StreamingAuthEnum{
+	StreamingAuthEnumAPIKey,
+	StreamingAuthEnumSession,
+}
+
+func (e StreamingAuthEnum) IsValid() bool {
+	switch e {
+	case StreamingAuthEnumAPIKey, StreamingAuthEnumSession:
+		return true
+	}
+	return false
+}
+
+func (e StreamingAuthEnum) String() string {
+	return string(e)
+}
+
+func (e *StreamingAuthEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StreamingAuthEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StreamingAuthEnum", str)
+	}
+	return nil
+}
+
+func (e StreamingAuthEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// StreamingClient describes the client requesting a stream.
+type StreamingClient struct {
+	// UserID is the authenticated user. API key requests resolve to the
+	// user that owns the key.
+	UserID string
+	// Auth is how the client authenticated. Empty if unauthenticated.
+	Auth  StreamingAuthEnum
+	Local bool
+}
+
+// StreamingBandwidthPolicy limits the bitrate of streams served to matching clients.
+type StreamingBandwidthPolicy struct {
+	// User restricts the policy to a single user. Matches all users if empty.
+	User string `json:"user"`
+	// Network restricts the policy to local or remote clients. Matches both if nil.
+	Network *StreamingNetworkEnum `json:"network"`
+	// Auth restricts the policy to clients using the API key or a login
+	// session. Matches both if nil.
+	Auth *StreamingAuthEnum `json:"auth"`
+	// MaxBitrate is the maximum stream bitrate in kilobits per second.
+	MaxBitrate int `json:"maxBitrate"`
+}
+
+func (p StreamingBandwidthPolicy) Matches(c StreamingClient) bool {
+	if p.User != "" && p.User != c.UserID {
+		return false
+	}
+
+	if p.Auth != nil && *p.Auth != c.Auth {
+		return false
+	}
+
+	if p.Network != nil {
+		switch *p.Network {
+		case StreamingNetworkEnumLocal:
+			return c.Local
+		case StreamingNetworkEnumRemote:
+			return !c.Local
+		}
+	}
+
+	return true
+}
+
+type StreamingBandwidthPolicies []*StreamingBandwidthPolicy
+
+// MaxBitrate returns the maximum bitrate in kilobits per second that may be
+// streamed to the provided client. Where multiple policies match, the most
+// restrictive applies. Returns 0 if the client is unrestricted.
+func (p StreamingBandwidthPolicies) MaxBitrate(c StreamingClient) int {
+	ret := 0
+	for _, policy := range p {
+		if policy.MaxBitrate <= 0 || !policy.Matches(c) {
+			continue
+		}
+
+		if ret == 0 || policy.MaxBitrate < ret {
+			ret = policy.MaxBitrate
+		}
+	}
+
+	return ret
+}
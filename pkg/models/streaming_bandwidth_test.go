@@ -0,0 +1,61 @@
+package models
+
+import "testing"
+
+func TestStreamingBandwidthPolicies_MaxBitrate(t *testing.T) {
+	local := StreamingNetworkEnumLocal
+	remote := StreamingNetworkEnumRemote
+	apiKey := StreamingAuthEnumAPIKey
+
+	policies := StreamingBandwidthPolicies{
+		{Network: &remote, MaxBitrate: 8000},
+		{User: "alice", Network: &remote, MaxBitrate: 4000},
+		{User: "bob", MaxBitrate: 6000},
+		{Network: &local, MaxBitrate: 0},
+		{User: "carol", Auth: &apiKey, MaxBitrate: 2000},
+	}
+
+	tests := []struct {
+		name   string
+		client StreamingClient
+		want   int
+	}{
+		{"local unrestricted", StreamingClient{UserID: "alice", Local: true}, 0},
+		{"remote default", StreamingClient{UserID: "carol"}, 8000},
+		{"remote user most restrictive", StreamingClient{UserID: "alice"}, 4000},
+		{"user any network local", StreamingClient{UserID: "bob", Local: true}, 6000},
+		{"user any network remote", StreamingClient{UserID: "bob"}, 6000},
+		{"anonymous remote", StreamingClient{}, 8000},
+		{"api key", StreamingClient{UserID: "carol", Auth: StreamingAuthEnumAPIKey, Local: true}, 2000},
+		{"session not api key", StreamingClient{UserID: "carol", Auth: StreamingAuthEnumSession, Local: true}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policies.MaxBitrate(tt.client); got != tt.want {
+				t.Errorf("StreamingBandwidthPolicies.MaxBitrate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVideoFile_ExceedsBitrate(t *testing.T) {
+	tests := []struct {
+		name       string
+		bitRate    int64
+		maxBitrate int
+		want       bool
+	}{
+		{"unlimited", 50000000, 0, false},
+		{"below", 3000000, 4000, false},
+		{"equal", 4000000, 4000, false},
+		{"above", 4000001, 4000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := VideoFile{BitRate: tt.bitRate}
+			if got := f.ExceedsBitrate(tt.maxBitrate); got != tt.want {
+				t.Errorf("VideoFile.ExceedsBitrate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type TrashReader interface {
+	Find(ctx context.Context, id int) (*TrashItem, error)
+	FindMany(ctx context.Context, ids []int) ([]*TrashItem, error)
+	// FindDeletedBefore returns the items deleted before t.
+	FindDeletedBefore(ctx context.Context, t time.Time) ([]*TrashItem, error)
+	Query(ctx context.Context, objectType *TrashObjectType, findFilter *FindFilterType) ([]*TrashItem, int, error)
+	All(ctx context.Context) ([]*TrashItem, error)
+}
+
+type TrashWriter interface {
+	Create(ctx context.Context, newObject *TrashItem) error
+	Destroy(ctx context.Context, id int) error
+}
+
+type TrashReaderWriter interface {
+	TrashReader
+	TrashWriter
+}
@@ -17,6 +17,7 @@ import (
 type ImporterReaderWriter interface {
 	models.PerformerCreatorUpdater
 	models.PerformerQueryer
+	models.CustomFieldsWriter
 }
 
 type Importer struct {
@@ -123,6 +124,14 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		}
 	}
 
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting performer custom fields: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -176,6 +176,38 @@ func TestImporterPostImport(t *testing.T) {
 	db.AssertExpectations(t)
 }
 
+func TestImporterPostImportCustomFields(t *testing.T) {
+	db := mocks.NewDatabase()
+
+	customFields := map[string]interface{}{
+		"source": "web",
+		"height": float64(180),
+	}
+
+	i := Importer{
+		ReaderWriter: db.Performer,
+		TagWriter:    db.Tag,
+		Input: jsonschema.Performer{
+			CustomFields: customFields,
+		},
+	}
+
+	setCustomFieldsErr := errors.New("SetCustomFields error")
+
+	db.Performer.On("SetCustomFields", testCtx, performerID, models.CustomFieldsInput{
+		Full: customFields,
+	}).Return(nil).Once()
+	db.Performer.On("SetCustomFields", testCtx, errImageID, mock.Anything).Return(setCustomFieldsErr).Once()
+
+	err := i.PostImport(testCtx, performerID)
+	assert.Nil(t, err)
+
+	err = i.PostImport(testCtx, errImageID)
+	assert.NotNil(t, err)
+
+	db.AssertExpectations(t)
+}
+
 func TestImporterFindExistingID(t *testing.T) {
 	db := mocks.NewDatabase()
 
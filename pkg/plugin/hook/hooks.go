@@ -79,6 +79,10 @@ var AllHookTriggerEnum = []TriggerEnum{
 	MovieUpdatePost,
 	MovieDestroyPost,
 
+	GroupCreatePost,
+	GroupUpdatePost,
+	GroupDestroyPost,
+
 	PerformerCreatePost,
 	PerformerUpdatePost,
 	PerformerDestroyPost,
@@ -91,6 +95,10 @@ var AllHookTriggerEnum = []TriggerEnum{
 	TagUpdatePost,
 	TagMergePost,
 	TagDestroyPost,
+
+	CharacterCreatePost,
+	CharacterUpdatePost,
+	CharacterDestroyPost,
 }
 
 func (e TriggerEnum) IsValid() bool {
@@ -120,6 +128,10 @@ func (e TriggerEnum) IsValid() bool {
 		MovieUpdatePost,
 		MovieDestroyPost,
 
+		GroupCreatePost,
+		GroupUpdatePost,
+		GroupDestroyPost,
+
 		PerformerCreatePost,
 		PerformerUpdatePost,
 		PerformerDestroyPost,
@@ -130,7 +142,12 @@ func (e TriggerEnum) IsValid() bool {
 
 		TagCreatePost,
 		TagUpdatePost,
-		TagDestroyPost:
+		TagMergePost,
+		TagDestroyPost,
+
+		CharacterCreatePost,
+		CharacterUpdatePost,
+		CharacterDestroyPost:
 		return true
 	}
 	return false
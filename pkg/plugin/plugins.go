@@ -104,8 +104,15 @@ type Cache struct {
 	plugins      []Config
 	sessionStore *session.Store
 	gqlHandler   http.Handler
+
+	postHookListeners []PostHookListener
 }
 
+// PostHookListener is called for each post hook that is triggered, whether or
+// not any plugin handles it. It is called after the transaction that
+// triggered the hook is committed.
+type PostHookListener func(ctx context.Context, id int, hookType hook.TriggerEnum, inputFields []string)
+
 // NewCache returns a new Cache.
 //
 // Plugins configurations are loaded from yml files in the plugin
@@ -123,6 +130,12 @@ func (c *Cache) RegisterGQLHandler(handler http.Handler) {
 	c.gqlHandler = handler
 }
 
+// AddPostHookListener adds a listener that is called for each post hook.
+// Listeners must be added before any hooks are triggered.
+func (c *Cache) AddPostHookListener(l PostHookListener) {
+	c.postHookListeners = append(c.postHookListeners, l)
+}
+
 func (c *Cache) RegisterSessionStore(sessionStore *session.Store) {
 	c.sessionStore = sessionStore
 }
@@ -358,6 +371,8 @@ func waitForTask(ctx context.Context, task Task) error {
 }
 
 func (c Cache) ExecutePostHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, inputFields []string) {
+	c.notifyPostHookListeners(ctx, id, hookType, inputFields)
+
 	if err := c.executePostHooks(ctx, hookType, common.HookContext{
 		ID:          id,
 		Type:        hookType.String(),
@@ -368,6 +383,27 @@ func (c Cache) ExecutePostHooks(ctx context.Context, id int, hookType hook.Trigg
 	}
 }
 
+// notifyPostHookListeners calls the post hook listeners. If ctx is in a
+// transaction, the listeners are called once the transaction is committed.
+func (c Cache) notifyPostHookListeners(ctx context.Context, id int, hookType hook.TriggerEnum, inputFields []string) {
+	if len(c.postHookListeners) == 0 {
+		return
+	}
+
+	notify := func(ctx context.Context) {
+		for _, l := range c.postHookListeners {
+			l(ctx, id, hookType, inputFields)
+		}
+	}
+
+	if txn.InTxn(ctx) {
+		txn.AddPostCommitHook(ctx, notify)
+		return
+	}
+
+	notify(ctx)
+}
+
 func (c Cache) RegisterPostHooks(ctx context.Context, id int, hookType hook.TriggerEnum, input interface{}, inputFields []string) {
 	txn.AddPostCommitHook(ctx, func(ctx context.Context) {
 		c.ExecutePostHooks(ctx, id, hookType, input, inputFields)
@@ -69,6 +69,12 @@ func (d *FileDeleter) MarkGeneratedFiles(scene *models.Scene) error {
 		files = append(files, vttPath)
 	}
 
+	cutsPath := d.Paths.Scene.GetSceneCutsPath(sceneHash)
+	exists, _ = fsutil.FileExists(cutsPath)
+	if exists {
+		files = append(files, cutsPath)
+	}
+
 	heatmapPath := d.Paths.Scene.GetInteractiveHeatmapPath(sceneHash)
 	exists, _ = fsutil.FileExists(heatmapPath)
 	if exists {
@@ -168,7 +174,7 @@ func (s *Service) deleteFiles(ctx context.Context, scene *models.Scene, fileDele
 			funscriptPath := video.GetFunscriptPath(f.Path)
 			funscriptExists, _ := fsutil.FileExists(funscriptPath)
 			if funscriptExists {
-				if err := fileDeleter.Files([]string{funscriptPath}); err != nil {
+				if err := fileDeleter.Trash([]string{funscriptPath}); err != nil {
 					return err
 				}
 			}
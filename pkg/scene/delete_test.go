@@ -0,0 +1,64 @@
+package scene
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileDeleter_MarkGeneratedFiles(t *testing.T) {
+	const hash = "0123456789abcdef"
+
+	p := paths.NewPaths(t.TempDir(), "")
+	generated := []string{
+		p.Scene.GetVideoPreviewPath(hash),
+		p.Scene.GetWebpPreviewPath(hash),
+		p.Scene.GetTranscodePath(hash),
+		p.Scene.GetSpriteImageFilePath(hash),
+		p.Scene.GetSpriteVttFilePath(hash),
+		p.Scene.GetSceneCutsPath(hash),
+		p.Scene.GetInteractiveHeatmapPath(hash),
+	}
+
+	// files of another scene are kept
+	other := p.Scene.GetSceneCutsPath("fedcba9876543210")
+
+	for _, f := range append(generated, other) {
+		writeTestFile(t, f)
+	}
+
+	d := &FileDeleter{
+		Deleter:        file.NewDeleter(),
+		FileNamingAlgo: models.HashAlgorithmOshash,
+		Paths:          &p,
+	}
+
+	if err := d.MarkGeneratedFiles(&models.Scene{OSHash: hash}); err != nil {
+		t.Fatalf("MarkGeneratedFiles() error = %v", err)
+	}
+	d.Commit()
+
+	for _, f := range generated {
+		_, err := os.Stat(f)
+		assert.True(t, os.IsNotExist(err), "%s was not deleted", f)
+	}
+
+	_, err := os.Stat(other)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,87 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/ffmpeg/transcoder"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+type ClipOptions struct {
+	Start    float64
+	Duration float64
+
+	// Copy copies the video and audio streams without re-encoding. The
+	// start point is aligned to the preceding keyframe.
+	Copy bool
+}
+
+// Clip cuts the section of input described by options into output.
+// Unlike the other generate functions, output is not a generated path
+// and will never be overwritten.
+func (g Generator) Clip(ctx context.Context, input string, output string, options ClipOptions) error {
+	if options.Duration <= 0 {
+		return fmt.Errorf("invalid clip duration %v", options.Duration)
+	}
+
+	if exists, _ := fsutil.FileExists(output); exists {
+		return fmt.Errorf("%s already exists", output)
+	}
+
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	// use the output extension so that ffmpeg picks the correct muxer
+	pattern := "*" + filepath.Ext(output)
+	if err := g.generateFile(lockCtx, g.ScenePaths, pattern, output, g.clip(input, options)); err != nil {
+		return err
+	}
+
+	logger.Debug("created clip: ", output)
+
+	return nil
+}
+
+func (g Generator) clip(input string, options ClipOptions) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		args := ClipArgs(input, tmpFn, options)
+		return g.generate(lockCtx, args)
+	}
+}
+
+// ClipArgs returns the ffmpeg arguments to cut the section of input described
+// by options into output.
+func ClipArgs(input string, output string, options ClipOptions) ffmpeg.Args {
+	trimOptions := transcoder.TranscodeOptions{
+		OutputPath: output,
+		StartTime:  options.Start,
+		Duration:   options.Duration,
+	}
+
+	if options.Copy {
+		trimOptions.VideoCodec = ffmpeg.VideoCodecCopy
+		trimOptions.AudioCodec = ffmpeg.AudioCodecCopy
+		trimOptions.ExtraOutputArgs = []string{
+			"-map", "0:v:0",
+			"-map", "0:a?",
+			"-avoid_negative_ts", "make_zero",
+		}
+	} else {
+		// slow seek so that the cut is frame accurate
+		trimOptions.SlowSeek = true
+		trimOptions.VideoCodec = ffmpeg.VideoCodecLibX264
+		trimOptions.VideoArgs = ffmpeg.Args{
+			"-pix_fmt", "yuv420p",
+			"-preset", "medium",
+			"-crf", "20",
+			"-movflags", "+faststart",
+		}
+		trimOptions.AudioCodec = ffmpeg.AudioCodecAAC
+	}
+
+	return transcoder.Transcode(input, trimOptions)
+}
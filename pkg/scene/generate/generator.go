@@ -19,6 +19,7 @@ const (
 	jpgPattern  = "*.jpg"
 	txtPattern  = "*.txt"
 	vttPattern  = "*.vtt"
+	jsonPattern = "*.json"
 )
 
 type Paths interface {
@@ -43,6 +44,8 @@ type ScenePaths interface {
 	GetSpriteVttFilePath(checksum string) string
 
 	GetTranscodePath(checksum string) string
+
+	GetSceneCutsPath(checksum string) string
 }
 
 type FFMpegConfig interface {
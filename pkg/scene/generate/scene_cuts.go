@@ -0,0 +1,150 @@
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// scene detection is performed on a downscaled video for speed
+const sceneCutsWidth = 320
+
+// SceneCuts detects scene changes in the input video and writes them to the
+// scene cuts file for hash. Only frames with a scene change score above
+// threshold are recorded.
+func (g Generator) SceneCuts(ctx context.Context, input string, hash string, threshold float64) error {
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	output := g.ScenePaths.GetSceneCutsPath(hash)
+	if !g.Overwrite {
+		if exists, _ := fsutil.FileExists(output); exists {
+			return nil
+		}
+	}
+
+	if err := g.generateFile(lockCtx, g.ScenePaths, jsonPattern, output, g.sceneCuts(input, threshold)); err != nil {
+		return err
+	}
+
+	logger.Debug("created scene cuts: ", output)
+
+	return nil
+}
+
+func (g Generator) sceneCuts(input string, threshold float64) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		var videoFilter ffmpeg.VideoFilter
+		videoFilter = videoFilter.ScaleWidth(sceneCutsWidth)
+		videoFilter = videoFilter.SelectSceneChange(threshold)
+		videoFilter = videoFilter.MetadataPrint()
+
+		var args ffmpeg.Args
+		// metadata is printed at the info log level
+		args = args.LogLevel(ffmpeg.LogLevelInfo)
+		args = append(args, "-nostats")
+		args = args.Input(input)
+		args = args.SkipAudio()
+		args = args.VideoFilter(videoFilter)
+		args = args.Format(ffmpeg.FormatNull)
+		args = args.NullOutput()
+
+		stderr, err := g.generateStderr(lockCtx, args)
+		if err != nil {
+			return err
+		}
+
+		cuts := parseSceneCuts(stderr)
+
+		data, err := json.Marshal(cuts)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(tmpFn, data, 0644)
+	}
+}
+
+// generateStderr runs ffmpeg with the given args and returns its log output.
+func (g Generator) generateStderr(lockCtx *fsutil.LockContext, args []string) ([]byte, error) {
+	cmd := g.Encoder.Command(lockCtx, args)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting command: %w", err)
+	}
+
+	lockCtx.AttachCommand(cmd)
+
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitErr.Stderr = stderr.Bytes()
+			err = exitErr
+		}
+		return nil, fmt.Errorf("error running ffmpeg command <%s>: %w", strings.Join(args, " "), err)
+	}
+
+	return stderr.Bytes(), nil
+}
+
+// parseSceneCuts parses the output of the metadata print filter. Each
+// selected frame is logged as a line containing pts_time, followed by a
+// line containing its lavfi.scene_score.
+func parseSceneCuts(output []byte) models.SceneCuts {
+	ret := models.SceneCuts{}
+
+	var current *models.SceneCut
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if v, ok := metadataValue(line, "pts_time:"); ok {
+			seconds, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				current = nil
+				continue
+			}
+
+			ret = append(ret, models.SceneCut{Seconds: seconds})
+			current = &ret[len(ret)-1]
+			continue
+		}
+
+		if v, ok := metadataValue(line, "lavfi.scene_score="); ok && current != nil {
+			if score, err := strconv.ParseFloat(v, 64); err == nil {
+				current.Score = score
+			}
+		}
+	}
+
+	return ret
+}
+
+func metadataValue(line string, key string) (string, bool) {
+	i := strings.Index(line, key)
+	if i == -1 {
+		return "", false
+	}
+
+	v := line[i+len(key):]
+	if fields := strings.Fields(v); len(fields) > 0 {
+		return fields[0], true
+	}
+
+	return "", false
+}
@@ -0,0 +1,32 @@
+package generate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_parseSceneCuts(t *testing.T) {
+	const output = `Input #0, mov,mp4,m4a,3gp,3g2,mj2, from 'video.mp4':
+  Duration: 00:01:40.00, start: 0.000000, bitrate: 1000 kb/s
+[Parsed_metadata_2 @ 0x5581c8e0c340] frame:0    pts:153600  pts_time:12.5
+[Parsed_metadata_2 @ 0x5581c8e0c340] lavfi.scene_score=0.512345
+[Parsed_metadata_2 @ 0x5581c8e0c340] frame:1    pts:491520  pts_time:40
+[Parsed_metadata_2 @ 0x5581c8e0c340] lavfi.scene_score=0.9
+[out#0/null @ 0x5581c8e0b840] video:1kB audio:0kB subtitle:0kB other streams:0kB global headers:0kB muxing overhead: unknown
+`
+
+	want := models.SceneCuts{
+		{Seconds: 12.5, Score: 0.512345},
+		{Seconds: 40, Score: 0.9},
+	}
+
+	if got := parseSceneCuts([]byte(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSceneCuts() = %v, want %v", got, want)
+	}
+
+	if got := parseSceneCuts(nil); len(got) != 0 {
+		t.Errorf("parseSceneCuts(nil) = %v, want empty", got)
+	}
+}
@@ -18,6 +18,7 @@ type ImporterReaderWriter interface {
 	models.SceneCreatorUpdater
 	models.ViewHistoryWriter
 	models.OHistoryWriter
+	models.CustomFieldsWriter
 	FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error)
 }
 
@@ -447,6 +448,14 @@ func (i *Importer) PostImport(ctx context.Context, id int) error {
 		return err
 	}
 
+	if len(i.Input.CustomFields) > 0 {
+		if err := i.ReaderWriter.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: i.Input.CustomFields,
+		}); err != nil {
+			return fmt.Errorf("error setting scene custom fields: %v", err)
+		}
+	}
+
 	return nil
 }
 
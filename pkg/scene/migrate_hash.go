@@ -37,6 +37,10 @@ func MigrateHash(p *paths.Paths, oldHash string, newHash string) {
 	migrateSceneFiles(oldPath, newPath)
 	migrateVttFile(newVttPath, oldPath, newPath)
 
+	oldPath = scenePaths.GetSceneCutsPath(oldHash)
+	newPath = scenePaths.GetSceneCutsPath(newHash)
+	migrateSceneFiles(oldPath, newPath)
+
 	oldPath = scenePaths.GetInteractiveHeatmapPath(oldHash)
 	newPath = scenePaths.GetInteractiveHeatmapPath(newHash)
 	migrateSceneFiles(oldPath, newPath)
@@ -0,0 +1,41 @@
+package scene
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateHash(t *testing.T) {
+	const (
+		oldHash = "0123456789abcdef"
+		newHash = "fedcba9876543210"
+	)
+
+	p := paths.NewPaths(t.TempDir(), "")
+	generated := []func(checksum string) string{
+		p.Scene.GetVideoPreviewPath,
+		p.Scene.GetWebpPreviewPath,
+		p.Scene.GetTranscodePath,
+		p.Scene.GetSpriteImageFilePath,
+		p.Scene.GetSpriteVttFilePath,
+		p.Scene.GetSceneCutsPath,
+		p.Scene.GetInteractiveHeatmapPath,
+	}
+
+	for _, path := range generated {
+		writeTestFile(t, path(oldHash))
+	}
+
+	MigrateHash(&p, oldHash, newHash)
+
+	for _, path := range generated {
+		_, err := os.Stat(path(oldHash))
+		assert.True(t, os.IsNotExist(err), "%s was not renamed", path(oldHash))
+
+		_, err = os.Stat(path(newHash))
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,176 @@
+// Package nfo reads and writes Kodi-style NFO files, which are used by media
+// centres such as Kodi and Jellyfin to store the metadata of a video file in
+// an XML file next to it.
+package nfo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Extension is the extension of NFO files.
+	Extension = ".nfo"
+
+	// movieFilename is the name of an NFO file that applies to the only video
+	// file in a folder.
+	movieFilename = "movie.nfo"
+
+	// uniqueIDType is the type of the unique ID element containing the ID of
+	// the stash scene.
+	uniqueIDType = "stash"
+)
+
+// Kinds of images written next to the NFO file.
+const (
+	ImagePoster = "poster"
+	ImageFanart = "fanart"
+)
+
+// Movie is the root element of a movie NFO file. Only the elements used by
+// stash are included.
+type Movie struct {
+	XMLName    xml.Name   `xml:"movie"`
+	Title      string     `xml:"title,omitempty"`
+	UserRating int        `xml:"userrating,omitempty"`
+	Plot       string     `xml:"plot,omitempty"`
+	Premiered  string     `xml:"premiered,omitempty"`
+	Year       int        `xml:"year,omitempty"`
+	Studio     string     `xml:"studio,omitempty"`
+	Director   string     `xml:"director,omitempty"`
+	Genres     []string   `xml:"genre,omitempty"`
+	Tags       []string   `xml:"tag,omitempty"`
+	Actors     []Actor    `xml:"actor,omitempty"`
+	UniqueIDs  []UniqueID `xml:"uniqueid,omitempty"`
+	Thumbs     []Thumb    `xml:"thumb,omitempty"`
+	Fanart     *Fanart    `xml:"fanart,omitempty"`
+	DateAdded  string     `xml:"dateadded,omitempty"`
+}
+
+type Actor struct {
+	Name  string `xml:"name"`
+	Role  string `xml:"role,omitempty"`
+	Order int    `xml:"order"`
+}
+
+type UniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr,omitempty"`
+	Value   string `xml:",chardata"`
+}
+
+type Thumb struct {
+	Aspect string `xml:"aspect,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+type Fanart struct {
+	Thumbs []Thumb `xml:"thumb"`
+}
+
+// TagNames returns the names of the genres and tags of the movie, without
+// duplicates.
+func (m *Movie) TagNames() []string {
+	var ret []string
+	seen := make(map[string]bool)
+	for _, v := range append(append([]string{}, m.Genres...), m.Tags...) {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+
+		seen[strings.ToLower(v)] = true
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+// ActorNames returns the names of the actors of the movie, without
+// duplicates.
+func (m *Movie) ActorNames() []string {
+	var ret []string
+	seen := make(map[string]bool)
+	for _, a := range m.Actors {
+		v := strings.TrimSpace(a.Name)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+
+		seen[strings.ToLower(v)] = true
+		ret = append(ret, v)
+	}
+
+	return ret
+}
+
+// Parse reads a movie NFO file.
+func Parse(r io.Reader) (*Movie, error) {
+	var ret Movie
+	if err := xml.NewDecoder(r).Decode(&ret); err != nil {
+		return nil, fmt.Errorf("parsing NFO: %w", err)
+	}
+
+	return &ret, nil
+}
+
+// Marshal returns the contents of the NFO file for the movie.
+func Marshal(m *Movie) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// Paths returns the paths of the NFO files that may contain the metadata of
+// the video file, in order of precedence. movie.nfo is only included if the
+// video file is the only video file in its folder, and the NFO file with the
+// same name as the video file takes precedence over it.
+func Paths(videoPath string, onlyVideo bool) []string {
+	ret := []string{Path(videoPath)}
+	if onlyVideo {
+		ret = append(ret, filepath.Join(filepath.Dir(videoPath), movieFilename))
+	}
+
+	return ret
+}
+
+// Path returns the path of the NFO file with the same name as the video file.
+func Path(videoPath string) string {
+	return strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + Extension
+}
+
+// ImagePath returns the path of the poster or fanart image for the video
+// file, using the image extension ext.
+func ImagePath(videoPath string, kind string, ext string) string {
+	return strings.TrimSuffix(videoPath, filepath.Ext(videoPath)) + "-" + kind + ext
+}
+
+// imageExtensions are the extensions of the images written next to NFO
+// files.
+var imageExtensions = []string{".jpg", ".png", ".webp"}
+
+// SidecarPaths returns the paths of the NFO file and images that may exist
+// for the video file. The order is the same for all video files, so that the
+// sidecars can be moved with the video file.
+func SidecarPaths(videoPath string) []string {
+	ret := []string{Path(videoPath)}
+	for _, kind := range []string{ImagePoster, ImageFanart} {
+		for _, ext := range imageExtensions {
+			ret = append(ret, ImagePath(videoPath, kind, ext))
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,105 @@
+package nfo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testNfo = `<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>
+<movie>
+  <title>Movie Title</title>
+  <originaltitle>Original Title</originaltitle>
+  <plot>The plot.</plot>
+  <premiered>2006-01-02</premiered>
+  <year>2006</year>
+  <studio>Studio</studio>
+  <genre>Drama</genre>
+  <genre>Comedy</genre>
+  <tag>drama</tag>
+  <tag>Tag</tag>
+  <actor>
+    <name>Actor One</name>
+    <role>Role</role>
+    <order>0</order>
+  </actor>
+  <actor>
+    <name>Actor Two</name>
+    <order>1</order>
+  </actor>
+  <actor>
+    <name>actor one</name>
+  </actor>
+  <uniqueid type="imdb" default="true">tt0000001</uniqueid>
+</movie>
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse(strings.NewReader(testNfo))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	assert := assert.New(t)
+	assert.Equal("Movie Title", m.Title)
+	assert.Equal("The plot.", m.Plot)
+	assert.Equal("2006-01-02", m.Premiered)
+	assert.Equal(2006, m.Year)
+	assert.Equal("Studio", m.Studio)
+	assert.Equal([]string{"Drama", "Comedy", "Tag"}, m.TagNames())
+	assert.Equal([]string{"Actor One", "Actor Two"}, m.ActorNames())
+	assert.Equal([]UniqueID{{Type: "imdb", Default: true, Value: "tt0000001"}}, m.UniqueIDs)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("<tvshow><title>Show</title></tvshow>"))
+	assert.NotNil(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	m := &Movie{
+		Title:     "Title",
+		Plot:      "Plot & details",
+		Premiered: "2006-01-02",
+		Year:      2006,
+		Tags:      []string{"Tag"},
+		Actors:    []Actor{{Name: "Actor", Order: 0}},
+		UniqueIDs: []UniqueID{{Type: uniqueIDType, Default: true, Value: "1"}},
+		Thumbs:    []Thumb{{Aspect: ImagePoster, Value: "video-poster.jpg"}},
+		Fanart:    &Fanart{Thumbs: []Thumb{{Value: "video-fanart.jpg"}}},
+	}
+
+	data, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	assert.Contains(t, string(data), "<plot>Plot &amp; details</plot>")
+
+	got, err := Parse(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got.XMLName = m.XMLName
+	assert.Equal(t, m, got)
+}
+
+func TestPaths(t *testing.T) {
+	assert.Equal(t, []string{
+		"/stash/videos/video.nfo",
+		"/stash/videos/movie.nfo",
+	}, Paths("/stash/videos/video.mp4", true))
+
+	assert.Equal(t, []string{
+		"/stash/videos/video.nfo",
+	}, Paths("/stash/videos/video.mp4", false))
+}
+
+func TestSidecarPaths(t *testing.T) {
+	got := SidecarPaths("/stash/video.mkv")
+	assert.Equal(t, "/stash/video.nfo", got[0])
+	assert.Contains(t, got, "/stash/video-poster.jpg")
+	assert.Contains(t, got, "/stash/video-fanart.webp")
+}
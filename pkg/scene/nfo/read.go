@@ -0,0 +1,340 @@
+package nfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil"
+)
+
+type SceneUpdater interface {
+	models.PerformerIDLoader
+	models.TagIDLoader
+	UpdatePartial(ctx context.Context, id int, updatedScene models.ScenePartial) (*models.Scene, error)
+}
+
+// Reader applies the metadata in NFO files to scenes. Studios, performers
+// and tags in the NFO file are matched by name, and are created if they do
+// not exist. VideoExtensions are used to determine whether a video file is
+// the only one in its folder.
+type Reader struct {
+	FS              models.FS
+	VideoExtensions []string
+	SceneUpdater    SceneUpdater
+	StudioWriter    models.StudioFinderCreator
+	PerformerWriter models.PerformerFinderCreator
+	TagWriter       models.TagFinderCreator
+	Precedence      models.NfoPrecedence
+}
+
+// Read applies the NFO file of the video file to the scene. It does nothing
+// if the video file has no NFO file.
+func (r *Reader) Read(ctx context.Context, s *models.Scene, f *models.VideoFile) error {
+	// NFO files are not read from zip files
+	if f.ZipFileID != nil {
+		return nil
+	}
+
+	m, path, err := r.find(f.Path)
+	if err != nil {
+		return err
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	partial, changed, err := r.ScenePartial(ctx, s, m)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	logger.Infof("Updating scene %s from %s", s.DisplayName(), path)
+
+	if _, err := r.SceneUpdater.UpdatePartial(ctx, s.ID, partial); err != nil {
+		return fmt.Errorf("updating scene: %w", err)
+	}
+
+	return nil
+}
+
+// find returns the first NFO file found for the video file. It returns nil
+// if there is no NFO file.
+func (r *Reader) find(videoPath string) (*Movie, string, error) {
+	paths := Paths(videoPath, false)
+
+	// the folder only needs to be read if there is no NFO file with the same
+	// name as the video file
+	if _, err := r.FS.Stat(paths[0]); errors.Is(err, fs.ErrNotExist) {
+		onlyVideo, err := r.onlyVideo(videoPath)
+		if err != nil {
+			return nil, "", err
+		}
+
+		paths = Paths(videoPath, onlyVideo)
+	}
+
+	for _, p := range paths {
+		f, err := r.FS.Open(p)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, "", fmt.Errorf("opening %s: %w", p, err)
+		}
+
+		m, err := Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("reading %s: %w", p, err)
+		}
+
+		return m, p, nil
+	}
+
+	return nil, "", nil
+}
+
+// onlyVideo returns whether the video file is the only video file in its
+// folder.
+func (r *Reader) onlyVideo(videoPath string) (bool, error) {
+	dir := filepath.Dir(videoPath)
+	d, err := r.FS.Open(dir)
+	if err != nil {
+		return false, fmt.Errorf("opening %s: %w", dir, err)
+	}
+	defer d.Close()
+
+	entries, err := d.ReadDir(-1)
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	basename := filepath.Base(videoPath)
+	for _, e := range entries {
+		if !e.IsDir() && e.Name() != basename && fsutil.MatchExtension(e.Name(), r.VideoExtensions) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ScenePartial returns the changes to the scene from the NFO file, and
+// whether there are any changes.
+func (r *Reader) ScenePartial(ctx context.Context, s *models.Scene, m *Movie) (models.ScenePartial, bool, error) {
+	ret := models.NewScenePartial()
+	changed := false
+	overwrite := r.Precedence == models.NfoPrecedenceNfo
+
+	setString := func(existing string, v string) models.OptionalString {
+		v = strings.TrimSpace(v)
+		if v == "" || v == existing || (!overwrite && existing != "") {
+			return models.OptionalString{}
+		}
+
+		changed = true
+		return models.NewOptionalString(v)
+	}
+
+	ret.Title = setString(s.Title, m.Title)
+	ret.Details = setString(s.Details, m.Plot)
+
+	if date := m.date(); date != nil && (overwrite || s.Date == nil) {
+		if s.Date == nil || !s.Date.Equal(date.Time) {
+			ret.Date = models.NewOptionalDate(*date)
+			changed = true
+		}
+	}
+
+	if name := strings.TrimSpace(m.Studio); name != "" && (overwrite || s.StudioID == nil) {
+		id, err := r.studioID(ctx, name)
+		if err != nil {
+			return ret, false, fmt.Errorf("finding studio: %w", err)
+		}
+
+		if s.StudioID == nil || *s.StudioID != id {
+			ret.StudioID = models.NewOptionalInt(id)
+			changed = true
+		}
+	}
+
+	if names := m.TagNames(); len(names) > 0 {
+		if err := s.LoadTagIDs(ctx, r.SceneUpdater); err != nil {
+			return ret, false, err
+		}
+
+		ids, err := r.tagIDs(ctx, names)
+		if err != nil {
+			return ret, false, fmt.Errorf("finding tags: %w", err)
+		}
+
+		ret.TagIDs = updateIDs(s.TagIDs.List(), ids, overwrite)
+		changed = changed || ret.TagIDs != nil
+	}
+
+	if names := m.ActorNames(); len(names) > 0 {
+		if err := s.LoadPerformerIDs(ctx, r.SceneUpdater); err != nil {
+			return ret, false, err
+		}
+
+		ids, err := r.performerIDs(ctx, names)
+		if err != nil {
+			return ret, false, fmt.Errorf("finding performers: %w", err)
+		}
+
+		ret.PerformerIDs = updateIDs(s.PerformerIDs.List(), ids, overwrite)
+		changed = changed || ret.PerformerIDs != nil
+	}
+
+	return ret, changed, nil
+}
+
+// date returns the premiere date of the movie, or the year if there is no
+// premiere date.
+func (m *Movie) date() *models.Date {
+	v := strings.TrimSpace(m.Premiered)
+	if v == "" && m.Year > 0 {
+		v = strconv.Itoa(m.Year)
+	}
+
+	if v == "" {
+		return nil
+	}
+
+	ret, err := models.ParseDate(v)
+	if err != nil {
+		logger.Warnf("Invalid date in NFO file %q: %v", v, err)
+		return nil
+	}
+
+	return &ret
+}
+
+// updateIDs returns the update to change the existing IDs to the new IDs,
+// or nil if no update is required. If overwrite is false, the new IDs are
+// added to the existing IDs.
+func updateIDs(existing []int, ids []int, overwrite bool) *models.UpdateIDs {
+	added := sliceutil.Filter(ids, func(id int) bool {
+		return !slices.Contains(existing, id)
+	})
+
+	if !overwrite {
+		if len(added) == 0 {
+			return nil
+		}
+
+		return &models.UpdateIDs{
+			IDs:  added,
+			Mode: models.RelationshipUpdateModeAdd,
+		}
+	}
+
+	if sliceutil.SliceSame(existing, ids) {
+		return nil
+	}
+
+	return &models.UpdateIDs{
+		IDs:  ids,
+		Mode: models.RelationshipUpdateModeSet,
+	}
+}
+
+func (r *Reader) studioID(ctx context.Context, name string) (int, error) {
+	studio, err := r.StudioWriter.FindByName(ctx, name, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if studio != nil {
+		return studio.ID, nil
+	}
+
+	newStudio := models.NewStudio()
+	newStudio.Name = name
+
+	if err := r.StudioWriter.Create(ctx, &newStudio); err != nil {
+		return 0, fmt.Errorf("creating studio %q: %w", name, err)
+	}
+
+	logger.Infof("Created studio %q from NFO file", name)
+	return newStudio.ID, nil
+}
+
+// missingNames returns the names that are not in found, ignoring case.
+func missingNames(names []string, found []string) []string {
+	return sliceutil.Filter(names, func(name string) bool {
+		return !slices.ContainsFunc(found, func(v string) bool {
+			return strings.EqualFold(v, name)
+		})
+	})
+}
+
+func (r *Reader) tagIDs(ctx context.Context, names []string) ([]int, error) {
+	tags, err := r.TagWriter.FindByNames(ctx, names, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	var found []string
+	for _, t := range tags {
+		ret = append(ret, t.ID)
+		found = append(found, t.Name)
+	}
+
+	for _, name := range missingNames(names, found) {
+		newTag := models.NewTag()
+		newTag.Name = name
+
+		if err := r.TagWriter.Create(ctx, &newTag); err != nil {
+			return nil, fmt.Errorf("creating tag %q: %w", name, err)
+		}
+
+		logger.Infof("Created tag %q from NFO file", name)
+		ret = append(ret, newTag.ID)
+	}
+
+	return sliceutil.Unique(ret), nil
+}
+
+func (r *Reader) performerIDs(ctx context.Context, names []string) ([]int, error) {
+	performers, err := r.PerformerWriter.FindByNames(ctx, names, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []int
+	var found []string
+	for _, p := range performers {
+		ret = append(ret, p.ID)
+		found = append(found, p.Name)
+	}
+
+	for _, name := range missingNames(names, found) {
+		newPerformer := models.NewPerformer()
+		newPerformer.Name = name
+
+		if err := r.PerformerWriter.Create(ctx, &newPerformer); err != nil {
+			return nil, fmt.Errorf("creating performer %q: %w", name, err)
+		}
+
+		logger.Infof("Created performer %q from NFO file", name)
+		ret = append(ret, newPerformer.ID)
+	}
+
+	return sliceutil.Unique(ret), nil
+}
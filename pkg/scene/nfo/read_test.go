@@ -0,0 +1,224 @@
+package nfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	existingStudioID    = 101
+	newStudioID         = 102
+	existingTagID       = 201
+	newTagID            = 202
+	otherTagID          = 203
+	existingPerformerID = 301
+
+	existingStudioName    = "Existing Studio"
+	existingTagName       = "Existing Tag"
+	newTagName            = "New Tag"
+	existingPerformerName = "Existing Performer"
+)
+
+var testCtx = context.Background()
+
+func newTestReader(precedence models.NfoPrecedence) *Reader {
+	db := mocks.NewDatabase()
+
+	db.Studio.On("FindByName", testCtx, existingStudioName, true).Return(&models.Studio{
+		ID:   existingStudioID,
+		Name: existingStudioName,
+	}, nil)
+	db.Studio.On("FindByName", testCtx, mock.Anything, true).Return(nil, nil)
+	db.Studio.On("Create", testCtx, mock.AnythingOfType("*models.Studio")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Studio).ID = newStudioID
+	}).Return(nil)
+
+	db.Tag.On("FindByNames", testCtx, mock.Anything, true).Return([]*models.Tag{
+		{ID: existingTagID, Name: existingTagName},
+	}, nil)
+	db.Tag.On("Create", testCtx, mock.AnythingOfType("*models.Tag")).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Tag).ID = newTagID
+	}).Return(nil)
+
+	db.Performer.On("FindByNames", testCtx, mock.Anything, true).Return([]*models.Performer{
+		{ID: existingPerformerID, Name: existingPerformerName},
+	}, nil)
+
+	return &Reader{
+		SceneUpdater:    db.Scene,
+		StudioWriter:    db.Studio,
+		PerformerWriter: db.Performer,
+		TagWriter:       db.Tag,
+		Precedence:      precedence,
+	}
+}
+
+func testMovie() *Movie {
+	return &Movie{
+		Title:     "NFO Title",
+		Plot:      "NFO Plot",
+		Premiered: "2006-01-02",
+		Studio:    existingStudioName,
+		Tags:      []string{"existing tag", newTagName},
+		Actors:    []Actor{{Name: existingPerformerName}},
+	}
+}
+
+func TestReader_ScenePartial_Empty(t *testing.T) {
+	r := newTestReader(models.NfoPrecedenceExisting)
+	s := &models.Scene{
+		ID:           1,
+		TagIDs:       models.NewRelatedIDs([]int{}),
+		PerformerIDs: models.NewRelatedIDs([]int{}),
+	}
+
+	got, changed, err := r.ScenePartial(testCtx, s, testMovie())
+	if err != nil {
+		t.Fatalf("ScenePartial() error = %v", err)
+	}
+
+	date, _ := models.ParseDate("2006-01-02")
+
+	assert := assert.New(t)
+	assert.True(changed)
+	assert.Equal(models.NewOptionalString("NFO Title"), got.Title)
+	assert.Equal(models.NewOptionalString("NFO Plot"), got.Details)
+	assert.Equal(models.NewOptionalDate(date), got.Date)
+	assert.Equal(models.NewOptionalInt(existingStudioID), got.StudioID)
+	assert.Equal(&models.UpdateIDs{
+		IDs:  []int{existingTagID, newTagID},
+		Mode: models.RelationshipUpdateModeAdd,
+	}, got.TagIDs)
+	assert.Equal(&models.UpdateIDs{
+		IDs:  []int{existingPerformerID},
+		Mode: models.RelationshipUpdateModeAdd,
+	}, got.PerformerIDs)
+}
+
+func TestReader_ScenePartial_PrecedenceExisting(t *testing.T) {
+	r := newTestReader(models.NfoPrecedenceExisting)
+	studioID := newStudioID
+	s := &models.Scene{
+		ID:           1,
+		Title:        "Title",
+		Details:      "Details",
+		StudioID:     &studioID,
+		TagIDs:       models.NewRelatedIDs([]int{existingTagID, newTagID}),
+		PerformerIDs: models.NewRelatedIDs([]int{existingPerformerID}),
+	}
+
+	m := testMovie()
+	m.Premiered = ""
+
+	got, changed, err := r.ScenePartial(testCtx, s, m)
+	if err != nil {
+		t.Fatalf("ScenePartial() error = %v", err)
+	}
+
+	assert := assert.New(t)
+	assert.False(changed)
+	assert.False(got.Title.Set)
+	assert.False(got.Details.Set)
+	assert.False(got.StudioID.Set)
+	assert.Nil(got.TagIDs)
+	assert.Nil(got.PerformerIDs)
+}
+
+func TestReader_ScenePartial_PrecedenceNfo(t *testing.T) {
+	r := newTestReader(models.NfoPrecedenceNfo)
+	studioID := newStudioID
+	s := &models.Scene{
+		ID:           1,
+		Title:        "Title",
+		Details:      "NFO Plot",
+		StudioID:     &studioID,
+		TagIDs:       models.NewRelatedIDs([]int{existingTagID, newTagID, otherTagID}),
+		PerformerIDs: models.NewRelatedIDs([]int{existingPerformerID}),
+	}
+
+	got, changed, err := r.ScenePartial(testCtx, s, testMovie())
+	if err != nil {
+		t.Fatalf("ScenePartial() error = %v", err)
+	}
+
+	assert := assert.New(t)
+	assert.True(changed)
+	assert.Equal(models.NewOptionalString("NFO Title"), got.Title)
+	assert.False(got.Details.Set)
+	assert.Equal(models.NewOptionalInt(existingStudioID), got.StudioID)
+	assert.Equal(&models.UpdateIDs{
+		IDs:  []int{existingTagID, newTagID},
+		Mode: models.RelationshipUpdateModeSet,
+	}, got.TagIDs)
+	assert.Nil(got.PerformerIDs)
+}
+
+func TestReader_find(t *testing.T) {
+	writeNfo := func(t *testing.T, path string, title string) {
+		t.Helper()
+		data := "<movie><title>" + title + "</title></movie>"
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		files    []string
+		videoNfo bool
+		movieNfo bool
+		// empty if no NFO file should be found
+		wantTitle string
+	}{
+		{"video nfo", []string{"video.mp4"}, true, true, "video"},
+		{"only video", []string{"video.mp4", "video.jpg"}, false, true, "movie"},
+		{"other video", []string{"video.mp4", "other.mkv"}, false, true, ""},
+		{"no nfo", []string{"video.mp4"}, false, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			videoPath := filepath.Join(dir, "video.mp4")
+			if tt.videoNfo {
+				writeNfo(t, Path(videoPath), "video")
+			}
+			if tt.movieNfo {
+				writeNfo(t, filepath.Join(dir, movieFilename), "movie")
+			}
+
+			r := &Reader{
+				FS:              &file.OsFS{},
+				VideoExtensions: []string{"mp4", "mkv"},
+			}
+
+			m, _, err := r.find(videoPath)
+			if err != nil {
+				t.Fatalf("find() error = %v", err)
+			}
+
+			if tt.wantTitle == "" {
+				assert.Nil(t, m)
+				return
+			}
+
+			if assert.NotNil(t, m) {
+				assert.Equal(t, tt.wantTitle, m.Title)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package nfo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const dateAddedFormat = "2006-01-02 15:04:05"
+
+type SceneReader interface {
+	GetCover(ctx context.Context, sceneID int) ([]byte, error)
+}
+
+type PerformerFinder interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*models.Performer, error)
+}
+
+type TagFinder interface {
+	FindBySceneID(ctx context.Context, sceneID int) ([]*models.Tag, error)
+}
+
+// Writer generates the NFO files of scenes, along with poster and fanart
+// images from the scene cover.
+type Writer struct {
+	FileGetter      models.FileGetter
+	SceneReader     SceneReader
+	StudioReader    models.StudioGetter
+	PerformerReader PerformerFinder
+	TagReader       TagFinder
+}
+
+// File is a file to be written next to a video file.
+type File struct {
+	Path string
+	Data []byte
+}
+
+// ToMovie converts the scene to the NFO movie element.
+func (w *Writer) ToMovie(ctx context.Context, s *models.Scene) (*Movie, error) {
+	ret := &Movie{
+		Title:    s.Title,
+		Plot:     s.Details,
+		Director: s.Director,
+		UniqueIDs: []UniqueID{
+			{
+				Type:    uniqueIDType,
+				Default: true,
+				Value:   strconv.Itoa(s.ID),
+			},
+		},
+		DateAdded: s.CreatedAt.Format(dateAddedFormat),
+	}
+
+	if s.Rating != nil {
+		ret.UserRating = int(math.Round(float64(*s.Rating) / 10))
+	}
+
+	if s.Date != nil {
+		ret.Premiered = s.Date.String()
+		ret.Year = s.Date.Year()
+	}
+
+	if s.StudioID != nil {
+		studio, err := w.StudioReader.Find(ctx, *s.StudioID)
+		if err != nil {
+			return nil, fmt.Errorf("getting studio: %w", err)
+		}
+
+		if studio != nil {
+			ret.Studio = studio.Name
+		}
+	}
+
+	tags, err := w.TagReader.FindBySceneID(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting tags: %w", err)
+	}
+
+	for _, t := range tags {
+		ret.Tags = append(ret.Tags, t.Name)
+	}
+
+	performers, err := w.PerformerReader.FindBySceneID(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting performers: %w", err)
+	}
+
+	for i, p := range performers {
+		ret.Actors = append(ret.Actors, Actor{
+			Name:  p.Name,
+			Order: i,
+		})
+	}
+
+	return ret, nil
+}
+
+// Files returns the NFO file and images to write next to the primary file of
+// the scene. It returns nil if the scene has no primary file, or if the
+// primary file is in a zip file.
+func (w *Writer) Files(ctx context.Context, s *models.Scene) ([]File, error) {
+	if err := s.LoadPrimaryFile(ctx, w.FileGetter); err != nil {
+		return nil, fmt.Errorf("getting primary file: %w", err)
+	}
+
+	f := s.Files.Primary()
+	if f == nil || f.ZipFileID != nil {
+		return nil, nil
+	}
+
+	m, err := w.ToMovie(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	cover, err := w.SceneReader.GetCover(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting cover: %w", err)
+	}
+
+	var ret []File
+	if len(cover) > 0 {
+		ext := imageExtension(cover)
+		for _, kind := range []string{ImagePoster, ImageFanart} {
+			p := ImagePath(f.Path, kind, ext)
+			ret = append(ret, File{Path: p, Data: cover})
+		}
+
+		posterName := filepath.Base(ImagePath(f.Path, ImagePoster, ext))
+		fanartName := filepath.Base(ImagePath(f.Path, ImageFanart, ext))
+		m.Thumbs = []Thumb{{Aspect: ImagePoster, Value: posterName}}
+		m.Fanart = &Fanart{Thumbs: []Thumb{{Value: fanartName}}}
+	}
+
+	data, err := Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("generating NFO: %w", err)
+	}
+
+	ret = append([]File{{Path: Path(f.Path), Data: data}}, ret...)
+	return ret, nil
+}
+
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".jpg"
+	}
+}
+
+// WriteFiles writes the files, skipping files whose contents are unchanged.
+// It returns the number of files written.
+func WriteFiles(files []File) (int, error) {
+	written := 0
+	for _, f := range files {
+		existing, err := os.ReadFile(f.Path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return written, err
+		}
+
+		if err == nil && bytes.Equal(existing, f.Data) {
+			continue
+		}
+
+		if err := os.WriteFile(f.Path, f.Data, 0644); err != nil {
+			return written, err
+		}
+
+		written++
+	}
+
+	return written, nil
+}
@@ -0,0 +1,245 @@
+package organise
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type fieldInfo struct {
+	// formattable is true if the field accepts a format
+	formattable bool
+}
+
+// sceneFields are the fields that can be used in templates.
+var sceneFields = map[string]fieldInfo{
+	"id":            {},
+	"title":         {},
+	"code":          {},
+	"director":      {},
+	"date":          {formattable: true},
+	"rating":        {},
+	"studio":        {},
+	"studio.parent": {},
+	"performers":    {},
+	"tags":          {},
+	"groups":        {},
+	"basename":      {},
+	"ext":           {},
+	"width":         {},
+	"height":        {},
+	"resolution":    {},
+	"video_codec":   {},
+	"audio_codec":   {},
+	"oshash":        {},
+	"md5":           {},
+	"phash":         {},
+}
+
+// FieldNames returns the names of the fields that can be used in templates.
+func FieldNames() []string {
+	ret := make([]string, 0, len(sceneFields))
+	for k := range sceneFields {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// SceneFields provides the template field values of a scene file.
+type SceneFields struct {
+	Scene        *models.Scene
+	File         *models.VideoFile
+	Studio       *models.Studio
+	ParentStudio *models.Studio
+	Performers   []*models.Performer
+	Tags         []*models.Tag
+	Groups       []*models.Group
+}
+
+// defaultDateFormat is the format of dates if no format is provided.
+const defaultDateFormat = "2006-01-02"
+
+func stringValue(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+func (f *SceneFields) Field(name string, format string) []string {
+	s := f.Scene
+	file := f.File
+
+	switch name {
+	case "id":
+		return []string{strconv.Itoa(s.ID)}
+	case "title":
+		if s.Title != "" {
+			return []string{s.Title}
+		}
+		// use the original file name if the scene has no title
+		return f.Field("basename", "")
+	case "code":
+		return stringValue(s.Code)
+	case "director":
+		return stringValue(s.Director)
+	case "date":
+		if s.Date == nil {
+			return nil
+		}
+		if format == "" {
+			format = defaultDateFormat
+		}
+		return []string{s.Date.Format(format)}
+	case "rating":
+		if s.Rating == nil {
+			return nil
+		}
+		return []string{strconv.Itoa(*s.Rating)}
+	case "studio":
+		if f.Studio == nil {
+			return nil
+		}
+		return []string{f.Studio.Name}
+	case "studio.parent":
+		if f.ParentStudio == nil {
+			return nil
+		}
+		return []string{f.ParentStudio.Name}
+	case "performers":
+		var ret []string
+		for _, p := range f.Performers {
+			ret = append(ret, p.Name)
+		}
+		return ret
+	case "tags":
+		var ret []string
+		for _, t := range f.Tags {
+			ret = append(ret, t.Name)
+		}
+		return ret
+	case "groups":
+		var ret []string
+		for _, g := range f.Groups {
+			ret = append(ret, g.Name)
+		}
+		return ret
+	case "basename":
+		return stringValue(strings.TrimSuffix(file.Basename, filepath.Ext(file.Basename)))
+	case "ext":
+		return stringValue(strings.TrimPrefix(filepath.Ext(file.Basename), "."))
+	case "width":
+		return []string{strconv.Itoa(file.Width)}
+	case "height":
+		return []string{strconv.Itoa(file.Height)}
+	case "resolution":
+		if file.Height == 0 {
+			return nil
+		}
+		return []string{fmt.Sprintf("%dp", min(file.Width, file.Height))}
+	case "video_codec":
+		return stringValue(file.VideoCodec)
+	case "audio_codec":
+		return stringValue(file.AudioCodec)
+	case "oshash":
+		return stringValue(file.Fingerprints.GetString(models.FingerprintTypeOshash))
+	case "md5":
+		return stringValue(file.Fingerprints.GetString(models.FingerprintTypeMD5))
+	case "phash":
+		phash := file.Fingerprints.GetInt64(models.FingerprintTypePhash)
+		if phash == 0 {
+			return nil
+		}
+		return []string{strconv.FormatUint(uint64(phash), 16)}
+	}
+
+	return nil
+}
+
+// FieldsRepository provides the methods needed to load the template fields
+// of a scene.
+type FieldsRepository struct {
+	Scene     models.SceneReader
+	File      models.FileFinder
+	Studio    models.StudioGetter
+	Performer models.PerformerGetter
+	Tag       models.TagGetter
+	Group     models.GroupGetter
+}
+
+// LoadSceneFields loads the template fields of the primary file of a scene.
+// It returns nil if the scene has no files.
+func LoadSceneFields(ctx context.Context, r FieldsRepository, s *models.Scene) (*SceneFields, error) {
+	if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+		return nil, fmt.Errorf("loading primary file: %w", err)
+	}
+
+	file := s.Files.Primary()
+	if file == nil {
+		return nil, nil
+	}
+
+	if err := s.LoadPerformerIDs(ctx, r.Scene); err != nil {
+		return nil, fmt.Errorf("loading performers: %w", err)
+	}
+	if err := s.LoadTagIDs(ctx, r.Scene); err != nil {
+		return nil, fmt.Errorf("loading tags: %w", err)
+	}
+	if err := s.LoadGroups(ctx, r.Scene); err != nil {
+		return nil, fmt.Errorf("loading groups: %w", err)
+	}
+
+	ret := &SceneFields{
+		Scene: s,
+		File:  file,
+	}
+
+	if s.StudioID != nil {
+		studio, err := r.Studio.Find(ctx, *s.StudioID)
+		if err != nil {
+			return nil, fmt.Errorf("finding studio: %w", err)
+		}
+		ret.Studio = studio
+
+		if studio != nil && studio.ParentID != nil {
+			ret.ParentStudio, err = r.Studio.Find(ctx, *studio.ParentID)
+			if err != nil {
+				return nil, fmt.Errorf("finding parent studio: %w", err)
+			}
+		}
+	}
+
+	var err error
+	ret.Performers, err = r.Performer.FindMany(ctx, s.PerformerIDs.List())
+	if err != nil {
+		return nil, fmt.Errorf("finding performers: %w", err)
+	}
+	sort.Slice(ret.Performers, func(i, j int) bool {
+		return ret.Performers[i].Name < ret.Performers[j].Name
+	})
+
+	ret.Tags, err = r.Tag.FindMany(ctx, s.TagIDs.List())
+	if err != nil {
+		return nil, fmt.Errorf("finding tags: %w", err)
+	}
+	sort.Slice(ret.Tags, func(i, j int) bool {
+		return ret.Tags[i].Name < ret.Tags[j].Name
+	})
+
+	groupIDs := make([]int, len(s.Groups.List()))
+	for i, g := range s.Groups.List() {
+		groupIDs[i] = g.GroupID
+	}
+	ret.Groups, err = r.Group.FindMany(ctx, groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("finding groups: %w", err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,263 @@
+package organise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/nfo"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// maxCollisions is the maximum number of suffixes tried when the generated
+// path already exists.
+const maxCollisions = 1000
+
+// Move is a planned move of the primary file of a scene.
+type Move struct {
+	SceneID int
+	FileID  models.FileID
+	OldPath string
+	NewPath string
+}
+
+// Planner generates the paths that scene files should be moved to.
+type Planner struct {
+	Repository FieldsRepository
+	// FS is used to check whether generated paths already exist.
+	FS       models.FS
+	Template *Template
+	// Destination is the folder that generated paths are relative to. If
+	// empty, paths are relative to the stash path containing the file.
+	Destination string
+	// StashPaths are the library paths. Files are only moved within them.
+	StashPaths []string
+	// Windows sanitises paths for Windows, and compares paths case
+	// insensitively.
+	Windows bool
+
+	planned map[string]bool
+}
+
+// Plan returns the planned move of the primary file of a scene. It returns
+// nil if the scene has no file, the file is in a zip file, or the file is
+// already at the generated path. Paths of previously planned moves are
+// treated as existing, so that moves planned by the same Planner do not
+// collide.
+func (p *Planner) Plan(ctx context.Context, s *models.Scene) (*Move, error) {
+	fields, err := LoadSceneFields(ctx, p.Repository, s)
+	if err != nil {
+		return nil, err
+	}
+
+	if fields == nil || fields.File.ZipFileID != nil {
+		return nil, nil
+	}
+
+	f := fields.File
+
+	base := p.Destination
+	if base == "" {
+		base = p.stashPath(f.Path)
+		if base == "" {
+			return nil, fmt.Errorf("%s is not in a stash path", f.Path)
+		}
+	}
+
+	rel, err := p.Template.Execute(fields, p.Windows)
+	if err != nil {
+		return nil, err
+	}
+
+	// the file type must not change
+	ext := filepath.Ext(f.Basename)
+	if !strings.EqualFold(filepath.Ext(rel), ext) {
+		rel += ext
+	}
+
+	newPath := filepath.Join(base, rel)
+	if p.stashPath(newPath) == "" {
+		return nil, fmt.Errorf("%s is not in a stash path", newPath)
+	}
+
+	newPath, err = p.resolveCollision(ctx, f.Path, newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if newPath == "" {
+		// already at the generated path
+		return nil, nil
+	}
+
+	if p.planned == nil {
+		p.planned = make(map[string]bool)
+	}
+	p.planned[p.pathKey(newPath)] = true
+
+	return &Move{
+		SceneID: s.ID,
+		FileID:  f.ID,
+		OldPath: f.Path,
+		NewPath: newPath,
+	}, nil
+}
+
+func (p *Planner) stashPath(path string) string {
+	for _, sp := range p.StashPaths {
+		if fsutil.IsPathInDir(sp, filepath.Dir(path)) {
+			return sp
+		}
+	}
+	return ""
+}
+
+func (p *Planner) pathKey(path string) string {
+	if p.Windows {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// resolveCollision returns the first path, out of newPath and newPath with
+// a numbered suffix, that does not already exist. It returns an empty string
+// if the file at oldPath is already at one of the candidate paths.
+func (p *Planner) resolveCollision(ctx context.Context, oldPath, newPath string) (string, error) {
+	ext := filepath.Ext(newPath)
+	prefix := strings.TrimSuffix(newPath, ext)
+
+	for i := 1; i <= maxCollisions; i++ {
+		candidate := newPath
+		if i > 1 {
+			candidate = fmt.Sprintf("%s (%d)%s", prefix, i, ext)
+		}
+
+		if p.pathKey(candidate) == p.pathKey(oldPath) {
+			return "", nil
+		}
+
+		exists, err := p.exists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("too many files named %s", newPath)
+}
+
+func (p *Planner) exists(ctx context.Context, path string) (bool, error) {
+	if p.planned[p.pathKey(path)] {
+		return true, nil
+	}
+
+	if _, err := p.FS.Lstat(path); err == nil {
+		return true, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("getting info for %s: %w", path, err)
+	}
+
+	// the file may be missing from the file system but not yet cleaned
+	f, err := p.Repository.File.FindByPath(ctx, path)
+	if err != nil {
+		return false, fmt.Errorf("finding file %s: %w", path, err)
+	}
+
+	return f != nil, nil
+}
+
+// Executor moves scene files to their planned paths.
+type Executor struct {
+	TxnManager txn.Manager
+	File       models.FileReaderWriter
+	Folder     models.FolderReaderWriter
+}
+
+// Execute moves a file to its planned path, along with its funscript,
+// captions and NFO files. The database and the file system are updated in a single
+// transaction, and the moves are undone if any part fails.
+func (e *Executor) Execute(ctx context.Context, m Move) error {
+	return txn.WithTxn(ctx, e.TxnManager, func(ctx context.Context) error {
+		files, err := e.File.Find(ctx, m.FileID)
+		if err != nil {
+			return fmt.Errorf("finding file %d: %w", m.FileID, err)
+		}
+
+		if len(files) == 0 {
+			return fmt.Errorf("file with id %d not found", m.FileID)
+		}
+
+		f := files[0]
+		if f.Base().Path != m.OldPath {
+			return fmt.Errorf("file %s has moved to %s since planning", m.OldPath, f.Base().Path)
+		}
+
+		mover := file.NewMover(e.File, e.Folder)
+		mover.RegisterHooks(ctx)
+
+		folderPath := filepath.Dir(m.NewPath)
+		if err := mover.CreateFolderHierarchy(folderPath); err != nil {
+			return fmt.Errorf("creating folder hierarchy %s in filesystem: %w", folderPath, err)
+		}
+
+		folder, err := file.GetOrCreateFolderHierarchy(ctx, e.Folder, folderPath)
+		if err != nil {
+			return fmt.Errorf("getting or creating folder hierarchy: %w", err)
+		}
+
+		if err := mover.Move(ctx, f, folder, filepath.Base(m.NewPath)); err != nil {
+			return err
+		}
+
+		if err := mover.MoveSidecar(video.GetFunscriptPath(m.OldPath), video.GetFunscriptPath(m.NewPath)); err != nil {
+			return fmt.Errorf("moving funscript: %w", err)
+		}
+
+		newSidecars := nfo.SidecarPaths(m.NewPath)
+		for i, p := range nfo.SidecarPaths(m.OldPath) {
+			if err := mover.MoveSidecar(p, newSidecars[i]); err != nil {
+				return fmt.Errorf("moving %s: %w", filepath.Base(p), err)
+			}
+		}
+
+		return e.moveCaptions(ctx, mover, m)
+	})
+}
+
+func (e *Executor) moveCaptions(ctx context.Context, mover *file.Mover, m Move) error {
+	captions, err := e.File.GetCaptions(ctx, m.FileID)
+	if err != nil {
+		return fmt.Errorf("getting captions: %w", err)
+	}
+
+	if len(captions) == 0 {
+		return nil
+	}
+
+	for _, c := range captions {
+		oldPath := c.Path(m.OldPath)
+		newPath := video.GetCaptionPath(m.NewPath, c.LanguageCode, c.CaptionType)
+
+		if err := mover.MoveSidecar(oldPath, newPath); err != nil {
+			return fmt.Errorf("moving caption %s: %w", c.Filename, err)
+		}
+
+		c.Filename = filepath.Base(newPath)
+	}
+
+	if err := e.File.UpdateCaptions(ctx, m.FileID, captions); err != nil {
+		return fmt.Errorf("updating captions: %w", err)
+	}
+
+	return nil
+}
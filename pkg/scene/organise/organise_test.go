@@ -0,0 +1,235 @@
+package organise
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testCtx = context.Background()
+
+func writeTestFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func makeTestScene(id int, path string, title string) *models.Scene {
+	return &models.Scene{
+		ID:    id,
+		Title: title,
+		Files: models.NewRelatedVideoFiles([]*models.VideoFile{
+			{
+				BaseFile: &models.BaseFile{
+					ID:       models.FileID(id),
+					Path:     path,
+					Basename: filepath.Base(path),
+				},
+			},
+		}),
+		PerformerIDs: models.NewRelatedIDs([]int{}),
+		TagIDs:       models.NewRelatedIDs([]int{}),
+		Groups:       models.NewRelatedGroups([]models.GroupsScenes{}),
+	}
+}
+
+func TestPlanner_Plan(t *testing.T) {
+	stash := t.TempDir()
+	oldPath := filepath.Join(stash, "in", "video.mp4")
+	writeTestFile(t, oldPath)
+	writeTestFile(t, filepath.Join(stash, "Existing.mp4"))
+
+	// missing from the file system, but not yet cleaned from the database
+	missingPath := filepath.Join(stash, "Missing.mp4")
+
+	tests := []struct {
+		name    string
+		title   string
+		path    string
+		zip     bool
+		dest    string
+		planned []string
+		// empty if no move is planned
+		want    string
+		wantErr bool
+	}{
+		{"move", "New", oldPath, false, "", nil, filepath.Join(stash, "New.mp4"), false},
+		{"already moved", "video", oldPath, false, filepath.Join(stash, "in"), nil, "", false},
+		{"zip file", "New", oldPath, true, "", nil, "", false},
+		{"existing file", "Existing", oldPath, false, "", nil, filepath.Join(stash, "Existing (2).mp4"), false},
+		{"file in database", "Missing", oldPath, false, "", nil, filepath.Join(stash, "Missing (2).mp4"), false},
+		{"planned move", "New", oldPath, false, "", []string{"New", "New"}, filepath.Join(stash, "New (3).mp4"), false},
+		{"outside stash path", "New", oldPath, false, filepath.Dir(stash), nil, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := mocks.NewDatabase()
+			db.File.On("FindByPath", testCtx, missingPath).Return(&models.VideoFile{}, nil)
+			db.File.On("FindByPath", testCtx, mock.Anything).Return(nil, nil)
+			db.Performer.On("FindMany", testCtx, mock.Anything).Return(nil, nil)
+			db.Tag.On("FindMany", testCtx, mock.Anything).Return(nil, nil)
+			db.Group.On("FindMany", testCtx, mock.Anything).Return(nil, nil)
+
+			template, err := ParseTemplate("{title}.{ext}")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := &Planner{
+				Repository: FieldsRepository{
+					Scene:     db.Scene,
+					File:      db.File,
+					Studio:    db.Studio,
+					Performer: db.Performer,
+					Tag:       db.Tag,
+					Group:     db.Group,
+				},
+				FS:          &file.OsFS{},
+				Template:    template,
+				Destination: tt.dest,
+				StashPaths:  []string{stash},
+			}
+
+			for i, title := range tt.planned {
+				other := makeTestScene(100+i, filepath.Join(stash, "other", title+".mp4"), title)
+				if _, err := p.Plan(testCtx, other); err != nil {
+					t.Fatalf("planning %s: %v", title, err)
+				}
+			}
+
+			s := makeTestScene(1, tt.path, tt.title)
+			if tt.zip {
+				zipFileID := models.FileID(10)
+				s.Files.Primary().ZipFileID = &zipFileID
+			}
+
+			got, err := p.Plan(testCtx, s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Plan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.Equal(t, &Move{
+				SceneID: 1,
+				FileID:  1,
+				OldPath: tt.path,
+				NewPath: tt.want,
+			}, got)
+		})
+	}
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	tests := []struct {
+		name string
+		// conflict is the basename of a file that already exists in the new
+		// folder
+		conflict string
+		wantErr  bool
+	}{
+		{"moves sidecars", "", false},
+		{"sidecar conflict", "new.nfo", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stash := t.TempDir()
+			oldPath := filepath.Join(stash, "in", "video.mp4")
+			newPath := filepath.Join(stash, "out", "new.mp4")
+
+			oldSidecars := []string{
+				oldPath,
+				filepath.Join(stash, "in", "video.funscript"),
+				filepath.Join(stash, "in", "video.nfo"),
+				filepath.Join(stash, "in", "video-poster.jpg"),
+			}
+			newSidecars := []string{
+				newPath,
+				filepath.Join(stash, "out", "new.funscript"),
+				filepath.Join(stash, "out", "new.nfo"),
+				filepath.Join(stash, "out", "new-poster.jpg"),
+			}
+
+			for _, p := range oldSidecars {
+				writeTestFile(t, p)
+			}
+
+			if tt.conflict != "" {
+				writeTestFile(t, filepath.Join(stash, "out", tt.conflict))
+			}
+
+			f := &models.VideoFile{
+				BaseFile: &models.BaseFile{
+					ID:       1,
+					Path:     oldPath,
+					Basename: filepath.Base(oldPath),
+				},
+			}
+
+			db := mocks.NewDatabase()
+			db.File.On("Find", mock.Anything, models.FileID(1)).Return([]models.File{f}, nil)
+			db.File.On("FindByZipFileID", mock.Anything, models.FileID(1)).Return(nil, nil)
+			db.File.On("Update", mock.Anything, f).Return(nil)
+			db.File.On("GetCaptions", mock.Anything, models.FileID(1)).Return(nil, nil).Maybe()
+			db.Folder.On("FindByZipFileID", mock.Anything, models.FileID(1)).Return(nil, nil)
+			db.Folder.On("FindByPath", mock.Anything, filepath.Join(stash, "out")).Return(&models.Folder{
+				ID:   2,
+				Path: filepath.Join(stash, "out"),
+			}, nil)
+
+			e := &Executor{
+				TxnManager: db,
+				File:       db.File,
+				Folder:     db.Folder,
+			}
+
+			err := e.Execute(testCtx, Move{
+				SceneID: 1,
+				FileID:  1,
+				OldPath: oldPath,
+				NewPath: newPath,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// the moves are undone on error
+			moved := !tt.wantErr
+			for i, p := range oldSidecars {
+				if exists(p) == moved {
+					t.Errorf("%s exists = %v, want %v", p, !moved, moved)
+				}
+
+				if filepath.Base(newSidecars[i]) == tt.conflict {
+					continue
+				}
+				if exists(newSidecars[i]) != moved {
+					t.Errorf("%s exists = %v, want %v", newSidecars[i], !moved, moved)
+				}
+			}
+
+			db.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,326 @@
+// Package organise moves scene files to paths generated from their metadata.
+package organise
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSegmentLength is the maximum length in bytes of a file or folder name.
+const maxSegmentLength = 255
+
+var (
+	ErrEmptyTemplate = errors.New("template is empty")
+	ErrEmptyPath     = errors.New("template produced an empty file name")
+)
+
+// Fields provides the values of template fields.
+type Fields interface {
+	// Field returns the values of the named field, formatted using format
+	// if it is not empty. Fields with multiple values, such as performers,
+	// return one value per item. A field without a value returns nil.
+	Field(name string, format string) []string
+}
+
+// Template is a path template used to generate the path of a file. Fields
+// are enclosed in braces, and may include a format and filters, for example
+// {date:2006} or {performers|join:, }. Literal braces are written as {{ and
+// }}. Path segments are separated by forward slashes.
+type Template struct {
+	parts []templatePart
+}
+
+type templatePart struct {
+	literal string
+	field   *templateField
+}
+
+type templateField struct {
+	name    string
+	format  string
+	filters []templateFilter
+}
+
+type templateFilter struct {
+	name string
+	arg  string
+}
+
+type filterFunc func(values []string, arg string) []string
+
+var filterFuncs = map[string]filterFunc{
+	"join": func(values []string, arg string) []string {
+		if len(values) == 0 {
+			return nil
+		}
+		return []string{strings.Join(values, arg)}
+	},
+	"first": func(values []string, arg string) []string {
+		if len(values) == 0 {
+			return nil
+		}
+		return values[:1]
+	},
+	"lower": func(values []string, arg string) []string {
+		return mapValues(values, strings.ToLower)
+	},
+	"upper": func(values []string, arg string) []string {
+		return mapValues(values, strings.ToUpper)
+	},
+	"default": func(values []string, arg string) []string {
+		if len(values) == 0 {
+			return []string{arg}
+		}
+		return values
+	},
+}
+
+func mapValues(values []string, fn func(string) string) []string {
+	ret := make([]string, len(values))
+	for i, v := range values {
+		ret[i] = fn(v)
+	}
+	return ret
+}
+
+// ParseTemplate parses a path template. It returns an error if the template
+// refers to unknown fields or filters.
+func ParseTemplate(s string) (*Template, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, ErrEmptyTemplate
+	}
+
+	ret := &Template{}
+	var literal strings.Builder
+
+	addLiteral := func() {
+		if literal.Len() > 0 {
+			ret.parts = append(ret.parts, templatePart{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], "{{"), strings.HasPrefix(s[i:], "}}"):
+			literal.WriteByte(c)
+			i++
+		case c == '}':
+			return nil, fmt.Errorf("unexpected } at position %d", i)
+		case c == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return nil, fmt.Errorf("unclosed { at position %d", i)
+			}
+
+			f, err := parseField(s[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+
+			addLiteral()
+			ret.parts = append(ret.parts, templatePart{field: f})
+			i += end
+		default:
+			literal.WriteByte(c)
+		}
+	}
+
+	addLiteral()
+
+	return ret, nil
+}
+
+func parseField(s string) (*templateField, error) {
+	parts := strings.Split(s, "|")
+
+	name, format, _ := strings.Cut(parts[0], ":")
+	name = strings.TrimSpace(name)
+
+	field, found := sceneFields[name]
+	if !found {
+		return nil, fmt.Errorf("unknown field %q", name)
+	}
+	if format != "" && !field.formattable {
+		return nil, fmt.Errorf("field %q does not support a format", name)
+	}
+
+	ret := &templateField{
+		name:   name,
+		format: format,
+	}
+
+	for _, p := range parts[1:] {
+		// the argument is not trimmed, since it may be a separator
+		filterName, arg, _ := strings.Cut(p, ":")
+		filterName = strings.TrimSpace(filterName)
+
+		if _, found := filterFuncs[filterName]; !found {
+			return nil, fmt.Errorf("unknown filter %q for field %q", filterName, name)
+		}
+
+		ret.filters = append(ret.filters, templateFilter{
+			name: filterName,
+			arg:  arg,
+		})
+	}
+
+	return ret, nil
+}
+
+func (f *templateField) execute(fields Fields) string {
+	values := fields.Field(f.name, f.format)
+
+	for _, filter := range f.filters {
+		values = filterFuncs[filter.name](values, filter.arg)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// Execute returns the relative path generated from the template, using the
+// provided field values. Each segment of the path is sanitised so that it is
+// a valid file name on the current operating system, or on Windows if
+// windows is true. Segments that are empty after removing empty brackets
+// are omitted.
+func (t *Template) Execute(fields Fields, windows bool) (string, error) {
+	var segments []string
+	var current strings.Builder
+
+	for _, p := range t.parts {
+		if p.field != nil {
+			// values cannot create path segments
+			v := p.field.execute(fields)
+			v = strings.ReplaceAll(v, "/", "-")
+			v = strings.ReplaceAll(v, `\`, "-")
+			current.WriteString(v)
+			continue
+		}
+
+		literal := p.literal
+		if windows {
+			literal = strings.ReplaceAll(literal, `\`, "/")
+		}
+
+		for {
+			before, after, found := strings.Cut(literal, "/")
+			current.WriteString(before)
+			if !found {
+				break
+			}
+
+			segments = append(segments, current.String())
+			current.Reset()
+			literal = after
+		}
+	}
+	segments = append(segments, current.String())
+
+	var ret []string
+	for i, s := range segments {
+		isFile := i == len(segments)-1
+		s = cleanSegment(s, isFile)
+		s = sanitiseSegment(s, windows)
+		s = truncateSegment(s, isFile)
+
+		if s == "" {
+			if isFile {
+				return "", ErrEmptyPath
+			}
+			continue
+		}
+
+		ret = append(ret, s)
+	}
+
+	return filepath.Join(ret...), nil
+}
+
+var (
+	emptyBracketsRE   = regexp.MustCompile(`\(\s*\)|\[\s*\]`)
+	multipleSpaceRE   = regexp.MustCompile(`\s{2,}`)
+	spaceBeforeExtRE  = regexp.MustCompile(`\s+(\.[^.\s]*)$`)
+	invalidWindowsRE  = regexp.MustCompile(`[<>:"|?*]`)
+	controlCharRE     = regexp.MustCompile(`[\x00-\x1f\x7f]`)
+	reservedWindowsRE = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[0-9]|lpt[0-9])(\..*)?$`)
+)
+
+// cleanSegment removes empty brackets and redundant whitespace left by
+// fields without a value.
+func cleanSegment(s string, isFile bool) string {
+	for {
+		cleaned := emptyBracketsRE.ReplaceAllString(s, "")
+		if cleaned == s {
+			break
+		}
+		s = cleaned
+	}
+
+	s = multipleSpaceRE.ReplaceAllString(s, " ")
+	if isFile {
+		s = spaceBeforeExtRE.ReplaceAllString(s, "$1")
+	}
+	s = strings.TrimSpace(s)
+
+	// a file name consisting of only the extension is empty
+	if isFile && strings.HasPrefix(s, ".") && !strings.Contains(s[1:], ".") {
+		return ""
+	}
+
+	return s
+}
+
+var windowsReplacer = strings.NewReplacer(
+	`"`, "'",
+	":", " -",
+)
+
+// sanitiseSegment replaces characters that are not valid in file names.
+func sanitiseSegment(s string, windows bool) string {
+	s = controlCharRE.ReplaceAllString(s, "")
+
+	if windows {
+		s = windowsReplacer.Replace(s)
+		s = invalidWindowsRE.ReplaceAllString(s, "_")
+		s = multipleSpaceRE.ReplaceAllString(s, " ")
+		// windows does not allow names ending with a dot or space
+		s = strings.TrimRight(s, ". ")
+
+		if reservedWindowsRE.MatchString(s) {
+			s = "_" + s
+		}
+	}
+
+	if s == "." || s == ".." {
+		return ""
+	}
+
+	return s
+}
+
+// truncateSegment truncates s to the maximum file name length, keeping the
+// extension of file names.
+func truncateSegment(s string, isFile bool) string {
+	if len(s) <= maxSegmentLength {
+		return s
+	}
+
+	ext := ""
+	if isFile {
+		ext = filepath.Ext(s)
+		s = strings.TrimSuffix(s, ext)
+	}
+
+	s = s[:maxSegmentLength-len(ext)]
+	// don't split multi-byte characters
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return strings.TrimSpace(s) + ext
+}
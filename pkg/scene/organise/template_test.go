@@ -0,0 +1,192 @@
+package organise
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testFields map[string][]string
+
+func (f testFields) Field(name string, format string) []string {
+	if format != "" {
+		return []string{name + ":" + format}
+	}
+	return f[name]
+}
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{"valid", "{studio}/{title}.{ext}", false},
+		{"format", "{date:2006}", false},
+		{"filters", "{performers|first|lower|join:, }", false},
+		{"escaped braces", "{{title}}", false},
+		{"empty", " ", true},
+		{"unknown field", "{foo}", true},
+		{"unknown filter", "{title|foo}", true},
+		{"format not supported", "{title:2006}", true},
+		{"unclosed", "{title", true},
+		{"unexpected close", "title}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTemplate(%q) error = %v, wantErr %v", tt.template, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplate_Execute(t *testing.T) {
+	fields := testFields{
+		"title":         {"My Title"},
+		"studio":        {"Studio"},
+		"studio.parent": {"Network"},
+		"performers":    {"Alice", "Bob"},
+		"ext":           {"mp4"},
+		"code":          {"AB/12"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		windows  bool
+		want     string
+		wantErr  error
+	}{
+		{
+			"full",
+			"{studio.parent}/{studio}/{date:2006}/{title} [{performers|join:, }].{ext}",
+			false,
+			"Network/Studio/date:2006/My Title [Alice, Bob].mp4",
+			nil,
+		},
+		{
+			"default separator",
+			"{performers} - {title}.{ext}",
+			false,
+			"Alice, Bob - My Title.mp4",
+			nil,
+		},
+		{
+			"first and upper",
+			"{performers|first|upper}.{ext}",
+			false,
+			"ALICE.mp4",
+			nil,
+		},
+		{
+			"empty segment omitted",
+			"{director}/{title}.{ext}",
+			false,
+			"My Title.mp4",
+			nil,
+		},
+		{
+			"empty brackets removed",
+			"{title} [{tags|join:, }] ({director}).{ext}",
+			false,
+			"My Title.mp4",
+			nil,
+		},
+		{
+			"default filter",
+			"{director|default:Unknown}/{title}.{ext}",
+			false,
+			"Unknown/My Title.mp4",
+			nil,
+		},
+		{
+			"values cannot add segments",
+			"{code}.{ext}",
+			false,
+			"AB-12.mp4",
+			nil,
+		},
+		{
+			"escaped braces",
+			"{{{title}}}.{ext}",
+			false,
+			"{My Title}.mp4",
+			nil,
+		},
+		{
+			"windows characters",
+			`{title}: "part" <1>?.{ext}`,
+			true,
+			"My Title - 'part' _1__.mp4",
+			nil,
+		},
+		{
+			"windows reserved name",
+			"con.{ext}",
+			true,
+			"_con.mp4",
+			nil,
+		},
+		{
+			"windows trailing dot",
+			"{studio}./{title}.{ext}",
+			true,
+			"Studio/My Title.mp4",
+			nil,
+		},
+		{
+			"empty file name",
+			"{title}/{director}.{ext}",
+			false,
+			"",
+			ErrEmptyPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseTemplate(%q) error = %v", tt.template, err)
+			}
+
+			got, err := tmpl.Execute(fields, tt.windows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if want := filepath.FromSlash(tt.want); got != want {
+				t.Errorf("Execute() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTemplate_ExecuteTruncates(t *testing.T) {
+	fields := testFields{
+		"title": {strings.Repeat("é", 200)},
+		"ext":   {"mp4"},
+	}
+
+	tmpl, err := ParseTemplate("{title}.{ext}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tmpl.Execute(fields, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) > maxSegmentLength {
+		t.Errorf("length = %d, want at most %d", len(got), maxSegmentLength)
+	}
+
+	if !strings.HasSuffix(got, ".mp4") {
+		t.Errorf("Execute() = %q, want .mp4 extension", got)
+	}
+}
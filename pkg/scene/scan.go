@@ -36,12 +36,21 @@ type ScanGenerator interface {
 	Generate(ctx context.Context, s *models.Scene, f *models.VideoFile) error
 }
 
+// NfoReader applies the metadata in the NFO file of a video file to its
+// scene.
+type NfoReader interface {
+	Read(ctx context.Context, s *models.Scene, f *models.VideoFile) error
+}
+
 type ScanHandler struct {
 	CreatorUpdater ScanCreatorUpdater
 
 	ScanGenerator  ScanGenerator
 	CaptionUpdater video.CaptionUpdater
 	PluginCache    *plugin.Cache
+	// NfoReader is used to read NFO files of new and changed video files.
+	// NFO files are not read if nil.
+	NfoReader NfoReader
 
 	FileNamingAlgorithm models.HashAlgorithm
 	Paths               *paths.Paths
@@ -97,7 +106,8 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		}
 	}
 
-	if len(existing) > 0 {
+	isNew := len(existing) == 0
+	if !isNew {
 		updateExisting := oldFile != nil
 		if err := h.associateExisting(ctx, existing, videoFile, updateExisting); err != nil {
 			return err
@@ -117,6 +127,17 @@ func (h *ScanHandler) Handle(ctx context.Context, f models.File, oldFile models.
 		existing = []*models.Scene{&newScene}
 	}
 
+	// only read NFO files of new and changed files, so that changes made
+	// in stash are not overwritten on every scan
+	if h.NfoReader != nil && (isNew || oldFile != nil) {
+		for _, s := range existing {
+			if err := h.NfoReader.Read(ctx, s, videoFile); err != nil {
+				// just log if reading the NFO file fails
+				logger.Errorf("Error reading NFO file for %s: %v", videoFile.Path, err)
+			}
+		}
+	}
+
 	if oldFile != nil {
 		// migrate hashes from the old file to the new
 		oldHash := GetHash(oldFile, h.FileNamingAlgorithm)
@@ -1,6 +1,11 @@
 package graphql
 
-import "github.com/99designs/gqlgen/graphql"
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Yamashou/gqlgenc/clientv2"
+)
 
 // Override for generated struct due to mistaken omitempty
 // https://github.com/Yamashou/gqlgenc/issues/77
@@ -18,3 +23,44 @@ type SceneDraftInput struct {
 	Image        *graphql.Upload     `json:"image,omitempty"`
 	Fingerprints []*FingerprintInput `json:"fingerprints"`
 }
+
+// FingerprintAlgorithmAudio is not part of the stash-box schema, and is only
+// accepted by endpoints that list it in their FingerprintAlgorithm enum.
+const FingerprintAlgorithmAudio FingerprintAlgorithm = "AUDIO"
+
+const FingerprintAlgorithmsDocument = `query FingerprintAlgorithms {
+	__type(name: "FingerprintAlgorithm") {
+		enumValues {
+			name
+		}
+	}
+}
+`
+
+type FingerprintAlgorithms struct {
+	Type *struct {
+		EnumValues []struct {
+			Name string `json:"name"`
+		} `json:"enumValues"`
+	} `json:"__type"`
+}
+
+// FingerprintAlgorithms returns the fingerprint algorithms accepted by the
+// endpoint.
+func (c *Client) FingerprintAlgorithms(ctx context.Context, interceptors ...clientv2.RequestInterceptor) ([]FingerprintAlgorithm, error) {
+	var res FingerprintAlgorithms
+	if err := c.Client.Post(ctx, "FingerprintAlgorithms", FingerprintAlgorithmsDocument, &res, nil, interceptors...); err != nil {
+		return nil, err
+	}
+
+	if res.Type == nil {
+		return nil, nil
+	}
+
+	ret := make([]FingerprintAlgorithm, len(res.Type.EnumValues))
+	for i, v := range res.Type.EnumValues {
+		ret[i] = FingerprintAlgorithm(v.Name)
+	}
+
+	return ret, nil
+}
@@ -260,6 +260,7 @@ func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []strin
 	}
 
 	endpoint := c.box.Endpoint
+	audio := c.supportsAudioFingerprints(ctx)
 
 	var fingerprints []graphql.FingerprintSubmission
 
@@ -333,6 +334,18 @@ func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []strin
 								Fingerprint: &fingerprint,
 							})
 						}
+
+						if audioFP := f.Fingerprints.GetString(models.FingerprintTypeAudio); audio && audioFP != "" {
+							fingerprint := graphql.FingerprintInput{
+								Hash:      audioFP,
+								Algorithm: graphql.FingerprintAlgorithmAudio,
+								Duration:  int(duration),
+							}
+							fingerprints = append(fingerprints, graphql.FingerprintSubmission{
+								SceneID:     sceneStashID,
+								Fingerprint: &fingerprint,
+							})
+						}
 					}
 				}
 			}
@@ -346,6 +359,24 @@ func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []strin
 	return c.submitStashBoxFingerprints(ctx, fingerprints)
 }
 
+// supportsAudioFingerprints returns true if the endpoint accepts audio
+// fingerprints.
+func (c Client) supportsAudioFingerprints(ctx context.Context) bool {
+	algorithms, err := c.client.FingerprintAlgorithms(ctx)
+	if err != nil {
+		logger.Debugf("Error getting fingerprint algorithms from %s: %v", c.box.Endpoint, err)
+		return false
+	}
+
+	for _, a := range algorithms {
+		if a == graphql.FingerprintAlgorithmAudio {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c Client) submitStashBoxFingerprints(ctx context.Context, fingerprints []graphql.FingerprintSubmission) (bool, error) {
 	for _, fingerprint := range fingerprints {
 		_, err := c.client.SubmitFingerprint(ctx, fingerprint)
@@ -1010,6 +1041,7 @@ func (c Client) SubmitSceneDraft(ctx context.Context, scene *models.Scene, cover
 	}
 
 	fingerprints := []*graphql.FingerprintInput{}
+	audio := c.supportsAudioFingerprints(ctx)
 
 	// submit all file fingerprints
 	if err := scene.LoadFiles(ctx, r.Scene); err != nil {
@@ -1046,6 +1078,15 @@ func (c Client) SubmitSceneDraft(ctx context.Context, scene *models.Scene, cover
 				}
 				fingerprints = appendFingerprintUnique(fingerprints, &fingerprint)
 			}
+
+			if audioFP := f.Fingerprints.GetString(models.FingerprintTypeAudio); audio && audioFP != "" {
+				fingerprint := graphql.FingerprintInput{
+					Hash:      audioFP,
+					Algorithm: graphql.FingerprintAlgorithmAudio,
+					Duration:  int(duration),
+				}
+				fingerprints = appendFingerprintUnique(fingerprints, &fingerprint)
+			}
 		}
 	}
 	draft.Fingerprints = fingerprints
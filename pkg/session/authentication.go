@@ -17,43 +17,55 @@ func (e ExternalAccessError) Error() string {
 
 func CheckAllowPublicWithoutAuth(c ExternalAccessConfig, r *http.Request) error {
 	if !c.HasCredentials() && !c.GetDangerousAllowPublicWithoutAuth() && !c.IsNewSystem() {
-		requestIPString, _, err := net.SplitHostPort(r.RemoteAddr)
-		if err != nil {
-			return fmt.Errorf("error parsing remote host (%s): %w", r.RemoteAddr, err)
-		}
+		return checkLocalRequest(r)
+	}
 
-		// presence of scope ID in IPv6 addresses prevents parsing. Remove if present
-		scopeIDIndex := strings.Index(requestIPString, "%")
-		if scopeIDIndex != -1 {
-			requestIPString = requestIPString[0:scopeIDIndex]
-		}
+	return nil
+}
 
-		requestIP := net.ParseIP(requestIPString)
-		if requestIP == nil {
-			return fmt.Errorf("unable to parse remote host (%s)", requestIPString)
-		}
+// IsLocalRequest returns true if the request originated from the local
+// network. Proxied requests are only considered local if the proxy and
+// every address in the X-Forwarded-For chain are local.
+func IsLocalRequest(r *http.Request) bool {
+	return checkLocalRequest(r) == nil
+}
 
-		if r.Header.Get("X-FORWARDED-FOR") != "" {
-			// Request was proxied
-			proxyChain := strings.Split(r.Header.Get("X-FORWARDED-FOR"), ", ")
-
-			// validate proxies against local network only
-			if !isLocalIP(requestIP) {
-				return ExternalAccessError(requestIP)
-			} else {
-				// Safe to validate X-Forwarded-For
-				for i := range proxyChain {
-					ip := net.ParseIP(proxyChain[i])
-					if !isLocalIP(ip) {
-						return ExternalAccessError(ip)
-					}
-				}
-			}
+func checkLocalRequest(r *http.Request) error {
+	requestIPString, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return fmt.Errorf("error parsing remote host (%s): %w", r.RemoteAddr, err)
+	}
+
+	// presence of scope ID in IPv6 addresses prevents parsing. Remove if present
+	scopeIDIndex := strings.Index(requestIPString, "%")
+	if scopeIDIndex != -1 {
+		requestIPString = requestIPString[0:scopeIDIndex]
+	}
+
+	requestIP := net.ParseIP(requestIPString)
+	if requestIP == nil {
+		return fmt.Errorf("unable to parse remote host (%s)", requestIPString)
+	}
+
+	if r.Header.Get("X-FORWARDED-FOR") != "" {
+		// Request was proxied
+		proxyChain := strings.Split(r.Header.Get("X-FORWARDED-FOR"), ", ")
 
-		} else if !isLocalIP(requestIP) { // request was not proxied
+		// validate proxies against local network only
+		if !isLocalIP(requestIP) {
 			return ExternalAccessError(requestIP)
+		} else {
+			// Safe to validate X-Forwarded-For
+			for i := range proxyChain {
+				ip := net.ParseIP(proxyChain[i])
+				if !isLocalIP(ip) {
+					return ExternalAccessError(ip)
+				}
+			}
 		}
 
+	} else if !isLocalIP(requestIP) { // request was not proxied
+		return ExternalAccessError(requestIP)
 	}
 
 	return nil
@@ -186,3 +186,33 @@ func TestCheckExternalAccessTripwire(t *testing.T) {
 		t.Errorf("unexpected error %v", err)
 	}
 }
+
+func TestIsLocalRequest(t *testing.T) {
+	testCases := []struct {
+		address string
+		xFwd    string
+		want    bool
+	}{
+		{"192.168.1.1:8080", "", true},
+		{"[::1]:8080", "", true},
+		{"193.168.1.1:8080", "", false},
+		{"192.168.1.1:8080", "10.0.0.2", true},
+		{"192.168.1.1:8080", "193.168.1.1", false},
+		{"193.168.1.1:8080", "192.168.1.1", false},
+		{"invalid", "", false},
+	}
+
+	for i, tc := range testCases {
+		header := make(http.Header)
+		header.Set("X-FORWARDED-FOR", tc.xFwd)
+
+		r := &http.Request{
+			RemoteAddr: tc.address,
+			Header:     header,
+		}
+
+		if got := IsLocalRequest(r); got != tc.want {
+			t.Errorf("[%d]: IsLocalRequest() = %v, want %v", i, got, tc.want)
+		}
+	}
+}
@@ -15,6 +15,8 @@ type key int
 const (
 	contextUser key = iota
 	contextVisitedPlugins
+	contextLocalRequest
+	contextAPIKeyRequest
 )
 
 const (
@@ -146,10 +148,35 @@ func GetCurrentUserID(ctx context.Context) *string {
 	return nil
 }
 
-func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
-	c := s.config
+// SetRequestIsLocal stores whether the current request originated from the
+// local network.
+func SetRequestIsLocal(ctx context.Context, local bool) context.Context {
+	return context.WithValue(ctx, contextLocalRequest, local)
+}
 
-	// translate api key into current user, if present
+// RequestIsLocal returns true if the current request was flagged as
+// originating from the local network. Returns false if not set.
+func RequestIsLocal(ctx context.Context) bool {
+	local, _ := ctx.Value(contextLocalRequest).(bool)
+	return local
+}
+
+// SetRequestUsesAPIKey stores whether the current request was authenticated
+// with the API key.
+func SetRequestUsesAPIKey(ctx context.Context, apiKey bool) context.Context {
+	return context.WithValue(ctx, contextAPIKeyRequest, apiKey)
+}
+
+// RequestUsesAPIKey returns true if the current request was flagged as
+// authenticated with the API key. Returns false if not set.
+func RequestUsesAPIKey(ctx context.Context) bool {
+	apiKey, _ := ctx.Value(contextAPIKeyRequest).(bool)
+	return apiKey
+}
+
+// getRequestAPIKey returns the API key provided in the request header or
+// query parameters, or an empty string if not present.
+func getRequestAPIKey(r *http.Request) string {
 	apiKey := r.Header.Get(ApiKeyHeader)
 
 	// try getting the api key as a query parameter
@@ -157,6 +184,20 @@ func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID str
 		apiKey = r.URL.Query().Get(ApiKeyParameter)
 	}
 
+	return apiKey
+}
+
+// UsesAPIKey returns true if the request provides an API key.
+func UsesAPIKey(r *http.Request) bool {
+	return getRequestAPIKey(r) != ""
+}
+
+func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
+	c := s.config
+
+	// translate api key into current user, if present
+	apiKey := getRequestAPIKey(r)
+
 	if apiKey != "" {
 		// match against configured API and set userID to the
 		// configured username. In future, we'll want to
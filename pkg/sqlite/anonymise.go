@@ -127,7 +127,7 @@ func (db *Anonymiser) anonymiseFolders(ctx context.Context) error {
 func (db *Anonymiser) anonymiseFoldersRecurse(ctx context.Context, parentFolderID int, parentPath string) error {
 	table := folderTableMgr.table
 
-	stmt := dialect.Update(table)
+	stmt := dialect(ctx).Update(table)
 
 	if parentFolderID == 0 {
 		stmt = stmt.Set(goqu.Record{"path": goqu.Cast(table.Col(idColumn), "VARCHAR")}).Where(table.Col("parent_folder_id").IsNull())
@@ -142,7 +142,7 @@ func (db *Anonymiser) anonymiseFoldersRecurse(ctx context.Context, parentFolderI
 	}
 
 	// now recurse to sub-folders
-	query := dialect.From(table).Select(table.Col(idColumn), table.Col("path"))
+	query := dialect(ctx).From(table).Select(table.Col(idColumn), table.Col("path"))
 	if parentFolderID == 0 {
 		query = query.Where(table.Col("parent_folder_id").IsNull())
 	} else {
@@ -165,7 +165,7 @@ func (db *Anonymiser) anonymiseFiles(ctx context.Context) error {
 	logger.Infof("Anonymising files")
 	return txn.WithTxn(ctx, db, func(ctx context.Context) error {
 		table := fileTableMgr.table
-		stmt := dialect.Update(table).Set(goqu.Record{"basename": goqu.Cast(table.Col(idColumn), "VARCHAR")})
+		stmt := dialect(ctx).Update(table).Set(goqu.Record{"basename": goqu.Cast(table.Col(idColumn), "VARCHAR")})
 
 		if _, err := exec(ctx, stmt); err != nil {
 			return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -179,7 +179,7 @@ func (db *Anonymiser) anonymiseCaptions(ctx context.Context) error {
 	logger.Infof("Anonymising captions")
 	return txn.WithTxn(ctx, db, func(ctx context.Context) error {
 		table := goqu.T(videoCaptionsTable)
-		stmt := dialect.Update(table).Set(goqu.Record{"filename": goqu.Cast(table.Col("file_id"), "VARCHAR")})
+		stmt := dialect(ctx).Update(table).Set(goqu.Record{"filename": goqu.Cast(table.Col("file_id"), "VARCHAR")})
 
 		if _, err := exec(ctx, stmt); err != nil {
 			return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -199,7 +199,7 @@ func (db *Anonymiser) anonymiseFingerprints(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(fileIDColumn),
 				table.Col("type"),
 				table.Col("fingerprint"),
@@ -256,7 +256,7 @@ func (db *Anonymiser) anonymiseScenes(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("title"),
 				table.Col("details"),
@@ -293,7 +293,7 @@ func (db *Anonymiser) anonymiseScenes(ctx context.Context) error {
 				db.obfuscateNullString(set, "details", details)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -343,7 +343,7 @@ func (db *Anonymiser) anonymiseMarkers(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("title"),
 			).Where(table.Col(idColumn).Gt(lastID)).Limit(1000)
@@ -395,7 +395,7 @@ func (db *Anonymiser) anonymiseImages(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("title"),
 			).Where(table.Col(idColumn).Gt(lastID)).Limit(1000)
@@ -420,7 +420,7 @@ func (db *Anonymiser) anonymiseImages(ctx context.Context) error {
 				db.obfuscateNullString(set, "title", title)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -458,7 +458,7 @@ func (db *Anonymiser) anonymiseGalleries(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("title"),
 				table.Col("details"),
@@ -491,7 +491,7 @@ func (db *Anonymiser) anonymiseGalleries(ctx context.Context) error {
 				db.obfuscateNullString(set, "photographer", photographer)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -529,7 +529,7 @@ func (db *Anonymiser) anonymisePerformers(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("name"),
 				table.Col("disambiguation"),
@@ -570,7 +570,7 @@ func (db *Anonymiser) anonymisePerformers(ctx context.Context) error {
 				db.obfuscateNullString(set, "piercings", piercings)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -612,7 +612,7 @@ func (db *Anonymiser) anonymiseStudios(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("name"),
 				table.Col("url"),
@@ -645,7 +645,7 @@ func (db *Anonymiser) anonymiseStudios(ctx context.Context) error {
 				db.obfuscateNullString(set, "details", details)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -684,7 +684,7 @@ func (db *Anonymiser) anonymiseAliases(ctx context.Context, table exp.Identifier
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("alias"),
 			).Where(goqu.L("(" + idColumn + ", alias)").Gt(goqu.L("(?, ?)", lastID, lastAlias))).Limit(1000)
@@ -709,7 +709,7 @@ func (db *Anonymiser) anonymiseAliases(ctx context.Context, table exp.Identifier
 				db.obfuscateNullString(set, "alias", alias)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(
+					stmt := dialect(ctx).Update(table).Set(set).Where(
 						table.Col(idColumn).Eq(id),
 						table.Col("alias").Eq(alias),
 					)
@@ -746,7 +746,7 @@ func (db *Anonymiser) anonymiseURLs(ctx context.Context, table exp.IdentifierExp
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("url"),
 			).Where(goqu.L("(" + idColumn + ", url)").Gt(goqu.L("(?, ?)", lastID, lastURL))).Limit(1000)
@@ -771,7 +771,7 @@ func (db *Anonymiser) anonymiseURLs(ctx context.Context, table exp.IdentifierExp
 				db.obfuscateNullString(set, "url", url)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(
+					stmt := dialect(ctx).Update(table).Set(set).Where(
 						table.Col(idColumn).Eq(id),
 						table.Col("url").Eq(url),
 					)
@@ -809,7 +809,7 @@ func (db *Anonymiser) anonymiseTags(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("name"),
 				table.Col("description"),
@@ -838,7 +838,7 @@ func (db *Anonymiser) anonymiseTags(ctx context.Context) error {
 				db.obfuscateNullString(set, "description", description)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -876,7 +876,7 @@ func (db *Anonymiser) anonymiseGroups(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("name"),
 				table.Col("aliases"),
@@ -913,7 +913,7 @@ func (db *Anonymiser) anonymiseGroups(ctx context.Context) error {
 				db.obfuscateNullString(set, "director", director)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -951,7 +951,7 @@ func (db *Anonymiser) anonymiseSavedFilters(ctx context.Context) error {
 
 	for gotSome := true; gotSome; {
 		if err := txn.WithTxn(ctx, db, func(ctx context.Context) error {
-			query := dialect.From(table).Select(
+			query := dialect(ctx).From(table).Select(
 				table.Col(idColumn),
 				table.Col("name"),
 			).Where(table.Col(idColumn).Gt(lastID)).Limit(1000)
@@ -976,7 +976,7 @@ func (db *Anonymiser) anonymiseSavedFilters(ctx context.Context) error {
 				db.obfuscateNullString(set, "name", name)
 
 				if len(set) > 0 {
-					stmt := dialect.Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
+					stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(idColumn).Eq(id))
 
 					if _, err := exec(ctx, stmt); err != nil {
 						return fmt.Errorf("anonymising %s: %w", table.GetTable(), err)
@@ -1005,7 +1005,7 @@ func (db *Anonymiser) anonymiseText(ctx context.Context, table exp.IdentifierExp
 	set := goqu.Record{}
 	set[column] = db.obfuscateString(value, letters)
 
-	stmt := dialect.Update(table).Set(set).Where(table.Col(column).Eq(value))
+	stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(column).Eq(value))
 
 	if _, err := exec(ctx, stmt); err != nil {
 		return fmt.Errorf("anonymising %s: %w", column, err)
@@ -1018,7 +1018,7 @@ func (db *Anonymiser) anonymiseFingerprint(ctx context.Context, table exp.Identi
 	set := goqu.Record{}
 	set[column] = db.obfuscateString(value, hex)
 
-	stmt := dialect.Update(table).Set(set).Where(table.Col(column).Eq(value))
+	stmt := dialect(ctx).Update(table).Set(set).Where(table.Col(column).Eq(value))
 
 	if _, err := exec(ctx, stmt); err != nil {
 		return fmt.Errorf("anonymising %s: %w", column, err)
@@ -12,6 +12,8 @@ import (
 )
 
 func TestAnonymiser_Anonymise(t *testing.T) {
+	skipIfPostgres(t)
+
 	f, err := os.CreateTemp("", "*.sqlite")
 	if err != nil {
 		t.Errorf("Could not create temporary file: %v", err)
@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// restoreSuffix is appended to the database path to give the path of a
+	// backup that replaces the database the next time it is opened.
+	restoreSuffix = ".restore"
+
+	// preRestoreSuffix is appended to the path of a database or directory
+	// that is replaced by a restore, followed by the time of the restore.
+	preRestoreSuffix     = ".pre-restore."
+	preRestoreTimeFormat = "20060102_150405"
+)
+
+// VerifyBackup runs an integrity check on the SQLite database file at path,
+// returning an error if the database is corrupt.
+func VerifyBackup(path string) error {
+	conn, err := sqlx.Open(sqlite3Driver, "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("open database %s failed: %w", path, err)
+	}
+	defer conn.Close()
+
+	var results []string
+	if err := conn.Select(&results, "PRAGMA integrity_check"); err != nil {
+		return fmt.Errorf("checking integrity of %s: %w", path, err)
+	}
+
+	if len(results) == 1 && results[0] == "ok" {
+		return nil
+	}
+
+	return fmt.Errorf("integrity check of %s failed: %s", path, strings.Join(results, "; "))
+}
+
+// StripBlobs removes the blob data stored in the SQLite database file at
+// path. The blob checksums are kept, so that references to the blobs remain
+// valid.
+func StripBlobs(path string) error {
+	conn, err := sqlx.Open(sqlite3Driver, "file:"+path)
+	if err != nil {
+		return fmt.Errorf("open database %s failed: %w", path, err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("UPDATE `" + blobTable + "` SET `blob` = NULL"); err != nil {
+		return fmt.Errorf("removing blobs from %s: %w", path, err)
+	}
+
+	if _, err := conn.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("vacuum failed: %w", err)
+	}
+
+	return nil
+}
+
+// CountMissingBlobs returns the number of blobs in the SQLite database file at
+// path that have no data, such as those removed by StripBlobs.
+func CountMissingBlobs(path string) (int, error) {
+	conn, err := sqlx.Open(sqlite3Driver, "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open database %s failed: %w", path, err)
+	}
+	defer conn.Close()
+
+	var ret int
+	if err := conn.Get(&ret, "SELECT COUNT(*) FROM `"+blobTable+"` WHERE `blob` IS NULL"); err != nil {
+		return 0, fmt.Errorf("counting blobs in %s: %w", path, err)
+	}
+
+	return ret, nil
+}
+
+// PreRestorePath returns the path that the file or directory at path is
+// moved to when it is replaced by a restore.
+func PreRestorePath(path string) string {
+	return path + preRestoreSuffix + time.Now().Format(preRestoreTimeFormat)
+}
+
+// PrunePreRestore deletes the copies of the file or directory at path that
+// were replaced by restores, except for the most recent.
+func PrunePreRestore(path string) error {
+	dir := filepath.Dir(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	prefix := filepath.Base(path) + preRestoreSuffix
+
+	// the times in the names sort chronologically. The -wal and -shm files
+	// of a replaced database have the same time as the database.
+	restoreTime := func(name string) string {
+		t := strings.TrimPrefix(name, prefix)
+		if len(t) > len(preRestoreTimeFormat) {
+			t = t[:len(preRestoreTimeFormat)]
+		}
+		return t
+	}
+
+	latest := ""
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) {
+			if t := restoreTime(e.Name()); t > latest {
+				latest = t
+			}
+		}
+	}
+
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), prefix) || restoreTime(e.Name()) == latest {
+			continue
+		}
+
+		p := filepath.Join(dir, e.Name())
+		logger.Infof("Deleting %s, which was replaced by an earlier restore", p)
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resetChangeLogID gives the change log of the SQLite database file at path a
+// new ID. The sequence numbers of a restored backup are reused for new
+// changes, so cursors into the change log of the replaced database must
+// expire. Backups from before the change log was added are unchanged, since
+// the change log is created with a new ID when they are migrated.
+func resetChangeLogID(path string) error {
+	conn, err := sqlx.Open(sqlite3Driver, "file:"+path)
+	if err != nil {
+		return fmt.Errorf("open database %s failed: %w", path, err)
+	}
+	defer conn.Close()
+
+	var n int
+	if err := conn.Get(&n, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", changeLogStateTable); err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	if _, err := conn.Exec("UPDATE `" + changeLogStateTable + "` SET `log_id` = lower(hex(randomblob(8)))"); err != nil {
+		return fmt.Errorf("resetting change log id: %w", err)
+	}
+
+	return nil
+}
+
+// RestorePath returns the path that a backup is copied to, so that it
+// replaces the database the next time the database is opened.
+func (db *Database) RestorePath() string {
+	return db.dbPath + restoreSuffix
+}
+
+// applyPendingRestore replaces the database with the backup at the restore
+// path, if one exists. The replaced database is kept alongside the database.
+func (db *Database) applyPendingRestore() error {
+	restorePath := db.RestorePath()
+	if _, err := os.Stat(restorePath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if exists, _ := fsutil.FileExists(db.dbPath); exists {
+		replacedPath := PreRestorePath(db.dbPath)
+
+		// move the write-ahead log with the database, so that the replaced
+		// database remains consistent
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Rename(db.dbPath+suffix, replacedPath+suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("moving database %s: %w", db.dbPath+suffix, err)
+			}
+		}
+
+		logger.Infof("Moved database %s to %s", db.dbPath, replacedPath)
+	}
+
+	if err := resetChangeLogID(restorePath); err != nil {
+		return fmt.Errorf("restoring backup %s: %w", restorePath, err)
+	}
+
+	if err := os.Rename(restorePath, db.dbPath); err != nil {
+		return fmt.Errorf("restoring backup %s: %w", restorePath, err)
+	}
+
+	logger.Infof("Restored database %s from backup", db.dbPath)
+
+	if err := PrunePreRestore(db.dbPath); err != nil {
+		logger.Warnf("error deleting replaced databases: %v", err)
+	}
+
+	return nil
+}
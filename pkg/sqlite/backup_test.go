@@ -0,0 +1,167 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyBackup(t *testing.T) {
+	skipIfPostgres(t)
+
+	dir := t.TempDir()
+
+	backupPath := filepath.Join(dir, "backup.sqlite")
+	if err := db.Backup(backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	if err := sqlite.VerifyBackup(backupPath); err != nil {
+		t.Errorf("VerifyBackup() error = %v", err)
+	}
+
+	corruptPath := filepath.Join(dir, "corrupt.sqlite")
+	if err := os.WriteFile(corruptPath, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sqlite.VerifyBackup(corruptPath); err == nil {
+		t.Error("VerifyBackup() of corrupt file returned no error")
+	}
+}
+
+func TestStripBlobs(t *testing.T) {
+	skipIfPostgres(t)
+
+	backupPath := filepath.Join(t.TempDir(), "backup.sqlite")
+	if err := db.Backup(backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	missing, err := sqlite.CountMissingBlobs(backupPath)
+	if err != nil {
+		t.Fatalf("CountMissingBlobs() error = %v", err)
+	}
+	if missing != 0 {
+		t.Errorf("CountMissingBlobs() before StripBlobs() = %d, want 0", missing)
+	}
+
+	if err := sqlite.StripBlobs(backupPath); err != nil {
+		t.Fatalf("StripBlobs() error = %v", err)
+	}
+
+	if err := sqlite.VerifyBackup(backupPath); err != nil {
+		t.Errorf("VerifyBackup() after StripBlobs() error = %v", err)
+	}
+
+	missing, err = sqlite.CountMissingBlobs(backupPath)
+	if err != nil {
+		t.Fatalf("CountMissingBlobs() error = %v", err)
+	}
+	if missing == 0 {
+		t.Error("CountMissingBlobs() after StripBlobs() = 0, want blobs without data")
+	}
+}
+
+func TestPrunePreRestore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stash-go.sqlite")
+
+	files := []string{
+		"stash-go.sqlite",
+		"stash-go.sqlite.pre-restore.20240101_000000",
+		"stash-go.sqlite.pre-restore.20240101_000000-wal",
+		"stash-go.sqlite.pre-restore.20240201_000000",
+		"stash-go.sqlite.pre-restore.20240201_000000-wal",
+		"stash-go.sqlite.pre-restore.20240201_000000-shm",
+		"other.sqlite.pre-restore.20240101_000000",
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sqlite.PrunePreRestore(path); err != nil {
+		t.Fatalf("PrunePreRestore() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+
+	want := []string{
+		"other.sqlite.pre-restore.20240101_000000",
+		"stash-go.sqlite",
+		"stash-go.sqlite.pre-restore.20240201_000000",
+		"stash-go.sqlite.pre-restore.20240201_000000-shm",
+		"stash-go.sqlite.pre-restore.20240201_000000-wal",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestRestore_resetsChangeLogID(t *testing.T) {
+	skipIfPostgres(t)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	var before *models.ChangeLogState
+	if err := withTxn(func(ctx context.Context) error {
+		var err error
+		before, err = db.ChangeLog.State(ctx)
+		return err
+	}); err != nil {
+		t.Fatalf("ChangeLogStore.State() error = %v", err)
+	}
+
+	// the replaced database and the restored backup are both copies of the
+	// test database
+	dbPath := filepath.Join(dir, "stash-go.sqlite")
+	if err := db.Backup(dbPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	restoredPath := dbPath + ".restore"
+	if err := db.Backup(restoredPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	restored := sqlite.NewDatabase()
+	if err := restored.Open(dbPath); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer restored.Close()
+
+	if _, err := os.Stat(restoredPath); !os.IsNotExist(err) {
+		t.Errorf("%s was not restored", restoredPath)
+	}
+
+	if err := txn.WithReadTxn(ctx, restored, func(ctx context.Context) error {
+		after, err := restored.ChangeLog.State(ctx)
+		if err != nil {
+			return err
+		}
+
+		assert.NotEmpty(t, after.LogID)
+		assert.NotEqual(t, before.LogID, after.LogID)
+		return nil
+	}); err != nil {
+		t.Errorf("ChangeLogStore.State() error = %v", err)
+	}
+}
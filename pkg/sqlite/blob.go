@@ -10,6 +10,7 @@ import (
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mattn/go-sqlite3"
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/hash/md5"
@@ -80,7 +81,7 @@ func (qb *BlobStore) table() exp.IdentifierExpression {
 
 func (qb *BlobStore) Count(ctx context.Context) (int, error) {
 	table := qb.table()
-	q := dialect.From(table).Select(goqu.COUNT(table.Col(blobChecksumColumn)))
+	q := dialect(ctx).From(table).Select(goqu.COUNT(table.Col(blobChecksumColumn)))
 
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -125,7 +126,7 @@ func (qb *BlobStore) Write(ctx context.Context, data []byte) (string, error) {
 
 func (qb *BlobStore) write(ctx context.Context, checksum string, data []byte) error {
 	table := qb.table()
-	q := dialect.Insert(table).Prepared(true).Rows(blobRow{
+	q := dialect(ctx).Insert(table).Prepared(true).Rows(blobRow{
 		Checksum: checksum,
 		Blob:     data,
 	}).OnConflict(goqu.DoNothing())
@@ -140,7 +141,7 @@ func (qb *BlobStore) write(ctx context.Context, checksum string, data []byte) er
 
 func (qb *BlobStore) update(ctx context.Context, checksum string, data []byte) error {
 	table := qb.table()
-	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+	q := dialect(ctx).Update(table).Prepared(true).Set(goqu.Record{
 		"blob": data,
 	}).Where(goqu.C(blobChecksumColumn).Eq(checksum))
 
@@ -236,7 +237,7 @@ func (qb *BlobStore) readFromFilesystem(ctx context.Context, checksum string) ([
 }
 
 func (qb *BlobStore) EntryExists(ctx context.Context, checksum string) (bool, error) {
-	q := dialect.From(qb.table()).Select(goqu.COUNT("*")).Where(qb.tableMgr.byID(checksum))
+	q := dialect(ctx).From(qb.table()).Select(goqu.COUNT("*")).Where(qb.tableMgr.byID(checksum))
 
 	var found int
 	if err := querySimple(ctx, q, &found); err != nil {
@@ -281,7 +282,7 @@ func (qb *BlobStore) Read(ctx context.Context, checksum string) ([]byte, error)
 }
 
 func (qb *BlobStore) readFromDatabase(ctx context.Context, checksum string) ([]byte, error) {
-	q := dialect.From(qb.table()).Select(qb.table().All()).Where(qb.tableMgr.byID(checksum))
+	q := dialect(ctx).From(qb.table()).Select(qb.table().All()).Where(qb.tableMgr.byID(checksum))
 
 	var row blobRow
 	const single = true
@@ -302,7 +303,10 @@ func (qb *BlobStore) readFromDatabase(ctx context.Context, checksum string) ([]b
 // If no references remain, the blob is deleted from the database and filesystem.
 func (qb *BlobStore) Delete(ctx context.Context, checksum string) error {
 	// try to delete the blob from the database
-	if err := qb.delete(ctx, checksum); err != nil {
+	// the expected constraint error must not abort the transaction
+	if err := withSavepoint(ctx, "blob_delete", func() error {
+		return qb.delete(ctx, checksum)
+	}); err != nil {
 		if qb.isConstraintError(err) {
 			// blob is still referenced - do not delete
 			logger.Debugf("Blob %s is still referenced - not deleting", checksum)
@@ -329,13 +333,19 @@ func (qb *BlobStore) isConstraintError(err error) bool {
 	if errors.As(err, &sqliteError) {
 		return sqliteError.Code == sqlite3.ErrConstraint
 	}
+
+	// integrity constraint violation class
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		return pqError.Code.Class() == "23"
+	}
 	return false
 }
 
 func (qb *BlobStore) delete(ctx context.Context, checksum string) error {
 	table := qb.table()
 
-	q := dialect.Delete(table).Where(goqu.C(blobChecksumColumn).Eq(checksum))
+	q := dialect(ctx).Delete(table).Where(goqu.C(blobChecksumColumn).Eq(checksum))
 
 	_, err := exec(ctx, q)
 	if err != nil {
@@ -9,7 +9,7 @@ import (
 
 func (qb *BlobStore) FindBlobs(ctx context.Context, n uint, lastChecksum string) ([]string, error) {
 	table := qb.table()
-	q := dialect.From(table).Select(table.Col(blobChecksumColumn)).Order(table.Col(blobChecksumColumn).Asc()).Limit(n)
+	q := dialect(ctx).From(table).Select(table.Col(blobChecksumColumn)).Order(table.Col(blobChecksumColumn).Asc()).Limit(n)
 
 	if lastChecksum != "" {
 		q = q.Where(table.Col(blobChecksumColumn).Gt(lastChecksum))
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	changeLogTable      = "change_log"
+	changeLogStateTable = "change_log_state"
+)
+
+// changeLogRow is an entry of the change log. Entries are inserted by
+// triggers, rather than by the store.
+type changeLogRow struct {
+	ID         int       `db:"id"`
+	EntityType string    `db:"entity_type"`
+	EntityID   int       `db:"entity_id"`
+	Operation  string    `db:"operation"`
+	ChangedAt  Timestamp `db:"changed_at"`
+}
+
+func (r *changeLogRow) resolve() *models.ChangeLogEntry {
+	return &models.ChangeLogEntry{
+		Sequence:   r.ID,
+		EntityType: r.EntityType,
+		EntityID:   r.EntityID,
+		Operation:  models.ChangeLogOperation(r.Operation),
+		ChangedAt:  r.ChangedAt.Timestamp,
+	}
+}
+
+type changeLogStateRow struct {
+	LogID          string `db:"log_id"`
+	PrunedSequence int    `db:"pruned_sequence"`
+}
+
+type ChangeLogStore struct {
+	tableMgr *table
+}
+
+func NewChangeLogStore() *ChangeLogStore {
+	return &ChangeLogStore{
+		tableMgr: changeLogTableMgr,
+	}
+}
+
+func (qb *ChangeLogStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+// committed returns the conditions restricting the change log to the
+// entries that cannot be preceded by entries still in flight. Writers are
+// serialised on SQLite, so entries are always committed in order. On
+// PostgreSQL, the horizon is found from the locks held by the writers, and
+// must be read after the snapshot of the query is taken.
+func (qb *ChangeLogStore) committed(ctx context.Context) []exp.Expression {
+	if !isPostgres(ctx) {
+		return nil
+	}
+
+	return []exp.Expression{
+		qb.table().Col(idColumn).Lte(goqu.L(`(SELECT "change_log_horizon"())`)),
+	}
+}
+
+func (qb *ChangeLogStore) FindSince(ctx context.Context, sequence int, limit int) ([]*models.ChangeLogEntry, error) {
+	table := qb.table()
+	q := dialect(ctx).From(table).Select(table.All()).Where(
+		table.Col(idColumn).Gt(sequence),
+	).Where(qb.committed(ctx)...).Order(table.Col(idColumn).Asc()).Limit(uint(limit))
+
+	const single = false
+	var ret []*models.ChangeLogEntry
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var r changeLogRow
+		if err := rows.StructScan(&r); err != nil {
+			return err
+		}
+
+		ret = append(ret, r.resolve())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *ChangeLogStore) State(ctx context.Context) (*models.ChangeLogState, error) {
+	var state changeLogStateRow
+	q := dialect(ctx).From(changeLogStateTable).Select("log_id", "pruned_sequence").Limit(1)
+	const single = true
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		return rows.StructScan(&state)
+	}); err != nil {
+		return nil, fmt.Errorf("getting change log state: %w", err)
+	}
+
+	var latest *int
+	q = dialect(ctx).From(qb.table()).Select(goqu.MAX(idColumn)).Where(qb.committed(ctx)...)
+	if err := querySimple(ctx, q, &latest); err != nil {
+		return nil, fmt.Errorf("getting latest change log sequence: %w", err)
+	}
+
+	ret := &models.ChangeLogState{
+		LogID:          state.LogID,
+		PrunedSequence: state.PrunedSequence,
+		LatestSequence: state.PrunedSequence,
+	}
+
+	// the log is empty if all entries have been pruned
+	if latest != nil && *latest > ret.LatestSequence {
+		ret.LatestSequence = *latest
+	}
+
+	return ret, nil
+}
+
+func (qb *ChangeLogStore) DestroyBefore(ctx context.Context, t time.Time) (int, error) {
+	table := qb.table()
+	before := table.Col("changed_at").Lt(UTCTimestamp{Timestamp{Timestamp: t}})
+
+	var pruned *int
+	q := dialect(ctx).From(table).Prepared(true).Select(goqu.MAX(idColumn)).Where(before).Where(qb.committed(ctx)...)
+	if err := querySimple(ctx, q, &pruned); err != nil {
+		return 0, fmt.Errorf("getting pruned change log sequence: %w", err)
+	}
+
+	if pruned == nil {
+		return 0, nil
+	}
+
+	// entries are removed up to the last expired entry, so that the log
+	// remains contiguous
+	r, err := exec(ctx, dialect(ctx).Delete(table).Prepared(true).Where(table.Col(idColumn).Lte(*pruned)))
+	if err != nil {
+		return 0, fmt.Errorf("pruning change log: %w", err)
+	}
+
+	if _, err := exec(ctx, dialect(ctx).Update(changeLogStateTable).Prepared(true).Set(
+		goqu.Record{"pruned_sequence": *pruned},
+	)); err != nil {
+		return 0, fmt.Errorf("updating change log state: %w", err)
+	}
+
+	n, err := r.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
@@ -0,0 +1,141 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeLog(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.ChangeLog
+
+		before, err := qb.State(ctx)
+		if err != nil {
+			t.Errorf("ChangeLogStore.State() error = %v", err)
+			return nil
+		}
+
+		assert.NotEmpty(t, before.LogID)
+
+		sceneID := sceneIDs[sceneIdxWithTag]
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, models.ScenePartial{
+			Title: models.NewOptionalString("change log"),
+		}); err != nil {
+			t.Errorf("SceneStore.UpdatePartial() error = %v", err)
+			return nil
+		}
+
+		// destroying the tag removes it from the scene
+		tagID := tagIDs[tagIdxWithScene]
+		if err := db.Tag.Destroy(ctx, tagID); err != nil {
+			t.Errorf("TagStore.Destroy() error = %v", err)
+			return nil
+		}
+
+		entries, err := qb.FindSince(ctx, before.LatestSequence, 100)
+		if err != nil {
+			t.Errorf("ChangeLogStore.FindSince() error = %v", err)
+			return nil
+		}
+
+		type change struct {
+			entityType string
+			id         int
+			op         models.ChangeLogOperation
+		}
+
+		var changes []change
+		for _, e := range entries {
+			assert.Greater(t, e.Sequence, before.LatestSequence)
+			changes = append(changes, change{e.EntityType, e.EntityID, e.Operation})
+		}
+
+		sceneUpserts := 0
+		for _, c := range changes {
+			if c == (change{"SCENE", sceneID, models.ChangeLogOperationUpsert}) {
+				sceneUpserts++
+			}
+		}
+
+		// the scene is changed again by removing the tag
+		assert.Equal(t, 2, sceneUpserts)
+		assert.Contains(t, changes, change{"TAG", tagID, models.ChangeLogOperationDelete})
+
+		// limited
+		entries, err = qb.FindSince(ctx, before.LatestSequence, 1)
+		if err == nil {
+			assert.Len(t, entries, 1)
+		}
+
+		after, err := qb.State(ctx)
+		if err != nil {
+			t.Errorf("ChangeLogStore.State() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(t, before.LogID, after.LogID)
+		assert.Greater(t, after.LatestSequence, before.LatestSequence)
+
+		n, err := qb.DestroyBefore(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Errorf("ChangeLogStore.DestroyBefore() error = %v", err)
+			return nil
+		}
+
+		assert.GreaterOrEqual(t, n, len(changes))
+
+		pruned, err := qb.State(ctx)
+		if err != nil {
+			t.Errorf("ChangeLogStore.State() error = %v", err)
+			return nil
+		}
+
+		// the latest sequence is kept after all entries are pruned
+		assert.Equal(t, after.LatestSequence, pruned.PrunedSequence)
+		assert.Equal(t, after.LatestSequence, pruned.LatestSequence)
+
+		return nil
+	})
+}
+
+func TestChangeLog_playback(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.ChangeLog
+
+		before, err := qb.State(ctx)
+		if err != nil {
+			t.Errorf("ChangeLogStore.State() error = %v", err)
+			return nil
+		}
+
+		// activity saved during playback is not logged
+		resumeTime := 10.0
+		playDuration := 5.0
+		if _, err := db.Scene.SaveActivity(ctx, sceneIDs[sceneIdxWithTag], &resumeTime, &playDuration); err != nil {
+			t.Errorf("SceneStore.SaveActivity() error = %v", err)
+			return nil
+		}
+
+		if _, err := db.Scene.ResetActivity(ctx, sceneIDs[sceneIdxWithTag], true, true); err != nil {
+			t.Errorf("SceneStore.ResetActivity() error = %v", err)
+			return nil
+		}
+
+		entries, err := qb.FindSince(ctx, before.LatestSequence, 100)
+		if err != nil {
+			t.Errorf("ChangeLogStore.FindSince() error = %v", err)
+			return nil
+		}
+
+		assert.Empty(t, entries)
+
+		return nil
+	})
+}
@@ -13,7 +13,7 @@ type oCounterManager struct {
 }
 
 func (qb *oCounterManager) getOCounter(ctx context.Context, id int) (int, error) {
-	q := dialect.From(qb.tableMgr.table).Select("o_counter").Where(goqu.Ex{"id": id})
+	q := dialect(ctx).From(qb.tableMgr.table).Select("o_counter").Where(goqu.Ex{"id": id})
 
 	const single = true
 	var ret int
@@ -49,7 +49,7 @@ func (qb *oCounterManager) DecrementOCounter(ctx context.Context, id int) (int,
 	}
 
 	table := qb.tableMgr.table
-	q := dialect.Update(table).Set(goqu.Record{
+	q := dialect(ctx).Update(table).Set(goqu.Record{
 		"o_counter": goqu.L("o_counter - 1"),
 	}).Where(qb.tableMgr.byID(id), goqu.L("o_counter > 0"))
 
@@ -39,25 +39,25 @@ func stringCriterionHandler(c *models.StringCriterionInput, column string) crite
 			if modifier := c.Modifier; c.Modifier.IsValid() {
 				switch modifier {
 				case models.CriterionModifierIncludes:
-					f.whereClauses = append(f.whereClauses, getStringSearchClause([]string{column}, c.Value, false))
+					f.whereClauses = append(f.whereClauses, getStringSearchClause(ctx, []string{column}, c.Value, false))
 				case models.CriterionModifierExcludes:
-					f.whereClauses = append(f.whereClauses, getStringSearchClause([]string{column}, c.Value, true))
+					f.whereClauses = append(f.whereClauses, getStringSearchClause(ctx, []string{column}, c.Value, true))
 				case models.CriterionModifierEquals:
-					f.addWhere(column+" LIKE ?", c.Value)
+					f.addWhere(likeSQL(ctx, column, false), c.Value)
 				case models.CriterionModifierNotEquals:
-					f.addWhere(column+" NOT LIKE ?", c.Value)
+					f.addWhere(likeSQL(ctx, column, true), c.Value)
 				case models.CriterionModifierMatchesRegex:
 					if _, err := regexp.Compile(c.Value); err != nil {
 						f.setError(err)
 						return
 					}
-					f.addWhere(fmt.Sprintf("(%s IS NOT NULL AND %[1]s regexp ?)", column), c.Value)
+					f.addWhere(fmt.Sprintf("(%s IS NOT NULL AND %s)", column, regexpSQL(ctx, column, false)), c.Value)
 				case models.CriterionModifierNotMatchesRegex:
 					if _, err := regexp.Compile(c.Value); err != nil {
 						f.setError(err)
 						return
 					}
-					f.addWhere(fmt.Sprintf("(%s IS NULL OR %[1]s NOT regexp ?)", column), c.Value)
+					f.addWhere(fmt.Sprintf("(%s IS NULL OR %s)", column, regexpSQL(ctx, column, true)), c.Value)
 				case models.CriterionModifierIsNull:
 					f.addWhere("(" + column + " IS NULL OR TRIM(" + column + ") = '')")
 				case models.CriterionModifierNotNull:
@@ -105,31 +105,31 @@ func pathCriterionHandler(c *models.StringCriterionInput, pathColumn string, bas
 			if modifier := c.Modifier; c.Modifier.IsValid() {
 				switch modifier {
 				case models.CriterionModifierIncludes:
-					f.whereClauses = append(f.whereClauses, getPathSearchClauseMany(pathColumn, basenameColumn, c.Value, addWildcards, not))
+					f.whereClauses = append(f.whereClauses, getPathSearchClauseMany(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not))
 				case models.CriterionModifierExcludes:
 					not = true
-					f.whereClauses = append(f.whereClauses, getPathSearchClauseMany(pathColumn, basenameColumn, c.Value, addWildcards, not))
+					f.whereClauses = append(f.whereClauses, getPathSearchClauseMany(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not))
 				case models.CriterionModifierEquals:
 					addWildcards = false
-					f.whereClauses = append(f.whereClauses, getPathSearchClause(pathColumn, basenameColumn, c.Value, addWildcards, not))
+					f.whereClauses = append(f.whereClauses, getPathSearchClause(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not))
 				case models.CriterionModifierNotEquals:
 					addWildcards = false
 					not = true
-					f.whereClauses = append(f.whereClauses, getPathSearchClause(pathColumn, basenameColumn, c.Value, addWildcards, not))
+					f.whereClauses = append(f.whereClauses, getPathSearchClause(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not))
 				case models.CriterionModifierMatchesRegex:
 					if _, err := regexp.Compile(c.Value); err != nil {
 						f.setError(err)
 						return
 					}
 					filepathColumn := fmt.Sprintf("%s || '%s' || %s", pathColumn, string(filepath.Separator), basenameColumn)
-					f.addWhere(fmt.Sprintf("%s IS NOT NULL AND %s IS NOT NULL AND %s regexp ?", pathColumn, basenameColumn, filepathColumn), c.Value)
+					f.addWhere(fmt.Sprintf("%s IS NOT NULL AND %s IS NOT NULL AND %s", pathColumn, basenameColumn, regexpSQL(ctx, filepathColumn, false)), c.Value)
 				case models.CriterionModifierNotMatchesRegex:
 					if _, err := regexp.Compile(c.Value); err != nil {
 						f.setError(err)
 						return
 					}
 					filepathColumn := fmt.Sprintf("%s || '%s' || %s", pathColumn, string(filepath.Separator), basenameColumn)
-					f.addWhere(fmt.Sprintf("%s IS NULL OR %s IS NULL OR %s NOT regexp ?", pathColumn, basenameColumn, filepathColumn), c.Value)
+					f.addWhere(fmt.Sprintf("%s IS NULL OR %s IS NULL OR %s", pathColumn, basenameColumn, regexpSQL(ctx, filepathColumn, true)), c.Value)
 				case models.CriterionModifierIsNull:
 					f.addWhere(fmt.Sprintf("%s IS NULL OR TRIM(%[1]s) = '' OR %s IS NULL OR TRIM(%[2]s) = ''", pathColumn, basenameColumn))
 				case models.CriterionModifierNotNull:
@@ -142,13 +142,13 @@ func pathCriterionHandler(c *models.StringCriterionInput, pathColumn string, bas
 	}
 }
 
-func getPathSearchClause(pathColumn, basenameColumn, p string, addWildcards, not bool) sqlClause {
+func getPathSearchClause(ctx context.Context, pathColumn, basenameColumn, p string, addWildcards, not bool) sqlClause {
 	if addWildcards {
 		p = "%" + p + "%"
 	}
 
 	filepathColumn := fmt.Sprintf("%s || '%s' || %s", pathColumn, string(filepath.Separator), basenameColumn)
-	ret := makeClause(fmt.Sprintf("%s LIKE ?", filepathColumn), p)
+	ret := makeClause(likeSQL(ctx, filepathColumn, false), p)
 
 	if not {
 		ret = ret.not()
@@ -159,7 +159,7 @@ func getPathSearchClause(pathColumn, basenameColumn, p string, addWildcards, not
 
 // getPathSearchClauseMany splits the query string p on whitespace
 // Used for backwards compatibility for the includes/excludes modifiers
-func getPathSearchClauseMany(pathColumn, basenameColumn, p string, addWildcards, not bool) sqlClause {
+func getPathSearchClauseMany(ctx context.Context, pathColumn, basenameColumn, p string, addWildcards, not bool) sqlClause {
 	q := strings.TrimSpace(p)
 	trimmedQuery := strings.Trim(q, "\"")
 
@@ -170,7 +170,7 @@ func getPathSearchClauseMany(pathColumn, basenameColumn, p string, addWildcards,
 		var ret []sqlClause
 		// Search for any word
 		for _, word := range queryWords {
-			ret = append(ret, getPathSearchClause(pathColumn, basenameColumn, word, addWildcards, not))
+			ret = append(ret, getPathSearchClause(ctx, pathColumn, basenameColumn, word, addWildcards, not))
 		}
 
 		if !not {
@@ -180,7 +180,7 @@ func getPathSearchClauseMany(pathColumn, basenameColumn, p string, addWildcards,
 		return andClauses(ret...)
 	}
 
-	return getPathSearchClause(pathColumn, basenameColumn, trimmedQuery, addWildcards, not)
+	return getPathSearchClause(ctx, pathColumn, basenameColumn, trimmedQuery, addWildcards, not)
 }
 
 func intCriterionHandler(c *models.IntCriterionInput, column string, addJoinFn func(f *filterBuilder)) criterionHandlerFunc {
@@ -227,9 +227,9 @@ func boolCriterionHandler(c *bool, column string, addJoinFn func(f *filterBuilde
 			}
 			var v string
 			if *c {
-				v = "1"
+				v = "TRUE"
 			} else {
-				v = "0"
+				v = "FALSE"
 			}
 
 			f.addWhere(column + " = " + v)
@@ -272,7 +272,7 @@ func (h *timestampCriterionHandler) handle(ctx context.Context, f *filterBuilder
 func yearFilterCriterionHandler(year *models.IntCriterionInput, col string) criterionHandlerFunc {
 	return func(ctx context.Context, f *filterBuilder) {
 		if year != nil && year.Modifier.IsValid() {
-			clause, args := getIntCriterionWhereClause("cast(strftime('%Y', "+col+") as int)", *year)
+			clause, args := getIntCriterionWhereClause(yearSQL(ctx, col), *year)
 			f.addWhere(clause, args...)
 		}
 	}
@@ -288,7 +288,7 @@ func resolutionCriterionHandler(resolution *models.ResolutionCriterionInput, hei
 			mn := resolution.Value.GetMinResolution()
 			mx := resolution.Value.GetMaxResolution()
 
-			widthHeight := fmt.Sprintf("MIN(%s, %s)", widthColumn, heightColumn)
+			widthHeight := leastSQL(ctx, widthColumn, heightColumn)
 
 			switch resolution.Modifier {
 			case models.CriterionModifierEquals:
@@ -417,7 +417,7 @@ func (m *joinedMultiCriterionHandlerBuilder) handler(c *models.MultiCriterionInp
 						"primaryFK":    m.primaryFK,
 						"primaryTable": m.primaryTable,
 					})
-					havingClause = fmt.Sprintf("count(distinct %s.%s) IS %d", joinAlias, m.foreignFK, len(criterion.Value))
+					havingClause = fmt.Sprintf("count(distinct %s.%s) = %d", joinAlias, m.foreignFK, len(criterion.Value))
 					args = append(args, len(criterion.Value))
 				case models.CriterionModifierNotEquals:
 					f.setError(fmt.Errorf("not equals modifier is not supported for multi criterion input"))
@@ -425,7 +425,7 @@ func (m *joinedMultiCriterionHandlerBuilder) handler(c *models.MultiCriterionInp
 					// includes all of the provided ids
 					m.addJoinTable(f)
 					whereClause = fmt.Sprintf("%s.%s IN %s", joinAlias, m.foreignFK, getInBinding(len(criterion.Value)))
-					havingClause = fmt.Sprintf("count(distinct %s.%s) IS %d", joinAlias, m.foreignFK, len(criterion.Value))
+					havingClause = fmt.Sprintf("count(distinct %s.%s) = %d", joinAlias, m.foreignFK, len(criterion.Value))
 				}
 
 				f.addWhere(whereClause, args...)
@@ -526,7 +526,7 @@ type stringListCriterionHandlerBuilder struct {
 	stringColumn string
 
 	addJoinTable   func(f *filterBuilder)
-	excludeHandler func(f *filterBuilder, criterion *models.StringCriterionInput)
+	excludeHandler func(ctx context.Context, f *filterBuilder, criterion *models.StringCriterionInput)
 }
 
 func (m *stringListCriterionHandlerBuilder) handler(criterion *models.StringCriterionInput) criterionHandlerFunc {
@@ -535,19 +535,19 @@ func (m *stringListCriterionHandlerBuilder) handler(criterion *models.StringCrit
 			if criterion.Modifier == models.CriterionModifierExcludes {
 				// special handling for excludes
 				if m.excludeHandler != nil {
-					m.excludeHandler(f, criterion)
+					m.excludeHandler(ctx, f, criterion)
 					return
 				}
 
 				// excludes all of the provided values
 				// need to use actual join table name for this
 				// <primaryTable>.id NOT IN (select <joinTable>.<primaryFK> from <joinTable> where <joinTable>.<foreignFK> in <values>)
-				whereClause := utils.StrFormat("{primaryTable}.id NOT IN (SELECT {joinTable}.{primaryFK} from {joinTable} where {joinTable}.{stringColumn} LIKE ?)",
+				whereClause := utils.StrFormat("{primaryTable}.id NOT IN (SELECT {joinTable}.{primaryFK} from {joinTable} where {likeClause})",
 					utils.StrFormatMap{
 						"primaryTable": m.primaryTable,
 						"joinTable":    m.joinTable,
 						"primaryFK":    m.primaryFK,
-						"stringColumn": m.stringColumn,
+						"likeClause":   likeSQL(ctx, m.joinTable+"."+m.stringColumn, false),
 					},
 				)
 
@@ -566,6 +566,47 @@ func (m *stringListCriterionHandlerBuilder) handler(criterion *models.StringCrit
 	}
 }
 
+// phashDistanceCriterionHandler filters on the phash fingerprint of the files
+// in filesTable, which is joined by addJoinFn.
+func phashDistanceCriterionHandler(phashDistance *models.PhashDistanceCriterionInput, filesTable string, addJoinFn func(f *filterBuilder)) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if phashDistance != nil {
+			addJoinFn(f)
+			f.addLeftJoin(fingerprintTable, "fingerprints_phash", filesTable+".file_id = fingerprints_phash.file_id AND fingerprints_phash.type = 'phash'")
+
+			value, _ := utils.StringToPhash(phashDistance.Value)
+			distance := 0
+			if phashDistance.Distance != nil {
+				distance = *phashDistance.Distance
+			}
+
+			if distance == 0 {
+				// use the default handler
+				intCriterionHandler(&models.IntCriterionInput{
+					Value:    int(value),
+					Modifier: phashDistance.Modifier,
+				}, "fingerprints_phash.fingerprint", nil)(ctx, f)
+			}
+
+			switch {
+			case phashDistance.Modifier == models.CriterionModifierEquals && distance > 0:
+				// needed to avoid a type mismatch
+				f.addWhere(isIntegerSQL(ctx, "fingerprints_phash.fingerprint"))
+				f.addWhere(phashDistanceSQL(ctx, "fingerprints_phash.fingerprint")+" < ?", value, distance)
+			case phashDistance.Modifier == models.CriterionModifierNotEquals && distance > 0:
+				// needed to avoid a type mismatch
+				f.addWhere(isIntegerSQL(ctx, "fingerprints_phash.fingerprint"))
+				f.addWhere(phashDistanceSQL(ctx, "fingerprints_phash.fingerprint")+" > ?", value, distance)
+			default:
+				intCriterionHandler(&models.IntCriterionInput{
+					Value:    int(value),
+					Modifier: phashDistance.Modifier,
+				}, "fingerprints_phash.fingerprint", nil)(ctx, f)
+			}
+		}
+	}
+}
+
 func studioCriterionHandler(primaryTable string, studios *models.HierarchicalMultiCriterionInput) criterionHandlerFunc {
 	return func(ctx context.Context, f *filterBuilder) {
 		if studios == nil {
@@ -679,7 +720,7 @@ WHERE id in {inBinding}
 {unionClause})
 `, withClauseMap)
 
-	query := fmt.Sprintf("WITH RECURSIVE %s SELECT 'VALUES' || GROUP_CONCAT('(' || root_id || ', ' || item_id || ')') AS val FROM items", withClause)
+	query := fmt.Sprintf("WITH RECURSIVE %s SELECT 'VALUES' || %s AS val FROM items", withClause, groupConcatSQL(ctx, "'(' || root_id || ', ' || item_id || ')'", false))
 
 	var valuesClause sql.NullString
 	err := dbWrapper.Get(ctx, &valuesClause, query, args...)
@@ -704,7 +745,7 @@ func addHierarchicalConditionClauses(f *filterBuilder, criterion models.Hierarch
 		f.addWhere(fmt.Sprintf("%s.%s IS NOT NULL", table, idColumn))
 	case models.CriterionModifierIncludesAll:
 		f.addWhere(fmt.Sprintf("%s.%s IS NOT NULL", table, idColumn))
-		f.addHaving(fmt.Sprintf("count(distinct %s.%s) IS %d", table, idColumn, len(criterion.Value)))
+		f.addHaving(fmt.Sprintf("count(distinct %s.%s) = %d", table, idColumn, len(criterion.Value)))
 	case models.CriterionModifierExcludes:
 		f.addWhere(fmt.Sprintf("%s.%s IS NULL", table, idColumn))
 	}
@@ -756,10 +797,10 @@ func (m *hierarchicalMultiCriterionHandlerBuilder) handler(c *models.Hierarchica
 
 				switch criterion.Modifier {
 				case models.CriterionModifierIncludes:
-					f.addWhere(fmt.Sprintf("%s.%s IN (SELECT column2 FROM (%s))", m.primaryTable, m.foreignFK, valuesClause))
+					f.addWhere(fmt.Sprintf("%s.%s IN (SELECT column2 FROM (%s) AS v)", m.primaryTable, m.foreignFK, valuesClause))
 				case models.CriterionModifierIncludesAll:
-					f.addWhere(fmt.Sprintf("%s.%s IN (SELECT column2 FROM (%s))", m.primaryTable, m.foreignFK, valuesClause))
-					f.addHaving(fmt.Sprintf("count(distinct %s.%s) IS %d", m.primaryTable, m.foreignFK, len(criterion.Value)))
+					f.addWhere(fmt.Sprintf("%s.%s IN (SELECT column2 FROM (%s) AS v)", m.primaryTable, m.foreignFK, valuesClause))
+					f.addHaving(fmt.Sprintf("count(distinct %s.%s) = %d", m.primaryTable, m.foreignFK, len(criterion.Value)))
 				}
 			}
 
@@ -770,7 +811,7 @@ func (m *hierarchicalMultiCriterionHandlerBuilder) handler(c *models.Hierarchica
 					return
 				}
 
-				f.addWhere(fmt.Sprintf("%s.%s NOT IN (SELECT column2 FROM (%s)) OR %[1]s.%[2]s IS NULL", m.primaryTable, m.foreignFK, valuesClause))
+				f.addWhere(fmt.Sprintf("%s.%s NOT IN (SELECT column2 FROM (%s) AS v) OR %[1]s.%[2]s IS NULL", m.primaryTable, m.foreignFK, valuesClause))
 			}
 		}
 	}
@@ -801,7 +842,7 @@ func (m *joinedHierarchicalMultiCriterionHandlerBuilder) addHierarchicalConditio
 	case models.CriterionModifierEquals:
 		// includes only the provided ids
 		f.addWhere(fmt.Sprintf("%s.%s IS NOT NULL", table, idColumn))
-		f.addHaving(fmt.Sprintf("count(distinct %s.%s) IS %d", table, idColumn, len(criterion.Value)))
+		f.addHaving(fmt.Sprintf("count(distinct %s.%s) = %d", table, idColumn, len(criterion.Value)))
 		f.addWhere(utils.StrFormat("(SELECT COUNT(*) FROM {joinTable} s WHERE s.{primaryFK} = {primaryTable}.{primaryKey}) = ?", utils.StrFormatMap{
 			"joinTable":    m.joinTable,
 			"primaryFK":    m.primaryFK,
@@ -983,7 +1024,7 @@ INNER JOIN (`+valuesClause+`) t ON t.column2 = pt.tag_id
 				return
 			}
 
-			clause := utils.StrFormat("{primaryTable}.id NOT IN (SELECT {joinTable}.{joinPrimaryKey} FROM {joinTable} INNER JOIN performers_tags ON {joinTable}.performer_id = performers_tags.performer_id WHERE performers_tags.tag_id IN (SELECT column2 FROM (%s)))", strFormatMap)
+			clause := utils.StrFormat("{primaryTable}.id NOT IN (SELECT {joinTable}.{joinPrimaryKey} FROM {joinTable} INNER JOIN performers_tags ON {joinTable}.performer_id = performers_tags.performer_id WHERE performers_tags.tag_id IN (SELECT column2 FROM (%s) AS v))", strFormatMap)
 			f.addWhere(fmt.Sprintf(clause, valuesClause))
 		}
 	}
@@ -1054,5 +1095,5 @@ func (h *relatedFilterHandler) handle(ctx context.Context, f *filterBuilder) {
 		h.joinFn(f)
 	}
 
-	f.addWhere(fmt.Sprintf("%s IN ("+subQuery.toSQL(false)+")", h.relatedIDCol), subQuery.args...)
+	f.addWhere(fmt.Sprintf("%s IN ("+subQuery.toSQL(ctx, false)+")", h.relatedIDCol), subQuery.args...)
 }
@@ -0,0 +1,537 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4/zero"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	customFieldDefinitionTable = "custom_field_definitions"
+	customFieldIDColumn        = "field_id"
+	customFieldValueColumn     = "value"
+
+	// customFieldSortPrefix is the prefix of sorts by a custom field, followed
+	// by the name of the field.
+	customFieldSortPrefix = "custom_fields."
+)
+
+type customFieldDefinitionRow struct {
+	ID         int         `db:"id" goqu:"skipinsert"`
+	EntityType string      `db:"entity_type"`
+	Name       string      `db:"name"`
+	Type       string      `db:"type"`
+	Options    zero.String `db:"options"`
+	CreatedAt  Timestamp   `db:"created_at"`
+	UpdatedAt  Timestamp   `db:"updated_at"`
+}
+
+func (r *customFieldDefinitionRow) fromCustomFieldDefinition(o models.CustomFieldDefinition) {
+	r.ID = o.ID
+	r.EntityType = o.EntityType.String()
+	r.Name = o.Name
+	r.Type = o.Type.String()
+	if len(o.Options) > 0 {
+		r.Options = zero.StringFrom(encodeJSONOrEmpty(o.Options))
+	}
+	r.CreatedAt = Timestamp{Timestamp: o.CreatedAt}
+	r.UpdatedAt = Timestamp{Timestamp: o.UpdatedAt}
+}
+
+func (r *customFieldDefinitionRow) resolve() *models.CustomFieldDefinition {
+	ret := &models.CustomFieldDefinition{
+		ID:         r.ID,
+		EntityType: models.CustomFieldEntityType(r.EntityType),
+		Name:       r.Name,
+		Type:       models.CustomFieldType(r.Type),
+		CreatedAt:  r.CreatedAt.Timestamp,
+		UpdatedAt:  r.UpdatedAt.Timestamp,
+	}
+
+	decodeJSON(r.Options.String, &ret.Options)
+
+	return ret
+}
+
+type CustomFieldStore struct {
+	repository
+	tableMgr *table
+}
+
+func NewCustomFieldStore() *CustomFieldStore {
+	return &CustomFieldStore{
+		repository: repository{
+			tableName: customFieldDefinitionTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: customFieldDefinitionTableMgr,
+	}
+}
+
+func (qb *CustomFieldStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *CustomFieldStore) Create(ctx context.Context, newObject *models.CustomFieldDefinition) error {
+	var r customFieldDefinitionRow
+	r.fromCustomFieldDefinition(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	updated, err := qb.Find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *CustomFieldStore) Update(ctx context.Context, updatedObject *models.CustomFieldDefinition) error {
+	var r customFieldDefinitionRow
+	r.fromCustomFieldDefinition(*updatedObject)
+
+	return qb.tableMgr.updateByID(ctx, updatedObject.ID, r)
+}
+
+// Destroy destroys the custom field definition, along with the values of the
+// field.
+func (qb *CustomFieldStore) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+// returns nil, nil if not found
+func (qb *CustomFieldStore) Find(ctx context.Context, id int) (*models.CustomFieldDefinition, error) {
+	ret, err := getCustomFieldDefinitions(ctx, qb.table().Col(idColumn).Eq(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+// returns nil, nil if not found
+func (qb *CustomFieldStore) FindByName(ctx context.Context, entityType models.CustomFieldEntityType, name string) (*models.CustomFieldDefinition, error) {
+	ret, err := getCustomFieldDefinitions(ctx,
+		qb.table().Col("entity_type").Eq(entityType.String()),
+		qb.table().Col("name").Eq(name),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *CustomFieldStore) FindByEntityType(ctx context.Context, entityType models.CustomFieldEntityType) ([]*models.CustomFieldDefinition, error) {
+	return getCustomFieldDefinitions(ctx, qb.table().Col("entity_type").Eq(entityType.String()))
+}
+
+func (qb *CustomFieldStore) All(ctx context.Context) ([]*models.CustomFieldDefinition, error) {
+	return getCustomFieldDefinitions(ctx)
+}
+
+func getCustomFieldDefinitions(ctx context.Context, where ...exp.Expression) ([]*models.CustomFieldDefinition, error) {
+	table := customFieldDefinitionTableMgr.table
+	q := dialect(ctx).From(table).Select(table.All()).Where(where...).Order(
+		table.Col("entity_type").Asc(),
+		table.Col("name").Asc(),
+	)
+
+	const single = false
+	var ret []*models.CustomFieldDefinition
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var r customFieldDefinitionRow
+		if err := rows.StructScan(&r); err != nil {
+			return err
+		}
+
+		ret = append(ret, r.resolve())
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting custom field definitions: %w", err)
+	}
+
+	return ret, nil
+}
+
+// customFieldsTable is a table of the custom field values of an entity type.
+// The id column references the object, and the field_id column references
+// the custom field definition.
+type customFieldsTable struct {
+	table
+	entityType models.CustomFieldEntityType
+}
+
+// definition returns the definition of the named custom field of the entity
+// type, or an error if the field is not defined.
+func (t *customFieldsTable) definition(ctx context.Context, name string) (*models.CustomFieldDefinition, error) {
+	ret, err := t.findDefinition(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		return nil, t.notDefinedError(name)
+	}
+
+	return ret, nil
+}
+
+// findDefinition returns the definition of the custom field with the given
+// name, or nil if it is not defined.
+func (t *customFieldsTable) findDefinition(ctx context.Context, name string) (*models.CustomFieldDefinition, error) {
+	defTable := customFieldDefinitionTableMgr.table
+	ret, err := getCustomFieldDefinitions(ctx,
+		defTable.Col("entity_type").Eq(t.entityType.String()),
+		defTable.Col("name").Eq(name),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (t *customFieldsTable) notDefinedError(name string) error {
+	return fmt.Errorf("custom field %q is not defined for %s", name, strings.ToLower(t.entityType.String()))
+}
+
+func (t *customFieldsTable) get(ctx context.Context, id int) (map[string]interface{}, error) {
+	defTable := customFieldDefinitionTableMgr.table
+	q := dialect(ctx).Select(defTable.Col("name"), defTable.Col("type"), t.table.table.Col(customFieldValueColumn)).
+		From(t.table.table).
+		InnerJoin(defTable, goqu.On(defTable.Col(idColumn).Eq(t.table.table.Col(customFieldIDColumn)))).
+		Where(t.idColumn.Eq(id))
+
+	const single = false
+	ret := make(map[string]interface{})
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var (
+			name      string
+			fieldType string
+			value     interface{}
+		)
+		if err := rows.Scan(&name, &fieldType, &value); err != nil {
+			return err
+		}
+
+		v, err := customFieldValue(models.CustomFieldType(fieldType), value)
+		if err != nil {
+			return fmt.Errorf("custom field %q: %w", name, err)
+		}
+
+		ret[name] = v
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting custom fields from %s: %w", t.table.table.GetTable(), err)
+	}
+
+	return ret, nil
+}
+
+// set sets the custom fields of the object. The values are converted to the
+// types of the fields, which must be defined for the entity type.
+func (t *customFieldsTable) set(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	if input.Full != nil {
+		if _, err := exec(ctx, dialect(ctx).Delete(t.table.table).Where(t.idColumn.Eq(id))); err != nil {
+			return fmt.Errorf("destroying custom fields from %s: %w", t.table.table.GetTable(), err)
+		}
+
+		return t.setValues(ctx, id, input.Full)
+	}
+
+	for _, name := range input.Remove {
+		d, err := t.definition(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if err := t.destroyValue(ctx, id, d.ID); err != nil {
+			return err
+		}
+	}
+
+	return t.setValues(ctx, id, input.Partial)
+}
+
+func (t *customFieldsTable) setValues(ctx context.Context, id int, values map[string]interface{}) error {
+	for name, v := range values {
+		d, err := t.definition(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		converted, err := d.ConvertValue(v)
+		if err != nil {
+			return err
+		}
+
+		if err := t.destroyValue(ctx, id, d.ID); err != nil {
+			return err
+		}
+
+		q := dialect(ctx).Insert(t.table.table).Cols(t.idColumn.GetCol(), customFieldIDColumn, customFieldValueColumn).Vals(
+			goqu.Vals{id, d.ID, customFieldDBValue(converted)},
+		)
+		if _, err := exec(ctx, q); err != nil {
+			return fmt.Errorf("inserting into %s: %w", t.table.table.GetTable(), err)
+		}
+	}
+
+	return nil
+}
+
+func (t *customFieldsTable) destroyValue(ctx context.Context, id int, fieldID int) error {
+	q := dialect(ctx).Delete(t.table.table).Where(
+		t.idColumn.Eq(id),
+		t.table.table.Col(customFieldIDColumn).Eq(fieldID),
+	)
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("destroying custom field from %s: %w", t.table.table.GetTable(), err)
+	}
+
+	return nil
+}
+
+// customFieldDBValue returns the value stored in the database for a
+// converted custom field value. Booleans are stored as integers so that they
+// sort consistently.
+func customFieldDBValue(v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		if b {
+			return int64(1)
+		}
+		return int64(0)
+	}
+
+	return v
+}
+
+// customFieldValue converts a value read from the database to the type of the
+// custom field. Values are stored as text on PostgreSQL.
+func customFieldValue(t models.CustomFieldType, v interface{}) (interface{}, error) {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	s, isString := v.(string)
+
+	switch t {
+	case models.CustomFieldTypeInt:
+		if isString {
+			return strconv.ParseInt(s, 10, 64)
+		}
+	case models.CustomFieldTypeFloat:
+		if isString {
+			return strconv.ParseFloat(s, 64)
+		}
+		if i, ok := v.(int64); ok {
+			return float64(i), nil
+		}
+	case models.CustomFieldTypeBool:
+		if isString {
+			i, err := strconv.ParseInt(s, 10, 64)
+			return i != 0, err
+		}
+		if i, ok := v.(int64); ok {
+			return i != 0, nil
+		}
+	default:
+		if !isString {
+			return fmt.Sprint(v), nil
+		}
+	}
+
+	return v, nil
+}
+
+// customFieldValueSQL returns the expression of the value column, converted
+// to the type of the custom field. Values are stored as text on PostgreSQL.
+func customFieldValueSQL(ctx context.Context, column string, t models.CustomFieldType) string {
+	if !isPostgres(ctx) {
+		return column
+	}
+
+	switch t {
+	case models.CustomFieldTypeInt, models.CustomFieldTypeBool:
+		return "CAST(" + column + " AS BIGINT)"
+	case models.CustomFieldTypeFloat:
+		return "CAST(" + column + " AS DOUBLE PRECISION)"
+	}
+
+	return column
+}
+
+func isCustomFieldSort(sort string) bool {
+	return strings.HasPrefix(sort, customFieldSortPrefix)
+}
+
+// sort returns the sort clause sorting the objects of primaryTable by the
+// custom field named in the sort.
+func (t *customFieldsTable) sort(ctx context.Context, sort string, direction string, primaryTable string) (string, error) {
+	name := strings.TrimPrefix(sort, customFieldSortPrefix)
+	d, err := t.findDefinition(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	if d == nil {
+		return "", fmt.Errorf("%w: %v", models.ErrInvalidSort, t.notDefinedError(name))
+	}
+
+	collate := ""
+	if d.Type == models.CustomFieldTypeString || d.Type == models.CustomFieldTypeEnum {
+		collate = " COLLATE NATURAL_CI"
+	}
+
+	return fmt.Sprintf(" ORDER BY (SELECT %s FROM %s AS sort WHERE sort.%s = %s.id AND sort.%s = %d)%s %s",
+		customFieldValueSQL(ctx, "sort."+customFieldValueColumn, d.Type),
+		t.table.table.GetTable(), t.idColumn.GetCol(), primaryTable, customFieldIDColumn, d.ID,
+		collate, getSortDirection(direction),
+	), nil
+}
+
+// customFieldsCriterionHandler filters on the custom fields of the objects
+// identified by idColumn.
+func customFieldsCriterionHandler(criteria []models.CustomFieldCriterionInput, t *customFieldsTable, idColumn string) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		for _, c := range criteria {
+			clause, err := t.criterionClause(ctx, c, idColumn)
+			if err != nil {
+				f.setError(err)
+				return
+			}
+
+			f.addWhere(clause.sql, clause.args...)
+		}
+	}
+}
+
+func (t *customFieldsTable) criterionClause(ctx context.Context, c models.CustomFieldCriterionInput, idColumn string) (sqlClause, error) {
+	d, err := t.definition(ctx, c.Field)
+	if err != nil {
+		return sqlClause{}, err
+	}
+
+	valueColumn := customFieldValueSQL(ctx, "v."+customFieldValueColumn, d.Type)
+	inValues := func(where string, args ...interface{}) sqlClause {
+		return makeClause(fmt.Sprintf("%s IN (SELECT v.%s FROM %s AS v WHERE v.%s = %d%s)",
+			idColumn, t.idColumn.GetCol(), t.table.table.GetTable(), customFieldIDColumn, d.ID, where,
+		), args...)
+	}
+
+	wantValues := func(n int) error {
+		if len(c.Value) != n {
+			return fmt.Errorf("custom field %q: %s modifier requires %d value(s)", c.Field, c.Modifier, n)
+		}
+		return nil
+	}
+
+	// regular expressions are matched against the stored values
+	if c.Modifier == models.CriterionModifierMatchesRegex || c.Modifier == models.CriterionModifierNotMatchesRegex {
+		if err := wantValues(1); err != nil {
+			return sqlClause{}, err
+		}
+
+		pattern := fmt.Sprint(c.Value[0])
+		if c.Modifier == models.CriterionModifierNotMatchesRegex {
+			return inValues(" AND "+regexpSQL(ctx, valueColumn, false), pattern).not(), nil
+		}
+		return inValues(" AND "+regexpSQL(ctx, valueColumn, false), pattern), nil
+	}
+
+	values := make([]interface{}, len(c.Value))
+	for i, v := range c.Value {
+		converted, err := d.ConvertValue(v)
+		if err != nil {
+			return sqlClause{}, err
+		}
+		values[i] = customFieldDBValue(converted)
+	}
+
+	switch c.Modifier {
+	case models.CriterionModifierIsNull:
+		return inValues("").not(), nil
+	case models.CriterionModifierNotNull:
+		return inValues(""), nil
+	case models.CriterionModifierEquals, models.CriterionModifierNotEquals:
+		if err := wantValues(1); err != nil {
+			return sqlClause{}, err
+		}
+
+		ret := inValues(" AND "+valueColumn+" = ?", values[0])
+		if c.Modifier == models.CriterionModifierNotEquals {
+			ret = ret.not()
+		}
+		return ret, nil
+	case models.CriterionModifierIncludes, models.CriterionModifierExcludes:
+		if len(values) == 0 {
+			return sqlClause{}, fmt.Errorf("custom field %q: %s modifier requires a value", c.Field, c.Modifier)
+		}
+
+		var ret sqlClause
+		if d.Type == models.CustomFieldTypeString {
+			// strings include a substring
+			if err := wantValues(1); err != nil {
+				return sqlClause{}, err
+			}
+			ret = inValues(" AND "+likeSQL(ctx, valueColumn, false), "%"+values[0].(string)+"%")
+		} else {
+			// other types include one of the values
+			ret = inValues(" AND "+valueColumn+" IN "+getInBinding(len(values)), values...)
+		}
+
+		if c.Modifier == models.CriterionModifierExcludes {
+			ret = ret.not()
+		}
+		return ret, nil
+	case models.CriterionModifierGreaterThan, models.CriterionModifierLessThan:
+		if err := wantValues(1); err != nil {
+			return sqlClause{}, err
+		}
+
+		op := ">"
+		if c.Modifier == models.CriterionModifierLessThan {
+			op = "<"
+		}
+		return inValues(" AND "+valueColumn+" "+op+" ?", values[0]), nil
+	case models.CriterionModifierBetween, models.CriterionModifierNotBetween:
+		if err := wantValues(2); err != nil {
+			return sqlClause{}, err
+		}
+
+		ret := inValues(" AND "+valueColumn+" BETWEEN ? AND ?", values[0], values[1])
+		if c.Modifier == models.CriterionModifierNotBetween {
+			ret = ret.not()
+		}
+		return ret, nil
+	}
+
+	return sqlClause{}, fmt.Errorf("custom field %q: unsupported modifier %s", c.Field, c.Modifier)
+}
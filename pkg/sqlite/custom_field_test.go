@@ -0,0 +1,264 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createCustomFieldDefinitions(ctx context.Context, entityType models.CustomFieldEntityType) error {
+	definitions := []models.CustomFieldDefinition{
+		{Name: "source", Type: models.CustomFieldTypeString},
+		{Name: "count", Type: models.CustomFieldTypeInt},
+		{Name: "verified", Type: models.CustomFieldTypeBool},
+		{Name: "quality", Type: models.CustomFieldTypeEnum, Options: []string{"low", "high"}},
+	}
+
+	for _, d := range definitions {
+		newDefinition := models.NewCustomFieldDefinition()
+		newDefinition.EntityType = entityType
+		newDefinition.Name = d.Name
+		newDefinition.Type = d.Type
+		newDefinition.Options = d.Options
+
+		if err := db.CustomField.Create(ctx, &newDefinition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestCustomFieldDefinitionCreateFind(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := createCustomFieldDefinitions(ctx, models.CustomFieldEntityTypePerformer); err != nil {
+			t.Errorf("error creating custom fields: %v", err)
+			return nil
+		}
+
+		got, err := db.CustomField.FindByName(ctx, models.CustomFieldEntityTypePerformer, "quality")
+		if err != nil {
+			t.Errorf("CustomFieldStore.FindByName() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(t, models.CustomFieldTypeEnum, got.Type)
+		assert.Equal(t, []string{"low", "high"}, got.Options)
+
+		got, err = db.CustomField.FindByName(ctx, models.CustomFieldEntityTypeScene, "quality")
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		all, err := db.CustomField.FindByEntityType(ctx, models.CustomFieldEntityTypePerformer)
+		assert.NoError(t, err)
+		assert.Len(t, all, 4)
+
+		return nil
+	})
+}
+
+func TestPerformerSetCustomFields(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := createCustomFieldDefinitions(ctx, models.CustomFieldEntityTypePerformer); err != nil {
+			t.Errorf("error creating custom fields: %v", err)
+			return nil
+		}
+
+		qb := db.Performer
+		id := performerIDs[performerIdxWithScene]
+
+		if err := qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: map[string]interface{}{
+				"source":   "web",
+				"count":    float64(3),
+				"verified": true,
+				"quality":  "high",
+			},
+		}); err != nil {
+			t.Errorf("PerformerStore.SetCustomFields() error = %v", err)
+			return nil
+		}
+
+		got, err := qb.GetCustomFields(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"source":   "web",
+			"count":    int64(3),
+			"verified": true,
+			"quality":  "high",
+		}, got)
+
+		if err := qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Partial: map[string]interface{}{
+				"count": 4,
+			},
+			Remove: []string{"source"},
+		}); err != nil {
+			t.Errorf("PerformerStore.SetCustomFields() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.GetCustomFields(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"count":    int64(4),
+			"verified": true,
+			"quality":  "high",
+		}, got)
+
+		// undefined fields and invalid values are rejected
+		assert.Error(t, qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Partial: map[string]interface{}{"undefined": "foo"},
+		}))
+		assert.Error(t, qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Partial: map[string]interface{}{"quality": "medium"},
+		}))
+
+		// fields of other entity types are not defined
+		assert.Error(t, db.Scene.SetCustomFields(ctx, sceneIDs[sceneIdxWithGallery], models.CustomFieldsInput{
+			Partial: map[string]interface{}{"source": "web"},
+		}))
+
+		return nil
+	})
+}
+
+func TestPerformerQueryCustomFields(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := createCustomFieldDefinitions(ctx, models.CustomFieldEntityTypePerformer); err != nil {
+			t.Errorf("error creating custom fields: %v", err)
+			return nil
+		}
+
+		qb := db.Performer
+		ids := []int{
+			performerIDs[performerIdxWithScene],
+			performerIDs[performerIdx1WithScene],
+			performerIDs[performerIdx2WithScene],
+		}
+
+		for i, id := range ids {
+			if err := qb.SetCustomFields(ctx, id, models.CustomFieldsInput{
+				Full: map[string]interface{}{
+					"source":   "source " + string(rune('a'+i)),
+					"count":    i * 10,
+					"verified": i != 1,
+				},
+			}); err != nil {
+				t.Errorf("PerformerStore.SetCustomFields() error = %v", err)
+				return nil
+			}
+		}
+
+		query := func(c models.CustomFieldCriterionInput) []int {
+			performers, _, err := qb.Query(ctx, &models.PerformerFilterType{
+				CustomFields: []models.CustomFieldCriterionInput{c},
+			}, nil)
+			if err != nil {
+				t.Errorf("PerformerStore.Query() error = %v", err)
+				return nil
+			}
+
+			var ret []int
+			for _, p := range performers {
+				ret = append(ret, p.ID)
+			}
+			return ret
+		}
+
+		assert.ElementsMatch(t, []int{ids[1]}, query(models.CustomFieldCriterionInput{
+			Field: "source", Value: []interface{}{"source b"}, Modifier: models.CriterionModifierEquals,
+		}))
+		assert.ElementsMatch(t, ids, query(models.CustomFieldCriterionInput{
+			Field: "source", Value: []interface{}{"source"}, Modifier: models.CriterionModifierIncludes,
+		}))
+		assert.ElementsMatch(t, []int{ids[1], ids[2]}, query(models.CustomFieldCriterionInput{
+			Field: "count", Value: []interface{}{5}, Modifier: models.CriterionModifierGreaterThan,
+		}))
+		assert.ElementsMatch(t, []int{ids[0], ids[1]}, query(models.CustomFieldCriterionInput{
+			Field: "count", Value: []interface{}{0, 10}, Modifier: models.CriterionModifierBetween,
+		}))
+		assert.ElementsMatch(t, []int{ids[0], ids[2]}, query(models.CustomFieldCriterionInput{
+			Field: "verified", Value: []interface{}{true}, Modifier: models.CriterionModifierEquals,
+		}))
+		assert.ElementsMatch(t, ids, query(models.CustomFieldCriterionInput{
+			Field: "count", Modifier: models.CriterionModifierNotNull,
+		}))
+
+		notNull := query(models.CustomFieldCriterionInput{
+			Field: "count", Modifier: models.CriterionModifierIsNull,
+		})
+		assert.NotContains(t, notNull, ids[0])
+		assert.NotEmpty(t, notNull)
+
+		_, _, err := qb.Query(ctx, &models.PerformerFilterType{
+			CustomFields: []models.CustomFieldCriterionInput{
+				{Field: "undefined", Modifier: models.CriterionModifierNotNull},
+			},
+		}, nil)
+		assert.Error(t, err)
+
+		// sort by custom field
+		sort := "custom_fields.count"
+		direction := models.SortDirectionEnumDesc
+		performers, _, err := qb.Query(ctx, &models.PerformerFilterType{
+			CustomFields: []models.CustomFieldCriterionInput{
+				{Field: "count", Modifier: models.CriterionModifierNotNull},
+			},
+		}, &models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		})
+		if err != nil {
+			t.Errorf("PerformerStore.Query() error = %v", err)
+			return nil
+		}
+
+		var got []int
+		for _, p := range performers {
+			got = append(got, p.ID)
+		}
+		assert.Equal(t, []int{ids[2], ids[1], ids[0]}, got)
+
+		return nil
+	})
+}
+
+func TestCustomFieldDefinitionDestroy(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := createCustomFieldDefinitions(ctx, models.CustomFieldEntityTypeScene); err != nil {
+			t.Errorf("error creating custom fields: %v", err)
+			return nil
+		}
+
+		id := sceneIDs[sceneIdxWithGallery]
+		if err := db.Scene.SetCustomFields(ctx, id, models.CustomFieldsInput{
+			Full: map[string]interface{}{"source": "web"},
+		}); err != nil {
+			t.Errorf("SceneStore.SetCustomFields() error = %v", err)
+			return nil
+		}
+
+		d, err := db.CustomField.FindByName(ctx, models.CustomFieldEntityTypeScene, "source")
+		if err != nil {
+			t.Errorf("CustomFieldStore.FindByName() error = %v", err)
+			return nil
+		}
+
+		if err := db.CustomField.Destroy(ctx, d.ID); err != nil {
+			t.Errorf("CustomFieldStore.Destroy() error = %v", err)
+			return nil
+		}
+
+		got, err := db.Scene.GetCustomFields(ctx, id)
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+
+		return nil
+	})
+}
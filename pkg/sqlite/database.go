@@ -14,6 +14,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 const (
@@ -25,6 +26,9 @@ const (
 	// but have diminishing returns.
 	// 10 was found to be a good tradeoff.
 	maxReadConnections = 10
+	// Number of connections to use for each pool when using PostgreSQL, which
+	// is not limited to a single writer.
+	maxPostgresConnections = 10
 	// Idle connection timeout, in seconds
 	// Closes a connection after a period of inactivity, which saves on memory and
 	// causes the sqlite -wal and -shm files to be automatically deleted.
@@ -34,15 +38,27 @@ const (
 	cacheSizeEnv = "STASH_SQLITE_CACHE_SIZE"
 )
 
-var appSchemaVersion uint = 70
+var appSchemaVersion uint = 77
 
 //go:embed migrations/*.sql
 var migrationsBox embed.FS
 
+// postgresInitialSchemaVersion is the schema version of the first PostgreSQL
+// migration, which creates the schema as of that version.
+const postgresInitialSchemaVersion uint = 75
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrationsBox embed.FS
+
 var (
 	// ErrDatabaseNotInitialized indicates that the database is not
 	// initialized, usually due to an incomplete configuration.
 	ErrDatabaseNotInitialized = errors.New("database not initialized")
+
+	// ErrNotSupportedByPostgres indicates that the operation is only
+	// supported by SQLite databases. PostgreSQL databases should be managed
+	// with the PostgreSQL tools, such as pg_dump.
+	ErrNotSupportedByPostgres = errors.New("not supported by PostgreSQL databases")
 )
 
 // ErrMigrationNeeded indicates that a database migration is needed
@@ -79,6 +95,10 @@ type storeRepository struct {
 	Studio         *StudioStore
 	Tag            *TagStore
 	Group          *GroupStore
+	Trash          *TrashStore
+	OfflinePath    *OfflinePathStore
+	CustomField    *CustomFieldStore
+	ChangeLog      *ChangeLogStore
 }
 
 type Database struct {
@@ -87,6 +107,7 @@ type Database struct {
 	readDB  *sqlx.DB
 	writeDB *sqlx.DB
 	dbPath  string
+	dbType  DatabaseType
 
 	schemaVersion uint
 
@@ -117,10 +138,15 @@ func NewDatabase() *Database {
 		Tag:            tagStore,
 		Group:          NewGroupStore(blobStore),
 		SavedFilter:    NewSavedFilterStore(),
+		Trash:          NewTrashStore(),
+		OfflinePath:    NewOfflinePathStore(),
+		CustomField:    NewCustomFieldStore(),
+		ChangeLog:      NewChangeLogStore(),
 	}
 
 	ret := &Database{
 		storeRepository: r,
+		dbType:          SqliteBackend,
 		lockChan:        make(chan struct{}, 1),
 	}
 
@@ -149,6 +175,13 @@ func (db *Database) Open(dbPath string) error {
 	defer db.unlock()
 
 	db.dbPath = dbPath
+	db.dbType = databaseTypeFor(dbPath)
+
+	if db.dbType == SqliteBackend {
+		if err := db.applyPendingRestore(); err != nil {
+			return err
+		}
+	}
 
 	databaseSchemaVersion, err := db.getDatabaseSchemaVersion()
 	if err != nil {
@@ -235,6 +268,17 @@ func (db *Database) Close() error {
 }
 
 func (db *Database) open(disableForeignKeys bool, writable bool) (*sqlx.DB, error) {
+	if db.dbType == PostgresBackend {
+		// foreign keys are always enforced, and read-only transactions are
+		// enforced by Begin
+		conn, err := sqlx.Open(postgresDriver, db.dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("db.Open(): %w", err)
+		}
+
+		return conn, nil
+	}
+
 	// https://github.com/mattn/go-sqlite3
 	url := "file:" + db.dbPath + "?_journal=WAL&_sync=NORMAL&_busy_timeout=50"
 	if !disableForeignKeys {
@@ -279,6 +323,9 @@ func (db *Database) openReadDB() error {
 	)
 	var err error
 	db.readDB, err = db.open(disableForeignKeys, writable)
+	if err != nil {
+		return err
+	}
 	db.readDB.SetMaxOpenConns(maxReadConnections)
 	db.readDB.SetMaxIdleConns(maxReadConnections)
 	db.readDB.SetConnMaxIdleTime(dbConnTimeout)
@@ -292,14 +339,27 @@ func (db *Database) openWriteDB() error {
 	)
 	var err error
 	db.writeDB, err = db.open(disableForeignKeys, writable)
-	db.writeDB.SetMaxOpenConns(maxWriteConnections)
-	db.writeDB.SetMaxIdleConns(maxWriteConnections)
+	if err != nil {
+		return err
+	}
+
+	maxConnections := maxWriteConnections
+	if db.dbType == PostgresBackend {
+		maxConnections = maxPostgresConnections
+	}
+	db.writeDB.SetMaxOpenConns(maxConnections)
+	db.writeDB.SetMaxIdleConns(maxConnections)
 	db.writeDB.SetConnMaxIdleTime(dbConnTimeout)
 	return err
 }
 
 func (db *Database) Remove() error {
 	databasePath := db.dbPath
+
+	if db.dbType == PostgresBackend {
+		return db.removePostgres()
+	}
+
 	err := db.Close()
 
 	if err != nil {
@@ -325,6 +385,26 @@ func (db *Database) Remove() error {
 	return nil
 }
 
+// removePostgres drops all objects in the PostgreSQL database, since the
+// database itself cannot be dropped while connected to it.
+func (db *Database) removePostgres() error {
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("error closing database: %w", err)
+	}
+
+	conn, err := db.open(false, true)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("DROP SCHEMA public CASCADE; CREATE SCHEMA public"); err != nil {
+		return fmt.Errorf("error removing database: %w", err)
+	}
+
+	return nil
+}
+
 func (db *Database) Reset() error {
 	databasePath := db.dbPath
 	if err := db.Remove(); err != nil {
@@ -341,6 +421,10 @@ func (db *Database) Reset() error {
 // Backup the database. If db is nil, then uses the existing database
 // connection.
 func (db *Database) Backup(backupPath string) (err error) {
+	if db.dbType == PostgresBackend {
+		return ErrNotSupportedByPostgres
+	}
+
 	thisDB := db.writeDB
 	if thisDB == nil {
 		thisDB, err = sqlx.Connect(sqlite3Driver, "file:"+db.dbPath+"?_fk=true")
@@ -360,6 +444,10 @@ func (db *Database) Backup(backupPath string) (err error) {
 }
 
 func (db *Database) Anonymise(outPath string) error {
+	if db.dbType == PostgresBackend {
+		return ErrNotSupportedByPostgres
+	}
+
 	anon, err := NewAnonymiser(db, outPath)
 
 	if err != nil {
@@ -370,6 +458,10 @@ func (db *Database) Anonymise(outPath string) error {
 }
 
 func (db *Database) RestoreFromBackup(backupPath string) error {
+	if db.dbType == PostgresBackend {
+		return ErrNotSupportedByPostgres
+	}
+
 	logger.Infof("Restoring backup database %s into %s", backupPath, db.dbPath)
 	return os.Rename(backupPath, db.dbPath)
 }
@@ -378,8 +470,16 @@ func (db *Database) AppSchemaVersion() uint {
 	return appSchemaVersion
 }
 
+// DatabasePath returns the path of the SQLite database file, or the URL of
+// the PostgreSQL database without its password.
 func (db *Database) DatabasePath() string {
-	return db.dbPath
+	ret, _ := utils.SplitURLPassword(db.dbPath)
+	return ret
+}
+
+// Type returns the type of the database.
+func (db *Database) Type() DatabaseType {
+	return db.dbType
 }
 
 func (db *Database) DatabaseBackupPath(backupDirectoryPath string) string {
@@ -0,0 +1,358 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+)
+
+// DatabaseType is the type of database used to store the repository.
+type DatabaseType string
+
+const (
+	SqliteBackend   DatabaseType = "SQLITE"
+	PostgresBackend DatabaseType = "POSTGRES"
+)
+
+const postgresDriver = "postgres"
+
+var (
+	sqliteDialect   = goqu.Dialect("sqlite3")
+	postgresDialect = goqu.Dialect("postgres")
+)
+
+// IsPostgresURL returns true if the database path is a PostgreSQL
+// connection URL rather than the path of an SQLite database file.
+func IsPostgresURL(dbPath string) bool {
+	return strings.HasPrefix(dbPath, "postgres://") || strings.HasPrefix(dbPath, "postgresql://")
+}
+
+func databaseTypeFor(dbPath string) DatabaseType {
+	if IsPostgresURL(dbPath) {
+		return PostgresBackend
+	}
+
+	return SqliteBackend
+}
+
+// withDatabaseType returns a context in which queries are generated for the
+// type of database.
+func withDatabaseType(ctx context.Context, t DatabaseType) context.Context {
+	return context.WithValue(ctx, dbTypeKey, t)
+}
+
+// databaseType returns the type of the database of the transaction in ctx.
+// The type is set by Database.Begin and Database.WithDatabase, so that
+// databases of different types may be open at the same time.
+func databaseType(ctx context.Context) DatabaseType {
+	if t, ok := ctx.Value(dbTypeKey).(DatabaseType); ok {
+		return t
+	}
+
+	return SqliteBackend
+}
+
+func isPostgres(ctx context.Context) bool {
+	return databaseType(ctx) == PostgresBackend
+}
+
+// dialect returns the goqu dialect of the database of the transaction in ctx.
+func dialect(ctx context.Context) goqu.DialectWrapper {
+	if isPostgres(ctx) {
+		return postgresDialect
+	}
+
+	return sqliteDialect
+}
+
+// rebind converts a query using ? placeholders and backtick-quoted
+// identifiers to the syntax of the open database. Numbered placeholders
+// such as ?1 are converted to the equivalent $1. Placeholders in string
+// literals are not converted.
+func rebind(ctx context.Context, query string) string {
+	if !isPostgres(ctx) {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+
+	n := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			inString = !inString
+			b.WriteByte(c)
+		case inString:
+			b.WriteByte(c)
+		case c == '`':
+			b.WriteByte('"')
+		case c == '?':
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+
+			b.WriteByte('$')
+			if j > i+1 {
+				b.WriteString(query[i+1 : j])
+				i = j - 1
+			} else {
+				n++
+				b.WriteString(strconv.Itoa(n))
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// likeSQL returns a case-insensitive LIKE clause matching the expression
+// against a single argument.
+func likeSQL(ctx context.Context, expr string, not bool) string {
+	op := "LIKE"
+	if isPostgres(ctx) {
+		op = "ILIKE"
+	}
+
+	if not {
+		op = "NOT " + op
+	}
+
+	return expr + " " + op + " ?"
+}
+
+// regexpSQL returns a clause matching the expression against a regular
+// expression argument.
+func regexpSQL(ctx context.Context, expr string, not bool) string {
+	var op string
+	switch {
+	case isPostgres(ctx) && not:
+		op = "!~"
+	case isPostgres(ctx):
+		op = "~"
+	case not:
+		op = "NOT regexp"
+	default:
+		op = "regexp"
+	}
+
+	return expr + " " + op + " ?"
+}
+
+// phashDistanceSQL returns the expression of the distance between the phash
+// fingerprint column and a phash argument. Fingerprints are stored as text
+// on PostgreSQL.
+func phashDistanceSQL(ctx context.Context, column string) string {
+	if isPostgres(ctx) {
+		return "phash_distance(CAST(" + column + " AS BIGINT), ?)"
+	}
+
+	return "phash_distance(" + column + ", ?)"
+}
+
+// isIntegerSQL returns a clause that is true if the fingerprint column holds
+// an integer.
+func isIntegerSQL(ctx context.Context, column string) string {
+	if isPostgres(ctx) {
+		return column + " ~ '^-?[0-9]+$'"
+	}
+
+	return "typeof(" + column + ") = 'integer'"
+}
+
+// yearSQL returns the expression of the year of a date column.
+func yearSQL(ctx context.Context, column string) string {
+	if isPostgres(ctx) {
+		return "CAST(EXTRACT(YEAR FROM " + column + ") AS INTEGER)"
+	}
+
+	return "cast(strftime('%Y', " + column + ") as int)"
+}
+
+// ageSQL returns the expression of the age in years at date of someone born
+// on birthdate.
+func ageSQL(ctx context.Context, date string, birthdate string) string {
+	if isPostgres(ctx) {
+		return fmt.Sprintf("CAST(EXTRACT(YEAR FROM age(%s, %s)) AS INTEGER)", date, birthdate)
+	}
+
+	return fmt.Sprintf("cast(strftime('%%Y.%%m%%d', %s) - strftime('%%Y.%%m%%d', %s) as int)", date, birthdate)
+}
+
+// leastSQL returns the expression of the smallest of two values.
+func leastSQL(ctx context.Context, a string, b string) string {
+	if isPostgres(ctx) {
+		return fmt.Sprintf("LEAST(%s, %s)", a, b)
+	}
+
+	return fmt.Sprintf("MIN(%s, %s)", a, b)
+}
+
+// groupConcatSQL returns the aggregate expression joining the values of the
+// expression with commas.
+func groupConcatSQL(ctx context.Context, expr string, distinct bool) string {
+	d := ""
+	if distinct {
+		d = "DISTINCT "
+	}
+
+	if isPostgres(ctx) {
+		return fmt.Sprintf("string_agg(%sCAST(%s AS TEXT), ',')", d, expr)
+	}
+
+	return fmt.Sprintf("GROUP_CONCAT(%s%s)", d, expr)
+}
+
+// coalesceIDSQL returns the expression of the column, or the id column if
+// the column is null. PostgreSQL requires both to be text.
+func coalesceIDSQL(ctx context.Context, column string, idColumn string) string {
+	if isPostgres(ctx) {
+		return fmt.Sprintf("COALESCE(%s, CAST(%s AS TEXT))", column, idColumn)
+	}
+
+	return fmt.Sprintf("COALESCE(%s, %s)", column, idColumn)
+}
+
+// rowIDColumn returns the column identifying a row in a table without a
+// primary key.
+func rowIDColumn(ctx context.Context) string {
+	if isPostgres(ctx) {
+		return "ctid"
+	}
+
+	return "rowid"
+}
+
+// aggregateSort converts a sort and pagination clause to sort by the
+// minimum of each sort expression, so that it may be used in a query grouped
+// by the id of the sorted table. PostgreSQL does not allow sorting a SELECT
+// DISTINCT query by expressions that are not selected.
+func aggregateSort(sortAndPagination string) string {
+	const orderBy = " ORDER BY "
+	i := strings.Index(sortAndPagination, orderBy)
+	if i == -1 {
+		return sortAndPagination
+	}
+
+	sort := sortAndPagination[i+len(orderBy):]
+	pagination := ""
+	if j := topLevelIndex(sort, " LIMIT "); j != -1 {
+		sort, pagination = sort[:j], sort[j:]
+	}
+
+	terms := splitTopLevel(sort, ',')
+	for k, term := range terms {
+		term = strings.TrimSpace(term)
+		expr, direction := term, ""
+		upper := strings.ToUpper(term)
+		for _, d := range []string{" ASC", " DESC"} {
+			if strings.HasSuffix(upper, d) {
+				expr, direction = term[:len(term)-len(d)], term[len(term)-len(d):]
+				break
+			}
+		}
+
+		if !isAggregate(expr) {
+			expr = "MIN(" + expr + ")"
+		}
+
+		terms[k] = expr + direction
+	}
+
+	return sortAndPagination[:i] + orderBy + strings.Join(terms, ", ") + pagination
+}
+
+func isAggregate(expr string) bool {
+	upper := strings.ToUpper(expr)
+	for _, fn := range []string{"COUNT(", "MIN(", "MAX(", "SUM(", "AVG("} {
+		// the function call must be the whole expression
+		if strings.HasPrefix(upper, fn) && topLevelIndex(expr[len(fn):], ")") == len(expr)-len(fn)-1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// topLevelIndex returns the index of the first occurrence of sub in s that
+// is not within parentheses or a string literal, or -1 if there is none. A
+// closing parenthesis without a matching opening parenthesis is at the top
+// level.
+func topLevelIndex(s string, sub string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		if !inString && depth <= 0 && strings.HasPrefix(s[i:], sub) {
+			return i
+		}
+
+		switch c := s[i]; {
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// splitTopLevel splits s by sep, ignoring separators within parentheses or
+// string literals.
+func splitTopLevel(s string, sep byte) []string {
+	var ret []string
+	depth := 0
+	inString := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\'':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			ret = append(ret, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(ret, s[start:])
+}
+
+// withSavepoint runs fn within a savepoint on PostgreSQL, rolling back to the
+// savepoint if fn fails. An error aborts the whole transaction on
+// PostgreSQL, so errors that are expected must be contained in a savepoint.
+// On SQLite, fn is run directly.
+func withSavepoint(ctx context.Context, name string, fn func() error) error {
+	if !isPostgres(ctx) {
+		return fn()
+	}
+
+	if _, err := dbWrapper.Exec(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := dbWrapper.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return fmt.Errorf("%w (rolling back: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	_, err := dbWrapper.Exec(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
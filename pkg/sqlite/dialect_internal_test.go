@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no placeholders", "SELECT 1", "SELECT 1"},
+		{"placeholders", "SELECT * FROM scenes WHERE id = ? AND title = ?", "SELECT * FROM scenes WHERE id = $1 AND title = $2"},
+		{"numbered placeholders", "SELECT ?1, ?2, ?1", "SELECT $1, $2, $1"},
+		{"string literal", "SELECT '?' WHERE a = ?", "SELECT '?' WHERE a = $1"},
+		{"backticks", "SELECT `primary` FROM files WHERE `primary` = ?", `SELECT "primary" FROM files WHERE "primary" = $1`},
+		{"backtick in string", "SELECT '`'", "SELECT '`'"},
+	}
+
+	ctx := withDatabaseType(context.Background(), PostgresBackend)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rebind(ctx, tt.query))
+		})
+	}
+}
+
+func TestRebindSqlite(t *testing.T) {
+	const query = "SELECT `primary` FROM files WHERE id = ?"
+	assert.Equal(t, query, rebind(context.Background(), query))
+}
+
+func TestAggregateSort(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		want string
+	}{
+		{"empty", "", ""},
+		{"column", " ORDER BY scenes.title ASC", " ORDER BY MIN(scenes.title) ASC"},
+		{"columns", " ORDER BY scenes.title ASC, scenes.id DESC", " ORDER BY MIN(scenes.title) ASC, MIN(scenes.id) DESC"},
+		{"no direction", " ORDER BY scenes.title", " ORDER BY MIN(scenes.title)"},
+		{"aggregate", " ORDER BY COUNT(scenes_tags.tag_id) DESC", " ORDER BY COUNT(scenes_tags.tag_id) DESC"},
+		{"function", " ORDER BY COALESCE(scenes.title, 'a, b') ASC", " ORDER BY MIN(COALESCE(scenes.title, 'a, b')) ASC"},
+		{"not whole aggregate", " ORDER BY MIN(a) + MIN(b) ASC", " ORDER BY MIN(MIN(a) + MIN(b)) ASC"},
+		{"pagination", " ORDER BY scenes.title ASC LIMIT 25 OFFSET 50", " ORDER BY MIN(scenes.title) ASC LIMIT 25 OFFSET 50"},
+		{"pagination only", " LIMIT 25", " LIMIT 25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, aggregateSort(tt.sort))
+		})
+	}
+}
@@ -23,6 +23,8 @@ const (
 	imageFileTable = "image_files"
 	fileIDColumn   = "file_id"
 
+	fileIntegrityTable = "file_integrity"
+
 	videoCaptionsTable    = "video_captions"
 	captionCodeColumn     = "language_code"
 	captionFilenameColumn = "filename"
@@ -63,6 +65,7 @@ type videoFileRow struct {
 	BitRate          int64         `db:"bit_rate"`
 	Interactive      bool          `db:"interactive"`
 	InteractiveSpeed null.Int      `db:"interactive_speed"`
+	Chapters         null.String   `db:"chapters"`
 }
 
 func (f *videoFileRow) fromVideoFile(ff models.VideoFile) {
@@ -77,6 +80,28 @@ func (f *videoFileRow) fromVideoFile(ff models.VideoFile) {
 	f.BitRate = ff.BitRate
 	f.Interactive = ff.Interactive
 	f.InteractiveSpeed = intFromPtr(ff.InteractiveSpeed)
+	f.Chapters = encodeChapters(ff.Chapters)
+}
+
+// encodeChapters encodes the chapters of a video file. Nil chapters have not
+// been probed and are stored as NULL, while an empty list is stored so that
+// the file is not probed again.
+func encodeChapters(chapters []models.VideoChapter) null.String {
+	if chapters == nil {
+		return null.String{}
+	}
+
+	return null.StringFrom(encodeJSONOrEmpty(chapters))
+}
+
+func decodeChapters(s null.String) []models.VideoChapter {
+	if !s.Valid {
+		return nil
+	}
+
+	ret := []models.VideoChapter{}
+	decodeJSON(s.String, &ret)
+	return ret
 }
 
 type imageFileRow struct {
@@ -107,6 +132,7 @@ type videoFileQueryRow struct {
 	BitRate          null.Int    `db:"bit_rate"`
 	Interactive      null.Bool   `db:"interactive"`
 	InteractiveSpeed null.Int    `db:"interactive_speed"`
+	Chapters         null.String `db:"chapters"`
 }
 
 func (f *videoFileQueryRow) resolve() *models.VideoFile {
@@ -121,6 +147,7 @@ func (f *videoFileQueryRow) resolve() *models.VideoFile {
 		BitRate:          f.BitRate.Int64,
 		Interactive:      f.Interactive.Bool,
 		InteractiveSpeed: nullIntPtr(f.InteractiveSpeed),
+		Chapters:         decodeChapters(f.Chapters),
 	}
 }
 
@@ -138,6 +165,7 @@ func videoFileQueryColumns() []interface{} {
 		table.Col("bit_rate"),
 		table.Col("interactive"),
 		table.Col("interactive_speed"),
+		table.Col("chapters"),
 	}
 }
 
@@ -438,7 +466,7 @@ func (qb *FileStore) updateOrCreateImageFile(ctx context.Context, id models.File
 	return nil
 }
 
-func (qb *FileStore) selectDataset() *goqu.SelectDataset {
+func (qb *FileStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 
 	folderTable := folderTableMgr.table
@@ -470,7 +498,7 @@ func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 	cols = append(cols, videoFileQueryColumns()...)
 	cols = append(cols, imageFileQueryRow{}.columns(imageFileTableMgr)...)
 
-	ret := dialect.From(table).Select(cols...)
+	ret := dialect(ctx).From(table).Select(cols...)
 
 	return ret.InnerJoin(
 		folderTable,
@@ -493,7 +521,7 @@ func (qb *FileStore) selectDataset() *goqu.SelectDataset {
 	)
 }
 
-func (qb *FileStore) countDataset() *goqu.SelectDataset {
+func (qb *FileStore) countDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 
 	folderTable := folderTableMgr.table
@@ -504,7 +532,7 @@ func (qb *FileStore) countDataset() *goqu.SelectDataset {
 	zipFileTable := table.As("zip_files")
 	zipFolderTable := folderTable.As("zip_files_folders")
 
-	ret := dialect.From(table).Select(goqu.COUNT(goqu.DISTINCT(table.Col("id"))))
+	ret := dialect(ctx).From(table).Select(goqu.COUNT(goqu.DISTINCT(table.Col("id"))))
 
 	return ret.InnerJoin(
 		folderTable,
@@ -577,7 +605,7 @@ func (qb *FileStore) Find(ctx context.Context, ids ...models.FileID) ([]models.F
 }
 
 func (qb *FileStore) find(ctx context.Context, id models.FileID) (models.File, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -618,12 +646,12 @@ func (qb *FileStore) FindAllByPath(ctx context.Context, p string) ([]models.File
 	folderTable := folderTableMgr.table
 
 	// like uses case-insensitive matching. Only use like if wildcards are used
-	q := qb.selectDataset().Prepared(true)
+	q := qb.selectDataset(ctx).Prepared(true)
 
 	if strings.Contains(basename, "%") || strings.Contains(dirName, "%") {
 		q = q.Where(
-			folderTable.Col("path").Like(dirName),
-			table.Col("basename").Like(basename),
+			folderTable.Col("path").ILike(dirName),
+			table.Col("basename").ILike(basename),
 		)
 	} else {
 		q = q.Where(
@@ -647,7 +675,7 @@ func (qb *FileStore) allInPaths(q *goqu.SelectDataset, p []string) *goqu.SelectD
 	for _, pp := range p {
 		ppWildcard := pp + string(filepath.Separator) + "%"
 
-		conds = append(conds, folderTable.Col("path").Eq(pp), folderTable.Col("path").Like(ppWildcard))
+		conds = append(conds, folderTable.Col("path").Eq(pp), folderTable.Col("path").ILike(ppWildcard))
 	}
 
 	return q.Where(
@@ -662,7 +690,7 @@ func (qb *FileStore) FindAllInPaths(ctx context.Context, p []string, limit, offs
 	table := qb.table()
 	folderTable := folderTableMgr.table
 
-	q := dialect.From(table).Prepared(true).InnerJoin(
+	q := dialect(ctx).From(table).Prepared(true).InnerJoin(
 		folderTable,
 		goqu.On(table.Col("parent_folder_id").Eq(folderTable.Col(idColumn))),
 	).Select(table.Col(idColumn))
@@ -686,7 +714,7 @@ func (qb *FileStore) FindAllInPaths(ctx context.Context, p []string, limit, offs
 // CountAllInPaths returns a count of all files that are within any of the given paths.
 // Returns count of all files if p is empty.
 func (qb *FileStore) CountAllInPaths(ctx context.Context, p []string) (int, error) {
-	q := qb.countDataset().Prepared(true)
+	q := qb.countDataset(ctx).Prepared(true)
 	q = qb.allInPaths(q, p)
 
 	return count(ctx, q)
@@ -695,7 +723,7 @@ func (qb *FileStore) CountAllInPaths(ctx context.Context, p []string) (int, erro
 func (qb *FileStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]models.File, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -709,7 +737,7 @@ func (qb *FileStore) FindByFingerprint(ctx context.Context, fp models.Fingerprin
 
 	fingerprints := fingerprintTable.As("fp")
 
-	sq := dialect.From(fingerprints).Select(fingerprints.Col(fileIDColumn)).Where(
+	sq := dialect(ctx).From(fingerprints).Select(fingerprints.Col(fileIDColumn)).Where(
 		fingerprints.Col("type").Eq(fp.Type),
 		fingerprints.Col("fingerprint").Eq(fp.Fingerprint),
 	)
@@ -720,7 +748,7 @@ func (qb *FileStore) FindByFingerprint(ctx context.Context, fp models.Fingerprin
 func (qb *FileStore) FindByZipFileID(ctx context.Context, zipFileID models.FileID) ([]models.File, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col("zip_file_id").Eq(zipFileID),
 	)
 
@@ -733,7 +761,7 @@ func (qb *FileStore) FindByFileInfo(ctx context.Context, info fs.FileInfo, size
 
 	modTime := info.ModTime().Format(time.RFC3339)
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col("basename").Eq(info.Name()),
 		table.Col("size").Eq(size),
 		table.Col("mod_time").Eq(modTime),
@@ -745,7 +773,7 @@ func (qb *FileStore) FindByFileInfo(ctx context.Context, info fs.FileInfo, size
 func (qb *FileStore) CountByFolderID(ctx context.Context, folderID models.FolderID) (int, error) {
 	table := qb.table()
 
-	q := qb.countDataset().Prepared(true).Where(
+	q := qb.countDataset(ctx).Prepared(true).Where(
 		table.Col("parent_folder_id").Eq(folderID),
 	)
 
@@ -762,9 +790,9 @@ func (qb *FileStore) IsPrimary(ctx context.Context, fileID models.FileID) (bool,
 	var sq *goqu.SelectDataset
 
 	for _, t := range joinTables {
-		qq := dialect.From(t).Select(t.Col(fileIDColumn)).Where(
+		qq := dialect(ctx).From(t).Select(t.Col(fileIDColumn)).Where(
 			t.Col(fileIDColumn).Eq(fileID),
-			t.Col("primary").Eq(1),
+			t.Col("primary").Eq(true),
 		)
 
 		if sq == nil {
@@ -774,7 +802,7 @@ func (qb *FileStore) IsPrimary(ctx context.Context, fileID models.FileID) (bool,
 		}
 	}
 
-	q := dialect.Select(goqu.COUNT("*").As("count")).Prepared(true).From(
+	q := dialect(ctx).Select(goqu.COUNT("*").As("count")).Prepared(true).From(
 		sq,
 	)
 
@@ -831,10 +859,47 @@ func (qb *FileStore) makeFilter(ctx context.Context, fileFilter *models.FileFilt
 	}
 
 	query.handleCriterion(ctx, pathCriterionHandler(fileFilter.Path, "folders.path", "files.basename", nil))
+	query.handleCriterion(ctx, fileIntegrityIssueCriterionHandler(fileFilter.IntegrityIssue))
+	query.handleCriterion(ctx, isOfflineCriterionHandler(fileFilter.IsOffline, "files.id", "SELECT files.id FROM files INNER JOIN folders ON folders.id = files.parent_folder_id"))
 
 	return query
 }
 
+func fileIntegrityIssueCriterionHandler(c *models.FileIntegrityIssueCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		const anyIssue = "files.id IN (SELECT file_id FROM " + fileIntegrityTable + " WHERE issue_type IS NOT NULL)"
+
+		switch c.Modifier {
+		case models.CriterionModifierIsNull:
+			f.addWhere("NOT " + anyIssue)
+		case models.CriterionModifierNotNull:
+			f.addWhere(anyIssue)
+		case models.CriterionModifierIncludes, models.CriterionModifierExcludes:
+			if len(c.Value) == 0 {
+				return
+			}
+
+			var args []interface{}
+			for _, v := range c.Value {
+				args = append(args, v.String())
+			}
+
+			clause := fmt.Sprintf("files.id IN (SELECT file_id FROM %s WHERE issue_type IN %s)", fileIntegrityTable, getInBinding(len(args)))
+			if c.Modifier == models.CriterionModifierExcludes {
+				clause = "NOT " + clause
+			}
+
+			f.addWhere(clause, args...)
+		default:
+			f.setError(fmt.Errorf("invalid modifier %s for integrity issue criterion", c.Modifier))
+		}
+	}
+}
+
 func (qb *FileStore) Query(ctx context.Context, options models.FileQueryOptions) (*models.FileQueryResult, error) {
 	fileFilter := options.FileFilter
 	findFilter := options.FindFilter
@@ -854,7 +919,7 @@ func (qb *FileStore) Query(ctx context.Context, options models.FileQueryOptions)
 	if q := findFilter.Q; q != nil && *q != "" {
 		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
 		searchColumns := []string{filepathColumn}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	if err := qb.validateFilter(fileFilter); err != nil {
@@ -866,7 +931,7 @@ func (qb *FileStore) Query(ctx context.Context, options models.FileQueryOptions)
 		return nil, err
 	}
 
-	if err := qb.setQuerySort(&query, findFilter); err != nil {
+	if err := qb.setQuerySort(ctx, &query, findFilter); err != nil {
 		return nil, err
 	}
 	query.sortAndPagination += getPagination(findFilter)
@@ -902,12 +967,12 @@ func (qb *FileStore) queryGroupedFields(ctx context.Context, options models.File
 	}
 
 	const includeSortPagination = false
-	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(includeSortPagination))
+	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(ctx, includeSortPagination))
 
 	out := struct {
 		Total int
 	}{}
-	if err := qb.repository.queryStruct(ctx, aggregateQuery.toSQL(includeSortPagination), query.args, &out); err != nil {
+	if err := qb.repository.queryStruct(ctx, aggregateQuery.toSQL(ctx, includeSortPagination), query.args, &out); err != nil {
 		return nil, err
 	}
 
@@ -923,9 +988,10 @@ var fileSortOptions = sortOptions{
 	"path",
 	"random",
 	"updated_at",
+	"verified_at",
 }
 
-func (qb *FileStore) setQuerySort(query *queryBuilder, findFilter *models.FindFilterType) error {
+func (qb *FileStore) setQuerySort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) error {
 	if findFilter == nil || findFilter.Sort == nil || *findFilter.Sort == "" {
 		return nil
 	}
@@ -941,8 +1007,12 @@ func (qb *FileStore) setQuerySort(query *queryBuilder, findFilter *models.FindFi
 	case "path":
 		// special handling for path
 		query.sortAndPagination += fmt.Sprintf(" ORDER BY folders.path %s, files.basename %[1]s", direction)
+	case "verified_at":
+		// files that have never been verified sort first in ascending order
+		query.join(fileIntegrityTable, "", "file_integrity.file_id = files.id")
+		query.sortAndPagination += fmt.Sprintf(" ORDER BY file_integrity.verified_at %s, files.id %[1]s", direction)
 	default:
-		query.sortAndPagination += getSort(sort, direction, "files")
+		query.sortAndPagination += getSort(ctx, sort, direction, "files")
 	}
 
 	return nil
@@ -964,3 +1034,63 @@ func (qb *FileStore) GetCaptions(ctx context.Context, fileID models.FileID) ([]*
 func (qb *FileStore) UpdateCaptions(ctx context.Context, fileID models.FileID, captions []*models.VideoCaption) error {
 	return qb.captionRepository().replace(ctx, fileID, captions)
 }
+
+type fileIntegrityRow struct {
+	FileID       models.FileID `db:"file_id"`
+	VerifiedAt   Timestamp     `db:"verified_at"`
+	IssueType    null.String   `db:"issue_type"`
+	IssueMessage null.String   `db:"issue_message"`
+}
+
+func (r *fileIntegrityRow) fromFileIntegrity(o models.FileIntegrity) {
+	r.FileID = o.FileID
+	r.VerifiedAt = Timestamp{Timestamp: o.VerifiedAt}
+	if o.Issue != nil {
+		r.IssueType = null.StringFrom(o.Issue.Type.String())
+		r.IssueMessage = null.StringFrom(o.Issue.Message)
+	}
+}
+
+func (qb *FileStore) GetIntegrityIssue(ctx context.Context, fileID models.FileID) (*models.FileIntegrityIssue, error) {
+	table := fileIntegrityTableMgr.table
+	q := dialect(ctx).From(table).Prepared(true).Select(table.All()).Where(
+		table.Col(fileIDColumn).Eq(fileID),
+		table.Col("issue_type").IsNotNull(),
+	)
+
+	var ret *models.FileIntegrityIssue
+	const single = true
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var r fileIntegrityRow
+		if err := rows.StructScan(&r); err != nil {
+			return err
+		}
+
+		ret = &models.FileIntegrityIssue{
+			Type:       models.FileIntegrityIssueType(r.IssueType.String),
+			Message:    r.IssueMessage.String,
+			DetectedAt: r.VerifiedAt.Timestamp,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *FileStore) SetIntegrity(ctx context.Context, integrity models.FileIntegrity) error {
+	if integrity.Issue != nil && !integrity.Issue.Type.IsValid() {
+		return fmt.Errorf("invalid integrity issue type: %s", integrity.Issue.Type)
+	}
+
+	if err := fileIntegrityTableMgr.destroy(ctx, []int{int(integrity.FileID)}); err != nil {
+		return err
+	}
+
+	var r fileIntegrityRow
+	r.fromFileIntegrity(integrity)
+
+	_, err := fileIntegrityTableMgr.insert(ctx, r)
+	return err
+}
@@ -662,3 +662,107 @@ func TestFileStore_IsPrimary(t *testing.T) {
 		})
 	}
 }
+
+func TestFileStore_Integrity(t *testing.T) {
+	verifiedAt := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	mismatch := &models.FileIntegrityIssue{
+		Type:       models.FileIntegrityIssueTypeHashMismatch,
+		Message:    "mismatch",
+		DetectedAt: verifiedAt,
+	}
+
+	queryIssues := func(ctx context.Context, c *models.FileIntegrityIssueCriterionInput) ([]models.FileID, error) {
+		result, err := db.File.Query(ctx, models.FileQueryOptions{
+			FileFilter: &models.FileFilterType{
+				IntegrityIssue: c,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.IDs, nil
+	}
+
+	runWithRollbackTxn(t, "set and clear issue", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+		qb := db.File
+		id := fileIDs[fileIdxZip]
+		otherID := fileIDs[fileIdxInZip]
+
+		if err := qb.SetIntegrity(ctx, models.FileIntegrity{
+			FileID:     id,
+			VerifiedAt: verifiedAt,
+			Issue:      mismatch,
+		}); err != nil {
+			t.Errorf("FileStore.SetIntegrity() error = %v", err)
+			return
+		}
+
+		if err := qb.SetIntegrity(ctx, models.FileIntegrity{
+			FileID:     otherID,
+			VerifiedAt: verifiedAt,
+		}); err != nil {
+			t.Errorf("FileStore.SetIntegrity() error = %v", err)
+			return
+		}
+
+		got, err := qb.GetIntegrityIssue(ctx, id)
+		if err != nil {
+			t.Errorf("FileStore.GetIntegrityIssue() error = %v", err)
+			return
+		}
+		assert.Equal(mismatch, got)
+
+		got, err = qb.GetIntegrityIssue(ctx, otherID)
+		if err != nil {
+			t.Errorf("FileStore.GetIntegrityIssue() error = %v", err)
+			return
+		}
+		assert.Nil(got)
+
+		ids, err := queryIssues(ctx, &models.FileIntegrityIssueCriterionInput{
+			Modifier: models.CriterionModifierNotNull,
+		})
+		if err != nil {
+			t.Errorf("FileStore.Query() error = %v", err)
+			return
+		}
+		assert.Equal([]models.FileID{id}, ids)
+
+		ids, err = queryIssues(ctx, &models.FileIntegrityIssueCriterionInput{
+			Value:    []models.FileIntegrityIssueType{models.FileIntegrityIssueTypeUnreadable},
+			Modifier: models.CriterionModifierIncludes,
+		})
+		if err != nil {
+			t.Errorf("FileStore.Query() error = %v", err)
+			return
+		}
+		assert.Len(ids, 0)
+
+		ids, err = queryIssues(ctx, &models.FileIntegrityIssueCriterionInput{
+			Modifier: models.CriterionModifierIsNull,
+		})
+		if err != nil {
+			t.Errorf("FileStore.Query() error = %v", err)
+			return
+		}
+		assert.NotContains(ids, id)
+		assert.Contains(ids, otherID)
+
+		// verifying again without an issue clears it
+		if err := qb.SetIntegrity(ctx, models.FileIntegrity{
+			FileID:     id,
+			VerifiedAt: verifiedAt.Add(time.Hour),
+		}); err != nil {
+			t.Errorf("FileStore.SetIntegrity() error = %v", err)
+			return
+		}
+
+		got, err = qb.GetIntegrityIssue(ctx, id)
+		if err != nil {
+			t.Errorf("FileStore.GetIntegrityIssue() error = %v", err)
+			return
+		}
+		assert.Nil(got)
+	})
+}
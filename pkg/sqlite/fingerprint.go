@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
@@ -24,9 +25,22 @@ func (r fingerprintQueryRow) valid() bool {
 }
 
 func (r *fingerprintQueryRow) resolve() models.Fingerprint {
+	fp := r.Fingerprint
+
+	// fingerprints are stored as text on PostgreSQL, whereas SQLite returns
+	// phashes as integers
+	if v, ok := fp.([]byte); ok {
+		fp = string(v)
+	}
+	if v, ok := fp.(string); ok && r.Type.String == models.FingerprintTypePhash {
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			fp = i
+		}
+	}
+
 	return models.Fingerprint{
 		Type:        r.Type.String,
-		Fingerprint: r.Fingerprint,
+		Fingerprint: fp,
 	}
 }
 
@@ -47,7 +61,7 @@ var FingerprintReaderWriter = &fingerprintQueryBuilder{
 
 func (qb *fingerprintQueryBuilder) insert(ctx context.Context, fileID models.FileID, f models.Fingerprint) error {
 	table := qb.table()
-	q := dialect.Insert(table).Cols(fileIDColumn, "type", "fingerprint").Vals(
+	q := dialect(ctx).Insert(table).Cols(fileIDColumn, "type", "fingerprint").Vals(
 		goqu.Vals{fileID, f.Type, f.Fingerprint},
 	)
 	_, err := exec(ctx, q)
@@ -97,7 +111,7 @@ func (qb *fingerprintQueryBuilder) replaceJoins(ctx context.Context, fileID mode
 
 func (qb *fingerprintQueryBuilder) destroyJoins(ctx context.Context, fileID models.FileID, types []string) error {
 	table := qb.table()
-	q := dialect.Delete(table).Where(
+	q := dialect(ctx).Delete(table).Where(
 		table.Col(fileIDColumn).Eq(fileID),
 		table.Col("type").In(types),
 	)
@@ -133,7 +133,7 @@ func (qb *FolderStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *FolderStore) selectDataset() *goqu.SelectDataset {
+func (qb *FolderStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 	fileTable := fileTableMgr.table
 
@@ -154,7 +154,7 @@ func (qb *FolderStore) selectDataset() *goqu.SelectDataset {
 		zipFileTable.Col("size").As("zip_size"),
 	}
 
-	ret := dialect.From(table).Select(cols...)
+	ret := dialect(ctx).From(table).Select(cols...)
 
 	return ret.LeftJoin(
 		zipFileTable,
@@ -165,14 +165,14 @@ func (qb *FolderStore) selectDataset() *goqu.SelectDataset {
 	)
 }
 
-func (qb *FolderStore) countDataset() *goqu.SelectDataset {
+func (qb *FolderStore) countDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 	fileTable := fileTableMgr.table
 
 	zipFileTable := fileTable.As("zip_files")
 	zipFolderTable := table.As("zip_files_folders")
 
-	ret := dialect.From(table).Select(goqu.COUNT(goqu.DISTINCT(table.Col("id"))))
+	ret := dialect(ctx).From(table).Select(goqu.COUNT(goqu.DISTINCT(table.Col("id"))))
 
 	return ret.LeftJoin(
 		zipFileTable,
@@ -215,7 +215,7 @@ func (qb *FolderStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*m
 }
 
 func (qb *FolderStore) Find(ctx context.Context, id models.FolderID) (*models.Folder, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -226,7 +226,7 @@ func (qb *FolderStore) Find(ctx context.Context, id models.FolderID) (*models.Fo
 }
 
 func (qb *FolderStore) FindByPath(ctx context.Context, p string) (*models.Folder, error) {
-	q := qb.selectDataset().Prepared(true).Where(qb.table().Col("path").Eq(p))
+	q := qb.selectDataset(ctx).Prepared(true).Where(qb.table().Col("path").Eq(p))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -237,7 +237,7 @@ func (qb *FolderStore) FindByPath(ctx context.Context, p string) (*models.Folder
 }
 
 func (qb *FolderStore) FindByParentFolderID(ctx context.Context, parentFolderID models.FolderID) ([]*models.Folder, error) {
-	q := qb.selectDataset().Where(qb.table().Col("parent_folder_id").Eq(int(parentFolderID)))
+	q := qb.selectDataset(ctx).Where(qb.table().Col("parent_folder_id").Eq(int(parentFolderID)))
 
 	ret, err := qb.getMany(ctx, q)
 	if err != nil {
@@ -254,7 +254,7 @@ func (qb *FolderStore) allInPaths(q *goqu.SelectDataset, p []string) *goqu.Selec
 	for _, pp := range p {
 		ppWildcard := pp + string(filepath.Separator) + "%"
 
-		conds = append(conds, table.Col("path").Eq(pp), table.Col("path").Like(ppWildcard))
+		conds = append(conds, table.Col("path").Eq(pp), table.Col("path").ILike(ppWildcard))
 	}
 
 	return q.Where(
@@ -266,7 +266,7 @@ func (qb *FolderStore) allInPaths(q *goqu.SelectDataset, p []string) *goqu.Selec
 // Returns all if limit is < 0.
 // Returns all folders if p is empty.
 func (qb *FolderStore) FindAllInPaths(ctx context.Context, p []string, limit, offset int) ([]*models.Folder, error) {
-	q := qb.selectDataset().Prepared(true)
+	q := qb.selectDataset(ctx).Prepared(true)
 	q = qb.allInPaths(q, p)
 
 	if limit > -1 {
@@ -286,7 +286,7 @@ func (qb *FolderStore) FindAllInPaths(ctx context.Context, p []string, limit, of
 // CountAllInPaths returns a count of all folders that are within any of the given paths.
 // Returns count of all folders if p is empty.
 func (qb *FolderStore) CountAllInPaths(ctx context.Context, p []string) (int, error) {
-	q := qb.countDataset().Prepared(true)
+	q := qb.countDataset(ctx).Prepared(true)
 	q = qb.allInPaths(q, p)
 
 	return count(ctx, q)
@@ -307,7 +307,7 @@ func (qb *FolderStore) CountAllInPaths(ctx context.Context, p []string) (int, er
 func (qb *FolderStore) FindByZipFileID(ctx context.Context, zipFileID models.FileID) ([]*models.Folder, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col("zip_file_id").Eq(zipFileID),
 	)
 
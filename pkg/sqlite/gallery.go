@@ -199,17 +199,17 @@ func (qb *GalleryStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *GalleryStore) selectDataset() *goqu.SelectDataset {
+func (qb *GalleryStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 	files := fileTableMgr.table
 	folders := folderTableMgr.table
 	galleryFolder := folderTableMgr.table.As("gallery_folder")
 
-	return dialect.From(table).LeftJoin(
+	return dialect(ctx).From(table).LeftJoin(
 		galleriesFilesJoinTable,
 		goqu.On(
 			galleriesFilesJoinTable.Col(galleryIDColumn).Eq(table.Col(idColumn)),
-			galleriesFilesJoinTable.Col("primary").Eq(1),
+			galleriesFilesJoinTable.Col("primary").Eq(true),
 		),
 	).LeftJoin(
 		files,
@@ -405,7 +405,7 @@ func (qb *GalleryStore) FindMany(ctx context.Context, ids []int) ([]*models.Gall
 	galleries := make([]*models.Gallery, len(ids))
 
 	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
-		q := qb.selectDataset().Prepared(true).Where(qb.table().Col(idColumn).In(batch))
+		q := qb.selectDataset(ctx).Prepared(true).Where(qb.table().Col(idColumn).In(batch))
 		unsorted, err := qb.getMany(ctx, q)
 		if err != nil {
 			return err
@@ -432,7 +432,7 @@ func (qb *GalleryStore) FindMany(ctx context.Context, ids []int) ([]*models.Gall
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *GalleryStore) find(ctx context.Context, id int) (*models.Gallery, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -445,7 +445,7 @@ func (qb *GalleryStore) find(ctx context.Context, id int) (*models.Gallery, erro
 func (qb *GalleryStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Gallery, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -495,7 +495,7 @@ func (qb *GalleryStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*
 }
 
 func (qb *GalleryStore) FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Gallery, error) {
-	sq := dialect.From(galleriesFilesJoinTable).Select(galleriesFilesJoinTable.Col(galleryIDColumn)).Where(
+	sq := dialect(ctx).From(galleriesFilesJoinTable).Select(galleriesFilesJoinTable.Col(galleryIDColumn)).Where(
 		galleriesFilesJoinTable.Col(fileIDColumn).Eq(fileID),
 	)
 
@@ -510,7 +510,7 @@ func (qb *GalleryStore) FindByFileID(ctx context.Context, fileID models.FileID)
 func (qb *GalleryStore) CountByFileID(ctx context.Context, fileID models.FileID) (int, error) {
 	joinTable := galleriesFilesJoinTable
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
 	return count(ctx, q)
 }
 
@@ -526,7 +526,7 @@ func (qb *GalleryStore) FindByFingerprints(ctx context.Context, fp []models.Fing
 		))
 	}
 
-	sq := dialect.From(galleriesFilesJoinTable).
+	sq := dialect(ctx).From(galleriesFilesJoinTable).
 		InnerJoin(
 			fingerprintTable,
 			goqu.On(fingerprintTable.Col(fileIDColumn).Eq(galleriesFilesJoinTable.Col(fileIDColumn))),
@@ -571,7 +571,7 @@ func (qb *GalleryStore) FindByPath(ctx context.Context, p string) ([]*models.Gal
 	basename := filepath.Base(p)
 	dir := filepath.Dir(p)
 
-	sq := dialect.From(table).LeftJoin(
+	sq := dialect(ctx).From(table).LeftJoin(
 		galleriesFilesJoinTable,
 		goqu.On(galleriesFilesJoinTable.Col(galleryIDColumn).Eq(table.Col(idColumn))),
 	).LeftJoin(
@@ -604,7 +604,7 @@ func (qb *GalleryStore) FindByPath(ctx context.Context, p string) ([]*models.Gal
 func (qb *GalleryStore) FindByFolderID(ctx context.Context, folderID models.FolderID) ([]*models.Gallery, error) {
 	table := qb.table()
 
-	sq := dialect.From(table).Select(table.Col(idColumn)).Where(
+	sq := dialect(ctx).From(table).Select(table.Col(idColumn)).Where(
 		table.Col("folder_id").Eq(folderID),
 	)
 
@@ -617,7 +617,7 @@ func (qb *GalleryStore) FindByFolderID(ctx context.Context, folderID models.Fold
 }
 
 func (qb *GalleryStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.Gallery, error) {
-	sq := dialect.From(galleriesScenesJoinTable).Select(galleriesScenesJoinTable.Col(galleryIDColumn)).Where(
+	sq := dialect(ctx).From(galleriesScenesJoinTable).Select(galleriesScenesJoinTable.Col(galleryIDColumn)).Where(
 		galleriesScenesJoinTable.Col(sceneIDColumn).Eq(sceneID),
 	)
 
@@ -630,7 +630,7 @@ func (qb *GalleryStore) FindBySceneID(ctx context.Context, sceneID int) ([]*mode
 }
 
 func (qb *GalleryStore) FindByImageID(ctx context.Context, imageID int) ([]*models.Gallery, error) {
-	sq := dialect.From(galleriesImagesJoinTable).Select(galleriesImagesJoinTable.Col(galleryIDColumn)).Where(
+	sq := dialect(ctx).From(galleriesImagesJoinTable).Select(galleriesImagesJoinTable.Col(galleryIDColumn)).Where(
 		galleriesImagesJoinTable.Col(imageIDColumn).Eq(imageID),
 	)
 
@@ -645,14 +645,14 @@ func (qb *GalleryStore) FindByImageID(ctx context.Context, imageID int) ([]*mode
 func (qb *GalleryStore) CountByImageID(ctx context.Context, imageID int) (int, error) {
 	joinTable := galleriesImagesJoinTable
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(imageIDColumn).Eq(imageID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(imageIDColumn).Eq(imageID))
 	return count(ctx, q)
 }
 
 func (qb *GalleryStore) FindUserGalleryByTitle(ctx context.Context, title string) ([]*models.Gallery, error) {
 	table := qb.table()
 
-	sq := dialect.From(table).LeftJoin(
+	sq := dialect(ctx).From(table).LeftJoin(
 		galleriesFilesJoinTable,
 		goqu.On(galleriesFilesJoinTable.Col(galleryIDColumn).Eq(table.Col(idColumn))),
 	).Select(table.Col(idColumn)).Where(
@@ -670,12 +670,12 @@ func (qb *GalleryStore) FindUserGalleryByTitle(ctx context.Context, title string
 }
 
 func (qb *GalleryStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *GalleryStore) All(ctx context.Context) ([]*models.Gallery, error) {
-	return qb.getMany(ctx, qb.selectDataset())
+	return qb.getMany(ctx, qb.selectDataset(ctx))
 }
 
 func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.GalleryFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
@@ -689,7 +689,8 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 	query := galleryRepository.newQuery()
 	distinctIDs(&query, galleryTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, galleriesFTSTable, "galleries.id", findFilter) {
 		query.addJoins(
 			join{
 				table:    galleriesFilesTable,
@@ -721,7 +722,7 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 		// add joins for files and checksum
 		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
 		searchColumns := []string{"galleries.title", "gallery_folder.path", filepathColumn, "files_fingerprints.fingerprint", "galleries_chapters.title"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &galleryFilterHandler{
@@ -732,7 +733,7 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 		return nil, err
 	}
 
-	if err := qb.setGallerySort(&query, findFilter); err != nil {
+	if err := qb.setGallerySort(ctx, &query, findFilter); err != nil {
 		return nil, err
 	}
 	query.sortAndPagination += getPagination(findFilter)
@@ -779,12 +780,13 @@ var gallerySortOptions = sortOptions{
 	"performer_count",
 	"random",
 	"rating",
+	"relevance",
 	"tag_count",
 	"title",
 	"updated_at",
 }
 
-func (qb *GalleryStore) setGallerySort(query *queryBuilder, findFilter *models.FindFilterType) error {
+func (qb *GalleryStore) setGallerySort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) error {
 	if findFilter == nil || findFilter.Sort == nil || *findFilter.Sort == "" {
 		return nil
 	}
@@ -797,6 +799,8 @@ func (qb *GalleryStore) setGallerySort(query *queryBuilder, findFilter *models.F
 		return err
 	}
 
+	sort = query.relevanceSortOr(sort, "title")
+
 	addFileTable := func() {
 		query.addJoins(
 			join{
@@ -841,17 +845,27 @@ func (qb *GalleryStore) setGallerySort(query *queryBuilder, findFilter *models.F
 	case "file_mod_time":
 		sort = "mod_time"
 		addFileTable()
-		query.sortAndPagination += getSort(sort, direction, fileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, fileTable)
 	case "title":
 		addFileTable()
 		addFolderTable()
 		query.sortAndPagination += " ORDER BY COALESCE(galleries.title, files.basename, basename(COALESCE(folders.path, ''))) COLLATE NATURAL_CI " + direction + ", file_folder.path COLLATE NATURAL_CI " + direction
+	case relevanceSort:
+		query.sortAndPagination += getRelevanceSort(direction)
 	default:
-		query.sortAndPagination += getSort(sort, direction, "galleries")
+		if isCustomFieldSort(sort) {
+			customSort, err := galleriesCustomFieldsTableMgr.sort(ctx, sort, direction, galleryTable)
+			if err != nil {
+				return err
+			}
+			query.sortAndPagination += customSort
+		} else {
+			query.sortAndPagination += getSort(ctx, sort, direction, "galleries")
+		}
 	}
 
 	// Whatever the sorting, always use title/id as a final sort
-	query.sortAndPagination += ", COALESCE(galleries.title, galleries.id) COLLATE NATURAL_CI ASC"
+	query.sortAndPagination += ", " + coalesceIDSQL(ctx, "galleries.title", "galleries.id") + " COLLATE NATURAL_CI ASC"
 
 	return nil
 }
@@ -901,3 +915,11 @@ func (qb *GalleryStore) ResetCover(ctx context.Context, galleryID int) error {
 func (qb *GalleryStore) GetSceneIDs(ctx context.Context, id int) ([]int, error) {
 	return galleryRepository.scenes.getIDs(ctx, id)
 }
+
+func (qb *GalleryStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return galleriesCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *GalleryStore) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	return galleriesCustomFieldsTableMgr.set(ctx, id, input)
+}
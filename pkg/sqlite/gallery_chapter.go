@@ -86,8 +86,8 @@ func (qb *GalleryChapterStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *GalleryChapterStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *GalleryChapterStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *GalleryChapterStore) Create(ctx context.Context, newObject *models.GalleryChapter) error {
@@ -155,7 +155,7 @@ func (qb *GalleryChapterStore) FindMany(ctx context.Context, ids []int) ([]*mode
 	ret := make([]*models.GalleryChapter, len(ids))
 
 	table := qb.table()
-	q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(ids))
+	q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(ids))
 	unsorted, err := qb.getMany(ctx, q)
 	if err != nil {
 		return nil, err
@@ -177,7 +177,7 @@ func (qb *GalleryChapterStore) FindMany(ctx context.Context, ids []int) ([]*mode
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *GalleryChapterStore) find(ctx context.Context, id int) (*models.GalleryChapter, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -88,6 +88,7 @@ func (qb *galleryFilterHandler) criterionHandler() criterionHandler {
 		intCriterionHandler(filter.Rating100, "galleries.rating", nil),
 		qb.urlsCriterionHandler(filter.URL),
 		boolCriterionHandler(filter.Organized, "galleries.organized", nil),
+		isOfflineCriterionHandler(filter.IsOffline, "galleries.id", "SELECT galleries.id FROM galleries LEFT JOIN galleries_files ON galleries_files.gallery_id = galleries.id AND galleries_files.`primary` = TRUE LEFT JOIN files ON files.id = galleries_files.file_id INNER JOIN folders ON folders.id = COALESCE(galleries.folder_id, files.parent_folder_id)"),
 		qb.missingCriterionHandler(filter.IsMissing),
 		qb.tagsCriterionHandler(filter.Tags),
 		qb.tagCountCriterionHandler(filter.TagCount),
@@ -104,6 +105,7 @@ func (qb *galleryFilterHandler) criterionHandler() criterionHandler {
 		&dateCriterionHandler{filter.Date, "galleries.date", nil},
 		&timestampCriterionHandler{filter.CreatedAt, "galleries.created_at", nil},
 		&timestampCriterionHandler{filter.UpdatedAt, "galleries.updated_at", nil},
+		customFieldsCriterionHandler(filter.CustomFields, galleriesCustomFieldsTableMgr, "galleries.id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "scenes_galleries.scene_id",
@@ -190,24 +192,24 @@ func (qb *galleryFilterHandler) pathCriterionHandler(c *models.StringCriterionIn
 			if modifier := c.Modifier; c.Modifier.IsValid() {
 				switch modifier {
 				case models.CriterionModifierIncludes:
-					clause := getPathSearchClauseMany(pathColumn, basenameColumn, c.Value, addWildcards, not)
-					clause2 := getStringSearchClause([]string{folderPathColumn}, c.Value, false)
+					clause := getPathSearchClauseMany(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not)
+					clause2 := getStringSearchClause(ctx, []string{folderPathColumn}, c.Value, false)
 					f.whereClauses = append(f.whereClauses, orClauses(clause, clause2))
 				case models.CriterionModifierExcludes:
 					not = true
-					clause := getPathSearchClauseMany(pathColumn, basenameColumn, c.Value, addWildcards, not)
-					clause2 := getStringSearchClause([]string{folderPathColumn}, c.Value, true)
+					clause := getPathSearchClauseMany(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not)
+					clause2 := getStringSearchClause(ctx, []string{folderPathColumn}, c.Value, true)
 					f.whereClauses = append(f.whereClauses, orClauses(clause, clause2))
 				case models.CriterionModifierEquals:
 					addWildcards = false
-					clause := getPathSearchClause(pathColumn, basenameColumn, c.Value, addWildcards, not)
-					clause2 := makeClause(folderPathColumn+" LIKE ?", c.Value)
+					clause := getPathSearchClause(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not)
+					clause2 := makeClause(likeSQL(ctx, folderPathColumn, false), c.Value)
 					f.whereClauses = append(f.whereClauses, orClauses(clause, clause2))
 				case models.CriterionModifierNotEquals:
 					addWildcards = false
 					not = true
-					clause := getPathSearchClause(pathColumn, basenameColumn, c.Value, addWildcards, not)
-					clause2 := makeClause(folderPathColumn+" NOT LIKE ?", c.Value)
+					clause := getPathSearchClause(ctx, pathColumn, basenameColumn, c.Value, addWildcards, not)
+					clause2 := makeClause(likeSQL(ctx, folderPathColumn, true), c.Value)
 					f.whereClauses = append(f.whereClauses, orClauses(clause, clause2))
 				case models.CriterionModifierMatchesRegex:
 					if _, err := regexp.Compile(c.Value); err != nil {
@@ -215,8 +217,8 @@ func (qb *galleryFilterHandler) pathCriterionHandler(c *models.StringCriterionIn
 						return
 					}
 					filepathColumn := fmt.Sprintf("%s || '%s' || %s", pathColumn, string(filepath.Separator), basenameColumn)
-					clause := makeClause(fmt.Sprintf("%s IS NOT NULL AND %s IS NOT NULL AND %s regexp ?", pathColumn, basenameColumn, filepathColumn), c.Value)
-					clause2 := makeClause(fmt.Sprintf("%s IS NOT NULL AND %[1]s regexp ?", folderPathColumn), c.Value)
+					clause := makeClause(fmt.Sprintf("%s IS NOT NULL AND %s IS NOT NULL AND %s", pathColumn, basenameColumn, regexpSQL(ctx, filepathColumn, false)), c.Value)
+					clause2 := makeClause(fmt.Sprintf("%s IS NOT NULL AND %s", folderPathColumn, regexpSQL(ctx, folderPathColumn, false)), c.Value)
 					f.whereClauses = append(f.whereClauses, orClauses(clause, clause2))
 				case models.CriterionModifierNotMatchesRegex:
 					if _, err := regexp.Compile(c.Value); err != nil {
@@ -224,8 +226,8 @@ func (qb *galleryFilterHandler) pathCriterionHandler(c *models.StringCriterionIn
 						return
 					}
 					filepathColumn := fmt.Sprintf("%s || '%s' || %s", pathColumn, string(filepath.Separator), basenameColumn)
-					f.addWhere(fmt.Sprintf("%s IS NULL OR %s IS NULL OR %s NOT regexp ?", pathColumn, basenameColumn, filepathColumn), c.Value)
-					f.addWhere(fmt.Sprintf("%s IS NULL OR %[1]s NOT regexp ?", folderPathColumn), c.Value)
+					f.addWhere(fmt.Sprintf("%s IS NULL OR %s IS NULL OR %s", pathColumn, basenameColumn, regexpSQL(ctx, filepathColumn, true)), c.Value)
+					f.addWhere(fmt.Sprintf("%s IS NULL OR %s", folderPathColumn, regexpSQL(ctx, folderPathColumn, true)), c.Value)
 				case models.CriterionModifierIsNull:
 					f.addWhere(fmt.Sprintf("%s IS NULL OR TRIM(%[1]s) = '' OR %s IS NULL OR TRIM(%[2]s) = ''", pathColumn, basenameColumn))
 					f.addWhere(fmt.Sprintf("%s IS NULL OR TRIM(%[1]s) = ''", folderPathColumn))
@@ -272,7 +274,7 @@ func (qb *galleryFilterHandler) missingCriterionHandler(isMissing *string) crite
 				galleryRepository.tags.join(f, "tags_join", "galleries.id")
 				f.addWhere("tags_join.gallery_id IS NULL")
 			default:
-				f.addWhere("(galleries." + *isMissing + " IS NULL OR TRIM(galleries." + *isMissing + ") = '')")
+				f.addWhere("(galleries." + *isMissing + " IS NULL OR TRIM(CAST(galleries." + *isMissing + " AS TEXT)) = '')")
 			}
 		}
 	}
@@ -378,12 +380,12 @@ func (qb *galleryFilterHandler) performerFavoriteCriterionHandler(performerfavor
 			if *performerfavorite {
 				// contains at least one favorite
 				f.addLeftJoin("performers", "", "performers.id = performers_galleries.performer_id")
-				f.addWhere("performers.favorite = 1")
+				f.addWhere("performers.favorite = TRUE")
 			} else {
 				// contains zero favorites
 				f.addLeftJoin(`(SELECT performers_galleries.gallery_id as id FROM performers_galleries 
 JOIN performers ON performers.id = performers_galleries.performer_id
-GROUP BY performers_galleries.gallery_id HAVING SUM(performers.favorite) = 0)`, "nofaves", "galleries.id = nofaves.id")
+GROUP BY performers_galleries.gallery_id HAVING SUM(CASE WHEN performers.favorite THEN 1 ELSE 0 END) = 0)`, "nofaves", "galleries.id = nofaves.id")
 				f.addWhere("performers_galleries.gallery_id IS NULL OR nofaves.id IS NOT NULL")
 			}
 		}
@@ -396,10 +398,9 @@ func (qb *galleryFilterHandler) performerAgeCriterionHandler(performerAge *model
 			f.addInnerJoin("performers_galleries", "", "galleries.id = performers_galleries.gallery_id")
 			f.addInnerJoin("performers", "", "performers_galleries.performer_id = performers.id")
 
-			f.addWhere("galleries.date != '' AND performers.birthdate != ''")
 			f.addWhere("galleries.date IS NOT NULL AND performers.birthdate IS NOT NULL")
 
-			ageCalc := "cast(strftime('%Y.%m%d', galleries.date) - strftime('%Y.%m%d', performers.birthdate) as int)"
+			ageCalc := ageSQL(ctx, "galleries.date", "performers.birthdate")
 			whereClause, args := getIntWhereClause(ageCalc, performerAge.Modifier, performerAge.Value, performerAge.Value2)
 			f.addWhere(whereClause, args...)
 		}
@@ -417,7 +418,7 @@ func (qb *galleryFilterHandler) averageResolutionCriterionHandler(resolution *mo
 			mn := resolution.Value.GetMinResolution()
 			mx := resolution.Value.GetMaxResolution()
 
-			const widthHeight = "avg(MIN(image_files.width, image_files.height))"
+			widthHeight := "avg(" + leastSQL(ctx, "image_files.width", "image_files.height") + ")"
 
 			switch resolution.Modifier {
 			case models.CriterionModifierEquals:
@@ -158,8 +158,8 @@ func (qb *GroupStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *GroupStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *GroupStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *GroupStore) Create(ctx context.Context, newObject *models.Group) error {
@@ -288,7 +288,7 @@ func (qb *GroupStore) FindMany(ctx context.Context, ids []int) ([]*models.Group,
 
 	table := qb.table()
 	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
-		q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(batch))
+		q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(batch))
 		unsorted, err := qb.getMany(ctx, q)
 		if err != nil {
 			return err
@@ -315,7 +315,7 @@ func (qb *GroupStore) FindMany(ctx context.Context, ids []int) ([]*models.Group,
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *GroupStore) find(ctx context.Context, id int) (*models.Group, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -369,7 +369,7 @@ func (qb *GroupStore) FindByName(ctx context.Context, name string, nocase bool)
 	if nocase {
 		where += " COLLATE NOCASE"
 	}
-	sq := qb.selectDataset().Prepared(true).Where(goqu.L(where, name)).Limit(1)
+	sq := qb.selectDataset(ctx).Prepared(true).Where(goqu.L(where, name)).Limit(1)
 	ret, err := qb.get(ctx, sq)
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -394,7 +394,7 @@ func (qb *GroupStore) FindByNames(ctx context.Context, names []string, nocase bo
 	for _, name := range names {
 		args = append(args, name)
 	}
-	sq := qb.selectDataset().Prepared(true).Where(goqu.L(where, args...))
+	sq := qb.selectDataset(ctx).Prepared(true).Where(goqu.L(where, args...))
 	ret, err := qb.getMany(ctx, sq)
 
 	if err != nil {
@@ -405,14 +405,14 @@ func (qb *GroupStore) FindByNames(ctx context.Context, names []string, nocase bo
 }
 
 func (qb *GroupStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *GroupStore) All(ctx context.Context) ([]*models.Group, error) {
 	table := qb.table()
 
-	return qb.getMany(ctx, qb.selectDataset().Order(
+	return qb.getMany(ctx, qb.selectDataset(ctx).Order(
 		table.Col("name").Asc(),
 		table.Col(idColumn).Asc(),
 	))
@@ -431,7 +431,7 @@ func (qb *GroupStore) makeQuery(ctx context.Context, groupFilter *models.GroupFi
 
 	if q := findFilter.Q; q != nil && *q != "" {
 		searchColumns := []string{"groups.name", "groups.aliases"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &groupFilterHandler{
@@ -442,7 +442,7 @@ func (qb *GroupStore) makeQuery(ctx context.Context, groupFilter *models.GroupFi
 		return nil, err
 	}
 
-	if err := qb.setGroupSort(&query, findFilter); err != nil {
+	if err := qb.setGroupSort(ctx, &query, findFilter); err != nil {
 		return nil, err
 	}
 
@@ -493,7 +493,7 @@ var groupSortOptions = sortOptions{
 	"updated_at",
 }
 
-func (qb *GroupStore) setGroupSort(query *queryBuilder, findFilter *models.FindFilterType) error {
+func (qb *GroupStore) setGroupSort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) error {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -513,23 +513,31 @@ func (qb *GroupStore) setGroupSort(query *queryBuilder, findFilter *models.FindF
 	case "sub_group_order":
 		// sub_group_order is a special sort that sorts by the order_index of the subgroups
 		if query.hasJoin("groups_parents") {
-			query.sortAndPagination += getSort("order_index", direction, "groups_parents")
+			query.sortAndPagination += getSort(ctx, "order_index", direction, "groups_parents")
 		} else {
 			// this will give unexpected results if the query is not filtered by a parent group and
 			// the group has multiple parents and order indexes
 			query.join(groupRelationsTable, "", "groups.id = groups_relations.sub_id")
-			query.sortAndPagination += getSort("order_index", direction, groupRelationsTable)
+			query.sortAndPagination += getSort(ctx, "order_index", direction, groupRelationsTable)
 		}
 	case "tag_count":
 		query.sortAndPagination += getCountSort(groupTable, groupsTagsTable, groupIDColumn, direction)
 	case "scenes_count": // generic getSort won't work for this
 		query.sortAndPagination += getCountSort(groupTable, groupsScenesTable, groupIDColumn, direction)
 	default:
-		query.sortAndPagination += getSort(sort, direction, "groups")
+		if isCustomFieldSort(sort) {
+			customSort, err := groupsCustomFieldsTableMgr.sort(ctx, sort, direction, groupTable)
+			if err != nil {
+				return err
+			}
+			query.sortAndPagination += customSort
+		} else {
+			query.sortAndPagination += getSort(ctx, sort, direction, "groups")
+		}
 	}
 
 	// Whatever the sorting, always use name/id as a final sort
-	query.sortAndPagination += ", COALESCE(groups.name, groups.id) COLLATE NATURAL_CI ASC"
+	query.sortAndPagination += ", " + coalesceIDSQL(ctx, "groups.name", "groups.id") + " COLLATE NATURAL_CI ASC"
 	return nil
 }
 
@@ -638,7 +646,7 @@ func (qb *GroupStore) FindSubGroupIDs(ctx context.Context, containingID int, ids
 		WHERE gr.containing_id = :parentID AND gr.sub_id IN (:ids);
 	*/
 	table := groupRelationshipTableMgr.table
-	q := dialect.From(table).Prepared(true).
+	q := dialect(ctx).From(table).Prepared(true).
 		Select(table.Col("sub_id")).Where(
 		table.Col("containing_id").Eq(containingID),
 		table.Col("sub_id").In(ids),
@@ -674,12 +682,12 @@ func (qb *GroupStore) FindInAncestors(ctx context.Context, ascestorIDs []int, id
 	table := qb.table()
 	const ascestors = "ancestors"
 	const parentID = "parent_id"
-	q := dialect.From(ascestors).Prepared(true).
+	q := dialect(ctx).From(ascestors).Prepared(true).
 		WithRecursive(ascestors,
-			dialect.From(qb.table()).Select(table.Col(idColumn).As(parentID)).
+			dialect(ctx).From(qb.table()).Select(table.Col(idColumn).As(parentID)).
 				Where(table.Col(idColumn).In(ascestorIDs)).
 				Union(
-					dialect.From(groupRelationsJoinTable).InnerJoin(
+					dialect(ctx).From(groupRelationsJoinTable).InnerJoin(
 						goqu.I(ascestors), goqu.On(goqu.I("parent_id").Eq(goqu.I("sub_id"))),
 					).Select("containing_id"),
 				),
@@ -701,3 +709,11 @@ func (qb *GroupStore) FindInAncestors(ctx context.Context, ascestorIDs []int, id
 
 	return ret, nil
 }
+
+func (qb *GroupStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return groupsCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *GroupStore) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	return groupsCustomFieldsTableMgr.set(ctx, id, input)
+}
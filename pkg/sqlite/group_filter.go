@@ -80,6 +80,7 @@ func (qb *groupFilterHandler) criterionHandler() criterionHandler {
 		groupHierarchyHandler.ChildCountCriterionHandler(groupFilter.SubGroupCount),
 		&timestampCriterionHandler{groupFilter.CreatedAt, "groups.created_at", nil},
 		&timestampCriterionHandler{groupFilter.UpdatedAt, "groups.updated_at", nil},
+		customFieldsCriterionHandler(groupFilter.CustomFields, groupsCustomFieldsTableMgr, "groups.id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "groups_scenes.scene_id",
@@ -110,7 +111,7 @@ func (qb *groupFilterHandler) missingCriterionHandler(isMissing *string) criteri
 				f.addLeftJoin("groups_scenes", "", "groups_scenes.group_id = groups.id")
 				f.addWhere("groups_scenes.scene_id IS NULL")
 			default:
-				f.addWhere("(groups." + *isMissing + " IS NULL OR TRIM(groups." + *isMissing + ") = '')")
+				f.addWhere("(groups." + *isMissing + " IS NULL OR TRIM(CAST(groups." + *isMissing + " AS TEXT)) = '')")
 			}
 		}
 	}
@@ -52,7 +52,7 @@ func (s *groupRelationshipStore) getGroupRelationships(ctx context.Context, id i
 	}
 
 	table := s.table.table
-	q := dialect.Select(table.All()).
+	q := dialect(ctx).Select(table.All()).
 		From(table).
 		Where(table.Col(col).Eq(id)).
 		Order(table.Col("order_index").Asc())
@@ -79,7 +79,7 @@ func (s *groupRelationshipStore) getGroupRelationships(ctx context.Context, id i
 func (s *groupRelationshipStore) getMaxOrderIndex(ctx context.Context, containingID int) (int, error) {
 	idColumn := s.table.table.Col("containing_id")
 
-	q := dialect.Select(goqu.MAX("order_index")).
+	q := dialect(ctx).Select(goqu.MAX("order_index")).
 		From(s.table.table).
 		Where(idColumn.Eq(containingID))
 
@@ -242,7 +242,7 @@ func (s *groupRelationshipStore) destroyAllJoins(ctx context.Context, id int, id
 		idColumn = table.Col("sub_id")
 	}
 
-	q := dialect.Delete(table).Where(idColumn.Eq(id))
+	q := dialect(ctx).Delete(table).Where(idColumn.Eq(id))
 
 	if _, err := exec(ctx, q); err != nil {
 		return fmt.Errorf("destroying %s: %w", table.GetTable(), err)
@@ -270,7 +270,7 @@ func (s *groupRelationshipStore) destroyJoins(ctx context.Context, id int, toRem
 		fkColumn = table.Col("containing_id")
 	}
 
-	q := dialect.Delete(table).Where(idColumn.Eq(id), fkColumn.In(toRemove))
+	q := dialect(ctx).Delete(table).Where(idColumn.Eq(id), fkColumn.In(toRemove))
 
 	if _, err := exec(ctx, q); err != nil {
 		return fmt.Errorf("destroying %s: %w", table.GetTable(), err)
@@ -281,7 +281,7 @@ func (s *groupRelationshipStore) destroyJoins(ctx context.Context, id int, toRem
 
 func (s *groupRelationshipStore) getOrderIndexOfSubGroup(ctx context.Context, containingGroupID int, subGroupID int) (int, error) {
 	table := s.table.table
-	q := dialect.Select("order_index").
+	q := dialect(ctx).Select("order_index").
 		From(table).
 		Where(
 			table.Col("containing_id").Eq(containingGroupID),
@@ -302,7 +302,7 @@ func (s *groupRelationshipStore) getOrderIndexOfSubGroup(ctx context.Context, co
 
 func (s *groupRelationshipStore) getGroupIDAtOrderIndex(ctx context.Context, containingGroupID int, orderIndex int) (*int, error) {
 	table := s.table.table
-	q := dialect.Select(table.Col("sub_id")).From(table).Where(
+	q := dialect(ctx).Select(table.Col("sub_id")).From(table).Where(
 		table.Col("containing_id").Eq(containingGroupID),
 		table.Col("order_index").Eq(orderIndex),
 	)
@@ -322,7 +322,7 @@ func (s *groupRelationshipStore) getGroupIDAtOrderIndex(ctx context.Context, con
 
 func (s *groupRelationshipStore) getOrderIndexAfterOrderIndex(ctx context.Context, containingGroupID int, orderIndex int) (int, error) {
 	table := s.table.table
-	q := dialect.Select(goqu.MIN("order_index")).From(table).Where(
+	q := dialect(ctx).Select(goqu.MIN("order_index")).From(table).Where(
 		table.Col("containing_id").Eq(containingGroupID),
 		table.Col("order_index").Gt(orderIndex),
 	)
@@ -347,7 +347,7 @@ func (s *groupRelationshipStore) incrementOrderIndexes(ctx context.Context, grou
 	// unique constraint violation.
 	// Instead, we first set the order index to a negative value temporarily
 	// see https://stackoverflow.com/a/7703239/695786
-	q := dialect.Update(table).Set(exp.Record{
+	q := dialect(ctx).Update(table).Set(exp.Record{
 		"order_index": goqu.L("-order_index"),
 	}).Where(
 		table.Col("containing_id").Eq(groupID),
@@ -358,7 +358,7 @@ func (s *groupRelationshipStore) incrementOrderIndexes(ctx context.Context, grou
 		return fmt.Errorf("updating %s: %w", table.GetTable(), err)
 	}
 
-	q = dialect.Update(table).Set(exp.Record{
+	q = dialect(ctx).Update(table).Set(exp.Record{
 		"order_index": goqu.L("1-order_index"),
 	}).Where(
 		table.Col("containing_id").Eq(groupID),
@@ -393,7 +393,7 @@ func (s *groupRelationshipStore) reorderSubGroup(ctx context.Context, groupID in
 
 	// set the order index of the sub-group to the insertion point
 	table := s.table.table
-	q := dialect.Update(table).Set(exp.Record{
+	q := dialect(ctx).Update(table).Set(exp.Record{
 		"order_index": insertPointIndex,
 	}).Where(
 		table.Col("containing_id").Eq(groupID),
@@ -0,0 +1,301 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Health check categories.
+const (
+	HealthCategoryFiles      = "files"
+	HealthCategoryFolders    = "folders"
+	HealthCategoryBlobs      = "blobs"
+	HealthCategoryJoins      = "joins"
+	HealthCategoryHistory    = "history"
+	HealthCategoryReferences = "references"
+)
+
+// maxFolderDepth is the depth at which a folder's parent chain is assumed to
+// contain a cycle.
+const maxFolderDepth = 1000
+
+// HealthCheck is a database consistency check.
+type HealthCheck struct {
+	Category    string
+	Name        string
+	Description string
+
+	// query selects the ID of each inconsistent row.
+	query string
+	// repair fixes the inconsistent rows. Checks without a repair are
+	// reported only, because they cannot be fixed without losing data.
+	repair func(ctx context.Context, ids []string) error
+}
+
+// Repairable returns true if the inconsistencies found by the check can be
+// fixed safely.
+func (c HealthCheck) Repairable() bool {
+	return c.repair != nil
+}
+
+// Find returns the IDs of the inconsistent rows found by the check. IDs may
+// be repeated if multiple rows reference the same missing ID.
+func (c HealthCheck) Find(ctx context.Context) ([]string, error) {
+	var ids []string
+	if err := dbWrapper.Select(ctx, &ids, c.query); err != nil {
+		return nil, fmt.Errorf("running health check %s: %w", c.Name, err)
+	}
+
+	return ids, nil
+}
+
+// Repair fixes the inconsistent rows with the provided IDs, as returned by
+// Find. It must be called in a write transaction.
+func (c HealthCheck) Repair(ctx context.Context, ids []string) error {
+	if c.repair == nil {
+		return fmt.Errorf("health check %s is not repairable", c.Name)
+	}
+
+	if err := c.repair(ctx, ids); err != nil {
+		return fmt.Errorf("repairing %s: %w", c.Name, err)
+	}
+
+	return nil
+}
+
+func execRepair(stmt string) func(ctx context.Context, ids []string) error {
+	return func(ctx context.Context, ids []string) error {
+		_, err := dbWrapper.Exec(ctx, stmt)
+		return err
+	}
+}
+
+type healthReferenceAction int
+
+const (
+	// the inconsistent row is reported only
+	healthReport healthReferenceAction = iota
+	// the inconsistent row is deleted
+	healthDelete
+	// the dangling reference is set to NULL, as if the foreign key was
+	// declared ON DELETE SET NULL
+	healthSetNull
+)
+
+// healthReference is a column that references a row in another table.
+type healthReference struct {
+	category     string
+	table        string
+	column       string
+	parent       string
+	parentColumn string
+	// idColumn is the column reported for each inconsistent row. The
+	// missing referenced ID is reported if empty.
+	idColumn string
+	action   healthReferenceAction
+}
+
+var healthReferences = []healthReference{
+	// files
+	{category: HealthCategoryFiles, table: fileTable, column: "parent_folder_id", parent: folderTable, idColumn: idColumn},
+	{category: HealthCategoryFiles, table: fileTable, column: "zip_file_id", parent: fileTable, idColumn: idColumn},
+	{category: HealthCategoryFiles, table: fingerprintTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+	{category: HealthCategoryFiles, table: videoFileTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+	{category: HealthCategoryFiles, table: imageFileTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+	{category: HealthCategoryFiles, table: videoCaptionsTable, column: fileIDColumn, parent: videoFileTable, parentColumn: fileIDColumn, action: healthDelete},
+	{category: HealthCategoryFiles, table: fileIntegrityTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+
+	// folders
+	{category: HealthCategoryFolders, table: folderTable, column: "parent_folder_id", parent: folderTable, idColumn: idColumn},
+	{category: HealthCategoryFolders, table: folderTable, column: "zip_file_id", parent: fileTable, idColumn: idColumn},
+
+	// blobs
+	{category: HealthCategoryBlobs, table: sceneTable, column: sceneCoverBlobColumn, parent: blobTable, parentColumn: blobChecksumColumn, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryBlobs, table: performerTable, column: performerImageBlobColumn, parent: blobTable, parentColumn: blobChecksumColumn, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryBlobs, table: studioTable, column: studioImageBlobColumn, parent: blobTable, parentColumn: blobChecksumColumn, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryBlobs, table: tagTable, column: tagImageBlobColumn, parent: blobTable, parentColumn: blobChecksumColumn, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryBlobs, table: groupTable, column: groupFrontImageBlobColumn, parent: blobTable, parentColumn: blobChecksumColumn, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryBlobs, table: groupTable, column: groupBackImageBlobColumn, parent: blobTable, parentColumn: blobChecksumColumn, idColumn: idColumn, action: healthSetNull},
+
+	// joins
+	{category: HealthCategoryJoins, table: scenesFilesTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: scenesFilesTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: imagesFilesTable, column: imageIDColumn, parent: imageTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: imagesFilesTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesFilesTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesFilesTable, column: fileIDColumn, parent: fileTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersScenesTable, column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersScenesTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: scenesTagsTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: scenesTagsTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "scene_markers_tags", column: "scene_marker_id", parent: sceneMarkerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "scene_markers_tags", column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupsScenesTable, column: groupIDColumn, parent: groupTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupsScenesTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: scenesGalleriesTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: scenesGalleriesTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "scene_stash_ids", column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: scenesURLsTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersImagesTable, column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersImagesTable, column: imageIDColumn, parent: imageTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: imagesTagsTable, column: imageIDColumn, parent: imageTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: imagesTagsTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: imagesURLsTable, column: imageIDColumn, parent: imageTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesImagesTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesImagesTable, column: imageIDColumn, parent: imageTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersGalleriesTable, column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersGalleriesTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesTagsTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesTagsTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesURLsTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: galleriesChaptersTable, column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersTagsTable, column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersTagsTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performersAliasesTable, column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: performerURLsTable, column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "performer_stash_ids", column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: studiosTagsTable, column: studioIDColumn, parent: studioTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: studiosTagsTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: studioAliasesTable, column: studioIDColumn, parent: studioTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "studio_stash_ids", column: studioIDColumn, parent: studioTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupsTagsTable, column: groupIDColumn, parent: groupTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupsTagsTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupURLsTable, column: groupIDColumn, parent: groupTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupRelationsTable, column: "containing_id", parent: groupTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: groupRelationsTable, column: "sub_id", parent: groupTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: tagRelationsTable, column: tagParentIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: tagRelationsTable, column: tagChildIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: tagAliasesTable, column: tagIDColumn, parent: tagTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "scene_custom_fields", column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "performer_custom_fields", column: performerIDColumn, parent: performerTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "studio_custom_fields", column: studioIDColumn, parent: studioTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "gallery_custom_fields", column: galleryIDColumn, parent: galleryTable, action: healthDelete},
+	{category: HealthCategoryJoins, table: "group_custom_fields", column: groupIDColumn, parent: groupTable, action: healthDelete},
+
+	// history
+	{category: HealthCategoryHistory, table: scenesViewDatesTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+	{category: HealthCategoryHistory, table: scenesODatesTable, column: sceneIDColumn, parent: sceneTable, action: healthDelete},
+
+	// references between objects
+	{category: HealthCategoryReferences, table: sceneTable, column: studioIDColumn, parent: studioTable, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryReferences, table: imageTable, column: studioIDColumn, parent: studioTable, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryReferences, table: galleryTable, column: studioIDColumn, parent: studioTable, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryReferences, table: groupTable, column: studioIDColumn, parent: studioTable, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryReferences, table: studioTable, column: studioParentIDColumn, parent: studioTable, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryReferences, table: galleryTable, column: "folder_id", parent: folderTable, idColumn: idColumn, action: healthSetNull},
+	{category: HealthCategoryReferences, table: sceneMarkerTable, column: sceneIDColumn, parent: sceneTable, idColumn: idColumn},
+	{category: HealthCategoryReferences, table: sceneMarkerTable, column: "primary_tag_id", parent: tagTable, idColumn: idColumn},
+}
+
+// missingWhere returns the condition selecting rows with a dangling reference.
+func (r healthReference) missingWhere() string {
+	parentColumn := r.parentColumn
+	if parentColumn == "" {
+		parentColumn = idColumn
+	}
+
+	return fmt.Sprintf("`%[1]s`.`%[2]s` IS NOT NULL AND NOT EXISTS (SELECT 1 FROM `%[3]s` WHERE `%[3]s`.`%[4]s` = `%[1]s`.`%[2]s`)",
+		r.table, r.column, r.parent, parentColumn)
+}
+
+func (r healthReference) check() HealthCheck {
+	reported := r.idColumn
+	if reported == "" {
+		reported = r.column
+	}
+
+	ret := HealthCheck{
+		Category: r.category,
+		Name:     fmt.Sprintf("missing_%s.%s", r.table, r.column),
+		query:    fmt.Sprintf("SELECT `%s`.`%s` FROM `%s` WHERE %s", r.table, reported, r.table, r.missingWhere()),
+	}
+
+	switch r.action {
+	case healthDelete:
+		ret.Description = fmt.Sprintf("%s rows referencing missing %s", r.table, r.parent)
+		ret.repair = execRepair(fmt.Sprintf("DELETE FROM `%s` WHERE %s", r.table, r.missingWhere()))
+	case healthSetNull:
+		ret.Description = fmt.Sprintf("%s with %s referencing missing %s", r.table, r.column, r.parent)
+		ret.repair = execRepair(fmt.Sprintf("UPDATE `%s` SET `%s` = NULL WHERE %s", r.table, r.column, r.missingWhere()))
+	default:
+		ret.Description = fmt.Sprintf("%s with %s referencing missing %s", r.table, r.column, r.parent)
+	}
+
+	return ret
+}
+
+// blobReferences are the columns that reference blobs.
+var blobReferences = []struct {
+	table  string
+	column string
+}{
+	{sceneTable, sceneCoverBlobColumn},
+	{performerTable, performerImageBlobColumn},
+	{studioTable, studioImageBlobColumn},
+	{tagTable, tagImageBlobColumn},
+	{groupTable, groupFrontImageBlobColumn},
+	{groupTable, groupBackImageBlobColumn},
+}
+
+func (db *Database) unreferencedBlobsCheck() HealthCheck {
+	var conditions []string
+	for _, r := range blobReferences {
+		conditions = append(conditions, fmt.Sprintf("NOT EXISTS (SELECT 1 FROM `%[1]s` WHERE `%[1]s`.`%[2]s` = `%[3]s`.`%[4]s`)",
+			r.table, r.column, blobTable, blobChecksumColumn))
+	}
+
+	return HealthCheck{
+		Category:    HealthCategoryBlobs,
+		Name:        "unreferenced_blobs",
+		Description: "blobs not referenced by any object",
+		query:       fmt.Sprintf("SELECT `%s` FROM `%s` WHERE %s", blobChecksumColumn, blobTable, strings.Join(conditions, " AND ")),
+		repair: func(ctx context.Context, ids []string) error {
+			for _, checksum := range ids {
+				// deletes the blob from the filesystem as well, if applicable
+				if err := db.Blobs.Delete(ctx, checksum); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// HealthChecks returns the catalogue of consistency checks of the database.
+func (db *Database) HealthChecks() []HealthCheck {
+	var ret []HealthCheck
+	for _, r := range healthReferences {
+		ret = append(ret, r.check())
+	}
+
+	ret = append(ret,
+		HealthCheck{
+			Category:    HealthCategoryFolders,
+			Name:        "folder_cycles",
+			Description: "folders whose parent folder chain contains a cycle",
+			query: fmt.Sprintf("WITH RECURSIVE `folder_chain`(`folder_id`, `parent_id`, `depth`) AS ("+
+				"SELECT `id`, `parent_folder_id`, 0 FROM `%[1]s` "+
+				"UNION ALL SELECT `folder_chain`.`folder_id`, `%[1]s`.`parent_folder_id`, `folder_chain`.`depth` + 1 FROM `folder_chain` "+
+				"INNER JOIN `%[1]s` ON `%[1]s`.`id` = `folder_chain`.`parent_id` WHERE `folder_chain`.`depth` < %[2]d"+
+				") SELECT DISTINCT `folder_id` FROM `folder_chain` WHERE `depth` = %[2]d", folderTable, maxFolderDepth),
+		},
+		HealthCheck{
+			Category:    HealthCategoryFiles,
+			Name:        "scenes_without_files",
+			Description: "scenes without files. These may have been created manually",
+			query: fmt.Sprintf("SELECT `id` FROM `%[1]s` WHERE NOT EXISTS (SELECT 1 FROM `%[2]s` WHERE `%[2]s`.`%[3]s` = `%[1]s`.`id`)",
+				sceneTable, scenesFilesTable, sceneIDColumn),
+		},
+		HealthCheck{
+			Category:    HealthCategoryFiles,
+			Name:        "images_without_files",
+			Description: "images without files",
+			query: fmt.Sprintf("SELECT `id` FROM `%[1]s` WHERE NOT EXISTS (SELECT 1 FROM `%[2]s` WHERE `%[2]s`.`%[3]s` = `%[1]s`.`id`)",
+				imageTable, imagesFilesTable, imageIDColumn),
+		},
+		db.unreferencedBlobsCheck(),
+	)
+
+	return ret
+}
@@ -0,0 +1,91 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func findHealthCheck(name string) *sqlite.HealthCheck {
+	for _, c := range db.HealthChecks() {
+		if c.Name == name {
+			return &c
+		}
+	}
+	return nil
+}
+
+func TestHealthChecks_Find(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		for _, c := range db.HealthChecks() {
+			if _, err := c.Find(ctx); err != nil {
+				t.Errorf("HealthCheck.Find() %s error = %v", c.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+func TestHealthCheck_Repair(t *testing.T) {
+	skipIfPostgres(t)
+
+	const missingTagID = 999999
+
+	withRollbackTxn(func(ctx context.Context) error {
+		// the orphaned row is never committed
+		if _, _, err := db.ExecSQL(ctx, "PRAGMA defer_foreign_keys = ON", nil); err != nil {
+			t.Errorf("deferring foreign keys: %v", err)
+			return nil
+		}
+
+		if _, _, err := db.ExecSQL(ctx, "INSERT INTO scenes_tags (scene_id, tag_id) VALUES (?, ?)", []interface{}{sceneIDs[sceneIdxWithTag], missingTagID}); err != nil {
+			t.Errorf("inserting orphaned row: %v", err)
+			return nil
+		}
+
+		c := findHealthCheck("missing_scenes_tags.tag_id")
+		if c == nil {
+			t.Error("health check missing_scenes_tags.tag_id not found")
+			return nil
+		}
+
+		assert.True(t, c.Repairable())
+
+		ids, err := c.Find(ctx)
+		if err != nil {
+			t.Errorf("HealthCheck.Find() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []string{strconv.Itoa(missingTagID)}, ids)
+
+		if err := c.Repair(ctx, ids); err != nil {
+			t.Errorf("HealthCheck.Repair() error = %v", err)
+			return nil
+		}
+
+		ids, err = c.Find(ctx)
+		if err != nil {
+			t.Errorf("HealthCheck.Find() error = %v", err)
+			return nil
+		}
+		assert.Empty(t, ids)
+
+		return nil
+	})
+}
+
+func TestHealthCheck_ReportOnly(t *testing.T) {
+	c := findHealthCheck("scenes_without_files")
+	if c == nil {
+		t.Fatal("health check scenes_without_files not found")
+	}
+
+	assert.False(t, c.Repairable())
+	assert.Error(t, c.Repair(context.Background(), []string{"1"}))
+}
@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"path/filepath"
 	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
+	"github.com/stashapp/stash/pkg/utils"
 	"gopkg.in/guregu/null.v4"
 	"gopkg.in/guregu/null.v4/zero"
 
@@ -29,6 +33,27 @@ const (
 	imageURLColumn        = "url"
 )
 
+// findExactImageDuplicateQuery is formatted with the aggregate expression of
+// the image ids.
+var findExactImageDuplicateQuery = `
+SELECT %s as ids
+FROM images_files
+INNER JOIN files ON (images_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (images_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+GROUP BY files_fingerprints.fingerprint
+HAVING COUNT(DISTINCT images_files.image_id) > 1
+ORDER BY SUM(files.size) DESC;
+`
+
+var findAllImagePhashesQuery = `
+SELECT images_files.image_id as id
+    , files_fingerprints.fingerprint as phash
+FROM images_files
+INNER JOIN files ON (images_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (images_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+ORDER BY files.size DESC;
+`
+
 type imageRow struct {
 	ID    int         `db:"id" goqu:"skipinsert"`
 	Title zero.String `db:"title"`
@@ -201,17 +226,17 @@ func (qb *ImageStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *ImageStore) selectDataset() *goqu.SelectDataset {
+func (qb *ImageStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 	files := fileTableMgr.table
 	folders := folderTableMgr.table
 	checksum := fingerprintTableMgr.table
 
-	return dialect.From(table).LeftJoin(
+	return dialect(ctx).From(table).LeftJoin(
 		imagesFilesJoinTable,
 		goqu.On(
 			imagesFilesJoinTable.Col(imageIDColumn).Eq(table.Col(idColumn)),
-			imagesFilesJoinTable.Col("primary").Eq(1),
+			imagesFilesJoinTable.Col("primary").Eq(true),
 		),
 	).LeftJoin(
 		files,
@@ -392,7 +417,7 @@ func (qb *ImageStore) FindMany(ctx context.Context, ids []int) ([]*models.Image,
 	images := make([]*models.Image, len(ids))
 
 	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
-		q := qb.selectDataset().Prepared(true).Where(qb.table().Col(idColumn).In(batch))
+		q := qb.selectDataset(ctx).Prepared(true).Where(qb.table().Col(idColumn).In(batch))
 		unsorted, err := qb.getMany(ctx, q)
 		if err != nil {
 			return err
@@ -419,7 +444,7 @@ func (qb *ImageStore) FindMany(ctx context.Context, ids []int) ([]*models.Image,
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *ImageStore) find(ctx context.Context, id int) (*models.Image, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -432,7 +457,7 @@ func (qb *ImageStore) find(ctx context.Context, id int) (*models.Image, error) {
 func (qb *ImageStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Image, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -485,7 +510,7 @@ func (qb *ImageStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*mo
 func (qb *ImageStore) CoverByGalleryID(ctx context.Context, galleryID int) (*models.Image, error) {
 	table := qb.table()
 
-	sq := dialect.From(table).
+	sq := dialect(ctx).From(table).
 		InnerJoin(
 			galleriesImagesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(galleriesImagesJoinTable.Col(imageIDColumn))),
@@ -496,7 +521,7 @@ func (qb *ImageStore) CoverByGalleryID(ctx context.Context, galleryID int) (*mod
 			galleriesImagesJoinTable.Col("cover").Eq(true),
 		))
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -543,7 +568,7 @@ func (qb *ImageStore) GetManyFileIDs(ctx context.Context, ids []int) ([][]models
 func (qb *ImageStore) FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Image, error) {
 	table := qb.table()
 
-	sq := dialect.From(table).
+	sq := dialect(ctx).From(table).
 		InnerJoin(
 			imagesFilesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(imagesFilesJoinTable.Col(imageIDColumn))),
@@ -561,7 +586,7 @@ func (qb *ImageStore) FindByFileID(ctx context.Context, fileID models.FileID) ([
 func (qb *ImageStore) CountByFileID(ctx context.Context, fileID models.FileID) (int, error) {
 	joinTable := imagesFilesJoinTable
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
 	return count(ctx, q)
 }
 
@@ -578,7 +603,7 @@ func (qb *ImageStore) FindByFingerprints(ctx context.Context, fp []models.Finger
 		))
 	}
 
-	sq := dialect.From(table).
+	sq := dialect(ctx).From(table).
 		InnerJoin(
 			imagesFilesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(imagesFilesJoinTable.Col(imageIDColumn))),
@@ -606,15 +631,17 @@ func (qb *ImageStore) FindByChecksum(ctx context.Context, checksum string) ([]*m
 	})
 }
 
-var defaultGalleryOrder = []exp.OrderedExpression{
-	goqu.L("COALESCE(folders.path, '') || COALESCE(files.basename, '') COLLATE NATURAL_CI").Asc(),
-	goqu.L("COALESCE(images.title, images.id) COLLATE NATURAL_CI").Asc(),
+func defaultGalleryOrder(ctx context.Context) []exp.OrderedExpression {
+	return []exp.OrderedExpression{
+		goqu.L("COALESCE(folders.path, '') || COALESCE(files.basename, '') COLLATE NATURAL_CI").Asc(),
+		goqu.L(coalesceIDSQL(ctx, "images.title", "images.id") + " COLLATE NATURAL_CI").Asc(),
+	}
 }
 
 func (qb *ImageStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*models.Image, error) {
 	table := qb.table()
 
-	sq := dialect.From(table).
+	sq := dialect(ctx).From(table).
 		InnerJoin(
 			galleriesImagesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(galleriesImagesJoinTable.Col(imageIDColumn))),
@@ -623,11 +650,11 @@ func (qb *ImageStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*mo
 		galleriesImagesJoinTable.Col("gallery_id").Eq(galleryID),
 	)
 
-	q := qb.selectDataset().Prepared(true).Where(
+	q := qb.selectDataset(ctx).Prepared(true).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
-	).Order(defaultGalleryOrder...)
+	).Order(defaultGalleryOrder(ctx)...)
 
 	ret, err := qb.getMany(ctx, q)
 	if err != nil {
@@ -640,14 +667,14 @@ func (qb *ImageStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*mo
 func (qb *ImageStore) FindByGalleryIDIndex(ctx context.Context, galleryID int, index uint) (*models.Image, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().
+	q := qb.selectDataset(ctx).
 		InnerJoin(
 			galleriesImagesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(galleriesImagesJoinTable.Col(imageIDColumn))),
 		).
 		Where(galleriesImagesJoinTable.Col(galleryIDColumn).Eq(galleryID)).
 		Prepared(true).
-		Order(defaultGalleryOrder...).
+		Order(defaultGalleryOrder(ctx)...).
 		Limit(1).Offset(index)
 
 	ret, err := qb.getMany(ctx, q)
@@ -665,14 +692,14 @@ func (qb *ImageStore) FindByGalleryIDIndex(ctx context.Context, galleryID int, i
 func (qb *ImageStore) CountByGalleryID(ctx context.Context, galleryID int) (int, error) {
 	joinTable := goqu.T(galleriesImagesTable)
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col("gallery_id").Eq(galleryID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col("gallery_id").Eq(galleryID))
 	return count(ctx, q)
 }
 
 func (qb *ImageStore) OCountByPerformerID(ctx context.Context, performerID int) (int, error) {
 	table := qb.table()
 	joinTable := performersImagesJoinTable
-	q := dialect.Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table).InnerJoin(joinTable, goqu.On(table.Col(idColumn).Eq(joinTable.Col(imageIDColumn)))).Where(joinTable.Col(performerIDColumn).Eq(performerID))
+	q := dialect(ctx).Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table).InnerJoin(joinTable, goqu.On(table.Col(idColumn).Eq(joinTable.Col(imageIDColumn)))).Where(joinTable.Col(performerIDColumn).Eq(performerID))
 
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -685,7 +712,7 @@ func (qb *ImageStore) OCountByPerformerID(ctx context.Context, performerID int)
 func (qb *ImageStore) OCount(ctx context.Context) (int, error) {
 	table := qb.table()
 
-	q := dialect.Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table)
+	q := dialect(ctx).Select(goqu.COALESCE(goqu.SUM("o_counter"), 0)).From(table)
 	var ret int
 	if err := querySimple(ctx, q, &ret); err != nil {
 		return 0, err
@@ -698,7 +725,7 @@ func (qb *ImageStore) FindByFolderID(ctx context.Context, folderID models.Folder
 	table := qb.table()
 	fileTable := goqu.T(fileTable)
 
-	sq := dialect.From(table).
+	sq := dialect(ctx).From(table).
 		InnerJoin(
 			imagesFilesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(imagesFilesJoinTable.Col(imageIDColumn))),
@@ -723,7 +750,7 @@ func (qb *ImageStore) FindByZipFileID(ctx context.Context, zipFileID models.File
 	table := qb.table()
 	fileTable := goqu.T(fileTable)
 
-	sq := dialect.From(table).
+	sq := dialect(ctx).From(table).
 		InnerJoin(
 			imagesFilesJoinTable,
 			goqu.On(table.Col(idColumn).Eq(imagesFilesJoinTable.Col(imageIDColumn))),
@@ -744,15 +771,82 @@ func (qb *ImageStore) FindByZipFileID(ctx context.Context, zipFileID models.File
 	return ret, nil
 }
 
+// FindDuplicates returns groups of images with phashes within distance of
+// each other, including images within zip files.
+func (qb *ImageStore) FindDuplicates(ctx context.Context, distance int) ([][]*models.Image, error) {
+	var dupeIds [][]int
+	if distance == 0 {
+		var ids []string
+		if err := dbWrapper.Select(ctx, &ids, fmt.Sprintf(findExactImageDuplicateQuery, groupConcatSQL(ctx, "images_files.image_id", true))); err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			var imageIds []int
+			for _, strId := range strings.Split(id, ",") {
+				if intId, err := strconv.Atoi(strId); err == nil {
+					imageIds = sliceutil.AppendUnique(imageIds, intId)
+				}
+			}
+			if len(imageIds) > 1 {
+				dupeIds = append(dupeIds, imageIds)
+			}
+		}
+	} else {
+		var hashes []*utils.Phash
+
+		if err := imageRepository.queryFunc(ctx, findAllImagePhashesQuery, nil, false, func(rows *sqlx.Rows) error {
+			phash := utils.Phash{
+				Bucket:   -1,
+				Duration: -1,
+			}
+			if err := rows.StructScan(&phash); err != nil {
+				return err
+			}
+
+			hashes = append(hashes, &phash)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		// images have no duration, so don't compare it
+		dupeIds = utils.FindDuplicates(hashes, distance, -1)
+	}
+
+	var duplicates [][]*models.Image
+	for _, imageIds := range dupeIds {
+		if images, err := qb.FindMany(ctx, imageIds); err == nil {
+			duplicates = append(duplicates, images)
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return firstImagePath(duplicates[i]) < firstImagePath(duplicates[j])
+	})
+
+	return duplicates, nil
+}
+
+func firstImagePath(images []*models.Image) string {
+	var firstPath string
+	for i, image := range images {
+		if i == 0 || image.Path < firstPath {
+			firstPath = image.Path
+		}
+	}
+	return firstPath
+}
+
 func (qb *ImageStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *ImageStore) Size(ctx context.Context) (float64, error) {
 	table := qb.table()
 	fileTable := fileTableMgr.table
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		goqu.COALESCE(goqu.SUM(fileTableMgr.table.Col("size")), 0),
 	).From(table).InnerJoin(
 		imagesFilesJoinTable,
@@ -770,7 +864,7 @@ func (qb *ImageStore) Size(ctx context.Context) (float64, error) {
 }
 
 func (qb *ImageStore) All(ctx context.Context) ([]*models.Image, error) {
-	return qb.getMany(ctx, qb.selectDataset())
+	return qb.getMany(ctx, qb.selectDataset(ctx))
 }
 
 func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFilterType, findFilter *models.FindFilterType) (*queryBuilder, error) {
@@ -784,7 +878,8 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 	query := imageRepository.newQuery()
 	distinctIDs(&query, imageTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, imagesFTSTable, "images.id", findFilter) {
 		query.addJoins(
 			join{
 				table:    imagesFilesTable,
@@ -806,7 +901,7 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 
 		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
 		searchColumns := []string{"images.title", filepathColumn, "files_fingerprints.fingerprint"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &imageFilterHandler{
@@ -817,7 +912,7 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 		return nil, err
 	}
 
-	if err := qb.setImageSortAndPagination(&query, findFilter); err != nil {
+	if err := qb.setImageSortAndPagination(ctx, &query, findFilter); err != nil {
 		return nil, err
 	}
 
@@ -887,14 +982,14 @@ func (qb *ImageStore) queryGroupedFields(ctx context.Context, options models.Ima
 	}
 
 	const includeSortPagination = false
-	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(includeSortPagination))
+	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(ctx, includeSortPagination))
 
 	out := struct {
 		Total      int
 		Megapixels null.Float
 		Size       null.Float
 	}{}
-	if err := imageRepository.queryStruct(ctx, aggregateQuery.toSQL(includeSortPagination), query.args, &out); err != nil {
+	if err := imageRepository.queryStruct(ctx, aggregateQuery.toSQL(ctx, includeSortPagination), query.args, &out); err != nil {
 		return nil, err
 	}
 
@@ -926,12 +1021,13 @@ var imageSortOptions = sortOptions{
 	"performer_count",
 	"random",
 	"rating",
+	"relevance",
 	"tag_count",
 	"title",
 	"updated_at",
 }
 
-func (qb *ImageStore) setImageSortAndPagination(q *queryBuilder, findFilter *models.FindFilterType) error {
+func (qb *ImageStore) setImageSortAndPagination(ctx context.Context, q *queryBuilder, findFilter *models.FindFilterType) error {
 	sortClause := ""
 
 	if findFilter != nil && findFilter.Sort != nil && *findFilter.Sort != "" {
@@ -943,6 +1039,8 @@ func (qb *ImageStore) setImageSortAndPagination(q *queryBuilder, findFilter *mod
 			return err
 		}
 
+		sort = q.relevanceSortOr(sort, "title")
+
 		// translate sort field
 		if sort == "file_mod_time" {
 			sort = "mod_time"
@@ -981,17 +1079,19 @@ func (qb *ImageStore) setImageSortAndPagination(q *queryBuilder, findFilter *mod
 			sortClause = getCountSort(imageTable, performersImagesTable, imageIDColumn, direction)
 		case "mod_time", "filesize":
 			addFilesJoin()
-			sortClause = getSort(sort, direction, "files")
+			sortClause = getSort(ctx, sort, direction, "files")
 		case "title":
 			addFilesJoin()
 			addFolderJoin()
 			sortClause = " ORDER BY COALESCE(images.title, files.basename) COLLATE NATURAL_CI " + direction + ", folders.path COLLATE NATURAL_CI " + direction
+		case relevanceSort:
+			sortClause = getRelevanceSort(direction)
 		default:
-			sortClause = getSort(sort, direction, "images")
+			sortClause = getSort(ctx, sort, direction, "images")
 		}
 
 		// Whatever the sorting, always use title/id as a final sort
-		sortClause += ", COALESCE(images.title, images.id) COLLATE NATURAL_CI ASC"
+		sortClause += ", " + coalesceIDSQL(ctx, "images.title", "images.id") + " COLLATE NATURAL_CI ASC"
 	}
 
 	q.sortAndPagination = sortClause + getPagination(findFilter)
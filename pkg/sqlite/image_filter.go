@@ -62,6 +62,7 @@ func (qb *imageFilterHandler) criterionHandler() criterionHandler {
 
 			stringCriterionHandler(imageFilter.Checksum, "fingerprints_md5.fingerprint")(ctx, f)
 		}),
+		phashDistanceCriterionHandler(imageFilter.PhashDistance, "images_files", imageRepository.addImagesFilesTable),
 		stringCriterionHandler(imageFilter.Title, "images.title"),
 		stringCriterionHandler(imageFilter.Code, "images.code"),
 		stringCriterionHandler(imageFilter.Details, "images.details"),
@@ -72,6 +73,7 @@ func (qb *imageFilterHandler) criterionHandler() criterionHandler {
 		intCriterionHandler(imageFilter.Rating100, "images.rating", nil),
 		intCriterionHandler(imageFilter.OCounter, "images.o_counter", nil),
 		boolCriterionHandler(imageFilter.Organized, "images.organized", nil),
+		isOfflineCriterionHandler(imageFilter.IsOffline, "images.id", "SELECT images_files.image_id FROM images_files INNER JOIN files ON files.id = images_files.file_id AND images_files.`primary` = TRUE INNER JOIN folders ON folders.id = files.parent_folder_id"),
 		&dateCriterionHandler{imageFilter.Date, "images.date", nil},
 		qb.urlsCriterionHandler(imageFilter.URL),
 
@@ -152,7 +154,7 @@ func (qb *imageFilterHandler) missingCriterionHandler(isMissing *string) criteri
 				imageRepository.tags.join(f, "tags_join", "images.id")
 				f.addWhere("tags_join.image_id IS NULL")
 			default:
-				f.addWhere("(images." + *isMissing + " IS NULL OR TRIM(images." + *isMissing + ") = '')")
+				f.addWhere("(images." + *isMissing + " IS NULL OR TRIM(CAST(images." + *isMissing + " AS TEXT)) = '')")
 			}
 		}
 	}
@@ -254,12 +256,12 @@ func (qb *imageFilterHandler) performerFavoriteCriterionHandler(performerfavorit
 			if *performerfavorite {
 				// contains at least one favorite
 				f.addLeftJoin("performers", "", "performers.id = performers_images.performer_id")
-				f.addWhere("performers.favorite = 1")
+				f.addWhere("performers.favorite = TRUE")
 			} else {
 				// contains zero favorites
 				f.addLeftJoin(`(SELECT performers_images.image_id as id FROM performers_images
 JOIN performers ON performers.id = performers_images.performer_id
-GROUP BY performers_images.image_id HAVING SUM(performers.favorite) = 0)`, "nofaves", "images.id = nofaves.id")
+GROUP BY performers_images.image_id HAVING SUM(CASE WHEN performers.favorite THEN 1 ELSE 0 END) = 0)`, "nofaves", "images.id = nofaves.id")
 				f.addWhere("performers_images.image_id IS NULL OR nofaves.id IS NOT NULL")
 			}
 		}
@@ -272,10 +274,9 @@ func (qb *imageFilterHandler) performerAgeCriterionHandler(performerAge *models.
 			f.addInnerJoin("performers_images", "", "images.id = performers_images.image_id")
 			f.addInnerJoin("performers", "", "performers_images.performer_id = performers.id")
 
-			f.addWhere("images.date != '' AND performers.birthdate != ''")
 			f.addWhere("images.date IS NOT NULL AND performers.birthdate IS NOT NULL")
 
-			ageCalc := "cast(strftime('%Y.%m%d', images.date) - strftime('%Y.%m%d', performers.birthdate) as int)"
+			ageCalc := ageSQL(ctx, "images.date", "performers.birthdate")
 			whereClause, args := getIntWhereClause(ageCalc, performerAge.Modifier, performerAge.Value, performerAge.Value2)
 			f.addWhere(whereClause, args...)
 		}
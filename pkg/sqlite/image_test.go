@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -3018,6 +3019,126 @@ func TestImageQueryPagination(t *testing.T) {
 	})
 }
 
+// phash values of the images created by createPhashImages. The exact copy
+// shares the base hash, near is one bit from it and far is three bits from
+// it.
+var (
+	phashBase  int64 = 0x0f0f0f0f0f0f0f0f
+	phashNear        = phashBase ^ 0x1
+	phashFar         = phashBase ^ 0x7
+	phashOther int64 = 0x70f0f0f0f0f0f0f0
+)
+
+// createPhashImages creates an image with a phash fingerprint for each of
+// base, an exact copy of base, near, far and other, returning their ids in
+// that order.
+func createPhashImages(ctx context.Context) ([]int, error) {
+	var ret []int
+	for i, phash := range []int64{phashBase, phashBase, phashNear, phashFar, phashOther} {
+		basename := "phash_" + strconv.Itoa(i) + ".jpg"
+		f := &models.ImageFile{
+			BaseFile: &models.BaseFile{
+				Path:           getFilePath(folderIdxWithImageFiles, basename),
+				Basename:       basename,
+				ParentFolderID: folderIDs[folderIdxWithImageFiles],
+				Fingerprints: []models.Fingerprint{
+					{
+						Type:        models.FingerprintTypePhash,
+						Fingerprint: phash,
+					},
+				},
+			},
+		}
+		if err := db.File.Create(ctx, f); err != nil {
+			return nil, err
+		}
+
+		image := &models.Image{}
+		if err := db.Image.Create(ctx, image, []models.FileID{f.ID}); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, image.ID)
+	}
+
+	return ret, nil
+}
+
+func TestImageStore_FindDuplicates(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		ids, err := createPhashImages(ctx)
+		if err != nil {
+			t.Errorf("creating images: %v", err)
+			return nil
+		}
+
+		qb := db.Image
+
+		got, err := qb.FindDuplicates(ctx, 0)
+		if err != nil {
+			t.Errorf("ImageStore.FindDuplicates() error = %v", err)
+			return nil
+		}
+
+		if assert.Len(t, got, 1) {
+			assert.ElementsMatch(t, []int{ids[0], ids[1]}, imagesToIDs(got[0]))
+		}
+
+		got, err = qb.FindDuplicates(ctx, 1)
+		if err != nil {
+			t.Errorf("ImageStore.FindDuplicates() error = %v", err)
+			return nil
+		}
+
+		if assert.Len(t, got, 1) {
+			assert.ElementsMatch(t, []int{ids[0], ids[1], ids[2]}, imagesToIDs(got[0]))
+		}
+
+		return nil
+	})
+}
+
+func TestImageQueryPhashDistance(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		ids, err := createPhashImages(ctx)
+		if err != nil {
+			t.Errorf("creating images: %v", err)
+			return nil
+		}
+
+		distance := func(d int) *int { return &d }
+		value := utils.PhashToString(phashBase)
+
+		tests := []struct {
+			name     string
+			modifier models.CriterionModifier
+			distance *int
+			want     []int
+		}{
+			{"equals", models.CriterionModifierEquals, nil, []int{ids[0], ids[1]}},
+			{"equals with distance", models.CriterionModifierEquals, distance(2), []int{ids[0], ids[1], ids[2]}},
+			{"not equals with distance", models.CriterionModifierNotEquals, distance(2), []int{ids[3], ids[4]}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				images := queryImages(ctx, t, db.Image, &models.ImageFilterType{
+					PhashDistance: &models.PhashDistanceCriterionInput{
+						Value:    value,
+						Modifier: tt.modifier,
+						Distance: tt.distance,
+					},
+				}, nil)
+
+				// images without a phash never match
+				assert.ElementsMatch(t, tt.want, imagesToIDs(images))
+			})
+		}
+
+		return nil
+	})
+}
+
 // TODO Count
 // TODO SizeCount
 // TODO All
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/golang-migrate/migrate/v4"
+	postgresmig "github.com/golang-migrate/migrate/v4/database/postgres"
 	sqlite3mig "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
@@ -59,6 +60,10 @@ func (m *Migrator) RequiredSchemaVersion() uint {
 }
 
 func (m *Migrator) getMigrate() (*migrate.Migrate, error) {
+	if m.db.dbType == PostgresBackend {
+		return m.getPostgresMigrate()
+	}
+
 	migrations, err := iofs.New(migrationsBox, "migrations")
 	if err != nil {
 		return nil, err
@@ -78,11 +83,51 @@ func (m *Migrator) getMigrate() (*migrate.Migrate, error) {
 	)
 }
 
+func (m *Migrator) getPostgresMigrate() (*migrate.Migrate, error) {
+	migrations, err := iofs.New(postgresMigrationsBox, "migrations/postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgresmig.WithInstance(m.conn.DB, &postgresmig.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance(
+		"iofs",
+		migrations,
+		"postgres",
+		driver,
+	)
+}
+
+// nextSchemaVersion returns the schema version following the current
+// version. The PostgreSQL migrations start at postgresInitialSchemaVersion.
+func (m *Migrator) nextSchemaVersion(current uint) uint {
+	if m.db.dbType == PostgresBackend && current < postgresInitialSchemaVersion {
+		return postgresInitialSchemaVersion
+	}
+
+	return current + 1
+}
+
 func (m *Migrator) RunMigration(ctx context.Context, newVersion uint) error {
 	databaseSchemaVersion, _, _ := m.m.Version()
 
-	if newVersion != databaseSchemaVersion+1 {
-		return fmt.Errorf("invalid migration version %d, expected %d", newVersion, databaseSchemaVersion+1)
+	if expected := m.nextSchemaVersion(databaseSchemaVersion); newVersion != expected {
+		return fmt.Errorf("invalid migration version %d, expected %d", newVersion, expected)
+	}
+
+	// the custom migrations are written for SQLite, and are not needed by
+	// the PostgreSQL migrations, which start after them
+	if m.db.dbType == PostgresBackend {
+		if err := m.m.Steps(1); err != nil {
+			return err
+		}
+
+		m.db.schemaVersion, _, _ = m.m.Version()
+		return nil
 	}
 
 	// run pre migrations as needed
@@ -150,17 +195,16 @@ func (db *Database) RunAllMigrations() error {
 	defer m.Close()
 
 	databaseSchemaVersion, _, _ := m.m.Version()
-	stepNumber := appSchemaVersion - databaseSchemaVersion
-	if stepNumber != 0 {
+	if databaseSchemaVersion != appSchemaVersion {
 		logger.Infof("Migrating database from version %d to %d", databaseSchemaVersion, appSchemaVersion)
 
 		// run each migration individually, and run custom migrations as needed
-		var i uint = 1
-		for ; i <= stepNumber; i++ {
-			newVersion := databaseSchemaVersion + i
+		for v := databaseSchemaVersion; v < appSchemaVersion; {
+			newVersion := m.nextSchemaVersion(v)
 			if err := m.RunMigration(ctx, newVersion); err != nil {
 				return err
 			}
+			v = newVersion
 		}
 	}
 
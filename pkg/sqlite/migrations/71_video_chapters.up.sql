@@ -0,0 +1 @@
+ALTER TABLE video_files ADD COLUMN chapters TEXT;
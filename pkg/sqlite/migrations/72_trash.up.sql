@@ -0,0 +1,10 @@
+CREATE TABLE `trash` (
+  `id` integer not null primary key autoincrement,
+  `object_type` varchar(255) not null,
+  `name` varchar(510) not null,
+  `files` text,
+  `snapshot` blob not null,
+  `deleted_at` datetime not null
+);
+
+CREATE INDEX `index_trash_on_deleted_at` on `trash` (`deleted_at`);
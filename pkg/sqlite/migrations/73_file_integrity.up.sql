@@ -0,0 +1,10 @@
+CREATE TABLE `file_integrity` (
+  `file_id` integer not null primary key,
+  `verified_at` datetime not null,
+  `issue_type` varchar(255),
+  `issue_message` text,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_file_integrity_on_verified_at` on `file_integrity` (`verified_at`);
+CREATE INDEX `index_file_integrity_on_issue_type` on `file_integrity` (`issue_type`);
@@ -0,0 +1,4 @@
+CREATE TABLE `offline_paths` (
+  `path` varchar(255) not null primary key,
+  `offline_since` datetime not null
+);
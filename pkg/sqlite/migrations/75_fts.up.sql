@@ -0,0 +1,670 @@
+-- Full-text search indexes. Each index is populated from a view containing
+-- the search document of each row, and is kept in sync by triggers on the
+-- tables the document is built from.
+
+CREATE VIEW `scenes_fts_documents` AS
+SELECT
+  `scenes`.`id`,
+  `scenes`.`title` AS `title`,
+  `scenes`.`code` AS `code`,
+  `scenes`.`details` AS `details`,
+  `scenes`.`director` AS `director`,
+  (SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `scenes_files` INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id` INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id` WHERE `scenes_files`.`scene_id` = `scenes`.`id`) AS `paths`,
+  (SELECT group_concat(`files_fingerprints`.`fingerprint`, ' ') FROM `scenes_files` INNER JOIN `files_fingerprints` ON `files_fingerprints`.`file_id` = `scenes_files`.`file_id` WHERE `scenes_files`.`scene_id` = `scenes`.`id`) AS `fingerprints`,
+  (SELECT `studios`.`name` FROM `studios` WHERE `studios`.`id` = `scenes`.`studio_id`) AS `studio`,
+  (SELECT group_concat(`performers`.`name`, ' ') FROM `performers_scenes` INNER JOIN `performers` ON `performers`.`id` = `performers_scenes`.`performer_id` WHERE `performers_scenes`.`scene_id` = `scenes`.`id`) AS `performers`,
+  (SELECT group_concat(`tags`.`name`, ' ') FROM `scenes_tags` INNER JOIN `tags` ON `tags`.`id` = `scenes_tags`.`tag_id` WHERE `scenes_tags`.`scene_id` = `scenes`.`id`) AS `tags`,
+  (SELECT group_concat(`scene_markers`.`title`, ' ') FROM `scene_markers` WHERE `scene_markers`.`scene_id` = `scenes`.`id`) AS `markers`
+FROM `scenes`;
+
+CREATE VIRTUAL TABLE `scenes_fts` USING fts5(
+  `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents`;
+
+CREATE TRIGGER `scenes_fts_insert` AFTER INSERT ON `scenes` BEGIN
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `scenes_fts_delete` AFTER DELETE ON `scenes` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `scenes_fts_update` AFTER UPDATE OF `title`, `code`, `details`, `director`, `studio_id` ON `scenes` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scenes_files_insert` AFTER INSERT ON `scenes_files` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scenes_files_update` AFTER UPDATE ON `scenes_files` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`, NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`, NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scenes_files_delete` AFTER DELETE ON `scenes_files` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_files_update` AFTER UPDATE OF `basename`, `parent_folder_id` ON `files` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` = NEW.`id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `scenes_fts_folders_update` AFTER UPDATE OF `path` ON `folders` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scenes_files`.`scene_id` FROM `scenes_files` INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id` WHERE `files`.`parent_folder_id` = NEW.`id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scenes_files`.`scene_id` FROM `scenes_files` INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id` WHERE `files`.`parent_folder_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `scenes_fts_fingerprints_insert` AFTER INSERT ON `files_fingerprints` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` = NEW.`file_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` = NEW.`file_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_fingerprints_update` AFTER UPDATE ON `files_fingerprints` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` IN (OLD.`file_id`, NEW.`file_id`));
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` IN (OLD.`file_id`, NEW.`file_id`));
+END;
+
+CREATE TRIGGER `scenes_fts_fingerprints_delete` AFTER DELETE ON `files_fingerprints` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` = OLD.`file_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scene_id` FROM `scenes_files` WHERE `file_id` = OLD.`file_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_performers_scenes_insert` AFTER INSERT ON `performers_scenes` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_performers_scenes_update` AFTER UPDATE ON `performers_scenes` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`, NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`, NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_performers_scenes_delete` AFTER DELETE ON `performers_scenes` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scenes_tags_insert` AFTER INSERT ON `scenes_tags` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scenes_tags_update` AFTER UPDATE ON `scenes_tags` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`, NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`, NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scenes_tags_delete` AFTER DELETE ON `scenes_tags` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scene_markers_insert` AFTER INSERT ON `scene_markers` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scene_markers_update` AFTER UPDATE OF `title`, `scene_id` ON `scene_markers` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`, NEW.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`, NEW.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_scene_markers_delete` AFTER DELETE ON `scene_markers` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (OLD.`scene_id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (OLD.`scene_id`);
+END;
+
+CREATE TRIGGER `scenes_fts_performers_update` AFTER UPDATE OF `name` ON `performers` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scene_id` FROM `performers_scenes` WHERE `performer_id` = NEW.`id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scene_id` FROM `performers_scenes` WHERE `performer_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `scenes_fts_tags_update` AFTER UPDATE OF `name` ON `tags` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `scene_id` FROM `scenes_tags` WHERE `tag_id` = NEW.`id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `scene_id` FROM `scenes_tags` WHERE `tag_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `scenes_fts_studios_update` AFTER UPDATE OF `name` ON `studios` BEGIN
+  DELETE FROM `scenes_fts` WHERE `rowid` IN (SELECT `id` FROM `scenes` WHERE `studio_id` = NEW.`id`);
+  INSERT INTO `scenes_fts` (`rowid`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers`)
+  SELECT `id`, `title`, `code`, `details`, `director`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `markers` FROM `scenes_fts_documents` WHERE `id` IN (SELECT `id` FROM `scenes` WHERE `studio_id` = NEW.`id`);
+END;
+
+CREATE VIEW `galleries_fts_documents` AS
+SELECT
+  `galleries`.`id`,
+  `galleries`.`title` AS `title`,
+  `galleries`.`code` AS `code`,
+  `galleries`.`details` AS `details`,
+  `galleries`.`photographer` AS `photographer`,
+  trim(coalesce((SELECT `folders`.`path` FROM `folders` WHERE `folders`.`id` = `galleries`.`folder_id`), '') || ' ' || coalesce((SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `galleries_files` INNER JOIN `files` ON `files`.`id` = `galleries_files`.`file_id` INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id` WHERE `galleries_files`.`gallery_id` = `galleries`.`id`), '')) AS `paths`,
+  (SELECT group_concat(`files_fingerprints`.`fingerprint`, ' ') FROM `galleries_files` INNER JOIN `files_fingerprints` ON `files_fingerprints`.`file_id` = `galleries_files`.`file_id` WHERE `galleries_files`.`gallery_id` = `galleries`.`id`) AS `fingerprints`,
+  (SELECT `studios`.`name` FROM `studios` WHERE `studios`.`id` = `galleries`.`studio_id`) AS `studio`,
+  (SELECT group_concat(`performers`.`name`, ' ') FROM `performers_galleries` INNER JOIN `performers` ON `performers`.`id` = `performers_galleries`.`performer_id` WHERE `performers_galleries`.`gallery_id` = `galleries`.`id`) AS `performers`,
+  (SELECT group_concat(`tags`.`name`, ' ') FROM `galleries_tags` INNER JOIN `tags` ON `tags`.`id` = `galleries_tags`.`tag_id` WHERE `galleries_tags`.`gallery_id` = `galleries`.`id`) AS `tags`,
+  (SELECT group_concat(`galleries_chapters`.`title`, ' ') FROM `galleries_chapters` WHERE `galleries_chapters`.`gallery_id` = `galleries`.`id`) AS `chapters`
+FROM `galleries`;
+
+CREATE VIRTUAL TABLE `galleries_fts` USING fts5(
+  `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents`;
+
+CREATE TRIGGER `galleries_fts_insert` AFTER INSERT ON `galleries` BEGIN
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `galleries_fts_delete` AFTER DELETE ON `galleries` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `galleries_fts_update` AFTER UPDATE OF `title`, `code`, `details`, `photographer`, `folder_id`, `studio_id` ON `galleries` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_files_insert` AFTER INSERT ON `galleries_files` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_files_update` AFTER UPDATE ON `galleries_files` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_files_delete` AFTER DELETE ON `galleries_files` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_files_update` AFTER UPDATE OF `basename`, `parent_folder_id` ON `files` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` = NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `galleries_fts_folders_update` AFTER UPDATE OF `path` ON `folders` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `galleries_files`.`gallery_id` FROM `galleries_files` INNER JOIN `files` ON `files`.`id` = `galleries_files`.`file_id` WHERE `files`.`parent_folder_id` = NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `galleries_files`.`gallery_id` FROM `galleries_files` INNER JOIN `files` ON `files`.`id` = `galleries_files`.`file_id` WHERE `files`.`parent_folder_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `galleries_fts_fingerprints_insert` AFTER INSERT ON `files_fingerprints` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` = NEW.`file_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` = NEW.`file_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_fingerprints_update` AFTER UPDATE ON `files_fingerprints` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` IN (OLD.`file_id`, NEW.`file_id`));
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` IN (OLD.`file_id`, NEW.`file_id`));
+END;
+
+CREATE TRIGGER `galleries_fts_fingerprints_delete` AFTER DELETE ON `files_fingerprints` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` = OLD.`file_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `gallery_id` FROM `galleries_files` WHERE `file_id` = OLD.`file_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_folder_update` AFTER UPDATE OF `path` ON `folders` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `id` FROM `galleries` WHERE `folder_id` = NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `id` FROM `galleries` WHERE `folder_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `galleries_fts_performers_galleries_insert` AFTER INSERT ON `performers_galleries` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_performers_galleries_update` AFTER UPDATE ON `performers_galleries` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_performers_galleries_delete` AFTER DELETE ON `performers_galleries` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_tags_insert` AFTER INSERT ON `galleries_tags` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_tags_update` AFTER UPDATE ON `galleries_tags` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_tags_delete` AFTER DELETE ON `galleries_tags` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_chapters_insert` AFTER INSERT ON `galleries_chapters` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_chapters_update` AFTER UPDATE OF `title`, `gallery_id` ON `galleries_chapters` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`, NEW.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_galleries_chapters_delete` AFTER DELETE ON `galleries_chapters` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (OLD.`gallery_id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (OLD.`gallery_id`);
+END;
+
+CREATE TRIGGER `galleries_fts_performers_update` AFTER UPDATE OF `name` ON `performers` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `gallery_id` FROM `performers_galleries` WHERE `performer_id` = NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `gallery_id` FROM `performers_galleries` WHERE `performer_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `galleries_fts_tags_update` AFTER UPDATE OF `name` ON `tags` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `gallery_id` FROM `galleries_tags` WHERE `tag_id` = NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `gallery_id` FROM `galleries_tags` WHERE `tag_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `galleries_fts_studios_update` AFTER UPDATE OF `name` ON `studios` BEGIN
+  DELETE FROM `galleries_fts` WHERE `rowid` IN (SELECT `id` FROM `galleries` WHERE `studio_id` = NEW.`id`);
+  INSERT INTO `galleries_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`, `chapters` FROM `galleries_fts_documents` WHERE `id` IN (SELECT `id` FROM `galleries` WHERE `studio_id` = NEW.`id`);
+END;
+
+CREATE VIEW `images_fts_documents` AS
+SELECT
+  `images`.`id`,
+  `images`.`title` AS `title`,
+  `images`.`code` AS `code`,
+  `images`.`details` AS `details`,
+  `images`.`photographer` AS `photographer`,
+  (SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `images_files` INNER JOIN `files` ON `files`.`id` = `images_files`.`file_id` INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id` WHERE `images_files`.`image_id` = `images`.`id`) AS `paths`,
+  (SELECT group_concat(`files_fingerprints`.`fingerprint`, ' ') FROM `images_files` INNER JOIN `files_fingerprints` ON `files_fingerprints`.`file_id` = `images_files`.`file_id` WHERE `images_files`.`image_id` = `images`.`id`) AS `fingerprints`,
+  (SELECT `studios`.`name` FROM `studios` WHERE `studios`.`id` = `images`.`studio_id`) AS `studio`,
+  (SELECT group_concat(`performers`.`name`, ' ') FROM `performers_images` INNER JOIN `performers` ON `performers`.`id` = `performers_images`.`performer_id` WHERE `performers_images`.`image_id` = `images`.`id`) AS `performers`,
+  (SELECT group_concat(`tags`.`name`, ' ') FROM `images_tags` INNER JOIN `tags` ON `tags`.`id` = `images_tags`.`tag_id` WHERE `images_tags`.`image_id` = `images`.`id`) AS `tags`
+FROM `images`;
+
+CREATE VIRTUAL TABLE `images_fts` USING fts5(
+  `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents`;
+
+CREATE TRIGGER `images_fts_insert` AFTER INSERT ON `images` BEGIN
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `images_fts_delete` AFTER DELETE ON `images` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `images_fts_update` AFTER UPDATE OF `title`, `code`, `details`, `photographer`, `studio_id` ON `images` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `images_fts_images_files_insert` AFTER INSERT ON `images_files` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (NEW.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (NEW.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_images_files_update` AFTER UPDATE ON `images_files` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (OLD.`image_id`, NEW.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (OLD.`image_id`, NEW.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_images_files_delete` AFTER DELETE ON `images_files` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (OLD.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (OLD.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_files_update` AFTER UPDATE OF `basename`, `parent_folder_id` ON `files` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` = NEW.`id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `images_fts_folders_update` AFTER UPDATE OF `path` ON `folders` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `images_files`.`image_id` FROM `images_files` INNER JOIN `files` ON `files`.`id` = `images_files`.`file_id` WHERE `files`.`parent_folder_id` = NEW.`id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `images_files`.`image_id` FROM `images_files` INNER JOIN `files` ON `files`.`id` = `images_files`.`file_id` WHERE `files`.`parent_folder_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `images_fts_fingerprints_insert` AFTER INSERT ON `files_fingerprints` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` = NEW.`file_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` = NEW.`file_id`);
+END;
+
+CREATE TRIGGER `images_fts_fingerprints_update` AFTER UPDATE ON `files_fingerprints` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` IN (OLD.`file_id`, NEW.`file_id`));
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` IN (OLD.`file_id`, NEW.`file_id`));
+END;
+
+CREATE TRIGGER `images_fts_fingerprints_delete` AFTER DELETE ON `files_fingerprints` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` = OLD.`file_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `image_id` FROM `images_files` WHERE `file_id` = OLD.`file_id`);
+END;
+
+CREATE TRIGGER `images_fts_performers_images_insert` AFTER INSERT ON `performers_images` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (NEW.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (NEW.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_performers_images_update` AFTER UPDATE ON `performers_images` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (OLD.`image_id`, NEW.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (OLD.`image_id`, NEW.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_performers_images_delete` AFTER DELETE ON `performers_images` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (OLD.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (OLD.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_images_tags_insert` AFTER INSERT ON `images_tags` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (NEW.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (NEW.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_images_tags_update` AFTER UPDATE ON `images_tags` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (OLD.`image_id`, NEW.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (OLD.`image_id`, NEW.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_images_tags_delete` AFTER DELETE ON `images_tags` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (OLD.`image_id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (OLD.`image_id`);
+END;
+
+CREATE TRIGGER `images_fts_performers_update` AFTER UPDATE OF `name` ON `performers` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `image_id` FROM `performers_images` WHERE `performer_id` = NEW.`id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `image_id` FROM `performers_images` WHERE `performer_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `images_fts_tags_update` AFTER UPDATE OF `name` ON `tags` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `image_id` FROM `images_tags` WHERE `tag_id` = NEW.`id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `image_id` FROM `images_tags` WHERE `tag_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `images_fts_studios_update` AFTER UPDATE OF `name` ON `studios` BEGIN
+  DELETE FROM `images_fts` WHERE `rowid` IN (SELECT `id` FROM `images` WHERE `studio_id` = NEW.`id`);
+  INSERT INTO `images_fts` (`rowid`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags`)
+  SELECT `id`, `title`, `code`, `details`, `photographer`, `paths`, `fingerprints`, `studio`, `performers`, `tags` FROM `images_fts_documents` WHERE `id` IN (SELECT `id` FROM `images` WHERE `studio_id` = NEW.`id`);
+END;
+
+CREATE VIEW `scene_markers_fts_documents` AS
+SELECT
+  `scene_markers`.`id`,
+  `scene_markers`.`title` AS `title`,
+  (SELECT `scenes`.`title` FROM `scenes` WHERE `scenes`.`id` = `scene_markers`.`scene_id`) AS `scene`,
+  trim(coalesce((SELECT `tags`.`name` FROM `tags` WHERE `tags`.`id` = `scene_markers`.`primary_tag_id`), '') || ' ' || coalesce((SELECT group_concat(`tags`.`name`, ' ') FROM `scene_markers_tags` INNER JOIN `tags` ON `tags`.`id` = `scene_markers_tags`.`tag_id` WHERE `scene_markers_tags`.`scene_marker_id` = `scene_markers`.`id`), '')) AS `tags`
+FROM `scene_markers`;
+
+CREATE VIRTUAL TABLE `scene_markers_fts` USING fts5(
+  `title`, `scene`, `tags`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents`;
+
+CREATE TRIGGER `scene_markers_fts_insert` AFTER INSERT ON `scene_markers` BEGIN
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `scene_markers_fts_delete` AFTER DELETE ON `scene_markers` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `scene_markers_fts_update` AFTER UPDATE OF `title`, `scene_id`, `primary_tag_id` ON `scene_markers` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `scene_markers_fts_scene_markers_tags_insert` AFTER INSERT ON `scene_markers_tags` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` IN (NEW.`scene_marker_id`);
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` IN (NEW.`scene_marker_id`);
+END;
+
+CREATE TRIGGER `scene_markers_fts_scene_markers_tags_update` AFTER UPDATE ON `scene_markers_tags` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` IN (OLD.`scene_marker_id`, NEW.`scene_marker_id`);
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` IN (OLD.`scene_marker_id`, NEW.`scene_marker_id`);
+END;
+
+CREATE TRIGGER `scene_markers_fts_scene_markers_tags_delete` AFTER DELETE ON `scene_markers_tags` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` IN (OLD.`scene_marker_id`);
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` IN (OLD.`scene_marker_id`);
+END;
+
+CREATE TRIGGER `scene_markers_fts_scenes_update` AFTER UPDATE OF `title` ON `scenes` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` IN (SELECT `id` FROM `scene_markers` WHERE `scene_id` = NEW.`id`);
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` IN (SELECT `id` FROM `scene_markers` WHERE `scene_id` = NEW.`id`);
+END;
+
+CREATE TRIGGER `scene_markers_fts_tags_update` AFTER UPDATE OF `name` ON `tags` BEGIN
+  DELETE FROM `scene_markers_fts` WHERE `rowid` IN (SELECT `id` FROM `scene_markers` WHERE `primary_tag_id` = NEW.`id` UNION SELECT `scene_marker_id` FROM `scene_markers_tags` WHERE `tag_id` = NEW.`id`);
+  INSERT INTO `scene_markers_fts` (`rowid`, `title`, `scene`, `tags`)
+  SELECT `id`, `title`, `scene`, `tags` FROM `scene_markers_fts_documents` WHERE `id` IN (SELECT `id` FROM `scene_markers` WHERE `primary_tag_id` = NEW.`id` UNION SELECT `scene_marker_id` FROM `scene_markers_tags` WHERE `tag_id` = NEW.`id`);
+END;
+
+CREATE VIEW `performers_fts_documents` AS
+SELECT
+  `performers`.`id`,
+  `performers`.`name` AS `name`,
+  `performers`.`disambiguation` AS `disambiguation`,
+  (SELECT group_concat(`performer_aliases`.`alias`, ' ') FROM `performer_aliases` WHERE `performer_aliases`.`performer_id` = `performers`.`id`) AS `aliases`
+FROM `performers`;
+
+CREATE VIRTUAL TABLE `performers_fts` USING fts5(
+  `name`, `disambiguation`, `aliases`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `performers_fts` (`rowid`, `name`, `disambiguation`, `aliases`)
+SELECT `id`, `name`, `disambiguation`, `aliases` FROM `performers_fts_documents`;
+
+CREATE TRIGGER `performers_fts_insert` AFTER INSERT ON `performers` BEGIN
+  INSERT INTO `performers_fts` (`rowid`, `name`, `disambiguation`, `aliases`)
+  SELECT `id`, `name`, `disambiguation`, `aliases` FROM `performers_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `performers_fts_delete` AFTER DELETE ON `performers` BEGIN
+  DELETE FROM `performers_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `performers_fts_update` AFTER UPDATE OF `name`, `disambiguation` ON `performers` BEGIN
+  DELETE FROM `performers_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `performers_fts` (`rowid`, `name`, `disambiguation`, `aliases`)
+  SELECT `id`, `name`, `disambiguation`, `aliases` FROM `performers_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `performers_fts_performer_aliases_insert` AFTER INSERT ON `performer_aliases` BEGIN
+  DELETE FROM `performers_fts` WHERE `rowid` IN (NEW.`performer_id`);
+  INSERT INTO `performers_fts` (`rowid`, `name`, `disambiguation`, `aliases`)
+  SELECT `id`, `name`, `disambiguation`, `aliases` FROM `performers_fts_documents` WHERE `id` IN (NEW.`performer_id`);
+END;
+
+CREATE TRIGGER `performers_fts_performer_aliases_update` AFTER UPDATE ON `performer_aliases` BEGIN
+  DELETE FROM `performers_fts` WHERE `rowid` IN (OLD.`performer_id`, NEW.`performer_id`);
+  INSERT INTO `performers_fts` (`rowid`, `name`, `disambiguation`, `aliases`)
+  SELECT `id`, `name`, `disambiguation`, `aliases` FROM `performers_fts_documents` WHERE `id` IN (OLD.`performer_id`, NEW.`performer_id`);
+END;
+
+CREATE TRIGGER `performers_fts_performer_aliases_delete` AFTER DELETE ON `performer_aliases` BEGIN
+  DELETE FROM `performers_fts` WHERE `rowid` IN (OLD.`performer_id`);
+  INSERT INTO `performers_fts` (`rowid`, `name`, `disambiguation`, `aliases`)
+  SELECT `id`, `name`, `disambiguation`, `aliases` FROM `performers_fts_documents` WHERE `id` IN (OLD.`performer_id`);
+END;
+
+CREATE VIEW `studios_fts_documents` AS
+SELECT
+  `studios`.`id`,
+  `studios`.`name` AS `name`,
+  (SELECT group_concat(`studio_aliases`.`alias`, ' ') FROM `studio_aliases` WHERE `studio_aliases`.`studio_id` = `studios`.`id`) AS `aliases`
+FROM `studios`;
+
+CREATE VIRTUAL TABLE `studios_fts` USING fts5(
+  `name`, `aliases`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `studios_fts` (`rowid`, `name`, `aliases`)
+SELECT `id`, `name`, `aliases` FROM `studios_fts_documents`;
+
+CREATE TRIGGER `studios_fts_insert` AFTER INSERT ON `studios` BEGIN
+  INSERT INTO `studios_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `studios_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `studios_fts_delete` AFTER DELETE ON `studios` BEGIN
+  DELETE FROM `studios_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `studios_fts_update` AFTER UPDATE OF `name` ON `studios` BEGIN
+  DELETE FROM `studios_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `studios_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `studios_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `studios_fts_studio_aliases_insert` AFTER INSERT ON `studio_aliases` BEGIN
+  DELETE FROM `studios_fts` WHERE `rowid` IN (NEW.`studio_id`);
+  INSERT INTO `studios_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `studios_fts_documents` WHERE `id` IN (NEW.`studio_id`);
+END;
+
+CREATE TRIGGER `studios_fts_studio_aliases_update` AFTER UPDATE ON `studio_aliases` BEGIN
+  DELETE FROM `studios_fts` WHERE `rowid` IN (OLD.`studio_id`, NEW.`studio_id`);
+  INSERT INTO `studios_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `studios_fts_documents` WHERE `id` IN (OLD.`studio_id`, NEW.`studio_id`);
+END;
+
+CREATE TRIGGER `studios_fts_studio_aliases_delete` AFTER DELETE ON `studio_aliases` BEGIN
+  DELETE FROM `studios_fts` WHERE `rowid` IN (OLD.`studio_id`);
+  INSERT INTO `studios_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `studios_fts_documents` WHERE `id` IN (OLD.`studio_id`);
+END;
+
+CREATE VIEW `tags_fts_documents` AS
+SELECT
+  `tags`.`id`,
+  `tags`.`name` AS `name`,
+  (SELECT group_concat(`tag_aliases`.`alias`, ' ') FROM `tag_aliases` WHERE `tag_aliases`.`tag_id` = `tags`.`id`) AS `aliases`
+FROM `tags`;
+
+CREATE VIRTUAL TABLE `tags_fts` USING fts5(
+  `name`, `aliases`,
+  tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO `tags_fts` (`rowid`, `name`, `aliases`)
+SELECT `id`, `name`, `aliases` FROM `tags_fts_documents`;
+
+CREATE TRIGGER `tags_fts_insert` AFTER INSERT ON `tags` BEGIN
+  INSERT INTO `tags_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `tags_fts_documents` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `tags_fts_delete` AFTER DELETE ON `tags` BEGIN
+  DELETE FROM `tags_fts` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `tags_fts_update` AFTER UPDATE OF `name` ON `tags` BEGIN
+  DELETE FROM `tags_fts` WHERE `rowid` IN (NEW.`id`);
+  INSERT INTO `tags_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `tags_fts_documents` WHERE `id` IN (NEW.`id`);
+END;
+
+CREATE TRIGGER `tags_fts_tag_aliases_insert` AFTER INSERT ON `tag_aliases` BEGIN
+  DELETE FROM `tags_fts` WHERE `rowid` IN (NEW.`tag_id`);
+  INSERT INTO `tags_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `tags_fts_documents` WHERE `id` IN (NEW.`tag_id`);
+END;
+
+CREATE TRIGGER `tags_fts_tag_aliases_update` AFTER UPDATE ON `tag_aliases` BEGIN
+  DELETE FROM `tags_fts` WHERE `rowid` IN (OLD.`tag_id`, NEW.`tag_id`);
+  INSERT INTO `tags_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `tags_fts_documents` WHERE `id` IN (OLD.`tag_id`, NEW.`tag_id`);
+END;
+
+CREATE TRIGGER `tags_fts_tag_aliases_delete` AFTER DELETE ON `tag_aliases` BEGIN
+  DELETE FROM `tags_fts` WHERE `rowid` IN (OLD.`tag_id`);
+  INSERT INTO `tags_fts` (`rowid`, `name`, `aliases`)
+  SELECT `id`, `name`, `aliases` FROM `tags_fts_documents` WHERE `id` IN (OLD.`tag_id`);
+END;
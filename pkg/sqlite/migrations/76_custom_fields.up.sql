@@ -0,0 +1,66 @@
+CREATE TABLE `custom_field_definitions` (
+  `id` integer not null primary key autoincrement,
+  `entity_type` varchar(255) not null,
+  `name` varchar(255) not null,
+  `type` varchar(255) not null,
+  `options` text,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_custom_field_definitions_on_entity_type_name_unique` on `custom_field_definitions` (`entity_type`, `name`);
+
+CREATE TABLE `scene_custom_fields` (
+  `scene_id` integer not null,
+  `field_id` integer not null,
+  `value` blob not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_field_definitions`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `field_id`)
+);
+
+CREATE INDEX `index_scene_custom_fields_on_field_id_value` on `scene_custom_fields` (`field_id`, `value`);
+
+CREATE TABLE `performer_custom_fields` (
+  `performer_id` integer not null,
+  `field_id` integer not null,
+  `value` blob not null,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_field_definitions`(`id`) on delete CASCADE,
+  PRIMARY KEY(`performer_id`, `field_id`)
+);
+
+CREATE INDEX `index_performer_custom_fields_on_field_id_value` on `performer_custom_fields` (`field_id`, `value`);
+
+CREATE TABLE `studio_custom_fields` (
+  `studio_id` integer not null,
+  `field_id` integer not null,
+  `value` blob not null,
+  foreign key(`studio_id`) references `studios`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_field_definitions`(`id`) on delete CASCADE,
+  PRIMARY KEY(`studio_id`, `field_id`)
+);
+
+CREATE INDEX `index_studio_custom_fields_on_field_id_value` on `studio_custom_fields` (`field_id`, `value`);
+
+CREATE TABLE `gallery_custom_fields` (
+  `gallery_id` integer not null,
+  `field_id` integer not null,
+  `value` blob not null,
+  foreign key(`gallery_id`) references `galleries`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_field_definitions`(`id`) on delete CASCADE,
+  PRIMARY KEY(`gallery_id`, `field_id`)
+);
+
+CREATE INDEX `index_gallery_custom_fields_on_field_id_value` on `gallery_custom_fields` (`field_id`, `value`);
+
+CREATE TABLE `group_custom_fields` (
+  `group_id` integer not null,
+  `field_id` integer not null,
+  `value` blob not null,
+  foreign key(`group_id`) references `groups`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_field_definitions`(`id`) on delete CASCADE,
+  PRIMARY KEY(`group_id`, `field_id`)
+);
+
+CREATE INDEX `index_group_custom_fields_on_field_id_value` on `group_custom_fields` (`field_id`, `value`);
@@ -0,0 +1,763 @@
+-- The change log records the objects changed by each transaction, so that
+-- clients can fetch the changes since they last synchronised. It is
+-- maintained by triggers, so that changes to relationships and cascading
+-- deletes are recorded in the same transaction as the change itself.
+
+CREATE TABLE `change_log` (
+  `id` integer not null primary key autoincrement,
+  `entity_type` varchar(255) not null,
+  `entity_id` integer not null,
+  `operation` varchar(255) not null,
+  `changed_at` datetime not null
+);
+
+CREATE INDEX `index_change_log_on_changed_at` on `change_log` (`changed_at`);
+
+-- log_id identifies this change log, so that cursors from a different
+-- database are rejected. pruned_sequence is the highest sequence number
+-- removed from the change log.
+CREATE TABLE `change_log_state` (
+  `log_id` varchar(255) not null,
+  `pruned_sequence` integer not null
+);
+
+INSERT INTO `change_log_state` (`log_id`, `pruned_sequence`) VALUES (lower(hex(randomblob(8))), 0);
+
+CREATE TRIGGER `change_log_scenes_insert` AFTER INSERT ON `scenes` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+-- resume_time and play_duration are updated throughout playback, so are not
+-- logged
+CREATE TRIGGER `change_log_scenes_update` AFTER UPDATE OF `id`, `title`, `details`, `date`, `rating`, `studio_id`, `organized`, `created_at`, `updated_at`, `code`, `director`, `cover_blob` ON `scenes` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_delete` AFTER DELETE ON `scenes` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_markers_insert` AFTER INSERT ON `scene_markers` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE_MARKER', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_markers_update` AFTER UPDATE ON `scene_markers` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE_MARKER', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_markers_delete` AFTER DELETE ON `scene_markers` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE_MARKER', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_insert` AFTER INSERT ON `images` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_update` AFTER UPDATE ON `images` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_delete` AFTER DELETE ON `images` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_insert` AFTER INSERT ON `galleries` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_update` AFTER UPDATE ON `galleries` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_delete` AFTER DELETE ON `galleries` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_chapters_insert` AFTER INSERT ON `galleries_chapters` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY_CHAPTER', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_chapters_update` AFTER UPDATE ON `galleries_chapters` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY_CHAPTER', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_chapters_delete` AFTER DELETE ON `galleries_chapters` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY_CHAPTER', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_insert` AFTER INSERT ON `groups` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_update` AFTER UPDATE ON `groups` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_delete` AFTER DELETE ON `groups` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_insert` AFTER INSERT ON `performers` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_update` AFTER UPDATE ON `performers` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_delete` AFTER DELETE ON `performers` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studios_insert` AFTER INSERT ON `studios` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studios_update` AFTER UPDATE ON `studios` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studios_delete` AFTER DELETE ON `studios` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_insert` AFTER INSERT ON `tags` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_update` AFTER UPDATE ON `tags` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_delete` AFTER DELETE ON `tags` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', OLD.`id`, 'DELETE', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+-- changes to relationships are recorded as changes to the object that owns
+-- them, unless the object itself is being deleted
+
+CREATE TRIGGER `change_log_scenes_files_scene_id_insert` AFTER INSERT ON `scenes_files`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_files_scene_id_update` AFTER UPDATE ON `scenes_files`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_files_scene_id_delete` AFTER DELETE ON `scenes_files`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_tags_scene_id_insert` AFTER INSERT ON `scenes_tags`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_tags_scene_id_update` AFTER UPDATE ON `scenes_tags`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_tags_scene_id_delete` AFTER DELETE ON `scenes_tags`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_scenes_scene_id_insert` AFTER INSERT ON `performers_scenes`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_scenes_scene_id_update` AFTER UPDATE ON `performers_scenes`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_scenes_scene_id_delete` AFTER DELETE ON `performers_scenes`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_galleries_scene_id_insert` AFTER INSERT ON `scenes_galleries`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_galleries_scene_id_update` AFTER UPDATE ON `scenes_galleries`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_galleries_scene_id_delete` AFTER DELETE ON `scenes_galleries`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_galleries_gallery_id_insert` AFTER INSERT ON `scenes_galleries`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_galleries_gallery_id_update` AFTER UPDATE ON `scenes_galleries`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_galleries_gallery_id_delete` AFTER DELETE ON `scenes_galleries`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_scenes_scene_id_insert` AFTER INSERT ON `groups_scenes`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_scenes_scene_id_update` AFTER UPDATE ON `groups_scenes`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_scenes_scene_id_delete` AFTER DELETE ON `groups_scenes`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_urls_scene_id_insert` AFTER INSERT ON `scene_urls`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_urls_scene_id_update` AFTER UPDATE ON `scene_urls`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_urls_scene_id_delete` AFTER DELETE ON `scene_urls`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_stash_ids_scene_id_insert` AFTER INSERT ON `scene_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_stash_ids_scene_id_update` AFTER UPDATE ON `scene_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_stash_ids_scene_id_delete` AFTER DELETE ON `scene_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_o_dates_scene_id_insert` AFTER INSERT ON `scenes_o_dates`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_o_dates_scene_id_update` AFTER UPDATE ON `scenes_o_dates`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_o_dates_scene_id_delete` AFTER DELETE ON `scenes_o_dates`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_view_dates_scene_id_insert` AFTER INSERT ON `scenes_view_dates`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_view_dates_scene_id_update` AFTER UPDATE ON `scenes_view_dates`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scenes_view_dates_scene_id_delete` AFTER DELETE ON `scenes_view_dates`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_custom_fields_scene_id_insert` AFTER INSERT ON `scene_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_custom_fields_scene_id_update` AFTER UPDATE ON `scene_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = NEW.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', NEW.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_custom_fields_scene_id_delete` AFTER DELETE ON `scene_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `scenes` WHERE `id` = OLD.`scene_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE', OLD.`scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_markers_tags_scene_marker_id_insert` AFTER INSERT ON `scene_markers_tags`
+WHEN EXISTS (SELECT 1 FROM `scene_markers` WHERE `id` = NEW.`scene_marker_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE_MARKER', NEW.`scene_marker_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_markers_tags_scene_marker_id_update` AFTER UPDATE ON `scene_markers_tags`
+WHEN EXISTS (SELECT 1 FROM `scene_markers` WHERE `id` = NEW.`scene_marker_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE_MARKER', NEW.`scene_marker_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_scene_markers_tags_scene_marker_id_delete` AFTER DELETE ON `scene_markers_tags`
+WHEN EXISTS (SELECT 1 FROM `scene_markers` WHERE `id` = OLD.`scene_marker_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('SCENE_MARKER', OLD.`scene_marker_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_files_image_id_insert` AFTER INSERT ON `images_files`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_files_image_id_update` AFTER UPDATE ON `images_files`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_files_image_id_delete` AFTER DELETE ON `images_files`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = OLD.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', OLD.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_tags_image_id_insert` AFTER INSERT ON `images_tags`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_tags_image_id_update` AFTER UPDATE ON `images_tags`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_images_tags_image_id_delete` AFTER DELETE ON `images_tags`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = OLD.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', OLD.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_images_image_id_insert` AFTER INSERT ON `performers_images`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_images_image_id_update` AFTER UPDATE ON `performers_images`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_images_image_id_delete` AFTER DELETE ON `performers_images`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = OLD.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', OLD.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_images_image_id_insert` AFTER INSERT ON `galleries_images`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_images_image_id_update` AFTER UPDATE ON `galleries_images`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_images_image_id_delete` AFTER DELETE ON `galleries_images`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = OLD.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', OLD.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_images_gallery_id_insert` AFTER INSERT ON `galleries_images`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_images_gallery_id_update` AFTER UPDATE ON `galleries_images`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_images_gallery_id_delete` AFTER DELETE ON `galleries_images`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_image_urls_image_id_insert` AFTER INSERT ON `image_urls`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_image_urls_image_id_update` AFTER UPDATE ON `image_urls`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = NEW.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', NEW.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_image_urls_image_id_delete` AFTER DELETE ON `image_urls`
+WHEN EXISTS (SELECT 1 FROM `images` WHERE `id` = OLD.`image_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('IMAGE', OLD.`image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_files_gallery_id_insert` AFTER INSERT ON `galleries_files`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_files_gallery_id_update` AFTER UPDATE ON `galleries_files`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_files_gallery_id_delete` AFTER DELETE ON `galleries_files`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_tags_gallery_id_insert` AFTER INSERT ON `galleries_tags`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_tags_gallery_id_update` AFTER UPDATE ON `galleries_tags`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_galleries_tags_gallery_id_delete` AFTER DELETE ON `galleries_tags`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_galleries_gallery_id_insert` AFTER INSERT ON `performers_galleries`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_galleries_gallery_id_update` AFTER UPDATE ON `performers_galleries`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_galleries_gallery_id_delete` AFTER DELETE ON `performers_galleries`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_gallery_urls_gallery_id_insert` AFTER INSERT ON `gallery_urls`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_gallery_urls_gallery_id_update` AFTER UPDATE ON `gallery_urls`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_gallery_urls_gallery_id_delete` AFTER DELETE ON `gallery_urls`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_gallery_custom_fields_gallery_id_insert` AFTER INSERT ON `gallery_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_gallery_custom_fields_gallery_id_update` AFTER UPDATE ON `gallery_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = NEW.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', NEW.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_gallery_custom_fields_gallery_id_delete` AFTER DELETE ON `gallery_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `galleries` WHERE `id` = OLD.`gallery_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GALLERY', OLD.`gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_tags_group_id_insert` AFTER INSERT ON `groups_tags`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_tags_group_id_update` AFTER UPDATE ON `groups_tags`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_tags_group_id_delete` AFTER DELETE ON `groups_tags`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = OLD.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', OLD.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_group_urls_group_id_insert` AFTER INSERT ON `group_urls`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_group_urls_group_id_update` AFTER UPDATE ON `group_urls`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_group_urls_group_id_delete` AFTER DELETE ON `group_urls`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = OLD.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', OLD.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_relations_containing_id_insert` AFTER INSERT ON `groups_relations`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`containing_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`containing_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_relations_containing_id_update` AFTER UPDATE ON `groups_relations`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`containing_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`containing_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_relations_containing_id_delete` AFTER DELETE ON `groups_relations`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = OLD.`containing_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', OLD.`containing_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_relations_sub_id_insert` AFTER INSERT ON `groups_relations`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`sub_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`sub_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_relations_sub_id_update` AFTER UPDATE ON `groups_relations`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`sub_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`sub_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_groups_relations_sub_id_delete` AFTER DELETE ON `groups_relations`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = OLD.`sub_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', OLD.`sub_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_group_custom_fields_group_id_insert` AFTER INSERT ON `group_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_group_custom_fields_group_id_update` AFTER UPDATE ON `group_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = NEW.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', NEW.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_group_custom_fields_group_id_delete` AFTER DELETE ON `group_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `groups` WHERE `id` = OLD.`group_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('GROUP', OLD.`group_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_aliases_performer_id_insert` AFTER INSERT ON `performer_aliases`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_aliases_performer_id_update` AFTER UPDATE ON `performer_aliases`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_aliases_performer_id_delete` AFTER DELETE ON `performer_aliases`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = OLD.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', OLD.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_urls_performer_id_insert` AFTER INSERT ON `performer_urls`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_urls_performer_id_update` AFTER UPDATE ON `performer_urls`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_urls_performer_id_delete` AFTER DELETE ON `performer_urls`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = OLD.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', OLD.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_stash_ids_performer_id_insert` AFTER INSERT ON `performer_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_stash_ids_performer_id_update` AFTER UPDATE ON `performer_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_stash_ids_performer_id_delete` AFTER DELETE ON `performer_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = OLD.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', OLD.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_tags_performer_id_insert` AFTER INSERT ON `performers_tags`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_tags_performer_id_update` AFTER UPDATE ON `performers_tags`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performers_tags_performer_id_delete` AFTER DELETE ON `performers_tags`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = OLD.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', OLD.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_custom_fields_performer_id_insert` AFTER INSERT ON `performer_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_custom_fields_performer_id_update` AFTER UPDATE ON `performer_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = NEW.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', NEW.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_performer_custom_fields_performer_id_delete` AFTER DELETE ON `performer_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `performers` WHERE `id` = OLD.`performer_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('PERFORMER', OLD.`performer_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_aliases_studio_id_insert` AFTER INSERT ON `studio_aliases`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_aliases_studio_id_update` AFTER UPDATE ON `studio_aliases`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_aliases_studio_id_delete` AFTER DELETE ON `studio_aliases`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = OLD.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', OLD.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_stash_ids_studio_id_insert` AFTER INSERT ON `studio_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_stash_ids_studio_id_update` AFTER UPDATE ON `studio_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_stash_ids_studio_id_delete` AFTER DELETE ON `studio_stash_ids`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = OLD.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', OLD.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studios_tags_studio_id_insert` AFTER INSERT ON `studios_tags`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studios_tags_studio_id_update` AFTER UPDATE ON `studios_tags`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studios_tags_studio_id_delete` AFTER DELETE ON `studios_tags`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = OLD.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', OLD.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_custom_fields_studio_id_insert` AFTER INSERT ON `studio_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_custom_fields_studio_id_update` AFTER UPDATE ON `studio_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = NEW.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', NEW.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_studio_custom_fields_studio_id_delete` AFTER DELETE ON `studio_custom_fields`
+WHEN EXISTS (SELECT 1 FROM `studios` WHERE `id` = OLD.`studio_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('STUDIO', OLD.`studio_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tag_aliases_tag_id_insert` AFTER INSERT ON `tag_aliases`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = NEW.`tag_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`tag_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tag_aliases_tag_id_update` AFTER UPDATE ON `tag_aliases`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = NEW.`tag_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`tag_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tag_aliases_tag_id_delete` AFTER DELETE ON `tag_aliases`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = OLD.`tag_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', OLD.`tag_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_relations_parent_id_insert` AFTER INSERT ON `tags_relations`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = NEW.`parent_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`parent_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_relations_parent_id_update` AFTER UPDATE ON `tags_relations`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = NEW.`parent_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`parent_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_relations_parent_id_delete` AFTER DELETE ON `tags_relations`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = OLD.`parent_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', OLD.`parent_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_relations_child_id_insert` AFTER INSERT ON `tags_relations`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = NEW.`child_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`child_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_relations_child_id_update` AFTER UPDATE ON `tags_relations`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = NEW.`child_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', NEW.`child_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+CREATE TRIGGER `change_log_tags_relations_child_id_delete` AFTER DELETE ON `tags_relations`
+WHEN EXISTS (SELECT 1 FROM `tags` WHERE `id` = OLD.`child_id`) BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) VALUES ('TAG', OLD.`child_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now'));
+END;
+
+-- changes to files are recorded as changes to the objects they belong to
+
+CREATE TRIGGER `change_log_files_update` AFTER UPDATE ON `files` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'SCENE', `scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `scenes_files` WHERE `file_id` = NEW.`id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'IMAGE', `image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `images_files` WHERE `file_id` = NEW.`id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'GALLERY', `gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `galleries_files` WHERE `file_id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `change_log_files_fingerprints_insert` AFTER INSERT ON `files_fingerprints` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'SCENE', `scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `scenes_files` WHERE `file_id` = NEW.`file_id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'IMAGE', `image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `images_files` WHERE `file_id` = NEW.`file_id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'GALLERY', `gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `galleries_files` WHERE `file_id` = NEW.`file_id`;
+END;
+
+CREATE TRIGGER `change_log_files_fingerprints_update` AFTER UPDATE ON `files_fingerprints` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'SCENE', `scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `scenes_files` WHERE `file_id` = NEW.`file_id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'IMAGE', `image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `images_files` WHERE `file_id` = NEW.`file_id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'GALLERY', `gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `galleries_files` WHERE `file_id` = NEW.`file_id`;
+END;
+
+CREATE TRIGGER `change_log_files_fingerprints_delete` AFTER DELETE ON `files_fingerprints` BEGIN
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'SCENE', `scene_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `scenes_files` WHERE `file_id` = OLD.`file_id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'IMAGE', `image_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `images_files` WHERE `file_id` = OLD.`file_id`;
+  INSERT INTO `change_log` (`entity_type`, `entity_id`, `operation`, `changed_at`) SELECT 'GALLERY', `gallery_id`, 'UPSERT', strftime('%Y-%m-%dT%H:%M:%SZ', 'now') FROM `galleries_files` WHERE `file_id` = OLD.`file_id`;
+END;
@@ -0,0 +1,583 @@
+-- Initial PostgreSQL schema, equivalent to the SQLite schema at version 75.
+-- Full-text search tables are not created, since searches use LIKE clauses
+-- on PostgreSQL.
+
+-- case insensitive natural sort, equivalent to the NATURAL_CI collation
+-- registered by the SQLite driver
+CREATE COLLATION "natural_ci" (provider = icu, locale = 'und-u-kn-true-ks-level2', deterministic = false);
+CREATE COLLATION "nocase" (provider = icu, locale = 'und-u-ks-level2', deterministic = false);
+
+-- functions registered by the SQLite driver
+CREATE FUNCTION "phash_distance"(a BIGINT, b BIGINT) RETURNS INTEGER AS $$
+  SELECT length(replace(((a # b)::bit(64))::text, '0', ''))
+$$ LANGUAGE SQL IMMUTABLE STRICT;
+
+CREATE FUNCTION "basename"(path TEXT) RETURNS TEXT AS $$
+  SELECT regexp_replace(path, '^.*[/\\]', '')
+$$ LANGUAGE SQL IMMUTABLE STRICT;
+
+CREATE TABLE "blobs" (
+  "checksum" TEXT NOT NULL PRIMARY KEY,
+  "blob" BYTEA
+);
+
+CREATE TABLE "file_integrity" (
+  "file_id" BIGINT NOT NULL PRIMARY KEY,
+  "verified_at" TIMESTAMPTZ NOT NULL,
+  "issue_type" TEXT,
+  "issue_message" TEXT
+);
+
+CREATE TABLE "files" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "basename" TEXT NOT NULL,
+  "zip_file_id" BIGINT,
+  "parent_folder_id" BIGINT NOT NULL,
+  "size" BIGINT NOT NULL,
+  "mod_time" TIMESTAMPTZ NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  CHECK ("basename" != '')
+);
+
+CREATE TABLE "files_fingerprints" (
+  "file_id" BIGINT NOT NULL,
+  "type" TEXT NOT NULL,
+  "fingerprint" TEXT NOT NULL,
+  PRIMARY KEY ("file_id", "type", "fingerprint")
+);
+
+CREATE TABLE "folders" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "path" TEXT NOT NULL,
+  "parent_folder_id" BIGINT,
+  "mod_time" TIMESTAMPTZ NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "zip_file_id" BIGINT
+);
+
+CREATE TABLE "galleries" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "folder_id" BIGINT,
+  "title" TEXT,
+  "date" DATE,
+  "details" TEXT,
+  "studio_id" BIGINT,
+  "rating" BIGINT,
+  "organized" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "code" TEXT,
+  "photographer" TEXT
+);
+
+CREATE TABLE "galleries_chapters" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "title" TEXT NOT NULL,
+  "image_index" BIGINT NOT NULL,
+  "gallery_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE "galleries_files" (
+  "gallery_id" BIGINT NOT NULL,
+  "file_id" BIGINT NOT NULL,
+  "primary" BOOLEAN NOT NULL,
+  PRIMARY KEY ("gallery_id", "file_id")
+);
+
+CREATE TABLE "galleries_images" (
+  "gallery_id" BIGINT NOT NULL,
+  "image_id" BIGINT NOT NULL,
+  "cover" BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY ("gallery_id", "image_id")
+);
+
+CREATE TABLE "galleries_tags" (
+  "gallery_id" BIGINT NOT NULL,
+  "tag_id" BIGINT NOT NULL,
+  PRIMARY KEY ("gallery_id", "tag_id")
+);
+
+CREATE TABLE "gallery_urls" (
+  "gallery_id" BIGINT NOT NULL,
+  "position" BIGINT NOT NULL,
+  "url" TEXT NOT NULL,
+  PRIMARY KEY ("gallery_id", "position", "url")
+);
+
+CREATE TABLE "group_urls" (
+  "group_id" BIGINT NOT NULL,
+  "position" BIGINT NOT NULL,
+  "url" TEXT NOT NULL,
+  PRIMARY KEY ("group_id", "position", "url")
+);
+
+CREATE TABLE "groups" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "aliases" TEXT,
+  "duration" BIGINT,
+  "date" DATE,
+  "rating" BIGINT,
+  "studio_id" BIGINT,
+  "director" TEXT,
+  "description" TEXT,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "front_image_blob" TEXT,
+  "back_image_blob" TEXT
+);
+
+CREATE TABLE "groups_relations" (
+  "containing_id" BIGINT NOT NULL,
+  "sub_id" BIGINT NOT NULL,
+  "order_index" BIGINT NOT NULL,
+  "description" TEXT,
+  PRIMARY KEY ("containing_id", "sub_id"),
+  CHECK ("containing_id" != "sub_id")
+);
+
+CREATE TABLE "groups_scenes" (
+  "group_id" BIGINT,
+  "scene_id" BIGINT,
+  "scene_index" BIGINT,
+  PRIMARY KEY ("group_id", "scene_id")
+);
+
+CREATE TABLE "groups_tags" (
+  "group_id" BIGINT NOT NULL,
+  "tag_id" BIGINT NOT NULL,
+  PRIMARY KEY ("group_id", "tag_id")
+);
+
+CREATE TABLE "image_files" (
+  "file_id" BIGINT NOT NULL PRIMARY KEY,
+  "format" TEXT NOT NULL,
+  "width" BIGINT NOT NULL,
+  "height" BIGINT NOT NULL
+);
+
+CREATE TABLE "image_urls" (
+  "image_id" BIGINT NOT NULL,
+  "position" BIGINT NOT NULL,
+  "url" TEXT NOT NULL,
+  PRIMARY KEY ("image_id", "position", "url")
+);
+
+CREATE TABLE "images" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "title" TEXT,
+  "rating" BIGINT,
+  "studio_id" BIGINT,
+  "o_counter" BIGINT NOT NULL DEFAULT 0,
+  "organized" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "date" DATE,
+  "code" TEXT,
+  "photographer" TEXT,
+  "details" TEXT
+);
+
+CREATE TABLE "images_files" (
+  "image_id" BIGINT NOT NULL,
+  "file_id" BIGINT NOT NULL,
+  "primary" BOOLEAN NOT NULL,
+  PRIMARY KEY ("image_id", "file_id")
+);
+
+CREATE TABLE "images_tags" (
+  "image_id" BIGINT,
+  "tag_id" BIGINT,
+  PRIMARY KEY ("image_id", "tag_id")
+);
+
+CREATE TABLE "offline_paths" (
+  "path" TEXT NOT NULL PRIMARY KEY,
+  "offline_since" TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE "performer_aliases" (
+  "performer_id" BIGINT NOT NULL,
+  "alias" TEXT NOT NULL,
+  PRIMARY KEY ("performer_id", "alias")
+);
+
+CREATE TABLE "performer_stash_ids" (
+  "performer_id" BIGINT,
+  "endpoint" TEXT,
+  "stash_id" TEXT,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01T00:00:00Z'
+);
+
+CREATE TABLE "performer_urls" (
+  "performer_id" BIGINT NOT NULL,
+  "position" BIGINT NOT NULL,
+  "url" TEXT NOT NULL,
+  PRIMARY KEY ("performer_id", "position", "url")
+);
+
+CREATE TABLE "performers" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "disambiguation" TEXT,
+  "gender" TEXT,
+  "birthdate" DATE,
+  "ethnicity" TEXT,
+  "country" TEXT,
+  "eye_color" TEXT,
+  "height" BIGINT,
+  "measurements" TEXT,
+  "fake_tits" TEXT,
+  "career_length" TEXT,
+  "tattoos" TEXT,
+  "piercings" TEXT,
+  "favorite" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "details" TEXT,
+  "death_date" DATE,
+  "hair_color" TEXT,
+  "weight" BIGINT,
+  "rating" BIGINT,
+  "ignore_auto_tag" BOOLEAN NOT NULL DEFAULT FALSE,
+  "image_blob" TEXT,
+  "penis_length" DOUBLE PRECISION,
+  "circumcised" TEXT
+);
+
+CREATE TABLE "performers_galleries" (
+  "performer_id" BIGINT NOT NULL,
+  "gallery_id" BIGINT NOT NULL,
+  PRIMARY KEY ("gallery_id", "performer_id")
+);
+
+CREATE TABLE "performers_images" (
+  "performer_id" BIGINT,
+  "image_id" BIGINT,
+  PRIMARY KEY ("image_id", "performer_id")
+);
+
+CREATE TABLE "performers_scenes" (
+  "performer_id" BIGINT,
+  "scene_id" BIGINT,
+  PRIMARY KEY ("scene_id", "performer_id")
+);
+
+CREATE TABLE "performers_tags" (
+  "performer_id" BIGINT NOT NULL,
+  "tag_id" BIGINT NOT NULL,
+  PRIMARY KEY ("performer_id", "tag_id")
+);
+
+CREATE TABLE "saved_filters" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "mode" TEXT NOT NULL,
+  "find_filter" BYTEA,
+  "object_filter" BYTEA,
+  "ui_options" BYTEA
+);
+
+CREATE TABLE "scene_markers" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "title" TEXT NOT NULL,
+  "seconds" DOUBLE PRECISION NOT NULL,
+  "primary_tag_id" BIGINT NOT NULL,
+  "scene_id" BIGINT NOT NULL,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "end_seconds" DOUBLE PRECISION
+);
+
+CREATE TABLE "scene_markers_tags" (
+  "scene_marker_id" BIGINT,
+  "tag_id" BIGINT,
+  PRIMARY KEY ("scene_marker_id", "tag_id")
+);
+
+CREATE TABLE "scene_stash_ids" (
+  "scene_id" BIGINT NOT NULL,
+  "endpoint" TEXT NOT NULL,
+  "stash_id" TEXT NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01T00:00:00Z',
+  PRIMARY KEY ("scene_id", "endpoint")
+);
+
+CREATE TABLE "scene_urls" (
+  "scene_id" BIGINT NOT NULL,
+  "position" BIGINT NOT NULL,
+  "url" TEXT NOT NULL,
+  PRIMARY KEY ("scene_id", "position", "url")
+);
+
+CREATE TABLE "scenes" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "title" TEXT,
+  "details" TEXT,
+  "date" DATE,
+  "rating" BIGINT,
+  "studio_id" BIGINT,
+  "organized" BOOLEAN NOT NULL DEFAULT FALSE,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "code" TEXT,
+  "director" TEXT,
+  "resume_time" DOUBLE PRECISION NOT NULL DEFAULT 0,
+  "play_duration" DOUBLE PRECISION NOT NULL DEFAULT 0,
+  "cover_blob" TEXT
+);
+
+CREATE TABLE "scenes_files" (
+  "scene_id" BIGINT NOT NULL,
+  "file_id" BIGINT NOT NULL,
+  "primary" BOOLEAN NOT NULL,
+  PRIMARY KEY ("scene_id", "file_id")
+);
+
+CREATE TABLE "scenes_galleries" (
+  "scene_id" BIGINT NOT NULL,
+  "gallery_id" BIGINT NOT NULL,
+  PRIMARY KEY ("scene_id", "gallery_id")
+);
+
+CREATE TABLE "scenes_o_dates" (
+  "scene_id" BIGINT NOT NULL,
+  "o_date" TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE "scenes_tags" (
+  "scene_id" BIGINT,
+  "tag_id" BIGINT,
+  PRIMARY KEY ("scene_id", "tag_id")
+);
+
+CREATE TABLE "scenes_view_dates" (
+  "scene_id" BIGINT NOT NULL,
+  "view_date" TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE "studio_aliases" (
+  "studio_id" BIGINT NOT NULL,
+  "alias" TEXT NOT NULL,
+  PRIMARY KEY ("studio_id", "alias")
+);
+
+CREATE TABLE "studio_stash_ids" (
+  "studio_id" BIGINT,
+  "endpoint" TEXT,
+  "stash_id" TEXT,
+  "updated_at" TIMESTAMPTZ NOT NULL DEFAULT '1970-01-01T00:00:00Z'
+);
+
+CREATE TABLE "studios" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "url" TEXT,
+  "parent_id" BIGINT DEFAULT NULL CHECK ("id" IS DISTINCT FROM "parent_id"),
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "details" TEXT,
+  "rating" BIGINT,
+  "ignore_auto_tag" BOOLEAN NOT NULL DEFAULT FALSE,
+  "image_blob" TEXT,
+  "favorite" BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE "studios_tags" (
+  "studio_id" BIGINT NOT NULL,
+  "tag_id" BIGINT NOT NULL,
+  PRIMARY KEY ("studio_id", "tag_id")
+);
+
+CREATE TABLE "tag_aliases" (
+  "tag_id" BIGINT NOT NULL,
+  "alias" TEXT NOT NULL,
+  PRIMARY KEY ("tag_id", "alias")
+);
+
+CREATE TABLE "tags" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "name" TEXT,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL,
+  "ignore_auto_tag" BOOLEAN NOT NULL DEFAULT FALSE,
+  "description" TEXT,
+  "image_blob" TEXT,
+  "favorite" BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE "tags_relations" (
+  "parent_id" BIGINT,
+  "child_id" BIGINT,
+  PRIMARY KEY ("parent_id", "child_id")
+);
+
+CREATE TABLE "trash" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "object_type" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "files" TEXT,
+  "snapshot" BYTEA NOT NULL,
+  "deleted_at" TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE "video_captions" (
+  "file_id" BIGINT NOT NULL,
+  "language_code" TEXT NOT NULL,
+  "filename" TEXT NOT NULL,
+  "caption_type" TEXT NOT NULL,
+  PRIMARY KEY ("file_id", "language_code", "caption_type")
+);
+
+CREATE TABLE "video_files" (
+  "file_id" BIGINT NOT NULL PRIMARY KEY,
+  "duration" DOUBLE PRECISION NOT NULL,
+  "video_codec" TEXT NOT NULL,
+  "format" TEXT NOT NULL,
+  "audio_codec" TEXT NOT NULL,
+  "width" BIGINT NOT NULL,
+  "height" BIGINT NOT NULL,
+  "frame_rate" DOUBLE PRECISION NOT NULL,
+  "bit_rate" BIGINT NOT NULL,
+  "interactive" BOOLEAN NOT NULL DEFAULT FALSE,
+  "interactive_speed" BIGINT,
+  "chapters" TEXT
+);
+
+ALTER TABLE "file_integrity" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "files" ADD FOREIGN KEY ("parent_folder_id") REFERENCES "folders"("id") DEFERRABLE;
+ALTER TABLE "files" ADD FOREIGN KEY ("zip_file_id") REFERENCES "files"("id") DEFERRABLE;
+ALTER TABLE "files_fingerprints" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "folders" ADD FOREIGN KEY ("zip_file_id") REFERENCES "files"("id") DEFERRABLE;
+ALTER TABLE "folders" ADD FOREIGN KEY ("parent_folder_id") REFERENCES "folders"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "galleries" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "galleries" ADD FOREIGN KEY ("folder_id") REFERENCES "folders"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "galleries_chapters" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "galleries_files" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "galleries_files" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "galleries_images" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "galleries_images" ADD FOREIGN KEY ("image_id") REFERENCES "images"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "galleries_tags" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "galleries_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "gallery_urls" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "group_urls" ADD FOREIGN KEY ("group_id") REFERENCES "groups"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "groups" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "groups" ADD FOREIGN KEY ("front_image_blob") REFERENCES "blobs"("checksum") DEFERRABLE;
+ALTER TABLE "groups" ADD FOREIGN KEY ("back_image_blob") REFERENCES "blobs"("checksum") DEFERRABLE;
+ALTER TABLE "groups_relations" ADD FOREIGN KEY ("containing_id") REFERENCES "groups"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "groups_relations" ADD FOREIGN KEY ("sub_id") REFERENCES "groups"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "groups_scenes" ADD FOREIGN KEY ("group_id") REFERENCES "groups"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "groups_scenes" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "groups_tags" ADD FOREIGN KEY ("group_id") REFERENCES "groups"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "groups_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "image_files" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "image_urls" ADD FOREIGN KEY ("image_id") REFERENCES "images"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "images" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "images_files" ADD FOREIGN KEY ("image_id") REFERENCES "images"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "images_files" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "images_tags" ADD FOREIGN KEY ("image_id") REFERENCES "images"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "images_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performer_aliases" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performer_stash_ids" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performer_urls" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers" ADD FOREIGN KEY ("image_blob") REFERENCES "blobs"("checksum") DEFERRABLE;
+ALTER TABLE "performers_galleries" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_galleries" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_images" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_images" ADD FOREIGN KEY ("image_id") REFERENCES "images"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_scenes" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_scenes" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_tags" ADD FOREIGN KEY ("performer_id") REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "performers_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scene_markers" ADD FOREIGN KEY ("primary_tag_id") REFERENCES "tags"("id") DEFERRABLE;
+ALTER TABLE "scene_markers" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") DEFERRABLE;
+ALTER TABLE "scene_markers_tags" ADD FOREIGN KEY ("scene_marker_id") REFERENCES "scene_markers"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scene_markers_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scene_stash_ids" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scene_urls" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes" ADD FOREIGN KEY ("cover_blob") REFERENCES "blobs"("checksum") DEFERRABLE;
+ALTER TABLE "scenes" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "scenes_files" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_files" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_galleries" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_galleries" ADD FOREIGN KEY ("gallery_id") REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_o_dates" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_tags" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "scenes_view_dates" ADD FOREIGN KEY ("scene_id") REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "studio_aliases" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "studio_stash_ids" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "studios" ADD FOREIGN KEY ("parent_id") REFERENCES "studios"("id") ON DELETE SET NULL DEFERRABLE;
+ALTER TABLE "studios" ADD FOREIGN KEY ("image_blob") REFERENCES "blobs"("checksum") DEFERRABLE;
+ALTER TABLE "studios_tags" ADD FOREIGN KEY ("studio_id") REFERENCES "studios"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "studios_tags" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "tag_aliases" ADD FOREIGN KEY ("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "tags" ADD FOREIGN KEY ("image_blob") REFERENCES "blobs"("checksum") DEFERRABLE;
+ALTER TABLE "tags_relations" ADD FOREIGN KEY ("parent_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "tags_relations" ADD FOREIGN KEY ("child_id") REFERENCES "tags"("id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "video_captions" ADD FOREIGN KEY ("file_id") REFERENCES "video_files"("file_id") ON DELETE CASCADE DEFERRABLE;
+ALTER TABLE "video_files" ADD FOREIGN KEY ("file_id") REFERENCES "files"("id") ON DELETE CASCADE DEFERRABLE;
+
+CREATE INDEX "gallery_urls_url" ON "gallery_urls" ("url");
+CREATE INDEX "group_urls_url" ON "group_urls" ("url");
+CREATE INDEX "image_urls_url" ON "image_urls" ("url");
+CREATE INDEX "index_file_integrity_on_issue_type" ON "file_integrity" ("issue_type");
+CREATE INDEX "index_file_integrity_on_verified_at" ON "file_integrity" ("verified_at");
+CREATE INDEX "index_files_on_basename" ON "files" ("basename");
+CREATE UNIQUE INDEX "index_files_on_parent_folder_id_basename_unique" ON "files" ("parent_folder_id", "basename");
+CREATE UNIQUE INDEX "index_files_zip_basename_unique" ON "files" ("zip_file_id", "parent_folder_id", "basename") WHERE "zip_file_id" IS NOT NULL;
+CREATE INDEX "index_fingerprint_type_fingerprint" ON "files_fingerprints" ("type", "fingerprint");
+CREATE INDEX "index_folders_on_parent_folder_id" ON "folders" ("parent_folder_id");
+CREATE UNIQUE INDEX "index_folders_on_path_unique" ON "folders" ("path");
+CREATE INDEX "index_folders_on_zip_file_id" ON "folders" ("zip_file_id") WHERE "zip_file_id" IS NOT NULL;
+CREATE INDEX "index_galleries_chapters_on_gallery_id" ON "galleries_chapters" ("gallery_id");
+CREATE INDEX "index_galleries_files_file_id" ON "galleries_files" ("file_id");
+CREATE UNIQUE INDEX "index_galleries_images_gallery_id_cover" ON "galleries_images" ("gallery_id", "cover") WHERE "cover";
+CREATE INDEX "index_galleries_images_on_image_id" ON "galleries_images" ("image_id");
+CREATE UNIQUE INDEX "index_galleries_on_folder_id_unique" ON "galleries" ("folder_id");
+CREATE INDEX "index_galleries_on_studio_id" ON "galleries" ("studio_id");
+CREATE INDEX "index_galleries_tags_on_tag_id" ON "galleries_tags" ("tag_id");
+CREATE INDEX "index_groups_on_name" ON "groups" ("name");
+CREATE INDEX "index_groups_on_studio_id" ON "groups" ("studio_id");
+CREATE UNIQUE INDEX "index_groups_relations_order_index_unique" ON "groups_relations" ("containing_id", "order_index");
+CREATE INDEX "index_groups_relations_sub_id" ON "groups_relations" ("sub_id");
+CREATE INDEX "index_groups_tags_on_movie_id" ON "groups_tags" ("group_id");
+CREATE INDEX "index_groups_tags_on_tag_id" ON "groups_tags" ("tag_id");
+CREATE INDEX "index_images_files_on_file_id" ON "images_files" ("file_id");
+CREATE INDEX "index_images_on_studio_id" ON "images" ("studio_id") WHERE "studio_id" IS NOT NULL;
+CREATE INDEX "index_images_tags_on_tag_id" ON "images_tags" ("tag_id");
+CREATE INDEX "index_movies_scenes_on_movie_id" ON "groups_scenes" ("group_id");
+CREATE INDEX "index_performer_stash_ids_on_performer_id" ON "performer_stash_ids" ("performer_id");
+CREATE INDEX "index_performers_galleries_on_performer_id" ON "performers_galleries" ("performer_id");
+CREATE INDEX "index_performers_images_on_performer_id" ON "performers_images" ("performer_id");
+CREATE INDEX "index_performers_scenes_on_performer_id" ON "performers_scenes" ("performer_id");
+CREATE INDEX "index_performers_tags_on_tag_id" ON "performers_tags" ("tag_id");
+CREATE UNIQUE INDEX "index_saved_filters_on_mode_name_unique" ON "saved_filters" ("mode", "name");
+CREATE INDEX "index_scene_markers_on_primary_tag_id" ON "scene_markers" ("primary_tag_id");
+CREATE INDEX "index_scene_markers_on_scene_id" ON "scene_markers" ("scene_id");
+CREATE INDEX "index_scene_markers_tags_on_tag_id" ON "scene_markers_tags" ("tag_id");
+CREATE INDEX "index_scenes_files_file_id" ON "scenes_files" ("file_id");
+CREATE INDEX "index_scenes_galleries_on_gallery_id" ON "scenes_galleries" ("gallery_id");
+CREATE INDEX "index_scenes_o_dates" ON "scenes_o_dates" ("scene_id");
+CREATE INDEX "index_scenes_on_studio_id" ON "scenes" ("studio_id");
+CREATE INDEX "index_scenes_tags_on_tag_id" ON "scenes_tags" ("tag_id");
+CREATE INDEX "index_scenes_view_dates" ON "scenes_view_dates" ("scene_id");
+CREATE INDEX "index_studio_stash_ids_on_studio_id" ON "studio_stash_ids" ("studio_id");
+CREATE UNIQUE INDEX "index_studios_on_name_unique" ON "studios" ("name");
+CREATE INDEX "index_studios_tags_on_tag_id" ON "studios_tags" ("tag_id");
+CREATE INDEX "index_tags_on_name" ON "tags" ("name");
+CREATE INDEX "index_trash_on_deleted_at" ON "trash" ("deleted_at");
+CREATE INDEX "performer_aliases_alias" ON "performer_aliases" ("alias");
+CREATE UNIQUE INDEX "performers_name_disambiguation_unique" ON "performers" ("name", "disambiguation") WHERE "disambiguation" IS NOT NULL;
+CREATE UNIQUE INDEX "performers_name_unique" ON "performers" ("name") WHERE "disambiguation" IS NULL;
+CREATE INDEX "performers_urls_url" ON "performer_urls" ("url");
+CREATE INDEX "scene_urls_url" ON "scene_urls" ("url");
+CREATE UNIQUE INDEX "studio_aliases_alias_unique" ON "studio_aliases" ("alias");
+CREATE UNIQUE INDEX "tag_aliases_alias_unique" ON "tag_aliases" ("alias");
+CREATE UNIQUE INDEX "unique_index_galleries_files_on_primary" ON "galleries_files" ("gallery_id") WHERE "primary";
+CREATE UNIQUE INDEX "unique_index_images_files_on_primary" ON "images_files" ("image_id") WHERE "primary";
+CREATE UNIQUE INDEX "unique_index_scenes_files_on_primary" ON "scenes_files" ("scene_id") WHERE "primary";
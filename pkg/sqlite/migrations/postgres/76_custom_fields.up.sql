@@ -0,0 +1,56 @@
+CREATE TABLE "custom_field_definitions" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "entity_type" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "type" TEXT NOT NULL,
+  "options" TEXT,
+  "created_at" TIMESTAMPTZ NOT NULL,
+  "updated_at" TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX "index_custom_field_definitions_on_entity_type_name_unique" ON "custom_field_definitions" ("entity_type", "name");
+
+CREATE TABLE "scene_custom_fields" (
+  "scene_id" BIGINT NOT NULL REFERENCES "scenes"("id") ON DELETE CASCADE DEFERRABLE,
+  "field_id" BIGINT NOT NULL REFERENCES "custom_field_definitions"("id") ON DELETE CASCADE DEFERRABLE,
+  "value" TEXT NOT NULL,
+  PRIMARY KEY("scene_id", "field_id")
+);
+
+CREATE INDEX "index_scene_custom_fields_on_field_id_value" ON "scene_custom_fields" ("field_id", "value");
+
+CREATE TABLE "performer_custom_fields" (
+  "performer_id" BIGINT NOT NULL REFERENCES "performers"("id") ON DELETE CASCADE DEFERRABLE,
+  "field_id" BIGINT NOT NULL REFERENCES "custom_field_definitions"("id") ON DELETE CASCADE DEFERRABLE,
+  "value" TEXT NOT NULL,
+  PRIMARY KEY("performer_id", "field_id")
+);
+
+CREATE INDEX "index_performer_custom_fields_on_field_id_value" ON "performer_custom_fields" ("field_id", "value");
+
+CREATE TABLE "studio_custom_fields" (
+  "studio_id" BIGINT NOT NULL REFERENCES "studios"("id") ON DELETE CASCADE DEFERRABLE,
+  "field_id" BIGINT NOT NULL REFERENCES "custom_field_definitions"("id") ON DELETE CASCADE DEFERRABLE,
+  "value" TEXT NOT NULL,
+  PRIMARY KEY("studio_id", "field_id")
+);
+
+CREATE INDEX "index_studio_custom_fields_on_field_id_value" ON "studio_custom_fields" ("field_id", "value");
+
+CREATE TABLE "gallery_custom_fields" (
+  "gallery_id" BIGINT NOT NULL REFERENCES "galleries"("id") ON DELETE CASCADE DEFERRABLE,
+  "field_id" BIGINT NOT NULL REFERENCES "custom_field_definitions"("id") ON DELETE CASCADE DEFERRABLE,
+  "value" TEXT NOT NULL,
+  PRIMARY KEY("gallery_id", "field_id")
+);
+
+CREATE INDEX "index_gallery_custom_fields_on_field_id_value" ON "gallery_custom_fields" ("field_id", "value");
+
+CREATE TABLE "group_custom_fields" (
+  "group_id" BIGINT NOT NULL REFERENCES "groups"("id") ON DELETE CASCADE DEFERRABLE,
+  "field_id" BIGINT NOT NULL REFERENCES "custom_field_definitions"("id") ON DELETE CASCADE DEFERRABLE,
+  "value" TEXT NOT NULL,
+  PRIMARY KEY("group_id", "field_id")
+);
+
+CREATE INDEX "index_group_custom_fields_on_field_id_value" ON "group_custom_fields" ("field_id", "value");
@@ -0,0 +1,282 @@
+CREATE TABLE "change_log" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "entity_type" TEXT NOT NULL,
+  "entity_id" BIGINT NOT NULL,
+  "operation" TEXT NOT NULL,
+  "changed_at" TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX "index_change_log_on_changed_at" ON "change_log" ("changed_at");
+
+CREATE TABLE "change_log_state" (
+  "log_id" TEXT NOT NULL,
+  "pruned_sequence" BIGINT NOT NULL
+);
+
+INSERT INTO "change_log_state" ("log_id", "pruned_sequence") VALUES (substr(md5(random()::TEXT), 1, 16), 0);
+
+-- Transactions writing to the change log commit in any order, so sequence
+-- numbers do not become visible in order. Before its first entry, each
+-- writer takes a shared advisory lock on the last sequence number allocated
+-- so far, which is held until it commits or rolls back. Readers only return
+-- entries up to the lowest sequence number locked, below which no entries
+-- are still in flight. The two key form of the lock keeps these locks apart
+-- from other advisory locks, which use a single key.
+
+-- change_log_insert adds an entry to the change log.
+CREATE FUNCTION "change_log_insert"("changed_type" TEXT, "changed_id" BIGINT, "changed_operation" TEXT) RETURNS VOID AS $$
+DECLARE
+  "allocated" BIGINT;
+BEGIN
+  IF coalesce(current_setting('stash.change_log_writing', true), '') = '' THEN
+    SELECT CASE WHEN "is_called" THEN "last_value" ELSE "last_value" - 1 END INTO "allocated" FROM "change_log_id_seq";
+    PERFORM pg_advisory_xact_lock_shared(("allocated" >> 32)::INTEGER, "allocated"::BIT(32)::INTEGER);
+    PERFORM set_config('stash.change_log_writing', 'on', true);
+  END IF;
+
+  INSERT INTO "change_log" ("entity_type", "entity_id", "operation", "changed_at")
+  VALUES ("changed_type", "changed_id", "changed_operation", now());
+END;
+$$ LANGUAGE plpgsql;
+
+-- change_log_horizon returns the highest sequence number below which no
+-- entries of other transactions are still in flight.
+CREATE FUNCTION "change_log_horizon"() RETURNS BIGINT AS $$
+  SELECT coalesce(min(("classid"::BIGINT << 32) | "objid"::BIGINT), 9223372036854775807)
+  FROM "pg_locks"
+  WHERE "locktype" = 'advisory' AND "objsubid" = 2 AND "granted" AND "pid" <> pg_backend_pid()
+    AND "database" = (SELECT "oid" FROM "pg_database" WHERE "datname" = current_database());
+$$ LANGUAGE sql STABLE;
+
+-- change_log_disabled is true while the database is populated by a
+-- conversion from SQLite
+CREATE FUNCTION "change_log_disabled"() RETURNS BOOLEAN AS $$
+BEGIN
+  RETURN coalesce(current_setting('stash.change_log_disabled', true), '') = 'on';
+END;
+$$ LANGUAGE plpgsql;
+
+-- change_log_object records changes to the objects of the table. The
+-- argument is the entity type of the objects.
+CREATE FUNCTION "change_log_object"() RETURNS TRIGGER AS $$
+BEGIN
+  IF "change_log_disabled"() THEN
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP = 'DELETE' THEN
+    PERFORM "change_log_insert"(TG_ARGV[0], OLD."id", 'DELETE');
+  ELSE
+    PERFORM "change_log_insert"(TG_ARGV[0], NEW."id", 'UPSERT');
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- change_log_relationship records changes to relationships as changes to
+-- the object that owns them, unless the object itself is being deleted. The
+-- arguments are the entity type, the table of the objects and the column
+-- referencing the object.
+CREATE FUNCTION "change_log_relationship"() RETURNS TRIGGER AS $$
+DECLARE
+  "object_id" BIGINT;
+  "object_exists" BOOLEAN;
+BEGIN
+  IF "change_log_disabled"() THEN
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP = 'DELETE' THEN
+    "object_id" := (to_jsonb(OLD) ->> TG_ARGV[2])::BIGINT;
+  ELSE
+    "object_id" := (to_jsonb(NEW) ->> TG_ARGV[2])::BIGINT;
+  END IF;
+
+  EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I WHERE "id" = $1)', TG_ARGV[1]) INTO "object_exists" USING "object_id";
+  IF "object_exists" THEN
+    PERFORM "change_log_insert"(TG_ARGV[0], "object_id", 'UPSERT');
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- change_log_file records changes to files as changes to the objects they
+-- belong to. The argument is the column containing the file ID.
+CREATE FUNCTION "change_log_file"() RETURNS TRIGGER AS $$
+DECLARE
+  "changed_file_id" BIGINT;
+BEGIN
+  IF "change_log_disabled"() THEN
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP = 'DELETE' THEN
+    "changed_file_id" := (to_jsonb(OLD) ->> TG_ARGV[0])::BIGINT;
+  ELSE
+    "changed_file_id" := (to_jsonb(NEW) ->> TG_ARGV[0])::BIGINT;
+  END IF;
+
+  PERFORM "change_log_insert"('SCENE', "scene_id", 'UPSERT') FROM "scenes_files" WHERE "file_id" = "changed_file_id";
+  PERFORM "change_log_insert"('IMAGE', "image_id", 'UPSERT') FROM "images_files" WHERE "file_id" = "changed_file_id";
+  PERFORM "change_log_insert"('GALLERY', "gallery_id", 'UPSERT') FROM "galleries_files" WHERE "file_id" = "changed_file_id";
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- resume_time and play_duration are updated throughout playback, so are not
+-- logged
+CREATE TRIGGER "change_log_scenes" AFTER INSERT OR UPDATE OF "id", "title", "details", "date", "rating", "studio_id", "organized", "created_at", "updated_at", "code", "director", "cover_blob" OR DELETE ON "scenes"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('SCENE');
+
+CREATE TRIGGER "change_log_scene_markers" AFTER INSERT OR UPDATE OR DELETE ON "scene_markers"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('SCENE_MARKER');
+
+CREATE TRIGGER "change_log_images" AFTER INSERT OR UPDATE OR DELETE ON "images"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('IMAGE');
+
+CREATE TRIGGER "change_log_galleries" AFTER INSERT OR UPDATE OR DELETE ON "galleries"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('GALLERY');
+
+CREATE TRIGGER "change_log_galleries_chapters" AFTER INSERT OR UPDATE OR DELETE ON "galleries_chapters"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('GALLERY_CHAPTER');
+
+CREATE TRIGGER "change_log_groups" AFTER INSERT OR UPDATE OR DELETE ON "groups"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('GROUP');
+
+CREATE TRIGGER "change_log_performers" AFTER INSERT OR UPDATE OR DELETE ON "performers"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('PERFORMER');
+
+CREATE TRIGGER "change_log_studios" AFTER INSERT OR UPDATE OR DELETE ON "studios"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('STUDIO');
+
+CREATE TRIGGER "change_log_tags" AFTER INSERT OR UPDATE OR DELETE ON "tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_object"('TAG');
+
+CREATE TRIGGER "change_log_scenes_files_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scenes_files"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scenes_tags_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scenes_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_performers_scenes_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "performers_scenes"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scenes_galleries_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scenes_galleries"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scenes_galleries_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "scenes_galleries"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_groups_scenes_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "groups_scenes"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scene_urls_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scene_urls"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scene_stash_ids_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scene_stash_ids"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scenes_o_dates_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scenes_o_dates"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scenes_view_dates_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scenes_view_dates"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scene_custom_fields_scene_id" AFTER INSERT OR UPDATE OR DELETE ON "scene_custom_fields"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE', 'scenes', 'scene_id');
+
+CREATE TRIGGER "change_log_scene_markers_tags_scene_marker_id" AFTER INSERT OR UPDATE OR DELETE ON "scene_markers_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('SCENE_MARKER', 'scene_markers', 'scene_marker_id');
+
+CREATE TRIGGER "change_log_images_files_image_id" AFTER INSERT OR UPDATE OR DELETE ON "images_files"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('IMAGE', 'images', 'image_id');
+
+CREATE TRIGGER "change_log_images_tags_image_id" AFTER INSERT OR UPDATE OR DELETE ON "images_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('IMAGE', 'images', 'image_id');
+
+CREATE TRIGGER "change_log_performers_images_image_id" AFTER INSERT OR UPDATE OR DELETE ON "performers_images"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('IMAGE', 'images', 'image_id');
+
+CREATE TRIGGER "change_log_galleries_images_image_id" AFTER INSERT OR UPDATE OR DELETE ON "galleries_images"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('IMAGE', 'images', 'image_id');
+
+CREATE TRIGGER "change_log_galleries_images_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "galleries_images"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_image_urls_image_id" AFTER INSERT OR UPDATE OR DELETE ON "image_urls"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('IMAGE', 'images', 'image_id');
+
+CREATE TRIGGER "change_log_galleries_files_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "galleries_files"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_galleries_tags_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "galleries_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_performers_galleries_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "performers_galleries"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_gallery_urls_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "gallery_urls"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_gallery_custom_fields_gallery_id" AFTER INSERT OR UPDATE OR DELETE ON "gallery_custom_fields"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GALLERY', 'galleries', 'gallery_id');
+
+CREATE TRIGGER "change_log_groups_tags_group_id" AFTER INSERT OR UPDATE OR DELETE ON "groups_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GROUP', 'groups', 'group_id');
+
+CREATE TRIGGER "change_log_group_urls_group_id" AFTER INSERT OR UPDATE OR DELETE ON "group_urls"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GROUP', 'groups', 'group_id');
+
+CREATE TRIGGER "change_log_groups_relations_containing_id" AFTER INSERT OR UPDATE OR DELETE ON "groups_relations"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GROUP', 'groups', 'containing_id');
+
+CREATE TRIGGER "change_log_groups_relations_sub_id" AFTER INSERT OR UPDATE OR DELETE ON "groups_relations"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GROUP', 'groups', 'sub_id');
+
+CREATE TRIGGER "change_log_group_custom_fields_group_id" AFTER INSERT OR UPDATE OR DELETE ON "group_custom_fields"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('GROUP', 'groups', 'group_id');
+
+CREATE TRIGGER "change_log_performer_aliases_performer_id" AFTER INSERT OR UPDATE OR DELETE ON "performer_aliases"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('PERFORMER', 'performers', 'performer_id');
+
+CREATE TRIGGER "change_log_performer_urls_performer_id" AFTER INSERT OR UPDATE OR DELETE ON "performer_urls"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('PERFORMER', 'performers', 'performer_id');
+
+CREATE TRIGGER "change_log_performer_stash_ids_performer_id" AFTER INSERT OR UPDATE OR DELETE ON "performer_stash_ids"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('PERFORMER', 'performers', 'performer_id');
+
+CREATE TRIGGER "change_log_performers_tags_performer_id" AFTER INSERT OR UPDATE OR DELETE ON "performers_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('PERFORMER', 'performers', 'performer_id');
+
+CREATE TRIGGER "change_log_performer_custom_fields_performer_id" AFTER INSERT OR UPDATE OR DELETE ON "performer_custom_fields"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('PERFORMER', 'performers', 'performer_id');
+
+CREATE TRIGGER "change_log_studio_aliases_studio_id" AFTER INSERT OR UPDATE OR DELETE ON "studio_aliases"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('STUDIO', 'studios', 'studio_id');
+
+CREATE TRIGGER "change_log_studio_stash_ids_studio_id" AFTER INSERT OR UPDATE OR DELETE ON "studio_stash_ids"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('STUDIO', 'studios', 'studio_id');
+
+CREATE TRIGGER "change_log_studios_tags_studio_id" AFTER INSERT OR UPDATE OR DELETE ON "studios_tags"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('STUDIO', 'studios', 'studio_id');
+
+CREATE TRIGGER "change_log_studio_custom_fields_studio_id" AFTER INSERT OR UPDATE OR DELETE ON "studio_custom_fields"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('STUDIO', 'studios', 'studio_id');
+
+CREATE TRIGGER "change_log_tag_aliases_tag_id" AFTER INSERT OR UPDATE OR DELETE ON "tag_aliases"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('TAG', 'tags', 'tag_id');
+
+CREATE TRIGGER "change_log_tags_relations_parent_id" AFTER INSERT OR UPDATE OR DELETE ON "tags_relations"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('TAG', 'tags', 'parent_id');
+
+CREATE TRIGGER "change_log_tags_relations_child_id" AFTER INSERT OR UPDATE OR DELETE ON "tags_relations"
+FOR EACH ROW EXECUTE FUNCTION "change_log_relationship"('TAG', 'tags', 'child_id');
+
+CREATE TRIGGER "change_log_files" AFTER UPDATE ON "files"
+FOR EACH ROW EXECUTE FUNCTION "change_log_file"('id');
+
+CREATE TRIGGER "change_log_files_fingerprints" AFTER INSERT OR UPDATE OR DELETE ON "files_fingerprints"
+FOR EACH ROW EXECUTE FUNCTION "change_log_file"('file_id');
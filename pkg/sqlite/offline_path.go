@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	offlinePathTable  = "offline_paths"
+	offlinePathColumn = "path"
+)
+
+type offlinePathRow struct {
+	Path         string    `db:"path"`
+	OfflineSince Timestamp `db:"offline_since"`
+}
+
+func (r *offlinePathRow) resolve() *models.OfflinePath {
+	return &models.OfflinePath{
+		Path:         r.Path,
+		OfflineSince: r.OfflineSince.Timestamp,
+	}
+}
+
+type OfflinePathStore struct {
+	tableMgr *table
+}
+
+func NewOfflinePathStore() *OfflinePathStore {
+	return &OfflinePathStore{
+		tableMgr: offlinePathTableMgr,
+	}
+}
+
+func (qb *OfflinePathStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *OfflinePathStore) Create(ctx context.Context, newObject *models.OfflinePath) error {
+	r := offlinePathRow{
+		Path:         newObject.Path,
+		OfflineSince: Timestamp{Timestamp: newObject.OfflineSince},
+	}
+
+	_, err := qb.tableMgr.insert(ctx, r)
+	return err
+}
+
+func (qb *OfflinePathStore) Destroy(ctx context.Context, path string) error {
+	q := dialect(ctx).Delete(qb.table()).Where(qb.table().Col(offlinePathColumn).Eq(path))
+
+	if _, err := exec(ctx, q); err != nil {
+		return fmt.Errorf("destroying %s: %w", qb.table().GetTable(), err)
+	}
+
+	return nil
+}
+
+func (qb *OfflinePathStore) All(ctx context.Context) ([]*models.OfflinePath, error) {
+	q := dialect(ctx).From(qb.table()).Select(qb.table().All()).Order(qb.table().Col(offlinePathColumn).Asc())
+
+	const single = false
+	var ret []*models.OfflinePath
+	if err := queryFunc(ctx, q, single, func(rows *sqlx.Rows) error {
+		var r offlinePathRow
+		if err := rows.StructScan(&r); err != nil {
+			return err
+		}
+
+		ret = append(ret, r.resolve())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// offlinePathClause returns an SQL expression that is true if pathColumn is
+// an offline path or is within one.
+func offlinePathClause(pathColumn string) string {
+	return fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM %[1]s WHERE %[2]s = %[1]s.path OR substr(%[2]s, 1, length(%[1]s.path) + 1) = %[1]s.path || '%[3]s')",
+		offlinePathTable, pathColumn, string(filepath.Separator),
+	)
+}
+
+// isOfflineCriterionHandler filters on whether the objects are in an offline
+// path. idQuery must select the IDs of the objects in offline paths, using
+// the folders table for the path.
+func isOfflineCriterionHandler(isOffline *bool, idColumn string, idQuery string) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if isOffline == nil {
+			return
+		}
+
+		not := ""
+		if !*isOffline {
+			not = "NOT "
+		}
+
+		f.addWhere(fmt.Sprintf("%s %sIN (%s WHERE %s)", idColumn, not, idQuery, offlinePathClause("folders.path")))
+	}
+}
@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflinePathStore(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.OfflinePath
+		path := folderPaths[folderIdxWithSceneFiles]
+		offlineSince := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		if err := qb.Create(ctx, &models.OfflinePath{
+			Path:         path,
+			OfflineSince: offlineSince,
+		}); err != nil {
+			t.Errorf("OfflinePathStore.Create() error = %v", err)
+			return nil
+		}
+
+		got, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("OfflinePathStore.All() error = %v", err)
+			return nil
+		}
+
+		assert.Equal(t, []*models.OfflinePath{
+			{
+				Path:         path,
+				OfflineSince: offlineSince,
+			},
+		}, got)
+
+		if err := qb.Destroy(ctx, path); err != nil {
+			t.Errorf("OfflinePathStore.Destroy() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.All(ctx)
+		if err != nil {
+			t.Errorf("OfflinePathStore.All() error = %v", err)
+			return nil
+		}
+
+		assert.Len(t, got, 0)
+
+		return nil
+	})
+}
+
+func TestSceneQueryIsOffline(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := db.OfflinePath.Create(ctx, &models.OfflinePath{
+			Path:         folderPaths[folderIdxWithSceneFiles],
+			OfflineSince: time.Now(),
+		}); err != nil {
+			t.Errorf("OfflinePathStore.Create() error = %v", err)
+			return nil
+		}
+
+		allPages := -1
+		findFilter := &models.FindFilterType{PerPage: &allPages}
+
+		isOffline := true
+		scenes := queryScene(ctx, t, db.Scene, &models.SceneFilterType{
+			IsOffline: &isOffline,
+		}, findFilter)
+
+		assert.Len(t, scenes, totalScenes)
+
+		images := queryImages(ctx, t, db.Image, &models.ImageFilterType{
+			IsOffline: &isOffline,
+		}, findFilter)
+
+		assert.Len(t, images, 0)
+
+		isOffline = false
+		scenes = queryScene(ctx, t, db.Scene, &models.SceneFilterType{
+			IsOffline: &isOffline,
+		}, findFilter)
+
+		assert.Len(t, scenes, 0)
+
+		return nil
+	})
+}
@@ -244,8 +244,8 @@ func (qb *PerformerStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *PerformerStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *PerformerStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *PerformerStore) Create(ctx context.Context, newObject *models.Performer) error {
@@ -418,7 +418,7 @@ func (qb *PerformerStore) FindMany(ctx context.Context, ids []int) ([]*models.Pe
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *PerformerStore) find(ctx context.Context, id int) (*models.Performer, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -431,7 +431,7 @@ func (qb *PerformerStore) find(ctx context.Context, id int) (*models.Performer,
 func (qb *PerformerStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Performer, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Where(
+	q := qb.selectDataset(ctx).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -475,7 +475,7 @@ func (qb *PerformerStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([
 }
 
 func (qb *PerformerStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.Performer, error) {
-	sq := dialect.From(scenesPerformersJoinTable).Select(scenesPerformersJoinTable.Col(performerIDColumn)).Where(
+	sq := dialect(ctx).From(scenesPerformersJoinTable).Select(scenesPerformersJoinTable.Col(performerIDColumn)).Where(
 		scenesPerformersJoinTable.Col(sceneIDColumn).Eq(sceneID),
 	)
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -488,7 +488,7 @@ func (qb *PerformerStore) FindBySceneID(ctx context.Context, sceneID int) ([]*mo
 }
 
 func (qb *PerformerStore) FindByImageID(ctx context.Context, imageID int) ([]*models.Performer, error) {
-	sq := dialect.From(performersImagesJoinTable).Select(performersImagesJoinTable.Col(performerIDColumn)).Where(
+	sq := dialect(ctx).From(performersImagesJoinTable).Select(performersImagesJoinTable.Col(performerIDColumn)).Where(
 		performersImagesJoinTable.Col(imageIDColumn).Eq(imageID),
 	)
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -501,7 +501,7 @@ func (qb *PerformerStore) FindByImageID(ctx context.Context, imageID int) ([]*mo
 }
 
 func (qb *PerformerStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*models.Performer, error) {
-	sq := dialect.From(performersGalleriesJoinTable).Select(performersGalleriesJoinTable.Col(performerIDColumn)).Where(
+	sq := dialect(ctx).From(performersGalleriesJoinTable).Select(performersGalleriesJoinTable.Col(performerIDColumn)).Where(
 		performersGalleriesJoinTable.Col(galleryIDColumn).Eq(galleryID),
 	)
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -525,7 +525,7 @@ func (qb *PerformerStore) FindByNames(ctx context.Context, names []string, nocas
 		args = append(args, name)
 	}
 
-	sq := qb.selectDataset().Prepared(true).Where(
+	sq := qb.selectDataset(ctx).Prepared(true).Where(
 		goqu.L(clause, args...),
 	)
 	ret, err := qb.getMany(ctx, sq)
@@ -540,25 +540,25 @@ func (qb *PerformerStore) FindByNames(ctx context.Context, names []string, nocas
 func (qb *PerformerStore) CountByTagID(ctx context.Context, tagID int) (int, error) {
 	joinTable := performersTagsJoinTable
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(tagIDColumn).Eq(tagID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(tagIDColumn).Eq(tagID))
 	return count(ctx, q)
 }
 
 func (qb *PerformerStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *PerformerStore) All(ctx context.Context) ([]*models.Performer, error) {
 	table := qb.table()
-	return qb.getMany(ctx, qb.selectDataset().Order(table.Col("name").Asc()))
+	return qb.getMany(ctx, qb.selectDataset(ctx).Order(table.Col("name").Asc()))
 }
 
 func (qb *PerformerStore) QueryForAutoTag(ctx context.Context, words []string) ([]*models.Performer, error) {
 	// TODO - Query needs to be changed to support queries of this type, and
 	// this method should be removed
 	table := qb.table()
-	sq := dialect.From(table).Select(table.Col(idColumn))
+	sq := dialect(ctx).From(table).Select(table.Col(idColumn))
 	// TODO - disabled alias matching until we get finer control over it
 	// .LeftJoin(
 	// 	performersAliasesJoinTable,
@@ -568,7 +568,7 @@ func (qb *PerformerStore) QueryForAutoTag(ctx context.Context, words []string) (
 	var whereClauses []exp.Expression
 
 	for _, w := range words {
-		whereClauses = append(whereClauses, table.Col("name").Like(w+"%"))
+		whereClauses = append(whereClauses, table.Col("name").ILike(w+"%"))
 		// TODO - see above
 		// whereClauses = append(whereClauses, performersAliasesJoinTable.Col("alias").Like(w+"%"))
 	}
@@ -598,10 +598,11 @@ func (qb *PerformerStore) makeQuery(ctx context.Context, performerFilter *models
 	query := performerRepository.newQuery()
 	distinctIDs(&query, performerTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, performersFTSTable, "performers.id", findFilter) {
 		query.join(performersAliasesTable, "", "performer_aliases.performer_id = performers.id")
 		searchColumns := []string{"performers.name", "performer_aliases.alias"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &performerFilterHandler{
@@ -613,7 +614,7 @@ func (qb *PerformerStore) makeQuery(ctx context.Context, performerFilter *models
 	}
 
 	var err error
-	query.sortAndPagination, err = qb.getPerformerSort(findFilter)
+	query.sortAndPagination, err = qb.getPerformerSort(ctx, &query, findFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -725,13 +726,14 @@ var performerSortOptions = sortOptions{
 	"play_count",
 	"random",
 	"rating",
+	"relevance",
 	"scenes_count",
 	"tag_count",
 	"updated_at",
 	"weight",
 }
 
-func (qb *PerformerStore) getPerformerSort(findFilter *models.FindFilterType) (string, error) {
+func (qb *PerformerStore) getPerformerSort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -747,6 +749,8 @@ func (qb *PerformerStore) getPerformerSort(findFilter *models.FindFilterType) (s
 		return "", err
 	}
 
+	sort = query.relevanceSortOr(sort, "name")
+
 	sortQuery := ""
 	switch sort {
 	case "tag_count":
@@ -765,12 +769,22 @@ func (qb *PerformerStore) getPerformerSort(findFilter *models.FindFilterType) (s
 		sortQuery += qb.sortByLastPlayedAt(direction)
 	case "last_o_at":
 		sortQuery += qb.sortByLastOAt(direction)
+	case relevanceSort:
+		sortQuery += getRelevanceSort(direction)
 	default:
-		sortQuery += getSort(sort, direction, "performers")
+		if isCustomFieldSort(sort) {
+			customSort, err := performersCustomFieldsTableMgr.sort(ctx, sort, direction, performerTable)
+			if err != nil {
+				return "", err
+			}
+			sortQuery += customSort
+		} else {
+			sortQuery += getSort(ctx, sort, direction, "performers")
+		}
 	}
 
 	// Whatever the sorting, always use name/id as a final sort
-	sortQuery += ", COALESCE(performers.name, performers.id) COLLATE NATURAL_CI ASC"
+	sortQuery += ", " + coalesceIDSQL(ctx, "performers.name", "performers.id") + " COLLATE NATURAL_CI ASC"
 	return sortQuery, nil
 }
 
@@ -807,7 +821,7 @@ func (qb *PerformerStore) GetStashIDs(ctx context.Context, performerID int) ([]m
 }
 
 func (qb *PerformerStore) FindByStashID(ctx context.Context, stashID models.StashID) ([]*models.Performer, error) {
-	sq := dialect.From(performersStashIDsJoinTable).Select(performersStashIDsJoinTable.Col(performerIDColumn)).Where(
+	sq := dialect(ctx).From(performersStashIDsJoinTable).Select(performersStashIDsJoinTable.Col(performerIDColumn)).Where(
 		performersStashIDsJoinTable.Col("stash_id").Eq(stashID.StashID),
 		performersStashIDsJoinTable.Col("endpoint").Eq(stashID.Endpoint),
 	)
@@ -822,7 +836,7 @@ func (qb *PerformerStore) FindByStashID(ctx context.Context, stashID models.Stas
 
 func (qb *PerformerStore) FindByStashIDStatus(ctx context.Context, hasStashID bool, stashboxEndpoint string) ([]*models.Performer, error) {
 	table := qb.table()
-	sq := dialect.From(table).LeftJoin(
+	sq := dialect(ctx).From(table).LeftJoin(
 		performersStashIDsJoinTable,
 		goqu.On(table.Col(idColumn).Eq(performersStashIDsJoinTable.Col(performerIDColumn))),
 	).Select(table.Col(idColumn))
@@ -846,3 +860,11 @@ func (qb *PerformerStore) FindByStashIDStatus(ctx context.Context, hasStashID bo
 
 	return ret, nil
 }
+
+func (qb *PerformerStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return performersCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *PerformerStore) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	return performersCustomFieldsTableMgr.set(ctx, id, input)
+}
@@ -167,6 +167,7 @@ func (qb *performerFilterHandler) criterionHandler() criterionHandler {
 		&dateCriterionHandler{filter.DeathDate, tableName + ".death_date", nil},
 		&timestampCriterionHandler{filter.CreatedAt, tableName + ".created_at", nil},
 		&timestampCriterionHandler{filter.UpdatedAt, tableName + ".updated_at", nil},
+		customFieldsCriterionHandler(filter.CustomFields, performersCustomFieldsTableMgr, tableName+".id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "performers_scenes.scene_id",
@@ -226,7 +227,7 @@ func (qb *performerFilterHandler) performerIsMissingCriterionHandler(isMissing *
 				performersAliasesTableMgr.join(f, "", "performers.id")
 				f.addWhere("performer_aliases.alias IS NULL")
 			default:
-				f.addWhere("(performers." + *isMissing + " IS NULL OR TRIM(performers." + *isMissing + ") = '')")
+				f.addWhere("(performers." + *isMissing + " IS NULL OR TRIM(CAST(performers." + *isMissing + " AS TEXT)) = '')")
 			}
 		}
 	}
@@ -236,7 +237,7 @@ func (qb *performerFilterHandler) performerAgeFilterCriterionHandler(age *models
 	return func(ctx context.Context, f *filterBuilder) {
 		if age != nil && age.Modifier.IsValid() {
 			clause, args := getIntCriterionWhereClause(
-				"cast(IFNULL(strftime('%Y.%m%d', performers.death_date), strftime('%Y.%m%d', 'now')) - strftime('%Y.%m%d', performers.birthdate) as int)",
+				ageSQL(ctx, "COALESCE(performers.death_date, CURRENT_DATE)", "performers.birthdate"),
 				*age,
 			)
 			f.addWhere(clause, args...)
@@ -0,0 +1,225 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type postgresColumn struct {
+	Name     string `db:"column_name"`
+	DataType string `db:"data_type"`
+}
+
+// MigrateToPostgres copies the contents of the SQLite database at sqlitePath
+// into the empty PostgreSQL database at postgresURL. The SQLite database must
+// be at the current schema version. The PostgreSQL schema is created by
+// running its migrations.
+func MigrateToPostgres(ctx context.Context, sqlitePath string, postgresURL string) error {
+	if !IsPostgresURL(postgresURL) {
+		redacted, _ := utils.SplitURLPassword(postgresURL)
+		return fmt.Errorf("invalid PostgreSQL URL: %s", redacted)
+	}
+
+	src, err := sqlx.Open(sqlite3Driver, "file:"+sqlitePath+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", sqlitePath, err)
+	}
+	defer src.Close()
+
+	var srcVersion uint
+	if err := src.GetContext(ctx, &srcVersion, "SELECT version FROM schema_migrations LIMIT 1"); err != nil {
+		return fmt.Errorf("getting schema version of %s: %w", sqlitePath, err)
+	}
+
+	if srcVersion != appSchemaVersion {
+		return fmt.Errorf("database %s must be migrated to schema version %d before conversion: %w", sqlitePath, appSchemaVersion, &MigrationNeededError{
+			CurrentSchemaVersion:  srcVersion,
+			RequiredSchemaVersion: appSchemaVersion,
+		})
+	}
+
+	dest := NewDatabase()
+	dest.dbPath = postgresURL
+	dest.dbType = PostgresBackend
+
+	destVersion, err := dest.getDatabaseSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("getting schema version of destination database: %w", err)
+	}
+	if destVersion != 0 {
+		return fmt.Errorf("destination database is not empty")
+	}
+
+	if err := dest.RunAllMigrations(); err != nil {
+		return fmt.Errorf("creating destination schema: %w", err)
+	}
+
+	conn, err := dest.open(false, true)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+
+	if err := copyToPostgres(ctx, src, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing: %w", err)
+	}
+
+	return nil
+}
+
+func copyToPostgres(ctx context.Context, src *sqlx.DB, tx *sqlx.Tx) error {
+	// rows are copied one table at a time, so foreign keys can only be
+	// checked once all tables are copied
+	if _, err := tx.ExecContext(ctx, "SET CONSTRAINTS ALL DEFERRED"); err != nil {
+		return err
+	}
+
+	// the change log is copied from the source database rather than
+	// recording the copied rows as changes
+	if _, err := tx.ExecContext(ctx, "SET LOCAL stash.change_log_disabled = 'on'"); err != nil {
+		return err
+	}
+
+	var tables []string
+	if err := tx.SelectContext(ctx, &tables, `SELECT table_name FROM information_schema.tables
+WHERE table_schema = 'public' AND table_type = 'BASE TABLE' AND table_name != 'schema_migrations'
+ORDER BY table_name`); err != nil {
+		return fmt.Errorf("getting tables: %w", err)
+	}
+
+	for _, table := range tables {
+		var columns []postgresColumn
+		if err := tx.SelectContext(ctx, &columns, `SELECT column_name, data_type FROM information_schema.columns
+WHERE table_schema = 'public' AND table_name = $1
+ORDER BY ordinal_position`, table); err != nil {
+			return fmt.Errorf("getting columns of %s: %w", table, err)
+		}
+
+		n, err := copyTableToPostgres(ctx, src, tx, table, columns)
+		if err != nil {
+			return fmt.Errorf("copying %s: %w", table, err)
+		}
+
+		logger.Infof("Copied %d rows from %s", n, table)
+
+		if !hasColumn(columns, "id") {
+			continue
+		}
+
+		// the sequences of serial ids are not updated by copying
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence($1, 'id'), MAX(id)) FROM %s HAVING MAX(id) IS NOT NULL",
+			pq.QuoteIdentifier(table),
+		), table); err != nil {
+			return fmt.Errorf("resetting id sequence of %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+func hasColumn(columns []postgresColumn, name string) bool {
+	for _, c := range columns {
+		if c.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyTableToPostgres(ctx context.Context, src *sqlx.DB, tx *sqlx.Tx, table string, columns []postgresColumn) (int, error) {
+	names := make([]string, len(columns))
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+		quoted[i] = pq.QuoteIdentifier(c.Name)
+	}
+
+	rows, err := src.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(quoted, ", "), pq.QuoteIdentifier(table)))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	// replace any rows inserted by the migrations
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", pq.QuoteIdentifier(table))); err != nil {
+		return 0, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, names...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	n := 0
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, err
+		}
+
+		for i, c := range columns {
+			values[i] = postgresValue(values[i], c.DataType)
+		}
+
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+
+	// flush the copied rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// postgresValue converts a value read from SQLite to the type of the
+// PostgreSQL column.
+func postgresValue(v interface{}, dataType string) interface{} {
+	switch dataType {
+	case "boolean":
+		switch b := v.(type) {
+		case int64:
+			return b != 0
+		case string:
+			return b == "1" || strings.EqualFold(b, "true")
+		}
+	case "text":
+		if s, ok := v.([]byte); ok {
+			return string(s)
+		}
+	}
+
+	return v
+}
@@ -32,7 +32,7 @@ func (qb *queryBuilder) addColumn(column string) {
 	qb.columns = append(qb.columns, column)
 }
 
-func (qb queryBuilder) toSQL(includeSortPagination bool) string {
+func (qb queryBuilder) toSQL(ctx context.Context, includeSortPagination bool) string {
 	body := qb.body()
 
 	withClause := ""
@@ -44,17 +44,35 @@ func (qb queryBuilder) toSQL(includeSortPagination bool) string {
 		withClause = "WITH " + recursive + strings.Join(qb.withClauses, ", ") + " "
 	}
 
-	body = withClause + qb.repository.buildQueryBody(body, qb.whereClauses, qb.havingClauses)
 	if includeSortPagination {
-		body += qb.sortAndPagination
+		return withClause + qb.repository.buildSortedQueryBody(ctx, body, qb.whereClauses, qb.havingClauses, qb.sortAndPagination)
 	}
 
-	return body
+	return withClause + qb.repository.buildQueryBody(body, qb.whereClauses, qb.havingClauses, qb.groupByColumns(ctx)...)
+}
+
+// groupByColumns returns the selected columns other than the id, which must
+// be grouped by on PostgreSQL when the query has having clauses.
+func (qb queryBuilder) groupByColumns(ctx context.Context) []string {
+	if !isPostgres(ctx) || len(qb.columns) < 2 {
+		return nil
+	}
+
+	var ret []string
+	for _, c := range qb.columns[1:] {
+		// remove the alias
+		if i := strings.LastIndex(strings.ToLower(c), " as "); i != -1 {
+			c = c[:i]
+		}
+		ret = append(ret, c)
+	}
+
+	return ret
 }
 
 func (qb queryBuilder) findIDs(ctx context.Context) ([]int, error) {
 	const includeSortPagination = true
-	sql := qb.toSQL(includeSortPagination)
+	sql := qb.toSQL(ctx, includeSortPagination)
 	return qb.repository.runIdsQuery(ctx, sql, qb.args)
 }
 
@@ -75,7 +93,7 @@ func (qb queryBuilder) executeCount(ctx context.Context) (int, error) {
 		withClause = "WITH " + recursive + strings.Join(qb.withClauses, ", ") + " "
 	}
 
-	body = qb.repository.buildQueryBody(body, qb.whereClauses, qb.havingClauses)
+	body = qb.repository.buildQueryBody(body, qb.whereClauses, qb.havingClauses, qb.groupByColumns(ctx)...)
 	countQuery := withClause + qb.repository.buildCountQuery(body)
 	return qb.repository.runCountQuery(ctx, countQuery, qb.args)
 }
@@ -174,14 +192,14 @@ func (qb *queryBuilder) addFilter(f *filterBuilder) error {
 	return nil
 }
 
-func (qb *queryBuilder) parseQueryString(columns []string, q string) {
+func (qb *queryBuilder) parseQueryString(ctx context.Context, columns []string, q string) {
 	specs := models.ParseSearchString(q)
 
 	for _, t := range specs.MustHave {
 		var clauses []string
 
 		for _, column := range columns {
-			clauses = append(clauses, column+" LIKE ?")
+			clauses = append(clauses, likeSQL(ctx, column, false))
 			qb.addArg(like(t))
 		}
 
@@ -190,7 +208,7 @@ func (qb *queryBuilder) parseQueryString(columns []string, q string) {
 
 	for _, t := range specs.MustNot {
 		for _, column := range columns {
-			qb.addWhere(coalesce(column) + " NOT LIKE ?")
+			qb.addWhere(likeSQL(ctx, coalesce(column), true))
 			qb.addArg(like(t))
 		}
 	}
@@ -200,7 +218,7 @@ func (qb *queryBuilder) parseQueryString(columns []string, q string) {
 
 		for _, column := range columns {
 			for _, v := range set {
-				clauses = append(clauses, column+" LIKE ?")
+				clauses = append(clauses, likeSQL(ctx, column, false))
 				qb.addArg(like(v))
 			}
 		}
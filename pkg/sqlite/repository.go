@@ -153,19 +153,46 @@ func (r *repository) querySimple(ctx context.Context, query string, args []inter
 	return nil
 }
 
-func (r *repository) buildQueryBody(body string, whereClauses []string, havingClauses []string) string {
+// buildQueryBody adds the where and having clauses to the query body. The
+// query is grouped by the id of the table, and any additional groupBy
+// expressions, if there are having clauses.
+func (r *repository) buildQueryBody(body string, whereClauses []string, havingClauses []string, groupBy ...string) string {
 	if len(whereClauses) > 0 {
 		body = body + " WHERE " + strings.Join(whereClauses, " AND ") // TODO handle AND or OR
 	}
 	if len(havingClauses) > 0 {
-		body = body + " GROUP BY " + r.tableName + ".id "
+		body = body + " GROUP BY " + strings.Join(append([]string{r.tableName + ".id"}, groupBy...), ", ") + " "
 		body = body + " HAVING " + strings.Join(havingClauses, " AND ") // TODO handle AND or OR
 	}
 
 	return body
 }
 
+// buildSortedQueryBody adds the where and having clauses, and the sort and
+// pagination clause, to the query body. PostgreSQL does not allow sorting a
+// SELECT DISTINCT query by expressions that are not selected, so on
+// PostgreSQL the query is grouped by the id of the table instead, and sorted
+// by aggregates of the sort expressions.
+func (r *repository) buildSortedQueryBody(ctx context.Context, body string, whereClauses []string, havingClauses []string, sortAndPagination string) string {
+	selectDistinct := "SELECT DISTINCT " + r.tableName + ".id FROM "
+	if !isPostgres(ctx) || (!strings.HasPrefix(body, selectDistinct) && len(havingClauses) == 0) {
+		return r.buildQueryBody(body, whereClauses, havingClauses) + sortAndPagination
+	}
+
+	body = strings.Replace(body, "SELECT DISTINCT ", "SELECT ", 1)
+	if len(whereClauses) > 0 {
+		body = body + " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	body = body + " GROUP BY " + r.tableName + ".id "
+	if len(havingClauses) > 0 {
+		body = body + " HAVING " + strings.Join(havingClauses, " AND ")
+	}
+
+	return body + aggregateSort(sortAndPagination)
+}
+
 func (r *repository) executeFindQuery(ctx context.Context, body string, args []interface{}, sortAndPagination string, whereClauses []string, havingClauses []string, withClauses []string, recursiveWith bool) ([]int, int, error) {
+	sortedBody := r.buildSortedQueryBody(ctx, body, whereClauses, havingClauses, sortAndPagination)
 	body = r.buildQueryBody(body, whereClauses, havingClauses)
 
 	withClause := ""
@@ -178,7 +205,7 @@ func (r *repository) executeFindQuery(ctx context.Context, body string, args []i
 	}
 
 	countQuery := withClause + r.buildCountQuery(body)
-	idsQuery := withClause + body + sortAndPagination
+	idsQuery := withClause + sortedBody
 
 	// Perform query and fetch result
 	var countResult int
@@ -447,7 +474,7 @@ func idToIndexMap(ids []int) map[int]int {
 func (r *filesRepository) getMany(ctx context.Context, ids []int, primaryOnly bool) ([][]models.FileID, error) {
 	var primaryClause string
 	if primaryOnly {
-		primaryClause = " AND `primary` = 1"
+		primaryClause = " AND `primary` = TRUE"
 	}
 
 	query := fmt.Sprintf("SELECT %s as id, file_id, `primary` from %s WHERE %[1]s IN %[3]s%s", r.idColumn, r.tableName, getInBinding(len(ids)), primaryClause)
@@ -107,8 +107,8 @@ func (qb *SavedFilterStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *SavedFilterStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *SavedFilterStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *SavedFilterStore) Create(ctx context.Context, newObject *models.SavedFilter) error {
@@ -158,7 +158,7 @@ func (qb *SavedFilterStore) FindMany(ctx context.Context, ids []int, ignoreNotFo
 	ret := make([]*models.SavedFilter, len(ids))
 
 	table := qb.table()
-	q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(ids))
+	q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(ids))
 	unsorted, err := qb.getMany(ctx, q)
 	if err != nil {
 		return nil, err
@@ -182,7 +182,7 @@ func (qb *SavedFilterStore) FindMany(ctx context.Context, ids []int, ignoreNotFo
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *SavedFilterStore) find(ctx context.Context, id int) (*models.SavedFilter, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -242,7 +242,7 @@ func (qb *SavedFilterStore) FindByMode(ctx context.Context, mode models.FilterMo
 		whereClause = table.Col("mode").Eq(mode)
 	}
 
-	sq := qb.selectDataset().Prepared(true).Where(whereClause).Order(table.Col("name").Asc())
+	sq := qb.selectDataset(ctx).Prepared(true).Where(whereClause).Order(table.Col("name").Asc())
 	ret, err := qb.getMany(ctx, sq)
 
 	if err != nil {
@@ -253,5 +253,5 @@ func (qb *SavedFilterStore) FindByMode(ctx context.Context, mode models.FilterMo
 }
 
 func (qb *SavedFilterStore) All(ctx context.Context) ([]*models.SavedFilter, error) {
-	return qb.getMany(ctx, qb.selectDataset())
+	return qb.getMany(ctx, qb.selectDataset(ctx))
 }
@@ -17,6 +17,7 @@ import (
 	"gopkg.in/guregu/null.v4"
 	"gopkg.in/guregu/null.v4/zero"
 
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/utils"
@@ -40,8 +41,10 @@ const (
 	sceneCoverBlobColumn = "cover_blob"
 )
 
+// findExactDuplicateQuery is formatted with the aggregate expression of the
+// scene ids.
 var findExactDuplicateQuery = `
-SELECT GROUP_CONCAT(DISTINCT scene_id) as ids
+SELECT %s as ids
 FROM (
 	SELECT scenes.id as scene_id
 		, video_files.duration as file_duration
@@ -52,8 +55,8 @@ FROM (
 	INNER JOIN scenes_files ON (scenes.id = scenes_files.scene_id)
 	INNER JOIN files ON (scenes_files.file_id = files.id)
 	INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
-	INNER JOIN video_files ON (files.id == video_files.file_id)
-)
+	INNER JOIN video_files ON (files.id = video_files.file_id)
+) AS phashes
 WHERE durationDiff <= ?1
     OR ?1 < 0   --  Always TRUE if the parameter is negative.
                 --  That will disable the durationDiff checking.
@@ -71,7 +74,27 @@ FROM scenes
 INNER JOIN scenes_files ON (scenes.id = scenes_files.scene_id)
 INNER JOIN files ON (scenes_files.file_id = files.id)
 INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
-INNER JOIN video_files ON (files.id == video_files.file_id)
+INNER JOIN video_files ON (files.id = video_files.file_id)
+ORDER BY files.size DESC;
+`
+
+var findAllPhashSegmentsQuery = `
+SELECT scenes.id as id
+    , files_fingerprints.fingerprint as segments
+FROM scenes
+INNER JOIN scenes_files ON (scenes.id = scenes_files.scene_id)
+INNER JOIN files ON (scenes_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash_segments')
+ORDER BY files.size DESC;
+`
+
+var findAllAudioFingerprintsQuery = `
+SELECT scenes.id as id
+    , files_fingerprints.fingerprint as fingerprint
+FROM scenes
+INNER JOIN scenes_files ON (scenes.id = scenes_files.scene_id)
+INNER JOIN files ON (scenes_files.file_id = files.id)
+INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'audio')
 ORDER BY files.size DESC;
 `
 
@@ -257,18 +280,18 @@ func (qb *SceneStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *SceneStore) selectDataset() *goqu.SelectDataset {
+func (qb *SceneStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
 	table := qb.table()
 	files := fileTableMgr.table
 	folders := folderTableMgr.table
 	checksum := fingerprintTableMgr.table.As("fingerprint_md5")
 	oshash := fingerprintTableMgr.table.As("fingerprint_oshash")
 
-	return dialect.From(table).LeftJoin(
+	return dialect(ctx).From(table).LeftJoin(
 		scenesFilesJoinTable,
 		goqu.On(
 			scenesFilesJoinTable.Col(sceneIDColumn).Eq(table.Col(idColumn)),
-			scenesFilesJoinTable.Col("primary").Eq(1),
+			scenesFilesJoinTable.Col("primary").Eq(true),
 		),
 	).LeftJoin(
 		files,
@@ -498,7 +521,7 @@ func (qb *SceneStore) FindMany(ctx context.Context, ids []int) ([]*models.Scene,
 
 	table := qb.table()
 	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
-		q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(batch))
+		q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(batch))
 		unsorted, err := qb.getMany(ctx, q)
 		if err != nil {
 			return err
@@ -525,7 +548,7 @@ func (qb *SceneStore) FindMany(ctx context.Context, ids []int) ([]*models.Scene,
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *SceneStore) find(ctx context.Context, id int) (*models.Scene, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -538,7 +561,7 @@ func (qb *SceneStore) find(ctx context.Context, id int) (*models.Scene, error) {
 func (qb *SceneStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Scene, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Where(
+	q := qb.selectDataset(ctx).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -616,7 +639,7 @@ func (qb *SceneStore) GetManyFileIDs(ctx context.Context, ids []int) ([][]models
 }
 
 func (qb *SceneStore) FindByFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error) {
-	sq := dialect.From(scenesFilesJoinTable).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
+	sq := dialect(ctx).From(scenesFilesJoinTable).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
 		scenesFilesJoinTable.Col(fileIDColumn).Eq(fileID),
 	)
 
@@ -629,9 +652,9 @@ func (qb *SceneStore) FindByFileID(ctx context.Context, fileID models.FileID) ([
 }
 
 func (qb *SceneStore) FindByPrimaryFileID(ctx context.Context, fileID models.FileID) ([]*models.Scene, error) {
-	sq := dialect.From(scenesFilesJoinTable).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
+	sq := dialect(ctx).From(scenesFilesJoinTable).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
 		scenesFilesJoinTable.Col(fileIDColumn).Eq(fileID),
-		scenesFilesJoinTable.Col("primary").Eq(1),
+		scenesFilesJoinTable.Col("primary").Eq(true),
 	)
 
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -645,7 +668,7 @@ func (qb *SceneStore) FindByPrimaryFileID(ctx context.Context, fileID models.Fil
 func (qb *SceneStore) CountByFileID(ctx context.Context, fileID models.FileID) (int, error) {
 	joinTable := scenesFilesJoinTable
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(fileIDColumn).Eq(fileID))
 	return count(ctx, q)
 }
 
@@ -661,7 +684,7 @@ func (qb *SceneStore) FindByFingerprints(ctx context.Context, fp []models.Finger
 		))
 	}
 
-	sq := dialect.From(scenesFilesJoinTable).
+	sq := dialect(ctx).From(scenesFilesJoinTable).
 		InnerJoin(
 			fingerprintTable,
 			goqu.On(fingerprintTable.Col(fileIDColumn).Eq(scenesFilesJoinTable.Col(fileIDColumn))),
@@ -704,15 +727,15 @@ func (qb *SceneStore) FindByPath(ctx context.Context, p string) ([]*models.Scene
 	basename = strings.ReplaceAll(basename, "*", "%")
 	dir = strings.ReplaceAll(dir, "*", "%")
 
-	sq := dialect.From(scenesFilesJoinTable).InnerJoin(
+	sq := dialect(ctx).From(scenesFilesJoinTable).InnerJoin(
 		filesTable,
 		goqu.On(filesTable.Col(idColumn).Eq(scenesFilesJoinTable.Col(fileIDColumn))),
 	).InnerJoin(
 		foldersTable,
 		goqu.On(foldersTable.Col(idColumn).Eq(filesTable.Col("parent_folder_id"))),
 	).Select(scenesFilesJoinTable.Col(sceneIDColumn)).Where(
-		foldersTable.Col("path").Like(dir),
-		filesTable.Col("basename").Like(basename),
+		foldersTable.Col("path").ILike(dir),
+		filesTable.Col("basename").ILike(basename),
 	)
 
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -724,7 +747,7 @@ func (qb *SceneStore) FindByPath(ctx context.Context, p string) ([]*models.Scene
 }
 
 func (qb *SceneStore) FindByPerformerID(ctx context.Context, performerID int) ([]*models.Scene, error) {
-	sq := dialect.From(scenesPerformersJoinTable).Select(scenesPerformersJoinTable.Col(sceneIDColumn)).Where(
+	sq := dialect(ctx).From(scenesPerformersJoinTable).Select(scenesPerformersJoinTable.Col(sceneIDColumn)).Where(
 		scenesPerformersJoinTable.Col(performerIDColumn).Eq(performerID),
 	)
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -737,7 +760,7 @@ func (qb *SceneStore) FindByPerformerID(ctx context.Context, performerID int) ([
 }
 
 func (qb *SceneStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*models.Scene, error) {
-	sq := dialect.From(galleriesScenesJoinTable).Select(galleriesScenesJoinTable.Col(sceneIDColumn)).Where(
+	sq := dialect(ctx).From(galleriesScenesJoinTable).Select(galleriesScenesJoinTable.Col(sceneIDColumn)).Where(
 		galleriesScenesJoinTable.Col(galleryIDColumn).Eq(galleryID),
 	)
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -752,7 +775,7 @@ func (qb *SceneStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*mo
 func (qb *SceneStore) CountByPerformerID(ctx context.Context, performerID int) (int, error) {
 	joinTable := scenesPerformersJoinTable
 
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(performerIDColumn).Eq(performerID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(performerIDColumn).Eq(performerID))
 	return count(ctx, q)
 }
 
@@ -761,7 +784,7 @@ func (qb *SceneStore) OCountByPerformerID(ctx context.Context, performerID int)
 	joinTable := scenesPerformersJoinTable
 	oHistoryTable := goqu.T(scenesODatesTable)
 
-	q := dialect.Select(goqu.COUNT("*")).From(table).InnerJoin(
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(table).InnerJoin(
 		oHistoryTable,
 		goqu.On(table.Col(idColumn).Eq(oHistoryTable.Col(sceneIDColumn))),
 	).InnerJoin(
@@ -780,7 +803,7 @@ func (qb *SceneStore) OCountByPerformerID(ctx context.Context, performerID int)
 }
 
 func (qb *SceneStore) FindByGroupID(ctx context.Context, groupID int) ([]*models.Scene, error) {
-	sq := dialect.From(scenesGroupsJoinTable).Select(scenesGroupsJoinTable.Col(sceneIDColumn)).Where(
+	sq := dialect(ctx).From(scenesGroupsJoinTable).Select(scenesGroupsJoinTable.Col(sceneIDColumn)).Where(
 		scenesGroupsJoinTable.Col(groupIDColumn).Eq(groupID),
 	)
 	ret, err := qb.findBySubquery(ctx, sq)
@@ -793,14 +816,14 @@ func (qb *SceneStore) FindByGroupID(ctx context.Context, groupID int) ([]*models
 }
 
 func (qb *SceneStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *SceneStore) Size(ctx context.Context) (float64, error) {
 	table := qb.table()
 	fileTable := fileTableMgr.table
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		goqu.COALESCE(goqu.SUM(fileTableMgr.table.Col("size")), 0),
 	).From(table).InnerJoin(
 		scenesFilesJoinTable,
@@ -821,7 +844,7 @@ func (qb *SceneStore) Duration(ctx context.Context) (float64, error) {
 	table := qb.table()
 	videoFileTable := videoFileTableMgr.table
 
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		goqu.COALESCE(goqu.SUM(videoFileTable.Col("duration")), 0),
 	).From(table).InnerJoin(
 		scenesFilesJoinTable,
@@ -842,7 +865,7 @@ func (qb *SceneStore) Duration(ctx context.Context) (float64, error) {
 func (qb *SceneStore) PlayDuration(ctx context.Context) (float64, error) {
 	table := qb.table()
 
-	q := dialect.Select(goqu.COALESCE(goqu.SUM("play_duration"), 0)).From(table)
+	q := dialect(ctx).Select(goqu.COALESCE(goqu.SUM("play_duration"), 0)).From(table)
 
 	var ret float64
 	if err := querySimple(ctx, q, &ret); err != nil {
@@ -856,14 +879,14 @@ func (qb *SceneStore) PlayDuration(ctx context.Context) (float64, error) {
 func (qb *SceneStore) CountByStudioID(ctx context.Context, studioID int) (int, error) {
 	table := qb.table()
 
-	q := dialect.Select(goqu.COUNT("*")).From(table).Where(table.Col(studioIDColumn).Eq(studioID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(table).Where(table.Col(studioIDColumn).Eq(studioID))
 	return count(ctx, q)
 }
 
 func (qb *SceneStore) countMissingFingerprints(ctx context.Context, fpType string) (int, error) {
 	fpTable := fingerprintTableMgr.table.As("fingerprints_temp")
 
-	q := dialect.From(scenesFilesJoinTable).LeftJoin(
+	q := dialect(ctx).From(scenesFilesJoinTable).LeftJoin(
 		fpTable,
 		goqu.On(
 			scenesFilesJoinTable.Col(fileIDColumn).Eq(fpTable.Col(fileIDColumn)),
@@ -891,7 +914,7 @@ func (qb *SceneStore) Wall(ctx context.Context, q *string) ([]*models.Scene, err
 	}
 
 	table := qb.table()
-	qq := qb.selectDataset().Prepared(true).Where(table.Col("details").Like("%" + s + "%")).Order(goqu.L("RANDOM()").Asc()).Limit(80)
+	qq := qb.selectDataset(ctx).Prepared(true).Where(table.Col("details").ILike("%" + s + "%")).Order(goqu.L("RANDOM()").Asc()).Limit(80)
 	return qb.getMany(ctx, qq)
 }
 
@@ -900,7 +923,7 @@ func (qb *SceneStore) All(ctx context.Context) ([]*models.Scene, error) {
 	fileTable := fileTableMgr.table
 	folderTable := folderTableMgr.table
 
-	return qb.getMany(ctx, qb.selectDataset().Order(
+	return qb.getMany(ctx, qb.selectDataset(ctx).Order(
 		folderTable.Col("path").Asc(),
 		fileTable.Col("basename").Asc(),
 		table.Col("date").Asc(),
@@ -918,7 +941,8 @@ func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFi
 	query := sceneRepository.newQuery()
 	distinctIDs(&query, sceneTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, scenesFTSTable, "scenes.id", findFilter) {
 		query.addJoins(
 			join{
 				table:    scenesFilesTable,
@@ -944,7 +968,7 @@ func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFi
 
 		filepathColumn := "folders.path || '" + string(filepath.Separator) + "' || files.basename"
 		searchColumns := []string{"scenes.title", "scenes.details", filepathColumn, "files_fingerprints.fingerprint", "scene_markers.title"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &sceneFilterHandler{
@@ -955,7 +979,7 @@ func (qb *SceneStore) makeQuery(ctx context.Context, sceneFilter *models.SceneFi
 		return nil, err
 	}
 
-	if err := qb.setSceneSort(&query, findFilter); err != nil {
+	if err := qb.setSceneSort(ctx, &query, findFilter); err != nil {
 		return nil, err
 	}
 	query.sortAndPagination += getPagination(findFilter)
@@ -1026,14 +1050,14 @@ func (qb *SceneStore) queryGroupedFields(ctx context.Context, options models.Sce
 	}
 
 	const includeSortPagination = false
-	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(includeSortPagination))
+	aggregateQuery.from = fmt.Sprintf("(%s) as temp", query.toSQL(ctx, includeSortPagination))
 
 	out := struct {
 		Total    int
 		Duration null.Float
 		Size     null.Float
 	}{}
-	if err := sceneRepository.queryStruct(ctx, aggregateQuery.toSQL(includeSortPagination), query.args, &out); err != nil {
+	if err := sceneRepository.queryStruct(ctx, aggregateQuery.toSQL(ctx, includeSortPagination), query.args, &out); err != nil {
 		return nil, err
 	}
 
@@ -1079,12 +1103,13 @@ var sceneSortOptions = sortOptions{
 	"perceptual_similarity",
 	"random",
 	"rating",
+	"relevance",
 	"tag_count",
 	"title",
 	"updated_at",
 }
 
-func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindFilterType) error {
+func (qb *SceneStore) setSceneSort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) error {
 	if findFilter == nil || findFilter.Sort == nil || *findFilter.Sort == "" {
 		return nil
 	}
@@ -1095,6 +1120,8 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 		return err
 	}
 
+	sort = query.relevanceSortOr(sort, "title")
+
 	addFileTable := func() {
 		query.addJoins(
 			join{
@@ -1131,10 +1158,10 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 	switch sort {
 	case "movie_scene_number":
 		query.join(groupsScenesTable, "", "scenes.id = groups_scenes.scene_id")
-		query.sortAndPagination += getSort("scene_index", direction, groupsScenesTable)
+		query.sortAndPagination += getSort(ctx, "scene_index", direction, groupsScenesTable)
 	case "group_scene_number":
 		query.join(groupsScenesTable, "scene_group", "scenes.id = scene_group.scene_id")
-		query.sortAndPagination += getSort("scene_index", direction, "scene_group")
+		query.sortAndPagination += getSort(ctx, "scene_index", direction, "scene_group")
 	case "tag_count":
 		query.sortAndPagination += getCountSort(sceneTable, scenesTagsTable, sceneIDColumn, direction)
 	case "performer_count":
@@ -1161,24 +1188,24 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 	case "bitrate":
 		sort = "bit_rate"
 		addVideoFileTable()
-		query.sortAndPagination += getSort(sort, direction, videoFileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, videoFileTable)
 	case "file_mod_time":
 		sort = "mod_time"
 		addFileTable()
-		query.sortAndPagination += getSort(sort, direction, fileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, fileTable)
 	case "framerate":
 		sort = "frame_rate"
 		addVideoFileTable()
-		query.sortAndPagination += getSort(sort, direction, videoFileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, videoFileTable)
 	case "filesize":
 		addFileTable()
-		query.sortAndPagination += getSort(sort, direction, fileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, fileTable)
 	case "duration":
 		addVideoFileTable()
-		query.sortAndPagination += getSort(sort, direction, videoFileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, videoFileTable)
 	case "interactive", "interactive_speed":
 		addVideoFileTable()
-		query.sortAndPagination += getSort(sort, direction, videoFileTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, videoFileTable)
 	case "title":
 		addFileTable()
 		addFolderTable()
@@ -1191,12 +1218,22 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 		query.sortAndPagination += fmt.Sprintf(" ORDER BY (SELECT MAX(o_date) FROM %s AS sort WHERE sort.%s = %s.id) %s", scenesODatesTable, sceneIDColumn, sceneTable, getSortDirection(direction))
 	case "o_counter":
 		query.sortAndPagination += getCountSort(sceneTable, scenesODatesTable, sceneIDColumn, direction)
+	case relevanceSort:
+		query.sortAndPagination += getRelevanceSort(direction)
 	default:
-		query.sortAndPagination += getSort(sort, direction, "scenes")
+		if isCustomFieldSort(sort) {
+			customSort, err := scenesCustomFieldsTableMgr.sort(ctx, sort, direction, sceneTable)
+			if err != nil {
+				return err
+			}
+			query.sortAndPagination += customSort
+		} else {
+			query.sortAndPagination += getSort(ctx, sort, direction, "scenes")
+		}
 	}
 
 	// Whatever the sorting, always use title/id as a final sort
-	query.sortAndPagination += ", COALESCE(scenes.title, scenes.id) COLLATE NATURAL_CI ASC"
+	query.sortAndPagination += ", " + coalesceIDSQL(ctx, "scenes.title", "scenes.id") + " COLLATE NATURAL_CI ASC"
 
 	return nil
 }
@@ -1328,11 +1365,14 @@ func (qb *SceneStore) GetStashIDs(ctx context.Context, sceneID int) ([]models.St
 	return sceneRepository.stashIDs.get(ctx, sceneID)
 }
 
-func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int, durationDiff float64) ([][]*models.Scene, error) {
+// FindDuplicates returns groups of scenes with phashes within distance of
+// each other. If audio is true, scenes with matching audio fingerprints are
+// also grouped, regardless of durationDiff.
+func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int, durationDiff float64, audio bool) ([][]*models.Scene, error) {
 	var dupeIds [][]int
 	if distance == 0 {
 		var ids []string
-		if err := dbWrapper.Select(ctx, &ids, findExactDuplicateQuery, durationDiff); err != nil {
+		if err := dbWrapper.Select(ctx, &ids, fmt.Sprintf(findExactDuplicateQuery, groupConcatSQL(ctx, "scene_id", true)), durationDiff); err != nil {
 			return nil, err
 		}
 
@@ -1370,6 +1410,15 @@ func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int, duration
 		dupeIds = utils.FindDuplicates(hashes, distance, durationDiff)
 	}
 
+	if audio {
+		audioIds, err := qb.findAudioDuplicates(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		dupeIds = utils.MergeDuplicates(dupeIds, audioIds)
+	}
+
 	var duplicates [][]*models.Scene
 	for _, sceneIds := range dupeIds {
 		if scenes, err := qb.FindMany(ctx, sceneIds); err == nil {
@@ -1382,6 +1431,114 @@ func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int, duration
 	return duplicates, nil
 }
 
+func (qb *SceneStore) findAudioDuplicates(ctx context.Context) ([][]int, error) {
+	var fps []*utils.AudioFingerprint
+
+	if err := sceneRepository.queryFunc(ctx, findAllAudioFingerprintsQuery, nil, false, func(rows *sqlx.Rows) error {
+		var row struct {
+			ID          int    `db:"id"`
+			Fingerprint string `db:"fingerprint"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+
+		fp, err := utils.StringToAudioFingerprint(row.Fingerprint)
+		if err != nil {
+			logger.Warnf("ignoring audio fingerprint for scene %d: %v", row.ID, err)
+			return nil
+		}
+
+		fps = append(fps, &utils.AudioFingerprint{
+			SceneID:     row.ID,
+			Fingerprint: fp,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return utils.FindAudioDuplicates(fps, utils.DefaultAudioSimilarity), nil
+}
+
+// FindOverlaps returns pairs of scenes sharing at least minDuration seconds
+// of footage, using the phash segments of their files.
+func (qb *SceneStore) FindOverlaps(ctx context.Context, distance int, minDuration float64) ([]*models.SceneOverlap, error) {
+	var segments []*utils.PhashSegments
+
+	if err := sceneRepository.queryFunc(ctx, findAllPhashSegmentsQuery, nil, false, func(rows *sqlx.Rows) error {
+		var row struct {
+			ID       int    `db:"id"`
+			Segments string `db:"segments"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+
+		s, err := utils.ParsePhashSegments(row.Segments)
+		if err != nil {
+			logger.Warnf("ignoring phash segments for scene %d: %v", row.ID, err)
+			return nil
+		}
+
+		s.SceneID = row.ID
+		segments = append(segments, s)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	overlaps := utils.FindSegmentOverlaps(segments, distance, minDuration)
+
+	// group the ranges by scene pair, preserving the longest first order.
+	// Scenes with more than one file may overlap in either order, so pairs
+	// are keyed with the lower scene id first.
+	var ret []*models.SceneOverlap
+	pairs := make(map[[2]int]*models.SceneOverlap)
+	for _, o := range overlaps {
+		idA, idB := segments[o.A].SceneID, segments[o.B].SceneID
+		key := [2]int{idA, idB}
+		if idA > idB {
+			key = [2]int{idB, idA}
+		}
+
+		pair := pairs[key]
+		if pair == nil {
+			sceneA, err := qb.Find(ctx, idA)
+			if err != nil {
+				return nil, err
+			}
+			sceneB, err := qb.Find(ctx, idB)
+			if err != nil {
+				return nil, err
+			}
+			if sceneA == nil || sceneB == nil {
+				continue
+			}
+
+			pair = &models.SceneOverlap{
+				SceneA: sceneA,
+				SceneB: sceneB,
+			}
+			pairs[key] = pair
+			ret = append(ret, pair)
+		}
+
+		r := &models.SceneOverlapRange{
+			StartA:   o.StartA,
+			StartB:   o.StartB,
+			Duration: o.Duration,
+		}
+		if pair.SceneA.ID != idA {
+			r.StartA, r.StartB = r.StartB, r.StartA
+		}
+
+		pair.Ranges = append(pair.Ranges, r)
+	}
+
+	return ret, nil
+}
+
 func sortByPath(scenes [][]*models.Scene) {
 	lessFunc := func(i int, j int) bool {
 		firstPathI := getFirstPath(scenes[i])
@@ -1400,3 +1557,11 @@ func getFirstPath(scenes []*models.Scene) string {
 	}
 	return firstPath
 }
+
+func (qb *SceneStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return scenesCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *SceneStore) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	return scenesCustomFieldsTableMgr.set(ctx, id, input)
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/stashapp/stash/pkg/models"
-	"github.com/stashapp/stash/pkg/utils"
 )
 
 type sceneFilterHandler struct {
@@ -95,6 +94,7 @@ func (qb *sceneFilterHandler) criterionHandler() criterionHandler {
 		intCriterionHandler(sceneFilter.Rating100, "scenes.rating", nil),
 		qb.oCountCriterionHandler(sceneFilter.OCounter),
 		boolCriterionHandler(sceneFilter.Organized, "scenes.organized", nil),
+		isOfflineCriterionHandler(sceneFilter.IsOffline, "scenes.id", "SELECT scenes_files.scene_id FROM scenes_files INNER JOIN files ON files.id = scenes_files.file_id AND scenes_files.`primary` = TRUE INNER JOIN folders ON folders.id = files.parent_folder_id"),
 
 		floatIntCriterionHandler(sceneFilter.Duration, "video_files.duration", qb.addVideoFilesTable),
 		resolutionCriterionHandler(sceneFilter.Resolution, "video_files.height", "video_files.width", qb.addVideoFilesTable),
@@ -137,7 +137,7 @@ func (qb *sceneFilterHandler) criterionHandler() criterionHandler {
 					"scene_last_view",
 					fmt.Sprintf("scene_last_view.%s = scenes.id", sceneIDColumn),
 				)
-				h := timestampCriterionHandler{sceneFilter.LastPlayedAt, "IFNULL(last_played_at, datetime(0))", nil}
+				h := timestampCriterionHandler{sceneFilter.LastPlayedAt, "COALESCE(last_played_at, '0001-01-01 00:00:00')", nil}
 				h.handle(ctx, f)
 			}
 		}),
@@ -159,6 +159,7 @@ func (qb *sceneFilterHandler) criterionHandler() criterionHandler {
 		&dateCriterionHandler{sceneFilter.Date, "scenes.date", nil},
 		&timestampCriterionHandler{sceneFilter.CreatedAt, "scenes.created_at", nil},
 		&timestampCriterionHandler{sceneFilter.UpdatedAt, "scenes.updated_at", nil},
+		customFieldsCriterionHandler(sceneFilter.CustomFields, scenesCustomFieldsTableMgr, "scenes.id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "scenes_galleries.gallery_id",
@@ -340,7 +341,7 @@ func (qb *sceneFilterHandler) isMissingCriterionHandler(isMissing *string) crite
 			case "cover":
 				f.addWhere("scenes.cover_blob IS NULL")
 			default:
-				f.addWhere("(scenes." + *isMissing + " IS NULL OR TRIM(scenes." + *isMissing + ") = '')")
+				f.addWhere("(scenes." + *isMissing + " IS NULL OR TRIM(CAST(scenes." + *isMissing + " AS TEXT)) = '')")
 			}
 		}
 	}
@@ -381,11 +382,11 @@ func (qb *sceneFilterHandler) captionCriterionHandler(captions *models.StringCri
 			qb.addSceneFilesTable(f)
 			f.addLeftJoin(videoCaptionsTable, "", "video_captions.file_id = scenes_files.file_id")
 		},
-		excludeHandler: func(f *filterBuilder, criterion *models.StringCriterionInput) {
+		excludeHandler: func(ctx context.Context, f *filterBuilder, criterion *models.StringCriterionInput) {
 			excludeClause := `scenes.id NOT IN (
 				SELECT scenes_files.scene_id from scenes_files 
 				INNER JOIN video_captions on video_captions.file_id = scenes_files.file_id 
-				WHERE video_captions.language_code LIKE ?
+				WHERE ` + likeSQL(ctx, "video_captions.language_code", false) + `
 			)`
 			f.addWhere(excludeClause, criterion.Value)
 
@@ -455,12 +456,12 @@ func (qb *sceneFilterHandler) performerFavoriteCriterionHandler(performerfavorit
 			if *performerfavorite {
 				// contains at least one favorite
 				f.addLeftJoin("performers", "", "performers.id = performers_scenes.performer_id")
-				f.addWhere("performers.favorite = 1")
+				f.addWhere("performers.favorite = TRUE")
 			} else {
 				// contains zero favorites
 				f.addLeftJoin(`(SELECT performers_scenes.scene_id as id FROM performers_scenes
 JOIN performers ON performers.id = performers_scenes.performer_id
-GROUP BY performers_scenes.scene_id HAVING SUM(performers.favorite) = 0)`, "nofaves", "scenes.id = nofaves.id")
+GROUP BY performers_scenes.scene_id HAVING SUM(CASE WHEN performers.favorite THEN 1 ELSE 0 END) = 0)`, "nofaves", "scenes.id = nofaves.id")
 				f.addWhere("performers_scenes.scene_id IS NULL OR nofaves.id IS NOT NULL")
 			}
 		}
@@ -473,10 +474,9 @@ func (qb *sceneFilterHandler) performerAgeCriterionHandler(performerAge *models.
 			f.addInnerJoin("performers_scenes", "", "scenes.id = performers_scenes.scene_id")
 			f.addInnerJoin("performers", "", "performers_scenes.performer_id = performers.id")
 
-			f.addWhere("scenes.date != '' AND performers.birthdate != ''")
 			f.addWhere("scenes.date IS NOT NULL AND performers.birthdate IS NOT NULL")
 
-			ageCalc := "cast(strftime('%Y.%m%d', scenes.date) - strftime('%Y.%m%d', performers.birthdate) as int)"
+			ageCalc := ageSQL(ctx, "scenes.date", "performers.birthdate")
 			whereClause, args := getIntWhereClause(ageCalc, performerAge.Modifier, performerAge.Value, performerAge.Value2)
 			f.addWhere(whereClause, args...)
 		}
@@ -529,40 +529,5 @@ func (qb *sceneFilterHandler) performerTagsCriterionHandler(tags *models.Hierarc
 }
 
 func (qb *sceneFilterHandler) phashDistanceCriterionHandler(phashDistance *models.PhashDistanceCriterionInput) criterionHandlerFunc {
-	return func(ctx context.Context, f *filterBuilder) {
-		if phashDistance != nil {
-			qb.addSceneFilesTable(f)
-			f.addLeftJoin(fingerprintTable, "fingerprints_phash", "scenes_files.file_id = fingerprints_phash.file_id AND fingerprints_phash.type = 'phash'")
-
-			value, _ := utils.StringToPhash(phashDistance.Value)
-			distance := 0
-			if phashDistance.Distance != nil {
-				distance = *phashDistance.Distance
-			}
-
-			if distance == 0 {
-				// use the default handler
-				intCriterionHandler(&models.IntCriterionInput{
-					Value:    int(value),
-					Modifier: phashDistance.Modifier,
-				}, "fingerprints_phash.fingerprint", nil)(ctx, f)
-			}
-
-			switch {
-			case phashDistance.Modifier == models.CriterionModifierEquals && distance > 0:
-				// needed to avoid a type mismatch
-				f.addWhere("typeof(fingerprints_phash.fingerprint) = 'integer'")
-				f.addWhere("phash_distance(fingerprints_phash.fingerprint, ?) < ?", value, distance)
-			case phashDistance.Modifier == models.CriterionModifierNotEquals && distance > 0:
-				// needed to avoid a type mismatch
-				f.addWhere("typeof(fingerprints_phash.fingerprint) = 'integer'")
-				f.addWhere("phash_distance(fingerprints_phash.fingerprint, ?) > ?", value, distance)
-			default:
-				intCriterionHandler(&models.IntCriterionInput{
-					Value:    int(value),
-					Modifier: phashDistance.Modifier,
-				}, "fingerprints_phash.fingerprint", nil)(ctx, f)
-			}
-		}
-	}
+	return phashDistanceCriterionHandler(phashDistance, "scenes_files", qb.addSceneFilesTable)
 }
@@ -119,8 +119,8 @@ func (qb *SceneMarkerStore) table() exp.IdentifierExpression {
 	return sceneMarkerTableMgr.table
 }
 
-func (qb *SceneMarkerStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *SceneMarkerStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *SceneMarkerStore) Create(ctx context.Context, newObject *models.SceneMarker) error {
@@ -188,7 +188,7 @@ func (qb *SceneMarkerStore) FindMany(ctx context.Context, ids []int) ([]*models.
 	ret := make([]*models.SceneMarker, len(ids))
 
 	table := qb.table()
-	q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(ids))
+	q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(ids))
 	unsorted, err := qb.getMany(ctx, q)
 	if err != nil {
 		return nil, err
@@ -210,7 +210,7 @@ func (qb *SceneMarkerStore) FindMany(ctx context.Context, ids []int) ([]*models.
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *SceneMarkerStore) find(ctx context.Context, id int) (*models.SceneMarker, error) {
-	q := qb.selectDataset().Where(sceneMarkerTableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(sceneMarkerTableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -271,9 +271,11 @@ func (qb *SceneMarkerStore) CountByTagID(ctx context.Context, tagID int) (int, e
 }
 
 func (qb *SceneMarkerStore) GetMarkerStrings(ctx context.Context, q *string, sort *string) ([]*models.MarkerStringsResultType, error) {
-	query := "SELECT count(*) as `count`, scene_markers.id as id, scene_markers.title as title FROM scene_markers"
+	query := "SELECT count(*) as `count`, MIN(scene_markers.id) as id, scene_markers.title as title FROM scene_markers"
+	var args []interface{}
 	if q != nil {
-		query += " WHERE title LIKE '%" + *q + "%'"
+		query += " WHERE " + likeSQL(ctx, "title", false)
+		args = append(args, "%"+*q+"%")
 	}
 	query += " GROUP BY title"
 	if sort != nil && *sort == "count" {
@@ -281,7 +283,6 @@ func (qb *SceneMarkerStore) GetMarkerStrings(ctx context.Context, q *string, sor
 	} else {
 		query += " ORDER BY title ASC"
 	}
-	var args []interface{}
 	return qb.queryMarkerStringsResultType(ctx, query, args)
 }
 
@@ -292,7 +293,7 @@ func (qb *SceneMarkerStore) Wall(ctx context.Context, q *string) ([]*models.Scen
 	}
 
 	table := qb.table()
-	qq := qb.selectDataset().Prepared(true).Where(table.Col("title").Like("%" + s + "%")).Order(goqu.L("RANDOM()").Asc()).Limit(80)
+	qq := qb.selectDataset(ctx).Prepared(true).Where(table.Col("title").ILike("%" + s + "%")).Order(goqu.L("RANDOM()").Asc()).Limit(80)
 	return qb.getMany(ctx, qq)
 }
 
@@ -307,11 +308,12 @@ func (qb *SceneMarkerStore) makeQuery(ctx context.Context, sceneMarkerFilter *mo
 	query := sceneMarkerRepository.newQuery()
 	distinctIDs(&query, sceneMarkerTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, sceneMarkersFTSTable, "scene_markers.id", findFilter) {
 		query.join(sceneTable, "", "scenes.id = scene_markers.scene_id")
 		query.join(tagTable, "", "scene_markers.primary_tag_id = tags.id")
 		searchColumns := []string{"scene_markers.title", "scenes.title", "tags.name"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &sceneMarkerFilterHandler{
@@ -322,7 +324,7 @@ func (qb *SceneMarkerStore) makeQuery(ctx context.Context, sceneMarkerFilter *mo
 		return nil, err
 	}
 
-	if err := qb.setSceneMarkerSort(&query, findFilter); err != nil {
+	if err := qb.setSceneMarkerSort(ctx, &query, findFilter); err != nil {
 		return nil, err
 	}
 	query.sortAndPagination += getPagination(findFilter)
@@ -363,13 +365,14 @@ var sceneMarkerSortOptions = sortOptions{
 	"id",
 	"title",
 	"random",
+	"relevance",
 	"scene_id",
 	"scenes_updated_at",
 	"seconds",
 	"updated_at",
 }
 
-func (qb *SceneMarkerStore) setSceneMarkerSort(query *queryBuilder, findFilter *models.FindFilterType) error {
+func (qb *SceneMarkerStore) setSceneMarkerSort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) error {
 	sort := findFilter.GetSort("title")
 	direction := findFilter.GetDirection()
 
@@ -378,16 +381,20 @@ func (qb *SceneMarkerStore) setSceneMarkerSort(query *queryBuilder, findFilter *
 		return err
 	}
 
+	sort = query.relevanceSortOr(sort, "title")
+
 	switch sort {
 	case "scenes_updated_at":
 		sort = "updated_at"
 		query.join(sceneTable, "", "scenes.id = scene_markers.scene_id")
-		query.sortAndPagination += getSort(sort, direction, sceneTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, sceneTable)
 	case "title":
 		query.join(tagTable, "", "scene_markers.primary_tag_id = tags.id")
 		query.sortAndPagination += " ORDER BY COALESCE(NULLIF(scene_markers.title,''), tags.name) COLLATE NATURAL_CI " + direction
+	case relevanceSort:
+		query.sortAndPagination += getRelevanceSort(direction)
 	default:
-		query.sortAndPagination += getSort(sort, direction, sceneMarkerTable)
+		query.sortAndPagination += getSort(ctx, sort, direction, sceneMarkerTable)
 	}
 
 	query.sortAndPagination += ", scene_markers.scene_id ASC, scene_markers.seconds ASC"
@@ -447,10 +454,10 @@ func (qb *SceneMarkerStore) UpdateTags(ctx context.Context, id int, tagIDs []int
 }
 
 func (qb *SceneMarkerStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *SceneMarkerStore) All(ctx context.Context) ([]*models.SceneMarker, error) {
-	return qb.getMany(ctx, qb.selectDataset())
+	return qb.getMany(ctx, qb.selectDataset(ctx))
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"path/filepath"
 	"reflect"
 	"regexp"
@@ -17,6 +18,7 @@ import (
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil"
 	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+	"github.com/stashapp/stash/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -4464,6 +4466,83 @@ func TestSceneQueryQTrim(t *testing.T) {
 	}
 }
 
+func TestSceneQueryQRelatedNames(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		// performer names are indexed with the scene
+		q := getPerformerStringValue(performerIdxWithScene, "Name") + " " + getSceneStringValue(sceneIdxWithPerformer, titleField)
+		sort := "relevance"
+		findFilter := &models.FindFilterType{
+			Q:    &q,
+			Sort: &sort,
+		}
+
+		scenes := queryScene(ctx, t, db.Scene, nil, findFilter)
+		assert.Equal(t, []int{sceneIDs[sceneIdxWithPerformer]}, scenesToIDs(scenes))
+
+		// related names are not searched without the relevance sort
+		findFilter.Sort = nil
+		scenes = queryScene(ctx, t, db.Scene, nil, findFilter)
+		assert.Empty(t, scenes)
+
+		return nil
+	})
+}
+
+func TestSceneQueryQSubstring(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		// searches match within words unless sorting by relevance
+		title := getSceneStringValue(sceneIdxWithPerformer, titleField)
+		q := title[1:]
+		sceneQueryQ(ctx, t, db.Scene, q, sceneIdxWithPerformer)
+
+		return nil
+	})
+}
+
+func TestSceneQueryRelevanceSort(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+
+		inDetails := &models.Scene{
+			Title:   "relevance_other",
+			Details: "relevancesort",
+		}
+		inTitle := &models.Scene{
+			Title: "relevancesort",
+		}
+
+		for _, s := range []*models.Scene{inDetails, inTitle} {
+			if err := qb.Create(ctx, s, nil); err != nil {
+				t.Errorf("SceneStore.Create() error = %v", err)
+				return nil
+			}
+		}
+
+		q := "relevancesort"
+		sort := "relevance"
+		direction := models.SortDirectionEnumDesc
+		findFilter := &models.FindFilterType{
+			Q:         &q,
+			Sort:      &sort,
+			Direction: &direction,
+		}
+
+		scenes := queryScene(ctx, t, qb, nil, findFilter)
+		assert.Equal(t, []int{inTitle.ID, inDetails.ID}, scenesToIDs(scenes))
+
+		direction = models.SortDirectionEnumAsc
+		scenes = queryScene(ctx, t, qb, nil, findFilter)
+		assert.Equal(t, []int{inDetails.ID, inTitle.ID}, scenesToIDs(scenes))
+
+		// relevance sort without a search query falls back to title
+		findFilter.Q = nil
+		scenes = queryScene(ctx, t, qb, nil, findFilter)
+		assert.NotEmpty(t, scenes)
+
+		return nil
+	})
+}
+
 func TestSceneStore_All(t *testing.T) {
 	qb := db.Scene
 
@@ -4487,7 +4566,7 @@ func TestSceneStore_FindDuplicates(t *testing.T) {
 	withRollbackTxn(func(ctx context.Context) error {
 		distance := 0
 		durationDiff := -1.
-		got, err := qb.FindDuplicates(ctx, distance, durationDiff)
+		got, err := qb.FindDuplicates(ctx, distance, durationDiff, false)
 		if err != nil {
 			t.Errorf("SceneStore.FindDuplicates() error = %v", err)
 			return nil
@@ -4497,7 +4576,7 @@ func TestSceneStore_FindDuplicates(t *testing.T) {
 
 		distance = 1
 		durationDiff = -1.
-		got, err = qb.FindDuplicates(ctx, distance, durationDiff)
+		got, err = qb.FindDuplicates(ctx, distance, durationDiff, false)
 		if err != nil {
 			t.Errorf("SceneStore.FindDuplicates() error = %v", err)
 			return nil
@@ -4509,6 +4588,75 @@ func TestSceneStore_FindDuplicates(t *testing.T) {
 	})
 }
 
+func TestSceneStore_FindOverlaps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	hashes := make([]int64, 12)
+	for i := range hashes {
+		hashes[i] = int64(r.Uint64())
+	}
+	segments := (&utils.PhashSegments{Interval: 10, Hashes: hashes}).String()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		createFile := func(name string, size int64) (models.FileID, error) {
+			f := &models.VideoFile{
+				BaseFile: &models.BaseFile{
+					Path:           getFilePath(folderIdxWithSceneFiles, name),
+					Basename:       name,
+					ParentFolderID: folderIDs[folderIdxWithSceneFiles],
+					Size:           size,
+					Fingerprints: []models.Fingerprint{
+						{
+							Type:        models.FingerprintTypePhashSegments,
+							Fingerprint: segments,
+						},
+					},
+				},
+			}
+			err := db.File.Create(ctx, f)
+			return f.ID, err
+		}
+
+		// the files of the first scene are ordered either side of the
+		// second scene's file, so the scenes overlap in both orders
+		var fileIDs []models.FileID
+		for i, size := range []int64{300, 200, 100} {
+			id, err := createFile("overlap_"+strconv.Itoa(i)+".mp4", size)
+			if err != nil {
+				t.Errorf("creating file: %v", err)
+				return nil
+			}
+			fileIDs = append(fileIDs, id)
+		}
+
+		sceneA := &models.Scene{}
+		if err := db.Scene.Create(ctx, sceneA, []models.FileID{fileIDs[0], fileIDs[2]}); err != nil {
+			t.Errorf("creating scene: %v", err)
+			return nil
+		}
+		sceneB := &models.Scene{}
+		if err := db.Scene.Create(ctx, sceneB, []models.FileID{fileIDs[1]}); err != nil {
+			t.Errorf("creating scene: %v", err)
+			return nil
+		}
+
+		got, err := db.Scene.FindOverlaps(ctx, 0, 60)
+		if err != nil {
+			t.Errorf("SceneStore.FindOverlaps() error = %v", err)
+			return nil
+		}
+
+		var pairs [][2]int
+		for _, o := range got {
+			pairs = append(pairs, [2]int{o.SceneA.ID, o.SceneB.ID})
+		}
+
+		// each pair appears once, with the scene with the larger file first
+		assert.Equal(t, [][2]int{{sceneA.ID, sceneB.ID}}, pairs)
+
+		return nil
+	})
+}
+
 func TestSceneStore_AssignFiles(t *testing.T) {
 	tests := []struct {
 		name    string
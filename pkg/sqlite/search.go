@@ -0,0 +1,169 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	// relevanceSort sorts by the relevance of the search query. Sorting
+	// descending returns the most relevant results first.
+	relevanceSort = "relevance"
+
+	// searchAlias is the alias of the full-text search results joined to the
+	// query.
+	searchAlias = "search"
+)
+
+// ftsTable is a full-text search index created by the 75_fts migration. The
+// rowid of the index is the id of the indexed row.
+type ftsTable struct {
+	name string
+	// weights are the bm25 weights of the columns of the index, in column
+	// order.
+	weights []float64
+}
+
+var (
+	scenesFTSTable = ftsTable{
+		name: "scenes_fts",
+		// title, code, details, director, paths, fingerprints, studio,
+		// performers, tags, markers
+		weights: []float64{10, 5, 1, 2, 2, 1, 3, 5, 3, 2},
+	}
+	galleriesFTSTable = ftsTable{
+		name: "galleries_fts",
+		// title, code, details, photographer, paths, fingerprints, studio,
+		// performers, tags, chapters
+		weights: []float64{10, 5, 1, 2, 2, 1, 3, 5, 3, 2},
+	}
+	imagesFTSTable = ftsTable{
+		name: "images_fts",
+		// title, code, details, photographer, paths, fingerprints, studio,
+		// performers, tags
+		weights: []float64{10, 5, 1, 2, 2, 1, 3, 5, 3},
+	}
+	sceneMarkersFTSTable = ftsTable{
+		name: "scene_markers_fts",
+		// title, scene, tags
+		weights: []float64{10, 2, 5},
+	}
+	performersFTSTable = ftsTable{
+		name: "performers_fts",
+		// name, disambiguation, aliases
+		weights: []float64{10, 2, 5},
+	}
+	studiosFTSTable = ftsTable{
+		name: "studios_fts",
+		// name, aliases
+		weights: []float64{10, 5},
+	}
+	tagsFTSTable = ftsTable{
+		name: "tags_fts",
+		// name, aliases
+		weights: []float64{10, 5},
+	}
+)
+
+// scoreSQL returns the expression of the relevance score of a match. The
+// score is higher for more relevant matches.
+func (t ftsTable) scoreSQL() string {
+	weights := make([]string, len(t.weights))
+	for i, w := range t.weights {
+		weights[i] = fmt.Sprintf("%g", w)
+	}
+
+	return fmt.Sprintf("-bm25(%s, %s)", t.name, strings.Join(weights, ", "))
+}
+
+// ftsPhrase returns the search term as an FTS5 phrase. Terms containing
+// whitespace are matched as exact phrases, while single words also match
+// words starting with the term.
+func ftsPhrase(term string) string {
+	ret := `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	if strings.ContainsAny(term, " \t") {
+		return ret
+	}
+
+	return ret + "*"
+}
+
+// ftsQuery converts a search string to an FTS5 query, using the same syntax
+// as parseQueryString. It returns an empty string if the search string has
+// no terms that must match, since FTS5 cannot match only excluded terms.
+func ftsQuery(q string) string {
+	specs := models.ParseSearchString(q)
+
+	var clauses []string
+	for _, t := range specs.MustHave {
+		clauses = append(clauses, ftsPhrase(t))
+	}
+
+	for _, set := range specs.AnySets {
+		var terms []string
+		for _, t := range set {
+			terms = append(terms, ftsPhrase(t))
+		}
+
+		clauses = append(clauses, "("+strings.Join(terms, " OR ")+")")
+	}
+
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	ret := strings.Join(clauses, " AND ")
+	for _, t := range specs.MustNot {
+		ret += " NOT " + ftsPhrase(t)
+	}
+
+	return ret
+}
+
+// addFTSSearch restricts the query to rows matching the search string of
+// findFilter in the full-text search index, and makes the relevance score
+// available for sorting. It must be called before any other arguments are
+// added to the query. The index matches the start of words rather than
+// substrings, so it is only used when sorting by relevance. It returns false
+// if the index is not used, in which case the caller should use
+// parseQueryString instead. The index is not available on PostgreSQL.
+func (qb *queryBuilder) addFTSSearch(ctx context.Context, t ftsTable, idColumn string, findFilter *models.FindFilterType) bool {
+	if isPostgres(ctx) || findFilter.Q == nil || findFilter.GetSort("") != relevanceSort {
+		return false
+	}
+
+	// searches that only exclude terms cannot use the index
+	expr := ftsQuery(*findFilter.Q)
+	if expr == "" {
+		return false
+	}
+
+	qb.addJoins(join{
+		table:    fmt.Sprintf("(SELECT rowid, %s AS score FROM %s WHERE %s MATCH ?)", t.scoreSQL(), t.name, t.name),
+		as:       searchAlias,
+		onClause: searchAlias + ".rowid = " + idColumn,
+		joinType: "INNER",
+	})
+	qb.addArg(expr)
+
+	return true
+}
+
+// relevanceSortOr returns fallback if sort is the relevance sort and the
+// query has no full-text search to sort by. Otherwise it returns sort.
+func (qb *queryBuilder) relevanceSortOr(sort string, fallback string) string {
+	if sort == relevanceSort && !qb.hasJoin(searchAlias) {
+		return fallback
+	}
+
+	return sort
+}
+
+// getRelevanceSort returns the sort clause for the relevance sort. The query
+// must have a full-text search.
+func getRelevanceSort(direction string) string {
+	return " ORDER BY " + searchAlias + ".score " + getSortDirection(direction)
+}
@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFTSQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want string
+	}{
+		{"empty", "", ""},
+		{"word", "alice", `"alice"*`},
+		{"words", "alice beach", `"alice"* AND "beach"*`},
+		{"phrase", `"alice beach"`, `"alice beach"`},
+		{"or", "alice or bob", `("alice"* OR "bob"*)`},
+		{"not", "alice -beach", `"alice"* NOT "beach"*`},
+		{"only not", "-beach", ""},
+		{"quote in word", `al"ice`, `"al""ice"*`},
+		{"punctuation", "alice.mp4", `"alice.mp4"*`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ftsQuery(tt.q))
+		})
+	}
+}
@@ -586,6 +586,20 @@ func indexFromID(ids []int, id int) int {
 
 var db *sqlite.Database
 
+// postgresURLEnv is the environment variable of the URL of an empty
+// PostgreSQL database. If set, the tests are run against it instead of a
+// temporary SQLite database.
+const postgresURLEnv = "STASH_TEST_POSTGRES_URL"
+
+// skipIfPostgres skips tests of features that are only supported by SQLite
+// databases.
+func skipIfPostgres(t *testing.T) {
+	t.Helper()
+	if db.Type() == sqlite.PostgresBackend {
+		t.Skip("not supported by PostgreSQL databases")
+	}
+}
+
 func TestMain(m *testing.M) {
 	// initialise empty config - needed by some migrations
 	_ = config.InitializeEmpty()
@@ -618,6 +632,14 @@ func runWithRollbackTxn(t *testing.T, name string, f func(t *testing.T, ctx cont
 }
 
 func testTeardown(databaseFile string) {
+	if db.Type() == sqlite.PostgresBackend {
+		// drops the test schema
+		if err := db.Remove(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	err := db.Close()
 
 	if err != nil {
@@ -631,14 +653,18 @@ func testTeardown(databaseFile string) {
 }
 
 func runTests(m *testing.M) int {
-	// create the database file
-	f, err := os.CreateTemp("", "*.sqlite")
-	if err != nil {
-		panic(fmt.Sprintf("Could not create temporary file: %s", err.Error()))
+	databaseFile := os.Getenv(postgresURLEnv)
+	if databaseFile == "" {
+		// create the database file
+		f, err := os.CreateTemp("", "*.sqlite")
+		if err != nil {
+			panic(fmt.Sprintf("Could not create temporary file: %s", err.Error()))
+		}
+
+		f.Close()
+		databaseFile = f.Name()
 	}
 
-	f.Close()
-	databaseFile := f.Name()
 	db = sqlite.NewDatabase()
 	db.SetBlobStoreOptions(sqlite.BlobStoreOptions{
 		UseDatabase: true,
@@ -652,8 +678,7 @@ func runTests(m *testing.M) int {
 	// defer close and delete the database
 	defer testTeardown(databaseFile)
 
-	err = populateDB()
-	if err != nil {
+	if err := populateDB(); err != nil {
 		panic(fmt.Sprintf("Could not populate database: %s", err.Error()))
 	}
 
@@ -1,9 +1,11 @@
 package sqlite
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -57,18 +59,53 @@ func (o sortOptions) validateSort(sort string) error {
 		seedStr := sort[len(randomSeedPrefix):]
 		_, err := strconv.ParseUint(seedStr, 10, 64)
 		if err != nil {
-			return fmt.Errorf("invalid random seed: %s", seedStr)
+			return fmt.Errorf("%w: invalid random seed: %s", models.ErrInvalidSort, seedStr)
 		}
 		return nil
 	}
 
+	// custom field sorts are validated against the custom field definitions
+	// when the sort is generated
+	if isCustomFieldSort(sort) {
+		return nil
+	}
+
 	for _, v := range o {
 		if v == sort {
 			return nil
 		}
 	}
 
-	return fmt.Errorf("invalid sort: %s", sort)
+	return fmt.Errorf("%w: %s", models.ErrInvalidSort, sort)
+}
+
+// SortOptions returns the sorts that are accepted when querying the objects
+// stored in table. Random sorts with a seed and custom field sorts are also
+// accepted, but are not included.
+func SortOptions(table string) []string {
+	var ret sortOptions
+	switch table {
+	case fileTable:
+		ret = fileSortOptions
+	case galleryTable:
+		ret = gallerySortOptions
+	case groupTable:
+		ret = groupSortOptions
+	case imageTable:
+		ret = imageSortOptions
+	case performerTable:
+		ret = performerSortOptions
+	case sceneTable:
+		ret = sceneSortOptions
+	case sceneMarkerTable:
+		ret = sceneMarkerSortOptions
+	case studioTable:
+		ret = studioSortOptions
+	case tagTable:
+		ret = tagSortOptions
+	}
+
+	return slices.Clone(ret)
 }
 
 func getSortDirection(direction string) string {
@@ -78,7 +115,7 @@ func getSortDirection(direction string) string {
 		return direction
 	}
 }
-func getSort(sort string, direction string, tableName string) string {
+func getSort(ctx context.Context, sort string, direction string, tableName string) string {
 	direction = getSortDirection(direction)
 
 	switch {
@@ -97,9 +134,9 @@ func getSort(sort string, direction string, tableName string) string {
 			// fallback to a random seed
 			seed = rand.Uint64()
 		}
-		return getRandomSort(tableName, direction, seed)
+		return getRandomSort(ctx, tableName, direction, seed)
 	case strings.Compare(sort, "random") == 0:
-		return getRandomSort(tableName, direction, rand.Uint64())
+		return getRandomSort(ctx, tableName, direction, rand.Uint64())
 	default:
 		colName := getColumn(tableName, sort)
 		if strings.Contains(sort, ".") {
@@ -116,11 +153,15 @@ func getSort(sort string, direction string, tableName string) string {
 	}
 }
 
-func getRandomSort(tableName string, direction string, seed uint64) string {
+func getRandomSort(ctx context.Context, tableName string, direction string, seed uint64) string {
 	// cap seed at 10^8
 	seed %= 1e8
 
 	colName := getColumn(tableName, "id")
+	if isPostgres(ctx) {
+		// PostgreSQL raises an error on integer overflow
+		colName = "CAST(" + colName + " AS NUMERIC)"
+	}
 
 	// https://stackoverflow.com/questions/21949795#comment33255354_21949859
 	// p1 := 52959209
@@ -137,14 +178,12 @@ func getCountSort(primaryTable, joinTable, primaryFK, direction string) string {
 	return fmt.Sprintf(" ORDER BY (SELECT COUNT(*) FROM %s AS sort WHERE sort.%s = %s.id) %s", joinTable, primaryFK, primaryTable, getSortDirection(direction))
 }
 
-func getStringSearchClause(columns []string, q string, not bool) sqlClause {
+func getStringSearchClause(ctx context.Context, columns []string, q string, not bool) sqlClause {
 	var likeClauses []string
 	var args []interface{}
 
-	notStr := ""
 	binaryType := " OR "
 	if not {
-		notStr = " NOT"
 		binaryType = " AND "
 	}
 	q = strings.TrimSpace(q)
@@ -156,14 +195,14 @@ func getStringSearchClause(columns []string, q string, not bool) sqlClause {
 		// Search for any word
 		for _, word := range queryWords {
 			for _, column := range columns {
-				likeClauses = append(likeClauses, column+notStr+" LIKE ?")
+				likeClauses = append(likeClauses, likeSQL(ctx, column, not))
 				args = append(args, "%"+word+"%")
 			}
 		}
 	} else {
 		// Search the exact query
 		for _, column := range columns {
-			likeClauses = append(likeClauses, column+notStr+" LIKE ?")
+			likeClauses = append(likeClauses, likeSQL(ctx, column, not))
 			args = append(args, "%"+trimmedQuery+"%")
 		}
 	}
@@ -262,9 +301,9 @@ func getDateWhereClause(column string, modifier models.CriterionModifier, value
 
 	switch modifier {
 	case models.CriterionModifierIsNull:
-		return fmt.Sprintf("(%s IS NULL OR %s = '')", column, column), nil
+		return fmt.Sprintf("(%s IS NULL OR CAST(%s AS TEXT) = '')", column, column), nil
 	case models.CriterionModifierNotNull:
-		return fmt.Sprintf("(%s IS NOT NULL AND %s != '')", column, column), nil
+		return fmt.Sprintf("(%s IS NOT NULL AND CAST(%s AS TEXT) != '')", column, column), nil
 	case models.CriterionModifierEquals:
 		return fmt.Sprintf("%s = ?", column), args
 	case models.CriterionModifierNotEquals:
@@ -165,8 +165,8 @@ func (qb *StudioStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *StudioStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *StudioStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *StudioStore) Create(ctx context.Context, newObject *models.Studio) error {
@@ -298,7 +298,7 @@ func (qb *StudioStore) FindMany(ctx context.Context, ids []int) ([]*models.Studi
 
 	table := qb.table()
 	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
-		q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(batch))
+		q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(batch))
 		unsorted, err := qb.getMany(ctx, q)
 		if err != nil {
 			return err
@@ -325,7 +325,7 @@ func (qb *StudioStore) FindMany(ctx context.Context, ids []int) ([]*models.Studi
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *StudioStore) find(ctx context.Context, id int) (*models.Studio, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -372,7 +372,7 @@ func (qb *StudioStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*m
 func (qb *StudioStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]*models.Studio, error) {
 	table := qb.table()
 
-	q := qb.selectDataset().Where(
+	q := qb.selectDataset(ctx).Where(
 		table.Col(idColumn).Eq(
 			sq,
 		),
@@ -384,7 +384,7 @@ func (qb *StudioStore) findBySubquery(ctx context.Context, sq *goqu.SelectDatase
 func (qb *StudioStore) FindChildren(ctx context.Context, id int) ([]*models.Studio, error) {
 	// SELECT studios.* FROM studios WHERE studios.parent_id = ?
 	table := qb.table()
-	sq := qb.selectDataset().Where(table.Col(studioParentIDColumn).Eq(id))
+	sq := qb.selectDataset(ctx).Where(table.Col(studioParentIDColumn).Eq(id))
 	ret, err := qb.getMany(ctx, sq)
 
 	if err != nil {
@@ -398,7 +398,7 @@ func (qb *StudioStore) FindBySceneID(ctx context.Context, sceneID int) (*models.
 	// SELECT studios.* FROM studios JOIN scenes ON studios.id = scenes.studio_id WHERE scenes.id = ? LIMIT 1
 	table := qb.table()
 	scenes := sceneTableMgr.table
-	sq := qb.selectDataset().Join(
+	sq := qb.selectDataset(ctx).Join(
 		scenes, goqu.On(table.Col(idColumn), scenes.Col(studioIDColumn)),
 	).Where(
 		scenes.Col(idColumn),
@@ -422,7 +422,7 @@ func (qb *StudioStore) FindByName(ctx context.Context, name string, nocase bool)
 	if nocase {
 		where += " COLLATE NOCASE"
 	}
-	sq := qb.selectDataset().Prepared(true).Where(goqu.L(where, name)).Limit(1)
+	sq := qb.selectDataset(ctx).Prepared(true).Where(goqu.L(where, name)).Limit(1)
 	ret, err := qb.get(ctx, sq)
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -433,7 +433,7 @@ func (qb *StudioStore) FindByName(ctx context.Context, name string, nocase bool)
 }
 
 func (qb *StudioStore) FindByStashID(ctx context.Context, stashID models.StashID) ([]*models.Studio, error) {
-	sq := dialect.From(studiosStashIDsJoinTable).Select(studiosStashIDsJoinTable.Col(studioIDColumn)).Where(
+	sq := dialect(ctx).From(studiosStashIDsJoinTable).Select(studiosStashIDsJoinTable.Col(studioIDColumn)).Where(
 		studiosStashIDsJoinTable.Col("stash_id").Eq(stashID.StashID),
 		studiosStashIDsJoinTable.Col("endpoint").Eq(stashID.Endpoint),
 	)
@@ -448,7 +448,7 @@ func (qb *StudioStore) FindByStashID(ctx context.Context, stashID models.StashID
 
 func (qb *StudioStore) FindByStashIDStatus(ctx context.Context, hasStashID bool, stashboxEndpoint string) ([]*models.Studio, error) {
 	table := qb.table()
-	sq := dialect.From(table).LeftJoin(
+	sq := dialect(ctx).From(table).LeftJoin(
 		studiosStashIDsJoinTable,
 		goqu.On(table.Col(idColumn).Eq(studiosStashIDsJoinTable.Col(studioIDColumn))),
 	).Select(table.Col(idColumn))
@@ -474,20 +474,20 @@ func (qb *StudioStore) FindByStashIDStatus(ctx context.Context, hasStashID bool,
 }
 
 func (qb *StudioStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *StudioStore) All(ctx context.Context) ([]*models.Studio, error) {
 	table := qb.table()
-	return qb.getMany(ctx, qb.selectDataset().Order(table.Col(studioNameColumn).Asc()))
+	return qb.getMany(ctx, qb.selectDataset(ctx).Order(table.Col(studioNameColumn).Asc()))
 }
 
 func (qb *StudioStore) QueryForAutoTag(ctx context.Context, words []string) ([]*models.Studio, error) {
 	// TODO - Query needs to be changed to support queries of this type, and
 	// this method should be removed
 	table := qb.table()
-	sq := dialect.From(table).Select(table.Col(idColumn)).LeftJoin(
+	sq := dialect(ctx).From(table).Select(table.Col(idColumn)).LeftJoin(
 		studiosAliasesJoinTable,
 		goqu.On(studiosAliasesJoinTable.Col(studioIDColumn).Eq(table.Col(idColumn))),
 	)
@@ -495,8 +495,8 @@ func (qb *StudioStore) QueryForAutoTag(ctx context.Context, words []string) ([]*
 	var whereClauses []exp.Expression
 
 	for _, w := range words {
-		whereClauses = append(whereClauses, table.Col(studioNameColumn).Like(w+"%"))
-		whereClauses = append(whereClauses, studiosAliasesJoinTable.Col("alias").Like(w+"%"))
+		whereClauses = append(whereClauses, table.Col(studioNameColumn).ILike(w+"%"))
+		whereClauses = append(whereClauses, studiosAliasesJoinTable.Col("alias").ILike(w+"%"))
 	}
 
 	sq = sq.Where(
@@ -524,10 +524,11 @@ func (qb *StudioStore) makeQuery(ctx context.Context, studioFilter *models.Studi
 	query := studioRepository.newQuery()
 	distinctIDs(&query, studioTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, studiosFTSTable, "studios.id", findFilter) {
 		query.join(studioAliasesTable, "", "studio_aliases.studio_id = studios.id")
 		searchColumns := []string{"studios.name", "studio_aliases.alias"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &studioFilterHandler{
@@ -539,7 +540,7 @@ func (qb *StudioStore) makeQuery(ctx context.Context, studioFilter *models.Studi
 	}
 
 	var err error
-	query.sortAndPagination, err = qb.getStudioSort(findFilter)
+	query.sortAndPagination, err = qb.getStudioSort(ctx, &query, findFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -586,10 +587,11 @@ var studioSortOptions = sortOptions{
 	"scenes_count",
 	"random",
 	"rating",
+	"relevance",
 	"updated_at",
 }
 
-func (qb *StudioStore) getStudioSort(findFilter *models.FindFilterType) (string, error) {
+func (qb *StudioStore) getStudioSort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -605,6 +607,8 @@ func (qb *StudioStore) getStudioSort(findFilter *models.FindFilterType) (string,
 		return "", err
 	}
 
+	sort = query.relevanceSortOr(sort, "name")
+
 	sortQuery := ""
 	switch sort {
 	case "tag_count":
@@ -617,12 +621,22 @@ func (qb *StudioStore) getStudioSort(findFilter *models.FindFilterType) (string,
 		sortQuery += getCountSort(studioTable, galleryTable, studioIDColumn, direction)
 	case "child_count":
 		sortQuery += getCountSort(studioTable, studioTable, studioParentIDColumn, direction)
+	case relevanceSort:
+		sortQuery += getRelevanceSort(direction)
 	default:
-		sortQuery += getSort(sort, direction, "studios")
+		if isCustomFieldSort(sort) {
+			customSort, err := studiosCustomFieldsTableMgr.sort(ctx, sort, direction, studioTable)
+			if err != nil {
+				return "", err
+			}
+			sortQuery += customSort
+		} else {
+			sortQuery += getSort(ctx, sort, direction, "studios")
+		}
 	}
 
 	// Whatever the sorting, always use name/id as a final sort
-	sortQuery += ", COALESCE(studios.name, studios.id) COLLATE NATURAL_CI ASC"
+	sortQuery += ", " + coalesceIDSQL(ctx, "studios.name", "studios.id") + " COLLATE NATURAL_CI ASC"
 	return sortQuery, nil
 }
 
@@ -649,3 +663,11 @@ func (qb *StudioStore) GetStashIDs(ctx context.Context, studioID int) ([]models.
 func (qb *StudioStore) GetAliases(ctx context.Context, studioID int) ([]string, error) {
 	return studiosAliasesTableMgr.get(ctx, studioID)
 }
+
+func (qb *StudioStore) GetCustomFields(ctx context.Context, id int) (map[string]interface{}, error) {
+	return studiosCustomFieldsTableMgr.get(ctx, id)
+}
+
+func (qb *StudioStore) SetCustomFields(ctx context.Context, id int, input models.CustomFieldsInput) error {
+	return studiosCustomFieldsTableMgr.set(ctx, id, input)
+}
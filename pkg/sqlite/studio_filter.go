@@ -84,6 +84,7 @@ func (qb *studioFilterHandler) criterionHandler() criterionHandler {
 		qb.childCountCriterionHandler(studioFilter.ChildCount),
 		&timestampCriterionHandler{studioFilter.CreatedAt, studioTable + ".created_at", nil},
 		&timestampCriterionHandler{studioFilter.UpdatedAt, studioTable + ".updated_at", nil},
+		customFieldsCriterionHandler(studioFilter.CustomFields, studiosCustomFieldsTableMgr, studioTable+".id"),
 
 		&relatedFilterHandler{
 			relatedIDCol:   "scenes.id",
@@ -124,7 +125,7 @@ func (qb *studioFilterHandler) isMissingCriterionHandler(isMissing *string) crit
 				studioRepository.stashIDs.join(f, "studio_stash_ids", "studios.id")
 				f.addWhere("studio_stash_ids.studio_id IS NULL")
 			default:
-				f.addWhere("(studios." + *isMissing + " IS NULL OR TRIM(studios." + *isMissing + ") = '')")
+				f.addWhere("(studios." + *isMissing + " IS NULL OR TRIM(CAST(studios." + *isMissing + " AS TEXT)) = '')")
 			}
 		}
 	}
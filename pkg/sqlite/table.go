@@ -32,7 +32,7 @@ func (e *NotFoundError) Error() string {
 }
 
 func (t *table) insert(ctx context.Context, o interface{}) (sql.Result, error) {
-	q := dialect.Insert(t.table).Prepared(true).Rows(o)
+	q := dialect(ctx).Insert(t.table).Prepared(true).Rows(o)
 	ret, err := exec(ctx, q)
 	if err != nil {
 		return nil, fmt.Errorf("inserting into %s: %w", t.table.GetTable(), err)
@@ -42,6 +42,22 @@ func (t *table) insert(ctx context.Context, o interface{}) (sql.Result, error) {
 }
 
 func (t *table) insertID(ctx context.Context, o interface{}) (int, error) {
+	if isPostgres(ctx) {
+		// LastInsertId is not supported by the PostgreSQL driver
+		q := dialect(ctx).Insert(t.table).Prepared(true).Rows(o).Returning(t.idColumn)
+		sql, args, err := q.ToSQL()
+		if err != nil {
+			return 0, fmt.Errorf("generating sql: %w", err)
+		}
+
+		var ret int
+		if err := dbWrapper.Get(ctx, &ret, sql, args...); err != nil {
+			return 0, fmt.Errorf("inserting into %s: %w", t.table.GetTable(), err)
+		}
+
+		return ret, nil
+	}
+
 	result, err := t.insert(ctx, o)
 	if err != nil {
 		return 0, err
@@ -56,7 +72,7 @@ func (t *table) insertID(ctx context.Context, o interface{}) (int, error) {
 }
 
 func (t *table) updateByID(ctx context.Context, id interface{}, o interface{}) error {
-	q := dialect.Update(t.table).Prepared(true).Set(o).Where(t.byID(id))
+	q := dialect(ctx).Update(t.table).Prepared(true).Set(o).Where(t.byID(id))
 
 	if _, err := exec(ctx, q); err != nil {
 		return fmt.Errorf("updating %s: %w", t.table.GetTable(), err)
@@ -78,7 +94,7 @@ func (t *table) byIDInts(ids ...int) exp.Expression {
 }
 
 func (t *table) idExists(ctx context.Context, id interface{}) (bool, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(t.table).Where(t.byID(id))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(t.table).Where(t.byID(id))
 
 	var count int
 	if err := querySimple(ctx, q, &count); err != nil {
@@ -120,7 +136,7 @@ func (t *table) destroyExisting(ctx context.Context, ids []int) error {
 }
 
 func (t *table) destroy(ctx context.Context, ids []int) error {
-	q := dialect.Delete(t.table).Where(t.idColumn.In(ids))
+	q := dialect(ctx).Delete(t.table).Where(t.idColumn.In(ids))
 
 	if _, err := exec(ctx, q); err != nil {
 		return fmt.Errorf("destroying %s: %w", t.table.GetTable(), err)
@@ -174,7 +190,7 @@ func (t *joinTable) invert() *joinTable {
 }
 
 func (t *joinTable) get(ctx context.Context, id int) ([]int, error) {
-	q := dialect.Select(t.fkColumn).From(t.table.table).Where(t.idColumn.Eq(id))
+	q := dialect(ctx).Select(t.fkColumn).From(t.table.table).Where(t.idColumn.Eq(id))
 
 	if t.orderBy != nil {
 		if t.foreignTable != nil {
@@ -245,7 +261,7 @@ func (t *joinTable) addJoins(ctx context.Context, id int, foreignIDs []int) erro
 }
 
 func (t *joinTable) destroyJoins(ctx context.Context, id int, foreignIDs []int) error {
-	q := dialect.Delete(t.table.table).Where(
+	q := dialect(ctx).Delete(t.table.table).Where(
 		t.idColumn.Eq(id),
 		t.fkColumn.In(foreignIDs),
 	)
@@ -289,7 +305,7 @@ func (r *stashIDRow) resolve() models.StashID {
 }
 
 func (t *stashIDTable) get(ctx context.Context, id int) ([]models.StashID, error) {
-	q := dialect.Select("endpoint", "stash_id", "updated_at").From(t.table.table).Where(t.idColumn.Eq(id))
+	q := dialect(ctx).Select("endpoint", "stash_id", "updated_at").From(t.table.table).Where(t.idColumn.Eq(id))
 
 	const single = false
 	var ret []models.StashID
@@ -310,7 +326,7 @@ func (t *stashIDTable) get(ctx context.Context, id int) ([]models.StashID, error
 }
 
 func (t *stashIDTable) insertJoin(ctx context.Context, id int, v models.StashID) (sql.Result, error) {
-	var q = dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), "endpoint", "stash_id", "updated_at").Vals(
+	var q = dialect(ctx).Insert(t.table.table).Cols(t.idColumn.GetCol(), "endpoint", "stash_id", "updated_at").Vals(
 		goqu.Vals{id, v.Endpoint, v.StashID, v.UpdatedAt},
 	)
 	ret, err := exec(ctx, q)
@@ -362,7 +378,7 @@ func (t *stashIDTable) addJoins(ctx context.Context, id int, v []models.StashID)
 
 func (t *stashIDTable) destroyJoins(ctx context.Context, id int, v []models.StashID) error {
 	for _, vv := range v {
-		q := dialect.Delete(t.table.table).Where(
+		q := dialect(ctx).Delete(t.table.table).Where(
 			t.idColumn.Eq(id),
 			t.table.table.Col("endpoint").Eq(vv.Endpoint),
 			t.table.table.Col("stash_id").Eq(vv.StashID),
@@ -395,7 +411,7 @@ type stringTable struct {
 }
 
 func (t *stringTable) get(ctx context.Context, id int) ([]string, error) {
-	q := dialect.Select(t.stringColumn).From(t.table.table).Where(t.idColumn.Eq(id))
+	q := dialect(ctx).Select(t.stringColumn).From(t.table.table).Where(t.idColumn.Eq(id))
 
 	const single = false
 	var ret []string
@@ -416,7 +432,7 @@ func (t *stringTable) get(ctx context.Context, id int) ([]string, error) {
 }
 
 func (t *stringTable) insertJoin(ctx context.Context, id int, v string) (sql.Result, error) {
-	q := dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), t.stringColumn.GetCol()).Vals(
+	q := dialect(ctx).Insert(t.table.table).Cols(t.idColumn.GetCol(), t.stringColumn.GetCol()).Vals(
 		goqu.Vals{id, v},
 	)
 	ret, err := exec(ctx, q)
@@ -459,7 +475,7 @@ func (t *stringTable) addJoins(ctx context.Context, id int, v []string) error {
 
 func (t *stringTable) destroyJoins(ctx context.Context, id int, v []string) error {
 	for _, vv := range v {
-		q := dialect.Delete(t.table.table).Where(
+		q := dialect(ctx).Delete(t.table.table).Where(
 			t.idColumn.Eq(id),
 			t.stringColumn.Eq(vv),
 		)
@@ -496,7 +512,7 @@ func (t *orderedValueTable[T]) positionColumn() exp.IdentifierExpression {
 }
 
 func (t *orderedValueTable[T]) get(ctx context.Context, id int) ([]T, error) {
-	q := dialect.Select(t.valueColumn).From(t.table.table).Where(t.idColumn.Eq(id)).Order(t.positionColumn().Asc())
+	q := dialect(ctx).Select(t.valueColumn).From(t.table.table).Where(t.idColumn.Eq(id)).Order(t.positionColumn().Asc())
 
 	const single = false
 	var ret []T
@@ -517,7 +533,7 @@ func (t *orderedValueTable[T]) get(ctx context.Context, id int) ([]T, error) {
 }
 
 func (t *orderedValueTable[T]) insertJoin(ctx context.Context, id int, position int, v T) (sql.Result, error) {
-	q := dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), t.positionColumn().GetCol(), t.valueColumn.GetCol()).Vals(
+	q := dialect(ctx).Insert(t.table.table).Cols(t.idColumn.GetCol(), t.positionColumn().GetCol(), t.valueColumn.GetCol()).Vals(
 		goqu.Vals{id, position, v},
 	)
 	ret, err := exec(ctx, q)
@@ -610,7 +626,7 @@ func (r groupsScenesRow) resolve(sceneID int) models.GroupsScenes {
 }
 
 func (t *scenesGroupsTable) get(ctx context.Context, id int) ([]models.GroupsScenes, error) {
-	q := dialect.Select("group_id", "scene_index").From(t.table.table).Where(t.idColumn.Eq(id))
+	q := dialect(ctx).Select("group_id", "scene_index").From(t.table.table).Where(t.idColumn.Eq(id))
 
 	const single = false
 	var ret []models.GroupsScenes
@@ -631,7 +647,7 @@ func (t *scenesGroupsTable) get(ctx context.Context, id int) ([]models.GroupsSce
 }
 
 func (t *scenesGroupsTable) insertJoin(ctx context.Context, id int, v models.GroupsScenes) (sql.Result, error) {
-	q := dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), "group_id", "scene_index").Vals(
+	q := dialect(ctx).Insert(t.table.table).Cols(t.idColumn.GetCol(), "group_id", "scene_index").Vals(
 		goqu.Vals{id, v.GroupID, intFromPtr(v.SceneIndex)},
 	)
 	ret, err := exec(ctx, q)
@@ -688,7 +704,7 @@ func (t *scenesGroupsTable) addJoins(ctx context.Context, id int, v []models.Gro
 
 func (t *scenesGroupsTable) destroyJoins(ctx context.Context, id int, v []models.GroupsScenes) error {
 	for _, vv := range v {
-		q := dialect.Delete(t.table.table).Where(
+		q := dialect(ctx).Delete(t.table.table).Where(
 			t.idColumn.Eq(id),
 			t.table.table.Col("group_id").Eq(vv.GroupID),
 		)
@@ -725,7 +741,7 @@ func (t *imageGalleriesTable) setCover(ctx context.Context, id int, galleryID in
 
 	table := t.table.table
 
-	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+	q := dialect(ctx).Update(table).Prepared(true).Set(goqu.Record{
 		"cover": true,
 	}).Where(t.idColumn.Eq(id), table.Col(galleryIDColumn).Eq(galleryID))
 
@@ -739,7 +755,7 @@ func (t *imageGalleriesTable) setCover(ctx context.Context, id int, galleryID in
 func (t *imageGalleriesTable) resetCover(ctx context.Context, galleryID int) error {
 	table := t.table.table
 
-	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
+	q := dialect(ctx).Update(table).Prepared(true).Set(goqu.Record{
 		"cover": false,
 	}).Where(
 		table.Col(galleryIDColumn).Eq(galleryID),
@@ -766,7 +782,7 @@ type relatedFilesTable struct {
 // get returns the file IDs related to the provided scene ID
 // the primary file is returned first
 func (t *relatedFilesTable) get(ctx context.Context, id int) ([]models.FileID, error) {
-	q := dialect.Select("file_id").From(t.table.table).Where(t.idColumn.Eq(id)).Order(t.table.table.Col("primary").Desc())
+	q := dialect(ctx).Select("file_id").From(t.table.table).Where(t.idColumn.Eq(id)).Order(t.table.table.Col("primary").Desc())
 
 	const single = false
 	var ret []models.FileID
@@ -787,7 +803,7 @@ func (t *relatedFilesTable) get(ctx context.Context, id int) ([]models.FileID, e
 }
 
 func (t *relatedFilesTable) insertJoin(ctx context.Context, id int, primary bool, fileID models.FileID) error {
-	q := dialect.Insert(t.table.table).Cols(t.idColumn.GetCol(), "primary", "file_id").Vals(
+	q := dialect(ctx).Insert(t.table.table).Cols(t.idColumn.GetCol(), "primary", "file_id").Vals(
 		goqu.Vals{id, primary, fileID},
 	)
 	_, err := exec(ctx, q)
@@ -819,7 +835,7 @@ func (t *relatedFilesTable) replaceJoins(ctx context.Context, id int, fileIDs []
 
 // destroyJoins destroys all entries in the table with the provided fileIDs
 func (t *relatedFilesTable) destroyJoins(ctx context.Context, fileIDs []models.FileID) error {
-	q := dialect.Delete(t.table.table).Where(t.table.table.Col("file_id").In(fileIDs))
+	q := dialect(ctx).Delete(t.table.table).Where(t.table.table.Col("file_id").In(fileIDs))
 
 	if _, err := exec(ctx, q); err != nil {
 		return fmt.Errorf("destroying file joins in %s: %w", t.table.table.GetTable(), err)
@@ -831,16 +847,16 @@ func (t *relatedFilesTable) destroyJoins(ctx context.Context, fileIDs []models.F
 func (t *relatedFilesTable) setPrimary(ctx context.Context, id int, fileID models.FileID) error {
 	table := t.table.table
 
-	q := dialect.Update(table).Prepared(true).Set(goqu.Record{
-		"primary": 0,
+	q := dialect(ctx).Update(table).Prepared(true).Set(goqu.Record{
+		"primary": false,
 	}).Where(t.idColumn.Eq(id), table.Col(fileIDColumn).Neq(fileID))
 
 	if _, err := exec(ctx, q); err != nil {
 		return fmt.Errorf("unsetting primary flags in %s: %w", t.table.table.GetTable(), err)
 	}
 
-	q = dialect.Update(table).Prepared(true).Set(goqu.Record{
-		"primary": 1,
+	q = dialect(ctx).Update(table).Prepared(true).Set(goqu.Record{
+		"primary": true,
 	}).Where(t.idColumn.Eq(id), table.Col(fileIDColumn).Eq(fileID))
 
 	if _, err := exec(ctx, q); err != nil {
@@ -858,7 +874,7 @@ type viewHistoryTable struct {
 func (t *viewHistoryTable) getDates(ctx context.Context, id int) ([]time.Time, error) {
 	table := t.table.table
 
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		t.dateColumn,
 	).From(table).Where(
 		t.idColumn.Eq(id),
@@ -883,7 +899,7 @@ func (t *viewHistoryTable) getDates(ctx context.Context, id int) ([]time.Time, e
 func (t *viewHistoryTable) getManyDates(ctx context.Context, ids []int) ([][]time.Time, error) {
 	table := t.table.table
 
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		t.idColumn,
 		t.dateColumn,
 	).From(table).Where(
@@ -913,7 +929,7 @@ func (t *viewHistoryTable) getManyDates(ctx context.Context, ids []int) ([][]tim
 
 func (t *viewHistoryTable) getLastDate(ctx context.Context, id int) (*time.Time, error) {
 	table := t.table.table
-	q := dialect.Select(t.dateColumn).From(table).Where(
+	q := dialect(ctx).Select(t.dateColumn).From(table).Where(
 		t.idColumn.Eq(id),
 	).Order(t.dateColumn.Desc()).Limit(1)
 
@@ -928,7 +944,7 @@ func (t *viewHistoryTable) getLastDate(ctx context.Context, id int) (*time.Time,
 func (t *viewHistoryTable) getManyLastDate(ctx context.Context, ids []int) ([]*time.Time, error) {
 	table := t.table.table
 
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		t.idColumn,
 		goqu.MAX(t.dateColumn),
 	).From(table).Where(
@@ -966,7 +982,7 @@ func (t *viewHistoryTable) getManyLastDate(ctx context.Context, ids []int) ([]*t
 
 func (t *viewHistoryTable) getCount(ctx context.Context, id int) (int, error) {
 	table := t.table.table
-	q := dialect.Select(goqu.COUNT("*")).From(table).Where(t.idColumn.Eq(id))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(table).Where(t.idColumn.Eq(id))
 
 	const single = true
 	var ret int
@@ -985,7 +1001,7 @@ func (t *viewHistoryTable) getCount(ctx context.Context, id int) (int, error) {
 func (t *viewHistoryTable) getManyCount(ctx context.Context, ids []int) ([]int, error) {
 	table := t.table.table
 
-	q := dialect.Select(
+	q := dialect(ctx).Select(
 		t.idColumn,
 		goqu.COUNT(t.dateColumn),
 	).From(table).Where(
@@ -1015,7 +1031,7 @@ func (t *viewHistoryTable) getManyCount(ctx context.Context, ids []int) ([]int,
 
 func (t *viewHistoryTable) getAllCount(ctx context.Context) (int, error) {
 	table := t.table.table
-	q := dialect.Select(goqu.COUNT("*")).From(table)
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(table)
 
 	const single = true
 	var ret int
@@ -1033,7 +1049,7 @@ func (t *viewHistoryTable) getAllCount(ctx context.Context) (int, error) {
 
 func (t *viewHistoryTable) getUniqueCount(ctx context.Context) (int, error) {
 	table := t.table.table
-	q := dialect.Select(goqu.COUNT(goqu.DISTINCT(t.idColumn))).From(table)
+	q := dialect(ctx).Select(goqu.COUNT(goqu.DISTINCT(t.idColumn))).From(table)
 
 	const single = true
 	var ret int
@@ -1057,7 +1073,7 @@ func (t *viewHistoryTable) addDates(ctx context.Context, id int, dates []time.Ti
 	}
 
 	for _, d := range dates {
-		q := dialect.Insert(table).Cols(t.idColumn.GetCol(), t.dateColumn.GetCol()).Vals(
+		q := dialect(ctx).Insert(table).Cols(t.idColumn.GetCol(), t.dateColumn.GetCol()).Vals(
 			// convert all dates to UTC
 			goqu.Vals{id, UTCTimestamp{Timestamp{d}}},
 		)
@@ -1083,17 +1099,17 @@ func (t *viewHistoryTable) deleteDates(ctx context.Context, id int, dates []time
 		var subquery *goqu.SelectDataset
 		if mostRecent {
 			// delete the most recent
-			subquery = dialect.Select("rowid").From(table).Where(
+			subquery = dialect(ctx).Select(rowIDColumn(ctx)).From(table).Where(
 				t.idColumn.Eq(id),
 			).Order(t.dateColumn.Desc()).Limit(1)
 		} else {
-			subquery = dialect.Select("rowid").From(table).Where(
+			subquery = dialect(ctx).Select(rowIDColumn(ctx)).From(table).Where(
 				t.idColumn.Eq(id),
 				t.dateColumn.Eq(UTCTimestamp{Timestamp{date}}),
 			).Limit(1)
 		}
 
-		q := dialect.Delete(table).Where(goqu.I("rowid").Eq(subquery))
+		q := dialect(ctx).Delete(table).Where(goqu.I(rowIDColumn(ctx)).Eq(subquery))
 
 		if _, err := exec(ctx, q); err != nil {
 			return nil, fmt.Errorf("deleting from %s: %w", table.GetTable(), err)
@@ -1105,7 +1121,7 @@ func (t *viewHistoryTable) deleteDates(ctx context.Context, id int, dates []time
 
 func (t *viewHistoryTable) deleteAllDates(ctx context.Context, id int) (int, error) {
 	table := t.table.table
-	q := dialect.Delete(table).Where(t.idColumn.Eq(id))
+	q := dialect(ctx).Delete(table).Where(t.idColumn.Eq(id))
 
 	if _, err := exec(ctx, q); err != nil {
 		return 0, fmt.Errorf("resetting dates for id %v: %w", id, err)
@@ -1129,6 +1145,7 @@ func exec(ctx context.Context, stmt sqler) (sql.Result, error) {
 		return nil, fmt.Errorf("generating sql: %w", err)
 	}
 
+	sql = rebind(ctx, sql)
 	logger.Tracef("SQL: %s [%v]", sql, args)
 	ret, err := tx.ExecContext(ctx, sql, args...)
 	if err != nil {
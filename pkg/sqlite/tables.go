@@ -3,10 +3,11 @@ package sqlite
 import (
 	"github.com/doug-martin/goqu/v9"
 
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
 	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
-)
 
-var dialect = goqu.Dialect("sqlite3")
+	"github.com/stashapp/stash/pkg/models"
+)
 
 var (
 	galleriesImagesJoinTable  = goqu.T(galleriesImagesTable)
@@ -244,6 +245,11 @@ var (
 		table:    goqu.T(fingerprintTable),
 		idColumn: goqu.T(fingerprintTable).Col(idColumn),
 	}
+
+	fileIntegrityTableMgr = &table{
+		table:    goqu.T(fileIntegrityTable),
+		idColumn: goqu.T(fileIntegrityTable).Col(fileIDColumn),
+	}
 )
 
 var (
@@ -382,4 +388,42 @@ var (
 		table:    goqu.T(savedFilterTable),
 		idColumn: goqu.T(savedFilterTable).Col(idColumn),
 	}
+
+	trashTableMgr = &table{
+		table:    goqu.T(trashTable),
+		idColumn: goqu.T(trashTable).Col(idColumn),
+	}
+
+	offlinePathTableMgr = &table{
+		table:    goqu.T(offlinePathTable),
+		idColumn: goqu.T(offlinePathTable).Col(offlinePathColumn),
+	}
+
+	changeLogTableMgr = &table{
+		table:    goqu.T(changeLogTable),
+		idColumn: goqu.T(changeLogTable).Col(idColumn),
+	}
 )
+
+var (
+	customFieldDefinitionTableMgr = &table{
+		table:    goqu.T(customFieldDefinitionTable),
+		idColumn: goqu.T(customFieldDefinitionTable).Col(idColumn),
+	}
+
+	scenesCustomFieldsTableMgr     = newCustomFieldsTable("scene_custom_fields", sceneIDColumn, models.CustomFieldEntityTypeScene)
+	performersCustomFieldsTableMgr = newCustomFieldsTable("performer_custom_fields", performerIDColumn, models.CustomFieldEntityTypePerformer)
+	studiosCustomFieldsTableMgr    = newCustomFieldsTable("studio_custom_fields", studioIDColumn, models.CustomFieldEntityTypeStudio)
+	galleriesCustomFieldsTableMgr  = newCustomFieldsTable("gallery_custom_fields", galleryIDColumn, models.CustomFieldEntityTypeGallery)
+	groupsCustomFieldsTableMgr     = newCustomFieldsTable("group_custom_fields", groupIDColumn, models.CustomFieldEntityTypeGroup)
+)
+
+func newCustomFieldsTable(tableName string, idColumn string, entityType models.CustomFieldEntityType) *customFieldsTable {
+	return &customFieldsTable{
+		table: table{
+			table:    goqu.T(tableName),
+			idColumn: goqu.T(tableName).Col(idColumn),
+		},
+		entityType: entityType,
+	}
+}
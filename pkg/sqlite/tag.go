@@ -164,8 +164,8 @@ func (qb *TagStore) table() exp.IdentifierExpression {
 	return qb.tableMgr.table
 }
 
-func (qb *TagStore) selectDataset() *goqu.SelectDataset {
-	return dialect.From(qb.table()).Select(qb.table().All())
+func (qb *TagStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
 }
 
 func (qb *TagStore) Create(ctx context.Context, newObject *models.Tag) error {
@@ -305,7 +305,7 @@ func (qb *TagStore) FindMany(ctx context.Context, ids []int) ([]*models.Tag, err
 
 	table := qb.table()
 	if err := batchExec(ids, defaultBatchSize, func(batch []int) error {
-		q := qb.selectDataset().Prepared(true).Where(table.Col(idColumn).In(batch))
+		q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(batch))
 		unsorted, err := qb.getMany(ctx, q)
 		if err != nil {
 			return err
@@ -332,7 +332,7 @@ func (qb *TagStore) FindMany(ctx context.Context, ids []int) ([]*models.Tag, err
 
 // returns nil, sql.ErrNoRows if not found
 func (qb *TagStore) find(ctx context.Context, id int) (*models.Tag, error) {
-	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -383,7 +383,7 @@ func (qb *TagStore) FindBySceneID(ctx context.Context, sceneID int) ([]*models.T
 		WHERE scenes_join.scene_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{sceneID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -395,7 +395,7 @@ func (qb *TagStore) FindByPerformerID(ctx context.Context, performerID int) ([]*
 		WHERE performers_join.performer_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{performerID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -407,7 +407,7 @@ func (qb *TagStore) FindByImageID(ctx context.Context, imageID int) ([]*models.T
 		WHERE images_join.image_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{imageID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -419,7 +419,7 @@ func (qb *TagStore) FindByGalleryID(ctx context.Context, galleryID int) ([]*mode
 		WHERE galleries_join.gallery_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{galleryID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -431,7 +431,7 @@ func (qb *TagStore) FindByGroupID(ctx context.Context, groupID int) ([]*models.T
 		WHERE groups_join.group_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{groupID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -443,7 +443,7 @@ func (qb *TagStore) FindBySceneMarkerID(ctx context.Context, sceneMarkerID int)
 		WHERE scene_markers_join.scene_marker_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{sceneMarkerID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -455,7 +455,7 @@ func (qb *TagStore) FindByStudioID(ctx context.Context, studioID int) ([]*models
 		WHERE studios_join.studio_id = ?
 		GROUP BY tags.id
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{studioID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -470,7 +470,7 @@ func (qb *TagStore) FindByName(ctx context.Context, name string, nocase bool) (*
 	if nocase {
 		where += " COLLATE NOCASE"
 	}
-	sq := qb.selectDataset().Prepared(true).Where(goqu.L(where, name)).Limit(1)
+	sq := qb.selectDataset(ctx).Prepared(true).Where(goqu.L(where, name)).Limit(1)
 	ret, err := qb.get(ctx, sq)
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -495,7 +495,7 @@ func (qb *TagStore) FindByNames(ctx context.Context, names []string, nocase bool
 	for _, name := range names {
 		args = append(args, name)
 	}
-	sq := qb.selectDataset().Prepared(true).Where(goqu.L(where, args...))
+	sq := qb.selectDataset(ctx).Prepared(true).Where(goqu.L(where, args...))
 	ret, err := qb.getMany(ctx, sq)
 
 	if err != nil {
@@ -519,7 +519,7 @@ func (qb *TagStore) FindByParentTagID(ctx context.Context, parentID int) ([]*mod
 		INNER JOIN tags_relations ON tags_relations.child_id = tags.id
 		WHERE tags_relations.parent_id = ?
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{parentID}
 	return qb.queryTags(ctx, query, args)
 }
@@ -530,34 +530,34 @@ func (qb *TagStore) FindByChildTagID(ctx context.Context, parentID int) ([]*mode
 		INNER JOIN tags_relations ON tags_relations.parent_id = tags.id
 		WHERE tags_relations.child_id = ?
 	`
-	query += qb.getDefaultTagSort()
+	query += qb.getDefaultTagSort(ctx)
 	args := []interface{}{parentID}
 	return qb.queryTags(ctx, query, args)
 }
 
 func (qb *TagStore) CountByParentTagID(ctx context.Context, parentID int) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(goqu.T("tags")).
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(goqu.T("tags")).
 		InnerJoin(goqu.T("tags_relations"), goqu.On(goqu.I("tags_relations.parent_id").Eq(goqu.I("tags.id")))).
 		Where(goqu.I("tags_relations.child_id").Eq(goqu.V(parentID))) // Pass the parentID here
 	return count(ctx, q)
 }
 
 func (qb *TagStore) CountByChildTagID(ctx context.Context, childID int) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(goqu.T("tags")).
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(goqu.T("tags")).
 		InnerJoin(goqu.T("tags_relations"), goqu.On(goqu.I("tags_relations.child_id").Eq(goqu.I("tags.id")))).
 		Where(goqu.I("tags_relations.parent_id").Eq(goqu.V(childID))) // Pass the childID here
 	return count(ctx, q)
 }
 
 func (qb *TagStore) Count(ctx context.Context) (int, error) {
-	q := dialect.Select(goqu.COUNT("*")).From(qb.table())
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(qb.table())
 	return count(ctx, q)
 }
 
 func (qb *TagStore) All(ctx context.Context) ([]*models.Tag, error) {
 	table := qb.table()
 
-	return qb.getMany(ctx, qb.selectDataset().Order(
+	return qb.getMany(ctx, qb.selectDataset(ctx).Order(
 		table.Col("name").Asc(),
 		table.Col(idColumn).Asc(),
 	))
@@ -574,17 +574,17 @@ func (qb *TagStore) QueryForAutoTag(ctx context.Context, words []string) ([]*mod
 
 	for _, w := range words {
 		ww := w + "%"
-		whereClauses = append(whereClauses, "tags.name like ?")
+		whereClauses = append(whereClauses, likeSQL(ctx, "tags.name", false))
 		args = append(args, ww)
 
 		// include aliases
-		whereClauses = append(whereClauses, "tag_aliases.alias like ?")
+		whereClauses = append(whereClauses, likeSQL(ctx, "tag_aliases.alias", false))
 		args = append(args, ww)
 	}
 
 	whereOr := "(" + strings.Join(whereClauses, " OR ") + ")"
 	where := strings.Join([]string{
-		"tags.ignore_auto_tag = 0",
+		"tags.ignore_auto_tag = FALSE",
 		whereOr,
 	}, " AND ")
 	return qb.queryTags(ctx, query+" WHERE "+where, args)
@@ -601,10 +601,11 @@ func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType,
 	query := tagRepository.newQuery()
 	distinctIDs(&query, tagTable)
 
-	if q := findFilter.Q; q != nil && *q != "" {
+	// the search index is only used when sorting by relevance
+	if q := findFilter.Q; q != nil && *q != "" && !query.addFTSSearch(ctx, tagsFTSTable, "tags.id", findFilter) {
 		query.join(tagAliasesTable, "", "tag_aliases.tag_id = tags.id")
 		searchColumns := []string{"tags.name", "tag_aliases.alias"}
-		query.parseQueryString(searchColumns, *q)
+		query.parseQueryString(ctx, searchColumns, *q)
 	}
 
 	filter := filterBuilderFromHandler(ctx, &tagFilterHandler{
@@ -616,7 +617,7 @@ func (qb *TagStore) Query(ctx context.Context, tagFilter *models.TagFilterType,
 	}
 
 	var err error
-	query.sortAndPagination, err = qb.getTagSort(&query, findFilter)
+	query.sortAndPagination, err = qb.getTagSort(ctx, &query, findFilter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -645,16 +646,17 @@ var tagSortOptions = sortOptions{
 	"name",
 	"performers_count",
 	"random",
+	"relevance",
 	"scene_markers_count",
 	"scenes_count",
 	"updated_at",
 }
 
-func (qb *TagStore) getDefaultTagSort() string {
-	return getSort("name", "ASC", "tags")
+func (qb *TagStore) getDefaultTagSort(ctx context.Context) string {
+	return getSort(ctx, "name", "ASC", "tags")
 }
 
-func (qb *TagStore) getTagSort(query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
+func (qb *TagStore) getTagSort(ctx context.Context, query *queryBuilder, findFilter *models.FindFilterType) (string, error) {
 	var sort string
 	var direction string
 	if findFilter == nil {
@@ -670,6 +672,8 @@ func (qb *TagStore) getTagSort(query *queryBuilder, findFilter *models.FindFilte
 		return "", err
 	}
 
+	sort = query.relevanceSortOr(sort, "name")
+
 	sortQuery := ""
 	switch sort {
 	case "scenes_count":
@@ -686,12 +690,14 @@ func (qb *TagStore) getTagSort(query *queryBuilder, findFilter *models.FindFilte
 		sortQuery += getCountSort(tagTable, studiosTagsTable, tagIDColumn, direction)
 	case "movies_count", "groups_count":
 		sortQuery += getCountSort(tagTable, groupsTagsTable, tagIDColumn, direction)
+	case relevanceSort:
+		sortQuery += getRelevanceSort(direction)
 	default:
-		sortQuery += getSort(sort, direction, "tags")
+		sortQuery += getSort(ctx, sort, direction, "tags")
 	}
 
 	// Whatever the sorting, always use name/id as a final sort
-	sortQuery += ", COALESCE(tags.name, tags.id) COLLATE NATURAL_CI ASC"
+	sortQuery += ", " + coalesceIDSQL(ctx, "tags.name", "tags.id") + " COLLATE NATURAL_CI ASC"
 	return sortQuery, nil
 }
 
@@ -927,7 +933,7 @@ type tagRelationshipStore struct {
 
 func (s *tagRelationshipStore) CountByTagID(ctx context.Context, tagID int) (int, error) {
 	joinTable := s.joinTable.table.table
-	q := dialect.Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(tagIDColumn).Eq(tagID))
+	q := dialect(ctx).Select(goqu.COUNT("*")).From(joinTable).Where(joinTable.Col(tagIDColumn).Eq(tagID))
 	return count(ctx, q)
 }
 
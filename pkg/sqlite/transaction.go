@@ -2,11 +2,13 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"runtime/debug"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/mattn/go-sqlite3"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -18,6 +20,7 @@ const (
 	txnKey key = iota + 1
 	dbKey
 	writableKey
+	dbTypeKey
 )
 
 func (db *Database) WithDatabase(ctx context.Context) (context.Context, error) {
@@ -26,6 +29,8 @@ func (db *Database) WithDatabase(ctx context.Context) (context.Context, error) {
 		return ctx, nil
 	}
 
+	ctx = withDatabaseType(ctx, db.dbType)
+
 	return context.WithValue(ctx, dbKey, db.readDB), nil
 }
 
@@ -42,12 +47,19 @@ func (db *Database) Begin(ctx context.Context, writable bool) (context.Context,
 		dbtx = db.writeDB
 	}
 
-	tx, err := dbtx.BeginTxx(ctx, nil)
+	var opts *sql.TxOptions
+	if db.dbType == PostgresBackend && !writable {
+		// SQLite read connections are opened in read-only mode
+		opts = &sql.TxOptions{ReadOnly: true}
+	}
+
+	tx, err := dbtx.BeginTxx(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("beginning transaction: %w", err)
 	}
 
 	ctx = context.WithValue(ctx, writableKey, writable)
+	ctx = withDatabaseType(ctx, db.dbType)
 
 	return context.WithValue(ctx, txnKey, tx), nil
 }
@@ -112,6 +124,13 @@ func (db *Database) IsLocked(err error) bool {
 	if errors.As(err, &sqliteError) {
 		return sqliteError.Code == sqlite3.ErrBusy
 	}
+
+	// serialization failure or deadlock, in which case the transaction may
+	// be retried
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		return pqError.Code == "40001" || pqError.Code == "40P01"
+	}
 	return false
 }
 
@@ -131,5 +150,9 @@ func (db *Database) Repository() models.Repository {
 		Studio:         db.Studio,
 		Tag:            db.Tag,
 		SavedFilter:    db.SavedFilter,
+		Trash:          db.Trash,
+		OfflinePath:    db.OfflinePath,
+		CustomField:    db.CustomField,
+		ChangeLog:      db.ChangeLog,
 	}
 }
@@ -285,3 +285,24 @@ func TestConcurrentExclusiveAndReadTxn(t *testing.T) {
 
 // 	wg.Wait()
 // }
+
+func TestReadTxnTimeout(t *testing.T) {
+	// an unbounded recursive query that only completes when interrupted
+	const query = `WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT MAX(x) FROM c`
+
+	ctx := txn.WithReadTimeout(context.Background(), 50*time.Millisecond)
+
+	start := time.Now()
+	err := txn.WithReadTxn(ctx, db, func(ctx context.Context) error {
+		_, _, err := db.QuerySQL(ctx, query, nil)
+		return err
+	})
+
+	if !errors.Is(err, txn.ErrTimeout) {
+		t.Errorf("WithReadTxn() error = %v, want %v", err, txn.ErrTimeout)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("query was not interrupted, took %v", elapsed)
+	}
+}
@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const trashTable = "trash"
+
+type trashRow struct {
+	ID         int                    `db:"id" goqu:"skipinsert"`
+	ObjectType models.TrashObjectType `db:"object_type"`
+	Name       string                 `db:"name"`
+	Files      string                 `db:"files"`
+	Snapshot   []byte                 `db:"snapshot"`
+	DeletedAt  Timestamp              `db:"deleted_at"`
+}
+
+func (r *trashRow) fromTrashItem(o models.TrashItem) {
+	r.ID = o.ID
+	r.ObjectType = o.ObjectType
+	r.Name = o.Name
+	r.Files = encodeJSONOrEmpty(o.Files)
+	r.Snapshot = o.Snapshot
+	r.DeletedAt = Timestamp{Timestamp: o.DeletedAt}
+}
+
+func (r *trashRow) resolve() *models.TrashItem {
+	ret := &models.TrashItem{
+		ID:         r.ID,
+		ObjectType: r.ObjectType,
+		Name:       r.Name,
+		Snapshot:   r.Snapshot,
+		DeletedAt:  r.DeletedAt.Timestamp,
+	}
+
+	decodeJSON(r.Files, &ret.Files)
+
+	return ret
+}
+
+type TrashStore struct {
+	repository
+	tableMgr *table
+}
+
+func NewTrashStore() *TrashStore {
+	return &TrashStore{
+		repository: repository{
+			tableName: trashTable,
+			idColumn:  idColumn,
+		},
+		tableMgr: trashTableMgr,
+	}
+}
+
+func (qb *TrashStore) table() exp.IdentifierExpression {
+	return qb.tableMgr.table
+}
+
+func (qb *TrashStore) selectDataset(ctx context.Context) *goqu.SelectDataset {
+	return dialect(ctx).From(qb.table()).Select(qb.table().All())
+}
+
+func (qb *TrashStore) Create(ctx context.Context, newObject *models.TrashItem) error {
+	var r trashRow
+	r.fromTrashItem(*newObject)
+
+	id, err := qb.tableMgr.insertID(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	updated, err := qb.Find(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding after create: %w", err)
+	}
+
+	*newObject = *updated
+
+	return nil
+}
+
+func (qb *TrashStore) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+// returns nil, nil if not found
+func (qb *TrashStore) Find(ctx context.Context, id int) (*models.TrashItem, error) {
+	q := qb.selectDataset(ctx).Where(qb.tableMgr.byID(id))
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *TrashStore) FindMany(ctx context.Context, ids []int) ([]*models.TrashItem, error) {
+	ret := make([]*models.TrashItem, len(ids))
+
+	table := qb.table()
+	q := qb.selectDataset(ctx).Prepared(true).Where(table.Col(idColumn).In(ids))
+	unsorted, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range unsorted {
+		i := slices.Index(ids, s.ID)
+		ret[i] = s
+	}
+
+	for i := range ret {
+		if ret[i] == nil {
+			return nil, fmt.Errorf("trash item with id %d not found", ids[i])
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *TrashStore) FindDeletedBefore(ctx context.Context, t time.Time) ([]*models.TrashItem, error) {
+	table := qb.table()
+	q := qb.selectDataset(ctx).Prepared(true).Where(
+		table.Col("deleted_at").Lt(Timestamp{Timestamp: t}),
+	).Order(table.Col("deleted_at").Asc())
+
+	return qb.getMany(ctx, q)
+}
+
+// Query returns the items matching the object type and the name query in
+// findFilter, most recently deleted first unless sorted otherwise, along with
+// the total number of matching items.
+func (qb *TrashStore) Query(ctx context.Context, objectType *models.TrashObjectType, findFilter *models.FindFilterType) ([]*models.TrashItem, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	table := qb.table()
+
+	var where []exp.Expression
+	if objectType != nil {
+		where = append(where, table.Col("object_type").Eq(*objectType))
+	}
+	if q := findFilter.Q; q != nil && *q != "" {
+		where = append(where, table.Col("name").ILike("%"+*q+"%"))
+	}
+
+	countQ := dialect(ctx).From(table).Prepared(true).Select(goqu.COUNT("*")).Where(where...)
+	total, err := count(ctx, countQ)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := qb.selectDataset(ctx).Prepared(true).Where(where...)
+
+	sort := "deleted_at"
+	direction := "DESC"
+	if findFilter.Sort != nil && *findFilter.Sort != "" {
+		sort = findFilter.GetSort("deleted_at")
+		direction = findFilter.GetDirection()
+	}
+
+	switch sort {
+	case "deleted_at", "name", "object_type":
+	default:
+		return nil, 0, fmt.Errorf("%w: %s", models.ErrInvalidSort, sort)
+	}
+
+	if direction == "DESC" {
+		q = q.Order(table.Col(sort).Desc(), table.Col(idColumn).Desc())
+	} else {
+		q = q.Order(table.Col(sort).Asc(), table.Col(idColumn).Asc())
+	}
+
+	if !findFilter.IsGetAll() {
+		perPage := findFilter.GetPageSize()
+		q = q.Limit(uint(perPage)).Offset(uint((findFilter.GetPage() - 1) * perPage))
+	}
+
+	ret, err := qb.getMany(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ret, total, nil
+}
+
+func (qb *TrashStore) All(ctx context.Context) ([]*models.TrashItem, error) {
+	return qb.getMany(ctx, qb.selectDataset(ctx).Order(qb.table().Col("deleted_at").Asc()))
+}
+
+func (qb *TrashStore) getMany(ctx context.Context, q *goqu.SelectDataset) ([]*models.TrashItem, error) {
+	const single = false
+	var ret []*models.TrashItem
+	if err := queryFunc(ctx, q, single, func(r *sqlx.Rows) error {
+		var f trashRow
+		if err := r.StructScan(&f); err != nil {
+			return err
+		}
+
+		ret = append(ret, f.resolve())
+		return nil
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,166 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTrashItem(objectType models.TrashObjectType, name string, deletedAt time.Time) *models.TrashItem {
+	return &models.TrashItem{
+		ObjectType: objectType,
+		Name:       name,
+		Files: []models.TrashFile{
+			{
+				OriginalPath: "/library/" + name,
+				TrashPath:    "/trash/1/" + name,
+				Size:         100,
+			},
+		},
+		Snapshot:  []byte(`{"scene":{"title":"` + name + `"}}`),
+		DeletedAt: deletedAt,
+	}
+}
+
+func TestTrashStore_RoundTrip(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Trash
+		deletedAt := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		item := makeTrashItem(models.TrashObjectTypeScene, "scene.mp4", deletedAt)
+		want := *item
+
+		if err := qb.Create(ctx, item); err != nil {
+			t.Errorf("TrashStore.Create() error = %v", err)
+			return nil
+		}
+
+		want.ID = item.ID
+		assert.NotZero(t, item.ID)
+		assert.Equal(t, want, *item)
+
+		got, err := qb.Find(ctx, item.ID)
+		if err != nil {
+			t.Errorf("TrashStore.Find() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, &want, got)
+
+		many, err := qb.FindMany(ctx, []int{item.ID})
+		if err != nil {
+			t.Errorf("TrashStore.FindMany() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []*models.TrashItem{&want}, many)
+
+		if err := qb.Destroy(ctx, item.ID); err != nil {
+			t.Errorf("TrashStore.Destroy() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.Find(ctx, item.ID)
+		if err != nil {
+			t.Errorf("TrashStore.Find() error = %v", err)
+			return nil
+		}
+		assert.Nil(t, got)
+
+		if _, err := qb.FindMany(ctx, []int{item.ID}); err == nil {
+			t.Errorf("TrashStore.FindMany() expected error for destroyed item")
+		}
+
+		return nil
+	})
+}
+
+func TestTrashStore_Query(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Trash
+		base := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		scene := makeTrashItem(models.TrashObjectTypeScene, "b scene.mp4", base)
+		image := makeTrashItem(models.TrashObjectTypeImage, "a image.jpg", base.Add(time.Hour))
+		gallery := makeTrashItem(models.TrashObjectTypeGallery, "c gallery.zip", base.Add(2*time.Hour))
+
+		for _, item := range []*models.TrashItem{scene, image, gallery} {
+			if err := qb.Create(ctx, item); err != nil {
+				t.Errorf("TrashStore.Create() error = %v", err)
+				return nil
+			}
+		}
+
+		ids := func(items []*models.TrashItem) []int {
+			var ret []int
+			for _, i := range items {
+				ret = append(ret, i.ID)
+			}
+			return ret
+		}
+
+		// most recently deleted first by default
+		got, total, err := qb.Query(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("TrashStore.Query() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []int{gallery.ID, image.ID, scene.ID}, ids(got))
+
+		objectType := models.TrashObjectTypeImage
+		got, total, err = qb.Query(ctx, &objectType, nil)
+		if err != nil {
+			t.Errorf("TrashStore.Query() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []int{image.ID}, ids(got))
+
+		q := "SCENE"
+		got, total, err = qb.Query(ctx, nil, &models.FindFilterType{Q: &q})
+		if err != nil {
+			t.Errorf("TrashStore.Query() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []int{scene.ID}, ids(got))
+
+		sort := "name"
+		direction := models.SortDirectionEnumAsc
+		perPage := 2
+		got, total, err = qb.Query(ctx, nil, &models.FindFilterType{Sort: &sort, Direction: &direction, PerPage: &perPage})
+		if err != nil {
+			t.Errorf("TrashStore.Query() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []int{image.ID, scene.ID}, ids(got))
+
+		invalidSort := "snapshot"
+		if _, _, err := qb.Query(ctx, nil, &models.FindFilterType{Sort: &invalidSort}); !errors.Is(err, models.ErrInvalidSort) {
+			t.Errorf("TrashStore.Query() error = %v, want %v", err, models.ErrInvalidSort)
+		}
+
+		got, err = qb.FindDeletedBefore(ctx, base.Add(90*time.Minute))
+		if err != nil {
+			t.Errorf("TrashStore.FindDeletedBefore() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []int{scene.ID, image.ID}, ids(got))
+
+		got, err = qb.All(ctx)
+		if err != nil {
+			t.Errorf("TrashStore.All() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, []int{scene.ID, image.ID, gallery.ID}, ids(got))
+
+		return nil
+	})
+}
@@ -14,10 +14,11 @@ const (
 	slowLogTime = time.Millisecond * 200
 )
 
+// dbReader uses the context variants so that queries are interrupted when
+// the context is cancelled or its deadline is exceeded.
 type dbReader interface {
-	Get(dest interface{}, query string, args ...interface{}) error
-	Select(dest interface{}, query string, args ...interface{}) error
-	Queryx(query string, args ...interface{}) (*sqlx.Rows, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
 }
 
@@ -53,8 +54,9 @@ func (*dbWrapperType) Get(ctx context.Context, dest interface{}, query string, a
 		return sqlError(err, query, args...)
 	}
 
+	query = rebind(ctx, query)
 	start := time.Now()
-	err = tx.Get(dest, query, args...)
+	err = tx.GetContext(ctx, dest, query, args...)
 	logSQL(start, query, args...)
 
 	return sqlError(err, query, args...)
@@ -66,8 +68,9 @@ func (*dbWrapperType) Select(ctx context.Context, dest interface{}, query string
 		return sqlError(err, query, args...)
 	}
 
+	query = rebind(ctx, query)
 	start := time.Now()
-	err = tx.Select(dest, query, args...)
+	err = tx.SelectContext(ctx, dest, query, args...)
 	logSQL(start, query, args...)
 
 	return sqlError(err, query, args...)
@@ -79,8 +82,9 @@ func (*dbWrapperType) Queryx(ctx context.Context, query string, args ...interfac
 		return nil, sqlError(err, query, args...)
 	}
 
+	query = rebind(ctx, query)
 	start := time.Now()
-	ret, err := tx.Queryx(query, args...)
+	ret, err := tx.QueryxContext(ctx, query, args...)
 	logSQL(start, query, args...)
 
 	return ret, sqlError(err, query, args...)
@@ -92,6 +96,7 @@ func (*dbWrapperType) QueryxContext(ctx context.Context, query string, args ...i
 		return nil, sqlError(err, query, args...)
 	}
 
+	query = rebind(ctx, query)
 	start := time.Now()
 	ret, err := tx.QueryxContext(ctx, query, args...)
 	logSQL(start, query, args...)
@@ -105,6 +110,7 @@ func (*dbWrapperType) NamedExec(ctx context.Context, query string, arg interface
 		return nil, sqlError(err, query, arg)
 	}
 
+	query = rebind(ctx, query)
 	start := time.Now()
 	ret, err := tx.NamedExec(query, arg)
 	logSQL(start, query, arg)
@@ -118,6 +124,7 @@ func (*dbWrapperType) Exec(ctx context.Context, query string, args ...interface{
 		return nil, sqlError(err, query, args...)
 	}
 
+	query = rebind(ctx, query)
 	start := time.Now()
 	ret, err := tx.Exec(query, args...)
 	logSQL(start, query, args...)
@@ -132,6 +139,7 @@ func (*dbWrapperType) Prepare(ctx context.Context, query string, args ...interfa
 		return nil, sqlError(err, query, args...)
 	}
 
+	query = rebind(ctx, query)
 	// nolint:sqlclosecheck
 	ret, err := tx.PrepareContext(ctx, query)
 	if err != nil {
@@ -81,3 +81,8 @@ func AddPostCompleteHook(ctx context.Context, hook MustFunc) {
 	m := hookManagerCtx(ctx)
 	m.postCompleteHooks = append(m.postCompleteHooks, hook)
 }
+
+// InTxn returns true if ctx is the context of a transaction.
+func InTxn(ctx context.Context) bool {
+	return hookManagerCtx(ctx) != nil
+}
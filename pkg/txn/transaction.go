@@ -3,9 +3,15 @@ package txn
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 )
 
+// ErrTimeout is returned when a transaction is not completed within its
+// timeout.
+var ErrTimeout = errors.New("transaction timed out")
+
 type Manager interface {
 	Begin(ctx context.Context, writable bool) (context.Context, error)
 	Commit(ctx context.Context) error
@@ -49,9 +55,71 @@ func WithReadTxn(ctx context.Context, m Manager, fn TxnFunc) error {
 	return withTxn(ctx, m, fn, writable, execComplete)
 }
 
+// WithTimeout executes fn in a transaction like WithTxn. If the transaction
+// is not completed within d, it is interrupted and rolled back, and an error
+// wrapping ErrTimeout is returned. A d of zero or less means no timeout.
+func WithTimeout(ctx context.Context, m Manager, d time.Duration, fn TxnFunc) error {
+	const (
+		execComplete = true
+		writable     = true
+	)
+	return withTimeout(ctx, d, func(timeoutCtx context.Context) error {
+		return runTxn(ctx, timeoutCtx, m, fn, writable, execComplete)
+	})
+}
+
+type readTimeoutKey struct{}
+
+// WithReadTimeout returns a context that limits the duration of read
+// transactions started with it to d. Read transactions that exceed the
+// timeout are interrupted and return an error wrapping ErrTimeout.
+// A d of zero or less means no timeout.
+func WithReadTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, readTimeoutKey{}, d)
+}
+
+// ReadTimeout returns the read transaction timeout set by WithReadTimeout,
+// or zero if none is set.
+func ReadTimeout(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(readTimeoutKey{}).(time.Duration)
+	return d
+}
+
+func withTimeout(ctx context.Context, d time.Duration, fn func(ctx context.Context) error) error {
+	if d <= 0 {
+		return fn(ctx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeoutCause(ctx, d, ErrTimeout)
+	defer cancel()
+
+	err := fn(timeoutCtx)
+	if err != nil && !errors.Is(err, ErrTimeout) && errors.Is(context.Cause(timeoutCtx), ErrTimeout) {
+		// the underlying error is usually an interrupt or deadline error,
+		// which is not useful to the caller
+		err = fmt.Errorf("%w after %v: %v", ErrTimeout, d, err)
+	}
+
+	return err
+}
+
 func withTxn(ctx context.Context, m Manager, fn TxnFunc, writable bool, execCompleteOnLocked bool) error {
+	if !writable {
+		if d := ReadTimeout(ctx); d > 0 {
+			return withTimeout(ctx, d, func(timeoutCtx context.Context) error {
+				return runTxn(ctx, timeoutCtx, m, fn, writable, execCompleteOnLocked)
+			})
+		}
+	}
+
+	return runTxn(ctx, ctx, m, fn, writable, execCompleteOnLocked)
+}
+
+// runTxn executes fn in a transaction started with txnCtx. Post-hooks are
+// executed with ctx.
+func runTxn(ctx context.Context, txnCtx context.Context, m Manager, fn TxnFunc, writable bool, execCompleteOnLocked bool) error {
 	// post-hooks should be executed with the outside context
-	txnCtx, err := begin(ctx, m, writable)
+	txnCtx, err := begin(txnCtx, m, writable)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,109 @@
+package txn
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testManager struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (m *testManager) Begin(ctx context.Context, writable bool) (context.Context, error) {
+	return ctx, nil
+}
+
+func (m *testManager) Commit(ctx context.Context) error {
+	m.committed = true
+	return nil
+}
+
+func (m *testManager) Rollback(ctx context.Context) error {
+	m.rolledBack = true
+	return nil
+}
+
+func (m *testManager) IsLocked(err error) bool {
+	return false
+}
+
+// waitForDone blocks until the context is done or the test times out.
+func waitForDone(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+func TestWithReadTxn_Timeout(t *testing.T) {
+	m := &testManager{}
+	ctx := WithReadTimeout(context.Background(), 10*time.Millisecond)
+
+	var hookErr error
+	err := WithReadTxn(ctx, m, func(ctx context.Context) error {
+		AddPostCompleteHook(ctx, func(ctx context.Context) {
+			hookErr = ctx.Err()
+		})
+		return waitForDone(ctx)
+	})
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("WithReadTxn() error = %v, want %v", err, ErrTimeout)
+	}
+	if !m.rolledBack {
+		t.Error("transaction was not rolled back")
+	}
+	if hookErr != nil {
+		t.Errorf("post-complete hook context error = %v, want nil", hookErr)
+	}
+}
+
+func TestWithReadTxn_NoTimeout(t *testing.T) {
+	m := &testManager{}
+	ctx := WithReadTimeout(context.Background(), time.Minute)
+
+	err := WithReadTxn(ctx, m, func(ctx context.Context) error {
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("WithReadTxn() error = %v, want nil", err)
+	}
+	if !m.committed {
+		t.Error("transaction was not committed")
+	}
+}
+
+func TestWithTxn_IgnoresReadTimeout(t *testing.T) {
+	m := &testManager{}
+	ctx := WithReadTimeout(context.Background(), time.Nanosecond)
+
+	err := WithTxn(ctx, m, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); ok {
+			return errors.New("writable transaction has a deadline")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Errorf("WithTxn() error = %v, want nil", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	m := &testManager{}
+
+	err := WithTimeout(context.Background(), m, 10*time.Millisecond, waitForDone)
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("WithTimeout() error = %v, want %v", err, ErrTimeout)
+	}
+	if !m.rolledBack {
+		t.Error("transaction was not rolled back")
+	}
+}
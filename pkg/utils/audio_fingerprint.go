@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// DefaultAudioSimilarity is the proportion of matching bits above which
+	// two audio fingerprints are considered to be the same audio.
+	DefaultAudioSimilarity = 0.85
+
+	// minimum number of overlapping sub-fingerprints compared, around ten
+	// seconds of audio
+	minAudioOverlap = 80
+
+	// minimum number of identical sub-fingerprints at an offset for it to be
+	// considered when finding duplicates
+	minAudioOffsetMatches = 5
+
+	// sub-fingerprints shared by more files than this are ignored when
+	// finding duplicates. These are typically silence or test tones.
+	maxAudioValueFiles = 100
+)
+
+// AudioFingerprintToString encodes the sub-fingerprints as base64.
+func AudioFingerprintToString(fp []uint32) string {
+	data := make([]byte, len(fp)*4)
+	for i, v := range fp {
+		binary.LittleEndian.PutUint32(data[i*4:], v)
+	}
+
+	return base64.RawStdEncoding.EncodeToString(data)
+}
+
+// StringToAudioFingerprint decodes sub-fingerprints encoded with
+// AudioFingerprintToString.
+func StringToAudioFingerprint(s string) ([]uint32, error) {
+	data, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid audio fingerprint length %d", len(data))
+	}
+
+	ret := make([]uint32, len(data)/4)
+	for i := range ret {
+		ret[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+
+	return ret, nil
+}
+
+// CompareAudioFingerprints returns the proportion of matching bits between a
+// and b, and the offset of b relative to a at which they were compared. The
+// offset is the one with the most identical sub-fingerprints.
+func CompareAudioFingerprints(a, b []uint32) (float64, int) {
+	positions := make(map[uint32][]int)
+	for j, v := range b {
+		positions[v] = append(positions[v], j)
+	}
+
+	offsets := make(map[int]int)
+	for i, v := range a {
+		// ignore silence
+		if v == 0 {
+			continue
+		}
+		for _, j := range positions[v] {
+			offsets[j-i]++
+		}
+	}
+
+	offset, count := bestAudioOffset(offsets)
+	if count == 0 {
+		return 0, 0
+	}
+
+	return audioSimilarity(a, b, offset), offset
+}
+
+func bestAudioOffset(offsets map[int]int) (int, int) {
+	best, count := 0, 0
+	for o, c := range offsets {
+		// prefer the smallest offset if counts are equal
+		if c > count || (c == count && (abs(o) < abs(best) || (abs(o) == abs(best) && o < best))) {
+			best, count = o, c
+		}
+	}
+
+	return best, count
+}
+
+// audioSimilarity returns the proportion of matching bits where a and b
+// overlap when b is offset by offset.
+func audioSimilarity(a, b []uint32, offset int) float64 {
+	var overlap, errors int
+	for i, v := range a {
+		j := i + offset
+		if j < 0 {
+			continue
+		}
+		if j >= len(b) {
+			break
+		}
+
+		overlap++
+		errors += bits.OnesCount32(v ^ b[j])
+	}
+
+	if overlap < minAudioOverlap {
+		return 0
+	}
+
+	return 1 - float64(errors)/float64(overlap*32)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+type AudioFingerprint struct {
+	SceneID     int
+	Fingerprint []uint32
+}
+
+// FindAudioDuplicates returns groups of scene IDs whose audio fingerprints
+// have at least minSimilarity matching bits at their best offset.
+func FindAudioDuplicates(fps []*AudioFingerprint, minSimilarity float64) [][]int {
+	type ref struct {
+		file  int
+		index int
+	}
+
+	index := make(map[uint32][]ref)
+	for fi, fp := range fps {
+		for i, v := range fp.Fingerprint {
+			if v == 0 {
+				continue
+			}
+			index[v] = append(index[v], ref{file: fi, index: i})
+		}
+	}
+
+	// count the identical sub-fingerprints at each offset of each file pair
+	offsets := make(map[[2]int]map[int]int)
+	for _, refs := range index {
+		if len(refs) > maxAudioValueFiles {
+			continue
+		}
+
+		for i, x := range refs {
+			for _, y := range refs[i+1:] {
+				a, b := x, y
+				if a.file > b.file {
+					a, b = b, a
+				}
+				if fps[a.file].SceneID == fps[b.file].SceneID {
+					continue
+				}
+
+				key := [2]int{a.file, b.file}
+				if offsets[key] == nil {
+					offsets[key] = make(map[int]int)
+				}
+				offsets[key][b.index-a.index]++
+			}
+		}
+	}
+
+	keys := make([][2]int, 0, len(offsets))
+	for key := range offsets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	var pairs [][]int
+	for _, key := range keys {
+		offset, count := bestAudioOffset(offsets[key])
+		if count < minAudioOffsetMatches {
+			continue
+		}
+
+		a, b := fps[key[0]], fps[key[1]]
+		if audioSimilarity(a.Fingerprint, b.Fingerprint, offset) >= minSimilarity {
+			pairs = append(pairs, []int{a.SceneID, b.SceneID})
+		}
+	}
+
+	return MergeDuplicates(pairs)
+}
+
+// MergeDuplicates merges groups of IDs that share an ID, returning the
+// resulting groups in the order they were first encountered.
+func MergeDuplicates(groups ...[][]int) [][]int {
+	parent := make(map[int]int)
+	var find func(id int) int
+	find = func(id int) int {
+		p, ok := parent[id]
+		if !ok {
+			parent[id] = id
+			return id
+		}
+		if p != id {
+			parent[id] = find(p)
+		}
+		return parent[id]
+	}
+
+	var order []int
+	for _, g := range groups {
+		for _, ids := range g {
+			for _, id := range ids {
+				if _, ok := parent[id]; !ok {
+					order = append(order, id)
+				}
+				if root, other := find(ids[0]), find(id); root != other {
+					parent[other] = root
+				}
+			}
+		}
+	}
+
+	var ret [][]int
+	groupIndex := make(map[int]int)
+	for _, id := range order {
+		root := find(id)
+		i, ok := groupIndex[root]
+		if !ok {
+			i = len(ret)
+			groupIndex[root] = i
+			ret = append(ret, nil)
+		}
+		ret[i] = append(ret[i], id)
+	}
+
+	// drop groups containing a single ID
+	var filtered [][]int
+	for _, g := range ret {
+		if len(g) > 1 {
+			filtered = append(filtered, g)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func randomAudioFingerprint(r *rand.Rand, n int) []uint32 {
+	ret := make([]uint32, n)
+	for i := range ret {
+		ret[i] = r.Uint32()
+	}
+	return ret
+}
+
+// flipBits flips a random bit in every fourth sub-fingerprint.
+func flipBits(r *rand.Rand, fp []uint32) []uint32 {
+	ret := make([]uint32, len(fp))
+	copy(ret, fp)
+	for i := 0; i < len(ret); i += 4 {
+		ret[i] ^= 1 << r.Intn(32)
+	}
+	return ret
+}
+
+func TestAudioFingerprintString(t *testing.T) {
+	fp := []uint32{0, 1, 0xffffffff, 0x12345678}
+
+	got, err := StringToAudioFingerprint(AudioFingerprintToString(fp))
+	if err != nil {
+		t.Fatalf("StringToAudioFingerprint() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, fp) {
+		t.Errorf("StringToAudioFingerprint() = %v, want %v", got, fp)
+	}
+
+	if _, err := StringToAudioFingerprint("AAA"); err == nil {
+		t.Error("StringToAudioFingerprint() expected error for truncated value")
+	}
+}
+
+func TestCompareAudioFingerprints(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	fp := randomAudioFingerprint(r, 500)
+
+	// b has a different 100 frame intro, followed by slightly different audio
+	b := append(randomAudioFingerprint(r, 100), flipBits(r, fp[20:])...)
+
+	similarity, offset := CompareAudioFingerprints(fp, b)
+	if offset != 80 {
+		t.Errorf("CompareAudioFingerprints() offset = %d, want 80", offset)
+	}
+	if similarity < DefaultAudioSimilarity {
+		t.Errorf("CompareAudioFingerprints() similarity = %v, want >= %v", similarity, DefaultAudioSimilarity)
+	}
+
+	similarity, _ = CompareAudioFingerprints(fp, randomAudioFingerprint(r, 500))
+	if similarity >= DefaultAudioSimilarity {
+		t.Errorf("CompareAudioFingerprints() of different audio similarity = %v, want < %v", similarity, DefaultAudioSimilarity)
+	}
+}
+
+func TestFindAudioDuplicates(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	a := randomAudioFingerprint(r, 500)
+	b := randomAudioFingerprint(r, 500)
+	other := randomAudioFingerprint(r, 500)
+
+	fps := []*AudioFingerprint{
+		{SceneID: 1, Fingerprint: a},
+		{SceneID: 2, Fingerprint: other},
+		{SceneID: 3, Fingerprint: append(randomAudioFingerprint(r, 50), flipBits(r, a)...)},
+		{SceneID: 4, Fingerprint: b},
+		{SceneID: 5, Fingerprint: flipBits(r, b[100:])},
+		// other files of the same scene are not duplicates
+		{SceneID: 2, Fingerprint: flipBits(r, other)},
+	}
+
+	got := FindAudioDuplicates(fps, DefaultAudioSimilarity)
+	want := [][]int{{1, 3}, {4, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAudioDuplicates() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	got := MergeDuplicates(
+		[][]int{{1, 2}, {5, 6}},
+		[][]int{{3, 2}, {7, 8}, {6, 9}},
+	)
+	want := [][]int{{1, 2, 3}, {5, 6, 9}, {7, 8}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeDuplicates() = %v, want %v", got, want)
+	}
+}
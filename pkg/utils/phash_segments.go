@@ -0,0 +1,288 @@
+package utils
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// segment hashes are indexed by each 16 bit band of the hash. Hashes
+	// within distance d of each other have at least one band within d/4 of
+	// the same band of the other hash, so each band is probed for all values
+	// within that radius.
+	phashBands    = 4
+	phashBandBits = 16
+	phashBandMask = 1<<phashBandBits - 1
+
+	// MaxSegmentDistance is the largest distance supported by
+	// FindSegmentOverlaps. The number of probes for each band grows quickly
+	// with the distance.
+	MaxSegmentDistance = 10
+
+	// bands shared by more segments than this are ignored. These are
+	// typically black or single-colour frames, which would otherwise match
+	// everything.
+	maxBandSegments = 1000
+
+	// maxSegmentGap is the number of consecutive non-matching segments
+	// allowed within a run, to tolerate the occasional mismatched frame.
+	maxSegmentGap = 1
+)
+
+// PhashSegments is a sequence of perceptual hashes, one for each Interval
+// seconds of a video file.
+type PhashSegments struct {
+	SceneID  int
+	Interval float64
+	Hashes   []int64
+}
+
+// String encodes the segments as the interval followed by the hex encoded
+// hashes, such as "10:a1b2c3d4e5f60718,...".
+func (s PhashSegments) String() string {
+	hashes := make([]string, len(s.Hashes))
+	for i, h := range s.Hashes {
+		hashes[i] = PhashToString(h)
+	}
+
+	return strconv.FormatFloat(s.Interval, 'f', -1, 64) + ":" + strings.Join(hashes, ",")
+}
+
+// ParsePhashSegments parses segments encoded by PhashSegments.String.
+func ParsePhashSegments(v string) (*PhashSegments, error) {
+	intervalStr, hashesStr, found := strings.Cut(v, ":")
+	if !found {
+		return nil, fmt.Errorf("invalid phash segments %q", v)
+	}
+
+	interval, err := strconv.ParseFloat(intervalStr, 64)
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid phash segment interval %q", intervalStr)
+	}
+
+	ret := &PhashSegments{
+		Interval: interval,
+	}
+
+	if hashesStr == "" {
+		return ret, nil
+	}
+
+	for _, h := range strings.Split(hashesStr, ",") {
+		hash, err := StringToPhash(h)
+		if err != nil {
+			return nil, fmt.Errorf("invalid phash segment %q: %w", h, err)
+		}
+		ret.Hashes = append(ret.Hashes, hash)
+	}
+
+	return ret, nil
+}
+
+// SegmentOverlap is a run of footage shared by two files. A and B are
+// indexes into the slice passed to FindSegmentOverlaps.
+type SegmentOverlap struct {
+	A int
+	B int
+	// StartA and StartB are the offsets in seconds of the run in each file.
+	StartA   float64
+	StartB   float64
+	Duration float64
+}
+
+type segmentRef struct {
+	file  int
+	index int
+}
+
+type segmentBand struct {
+	band  int
+	value uint64
+}
+
+type segmentPair struct {
+	a      int
+	b      int
+	offset int
+}
+
+// FindSegmentOverlaps returns the runs of aligned segments shared by files
+// of different scenes. Segments match if their hashes are within distance,
+// which must not exceed MaxSegmentDistance. Only runs at least minDuration
+// seconds long are returned. A is always the file that appears first in
+// segments, so ordering the input by preference orders each overlap the
+// same way. Overlaps are ordered longest first.
+func FindSegmentOverlaps(segments []*PhashSegments, distance int, minDuration float64) []SegmentOverlap {
+	index := make(map[segmentBand][]segmentRef)
+	for fi, s := range segments {
+		for si, h := range s.Hashes {
+			for b := 0; b < phashBands; b++ {
+				key := segmentBand{band: b, value: phashBand(h, b)}
+				index[key] = append(index[key], segmentRef{file: fi, index: si})
+			}
+		}
+	}
+
+	probes := bandProbes(distance / phashBands)
+
+	// indexes of matching segments in file a, for each file pair and offset
+	matches := make(map[segmentPair]map[int]struct{})
+	for fi, sa := range segments {
+		for si, h := range sa.Hashes {
+			for b := 0; b < phashBands; b++ {
+				value := phashBand(h, b)
+				for _, probe := range probes {
+					refs := index[segmentBand{band: b, value: value ^ probe}]
+					if len(refs) > maxBandSegments {
+						continue
+					}
+
+					for _, ref := range refs {
+						// each pair is compared from the first file only
+						if ref.file <= fi {
+							continue
+						}
+
+						sb := segments[ref.file]
+						if sa.SceneID == sb.SceneID || sa.Interval != sb.Interval {
+							continue
+						}
+
+						if bits.OnesCount64(uint64(h^sb.Hashes[ref.index])) > distance {
+							continue
+						}
+
+						p := segmentPair{a: fi, b: ref.file, offset: ref.index - si}
+						if matches[p] == nil {
+							matches[p] = make(map[int]struct{})
+						}
+						matches[p][si] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	// find the runs for each file pair
+	runs := make(map[[2]int][]SegmentOverlap)
+	for p, set := range matches {
+		indexes := make([]int, 0, len(set))
+		for i := range set {
+			indexes = append(indexes, i)
+		}
+		sort.Ints(indexes)
+
+		interval := segments[p.a].Interval
+		addRun := func(first, last int) {
+			duration := float64(last-first+1) * interval
+			if duration < minDuration {
+				return
+			}
+
+			key := [2]int{p.a, p.b}
+			runs[key] = append(runs[key], SegmentOverlap{
+				A:        p.a,
+				B:        p.b,
+				StartA:   float64(first) * interval,
+				StartB:   float64(first+p.offset) * interval,
+				Duration: duration,
+			})
+		}
+
+		first := indexes[0]
+		last := first
+		for _, i := range indexes[1:] {
+			if i-last > maxSegmentGap+1 {
+				addRun(first, last)
+				first = i
+			}
+			last = i
+		}
+		addRun(first, last)
+	}
+
+	var ret []SegmentOverlap
+	for _, r := range runs {
+		ret = append(ret, distinctOverlaps(r)...)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Duration != ret[j].Duration {
+			return ret[i].Duration > ret[j].Duration
+		}
+		if ret[i].A != ret[j].A {
+			return ret[i].A < ret[j].A
+		}
+		if ret[i].B != ret[j].B {
+			return ret[i].B < ret[j].B
+		}
+		return ret[i].StartA < ret[j].StartA
+	})
+
+	return ret
+}
+
+func phashBand(h int64, band int) uint64 {
+	return (uint64(h) >> (band * phashBandBits)) & phashBandMask
+}
+
+// bandProbes returns the masks of all band values within radius bits of a
+// band value, including the value itself.
+func bandProbes(radius int) []uint64 {
+	ret := []uint64{0}
+	// each mask is extended only with bits above its highest set bit, so
+	// that each combination is produced once
+	prev := []uint64{0}
+	for r := 0; r < radius; r++ {
+		var next []uint64
+		for _, m := range prev {
+			low := 0
+			if m != 0 {
+				low = 64 - bits.LeadingZeros64(m)
+			}
+			for i := low; i < phashBandBits; i++ {
+				next = append(next, m|1<<i)
+			}
+		}
+		ret = append(ret, next...)
+		prev = next
+	}
+
+	return ret
+}
+
+// distinctOverlaps removes runs that overlap a longer run between the same
+// files. These are produced by neighbouring offsets when the footage is not
+// aligned to the segment boundaries.
+func distinctOverlaps(runs []SegmentOverlap) []SegmentOverlap {
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].Duration != runs[j].Duration {
+			return runs[i].Duration > runs[j].Duration
+		}
+		return runs[i].StartA < runs[j].StartA
+	})
+
+	var ret []SegmentOverlap
+	for _, r := range runs {
+		overlaps := false
+		for _, o := range ret {
+			if rangesOverlap(r.StartA, o.StartA, r.Duration, o.Duration) || rangesOverlap(r.StartB, o.StartB, r.Duration, o.Duration) {
+				overlaps = true
+				break
+			}
+		}
+
+		if !overlaps {
+			ret = append(ret, r)
+		}
+	}
+
+	return ret
+}
+
+func rangesOverlap(startA, startB, durationA, durationB float64) bool {
+	return startA < startB+durationB && startB < startA+durationA
+}
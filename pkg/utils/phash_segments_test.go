@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"math/bits"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func randomHashes(r *rand.Rand, n int) []int64 {
+	ret := make([]int64, n)
+	for i := range ret {
+		ret[i] = int64(r.Uint64())
+	}
+	return ret
+}
+
+func concatHashes(s ...[]int64) []int64 {
+	var ret []int64
+	for _, v := range s {
+		ret = append(ret, v...)
+	}
+	return ret
+}
+
+func TestFindSegmentOverlaps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const interval = 10
+
+	full := randomHashes(r, 20)
+
+	// contains segments 4-15 of full, after 5 segments of other footage
+	cut := concatHashes(randomHashes(r, 5), full[4:16], randomHashes(r, 3))
+
+	// as cut, but with a bit flipped in one segment and another segment replaced
+	noisy := concatHashes(randomHashes(r, 5), full[4:16], randomHashes(r, 3))
+	noisy[6] ^= 1
+	noisy[10] = int64(r.Uint64())
+
+	// as cut, but with two bits flipped in each band of the shared segments,
+	// so that no band matches exactly
+	const flipMask = 0x0003000300030003
+	distant := concatHashes(randomHashes(r, 5), full[4:16], randomHashes(r, 3))
+	for i := 5; i < 17; i++ {
+		distant[i] ^= flipMask
+	}
+
+	tests := []struct {
+		name        string
+		segments    []*PhashSegments
+		distance    int
+		minDuration float64
+		want        []SegmentOverlap
+	}{
+		{
+			name: "aligned run",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 2, Interval: interval, Hashes: cut},
+			},
+			minDuration: 60,
+			want: []SegmentOverlap{
+				{A: 0, B: 1, StartA: 40, StartB: 50, Duration: 120},
+			},
+		},
+		{
+			name: "run shorter than min duration",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 2, Interval: interval, Hashes: cut},
+			},
+			minDuration: 130,
+			want:        nil,
+		},
+		{
+			name: "tolerates distance and gaps",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 2, Interval: interval, Hashes: noisy},
+			},
+			distance:    1,
+			minDuration: 60,
+			want: []SegmentOverlap{
+				{A: 0, B: 1, StartA: 40, StartB: 50, Duration: 120},
+			},
+		},
+		{
+			name: "distance 8",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 2, Interval: interval, Hashes: distant},
+			},
+			distance:    8,
+			minDuration: 60,
+			want: []SegmentOverlap{
+				{A: 0, B: 1, StartA: 40, StartB: 50, Duration: 120},
+			},
+		},
+		{
+			name: "outside distance",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 2, Interval: interval, Hashes: distant},
+			},
+			distance:    7,
+			minDuration: 60,
+			want:        nil,
+		},
+		{
+			name: "same scene",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 1, Interval: interval, Hashes: cut},
+			},
+			minDuration: 60,
+			want:        nil,
+		},
+		{
+			name: "different intervals",
+			segments: []*PhashSegments{
+				{SceneID: 1, Interval: interval, Hashes: full},
+				{SceneID: 2, Interval: 5, Hashes: cut},
+			},
+			minDuration: 60,
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindSegmentOverlaps(tt.segments, tt.distance, tt.minDuration)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindSegmentOverlaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBandProbes(t *testing.T) {
+	for radius, want := range []int{1, 17, 137} {
+		probes := bandProbes(radius)
+		if len(probes) != want {
+			t.Errorf("bandProbes(%d) returned %d probes, want %d", radius, len(probes), want)
+		}
+
+		seen := make(map[uint64]bool)
+		for _, p := range probes {
+			if seen[p] || bits.OnesCount64(p) > radius {
+				t.Errorf("bandProbes(%d) returned invalid probe %x", radius, p)
+			}
+			seen[p] = true
+		}
+	}
+}
+
+func TestParsePhashSegments(t *testing.T) {
+	s := PhashSegments{
+		Interval: 2.5,
+		Hashes:   []int64{0, 1, -1},
+	}
+
+	encoded := s.String()
+	if encoded != "2.5:0,1,ffffffffffffffff" {
+		t.Errorf("PhashSegments.String() = %q", encoded)
+	}
+
+	got, err := ParsePhashSegments(encoded)
+	if err != nil {
+		t.Fatalf("ParsePhashSegments() error = %v", err)
+	}
+	if !reflect.DeepEqual(*got, s) {
+		t.Errorf("ParsePhashSegments() = %v, want %v", *got, s)
+	}
+
+	for _, v := range []string{"", "10", "x:0", "0:0", "10:xyz"} {
+		if _, err := ParsePhashSegments(v); err == nil {
+			t.Errorf("ParsePhashSegments(%q) expected error", v)
+		}
+	}
+}
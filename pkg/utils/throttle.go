@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// throttleInterval is the granularity at which ThrottledWriter paces writes.
+const throttleInterval = 100 * time.Millisecond
+
+// ThrottledWriter limits the average rate at which data is written to the
+// underlying writer.
+type ThrottledWriter struct {
+	w              io.Writer
+	bytesPerSecond int64
+
+	start   time.Time
+	written int64
+
+	// sleep is overridden in tests
+	sleep func(time.Duration)
+}
+
+// NewThrottledWriter returns a writer that writes to w at no more than
+// bytesPerSecond on average.
+func NewThrottledWriter(w io.Writer, bytesPerSecond int64) *ThrottledWriter {
+	return &ThrottledWriter{
+		w:              w,
+		bytesPerSecond: bytesPerSecond,
+		sleep:          time.Sleep,
+	}
+}
+
+func (t *ThrottledWriter) Write(p []byte) (int, error) {
+	if t.start.IsZero() {
+		t.start = time.Now()
+	}
+
+	// write in chunks so that large buffers don't burst
+	chunkSize := int(t.bytesPerSecond * int64(throttleInterval) / int64(time.Second))
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	total := 0
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+
+		n, err := t.w.Write(chunk)
+		total += n
+		t.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+
+		p = p[n:]
+
+		// sleep until the elapsed time catches up with the written bytes
+		if wait := t.expected() - time.Since(t.start); wait > 0 {
+			t.sleep(wait)
+		}
+	}
+
+	return total, nil
+}
+
+// expected returns the time that writing the bytes written so far should
+// take. The whole seconds are calculated separately from the remainder so
+// that the result does not overflow for long streams.
+func (t *ThrottledWriter) expected() time.Duration {
+	seconds := t.written / t.bytesPerSecond
+	remainder := t.written % t.bytesPerSecond
+	return time.Duration(seconds)*time.Second + time.Duration(remainder*int64(time.Second)/t.bytesPerSecond)
+}
+
+type throttledResponseWriter struct {
+	http.ResponseWriter
+	w *ThrottledWriter
+}
+
+func (t *throttledResponseWriter) Write(p []byte) (int, error) {
+	return t.w.Write(p)
+}
+
+// ThrottleResponseWriter returns a ResponseWriter that writes the response
+// body at no more than bytesPerSecond on average. Returns w unchanged if
+// bytesPerSecond is not positive.
+func ThrottleResponseWriter(w http.ResponseWriter, bytesPerSecond int64) http.ResponseWriter {
+	if bytesPerSecond <= 0 {
+		return w
+	}
+
+	return &throttledResponseWriter{
+		ResponseWriter: w,
+		w:              NewThrottledWriter(w, bytesPerSecond),
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestThrottledWriter(t *testing.T) {
+	const bytesPerSecond = 1000
+
+	var buf bytes.Buffer
+	w := NewThrottledWriter(&buf, bytesPerSecond)
+
+	var slept time.Duration
+	w.sleep = func(d time.Duration) {
+		slept += d
+		// pretend the time has passed
+		w.start = w.start.Add(-d)
+	}
+
+	data := bytes.Repeat([]byte("a"), 2500)
+	n, err := w.Write(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != len(data) {
+		t.Errorf("wrote %d bytes, expected %d", n, len(data))
+	}
+
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("written data does not match input")
+	}
+
+	// 2500 bytes at 1000 bytes per second should take 2.5 seconds
+	const want = 2500 * time.Millisecond
+	const tolerance = 50 * time.Millisecond
+	if slept < want-tolerance || slept > want {
+		t.Errorf("slept for %v, expected approximately %v", slept, want)
+	}
+}
+
+func TestThrottledWriter_expected(t *testing.T) {
+	const bytesPerSecond = 1000000
+
+	w := NewThrottledWriter(nil, bytesPerSecond)
+
+	// multiplying by time.Second would overflow beyond ~9.2GB
+	w.written = 20*1000*1000*1000 + 500000
+	const want = 20000*time.Second + 500*time.Millisecond
+	if got := w.expected(); got != want {
+		t.Errorf("expected() = %v, want %v", got, want)
+	}
+}
+
+func TestThrottleResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	if got := ThrottleResponseWriter(rec, 0); got != rec {
+		t.Errorf("expected unthrottled writer to be returned unchanged")
+	}
+
+	got := ThrottleResponseWriter(rec, 1000)
+	if got == rec {
+		t.Errorf("expected throttled writer")
+	}
+
+	got.Header().Set("Content-Type", "text/plain")
+	if rec.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("expected headers to be passed through")
+	}
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "regexp"
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
 
 // URLFromHandle adds the site URL to the input if the input is not already a URL
 // siteURL must not end with a slash
@@ -13,3 +17,43 @@ func URLFromHandle(input string, siteURL string) string {
 
 	return siteURL + "/" + input
 }
+
+// SplitURLPassword returns the URL without its password, and the password.
+// Inputs that are not URLs with a password are returned unchanged with an
+// empty password.
+func SplitURLPassword(input string) (string, string) {
+	u, err := url.Parse(input)
+	if err != nil || u.User == nil {
+		return input, ""
+	}
+
+	password, ok := u.User.Password()
+	if !ok {
+		return input, ""
+	}
+
+	u.User = url.User(u.User.Username())
+	return u.String(), password
+}
+
+// URLWithPassword returns the URL with its password set to password. Inputs
+// that are not URLs with an authority, such as file paths, are returned
+// unchanged.
+func URLWithPassword(input string, password string) string {
+	if password == "" || !strings.Contains(input, "://") {
+		return input
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return input
+	}
+
+	username := ""
+	if u.User != nil {
+		username = u.User.Username()
+	}
+
+	u.User = url.UserPassword(username, password)
+	return u.String()
+}
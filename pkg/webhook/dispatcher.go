@@ -0,0 +1,335 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/hook"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 5 * time.Second
+	defaultTimeout     = 30 * time.Second
+
+	// maxDeadLetters is the number of failed deliveries that are kept. The
+	// oldest are discarded first.
+	maxDeadLetters = 100
+
+	defaultWorkers   = 2
+	defaultQueueSize = 100
+)
+
+// errQueueFull is the error of deliveries that are discarded because too
+// many deliveries to the webhook are pending.
+var errQueueFull = errors.New("delivery queue is full")
+
+type delivery struct {
+	webhook *Webhook
+	event   Event
+}
+
+// DeadLetter is a delivery that failed after all attempts.
+type DeadLetter struct {
+	Webhook  string    `json:"webhook"`
+	URL      string    `json:"url"`
+	Trigger  string    `json:"trigger"`
+	ID       int       `json:"id"`
+	Payload  string    `json:"payload"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// Dispatcher sends hook trigger events to the subscribed webhooks. Deliveries
+// are made in the background, retrying failed requests with exponential
+// backoff. Each webhook has a queue of pending deliveries, which are made by a
+// fixed number of workers. Events are added to the dead letters if the queue
+// is full. Refresh should be called when the webhooks are changed, to stop
+// the workers of removed webhooks.
+type Dispatcher struct {
+	// Webhooks returns the current webhooks. It is called for each event so
+	// that configuration changes apply immediately.
+	Webhooks func() []*Webhook
+	Client   *http.Client
+	// MaxAttempts is the number of times a delivery is attempted before it is
+	// added to the dead letters.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It is doubled for each
+	// subsequent retry.
+	Backoff time.Duration
+	// Workers is the number of deliveries to each webhook that are made at
+	// the same time.
+	Workers int
+	// QueueSize is the number of deliveries to each webhook that may be
+	// waiting for a worker.
+	QueueSize int
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+
+	queuesMutex sync.Mutex
+	queues      map[string]chan delivery
+
+	deadLettersMutex sync.Mutex
+	deadLetters      []*DeadLetter
+}
+
+func NewDispatcher(webhooks func() []*Webhook) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		Webhooks:    webhooks,
+		Client:      &http.Client{Timeout: defaultTimeout},
+		MaxAttempts: defaultMaxAttempts,
+		Backoff:     defaultBackoff,
+		Workers:     defaultWorkers,
+		QueueSize:   defaultQueueSize,
+		ctx:         ctx,
+		cancel:      cancel,
+		queues:      make(map[string]chan delivery),
+	}
+}
+
+// PostHook is a plugin.PostHookListener that sends the event to the webhooks
+// subscribed to the hook trigger.
+func (d *Dispatcher) PostHook(ctx context.Context, id int, hookType hook.TriggerEnum, inputFields []string) {
+	fields := make([]string, len(inputFields))
+	copy(fields, inputFields)
+
+	e := Event{
+		ID:          id,
+		Trigger:     hookType,
+		InputFields: fields,
+		Timestamp:   time.Now(),
+	}
+
+	for _, w := range d.Webhooks() {
+		if !w.subscribed(hookType) {
+			continue
+		}
+
+		d.enqueue(w, e)
+	}
+}
+
+// queueKey returns the key of the webhook's queue. A webhook whose URL
+// changes gets a new queue.
+func queueKey(w *Webhook) string {
+	return w.Name + "\x00" + w.URL
+}
+
+// enqueue adds the event to the queue of the webhook, starting its workers if
+// the queue is new. The event is added to the dead letters if the queue is
+// full.
+func (d *Dispatcher) enqueue(w *Webhook, e Event) {
+	d.queuesMutex.Lock()
+	defer d.queuesMutex.Unlock()
+
+	if d.ctx.Err() != nil {
+		// closed
+		return
+	}
+
+	key := queueKey(w)
+	q, ok := d.queues[key]
+	if !ok {
+		q = make(chan delivery, d.QueueSize)
+		d.queues[key] = q
+
+		for i := 0; i < d.Workers; i++ {
+			d.workers.Add(1)
+			go d.work(q)
+		}
+	}
+
+	select {
+	case q <- delivery{webhook: w, event: e}:
+	default:
+		d.addDeadLetter(w, e, nil, 0, errQueueFull)
+	}
+}
+
+// Refresh removes the queues of webhooks that have been removed, disabled or
+// had their URL changed. The workers of a removed queue stop once its pending
+// deliveries are made.
+func (d *Dispatcher) Refresh() {
+	d.removeStaleQueues(d.Webhooks())
+}
+
+func (d *Dispatcher) removeStaleQueues(webhooks []*Webhook) {
+	current := make(map[string]bool)
+	for _, w := range webhooks {
+		if w.Enabled {
+			current[queueKey(w)] = true
+		}
+	}
+
+	d.queuesMutex.Lock()
+	defer d.queuesMutex.Unlock()
+
+	for key, q := range d.queues {
+		if !current[key] {
+			close(q)
+			delete(d.queues, key)
+		}
+	}
+}
+
+func (d *Dispatcher) work(q chan delivery) {
+	defer d.workers.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case dl, ok := <-q:
+			if !ok {
+				return
+			}
+
+			d.deliver(dl.webhook, dl.event)
+		}
+	}
+}
+
+// Close cancels pending deliveries and waits for the workers to finish.
+func (d *Dispatcher) Close() {
+	d.queuesMutex.Lock()
+	d.cancel()
+	d.queuesMutex.Unlock()
+
+	d.workers.Wait()
+}
+
+func (d *Dispatcher) deliver(w *Webhook, e Event) {
+	body, err := w.payload(e)
+	if err != nil {
+		d.addDeadLetter(w, e, nil, 0, fmt.Errorf("generating payload: %w", err))
+		return
+	}
+
+	backoff := d.Backoff
+	attempt := 0
+	for {
+		attempt++
+
+		var retry bool
+		retry, err = d.send(w, e, body)
+		if err == nil {
+			return
+		}
+
+		if !retry || attempt >= d.MaxAttempts {
+			break
+		}
+
+		logger.Debugf("webhook %s: attempt %d failed: %v - retrying in %s", w.Name, attempt, err, backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-d.ctx.Done():
+			d.addDeadLetter(w, e, body, attempt, fmt.Errorf("%w (cancelled before retrying)", err))
+			return
+		}
+
+		backoff *= 2
+	}
+
+	d.addDeadLetter(w, e, body, attempt, err)
+}
+
+// send makes a single request to the webhook. It returns whether a failed
+// request should be retried.
+func (d *Dispatcher) send(w *Webhook, e Event, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, e.Trigger.String())
+	for _, h := range w.Headers {
+		req.Header.Set(h.Name, h.Value)
+	}
+
+	if w.Secret != "" {
+		req.Header.Set(SignatureHeader, sign(w.Secret, body))
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return d.ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("unexpected response status: %s", resp.Status)
+
+	// client errors other than timeouts and rate limiting won't succeed on
+	// retry
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return true, err
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return false, err
+	}
+
+	return true, err
+}
+
+func (d *Dispatcher) addDeadLetter(w *Webhook, e Event, body []byte, attempts int, err error) {
+	logger.Warnf("webhook %s: delivery of %s for ID %d failed after %d attempt(s): %v", w.Name, e.Trigger, e.ID, attempts, err)
+
+	d.deadLettersMutex.Lock()
+	defer d.deadLettersMutex.Unlock()
+
+	d.deadLetters = append(d.deadLetters, &DeadLetter{
+		Webhook:  w.Name,
+		URL:      w.URL,
+		Trigger:  e.Trigger.String(),
+		ID:       e.ID,
+		Payload:  string(body),
+		Attempts: attempts,
+		Error:    err.Error(),
+		FailedAt: time.Now(),
+	})
+
+	if len(d.deadLetters) > maxDeadLetters {
+		d.deadLetters = d.deadLetters[len(d.deadLetters)-maxDeadLetters:]
+	}
+}
+
+// DeadLetters returns the failed deliveries, most recent first.
+func (d *Dispatcher) DeadLetters() []*DeadLetter {
+	d.deadLettersMutex.Lock()
+	defer d.deadLettersMutex.Unlock()
+
+	ret := make([]*DeadLetter, len(d.deadLetters))
+	for i, l := range d.deadLetters {
+		ret[len(ret)-1-i] = l
+	}
+
+	return ret
+}
+
+// ClearDeadLetters removes all failed deliveries.
+func (d *Dispatcher) ClearDeadLetters() {
+	d.deadLettersMutex.Lock()
+	defer d.deadLettersMutex.Unlock()
+
+	d.deadLetters = nil
+}
@@ -0,0 +1,313 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stretchr/testify/assert"
+)
+
+type request struct {
+	header http.Header
+	body   string
+}
+
+// testServer records the requests it receives and responds with the given
+// statuses in turn, repeating the last.
+type testServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	statuses []int
+	requests []request
+}
+
+func newTestServer(statuses ...int) *testServer {
+	s := &testServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		status := s.statuses[len(s.statuses)-1]
+		if len(s.requests) < len(s.statuses) {
+			status = s.statuses[len(s.requests)]
+		}
+		s.requests = append(s.requests, request{header: r.Header, body: string(body)})
+
+		w.WriteHeader(status)
+	}))
+
+	return s
+}
+
+func (s *testServer) getRequests() []request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func newTestDispatcher(webhooks ...*Webhook) *Dispatcher {
+	d := NewDispatcher(func() []*Webhook { return webhooks })
+	d.Backoff = time.Millisecond
+	d.MaxAttempts = 3
+	return d
+}
+
+// wait waits for the queued deliveries to be made by removing all queues.
+func wait(d *Dispatcher) {
+	d.removeStaleQueues(nil)
+	d.workers.Wait()
+}
+
+func TestDispatcher_PostHook(t *testing.T) {
+	s := newTestServer(http.StatusOK)
+	defer s.Close()
+
+	d := newTestDispatcher(&Webhook{
+		Name:     "test",
+		URL:      s.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+		Secret:   "secret",
+		Headers:  []*Header{{Name: "Authorization", Value: "Bearer token"}},
+		Enabled:  true,
+	})
+
+	d.PostHook(ctx, 1, hook.SceneUpdatePost, []string{"title"})
+	// not subscribed
+	d.PostHook(ctx, 2, hook.SceneCreatePost, nil)
+	wait(d)
+
+	requests := s.getRequests()
+	if !assert.Len(t, requests, 1) {
+		return
+	}
+
+	r := requests[0]
+	assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", r.header.Get("Authorization"))
+	assert.Equal(t, hook.SceneUpdatePost.String(), r.header.Get(EventHeader))
+	assert.Equal(t, sign("secret", []byte(r.body)), r.header.Get(SignatureHeader))
+
+	var e Event
+	if assert.NoError(t, json.Unmarshal([]byte(r.body), &e)) {
+		assert.Equal(t, 1, e.ID)
+		assert.Equal(t, hook.SceneUpdatePost, e.Trigger)
+		assert.Equal(t, []string{"title"}, e.InputFields)
+	}
+
+	assert.Empty(t, d.DeadLetters())
+}
+
+func TestDispatcher_disabled(t *testing.T) {
+	s := newTestServer(http.StatusOK)
+	defer s.Close()
+
+	d := newTestDispatcher(&Webhook{
+		URL:      s.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+	})
+
+	d.PostHook(ctx, 1, hook.SceneUpdatePost, nil)
+	wait(d)
+
+	assert.Empty(t, s.getRequests())
+}
+
+func TestDispatcher_payloadTemplate(t *testing.T) {
+	s := newTestServer(http.StatusOK)
+	defer s.Close()
+
+	d := newTestDispatcher(&Webhook{
+		URL:             s.URL,
+		Triggers:        []string{hook.PerformerCreatePost.String()},
+		PayloadTemplate: `{"text": {{ printf "%s %d" .Trigger .ID | json }}}`,
+		Enabled:         true,
+	})
+
+	d.PostHook(ctx, 5, hook.PerformerCreatePost, nil)
+	wait(d)
+
+	requests := s.getRequests()
+	if assert.Len(t, requests, 1) {
+		assert.Equal(t, `{"text": "Performer.Create.Post 5"}`, requests[0].body)
+		// unsigned without a secret
+		assert.Empty(t, requests[0].header.Get(SignatureHeader))
+	}
+}
+
+func TestDispatcher_retry(t *testing.T) {
+	s := newTestServer(http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK)
+	defer s.Close()
+
+	d := newTestDispatcher(&Webhook{
+		URL:      s.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+		Enabled:  true,
+	})
+
+	d.PostHook(ctx, 1, hook.SceneUpdatePost, nil)
+	wait(d)
+
+	assert.Len(t, s.getRequests(), 3)
+	assert.Empty(t, d.DeadLetters())
+}
+
+func TestDispatcher_deadLetter(t *testing.T) {
+	s := newTestServer(http.StatusServiceUnavailable)
+	defer s.Close()
+
+	w := &Webhook{
+		Name:     "failing",
+		URL:      s.URL,
+		Triggers: []string{hook.TagDestroyPost.String()},
+		Enabled:  true,
+	}
+	d := newTestDispatcher(w)
+
+	d.PostHook(ctx, 1, hook.TagDestroyPost, nil)
+	wait(d)
+	d.PostHook(ctx, 2, hook.TagDestroyPost, nil)
+	wait(d)
+
+	assert.Len(t, s.getRequests(), 2*d.MaxAttempts)
+
+	letters := d.DeadLetters()
+	if assert.Len(t, letters, 2) {
+		// most recent first
+		l := letters[0]
+		assert.Equal(t, "failing", l.Webhook)
+		assert.Equal(t, s.URL, l.URL)
+		assert.Equal(t, hook.TagDestroyPost.String(), l.Trigger)
+		assert.Equal(t, 2, l.ID)
+		assert.Equal(t, d.MaxAttempts, l.Attempts)
+		assert.Contains(t, l.Error, "503")
+		assert.NotEmpty(t, l.Payload)
+
+		assert.Equal(t, 1, letters[1].ID)
+	}
+
+	d.ClearDeadLetters()
+	assert.Empty(t, d.DeadLetters())
+}
+
+func TestDispatcher_clientError(t *testing.T) {
+	s := newTestServer(http.StatusNotFound)
+	defer s.Close()
+
+	d := newTestDispatcher(&Webhook{
+		URL:      s.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+		Enabled:  true,
+	})
+
+	d.PostHook(ctx, 1, hook.SceneUpdatePost, nil)
+	wait(d)
+
+	// client errors are not retried
+	assert.Len(t, s.getRequests(), 1)
+	if letters := d.DeadLetters(); assert.Len(t, letters, 1) {
+		assert.Equal(t, 1, letters[0].Attempts)
+	}
+}
+
+func TestDispatcher_deadLetterLimit(t *testing.T) {
+	d := newTestDispatcher()
+	w := &Webhook{Name: "test"}
+
+	for i := 0; i < maxDeadLetters+10; i++ {
+		d.addDeadLetter(w, Event{ID: i}, nil, 1, assert.AnError)
+	}
+
+	letters := d.DeadLetters()
+	assert.Len(t, letters, maxDeadLetters)
+	assert.Equal(t, maxDeadLetters+9, letters[0].ID)
+	assert.Equal(t, 10, letters[len(letters)-1].ID)
+}
+
+func TestDispatcher_Refresh(t *testing.T) {
+	s1 := newTestServer(http.StatusOK)
+	defer s1.Close()
+	s2 := newTestServer(http.StatusOK)
+	defer s2.Close()
+
+	webhooks := []*Webhook{{
+		Name:     "test",
+		URL:      s1.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+		Enabled:  true,
+	}}
+	d := newTestDispatcher(webhooks...)
+
+	d.PostHook(ctx, 1, hook.SceneUpdatePost, nil)
+
+	// unchanged webhooks keep their queue
+	d.Refresh()
+	assert.Len(t, d.queues, 1)
+
+	webhooks[0] = &Webhook{
+		Name:     "test",
+		URL:      s2.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+		Enabled:  true,
+	}
+
+	// the workers of the old queue stop once the pending delivery is made
+	d.Refresh()
+	assert.Empty(t, d.queues)
+	d.workers.Wait()
+	assert.Len(t, s1.getRequests(), 1)
+
+	d.PostHook(ctx, 2, hook.SceneUpdatePost, nil)
+	wait(d)
+
+	assert.Len(t, s1.getRequests(), 1)
+	assert.Len(t, s2.getRequests(), 1)
+}
+
+func TestDispatcher_queueFull(t *testing.T) {
+	received := make(chan struct{}, 2)
+	release := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer s.Close()
+
+	d := newTestDispatcher(&Webhook{
+		URL:      s.URL,
+		Triggers: []string{hook.SceneUpdatePost.String()},
+		Enabled:  true,
+	})
+	d.Workers = 1
+	d.QueueSize = 1
+
+	// the first event is being delivered, the second is queued and the third
+	// overflows the queue
+	d.PostHook(ctx, 1, hook.SceneUpdatePost, nil)
+	<-received
+	d.PostHook(ctx, 2, hook.SceneUpdatePost, nil)
+	d.PostHook(ctx, 3, hook.SceneUpdatePost, nil)
+
+	letters := d.DeadLetters()
+	if assert.Len(t, letters, 1) {
+		assert.Equal(t, 3, letters[0].ID)
+		assert.Equal(t, 0, letters[0].Attempts)
+		assert.Equal(t, errQueueFull.Error(), letters[0].Error)
+	}
+
+	close(release)
+	wait(d)
+
+	assert.Len(t, received, 1)
+	assert.Len(t, d.DeadLetters(), 1)
+
+	d.Close()
+}
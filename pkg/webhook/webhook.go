@@ -0,0 +1,243 @@
+// Package webhook delivers hook trigger events to external HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/stashapp/stash/pkg/plugin/hook"
+)
+
+// SignatureHeader is the request header containing the HMAC-SHA256 signature
+// of the request body, if the webhook has a secret.
+const SignatureHeader = "X-Stash-Signature"
+
+// EventHeader is the request header containing the hook trigger of the event.
+const EventHeader = "X-Stash-Event"
+
+// Header is a custom header sent with each webhook request.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Webhook is an endpoint that is sent the events of the subscribed hook
+// triggers.
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Triggers are the hook.TriggerEnum values that the webhook is sent.
+	Triggers []string `json:"triggers"`
+	// Secret is used to sign the request body. Requests are not signed if it
+	// is empty.
+	Secret  string    `json:"secret"`
+	Headers []*Header `json:"headers"`
+	// PayloadTemplate is a text/template executed with the Event to produce
+	// the request body. The Event is sent as JSON if it is empty.
+	PayloadTemplate string `json:"payloadTemplate"`
+	Enabled         bool   `json:"enabled"`
+}
+
+// HasSecret returns true if the webhook has a secret. The secret itself is
+// not returned by the configuration query.
+func (w Webhook) HasSecret() bool {
+	return w.Secret != ""
+}
+
+// HeaderInput is a custom header of a WebhookInput.
+type HeaderInput struct {
+	Name string `json:"name"`
+	// Value is nil to keep the existing value of the header
+	Value *string `json:"value"`
+}
+
+// WebhookInput is the configuration of a webhook. The secret and header
+// values are write-only, so nil values keep those of the existing webhook.
+type WebhookInput struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Triggers []string `json:"triggers"`
+	// Secret is nil to keep the existing secret
+	Secret          *string        `json:"secret"`
+	Headers         []*HeaderInput `json:"headers"`
+	PayloadTemplate string         `json:"payloadTemplate"`
+	Enabled         bool           `json:"enabled"`
+}
+
+// Webhook returns the webhook of the input. The secret and header values
+// are kept from the existing webhook if they are not set in the input. The
+// existing values are only kept if the URL is unchanged, so that they are
+// not sent to a different endpoint.
+func (i WebhookInput) Webhook(existing *Webhook) *Webhook {
+	if existing != nil && existing.URL != i.URL {
+		existing = nil
+	}
+
+	ret := &Webhook{
+		Name:            i.Name,
+		URL:             i.URL,
+		Triggers:        i.Triggers,
+		PayloadTemplate: i.PayloadTemplate,
+		Enabled:         i.Enabled,
+	}
+
+	switch {
+	case i.Secret != nil:
+		ret.Secret = *i.Secret
+	case existing != nil:
+		ret.Secret = existing.Secret
+	}
+
+	for _, h := range i.Headers {
+		header := &Header{Name: h.Name}
+		switch {
+		case h.Value != nil:
+			header.Value = *h.Value
+		case existing != nil:
+			header.Value = existing.header(h.Name)
+		}
+
+		ret.Headers = append(ret.Headers, header)
+	}
+
+	return ret
+}
+
+func (w *Webhook) header(name string) string {
+	for _, h := range w.Headers {
+		if h.Name == name {
+			return h.Value
+		}
+	}
+
+	return ""
+}
+
+// NewWebhooks returns the webhooks of the inputs. Each input keeps the secret
+// and header values of the existing webhook with the same name.
+func NewWebhooks(inputs []*WebhookInput, existing []*Webhook) []*Webhook {
+	ret := make([]*Webhook, len(inputs))
+	for i, input := range inputs {
+		var existingWebhook *Webhook
+		for _, e := range existing {
+			if e.Name == input.Name {
+				existingWebhook = e
+				break
+			}
+		}
+
+		ret[i] = input.Webhook(existingWebhook)
+	}
+
+	return ret
+}
+
+// Event is the hook trigger event sent to webhooks.
+type Event struct {
+	ID          int              `json:"id"`
+	Trigger     hook.TriggerEnum `json:"trigger"`
+	InputFields []string         `json:"input_fields"`
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func (w *Webhook) parseTemplate() (*template.Template, error) {
+	return template.New(w.Name).Funcs(templateFuncs).Parse(w.PayloadTemplate)
+}
+
+// Validate returns an error if the webhook is not valid.
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+
+	if len(w.Triggers) == 0 {
+		return errors.New("at least one trigger is required")
+	}
+
+	for _, t := range w.Triggers {
+		if !hook.TriggerEnum(t).IsValid() {
+			return fmt.Errorf("invalid trigger %q", t)
+		}
+	}
+
+	for _, h := range w.Headers {
+		if h.Name == "" {
+			return errors.New("header name cannot be blank")
+		}
+	}
+
+	if w.PayloadTemplate != "" {
+		if _, err := w.parseTemplate(); err != nil {
+			return fmt.Errorf("invalid payload template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateWebhooks returns an error if any of the webhooks are not valid.
+func ValidateWebhooks(webhooks []*Webhook) error {
+	for i, w := range webhooks {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("webhook %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Webhook) subscribed(trigger hook.TriggerEnum) bool {
+	if !w.Enabled {
+		return false
+	}
+
+	for _, t := range w.Triggers {
+		if hook.TriggerEnum(t) == trigger {
+			return true
+		}
+	}
+
+	return false
+}
+
+// payload returns the request body for the event.
+func (w *Webhook) payload(e Event) ([]byte, error) {
+	if w.PayloadTemplate == "" {
+		return json.Marshal(e)
+	}
+
+	t, err := w.parseTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, e); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sign returns the value of the signature header for the body.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
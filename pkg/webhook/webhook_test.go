@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/plugin/hook"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func TestWebhook_Validate(t *testing.T) {
+	valid := func() *Webhook {
+		return &Webhook{
+			Name:     "test",
+			URL:      "https://example.com/hook",
+			Triggers: []string{hook.SceneUpdatePost.String(), hook.GroupCreatePost.String()},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(w *Webhook)
+		wantErr bool
+	}{
+		{"valid", func(w *Webhook) {}, false},
+		{"template", func(w *Webhook) { w.PayloadTemplate = `{"id": {{ .ID }}}` }, false},
+		{"relative url", func(w *Webhook) { w.URL = "/hook" }, true},
+		{"invalid scheme", func(w *Webhook) { w.URL = "ftp://example.com" }, true},
+		{"no triggers", func(w *Webhook) { w.Triggers = nil }, true},
+		{"invalid trigger", func(w *Webhook) { w.Triggers = []string{"Scene.Update"} }, true},
+		{"blank header", func(w *Webhook) { w.Headers = []*Header{{Value: "v"}} }, true},
+		{"invalid template", func(w *Webhook) { w.PayloadTemplate = "{{ .ID" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid()
+			tt.modify(w)
+			if err := w.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Webhook.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 of "body" with key "secret"
+	const want = "sha256=dc46983557fea127b43af721467eb9b3fde2338fe3e14f51952aa8478c13d355"
+	assert.Equal(t, want, sign("secret", []byte("body")))
+}
+
+func TestNewWebhooks(t *testing.T) {
+	existing := []*Webhook{
+		{
+			Name:    "test",
+			URL:     "https://example.com/hook",
+			Secret:  "secret",
+			Headers: []*Header{{Name: "Authorization", Value: "Bearer token"}},
+		},
+	}
+
+	newSecret := "new secret"
+	value := "value"
+
+	tests := []struct {
+		name  string
+		input *WebhookInput
+		want  *Webhook
+	}{
+		{
+			"keep",
+			&WebhookInput{
+				Name:    "test",
+				URL:     "https://example.com/hook",
+				Headers: []*HeaderInput{{Name: "Authorization"}, {Name: "X-New"}},
+			},
+			&Webhook{
+				Name:    "test",
+				URL:     "https://example.com/hook",
+				Secret:  "secret",
+				Headers: []*Header{{Name: "Authorization", Value: "Bearer token"}, {Name: "X-New"}},
+			},
+		},
+		{
+			"set",
+			&WebhookInput{
+				Name:    "test",
+				URL:     "https://example.com/hook",
+				Secret:  &newSecret,
+				Headers: []*HeaderInput{{Name: "Authorization", Value: &value}},
+			},
+			&Webhook{
+				Name:    "test",
+				URL:     "https://example.com/hook",
+				Secret:  newSecret,
+				Headers: []*Header{{Name: "Authorization", Value: value}},
+			},
+		},
+		{
+			"changed url",
+			&WebhookInput{
+				Name:    "test",
+				URL:     "https://example.org/hook",
+				Headers: []*HeaderInput{{Name: "Authorization"}},
+			},
+			&Webhook{
+				Name:    "test",
+				URL:     "https://example.org/hook",
+				Headers: []*Header{{Name: "Authorization"}},
+			},
+		},
+		{
+			"new",
+			&WebhookInput{
+				Name: "other",
+				URL:  "https://example.com/hook",
+			},
+			&Webhook{
+				Name: "other",
+				URL:  "https://example.com/hook",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewWebhooks([]*WebhookInput{tt.input}, existing)
+			assert.Equal(t, []*Webhook{tt.want}, got)
+		})
+	}
+}
@@ -78,6 +78,7 @@ const SceneFilenameParser = lazyComponent(
 const SceneDuplicateChecker = lazyComponent(
   () => import("./components/SceneDuplicateChecker/SceneDuplicateChecker")
 );
+const Trash = lazyComponent(() => import("./components/Trash/Trash"));
 
 const appleRendering = isPlatformUniquelyRenderedByApple();
 
@@ -323,6 +324,7 @@ export const App: React.FC = () => {
               path="/sceneDuplicateChecker"
               component={SceneDuplicateChecker}
             />
+            <Route path="/trash" component={Trash} />
             <Route path="/setup" component={Setup} />
             <Route path="/migrate" component={Migrate} />
             <PluginRoutes />
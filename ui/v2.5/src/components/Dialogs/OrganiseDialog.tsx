@@ -0,0 +1,173 @@
+import React, { useState } from "react";
+import { Button, Form, Table } from "react-bootstrap";
+import { FormattedMessage, useIntl } from "react-intl";
+import * as GQL from "src/core/generated-graphql";
+import { ModalComponent } from "../Shared/Modal";
+import { LoadingIndicator } from "../Shared/LoadingIndicator";
+import { useToast } from "src/hooks/Toast";
+import { faFolderTree } from "@fortawesome/free-solid-svg-icons";
+
+export const defaultOrganiseTemplate =
+  "{studio.parent}/{studio}/{date:2006}/{title} [{performers|join:, }].{ext}";
+
+interface IOrganiseDialog {
+  selectedIds?: string[];
+  sceneFilter?: GQL.SceneFilterType;
+  template?: string;
+  destination?: string;
+  onClose: () => void;
+}
+
+export const OrganiseDialog: React.FC<IOrganiseDialog> = ({
+  selectedIds,
+  sceneFilter,
+  template: initialTemplate,
+  destination: initialDestination,
+  onClose,
+}) => {
+  const intl = useIntl();
+  const Toast = useToast();
+
+  const [template, setTemplate] = useState(
+    initialTemplate ?? defaultOrganiseTemplate
+  );
+  const [destination, setDestination] = useState(initialDestination ?? "");
+  const [isRunning, setIsRunning] = useState(false);
+
+  const [organiseScenes] = GQL.useOrganiseScenesMutation();
+  const [plan, { data, loading, error }] = GQL.useOrganisePlanLazyQuery({
+    fetchPolicy: "network-only",
+  });
+
+  function makeInput(): GQL.OrganiseScenesInput {
+    return {
+      ids: selectedIds,
+      scene_filter: sceneFilter,
+      template,
+      destination: destination || undefined,
+    };
+  }
+
+  function onPreview() {
+    plan({ variables: { input: makeInput() } });
+  }
+
+  async function onOrganise() {
+    try {
+      setIsRunning(true);
+      await organiseScenes({ variables: { input: makeInput() } });
+      Toast.success(
+        intl.formatMessage(
+          { id: "config.tasks.added_job_to_queue" },
+          { operation_name: intl.formatMessage({ id: "actions.organise" }) }
+        )
+      );
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setIsRunning(false);
+      onClose();
+    }
+  }
+
+  function renderPlan() {
+    if (loading) {
+      return <LoadingIndicator inline small />;
+    }
+
+    if (error) {
+      return <div className="text-danger">{error.message}</div>;
+    }
+
+    const moves = data?.organisePlan;
+    if (!moves) {
+      return;
+    }
+
+    if (moves.length === 0) {
+      return <FormattedMessage id="dialogs.organise.no_moves" />;
+    }
+
+    return (
+      <Table responsive striped className="organise-plan-table">
+        <thead>
+          <tr>
+            <th>
+              <FormattedMessage id="dialogs.organise.old_path" />
+            </th>
+            <th>
+              <FormattedMessage id="dialogs.organise.new_path" />
+            </th>
+          </tr>
+        </thead>
+        <tbody>
+          {moves.map((m) => (
+            <tr key={m.file_id}>
+              <td>{m.old_path}</td>
+              <td>{m.new_path}</td>
+            </tr>
+          ))}
+        </tbody>
+      </Table>
+    );
+  }
+
+  return (
+    <ModalComponent
+      show
+      modalProps={{ size: "xl" }}
+      icon={faFolderTree}
+      header={intl.formatMessage({ id: "actions.organise" })}
+      accept={{
+        onClick: onOrganise,
+        text: intl.formatMessage({ id: "actions.organise" }),
+      }}
+      cancel={{
+        onClick: () => onClose(),
+        text: intl.formatMessage({ id: "actions.cancel" }),
+        variant: "secondary",
+      }}
+      disabled={!template}
+      isRunning={isRunning}
+    >
+      <Form>
+        <Form.Group id="organise-template">
+          <h6>
+            <FormattedMessage id="dialogs.organise.template" />
+          </h6>
+          <Form.Control
+            className="text-input"
+            value={template}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setTemplate(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            <FormattedMessage id="dialogs.organise.template_desc" />
+          </Form.Text>
+        </Form.Group>
+        <Form.Group id="organise-destination">
+          <h6>
+            <FormattedMessage id="dialogs.organise.destination" />
+          </h6>
+          <Form.Control
+            className="text-input"
+            value={destination}
+            onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+              setDestination(e.currentTarget.value)
+            }
+          />
+          <Form.Text className="text-muted">
+            <FormattedMessage id="dialogs.organise.destination_desc" />
+          </Form.Text>
+        </Form.Group>
+        <Form.Group>
+          <Button disabled={!template || loading} onClick={onPreview}>
+            <FormattedMessage id="actions.preview" />
+          </Button>
+        </Form.Group>
+        {renderPlan()}
+      </Form>
+    </ModalComponent>
+  );
+};
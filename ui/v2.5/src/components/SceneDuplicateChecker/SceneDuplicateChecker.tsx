@@ -59,6 +59,7 @@ export const SceneDuplicateChecker: React.FC = () => {
   const durationDiff = Number.parseFloat(
     query.get("durationDiff") ?? defaultDurationDiff
   );
+  const matchAudio = query.get("audio") === "true";
 
   const [currentPageSize, setCurrentPageSize] = useState(pageSize);
   const [isMultiDelete, setIsMultiDelete] = useState(false);
@@ -75,6 +76,7 @@ export const SceneDuplicateChecker: React.FC = () => {
     variables: {
       distance: hashDistance,
       duration_diff: durationDiff,
+      audio: matchAudio,
     },
   });
 
@@ -750,6 +752,22 @@ export const SceneDuplicateChecker: React.FC = () => {
               </Col>
             </Row>
           </Form.Group>
+          <Form.Group>
+            <Form.Check
+              id="match-audio"
+              checked={matchAudio}
+              label={intl.formatMessage({ id: "dupe_check.match_audio" })}
+              onChange={(e) =>
+                setQuery({
+                  audio: e.currentTarget.checked ? "true" : undefined,
+                  page: undefined,
+                })
+              }
+            />
+            <Form.Text>
+              <FormattedMessage id="dupe_check.match_audio_description" />
+            </Form.Text>
+          </Form.Group>
           <Form.Group>
             <Row noGutters>
               <Col xs="12">
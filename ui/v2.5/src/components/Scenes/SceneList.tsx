@@ -19,6 +19,7 @@ import { ExportDialog } from "../Shared/ExportDialog";
 import { SceneCardsGrid } from "./SceneCardsGrid";
 import { TaggerContext } from "../Tagger/context";
 import { IdentifyDialog } from "../Dialogs/IdentifyDialog/IdentifyDialog";
+import { OrganiseDialog } from "../Dialogs/OrganiseDialog";
 import { ConfigurationContext } from "src/hooks/Config";
 import { faPlay } from "@fortawesome/free-solid-svg-icons";
 import { SceneMergeModal } from "./SceneMergeDialog";
@@ -92,6 +93,7 @@ export const SceneList: React.FC<ISceneList> = ({
   const [mergeScenes, setMergeScenes] =
     useState<{ id: string; title: string }[]>();
   const [isIdentifyDialogOpen, setIsIdentifyDialogOpen] = useState(false);
+  const [isOrganiseDialogOpen, setIsOrganiseDialogOpen] = useState(false);
   const [isExportDialogOpen, setIsExportDialogOpen] = useState(false);
   const [isExportAll, setIsExportAll] = useState(false);
 
@@ -118,6 +120,11 @@ export const SceneList: React.FC<ISceneList> = ({
       onClick: async () => setIsIdentifyDialogOpen(true),
       isDisplayed: showWhenSelected,
     },
+    {
+      text: `${intl.formatMessage({ id: "actions.organise" })}…`,
+      onClick: async () => setIsOrganiseDialogOpen(true),
+      isDisplayed: showWhenSelected,
+    },
     {
       text: `${intl.formatMessage({ id: "actions.merge" })}…`,
       onClick: onMerge,
@@ -253,6 +260,17 @@ export const SceneList: React.FC<ISceneList> = ({
       }
     }
 
+    function maybeRenderSceneOrganiseDialog() {
+      if (isOrganiseDialogOpen) {
+        return (
+          <OrganiseDialog
+            selectedIds={Array.from(selectedIds.values())}
+            onClose={() => setIsOrganiseDialogOpen(false)}
+          />
+        );
+      }
+    }
+
     function maybeRenderSceneExportDialog() {
       if (isExportDialogOpen) {
         return (
@@ -330,6 +348,7 @@ export const SceneList: React.FC<ISceneList> = ({
       <>
         {maybeRenderSceneGenerateDialog()}
         {maybeRenderSceneIdentifyDialog()}
+        {maybeRenderSceneOrganiseDialog()}
         {maybeRenderSceneExportDialog()}
         {renderMergeDialog()}
         {renderScenes()}
@@ -0,0 +1,262 @@
+import React, { useState } from "react";
+import { Button, Form } from "react-bootstrap";
+import { FormattedMessage, useIntl } from "react-intl";
+import * as GQL from "src/core/generated-graphql";
+import { useFindSavedFilters } from "src/core/StashService";
+import { ConfigurationContext } from "src/hooks/Config";
+import { ListFilterModel } from "src/models/list-filter/filter";
+import {
+  OrganiseDialog,
+  defaultOrganiseTemplate,
+} from "../Dialogs/OrganiseDialog";
+import { SettingSection } from "./SettingSection";
+import { SettingModal } from "./Inputs";
+
+interface IOrganiserRuleModal {
+  value: GQL.OrganiserRuleInput;
+  savedFilters: GQL.SavedFilterDataFragment[];
+  close: (v?: GQL.OrganiserRuleInput) => void;
+}
+
+const OrganiserRuleModal: React.FC<IOrganiserRuleModal> = ({
+  value,
+  savedFilters,
+  close,
+}) => {
+  const intl = useIntl();
+  const { configuration } = React.useContext(ConfigurationContext);
+
+  // copies the scene filter from the saved filter, since the server cannot
+  // interpret saved filters
+  function makeSceneFilter(savedFilterId?: string | null) {
+    const savedFilter = savedFilters.find((f) => f.id === savedFilterId);
+    if (!savedFilter) {
+      return "{}";
+    }
+
+    const filter = new ListFilterModel(GQL.FilterMode.Scenes, configuration);
+    filter.configureFromSavedFilter(savedFilter);
+    return JSON.stringify(filter.makeFilter());
+  }
+
+  function onClose(v?: GQL.OrganiserRuleInput) {
+    if (!v) {
+      close();
+      return;
+    }
+
+    close({ ...v, sceneFilter: makeSceneFilter(v.savedFilterId) });
+  }
+
+  return (
+    <SettingModal<GQL.OrganiserRuleInput>
+      headingID="config.library.organiser_rules.heading"
+      value={value}
+      renderField={(v, setValue) => (
+        <>
+          <Form.Group id="organiser-rule-saved-filter">
+            <h6>
+              {intl.formatMessage({
+                id: "config.library.organiser_rules.saved_filter",
+              })}
+            </h6>
+            <Form.Control
+              as="select"
+              className="input-control"
+              value={v?.savedFilterId ?? ""}
+              onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+                setValue({
+                  ...v!,
+                  savedFilterId: e.currentTarget.value || undefined,
+                })
+              }
+            >
+              <option value="">
+                {intl.formatMessage({
+                  id: "config.library.organiser_rules.all_scenes",
+                })}
+              </option>
+              {savedFilters.map((f) => (
+                <option key={f.id} value={f.id}>
+                  {f.name}
+                </option>
+              ))}
+            </Form.Control>
+          </Form.Group>
+
+          <Form.Group id="organiser-rule-template">
+            <h6>
+              {intl.formatMessage({ id: "dialogs.organise.template" })}
+            </h6>
+            <Form.Control
+              className="text-input"
+              value={v?.template}
+              isValid={(v?.template?.length ?? 0) > 0}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setValue({ ...v!, template: e.currentTarget.value })
+              }
+            />
+            <Form.Text className="text-muted">
+              {intl.formatMessage({ id: "dialogs.organise.template_desc" })}
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="organiser-rule-destination">
+            <h6>
+              {intl.formatMessage({ id: "dialogs.organise.destination" })}
+            </h6>
+            <Form.Control
+              className="text-input"
+              value={v?.destination ?? ""}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setValue({ ...v!, destination: e.currentTarget.value.trim() })
+              }
+            />
+            <Form.Text className="text-muted">
+              {intl.formatMessage({ id: "dialogs.organise.destination_desc" })}
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="organiser-rule-auto">
+            <Form.Check
+              id="organiser-rule-auto-organise"
+              checked={v?.autoOrganise ?? false}
+              label={intl.formatMessage({
+                id: "config.library.organiser_rules.auto_organise",
+              })}
+              onChange={() =>
+                setValue({ ...v!, autoOrganise: !v?.autoOrganise })
+              }
+            />
+          </Form.Group>
+        </>
+      )}
+      validate={(v) => v.template.length > 0}
+      close={onClose}
+    />
+  );
+};
+
+interface IOrganiserRuleSetting {
+  value: GQL.OrganiserRuleInput[];
+  onChange: (v: GQL.OrganiserRuleInput[]) => void;
+}
+
+export const OrganiserRuleSetting: React.FC<IOrganiserRuleSetting> = ({
+  value,
+  onChange,
+}) => {
+  const intl = useIntl();
+  const [isCreating, setIsCreating] = useState(false);
+  const [editingIndex, setEditingIndex] = useState<number | undefined>();
+  const [runningIndex, setRunningIndex] = useState<number | undefined>();
+
+  const { data } = useFindSavedFilters(GQL.FilterMode.Scenes);
+  const savedFilters = data?.findSavedFilters ?? [];
+
+  function ruleName(rule: GQL.OrganiserRuleInput) {
+    if (!rule.savedFilterId) {
+      return intl.formatMessage({
+        id: "config.library.organiser_rules.all_scenes",
+      });
+    }
+
+    return (
+      savedFilters.find((f) => f.id === rule.savedFilterId)?.name ??
+      `#${rule.savedFilterId}`
+    );
+  }
+
+  function onDelete(index: number) {
+    onChange(value.filter((v, i) => i !== index));
+  }
+
+  function maybeRenderRunDialog() {
+    if (runningIndex === undefined) {
+      return;
+    }
+
+    const rule = value[runningIndex];
+    return (
+      <OrganiseDialog
+        sceneFilter={JSON.parse(rule.sceneFilter)}
+        template={rule.template}
+        destination={rule.destination ?? undefined}
+        onClose={() => setRunningIndex(undefined)}
+      />
+    );
+  }
+
+  return (
+    <SettingSection
+      id="organiser-rules"
+      headingID="config.library.organiser_rules.heading"
+      subHeadingID="config.library.organiser_rules.description"
+    >
+      {isCreating ? (
+        <OrganiserRuleModal
+          value={{
+            sceneFilter: "{}",
+            template: defaultOrganiseTemplate,
+            autoOrganise: false,
+          }}
+          savedFilters={savedFilters}
+          close={(v) => {
+            if (v) onChange([...value, v]);
+            setIsCreating(false);
+          }}
+        />
+      ) : undefined}
+
+      {editingIndex !== undefined ? (
+        <OrganiserRuleModal
+          value={value[editingIndex]}
+          savedFilters={savedFilters}
+          close={(v) => {
+            if (v)
+              onChange(
+                value.map((vv, index) => {
+                  if (index === editingIndex) {
+                    return v;
+                  }
+                  return vv;
+                })
+              );
+            setEditingIndex(undefined);
+          }}
+        />
+      ) : undefined}
+
+      {maybeRenderRunDialog()}
+
+      {value.map((r, index) => (
+        // eslint-disable-next-line react/no-array-index-key
+        <div key={index} className="setting">
+          <div>
+            <h3>{ruleName(r)}</h3>
+            <div className="value">{r.template}</div>
+          </div>
+          <div>
+            <Button onClick={() => setRunningIndex(index)}>
+              <FormattedMessage id="actions.organise" />…
+            </Button>
+            <Button onClick={() => setEditingIndex(index)}>
+              <FormattedMessage id="actions.edit" />
+            </Button>
+            <Button variant="danger" onClick={() => onDelete(index)}>
+              <FormattedMessage id="actions.delete" />
+            </Button>
+          </div>
+        </div>
+      ))}
+      <div className="setting">
+        <div />
+        <div>
+          <Button onClick={() => setIsCreating(true)}>
+            <FormattedMessage id="actions.add" />
+          </Button>
+        </div>
+      </div>
+    </SettingSection>
+  );
+};
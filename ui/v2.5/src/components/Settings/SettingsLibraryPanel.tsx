@@ -1,9 +1,17 @@
 import React from "react";
+import * as GQL from "src/core/generated-graphql";
 import { Icon } from "../Shared/Icon";
 import { LoadingIndicator } from "../Shared/LoadingIndicator";
 import { StashSetting } from "./StashConfiguration";
+import { OrganiserRuleSetting } from "./OrganiserRuleConfiguration";
 import { SettingSection } from "./SettingSection";
-import { BooleanSetting, StringListSetting, StringSetting } from "./Inputs";
+import {
+  BooleanSetting,
+  NumberSetting,
+  SelectSetting,
+  StringListSetting,
+  StringSetting,
+} from "./Inputs";
 import { useSettings } from "./context";
 import { useIntl } from "react-intl";
 import { faQuestionCircle } from "@fortawesome/free-solid-svg-icons";
@@ -36,6 +44,61 @@ export const SettingsLibraryPanel: React.FC = () => {
         onChange={(v) => saveGeneral({ stashes: v })}
       />
 
+      <SettingSection headingID="config.library.watching">
+        <BooleanSetting
+          id="watch-library"
+          headingID="config.general.watch_library_head"
+          subHeadingID="config.general.watch_library_desc"
+          checked={general.watchLibrary ?? false}
+          onChange={(v) => saveGeneral({ watchLibrary: v })}
+        />
+
+        <NumberSetting
+          id="watch-rescan-interval"
+          headingID="config.general.watch_rescan_interval_head"
+          subHeadingID="config.general.watch_rescan_interval_desc"
+          disabled={!general.watchLibrary}
+          value={general.watchRescanInterval ?? undefined}
+          onChange={(v) => saveGeneral({ watchRescanInterval: v })}
+        />
+      </SettingSection>
+
+      <SettingSection headingID="config.library.nfo.heading">
+        <BooleanSetting
+          id="read-nfo"
+          headingID="config.library.nfo.read_nfo_head"
+          subHeadingID="config.library.nfo.read_nfo_desc"
+          checked={general.readNfo ?? false}
+          onChange={(v) => saveGeneral({ readNfo: v })}
+        />
+
+        <SelectSetting
+          id="nfo-precedence"
+          headingID="config.library.nfo.precedence.heading"
+          subHeadingID="config.library.nfo.precedence.description"
+          value={general.nfoPrecedence ?? GQL.NfoPrecedence.Existing}
+          onChange={(v) =>
+            saveGeneral({ nfoPrecedence: v as GQL.NfoPrecedence })
+          }
+        >
+          {Object.values(GQL.NfoPrecedence).map((p) => (
+            <option key={p} value={p}>
+              {intl.formatMessage({
+                id: `config.library.nfo.precedence.${p.toLowerCase()}`,
+              })}
+            </option>
+          ))}
+        </SelectSetting>
+
+        <BooleanSetting
+          id="write-nfo"
+          headingID="config.library.nfo.write_nfo_head"
+          subHeadingID="config.library.nfo.write_nfo_desc"
+          checked={general.writeNfo ?? false}
+          onChange={(v) => saveGeneral({ writeNfo: v })}
+        />
+      </SettingSection>
+
       <SettingSection headingID="config.library.media_content_extensions">
         <StringSetting
           id="video-extensions"
@@ -140,6 +203,11 @@ export const SettingsLibraryPanel: React.FC = () => {
         />
       </SettingSection>
 
+      <OrganiserRuleSetting
+        value={general.organiserRules ?? []}
+        onChange={(v) => saveGeneral({ organiserRules: v })}
+      />
+
       <SettingSection headingID="config.ui.delete_options.heading">
         <BooleanSetting
           id="delete-file-default"
@@ -27,6 +27,7 @@ import {
   InstalledPluginPackages,
 } from "./PluginPackageManager";
 import { ExternalLink } from "../Shared/ExternalLink";
+import { WebhookSetting } from "./WebhookConfiguration";
 import { PatchComponent } from "src/patch";
 
 interface IPluginSettingProps {
@@ -107,7 +108,7 @@ export const SettingsPluginsPanel: React.FC = () => {
   const Toast = useToast();
   const intl = useIntl();
 
-  const { loading: configLoading } = useSettings();
+  const { general, saveGeneral, loading: configLoading } = useSettings();
   const { data, loading } = usePlugins();
 
   const [changedPluginID, setChangedPluginID] = React.useState<
@@ -262,6 +263,11 @@ export const SettingsPluginsPanel: React.FC = () => {
         </Setting>
         {pluginElements}
       </SettingSection>
+
+      <WebhookSetting
+        value={general.webhooks ?? []}
+        onChange={(v) => saveGeneral({ webhooks: v })}
+      />
     </>
   );
 };
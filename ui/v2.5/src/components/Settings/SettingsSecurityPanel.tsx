@@ -164,6 +164,32 @@ export const SettingsSecurityPanel: React.FC = () => {
           onChange={(v) => saveGeneral({ maxSessionAge: v })}
         />
       </SettingSection>
+
+      <SettingSection headingID="config.general.query_limits">
+        <NumberSetting
+          id="graphql-max-complexity"
+          headingID="config.general.graphql_max_complexity.heading"
+          subHeadingID="config.general.graphql_max_complexity.description"
+          value={general.graphqlMaxComplexity ?? undefined}
+          onChange={(v) => saveGeneral({ graphqlMaxComplexity: v })}
+        />
+
+        <NumberSetting
+          id="graphql-max-depth"
+          headingID="config.general.graphql_max_depth.heading"
+          subHeadingID="config.general.graphql_max_depth.description"
+          value={general.graphqlMaxDepth ?? undefined}
+          onChange={(v) => saveGeneral({ graphqlMaxDepth: v })}
+        />
+
+        <NumberSetting
+          id="query-timeout"
+          headingID="config.general.query_timeout.heading"
+          subHeadingID="config.general.query_timeout.description"
+          value={general.queryTimeout ?? undefined}
+          onChange={(v) => saveGeneral({ queryTimeout: v })}
+        />
+      </SettingSection>
     </>
   );
 };
@@ -222,6 +222,70 @@ export const SettingsConfigurationPanel: React.FC = () => {
           value={general.backupDirectoryPath ?? undefined}
           onChange={(v) => saveGeneral({ backupDirectoryPath: v })}
         />
+
+        <NumberSetting
+          id="backup-interval"
+          headingID="config.general.backup_interval.heading"
+          subHeadingID="config.general.backup_interval.description"
+          value={general.backupInterval ?? undefined}
+          onChange={(v) => saveGeneral({ backupInterval: v })}
+        />
+
+        <NumberSetting
+          id="backup-keep-count"
+          headingID="config.general.backup_keep_count.heading"
+          subHeadingID="config.general.backup_keep_count.description"
+          value={general.backupKeepCount ?? undefined}
+          onChange={(v) => saveGeneral({ backupKeepCount: v })}
+        />
+
+        <NumberSetting
+          id="backup-keep-daily"
+          headingID="config.general.backup_keep_daily.heading"
+          subHeadingID="config.general.backup_keep_daily.description"
+          value={general.backupKeepDaily ?? undefined}
+          onChange={(v) => saveGeneral({ backupKeepDaily: v })}
+        />
+
+        <NumberSetting
+          id="backup-keep-weekly"
+          headingID="config.general.backup_keep_weekly.heading"
+          subHeadingID="config.general.backup_keep_weekly.description"
+          value={general.backupKeepWeekly ?? undefined}
+          onChange={(v) => saveGeneral({ backupKeepWeekly: v })}
+        />
+
+        <BooleanSetting
+          id="backup-compress"
+          headingID="config.general.backup_compress.heading"
+          subHeadingID="config.general.backup_compress.description"
+          checked={general.backupCompress ?? false}
+          onChange={(v) => saveGeneral({ backupCompress: v })}
+        />
+
+        <BooleanSetting
+          id="backup-include-blobs"
+          headingID="config.general.backup_include_blobs.heading"
+          subHeadingID="config.general.backup_include_blobs.description"
+          checked={general.backupIncludeBlobs ?? false}
+          onChange={(v) => saveGeneral({ backupIncludeBlobs: v })}
+        />
+
+        <StringSetting
+          id="trash-path"
+          headingID="config.general.trash_path.heading"
+          subHeadingID="config.general.trash_path.description"
+          value={general.trashPath ?? undefined}
+          onChange={(v) => saveGeneral({ trashPath: v })}
+        />
+
+        <NumberSetting
+          id="trash-retention-days"
+          headingID="config.general.trash_retention_days.heading"
+          subHeadingID="config.general.trash_retention_days.description"
+          value={general.trashRetentionDays ?? undefined}
+          onChange={(v) => saveGeneral({ trashRetentionDays: v })}
+        />
       </SettingSection>
 
       <SettingSection headingID="config.general.database">
@@ -254,6 +318,13 @@ export const SettingsConfigurationPanel: React.FC = () => {
           value={general.blobsPath ?? ""}
           onChange={(v) => saveGeneral({ blobsPath: v })}
         />
+        <NumberSetting
+          id="change-log-retention-days"
+          headingID="config.general.change_log_retention_days.heading"
+          subHeadingID="config.general.change_log_retention_days.description"
+          value={general.changeLogRetentionDays ?? undefined}
+          onChange={(v) => saveGeneral({ changeLogRetentionDays: v })}
+        />
       </SettingSection>
 
       <SettingSection advanced headingID="config.general.hashing">
@@ -31,6 +31,16 @@ export const SettingsToolsPanel: React.FC = () => {
             </Link>
           }
         />
+
+        <Setting
+          heading={
+            <Link to="/trash">
+              <Button>
+                <FormattedMessage id="trash.title" />
+              </Button>
+            </Link>
+          }
+        />
       </SettingsToolsSection>
     </SettingSection>
   );
@@ -1,18 +1,217 @@
 import { faEllipsisV } from "@fortawesome/free-solid-svg-icons";
 import React, { useState } from "react";
 import { Button, Form, Row, Col, Dropdown } from "react-bootstrap";
-import { FormattedMessage } from "react-intl";
+import { FormattedMessage, useIntl } from "react-intl";
 import { Icon } from "src/components/Shared/Icon";
 import * as GQL from "src/core/generated-graphql";
 import { FolderSelectDialog } from "../Shared/FolderSelect/FolderSelectDialog";
-import { BooleanSetting } from "./Inputs";
+import { BooleanSetting, SettingModal } from "./Inputs";
 import { SettingSection } from "./SettingSection";
 
+const remoteStorageTypes = [
+  GQL.RemoteStorageType.Webdav,
+  GQL.RemoteStorageType.Sftp,
+];
+
+// the password of a saved remote stash is not returned, only whether it is
+// set. The password is kept if it is not set in the input.
+type IRemoteStashConfig = GQL.RemoteStashConfigInput & {
+  hasPassword?: boolean;
+};
+
+export type IStashConfig = Omit<GQL.StashConfigInput, "remote"> & {
+  remote?: IRemoteStashConfig | null;
+};
+
+interface IRemoteStashModal {
+  value: IStashConfig;
+  close: (v?: IStashConfig) => void;
+}
+
+const RemoteStashModal: React.FC<IRemoteStashModal> = ({ value, close }) => {
+  const intl = useIntl();
+
+  function renderTextField(
+    id: string,
+    v: IStashConfig | undefined,
+    field: "url" | "username" | "password" | "keyFile" | "hostKey",
+    setValue: (v?: IStashConfig) => void,
+    options?: {
+      type?: string;
+      descriptionID?: string;
+      as?: "textarea";
+      placeholder?: string;
+    }
+  ) {
+    const remote = v?.remote;
+    return (
+      <Form.Group id={`remote-stash-${field}`}>
+        <h6>
+          <FormattedMessage id={`config.general.remote_stash.${id}`} />
+        </h6>
+        <Form.Control
+          className="text-input"
+          type={options?.type}
+          as={options?.as}
+          placeholder={options?.placeholder}
+          value={remote?.[field] ?? ""}
+          onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+            setValue({
+              ...v!,
+              remote: { ...remote!, [field]: e.currentTarget.value },
+            })
+          }
+        />
+        {options?.descriptionID && (
+          <Form.Text className="text-muted">
+            <FormattedMessage id={options.descriptionID} />
+          </Form.Text>
+        )}
+      </Form.Group>
+    );
+  }
+
+  return (
+    <SettingModal<IStashConfig>
+      headingID="config.general.remote_stash.heading"
+      value={value}
+      validate={(v) => !!v.path && !!v.remote?.url}
+      renderField={(v, setValue) => (
+        <>
+          <Form.Group id="remote-stash-type">
+            <h6>
+              <FormattedMessage id="config.general.remote_stash.type" />
+            </h6>
+            <Form.Control
+              as="select"
+              className="input-control"
+              value={v?.remote?.type}
+              onChange={(e: React.ChangeEvent<HTMLSelectElement>) =>
+                setValue({
+                  ...v!,
+                  remote: {
+                    ...v!.remote!,
+                    type: e.currentTarget.value as GQL.RemoteStorageType,
+                  },
+                })
+              }
+            >
+              {remoteStorageTypes.map((t) => (
+                <option key={t} value={t}>
+                  {t}
+                </option>
+              ))}
+            </Form.Control>
+          </Form.Group>
+
+          {renderTextField("url", v, "url", setValue, {
+            descriptionID: "config.general.remote_stash.url_desc",
+          })}
+          {renderTextField("username", v, "username", setValue)}
+          {renderTextField("password", v, "password", setValue, {
+            type: "password",
+            placeholder: v?.remote?.hasPassword
+              ? intl.formatMessage({
+                  id: "config.general.remote_stash.password_unchanged",
+                })
+              : undefined,
+          })}
+
+          {v?.remote?.type === GQL.RemoteStorageType.Sftp && (
+            <>
+              {renderTextField("key_file", v, "keyFile", setValue, {
+                descriptionID: "config.general.remote_stash.key_file_desc",
+              })}
+              {renderTextField("host_key", v, "hostKey", setValue, {
+                as: "textarea",
+                descriptionID: "config.general.remote_stash.host_key_desc",
+              })}
+            </>
+          )}
+
+          <Form.Group id="remote-stash-max-connections">
+            <h6>
+              <FormattedMessage
+                id="config.general.remote_stash.max_connections"
+              />
+            </h6>
+            <Form.Control
+              className="text-input"
+              type="number"
+              min={1}
+              value={v?.remote?.maxConnections ?? ""}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) => {
+                const n = Number.parseInt(e.currentTarget.value, 10);
+                setValue({
+                  ...v!,
+                  remote: {
+                    ...v!.remote!,
+                    maxConnections: Number.isNaN(n) ? undefined : n,
+                  },
+                });
+              }}
+            />
+          </Form.Group>
+
+          <Form.Group id="remote-stash-path">
+            <h6>
+              <FormattedMessage id="config.general.remote_stash.local_path" />
+            </h6>
+            <Form.Control
+              className="text-input"
+              placeholder={intl.formatMessage({
+                id: "config.general.remote_stash.local_path",
+              })}
+              value={v?.path ?? ""}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setValue({ ...v!, path: e.currentTarget.value.trim() })
+              }
+            />
+            <Form.Text className="text-muted">
+              <FormattedMessage
+                id="config.general.remote_stash.local_path_desc"
+              />
+            </Form.Text>
+          </Form.Group>
+        </>
+      )}
+      close={close}
+    />
+  );
+};
+
+interface IMountMarkerModal {
+  value: string;
+  close: (v?: string) => void;
+}
+
+const MountMarkerModal: React.FC<IMountMarkerModal> = ({ value, close }) => {
+  return (
+    <SettingModal<string>
+      headingID="config.general.mount_marker.heading"
+      subHeadingID="config.general.mount_marker.description"
+      value={value}
+      validate={() => true}
+      renderField={(v, setValue) => (
+        <Form.Control
+          className="text-input"
+          value={v ?? ""}
+          onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+            setValue(e.currentTarget.value.trim())
+          }
+        />
+      )}
+      close={close}
+    />
+  );
+};
+
 interface IStashProps {
   index: number;
-  stash: GQL.StashConfig;
-  onSave: (instance: GQL.StashConfig) => void;
+  stash: IStashConfig;
+  onSave: (instance: IStashConfig) => void;
   onEdit: () => void;
+  onEditMountMarker: () => void;
   onDelete: () => void;
 }
 
@@ -21,6 +220,7 @@ const Stash: React.FC<IStashProps> = ({
   stash,
   onSave,
   onEdit,
+  onEditMountMarker,
   onDelete,
 }) => {
   // eslint-disable-next-line
@@ -38,6 +238,21 @@ const Stash: React.FC<IStashProps> = ({
     <Row className={`stash-row align-items-center ${classAdd}`}>
       <Form.Label column md={7}>
         {stash.path}
+        {stash.remote && (
+          <div className="text-muted">
+            <small>{stash.remote.url}</small>
+          </div>
+        )}
+        {stash.mountMarker && (
+          <div className="text-muted">
+            <small>
+              <FormattedMessage
+                id="config.general.mount_marker.value"
+                values={{ marker: stash.mountMarker }}
+              />
+            </small>
+          </div>
+        )}
       </Form.Label>
       <Col md={2} xs={4} className="col form-label">
         {/* NOTE - language is opposite to meaning:
@@ -79,6 +294,9 @@ const Stash: React.FC<IStashProps> = ({
             <Dropdown.Item onClick={() => onEdit()}>
               <FormattedMessage id="actions.edit" />
             </Dropdown.Item>
+            <Dropdown.Item onClick={() => onEditMountMarker()}>
+              <FormattedMessage id="config.general.mount_marker.heading" />
+            </Dropdown.Item>
             <Dropdown.Item onClick={() => onDelete()}>
               <FormattedMessage id="actions.delete" />
             </Dropdown.Item>
@@ -90,8 +308,8 @@ const Stash: React.FC<IStashProps> = ({
 };
 
 interface IStashConfigurationProps {
-  stashes: GQL.StashConfig[];
-  setStashes: (v: GQL.StashConfig[]) => void;
+  stashes: IStashConfig[];
+  setStashes: (v: IStashConfig[]) => void;
 }
 
 const StashConfiguration: React.FC<IStashConfigurationProps> = ({
@@ -99,7 +317,12 @@ const StashConfiguration: React.FC<IStashConfigurationProps> = ({
   setStashes,
 }) => {
   const [isCreating, setIsCreating] = useState(false);
+  const [isCreatingRemote, setIsCreatingRemote] = useState(false);
   const [editingIndex, setEditingIndex] = useState<number | undefined>();
+  const [markerIndex, setMarkerIndex] = useState<number | undefined>();
+
+  const editingStash =
+    editingIndex !== undefined ? stashes[editingIndex] : undefined;
 
   function onEdit(index: number) {
     setEditingIndex(index);
@@ -113,7 +336,7 @@ const StashConfiguration: React.FC<IStashConfigurationProps> = ({
     setIsCreating(true);
   }
 
-  const handleSave = (index: number, stash: GQL.StashConfig) =>
+  const handleSave = (index: number, stash: IStashConfig) =>
     setStashes(stashes.map((s, i) => (i === index ? stash : s)));
 
   return (
@@ -135,9 +358,37 @@ const StashConfiguration: React.FC<IStashConfigurationProps> = ({
         />
       ) : undefined}
 
-      {editingIndex !== undefined ? (
+      {isCreatingRemote ? (
+        <RemoteStashModal
+          value={{
+            path: "",
+            excludeVideo: false,
+            excludeImage: false,
+            remote: {
+              type: GQL.RemoteStorageType.Webdav,
+              url: "",
+            },
+          }}
+          close={(v) => {
+            if (v) setStashes([...stashes, v]);
+            setIsCreatingRemote(false);
+          }}
+        />
+      ) : undefined}
+
+      {editingStash?.remote ? (
+        <RemoteStashModal
+          value={editingStash}
+          close={(v) => {
+            if (v) handleSave(editingIndex!, v);
+            setEditingIndex(undefined);
+          }}
+        />
+      ) : undefined}
+
+      {editingStash && !editingStash.remote ? (
         <FolderSelectDialog
-          defaultValue={stashes[editingIndex].path}
+          defaultValue={editingStash.path}
           onClose={(v) => {
             if (v)
               setStashes(
@@ -156,6 +407,21 @@ const StashConfiguration: React.FC<IStashConfigurationProps> = ({
         />
       ) : undefined}
 
+      {markerIndex !== undefined ? (
+        <MountMarkerModal
+          value={stashes[markerIndex].mountMarker ?? ""}
+          close={(v) => {
+            if (v !== undefined) {
+              handleSave(markerIndex, {
+                ...stashes[markerIndex],
+                mountMarker: v || undefined,
+              });
+            }
+            setMarkerIndex(undefined);
+          }}
+        />
+      ) : undefined}
+
       <div className="content" id="stash-table">
         {stashes.length > 0 && (
           <Row className="d-none d-md-flex">
@@ -176,6 +442,7 @@ const StashConfiguration: React.FC<IStashConfigurationProps> = ({
             stash={stash}
             onSave={(s) => handleSave(index, s)}
             onEdit={() => onEdit(index)}
+            onEditMountMarker={() => setMarkerIndex(index)}
             onDelete={() => onDelete(index)}
             key={stash.path}
           />
@@ -183,6 +450,13 @@ const StashConfiguration: React.FC<IStashConfigurationProps> = ({
         <Button className="mt-2" variant="secondary" onClick={() => onNew()}>
           <FormattedMessage id="actions.add_directory" />
         </Button>
+        <Button
+          className="mt-2 ml-2"
+          variant="secondary"
+          onClick={() => setIsCreatingRemote(true)}
+        >
+          <FormattedMessage id="actions.add_remote_directory" />
+        </Button>
       </div>
     </>
   );
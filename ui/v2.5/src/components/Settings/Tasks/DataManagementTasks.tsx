@@ -11,7 +11,10 @@ import {
   mutateMigrateSceneScreenshots,
   mutateMigrateBlobs,
   mutateOptimiseDatabase,
+  mutateDatabaseHealthCheck,
   mutateCleanGenerated,
+  mutateMetadataVerify,
+  mutateMetadataWriteNfo,
 } from "src/core/StashService";
 import { useToast } from "src/hooks/Toast";
 import downloadFile from "src/utils/download";
@@ -19,7 +22,7 @@ import { ModalComponent } from "src/components/Shared/Modal";
 import { ImportDialog } from "./ImportDialog";
 import * as GQL from "src/core/generated-graphql";
 import { SettingSection } from "../SettingSection";
-import { BooleanSetting, Setting } from "../Inputs";
+import { BooleanSetting, NumberSetting, Setting } from "../Inputs";
 import { ManualLink } from "src/components/Help/context";
 import { Icon } from "src/components/Shared/Icon";
 import { ConfigurationContext } from "src/hooks/Config";
@@ -176,6 +179,9 @@ export const DataManagementTasks: React.FC<IDataManagementTasks> = ({
     dryRun: false,
   });
 
+  const [verifyOptions, setVerifyOptions] =
+    useState<GQL.VerifyMetadataInput>({});
+
   const [migrateBlobsOptions, setMigrateBlobsOptions] =
     useState<GQL.MigrateBlobsInput>({
       deleteOld: true,
@@ -276,6 +282,36 @@ export const DataManagementTasks: React.FC<IDataManagementTasks> = ({
     }
   }
 
+  async function onVerify() {
+    try {
+      await mutateMetadataVerify(verifyOptions);
+
+      Toast.success(
+        intl.formatMessage(
+          { id: "config.tasks.added_job_to_queue" },
+          { operation_name: intl.formatMessage({ id: "actions.verify_files" }) }
+        )
+      );
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  async function onWriteNfo() {
+    try {
+      await mutateMetadataWriteNfo({});
+
+      Toast.success(
+        intl.formatMessage(
+          { id: "config.tasks.added_job_to_queue" },
+          { operation_name: intl.formatMessage({ id: "actions.write_nfo" }) }
+        )
+      );
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
   async function onMigrateHashNaming() {
     try {
       await mutateMigrateHashNaming();
@@ -381,6 +417,26 @@ export const DataManagementTasks: React.FC<IDataManagementTasks> = ({
     }
   }
 
+  async function onDatabaseHealthCheck(repair: boolean) {
+    try {
+      await mutateDatabaseHealthCheck({ repair });
+      Toast.success(
+        intl.formatMessage(
+          { id: "config.tasks.added_job_to_queue" },
+          {
+            operation_name: intl.formatMessage({
+              id: repair
+                ? "actions.repair_database"
+                : "actions.check_database_health",
+            }),
+          }
+        )
+      );
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
   async function onAnonymise(download?: boolean) {
     try {
       setIsAnonymiseRunning(true);
@@ -489,6 +545,56 @@ export const DataManagementTasks: React.FC<IDataManagementTasks> = ({
           </Setting>
         </div>
 
+        <div className="setting-group">
+          <Setting
+            heading={
+              <>
+                <FormattedMessage id="actions.verify_files" />
+                <ManualLink tab="Tasks">
+                  <Icon icon={faQuestionCircle} />
+                </ManualLink>
+              </>
+            }
+            subHeadingID="config.tasks.verify_files.description"
+          >
+            <Button
+              id="verifyFiles"
+              variant="secondary"
+              type="submit"
+              onClick={() => onVerify()}
+            >
+              <FormattedMessage id="actions.verify_files" />
+            </Button>
+          </Setting>
+          <NumberSetting
+            id="verify-sample-percentage"
+            className="sub-setting"
+            headingID="config.tasks.verify_files.sample_percentage"
+            subHeadingID="config.tasks.verify_files.sample_percentage_desc"
+            value={verifyOptions.sample_percentage ?? undefined}
+            onChange={(v) =>
+              setVerifyOptions({
+                ...verifyOptions,
+                sample_percentage: v || undefined,
+              })
+            }
+          />
+        </div>
+
+        <Setting
+          headingID="actions.write_nfo"
+          subHeadingID="config.tasks.write_nfo_desc"
+        >
+          <Button
+            id="writeNfo"
+            variant="secondary"
+            type="submit"
+            onClick={() => onWriteNfo()}
+          >
+            <FormattedMessage id="actions.write_nfo" />
+          </Button>
+        </Setting>
+
         <Setting
           headingID="actions.optimise_database"
           subHeading={
@@ -507,6 +613,27 @@ export const DataManagementTasks: React.FC<IDataManagementTasks> = ({
             <FormattedMessage id="actions.optimise_database" />
           </Button>
         </Setting>
+
+        <Setting
+          headingID="actions.check_database_health"
+          subHeadingID="config.tasks.database_health_check"
+        >
+          <Button
+            id="databaseHealthCheck"
+            variant="secondary"
+            type="submit"
+            onClick={() => onDatabaseHealthCheck(false)}
+          >
+            <FormattedMessage id="actions.check_database_health" />
+          </Button>
+          <Button
+            id="databaseHealthRepair"
+            variant="danger"
+            onClick={() => onDatabaseHealthCheck(true)}
+          >
+            <FormattedMessage id="actions.repair_database" />
+          </Button>
+        </Setting>
       </SettingSection>
 
       <SettingSection headingID="metadata">
@@ -149,12 +149,44 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
             onChange={(v) => setOptions({ phashes: v })}
           />
 
+          <BooleanSetting
+            id="phash-segments-task"
+            checked={options.phashSegments ?? false}
+            headingID="dialogs.scene_gen.phash_segments"
+            tooltipID="dialogs.scene_gen.phash_segments_tooltip"
+            onChange={(v) => setOptions({ phashSegments: v })}
+          />
+
+          <BooleanSetting
+            id="audio-fingerprints-task"
+            checked={options.audioFingerprints ?? false}
+            headingID="dialogs.scene_gen.audio_fingerprints"
+            tooltipID="dialogs.scene_gen.audio_fingerprints_tooltip"
+            onChange={(v) => setOptions({ audioFingerprints: v })}
+          />
+
           <BooleanSetting
             id="interactive-heatmap-speed-task"
             checked={options.interactiveHeatmapsSpeeds ?? false}
             headingID="dialogs.scene_gen.interactive_heatmap_speed"
             onChange={(v) => setOptions({ interactiveHeatmapsSpeeds: v })}
           />
+
+          <BooleanSetting
+            id="scene-cuts-task"
+            checked={options.sceneCuts ?? false}
+            headingID="dialogs.scene_gen.scene_cuts"
+            tooltipID="dialogs.scene_gen.scene_cuts_tooltip"
+            onChange={(v) => setOptions({ sceneCuts: v })}
+          />
+
+          <BooleanSetting
+            id="import-chapters-task"
+            checked={options.importChapters ?? false}
+            headingID="dialogs.scene_gen.import_chapters"
+            tooltipID="dialogs.scene_gen.import_chapters_tooltip"
+            onChange={(v) => setOptions({ importChapters: v })}
+          />
         </>
       )}
       {showImageOptions && (
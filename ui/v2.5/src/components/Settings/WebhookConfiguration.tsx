@@ -0,0 +1,334 @@
+import React, { useState } from "react";
+import { Button, Form, Table } from "react-bootstrap";
+import { FormattedMessage, useIntl } from "react-intl";
+import * as GQL from "src/core/generated-graphql";
+import {
+  mutateClearWebhookDeadLetters,
+  useWebhookDeadLetters,
+} from "src/core/StashService";
+import { useToast } from "src/hooks/Toast";
+import TextUtils from "src/utils/text";
+import { SettingSection } from "./SettingSection";
+import { SettingModal } from "./Inputs";
+
+function splitLines(v: string) {
+  return v
+    .split("\n")
+    .map((s) => s.trim())
+    .filter((s) => s.length > 0);
+}
+
+// the secret and header values of saved webhooks are not returned by the
+// configuration query
+export type IWebhook = GQL.WebhookInput & {
+  hasSecret?: boolean;
+};
+
+// headers are edited as "Name: Value" lines. A line with only the name keeps
+// the saved value of the header.
+function headersToString(headers?: GQL.WebhookHeaderInput[] | null) {
+  return (headers ?? [])
+    .map((h) => (h.value != null ? `${h.name}: ${h.value}` : h.name))
+    .join("\n");
+}
+
+function stringToHeaders(v: string): GQL.WebhookHeaderInput[] {
+  return splitLines(v).map((line) => {
+    const i = line.indexOf(":");
+    if (i === -1) {
+      return { name: line };
+    }
+    return { name: line.slice(0, i).trim(), value: line.slice(i + 1).trim() };
+  });
+}
+
+interface IWebhookModal {
+  value: IWebhook;
+  close: (v?: IWebhook) => void;
+}
+
+const WebhookModal: React.FC<IWebhookModal> = ({ value, close }) => {
+  const intl = useIntl();
+  const [headers, setHeaders] = useState(headersToString(value.headers));
+
+  return (
+    <SettingModal<IWebhook>
+      headingID="config.webhooks.heading"
+      value={value}
+      renderField={(v, setValue) => (
+        <>
+          <Form.Group id="webhook-name">
+            <h6>{intl.formatMessage({ id: "config.webhooks.name" })}</h6>
+            <Form.Control
+              className="text-input"
+              value={v?.name}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setValue({ ...v!, name: e.currentTarget.value })
+              }
+            />
+          </Form.Group>
+
+          <Form.Group id="webhook-url">
+            <h6>{intl.formatMessage({ id: "config.webhooks.url" })}</h6>
+            <Form.Control
+              className="text-input"
+              value={v?.url}
+              isValid={(v?.url?.length ?? 0) > 0}
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setValue({ ...v!, url: e.currentTarget.value.trim() })
+              }
+            />
+          </Form.Group>
+
+          <Form.Group id="webhook-triggers">
+            <h6>{intl.formatMessage({ id: "config.webhooks.triggers" })}</h6>
+            <Form.Control
+              as="textarea"
+              className="text-input"
+              rows={4}
+              value={v?.triggers.join("\n")}
+              onChange={(e: React.ChangeEvent<HTMLTextAreaElement>) =>
+                setValue({ ...v!, triggers: splitLines(e.currentTarget.value) })
+              }
+            />
+            <Form.Text className="text-muted">
+              {intl.formatMessage({ id: "config.webhooks.triggers_desc" })}
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="webhook-secret">
+            <h6>{intl.formatMessage({ id: "config.webhooks.secret" })}</h6>
+            <Form.Control
+              className="text-input"
+              value={v?.secret ?? ""}
+              placeholder={
+                v?.hasSecret
+                  ? intl.formatMessage({ id: "config.webhooks.unchanged" })
+                  : undefined
+              }
+              onChange={(e: React.ChangeEvent<HTMLInputElement>) =>
+                setValue({ ...v!, secret: e.currentTarget.value })
+              }
+            />
+            <Form.Text className="text-muted">
+              {intl.formatMessage({ id: "config.webhooks.secret_desc" })}
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="webhook-headers">
+            <h6>{intl.formatMessage({ id: "config.webhooks.headers" })}</h6>
+            <Form.Control
+              as="textarea"
+              className="text-input"
+              rows={3}
+              value={headers}
+              onChange={(e: React.ChangeEvent<HTMLTextAreaElement>) => {
+                setHeaders(e.currentTarget.value);
+                setValue({
+                  ...v!,
+                  headers: stringToHeaders(e.currentTarget.value),
+                });
+              }}
+            />
+            <Form.Text className="text-muted">
+              {intl.formatMessage({ id: "config.webhooks.headers_desc" })}
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="webhook-payload-template">
+            <h6>
+              {intl.formatMessage({ id: "config.webhooks.payload_template" })}
+            </h6>
+            <Form.Control
+              as="textarea"
+              className="text-input"
+              rows={4}
+              value={v?.payloadTemplate ?? ""}
+              onChange={(e: React.ChangeEvent<HTMLTextAreaElement>) =>
+                setValue({ ...v!, payloadTemplate: e.currentTarget.value })
+              }
+            />
+            <Form.Text className="text-muted">
+              {intl.formatMessage({
+                id: "config.webhooks.payload_template_desc",
+              })}
+            </Form.Text>
+          </Form.Group>
+
+          <Form.Group id="webhook-enabled">
+            <Form.Check
+              id="webhook-enabled-check"
+              checked={v?.enabled ?? false}
+              label={intl.formatMessage({ id: "config.webhooks.enabled" })}
+              onChange={() => setValue({ ...v!, enabled: !v?.enabled })}
+            />
+          </Form.Group>
+        </>
+      )}
+      validate={(v) => v.url.length > 0 && v.triggers.length > 0}
+      close={close}
+    />
+  );
+};
+
+const WebhookDeadLetters: React.FC = () => {
+  const intl = useIntl();
+  const Toast = useToast();
+  const { data, refetch } = useWebhookDeadLetters();
+  const deadLetters = data?.webhookDeadLetters ?? [];
+
+  async function onClear() {
+    try {
+      await mutateClearWebhookDeadLetters();
+      refetch();
+    } catch (e) {
+      Toast.error(e);
+    }
+  }
+
+  return (
+    <SettingSection
+      id="webhook-dead-letters"
+      headingID="config.webhooks.dead_letters.heading"
+      subHeadingID="config.webhooks.dead_letters.description"
+    >
+      {deadLetters.length > 0 ? (
+        <Table className="webhook-dead-letters" size="sm">
+          <thead>
+            <tr>
+              <th>
+                <FormattedMessage id="config.webhooks.dead_letters.failed_at" />
+              </th>
+              <th>
+                <FormattedMessage id="config.webhooks.name" />
+              </th>
+              <th>
+                <FormattedMessage id="config.webhooks.dead_letters.event" />
+              </th>
+              <th>
+                <FormattedMessage id="config.webhooks.dead_letters.attempts" />
+              </th>
+              <th>
+                <FormattedMessage id="config.webhooks.dead_letters.error" />
+              </th>
+            </tr>
+          </thead>
+          <tbody>
+            {deadLetters.map((l, index) => (
+              // eslint-disable-next-line react/no-array-index-key
+              <tr key={index}>
+                <td>{TextUtils.formatDateTime(intl, l.failed_at)}</td>
+                <td>{l.webhook || l.url}</td>
+                <td>{`${l.trigger} #${l.id}`}</td>
+                <td>{l.attempts}</td>
+                <td>{l.error}</td>
+              </tr>
+            ))}
+          </tbody>
+        </Table>
+      ) : (
+        <div className="setting">
+          <FormattedMessage id="config.webhooks.dead_letters.empty" />
+        </div>
+      )}
+      <div className="setting">
+        <div />
+        <div>
+          <Button onClick={() => refetch()}>
+            <FormattedMessage id="actions.refresh" />
+          </Button>
+          <Button
+            variant="danger"
+            disabled={deadLetters.length === 0}
+            onClick={() => onClear()}
+          >
+            <FormattedMessage id="actions.clear" />
+          </Button>
+        </div>
+      </div>
+    </SettingSection>
+  );
+};
+
+interface IWebhookSetting {
+  value: IWebhook[];
+  onChange: (v: IWebhook[]) => void;
+}
+
+export const WebhookSetting: React.FC<IWebhookSetting> = ({
+  value,
+  onChange,
+}) => {
+  const [isCreating, setIsCreating] = useState(false);
+  const [editingIndex, setEditingIndex] = useState<number | undefined>();
+
+  function onDelete(index: number) {
+    onChange(value.filter((v, i) => i !== index));
+  }
+
+  return (
+    <>
+      <SettingSection
+        id="webhooks"
+        headingID="config.webhooks.heading"
+        subHeadingID="config.webhooks.description"
+      >
+        {isCreating ? (
+          <WebhookModal
+            value={{ name: "", url: "", triggers: [], enabled: true }}
+            close={(v) => {
+              if (v) onChange([...value, v]);
+              setIsCreating(false);
+            }}
+          />
+        ) : undefined}
+
+        {editingIndex !== undefined ? (
+          <WebhookModal
+            value={value[editingIndex]}
+            close={(v) => {
+              if (v)
+                onChange(
+                  value.map((vv, index) => {
+                    if (index === editingIndex) {
+                      return v;
+                    }
+                    return vv;
+                  })
+                );
+              setEditingIndex(undefined);
+            }}
+          />
+        ) : undefined}
+
+        {value.map((w, index) => (
+          // eslint-disable-next-line react/no-array-index-key
+          <div key={index} className="setting">
+            <div>
+              <h3>{w.name || w.url}</h3>
+              <div className="value">{w.triggers.join(", ")}</div>
+            </div>
+            <div>
+              <Button onClick={() => setEditingIndex(index)}>
+                <FormattedMessage id="actions.edit" />
+              </Button>
+              <Button variant="danger" onClick={() => onDelete(index)}>
+                <FormattedMessage id="actions.delete" />
+              </Button>
+            </div>
+          </div>
+        ))}
+        <div className="setting">
+          <div />
+          <div>
+            <Button onClick={() => setIsCreating(true)}>
+              <FormattedMessage id="actions.add" />
+            </Button>
+          </div>
+        </div>
+      </SettingSection>
+
+      <WebhookDeadLetters />
+    </>
+  );
+};
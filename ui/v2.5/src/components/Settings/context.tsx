@@ -81,6 +81,27 @@ const emptyState: ISettingsContextState = {
   refetch: noop,
 };
 
+// removes the output only fields of a stash returned by the configuration
+// query, so that it can be used as input
+function stashConfigInput(s: GQL.StashConfigInput): GQL.StashConfigInput {
+  if (!s.remote) {
+    return s;
+  }
+
+  const { hasPassword, ...remote } =
+    s.remote as GQL.RemoteStashConfigInput & { hasPassword?: boolean };
+  return { ...s, remote };
+}
+
+// removes the output only fields of a webhook returned by the configuration
+// query, so that it can be used as input
+function webhookInput(w: GQL.WebhookInput): GQL.WebhookInput {
+  const { hasSecret, ...ret } = w as GQL.WebhookInput & {
+    hasSecret?: boolean;
+  };
+  return ret;
+}
+
 export const SettingStateContext =
   React.createContext<ISettingsContextState | null>(null);
 
@@ -188,7 +209,11 @@ export const SettingsContext: React.FC = ({ children }) => {
         setUpdateSuccess(undefined);
         await updateGeneralConfig({
           variables: {
-            input,
+            input: {
+              ...input,
+              stashes: input.stashes?.map(stashConfigInput),
+              webhooks: input.webhooks?.map(webhookInput),
+            },
           },
         });
 
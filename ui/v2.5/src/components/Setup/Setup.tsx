@@ -515,7 +515,7 @@ const SetPathsStep: React.FC<IWizardStep> = ({ goBack, next }) => {
 
   const [showStashAlert, setShowStashAlert] = useState(false);
 
-  const [stashes, setStashes] = useState<GQL.StashConfig[]>([]);
+  const [stashes, setStashes] = useState<GQL.StashConfigInput[]>([]);
   const [databaseFile, setDatabaseFile] = useState("");
   const [generatedLocation, setGeneratedLocation] = useState("");
   const [cacheLocation, setCacheLocation] = useState("");
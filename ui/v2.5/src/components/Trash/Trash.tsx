@@ -0,0 +1,273 @@
+import React, { useState } from "react";
+import { Button, Form, Table } from "react-bootstrap";
+import { useHistory } from "react-router-dom";
+import { FormattedMessage, FormattedNumber, useIntl } from "react-intl";
+import { faTrashAlt, faUndo } from "@fortawesome/free-solid-svg-icons";
+
+import * as GQL from "src/core/generated-graphql";
+import { LoadingIndicator } from "src/components/Shared/LoadingIndicator";
+import { ErrorMessage } from "src/components/Shared/ErrorMessage";
+import { ModalComponent } from "src/components/Shared/Modal";
+import { Icon } from "src/components/Shared/Icon";
+import { Pagination } from "src/components/List/Pagination";
+import { useToast } from "src/hooks/Toast";
+import TextUtils from "src/utils/text";
+
+const CLASSNAME = "trash";
+
+const pageSize = 40;
+
+export const Trash: React.FC = () => {
+  const intl = useIntl();
+  const Toast = useToast();
+  const history = useHistory();
+  const query = new URLSearchParams(history.location.search);
+  const currentPage = Number.parseInt(query.get("page") ?? "1", 10);
+  const objectType = (query.get("type") ?? undefined) as
+    | GQL.TrashObjectType
+    | undefined;
+
+  const [checked, setChecked] = useState<Record<string, boolean>>({});
+  const [emptying, setEmptying] = useState<string[] | null>();
+  const [running, setRunning] = useState(false);
+
+  const { data, loading, error, refetch } = GQL.useFindTrashQuery({
+    fetchPolicy: "no-cache",
+    variables: {
+      object_type: objectType,
+      filter: { page: currentPage, per_page: pageSize },
+    },
+  });
+
+  const [restoreFromTrash] = GQL.useRestoreFromTrashMutation();
+  const [emptyTrash] = GQL.useEmptyTrashMutation();
+
+  if (loading) return <LoadingIndicator />;
+  if (error) return <ErrorMessage error={error.message} />;
+
+  const items = data?.findTrash.items ?? [];
+  const count = data?.findTrash.count ?? 0;
+  const checkedIDs = Object.keys(checked).filter((id) => checked[id]);
+
+  const setQuery = (q: Record<string, string | number | undefined>) => {
+    const newQuery = new URLSearchParams(query);
+    for (const key of Object.keys(q)) {
+      const value = q[key];
+      if (value !== undefined) {
+        newQuery.set(key, String(value));
+      } else {
+        newQuery.delete(key);
+      }
+    }
+    history.push({ search: newQuery.toString() });
+    setChecked({});
+  };
+
+  async function onRestore(ids: string[]) {
+    setRunning(true);
+    try {
+      await restoreFromTrash({ variables: { ids } });
+      Toast.success(
+        intl.formatMessage({ id: "trash.restored" }, { count: ids.length })
+      );
+      setChecked({});
+      refetch();
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setRunning(false);
+    }
+  }
+
+  async function onEmpty() {
+    if (emptying === undefined) return;
+
+    setRunning(true);
+    try {
+      const result = await emptyTrash({
+        variables: { ids: emptying ?? undefined },
+      });
+      Toast.success(
+        intl.formatMessage(
+          { id: "trash.deleted" },
+          { count: result.data?.emptyTrash ?? 0 }
+        )
+      );
+      setChecked({});
+      refetch();
+    } catch (e) {
+      Toast.error(e);
+    } finally {
+      setRunning(false);
+      setEmptying(undefined);
+    }
+  }
+
+  function renderFilesize(bytes: number) {
+    const { size, unit } = TextUtils.fileSize(bytes);
+    return (
+      <FormattedNumber
+        value={size}
+        style="unit"
+        unit={unit}
+        unitDisplay="narrow"
+        maximumFractionDigits={TextUtils.fileSizeFractionalDigits(unit)}
+      />
+    );
+  }
+
+  function renderEmptyDialog() {
+    if (emptying === undefined) return;
+
+    return (
+      <ModalComponent
+        show
+        icon={faTrashAlt}
+        header={intl.formatMessage({ id: "trash.empty_trash" })}
+        accept={{
+          variant: "danger",
+          onClick: onEmpty,
+          text: intl.formatMessage({ id: "actions.delete" }),
+        }}
+        cancel={{ onClick: () => setEmptying(undefined) }}
+        isRunning={running}
+      >
+        <p>
+          <FormattedMessage
+            id="trash.empty_confirm"
+            values={{ count: emptying?.length ?? count }}
+          />
+        </p>
+      </ModalComponent>
+    );
+  }
+
+  function renderItem(item: GQL.TrashItem) {
+    const size = item.files.reduce((total, f) => total + f.size, 0);
+
+    return (
+      <tr key={item.id}>
+        <td>
+          <Form.Check
+            checked={checked[item.id] ?? false}
+            onChange={(e) =>
+              setChecked({ ...checked, [item.id]: e.currentTarget.checked })
+            }
+          />
+        </td>
+        <td>
+          <FormattedMessage
+            id={`trash.object_type.${item.object_type.toLowerCase()}`}
+          />
+        </td>
+        <td className={`${CLASSNAME}-name`}>
+          <div>{item.name}</div>
+          {item.files.map((f) => (
+            <div key={f.trash_path} className="text-muted">
+              {f.original_path}
+            </div>
+          ))}
+        </td>
+        <td>{renderFilesize(size)}</td>
+        <td>{TextUtils.formatDateTime(intl, item.deleted_at)}</td>
+        <td>
+          <Button
+            size="sm"
+            variant="secondary"
+            disabled={running}
+            title={intl.formatMessage({ id: "actions.restore" })}
+            onClick={() => onRestore([item.id])}
+          >
+            <Icon icon={faUndo} />
+          </Button>
+        </td>
+      </tr>
+    );
+  }
+
+  return (
+    <div className={CLASSNAME}>
+      {renderEmptyDialog()}
+      <h4>
+        <FormattedMessage id="trash.title" />
+      </h4>
+      <div className={`${CLASSNAME}-toolbar`}>
+        <Form.Control
+          as="select"
+          className="btn-secondary"
+          value={objectType ?? ""}
+          onChange={(e) =>
+            setQuery({ type: e.currentTarget.value || undefined, page: 1 })
+          }
+        >
+          <option value="">{intl.formatMessage({ id: "all" })}</option>
+          {Object.values(GQL.TrashObjectType).map((t) => (
+            <option key={t} value={t}>
+              {intl.formatMessage({
+                id: `trash.object_type.${t.toLowerCase()}`,
+              })}
+            </option>
+          ))}
+        </Form.Control>
+        <Button
+          variant="secondary"
+          disabled={running || checkedIDs.length === 0}
+          onClick={() => onRestore(checkedIDs)}
+        >
+          <FormattedMessage id="actions.restore" />
+        </Button>
+        <Button
+          variant="danger"
+          disabled={running || checkedIDs.length === 0}
+          onClick={() => setEmptying(checkedIDs)}
+        >
+          <FormattedMessage id="actions.delete" />
+        </Button>
+        <Button
+          variant="danger"
+          disabled={running || count === 0}
+          onClick={() => setEmptying(null)}
+        >
+          <FormattedMessage id="trash.empty_trash" />
+        </Button>
+      </div>
+      <Pagination
+        itemsPerPage={pageSize}
+        currentPage={currentPage}
+        totalItems={count}
+        metadataByline={[]}
+        onChangePage={(newPage) =>
+          setQuery({ page: newPage === 1 ? undefined : newPage })
+        }
+      />
+      <Table striped className={`${CLASSNAME}-table`}>
+        <thead>
+          <tr>
+            <th />
+            <th>
+              <FormattedMessage id="type" />
+            </th>
+            <th>
+              <FormattedMessage id="name" />
+            </th>
+            <th>
+              <FormattedMessage id="filesize" />
+            </th>
+            <th>
+              <FormattedMessage id="trash.deleted_at" />
+            </th>
+            <th />
+          </tr>
+        </thead>
+        <tbody>{items.map(renderItem)}</tbody>
+      </Table>
+      {count === 0 && (
+        <h5 className="text-center">
+          <FormattedMessage id="trash.no_items" />
+        </h5>
+      )}
+    </div>
+  );
+};
+
+export default Trash;
@@ -2318,6 +2318,11 @@ export const usePlugins = () => GQL.usePluginsQuery();
 
 export const usePluginTasks = () => GQL.usePluginTasksQuery();
 
+export const useWebhookDeadLetters = () =>
+  GQL.useWebhookDeadLettersQuery({
+    fetchPolicy: "no-cache",
+  });
+
 export const useStats = () => GQL.useStatsQuery();
 
 export const useVersion = () => GQL.useVersionQuery();
@@ -2397,6 +2402,11 @@ export const mutateReloadPlugins = () =>
     },
   });
 
+export const mutateClearWebhookDeadLetters = () =>
+  client.mutate<GQL.ClearWebhookDeadLettersMutation>({
+    mutation: GQL.ClearWebhookDeadLettersDocument,
+  });
+
 type BoolMap = { [key: string]: boolean };
 
 export const mutateSetPluginsEnabled = (enabledMap: BoolMap) =>
@@ -2686,6 +2696,18 @@ export const mutateMetadataClean = (input: GQL.CleanMetadataInput) =>
     variables: { input },
   });
 
+export const mutateMetadataVerify = (input: GQL.VerifyMetadataInput) =>
+  client.mutate<GQL.MetadataVerifyMutation>({
+    mutation: GQL.MetadataVerifyDocument,
+    variables: { input },
+  });
+
+export const mutateMetadataWriteNfo = (input: GQL.WriteNfoMetadataInput) =>
+  client.mutate<GQL.MetadataWriteNfoMutation>({
+    mutation: GQL.MetadataWriteNfoDocument,
+    variables: { input },
+  });
+
 export const mutateCleanGenerated = (input: GQL.CleanGeneratedInput) =>
   client.mutate<GQL.MetadataCleanGeneratedMutation>({
     mutation: GQL.MetadataCleanGeneratedDocument,
@@ -2741,6 +2763,14 @@ export const mutateOptimiseDatabase = () =>
     mutation: GQL.OptimiseDatabaseDocument,
   });
 
+export const mutateDatabaseHealthCheck = (
+  input: GQL.DatabaseHealthCheckInput
+) =>
+  client.mutate<GQL.DatabaseHealthCheckMutation>({
+    mutation: GQL.DatabaseHealthCheckDocument,
+    variables: { input },
+  });
+
 export const mutateMigrateHashNaming = () =>
   client.mutate<GQL.MigrateHashNamingMutation>({
     mutation: GQL.MigrateHashNamingDocument,
@@ -0,0 +1,13 @@
+import { BooleanCriterion, BooleanCriterionOption } from "./criterion";
+
+export const IsOfflineCriterionOption = new BooleanCriterionOption(
+  "is_offline",
+  "is_offline",
+  () => new IsOfflineCriterion()
+);
+
+export class IsOfflineCriterion extends BooleanCriterion {
+  constructor() {
+    super(IsOfflineCriterionOption);
+  }
+}
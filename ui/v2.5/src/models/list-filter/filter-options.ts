@@ -14,6 +14,7 @@ export const MediaSortByOptions = [
   "tag_count",
   "performer_count",
   "random",
+  "relevance",
 ];
 
 export class ListFilterOptions {
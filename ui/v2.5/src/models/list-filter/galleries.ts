@@ -7,6 +7,7 @@ import {
 import { PerformerFavoriteCriterionOption } from "./criteria/favorite";
 import { GalleryIsMissingCriterionOption } from "./criteria/is-missing";
 import { OrganizedCriterionOption } from "./criteria/organized";
+import { IsOfflineCriterionOption } from "./criteria/is-offline";
 import { HasChaptersCriterionOption } from "./criteria/has-chapters";
 import { PerformersCriterionOption } from "./criteria/performers";
 import { AverageResolutionCriterionOption } from "./criteria/resolution";
@@ -52,6 +53,7 @@ const criterionOptions = [
   createStringCriterionOption("checksum", "media_info.checksum"),
   RatingCriterionOption,
   OrganizedCriterionOption,
+  IsOfflineCriterionOption,
   AverageResolutionCriterionOption,
   GalleryIsMissingCriterionOption,
   TagsCriterionOption,
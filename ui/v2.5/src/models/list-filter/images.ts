@@ -8,7 +8,9 @@ import {
 import { PerformerFavoriteCriterionOption } from "./criteria/favorite";
 import { ImageIsMissingCriterionOption } from "./criteria/is-missing";
 import { OrganizedCriterionOption } from "./criteria/organized";
+import { IsOfflineCriterionOption } from "./criteria/is-offline";
 import { PathCriterionOption } from "./criteria/path";
+import { PhashCriterionOption } from "./criteria/phash";
 import { PerformersCriterionOption } from "./criteria/performers";
 import { RatingCriterionOption } from "./criteria/rating";
 import { ResolutionCriterionOption } from "./criteria/resolution";
@@ -40,9 +42,11 @@ const criterionOptions = [
   createStringCriterionOption("details"),
   createStringCriterionOption("photographer"),
   createMandatoryStringCriterionOption("checksum", "media_info.checksum"),
+  PhashCriterionOption,
   PathCriterionOption,
   GalleriesCriterionOption,
   OrganizedCriterionOption,
+  IsOfflineCriterionOption,
   createMandatoryNumberCriterionOption("o_counter", "o_count"),
   ResolutionCriterionOption,
   OrientationCriterionOption,
@@ -14,6 +14,7 @@ const sortByOptions = [
   "seconds",
   "scene_id",
   "random",
+  "relevance",
   "scenes_updated_at",
 ].map(ListFilterOptions.createSortBy);
 const displayModeOptions = [DisplayMode.Wall];
@@ -14,6 +14,7 @@ import {
 } from "./criteria/groups";
 import { GalleriesCriterionOption } from "./criteria/galleries";
 import { OrganizedCriterionOption } from "./criteria/organized";
+import { IsOfflineCriterionOption } from "./criteria/is-offline";
 import { PerformersCriterionOption } from "./criteria/performers";
 import { ResolutionCriterionOption } from "./criteria/resolution";
 import { StudiosCriterionOption } from "./criteria/studios";
@@ -84,6 +85,7 @@ const criterionOptions = [
   PhashCriterionOption,
   DuplicatedCriterionOption,
   OrganizedCriterionOption,
+  IsOfflineCriterionOption,
   RatingCriterionOption,
   createMandatoryNumberCriterionOption("o_counter", "o_count"),
   ResolutionCriterionOption,
@@ -139,6 +139,7 @@ export type CriterionType =
   | "favorite"
   | "has_markers"
   | "is_missing"
+  | "is_offline"
   | "tags"
   | "scene_tags"
   | "performer_tags"